@@ -22,9 +22,229 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How CreateUnique generates the part of a key after its prefix.
+type KeyKind int32
+
+const (
+	// 26 characters of Crockford base32 sorting by creation time, to the
+	// millisecond.
+	KeyKind_KEY_KIND_ULID KeyKind = 0
+	// A random version 4 UUID.
+	KeyKind_KEY_KIND_UUID KeyKind = 1
+	// The next number of a counter the server keeps for the prefix, as 20
+	// zero-padded digits. Requires a store keeping versions.
+	KeyKind_KEY_KIND_SEQUENCE KeyKind = 2
+)
+
+// Enum value maps for KeyKind.
+var (
+	KeyKind_name = map[int32]string{
+		0: "KEY_KIND_ULID",
+		1: "KEY_KIND_UUID",
+		2: "KEY_KIND_SEQUENCE",
+	}
+	KeyKind_value = map[string]int32{
+		"KEY_KIND_ULID":     0,
+		"KEY_KIND_UUID":     1,
+		"KEY_KIND_SEQUENCE": 2,
+	}
+)
+
+func (x KeyKind) Enum() *KeyKind {
+	p := new(KeyKind)
+	*p = x
+	return p
+}
+
+func (x KeyKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_clavis_proto_enumTypes[0].Descriptor()
+}
+
+func (KeyKind) Type() protoreflect.EnumType {
+	return &file_api_proto_clavis_proto_enumTypes[0]
+}
+
+func (x KeyKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyKind.Descriptor instead.
+func (KeyKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{0}
+}
+
+// How a counter is stored.
+type CounterEncoding int32
+
+const (
+	// Decimal digits with an optional sign, as in "-42".
+	CounterEncoding_COUNTER_ENCODING_ASCII CounterEncoding = 0
+	// Eight bytes of an int64, least significant first.
+	CounterEncoding_COUNTER_ENCODING_LITTLE_ENDIAN CounterEncoding = 1
+)
+
+// Enum value maps for CounterEncoding.
+var (
+	CounterEncoding_name = map[int32]string{
+		0: "COUNTER_ENCODING_ASCII",
+		1: "COUNTER_ENCODING_LITTLE_ENDIAN",
+	}
+	CounterEncoding_value = map[string]int32{
+		"COUNTER_ENCODING_ASCII":         0,
+		"COUNTER_ENCODING_LITTLE_ENDIAN": 1,
+	}
+)
+
+func (x CounterEncoding) Enum() *CounterEncoding {
+	p := new(CounterEncoding)
+	*p = x
+	return p
+}
+
+func (x CounterEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CounterEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_clavis_proto_enumTypes[1].Descriptor()
+}
+
+func (CounterEncoding) Type() protoreflect.EnumType {
+	return &file_api_proto_clavis_proto_enumTypes[1]
+}
+
+func (x CounterEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CounterEncoding.Descriptor instead.
+func (CounterEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{1}
+}
+
+type DiffChange_Op int32
+
+const (
+	DiffChange_OP_UNSPECIFIED DiffChange_Op = 0
+	DiffChange_OP_ADDED       DiffChange_Op = 1
+	DiffChange_OP_REMOVED     DiffChange_Op = 2
+	DiffChange_OP_CHANGED     DiffChange_Op = 3
+)
+
+// Enum value maps for DiffChange_Op.
+var (
+	DiffChange_Op_name = map[int32]string{
+		0: "OP_UNSPECIFIED",
+		1: "OP_ADDED",
+		2: "OP_REMOVED",
+		3: "OP_CHANGED",
+	}
+	DiffChange_Op_value = map[string]int32{
+		"OP_UNSPECIFIED": 0,
+		"OP_ADDED":       1,
+		"OP_REMOVED":     2,
+		"OP_CHANGED":     3,
+	}
+)
+
+func (x DiffChange_Op) Enum() *DiffChange_Op {
+	p := new(DiffChange_Op)
+	*p = x
+	return p
+}
+
+func (x DiffChange_Op) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiffChange_Op) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_clavis_proto_enumTypes[2].Descriptor()
+}
+
+func (DiffChange_Op) Type() protoreflect.EnumType {
+	return &file_api_proto_clavis_proto_enumTypes[2]
+}
+
+func (x DiffChange_Op) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiffChange_Op.Descriptor instead.
+func (DiffChange_Op) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{62, 0}
+}
+
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_TYPE_UNSPECIFIED WatchEvent_Type = 0
+	WatchEvent_TYPE_PUT         WatchEvent_Type = 1
+	WatchEvent_TYPE_DELETE      WatchEvent_Type = 2
+	// The key was removed because it expired.
+	WatchEvent_TYPE_EXPIRE WatchEvent_Type = 3
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_PUT",
+		2: "TYPE_DELETE",
+		3: "TYPE_EXPIRE",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_PUT":         1,
+		"TYPE_DELETE":      2,
+		"TYPE_EXPIRE":      3,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_clavis_proto_enumTypes[3].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_api_proto_clavis_proto_enumTypes[3]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{65, 0}
+}
+
 type GetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Also return the version of the value, for a later conditional Put.
+	WithVersion bool `protobuf:"varint,2,opt,name=with_version,json=withVersion,proto3" json:"with_version,omitempty"`
+	// Namespace the keys belong to. Keys are stored as namespace/key, and keys
+	// in responses are relative to the namespace. Empty addresses the whole
+	// keyspace.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Reads the value as it was at this version instead of the latest one,
+	// implying with_version. Older values are only kept while the server
+	// retains several versions of each key; the key is reported as not found
+	// at a version whose value was discarded.
+	AtVersion     uint64 `protobuf:"varint,4,opt,name=at_version,json=atVersion,proto3" json:"at_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,10 +286,34 @@ func (x *GetRequest) GetKey() string {
 	return ""
 }
 
+func (x *GetRequest) GetWithVersion() bool {
+	if x != nil {
+		return x.WithVersion
+	}
+	return false
+}
+
+func (x *GetRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetRequest) GetAtVersion() uint64 {
+	if x != nil {
+		return x.AtVersion
+	}
+	return 0
+}
+
 type GetResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Value         []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Value []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	// Version of the value when requested with with_version or at_version; 0
+	// if not found.
+	Version       uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -118,10 +362,39 @@ func (x *GetResponse) GetFound() bool {
 	return false
 }
 
+func (x *GetResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type PutRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Optional token proving the writer holds the named lock, in the namespace
+	// as keys are. The write fails with FAILED_PRECONDITION, coded
+	// STALE_FENCING_TOKEN, once a newer lease on the lock is granted, and with
+	// INVALID_ARGUMENT for a token the lock never issued.
+	Fencing *FencingToken `protobuf:"bytes,3,opt,name=fencing,proto3" json:"fencing,omitempty"`
+	// When set, the write only succeeds if the key is still at this version, as
+	// returned by Get with with_version; 0 requires the key not to exist. A
+	// mismatch fails with ABORTED.
+	ExpectedVersion *uint64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// When set, the write only succeeds if the key does not exist yet, which
+	// makes creation idempotent and lets clients build locks. An existing key
+	// fails with FAILED_PRECONDITION. Exclusive with expected_version.
+	MustNotExist bool `protobuf:"varint,6,opt,name=must_not_exist,json=mustNotExist,proto3" json:"must_not_exist,omitempty"`
+	// Tags replacing those of the key, which QueryByTag finds it by. The key
+	// keeps its tags when none are given. Requires a tag index on the server;
+	// otherwise fails with UNIMPLEMENTED.
+	Tags []string `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Attaches the key to this live lease, so it is deleted when the lease
+	// ends; see GrantLease.
+	Lease         uint64 `protobuf:"varint,8,opt,name=lease,proto3" json:"lease,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -170,26 +443,70 @@ func (x *PutRequest) GetValue() []byte {
 	return nil
 }
 
-type PutResponse struct {
+func (x *PutRequest) GetFencing() *FencingToken {
+	if x != nil {
+		return x.Fencing
+	}
+	return nil
+}
+
+func (x *PutRequest) GetExpectedVersion() uint64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *PutRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *PutRequest) GetMustNotExist() bool {
+	if x != nil {
+		return x.MustNotExist
+	}
+	return false
+}
+
+func (x *PutRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *PutRequest) GetLease() uint64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+type FencingToken struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lock          string                 `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`
+	Token         uint64                 `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PutResponse) Reset() {
-	*x = PutResponse{}
+func (x *FencingToken) Reset() {
+	*x = FencingToken{}
 	mi := &file_api_proto_clavis_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutResponse) String() string {
+func (x *FencingToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutResponse) ProtoMessage() {}
+func (*FencingToken) ProtoMessage() {}
 
-func (x *PutResponse) ProtoReflect() protoreflect.Message {
+func (x *FencingToken) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_clavis_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -201,32 +518,54 @@ func (x *PutResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
-func (*PutResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use FencingToken.ProtoReflect.Descriptor instead.
+func (*FencingToken) Descriptor() ([]byte, []int) {
 	return file_api_proto_clavis_proto_rawDescGZIP(), []int{3}
 }
 
-type DeleteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+func (x *FencingToken) GetLock() string {
+	if x != nil {
+		return x.Lock
+	}
+	return ""
+}
+
+func (x *FencingToken) GetToken() uint64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+type LockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the lock, in the namespace as keys are; see
+	// GetRequest.namespace.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Identifies the holder in the errors of other callers.
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// How long the lease lasts unless renewed; 30 seconds if zero, at most an
+	// hour.
+	TtlMs         int64  `protobuf:"varint,3,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *LockRequest) Reset() {
+	*x = LockRequest{}
 	mi := &file_api_proto_clavis_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *LockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*LockRequest) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+func (x *LockRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_clavis_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -238,38 +577,64 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
+func (*LockRequest) Descriptor() ([]byte, []int) {
 	return file_api_proto_clavis_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *DeleteRequest) GetKey() string {
+func (x *LockRequest) GetName() string {
 	if x != nil {
-		return x.Key
+		return x.Name
 	}
 	return ""
 }
 
-type DeleteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LockRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
+func (x *LockRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *LockRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type LockResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token to pass to Put, RenewLock and Unlock. Its lock is the name of the
+	// lock within the namespace of the request, as passed to Put in the same
+	// namespace.
+	Fencing         *FencingToken `protobuf:"bytes,1,opt,name=fencing,proto3" json:"fencing,omitempty"`
+	ExpiresUnixNano int64         `protobuf:"varint,2,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LockResponse) Reset() {
+	*x = LockResponse{}
 	mi := &file_api_proto_clavis_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteResponse) String() string {
+func (x *LockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*LockResponse) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+func (x *LockResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_clavis_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -281,87 +646,4178 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LockResponse.ProtoReflect.Descriptor instead.
+func (*LockResponse) Descriptor() ([]byte, []int) {
 	return file_api_proto_clavis_proto_rawDescGZIP(), []int{5}
 }
 
-var File_api_proto_clavis_proto protoreflect.FileDescriptor
+func (x *LockResponse) GetFencing() *FencingToken {
+	if x != nil {
+		return x.Fencing
+	}
+	return nil
+}
 
-const file_api_proto_clavis_proto_rawDesc = "" +
-	"\n" +
-	"\x16api/proto/clavis.proto\x12\tclavis.v1\"\x1e\n" +
-	"\n" +
-	"GetRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"9\n" +
-	"\vGetResponse\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\fR\x05value\x12\x14\n" +
-	"\x05found\x18\x02 \x01(\bR\x05found\"4\n" +
-	"\n" +
-	"PutRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\fR\x05value\"\r\n" +
-	"\vPutResponse\"!\n" +
-	"\rDeleteRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"\x10\n" +
-	"\x0eDeleteResponse2\xb9\x01\n" +
-	"\x06Clavis\x126\n" +
-	"\x03Get\x12\x15.clavis.v1.GetRequest\x1a\x16.clavis.v1.GetResponse\"\x00\x126\n" +
-	"\x03Put\x12\x15.clavis.v1.PutRequest\x1a\x16.clavis.v1.PutResponse\"\x00\x12?\n" +
-	"\x06Delete\x12\x18.clavis.v1.DeleteRequest\x1a\x19.clavis.v1.DeleteResponse\"\x00B1Z/github.com/yourusername/clavis/api/proto;clavisb\x06proto3"
+func (x *LockResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
 
-var (
-	file_api_proto_clavis_proto_rawDescOnce sync.Once
-	file_api_proto_clavis_proto_rawDescData []byte
-)
+type RenewLockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Token uint64                 `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
+	// New duration of the lease from now; see LockRequest.ttl_ms.
+	TtlMs         int64  `protobuf:"varint,3,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-func file_api_proto_clavis_proto_rawDescGZIP() []byte {
-	file_api_proto_clavis_proto_rawDescOnce.Do(func() {
-		file_api_proto_clavis_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_clavis_proto_rawDesc), len(file_api_proto_clavis_proto_rawDesc)))
-	})
-	return file_api_proto_clavis_proto_rawDescData
+func (x *RenewLockRequest) Reset() {
+	*x = RenewLockRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-var file_api_proto_clavis_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_api_proto_clavis_proto_goTypes = []any{
-	(*GetRequest)(nil),     // 0: clavis.v1.GetRequest
-	(*GetResponse)(nil),    // 1: clavis.v1.GetResponse
-	(*PutRequest)(nil),     // 2: clavis.v1.PutRequest
-	(*PutResponse)(nil),    // 3: clavis.v1.PutResponse
-	(*DeleteRequest)(nil),  // 4: clavis.v1.DeleteRequest
-	(*DeleteResponse)(nil), // 5: clavis.v1.DeleteResponse
+func (x *RenewLockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-var file_api_proto_clavis_proto_depIdxs = []int32{
-	0, // 0: clavis.v1.Clavis.Get:input_type -> clavis.v1.GetRequest
-	2, // 1: clavis.v1.Clavis.Put:input_type -> clavis.v1.PutRequest
-	4, // 2: clavis.v1.Clavis.Delete:input_type -> clavis.v1.DeleteRequest
-	1, // 3: clavis.v1.Clavis.Get:output_type -> clavis.v1.GetResponse
-	3, // 4: clavis.v1.Clavis.Put:output_type -> clavis.v1.PutResponse
-	5, // 5: clavis.v1.Clavis.Delete:output_type -> clavis.v1.DeleteResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+func (*RenewLockRequest) ProtoMessage() {}
+
+func (x *RenewLockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func init() { file_api_proto_clavis_proto_init() }
-func file_api_proto_clavis_proto_init() {
-	if File_api_proto_clavis_proto != nil {
-		return
+// Deprecated: Use RenewLockRequest.ProtoReflect.Descriptor instead.
+func (*RenewLockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RenewLockRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RenewLockRequest) GetToken() uint64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *RenewLockRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+func (x *RenewLockRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type UnlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Token         uint64                 `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockRequest) Reset() {
+	*x = UnlockRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockRequest) ProtoMessage() {}
+
+func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockRequest.ProtoReflect.Descriptor instead.
+func (*UnlockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UnlockRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UnlockRequest) GetToken() uint64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *UnlockRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type UnlockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockResponse) Reset() {
+	*x = UnlockResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockResponse) ProtoMessage() {}
+
+func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockResponse.ProtoReflect.Descriptor instead.
+func (*UnlockResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{8}
+}
+
+type CreateUniqueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Prefix of the generated key, such as "orders/".
+	Prefix string  `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Kind   KeyKind `protobuf:"varint,2,opt,name=kind,proto3,enum=clavis.v1.KeyKind" json:"kind,omitempty"`
+	Value  []byte  `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUniqueRequest) Reset() {
+	*x = CreateUniqueRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUniqueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUniqueRequest) ProtoMessage() {}
+
+func (x *CreateUniqueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUniqueRequest.ProtoReflect.Descriptor instead.
+func (*CreateUniqueRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateUniqueRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *CreateUniqueRequest) GetKind() KeyKind {
+	if x != nil {
+		return x.Kind
+	}
+	return KeyKind_KEY_KIND_ULID
+}
+
+func (x *CreateUniqueRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CreateUniqueRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type CreateUniqueResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generated key, relative to the namespace.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Version the value was written at; see PutResponse.version.
+	Version       uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUniqueResponse) Reset() {
+	*x = CreateUniqueResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUniqueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUniqueResponse) ProtoMessage() {}
+
+func (x *CreateUniqueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUniqueResponse.ProtoReflect.Descriptor instead.
+func (*CreateUniqueResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateUniqueResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateUniqueResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NextSequenceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the sequence; a new name starts a sequence at 1.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// How many consecutive numbers to reserve; 1 if zero, at most 65536.
+	Bandwidth uint64 `protobuf:"varint,2,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	// Namespace the sequence belongs to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextSequenceRequest) Reset() {
+	*x = NextSequenceRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextSequenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextSequenceRequest) ProtoMessage() {}
+
+func (x *NextSequenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextSequenceRequest.ProtoReflect.Descriptor instead.
+func (*NextSequenceRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NextSequenceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NextSequenceRequest) GetBandwidth() uint64 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *NextSequenceRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type NextSequenceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// First number reserved; the range runs up to first + count - 1.
+	First         uint64 `protobuf:"varint,1,opt,name=first,proto3" json:"first,omitempty"`
+	Count         uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextSequenceResponse) Reset() {
+	*x = NextSequenceResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextSequenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextSequenceResponse) ProtoMessage() {}
+
+func (x *NextSequenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextSequenceResponse.ProtoReflect.Descriptor instead.
+func (*NextSequenceResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NextSequenceResponse) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *NextSequenceResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type DeletePrefixRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keys starting with the prefix are deleted; may only be empty within a
+	// namespace, to delete all of its keys.
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Only count the keys that would be deleted.
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Token returned by a dry run of the same prefix, required to delete the
+	// keys. Each token is accepted once.
+	ConfirmationToken string `protobuf:"bytes,4,opt,name=confirmation_token,json=confirmationToken,proto3" json:"confirmation_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DeletePrefixRequest) Reset() {
+	*x = DeletePrefixRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePrefixRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePrefixRequest) ProtoMessage() {}
+
+func (x *DeletePrefixRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePrefixRequest.ProtoReflect.Descriptor instead.
+func (*DeletePrefixRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeletePrefixRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *DeletePrefixRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *DeletePrefixRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DeletePrefixRequest) GetConfirmationToken() string {
+	if x != nil {
+		return x.ConfirmationToken
+	}
+	return ""
+}
+
+type DeletePrefixResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Keys deleted, or that would be deleted on a dry run.
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Set on a dry run; repeat the request with it, without dry_run, to
+	// delete the keys before it expires.
+	ConfirmationToken             string `protobuf:"bytes,2,opt,name=confirmation_token,json=confirmationToken,proto3" json:"confirmation_token,omitempty"`
+	ConfirmationExpiresAtUnixNano int64  `protobuf:"varint,3,opt,name=confirmation_expires_at_unix_nano,json=confirmationExpiresAtUnixNano,proto3" json:"confirmation_expires_at_unix_nano,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *DeletePrefixResponse) Reset() {
+	*x = DeletePrefixResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePrefixResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePrefixResponse) ProtoMessage() {}
+
+func (x *DeletePrefixResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePrefixResponse.ProtoReflect.Descriptor instead.
+func (*DeletePrefixResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeletePrefixResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeletePrefixResponse) GetConfirmationToken() string {
+	if x != nil {
+		return x.ConfirmationToken
+	}
+	return ""
+}
+
+func (x *DeletePrefixResponse) GetConfirmationExpiresAtUnixNano() int64 {
+	if x != nil {
+		return x.ConfirmationExpiresAtUnixNano
+	}
+	return 0
+}
+
+type CopyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key whose value is copied; a missing source fails with NOT_FOUND.
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// Key the value is written to; must differ from the source.
+	Destination string `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Namespace both keys belong to; see GetRequest.namespace.
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Replace the value of an existing destination; otherwise an existing
+	// destination fails with FAILED_PRECONDITION and is left as it was, which
+	// requires a store with conditional writes, as must_not_exist of Put does.
+	Overwrite bool `protobuf:"varint,4,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	// Attaches the destination to this live lease, so it is deleted when the
+	// lease ends; see PutRequest.lease. The source keeps its own lease, if
+	// any, which the copy does not inherit.
+	Lease uint64 `protobuf:"varint,5,opt,name=lease,proto3" json:"lease,omitempty"`
+	// Grants a new lease of this duration to the destination alone, as
+	// GrantLease would, and returns it. Exclusive with lease.
+	TtlMs         int64 `protobuf:"varint,6,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyRequest) Reset() {
+	*x = CopyRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyRequest) ProtoMessage() {}
+
+func (x *CopyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyRequest.ProtoReflect.Descriptor instead.
+func (*CopyRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CopyRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+func (x *CopyRequest) GetLease() uint64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+func (x *CopyRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type CopyResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Warnings []*Warning             `protobuf:"bytes,1,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Version the copy was committed at; see PutResponse.version.
+	Version uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Lease granted for ttl_ms, which the client may keep alive or revoke
+	// like any other; 0 without ttl_ms.
+	Lease           uint64 `protobuf:"varint,3,opt,name=lease,proto3" json:"lease,omitempty"`
+	ExpiresUnixNano int64  `protobuf:"varint,4,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CopyResponse) Reset() {
+	*x = CopyResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyResponse) ProtoMessage() {}
+
+func (x *CopyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyResponse.ProtoReflect.Descriptor instead.
+func (*CopyResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CopyResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *CopyResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetLease() uint64 {
+	if x != nil {
+		return x.Lease
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+type GrantLeaseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// How long the lease lives after it is granted or kept alive; a minute if
+	// zero, at most a day.
+	TtlMs         int64 `protobuf:"varint,1,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantLeaseRequest) Reset() {
+	*x = GrantLeaseRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantLeaseRequest) ProtoMessage() {}
+
+func (x *GrantLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantLeaseRequest.ProtoReflect.Descriptor instead.
+func (*GrantLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GrantLeaseRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type GrantLeaseResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpiresUnixNano int64                  `protobuf:"varint,2,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GrantLeaseResponse) Reset() {
+	*x = GrantLeaseResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantLeaseResponse) ProtoMessage() {}
+
+func (x *GrantLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantLeaseResponse.ProtoReflect.Descriptor instead.
+func (*GrantLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GrantLeaseResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GrantLeaseResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+type AttachLeaseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Keys  []string               `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachLeaseRequest) Reset() {
+	*x = AttachLeaseRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachLeaseRequest) ProtoMessage() {}
+
+func (x *AttachLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachLeaseRequest.ProtoReflect.Descriptor instead.
+func (*AttachLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AttachLeaseRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AttachLeaseRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *AttachLeaseRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type AttachLeaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachLeaseResponse) Reset() {
+	*x = AttachLeaseResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachLeaseResponse) ProtoMessage() {}
+
+func (x *AttachLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachLeaseResponse.ProtoReflect.Descriptor instead.
+func (*AttachLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{20}
+}
+
+type KeepAliveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeepAliveRequest) Reset() {
+	*x = KeepAliveRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepAliveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepAliveRequest) ProtoMessage() {}
+
+func (x *KeepAliveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepAliveRequest.ProtoReflect.Descriptor instead.
+func (*KeepAliveRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *KeepAliveRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type KeepAliveResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpiresUnixNano int64                  `protobuf:"varint,2,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *KeepAliveResponse) Reset() {
+	*x = KeepAliveResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeepAliveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepAliveResponse) ProtoMessage() {}
+
+func (x *KeepAliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepAliveResponse.ProtoReflect.Descriptor instead.
+func (*KeepAliveResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *KeepAliveResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *KeepAliveResponse) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+type RevokeLeaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeLeaseRequest) Reset() {
+	*x = RevokeLeaseRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeLeaseRequest) ProtoMessage() {}
+
+func (x *RevokeLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeLeaseRequest.ProtoReflect.Descriptor instead.
+func (*RevokeLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RevokeLeaseRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RevokeLeaseResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of keys that were attached to the lease.
+	AttachedKeys  int64 `protobuf:"varint,1,opt,name=attached_keys,json=attachedKeys,proto3" json:"attached_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeLeaseResponse) Reset() {
+	*x = RevokeLeaseResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeLeaseResponse) ProtoMessage() {}
+
+func (x *RevokeLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeLeaseResponse.ProtoReflect.Descriptor instead.
+func (*RevokeLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RevokeLeaseResponse) GetAttachedKeys() int64 {
+	if x != nil {
+		return x.AttachedKeys
+	}
+	return 0
+}
+
+// Warning is a non-fatal condition met while serving a write, such as a
+// namespace nearing its quota or a request that was normalized. The write
+// succeeded regardless.
+type Warning struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Machine-readable reason, such as "near_quota" or "duplicate_key".
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Key the warning is about, if any.
+	Key           string            `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata      map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_api_proto_clavis_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Warning) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Warning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Warning) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Warning) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type PutResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Warnings []*Warning             `protobuf:"bytes,1,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Version the write was committed at, greater than that of every earlier
+	// write; pass it as expected_version to update the key conditionally. 0
+	// when the store does not track versions.
+	Version       uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutResponse) Reset() {
+	*x = PutResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutResponse) ProtoMessage() {}
+
+func (x *PutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
+func (*PutResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PutResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *PutResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warnings      []*Warning             `protobuf:"bytes,1,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type KeyValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Set by scans requesting with_metadata.
+	Metadata      *KeyMetadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyValue) Reset() {
+	*x = KeyValue{}
+	mi := &file_api_proto_clavis_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyValue) ProtoMessage() {}
+
+func (x *KeyValue) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyValue.ProtoReflect.Descriptor instead.
+func (*KeyValue) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *KeyValue) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyValue) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *KeyValue) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// When and how often a key was written. Every field is zero for a key last
+// written before the server tracked key metadata.
+type KeyMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// When the key was first written, or written again after a delete.
+	CreatedUnixNano int64 `protobuf:"varint,1,opt,name=created_unix_nano,json=createdUnixNano,proto3" json:"created_unix_nano,omitempty"`
+	// When the key was last written.
+	UpdatedUnixNano int64 `protobuf:"varint,2,opt,name=updated_unix_nano,json=updatedUnixNano,proto3" json:"updated_unix_nano,omitempty"`
+	// Writes of the key since it was created, counting the first.
+	UpdateCount   uint64 `protobuf:"varint,3,opt,name=update_count,json=updateCount,proto3" json:"update_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyMetadata) Reset() {
+	*x = KeyMetadata{}
+	mi := &file_api_proto_clavis_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyMetadata) ProtoMessage() {}
+
+func (x *KeyMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyMetadata.ProtoReflect.Descriptor instead.
+func (*KeyMetadata) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *KeyMetadata) GetCreatedUnixNano() int64 {
+	if x != nil {
+		return x.CreatedUnixNano
+	}
+	return 0
+}
+
+func (x *KeyMetadata) GetUpdatedUnixNano() int64 {
+	if x != nil {
+		return x.UpdatedUnixNano
+	}
+	return 0
+}
+
+func (x *KeyMetadata) GetUpdateCount() uint64 {
+	if x != nil {
+		return x.UpdateCount
+	}
+	return 0
+}
+
+type ScanRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Prefix string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Maximum number of items to return; zero selects the server default.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Opaque token from a previous response to resume iteration.
+	Cursor string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Lets the server spill a result too large for one response to disk and
+	// return a spill handle instead of items. A zero limit then selects every
+	// match.
+	AllowSpill bool `protobuf:"varint,4,opt,name=allow_spill,json=allowSpill,proto3" json:"allow_spill,omitempty"`
+	// Reads every key as it was at this time instead of now, so that exports
+	// are consistent while writes continue. The time is resolved to a store
+	// version, returned in ScanResponse.as_of_version; pass that version when
+	// resuming from a cursor. Requires version history on the server;
+	// otherwise fails with FAILED_PRECONDITION.
+	AsOfUnixNano int64 `protobuf:"varint,5,opt,name=as_of_unix_nano,json=asOfUnixNano,proto3" json:"as_of_unix_nano,omitempty"`
+	// Reads every key as it was at this store version. Exclusive with
+	// as_of_unix_nano.
+	AsOfVersion uint64 `protobuf:"varint,6,opt,name=as_of_version,json=asOfVersion,proto3" json:"as_of_version,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace string `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Return the keys without their values, so the server need not read the
+	// values at all. Listing large values is then much cheaper.
+	KeysOnly bool `protobuf:"varint,8,opt,name=keys_only,json=keysOnly,proto3" json:"keys_only,omitempty"`
+	// Return the metadata of each key with it, as of the same version as the
+	// items. Requires key metadata on the server; otherwise fails with
+	// UNIMPLEMENTED.
+	WithMetadata  bool `protobuf:"varint,9,opt,name=with_metadata,json=withMetadata,proto3" json:"with_metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanRequest) ProtoMessage() {}
+
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ScanRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ScanRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetAllowSpill() bool {
+	if x != nil {
+		return x.AllowSpill
+	}
+	return false
+}
+
+func (x *ScanRequest) GetAsOfUnixNano() int64 {
+	if x != nil {
+		return x.AsOfUnixNano
+	}
+	return 0
+}
+
+func (x *ScanRequest) GetAsOfVersion() uint64 {
+	if x != nil {
+		return x.AsOfVersion
+	}
+	return 0
+}
+
+func (x *ScanRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetKeysOnly() bool {
+	if x != nil {
+		return x.KeysOnly
+	}
+	return false
+}
+
+func (x *ScanRequest) GetWithMetadata() bool {
+	if x != nil {
+		return x.WithMetadata
+	}
+	return false
+}
+
+type ScanResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*KeyValue            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Empty when there are no more results.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// Set when the items were spilled; fetch them with FetchSpill.
+	Spill *SpillHandle `protobuf:"bytes,3,opt,name=spill,proto3" json:"spill,omitempty"`
+	// Version the items were read at, when the request asked for a point in
+	// time.
+	AsOfVersion   uint64 `protobuf:"varint,4,opt,name=as_of_version,json=asOfVersion,proto3" json:"as_of_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanResponse) ProtoMessage() {}
+
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ScanResponse) GetItems() []*KeyValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ScanResponse) GetSpill() *SpillHandle {
+	if x != nil {
+		return x.Spill
+	}
+	return nil
+}
+
+func (x *ScanResponse) GetAsOfVersion() uint64 {
+	if x != nil {
+		return x.AsOfVersion
+	}
+	return 0
+}
+
+type RangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// First key of the range, inclusive; empty starts at the first key.
+	Start string `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	// Key the range stops before, exclusive; empty runs to the last key.
+	End string `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	// Maximum number of items to return; zero selects the server default.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Return the items in descending key order, starting from the end.
+	Reverse bool `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Return the keys without their values; see ScanRequest.keys_only.
+	KeysOnly bool `protobuf:"varint,6,opt,name=keys_only,json=keysOnly,proto3" json:"keys_only,omitempty"`
+	// Opaque token from a previous response to resume iteration, with the
+	// same start, end and direction.
+	Cursor        string `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeRequest) Reset() {
+	*x = RangeRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeRequest) ProtoMessage() {}
+
+func (x *RangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeRequest.ProtoReflect.Descriptor instead.
+func (*RangeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RangeRequest) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *RangeRequest) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *RangeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RangeRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+func (x *RangeRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *RangeRequest) GetKeysOnly() bool {
+	if x != nil {
+		return x.KeysOnly
+	}
+	return false
+}
+
+func (x *RangeRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type RangeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Items []*KeyValue            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Empty when there are no more results.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeResponse) Reset() {
+	*x = RangeResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeResponse) ProtoMessage() {}
+
+func (x *RangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeResponse.ProtoReflect.Descriptor instead.
+func (*RangeResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RangeResponse) GetItems() []*KeyValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *RangeResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ExistsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExistsRequest) Reset() {
+	*x = ExistsRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExistsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExistsRequest) ProtoMessage() {}
+
+func (x *ExistsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExistsRequest.ProtoReflect.Descriptor instead.
+func (*ExistsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExistsRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ExistsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ExistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExistsResponse) Reset() {
+	*x = ExistsResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExistsResponse) ProtoMessage() {}
+
+func (x *ExistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExistsResponse.ProtoReflect.Descriptor instead.
+func (*ExistsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ExistsResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type CountRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Prefix string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountRequest) Reset() {
+	*x = CountRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountRequest) ProtoMessage() {}
+
+func (x *CountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
+func (*CountRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CountRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *CountRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type CountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountResponse) Reset() {
+	*x = CountResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountResponse) ProtoMessage() {}
+
+func (x *CountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
+func (*CountResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CountResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type IncrementRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Added to the counter; negative to decrement. A missing key counts as 0.
+	// A result that does not fit in an int64 fails with OUT_OF_RANGE.
+	Delta int64 `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	// Encoding of the stored counter. A value in another encoding fails with
+	// FAILED_PRECONDITION.
+	Encoding CounterEncoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=clavis.v1.CounterEncoding" json:"encoding,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementRequest) Reset() {
+	*x = IncrementRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementRequest) ProtoMessage() {}
+
+func (x *IncrementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementRequest.ProtoReflect.Descriptor instead.
+func (*IncrementRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *IncrementRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IncrementRequest) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *IncrementRequest) GetEncoding() CounterEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return CounterEncoding_COUNTER_ENCODING_ASCII
+}
+
+func (x *IncrementRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type IncrementResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Value of the counter after the increment.
+	Value         int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementResponse) Reset() {
+	*x = IncrementResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementResponse) ProtoMessage() {}
+
+func (x *IncrementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementResponse.ProtoReflect.Descriptor instead.
+func (*IncrementResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *IncrementResponse) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type UndeleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndeleteRequest) Reset() {
+	*x = UndeleteRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndeleteRequest) ProtoMessage() {}
+
+func (x *UndeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndeleteRequest.ProtoReflect.Descriptor instead.
+func (*UndeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *UndeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UndeleteRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type UndeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndeleteResponse) Reset() {
+	*x = UndeleteResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndeleteResponse) ProtoMessage() {}
+
+func (x *UndeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndeleteResponse.ProtoReflect.Descriptor instead.
+func (*UndeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{42}
+}
+
+type GetMetadataRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataRequest) Reset() {
+	*x = GetMetadataRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataRequest) ProtoMessage() {}
+
+func (x *GetMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetMetadataRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetMetadataRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type GetMetadataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Metadata      *KeyMetadata           `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataResponse) Reset() {
+	*x = GetMetadataResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataResponse) ProtoMessage() {}
+
+func (x *GetMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataResponse.ProtoReflect.Descriptor instead.
+func (*GetMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetMetadataResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetMetadataResponse) GetMetadata() *KeyMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type QueryByTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tag   string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Only lists the keys starting with this prefix.
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Maximum number of keys to return; zero selects the server default.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Opaque token from a previous response to resume listing.
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryByTagRequest) Reset() {
+	*x = QueryByTagRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryByTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryByTagRequest) ProtoMessage() {}
+
+func (x *QueryByTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryByTagRequest.ProtoReflect.Descriptor instead.
+func (*QueryByTagRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *QueryByTagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *QueryByTagRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *QueryByTagRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryByTagRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *QueryByTagRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type QueryByTagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Keys  []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Empty when there are no more keys.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryByTagResponse) Reset() {
+	*x = QueryByTagResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryByTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryByTagResponse) ProtoMessage() {}
+
+func (x *QueryByTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryByTagResponse.ProtoReflect.Descriptor instead.
+func (*QueryByTagResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *QueryByTagResponse) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *QueryByTagResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Words every match must contain, compared case-insensitively. A word
+	// ending with * also matches the longer words it starts, which rank below
+	// the word itself.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Only searches the keys starting with this prefix.
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Maximum number of matches to return; zero selects the server default.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SearchResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+type SearchHit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// How well the value matches; higher is better.
+	Score         float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_api_proto_clavis_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SearchHit) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SpillHandle struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Number and encoded size of the spilled items.
+	Items int64 `protobuf:"varint,2,opt,name=items,proto3" json:"items,omitempty"`
+	Bytes int64 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// When the spill is removed unless fetched from again.
+	ExpiresUnixNano int64 `protobuf:"varint,4,opt,name=expires_unix_nano,json=expiresUnixNano,proto3" json:"expires_unix_nano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SpillHandle) Reset() {
+	*x = SpillHandle{}
+	mi := &file_api_proto_clavis_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpillHandle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpillHandle) ProtoMessage() {}
+
+func (x *SpillHandle) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpillHandle.ProtoReflect.Descriptor instead.
+func (*SpillHandle) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SpillHandle) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SpillHandle) GetItems() int64 {
+	if x != nil {
+		return x.Items
+	}
+	return 0
+}
+
+func (x *SpillHandle) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SpillHandle) GetExpiresUnixNano() int64 {
+	if x != nil {
+		return x.ExpiresUnixNano
+	}
+	return 0
+}
+
+type FetchSpillRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Prefix of the scan that produced the spill.
+	Prefix string `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Offset from a previous response; zero for the first chunk.
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Maximum encoded size of the chunk; zero selects the server default. At
+	// least one item is returned regardless.
+	MaxBytes int32 `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchSpillRequest) Reset() {
+	*x = FetchSpillRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchSpillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchSpillRequest) ProtoMessage() {}
+
+func (x *FetchSpillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchSpillRequest.ProtoReflect.Descriptor instead.
+func (*FetchSpillRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *FetchSpillRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FetchSpillRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *FetchSpillRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FetchSpillRequest) GetMaxBytes() int32 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *FetchSpillRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type FetchSpillResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Items      []*KeyValue            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextOffset int64                  `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	// True when the chunk ends the spill.
+	Done          bool `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchSpillResponse) Reset() {
+	*x = FetchSpillResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchSpillResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchSpillResponse) ProtoMessage() {}
+
+func (x *FetchSpillResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchSpillResponse.ProtoReflect.Descriptor instead.
+func (*FetchSpillResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *FetchSpillResponse) GetItems() []*KeyValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *FetchSpillResponse) GetNextOffset() int64 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *FetchSpillResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type BatchPutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// When a key appears more than once, the last item wins.
+	Items []*KeyValue `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPutRequest) Reset() {
+	*x = BatchPutRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPutRequest) ProtoMessage() {}
+
+func (x *BatchPutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPutRequest.ProtoReflect.Descriptor instead.
+func (*BatchPutRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *BatchPutRequest) GetItems() []*KeyValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchPutRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type BatchPutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warnings      []*Warning             `protobuf:"bytes,1,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPutResponse) Reset() {
+	*x = BatchPutResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPutResponse) ProtoMessage() {}
+
+func (x *BatchPutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPutResponse.ProtoReflect.Descriptor instead.
+func (*BatchPutResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *BatchPutResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type BatchGetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Keys  []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRequest) Reset() {
+	*x = BatchGetRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRequest) ProtoMessage() {}
+
+func (x *BatchGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BatchGetRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *BatchGetRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type BatchGetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only keys that exist are returned, in request order.
+	Items []*KeyValue `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// Requested keys that do not exist, in request order, so callers need not
+	// compare the items with the request.
+	Missing       []string `protobuf:"bytes,2,rep,name=missing,proto3" json:"missing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetResponse) Reset() {
+	*x = BatchGetResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResponse) ProtoMessage() {}
+
+func (x *BatchGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *BatchGetResponse) GetItems() []*KeyValue {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchGetResponse) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+type BatchDeleteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Keys  []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteRequest) Reset() {
+	*x = BatchDeleteRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteRequest) ProtoMessage() {}
+
+func (x *BatchDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *BatchDeleteRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *BatchDeleteRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type BatchDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Warnings      []*Warning             `protobuf:"bytes,1,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteResponse) Reset() {
+	*x = BatchDeleteResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteResponse) ProtoMessage() {}
+
+func (x *BatchDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *BatchDeleteResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// DiffOperand is one side of a diff: a stored key, optionally at a past
+// version, or a literal JSON document.
+type DiffOperand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*DiffOperand_Key
+	//	*DiffOperand_Value
+	Source        isDiffOperand_Source `protobuf_oneof:"source"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffOperand) Reset() {
+	*x = DiffOperand{}
+	mi := &file_api_proto_clavis_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffOperand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffOperand) ProtoMessage() {}
+
+func (x *DiffOperand) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffOperand.ProtoReflect.Descriptor instead.
+func (*DiffOperand) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *DiffOperand) GetSource() isDiffOperand_Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *DiffOperand) GetKey() *KeyVersion {
+	if x != nil {
+		if x, ok := x.Source.(*DiffOperand_Key); ok {
+			return x.Key
+		}
+	}
+	return nil
+}
+
+func (x *DiffOperand) GetValue() []byte {
+	if x != nil {
+		if x, ok := x.Source.(*DiffOperand_Value); ok {
+			return x.Value
+		}
+	}
+	return nil
+}
+
+type isDiffOperand_Source interface {
+	isDiffOperand_Source()
+}
+
+type DiffOperand_Key struct {
+	Key *KeyVersion `protobuf:"bytes,1,opt,name=key,proto3,oneof"`
+}
+
+type DiffOperand_Value struct {
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3,oneof"`
+}
+
+func (*DiffOperand_Key) isDiffOperand_Source() {}
+
+func (*DiffOperand_Value) isDiffOperand_Source() {}
+
+type KeyVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Zero selects the latest value.
+	Version       uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyVersion) Reset() {
+	*x = KeyVersion{}
+	mi := &file_api_proto_clavis_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyVersion) ProtoMessage() {}
+
+func (x *KeyVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyVersion.ProtoReflect.Descriptor instead.
+func (*KeyVersion) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *KeyVersion) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyVersion) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Left          *DiffOperand           `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
+	Right         *DiffOperand           `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffRequest) Reset() {
+	*x = DiffRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffRequest) ProtoMessage() {}
+
+func (x *DiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffRequest.ProtoReflect.Descriptor instead.
+func (*DiffRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *DiffRequest) GetLeft() *DiffOperand {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *DiffRequest) GetRight() *DiffOperand {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+type DiffChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Op    DiffChange_Op          `protobuf:"varint,1,opt,name=op,proto3,enum=clavis.v1.DiffChange_Op" json:"op,omitempty"`
+	// JSON Pointer to the changed location; empty for the whole document.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// JSON encoding of the value before and after the change, unset when absent.
+	OldValue      []byte `protobuf:"bytes,3,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      []byte `protobuf:"bytes,4,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffChange) Reset() {
+	*x = DiffChange{}
+	mi := &file_api_proto_clavis_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffChange) ProtoMessage() {}
+
+func (x *DiffChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffChange.ProtoReflect.Descriptor instead.
+func (*DiffChange) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DiffChange) GetOp() DiffChange_Op {
+	if x != nil {
+		return x.Op
+	}
+	return DiffChange_OP_UNSPECIFIED
+}
+
+func (x *DiffChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiffChange) GetOldValue() []byte {
+	if x != nil {
+		return x.OldValue
+	}
+	return nil
+}
+
+func (x *DiffChange) GetNewValue() []byte {
+	if x != nil {
+		return x.NewValue
+	}
+	return nil
+}
+
+type DiffResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Changes []*DiffChange          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// Versions the stored operands resolved to, zero for literal documents.
+	LeftVersion   uint64 `protobuf:"varint,2,opt,name=left_version,json=leftVersion,proto3" json:"left_version,omitempty"`
+	RightVersion  uint64 `protobuf:"varint,3,opt,name=right_version,json=rightVersion,proto3" json:"right_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffResponse) Reset() {
+	*x = DiffResponse{}
+	mi := &file_api_proto_clavis_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffResponse) ProtoMessage() {}
+
+func (x *DiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffResponse.ProtoReflect.Descriptor instead.
+func (*DiffResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DiffResponse) GetChanges() []*DiffChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *DiffResponse) GetLeftVersion() uint64 {
+	if x != nil {
+		return x.LeftVersion
+	}
+	return 0
+}
+
+func (x *DiffResponse) GetRightVersion() uint64 {
+	if x != nil {
+		return x.RightVersion
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only changes to keys starting with the prefix are streamed.
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// When set, only changes to exactly this key are streamed; prefix must
+	// then be empty.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *WatchRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type WatchEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  WatchEvent_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=clavis.v1.WatchEvent_Type" json:"type,omitempty"`
+	Key   string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// New value for puts, empty for deletes and expirations.
+	Value         []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	TimeUnixNano  int64  `protobuf:"varint,4,opt,name=time_unix_nano,json=timeUnixNano,proto3" json:"time_unix_nano,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_api_proto_clavis_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_TYPE_UNSPECIFIED
+}
+
+func (x *WatchEvent) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetTimeUnixNano() int64 {
+	if x != nil {
+		return x.TimeUnixNano
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only changes to keys starting with the prefix are streamed.
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Namespace the keys belong to; see GetRequest.namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_api_proto_clavis_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_clavis_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_clavis_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SubscribeRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+var File_api_proto_clavis_proto protoreflect.FileDescriptor
+
+const file_api_proto_clavis_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/proto/clavis.proto\x12\tclavis.v1\"~\n" +
+	"\n" +
+	"GetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12!\n" +
+	"\fwith_version\x18\x02 \x01(\bR\vwithVersion\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1d\n" +
+	"\n" +
+	"at_version\x18\x04 \x01(\x04R\tatVersion\"S\n" +
+	"\vGetResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\"\x9a\x02\n" +
+	"\n" +
+	"PutRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x121\n" +
+	"\afencing\x18\x03 \x01(\v2\x17.clavis.v1.FencingTokenR\afencing\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x04H\x00R\x0fexpectedVersion\x88\x01\x01\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12$\n" +
+	"\x0emust_not_exist\x18\x06 \x01(\bR\fmustNotExist\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x14\n" +
+	"\x05lease\x18\b \x01(\x04R\x05leaseB\x13\n" +
+	"\x11_expected_version\"8\n" +
+	"\fFencingToken\x12\x12\n" +
+	"\x04lock\x18\x01 \x01(\tR\x04lock\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\x04R\x05token\"l\n" +
+	"\vLockRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x15\n" +
+	"\x06ttl_ms\x18\x03 \x01(\x03R\x05ttlMs\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"m\n" +
+	"\fLockResponse\x121\n" +
+	"\afencing\x18\x01 \x01(\v2\x17.clavis.v1.FencingTokenR\afencing\x12*\n" +
+	"\x11expires_unix_nano\x18\x02 \x01(\x03R\x0fexpiresUnixNano\"q\n" +
+	"\x10RenewLockRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\x04R\x05token\x12\x15\n" +
+	"\x06ttl_ms\x18\x03 \x01(\x03R\x05ttlMs\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"W\n" +
+	"\rUnlockRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\x04R\x05token\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\x10\n" +
+	"\x0eUnlockResponse\"\x89\x01\n" +
+	"\x13CreateUniqueRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12&\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x12.clavis.v1.KeyKindR\x04kind\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\fR\x05value\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\"B\n" +
+	"\x14CreateUniqueResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"e\n" +
+	"\x13NextSequenceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1c\n" +
+	"\tbandwidth\x18\x02 \x01(\x04R\tbandwidth\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"B\n" +
+	"\x14NextSequenceResponse\x12\x14\n" +
+	"\x05first\x18\x01 \x01(\x04R\x05first\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"\x93\x01\n" +
+	"\x13DeletePrefixRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12-\n" +
+	"\x12confirmation_token\x18\x04 \x01(\tR\x11confirmationToken\"\xa5\x01\n" +
+	"\x14DeletePrefixResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12-\n" +
+	"\x12confirmation_token\x18\x02 \x01(\tR\x11confirmationToken\x12H\n" +
+	"!confirmation_expires_at_unix_nano\x18\x03 \x01(\x03R\x1dconfirmationExpiresAtUnixNano\"\xb0\x01\n" +
+	"\vCopyRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1c\n" +
+	"\toverwrite\x18\x04 \x01(\bR\toverwrite\x12\x14\n" +
+	"\x05lease\x18\x05 \x01(\x04R\x05lease\x12\x15\n" +
+	"\x06ttl_ms\x18\x06 \x01(\x03R\x05ttlMs\"\x9a\x01\n" +
+	"\fCopyResponse\x12.\n" +
+	"\bwarnings\x18\x01 \x03(\v2\x12.clavis.v1.WarningR\bwarnings\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x14\n" +
+	"\x05lease\x18\x03 \x01(\x04R\x05lease\x12*\n" +
+	"\x11expires_unix_nano\x18\x04 \x01(\x03R\x0fexpiresUnixNano\"*\n" +
+	"\x11GrantLeaseRequest\x12\x15\n" +
+	"\x06ttl_ms\x18\x01 \x01(\x03R\x05ttlMs\"P\n" +
+	"\x12GrantLeaseResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12*\n" +
+	"\x11expires_unix_nano\x18\x02 \x01(\x03R\x0fexpiresUnixNano\"V\n" +
+	"\x12AttachLeaseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\x15\n" +
+	"\x13AttachLeaseResponse\"\"\n" +
+	"\x10KeepAliveRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"O\n" +
+	"\x11KeepAliveResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12*\n" +
+	"\x11expires_unix_nano\x18\x02 \x01(\x03R\x0fexpiresUnixNano\"$\n" +
+	"\x12RevokeLeaseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\":\n" +
+	"\x13RevokeLeaseResponse\x12#\n" +
+	"\rattached_keys\x18\x01 \x01(\x03R\fattachedKeys\"\xc4\x01\n" +
+	"\aWarning\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12<\n" +
+	"\bmetadata\x18\x04 \x03(\v2 .clavis.v1.Warning.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"W\n" +
+	"\vPutResponse\x12.\n" +
+	"\bwarnings\x18\x01 \x03(\v2\x12.clavis.v1.WarningR\bwarnings\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"?\n" +
+	"\rDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"@\n" +
+	"\x0eDeleteResponse\x12.\n" +
+	"\bwarnings\x18\x01 \x03(\v2\x12.clavis.v1.WarningR\bwarnings\"f\n" +
+	"\bKeyValue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x122\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x16.clavis.v1.KeyMetadataR\bmetadata\"\x88\x01\n" +
+	"\vKeyMetadata\x12*\n" +
+	"\x11created_unix_nano\x18\x01 \x01(\x03R\x0fcreatedUnixNano\x12*\n" +
+	"\x11updated_unix_nano\x18\x02 \x01(\x03R\x0fupdatedUnixNano\x12!\n" +
+	"\fupdate_count\x18\x03 \x01(\x04R\vupdateCount\"\x9f\x02\n" +
+	"\vScanRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x1f\n" +
+	"\vallow_spill\x18\x04 \x01(\bR\n" +
+	"allowSpill\x12%\n" +
+	"\x0fas_of_unix_nano\x18\x05 \x01(\x03R\fasOfUnixNano\x12\"\n" +
+	"\ras_of_version\x18\x06 \x01(\x04R\vasOfVersion\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tkeys_only\x18\b \x01(\bR\bkeysOnly\x12#\n" +
+	"\rwith_metadata\x18\t \x01(\bR\fwithMetadata\"\xac\x01\n" +
+	"\fScanResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.clavis.v1.KeyValueR\x05items\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12,\n" +
+	"\x05spill\x18\x03 \x01(\v2\x16.clavis.v1.SpillHandleR\x05spill\x12\"\n" +
+	"\ras_of_version\x18\x04 \x01(\x04R\vasOfVersion\"\xb9\x01\n" +
+	"\fRangeRequest\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x18\n" +
+	"\areverse\x18\x04 \x01(\bR\areverse\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tkeys_only\x18\x06 \x01(\bR\bkeysOnly\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\"[\n" +
+	"\rRangeResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.clavis.v1.KeyValueR\x05items\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"?\n" +
+	"\rExistsRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"&\n" +
+	"\x0eExistsResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\"D\n" +
+	"\fCountRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"%\n" +
+	"\rCountResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"\x90\x01\n" +
+	"\x10IncrementRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x03R\x05delta\x126\n" +
+	"\bencoding\x18\x03 \x01(\x0e2\x1a.clavis.v1.CounterEncodingR\bencoding\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\")\n" +
+	"\x11IncrementResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\"A\n" +
+	"\x0fUndeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"\x12\n" +
+	"\x10UndeleteResponse\"D\n" +
+	"\x12GetMetadataRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"_\n" +
+	"\x13GetMetadataResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x122\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x16.clavis.v1.KeyMetadataR\bmetadata\"\x89\x01\n" +
+	"\x11QueryByTagRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\"I\n" +
+	"\x12QueryByTagResponse\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"q\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\tnamespace\x18\x04 \x01(\tR\tnamespace\":\n" +
+	"\x0eSearchResponse\x12(\n" +
+	"\x04hits\x18\x01 \x03(\v2\x14.clavis.v1.SearchHitR\x04hits\"3\n" +
+	"\tSearchHit\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"u\n" +
+	"\vSpillHandle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05items\x18\x02 \x01(\x03R\x05items\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x12*\n" +
+	"\x11expires_unix_nano\x18\x04 \x01(\x03R\x0fexpiresUnixNano\"\x8e\x01\n" +
+	"\x11FetchSpillRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x1b\n" +
+	"\tmax_bytes\x18\x04 \x01(\x05R\bmaxBytes\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\"t\n" +
+	"\x12FetchSpillResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.clavis.v1.KeyValueR\x05items\x12\x1f\n" +
+	"\vnext_offset\x18\x02 \x01(\x03R\n" +
+	"nextOffset\x12\x12\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\"Z\n" +
+	"\x0fBatchPutRequest\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.clavis.v1.KeyValueR\x05items\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"B\n" +
+	"\x10BatchPutResponse\x12.\n" +
+	"\bwarnings\x18\x01 \x03(\v2\x12.clavis.v1.WarningR\bwarnings\"C\n" +
+	"\x0fBatchGetRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"W\n" +
+	"\x10BatchGetResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.clavis.v1.KeyValueR\x05items\x12\x18\n" +
+	"\amissing\x18\x02 \x03(\tR\amissing\"F\n" +
+	"\x12BatchDeleteRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"E\n" +
+	"\x13BatchDeleteResponse\x12.\n" +
+	"\bwarnings\x18\x01 \x03(\v2\x12.clavis.v1.WarningR\bwarnings\"Z\n" +
+	"\vDiffOperand\x12)\n" +
+	"\x03key\x18\x01 \x01(\v2\x15.clavis.v1.KeyVersionH\x00R\x03key\x12\x16\n" +
+	"\x05value\x18\x02 \x01(\fH\x00R\x05valueB\b\n" +
+	"\x06source\"8\n" +
+	"\n" +
+	"KeyVersion\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"g\n" +
+	"\vDiffRequest\x12*\n" +
+	"\x04left\x18\x01 \x01(\v2\x16.clavis.v1.DiffOperandR\x04left\x12,\n" +
+	"\x05right\x18\x02 \x01(\v2\x16.clavis.v1.DiffOperandR\x05right\"\xcc\x01\n" +
+	"\n" +
+	"DiffChange\x12(\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x18.clavis.v1.DiffChange.OpR\x02op\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1b\n" +
+	"\told_value\x18\x03 \x01(\fR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x04 \x01(\fR\bnewValue\"F\n" +
+	"\x02Op\x12\x12\n" +
+	"\x0eOP_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bOP_ADDED\x10\x01\x12\x0e\n" +
+	"\n" +
+	"OP_REMOVED\x10\x02\x12\x0e\n" +
+	"\n" +
+	"OP_CHANGED\x10\x03\"\x87\x01\n" +
+	"\fDiffResponse\x12/\n" +
+	"\achanges\x18\x01 \x03(\v2\x15.clavis.v1.DiffChangeR\achanges\x12!\n" +
+	"\fleft_version\x18\x02 \x01(\x04R\vleftVersion\x12#\n" +
+	"\rright_version\x18\x03 \x01(\x04R\frightVersion\"V\n" +
+	"\fWatchRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\xd8\x01\n" +
+	"\n" +
+	"WatchEvent\x12.\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1a.clavis.v1.WatchEvent.TypeR\x04type\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\fR\x05value\x12$\n" +
+	"\x0etime_unix_nano\x18\x04 \x01(\x03R\ftimeUnixNano\"L\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bTYPE_PUT\x10\x01\x12\x0f\n" +
+	"\vTYPE_DELETE\x10\x02\x12\x0f\n" +
+	"\vTYPE_EXPIRE\x10\x03\"H\n" +
+	"\x10SubscribeRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace*F\n" +
+	"\aKeyKind\x12\x11\n" +
+	"\rKEY_KIND_ULID\x10\x00\x12\x11\n" +
+	"\rKEY_KIND_UUID\x10\x01\x12\x15\n" +
+	"\x11KEY_KIND_SEQUENCE\x10\x02*Q\n" +
+	"\x0fCounterEncoding\x12\x1a\n" +
+	"\x16COUNTER_ENCODING_ASCII\x10\x00\x12\"\n" +
+	"\x1eCOUNTER_ENCODING_LITTLE_ENDIAN\x10\x012\xf7\x10\n" +
+	"\x06Clavis\x126\n" +
+	"\x03Get\x12\x15.clavis.v1.GetRequest\x1a\x16.clavis.v1.GetResponse\"\x00\x126\n" +
+	"\x03Put\x12\x15.clavis.v1.PutRequest\x1a\x16.clavis.v1.PutResponse\"\x00\x12?\n" +
+	"\x06Delete\x12\x18.clavis.v1.DeleteRequest\x1a\x19.clavis.v1.DeleteResponse\"\x00\x129\n" +
+	"\x04Scan\x12\x16.clavis.v1.ScanRequest\x1a\x17.clavis.v1.ScanResponse\"\x00\x12=\n" +
+	"\n" +
+	"ScanStream\x12\x16.clavis.v1.ScanRequest\x1a\x13.clavis.v1.KeyValue\"\x000\x01\x12E\n" +
+	"\bBatchPut\x12\x1a.clavis.v1.BatchPutRequest\x1a\x1b.clavis.v1.BatchPutResponse\"\x00\x12E\n" +
+	"\bBatchGet\x12\x1a.clavis.v1.BatchGetRequest\x1a\x1b.clavis.v1.BatchGetResponse\"\x00\x12N\n" +
+	"\vBatchDelete\x12\x1d.clavis.v1.BatchDeleteRequest\x1a\x1e.clavis.v1.BatchDeleteResponse\"\x00\x129\n" +
+	"\x04Diff\x12\x16.clavis.v1.DiffRequest\x1a\x17.clavis.v1.DiffResponse\"\x00\x12;\n" +
+	"\x05Watch\x12\x17.clavis.v1.WatchRequest\x1a\x15.clavis.v1.WatchEvent\"\x000\x01\x12K\n" +
+	"\n" +
+	"FetchSpill\x12\x1c.clavis.v1.FetchSpillRequest\x1a\x1d.clavis.v1.FetchSpillResponse\"\x00\x12<\n" +
+	"\x05Range\x12\x17.clavis.v1.RangeRequest\x1a\x18.clavis.v1.RangeResponse\"\x00\x12?\n" +
+	"\x06Exists\x12\x18.clavis.v1.ExistsRequest\x1a\x19.clavis.v1.ExistsResponse\"\x00\x12<\n" +
+	"\x05Count\x12\x17.clavis.v1.CountRequest\x1a\x18.clavis.v1.CountResponse\"\x00\x12H\n" +
+	"\tIncrement\x12\x1b.clavis.v1.IncrementRequest\x1a\x1c.clavis.v1.IncrementResponse\"\x00\x12E\n" +
+	"\bUndelete\x12\x1a.clavis.v1.UndeleteRequest\x1a\x1b.clavis.v1.UndeleteResponse\"\x00\x12N\n" +
+	"\vGetMetadata\x12\x1d.clavis.v1.GetMetadataRequest\x1a\x1e.clavis.v1.GetMetadataResponse\"\x00\x12K\n" +
+	"\n" +
+	"QueryByTag\x12\x1c.clavis.v1.QueryByTagRequest\x1a\x1d.clavis.v1.QueryByTagResponse\"\x00\x12?\n" +
+	"\x06Search\x12\x18.clavis.v1.SearchRequest\x1a\x19.clavis.v1.SearchResponse\"\x00\x12C\n" +
+	"\tSubscribe\x12\x1b.clavis.v1.SubscribeRequest\x1a\x15.clavis.v1.WatchEvent\"\x000\x01\x129\n" +
+	"\x04Lock\x12\x16.clavis.v1.LockRequest\x1a\x17.clavis.v1.LockResponse\"\x00\x12C\n" +
+	"\tRenewLock\x12\x1b.clavis.v1.RenewLockRequest\x1a\x17.clavis.v1.LockResponse\"\x00\x12?\n" +
+	"\x06Unlock\x12\x18.clavis.v1.UnlockRequest\x1a\x19.clavis.v1.UnlockResponse\"\x00\x12K\n" +
+	"\n" +
+	"GrantLease\x12\x1c.clavis.v1.GrantLeaseRequest\x1a\x1d.clavis.v1.GrantLeaseResponse\"\x00\x12N\n" +
+	"\vAttachLease\x12\x1d.clavis.v1.AttachLeaseRequest\x1a\x1e.clavis.v1.AttachLeaseResponse\"\x00\x12L\n" +
+	"\tKeepAlive\x12\x1b.clavis.v1.KeepAliveRequest\x1a\x1c.clavis.v1.KeepAliveResponse\"\x00(\x010\x01\x12N\n" +
+	"\vRevokeLease\x12\x1d.clavis.v1.RevokeLeaseRequest\x1a\x1e.clavis.v1.RevokeLeaseResponse\"\x00\x12Q\n" +
+	"\fCreateUnique\x12\x1e.clavis.v1.CreateUniqueRequest\x1a\x1f.clavis.v1.CreateUniqueResponse\"\x00\x12Q\n" +
+	"\fNextSequence\x12\x1e.clavis.v1.NextSequenceRequest\x1a\x1f.clavis.v1.NextSequenceResponse\"\x00\x12Q\n" +
+	"\fDeletePrefix\x12\x1e.clavis.v1.DeletePrefixRequest\x1a\x1f.clavis.v1.DeletePrefixResponse\"\x00\x129\n" +
+	"\x04Copy\x12\x16.clavis.v1.CopyRequest\x1a\x17.clavis.v1.CopyResponse\"\x00B1Z/github.com/yourusername/clavis/api/proto;clavisb\x06proto3"
+
+var (
+	file_api_proto_clavis_proto_rawDescOnce sync.Once
+	file_api_proto_clavis_proto_rawDescData []byte
+)
+
+func file_api_proto_clavis_proto_rawDescGZIP() []byte {
+	file_api_proto_clavis_proto_rawDescOnce.Do(func() {
+		file_api_proto_clavis_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_clavis_proto_rawDesc), len(file_api_proto_clavis_proto_rawDesc)))
+	})
+	return file_api_proto_clavis_proto_rawDescData
+}
+
+var file_api_proto_clavis_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_api_proto_clavis_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
+var file_api_proto_clavis_proto_goTypes = []any{
+	(KeyKind)(0),                 // 0: clavis.v1.KeyKind
+	(CounterEncoding)(0),         // 1: clavis.v1.CounterEncoding
+	(DiffChange_Op)(0),           // 2: clavis.v1.DiffChange.Op
+	(WatchEvent_Type)(0),         // 3: clavis.v1.WatchEvent.Type
+	(*GetRequest)(nil),           // 4: clavis.v1.GetRequest
+	(*GetResponse)(nil),          // 5: clavis.v1.GetResponse
+	(*PutRequest)(nil),           // 6: clavis.v1.PutRequest
+	(*FencingToken)(nil),         // 7: clavis.v1.FencingToken
+	(*LockRequest)(nil),          // 8: clavis.v1.LockRequest
+	(*LockResponse)(nil),         // 9: clavis.v1.LockResponse
+	(*RenewLockRequest)(nil),     // 10: clavis.v1.RenewLockRequest
+	(*UnlockRequest)(nil),        // 11: clavis.v1.UnlockRequest
+	(*UnlockResponse)(nil),       // 12: clavis.v1.UnlockResponse
+	(*CreateUniqueRequest)(nil),  // 13: clavis.v1.CreateUniqueRequest
+	(*CreateUniqueResponse)(nil), // 14: clavis.v1.CreateUniqueResponse
+	(*NextSequenceRequest)(nil),  // 15: clavis.v1.NextSequenceRequest
+	(*NextSequenceResponse)(nil), // 16: clavis.v1.NextSequenceResponse
+	(*DeletePrefixRequest)(nil),  // 17: clavis.v1.DeletePrefixRequest
+	(*DeletePrefixResponse)(nil), // 18: clavis.v1.DeletePrefixResponse
+	(*CopyRequest)(nil),          // 19: clavis.v1.CopyRequest
+	(*CopyResponse)(nil),         // 20: clavis.v1.CopyResponse
+	(*GrantLeaseRequest)(nil),    // 21: clavis.v1.GrantLeaseRequest
+	(*GrantLeaseResponse)(nil),   // 22: clavis.v1.GrantLeaseResponse
+	(*AttachLeaseRequest)(nil),   // 23: clavis.v1.AttachLeaseRequest
+	(*AttachLeaseResponse)(nil),  // 24: clavis.v1.AttachLeaseResponse
+	(*KeepAliveRequest)(nil),     // 25: clavis.v1.KeepAliveRequest
+	(*KeepAliveResponse)(nil),    // 26: clavis.v1.KeepAliveResponse
+	(*RevokeLeaseRequest)(nil),   // 27: clavis.v1.RevokeLeaseRequest
+	(*RevokeLeaseResponse)(nil),  // 28: clavis.v1.RevokeLeaseResponse
+	(*Warning)(nil),              // 29: clavis.v1.Warning
+	(*PutResponse)(nil),          // 30: clavis.v1.PutResponse
+	(*DeleteRequest)(nil),        // 31: clavis.v1.DeleteRequest
+	(*DeleteResponse)(nil),       // 32: clavis.v1.DeleteResponse
+	(*KeyValue)(nil),             // 33: clavis.v1.KeyValue
+	(*KeyMetadata)(nil),          // 34: clavis.v1.KeyMetadata
+	(*ScanRequest)(nil),          // 35: clavis.v1.ScanRequest
+	(*ScanResponse)(nil),         // 36: clavis.v1.ScanResponse
+	(*RangeRequest)(nil),         // 37: clavis.v1.RangeRequest
+	(*RangeResponse)(nil),        // 38: clavis.v1.RangeResponse
+	(*ExistsRequest)(nil),        // 39: clavis.v1.ExistsRequest
+	(*ExistsResponse)(nil),       // 40: clavis.v1.ExistsResponse
+	(*CountRequest)(nil),         // 41: clavis.v1.CountRequest
+	(*CountResponse)(nil),        // 42: clavis.v1.CountResponse
+	(*IncrementRequest)(nil),     // 43: clavis.v1.IncrementRequest
+	(*IncrementResponse)(nil),    // 44: clavis.v1.IncrementResponse
+	(*UndeleteRequest)(nil),      // 45: clavis.v1.UndeleteRequest
+	(*UndeleteResponse)(nil),     // 46: clavis.v1.UndeleteResponse
+	(*GetMetadataRequest)(nil),   // 47: clavis.v1.GetMetadataRequest
+	(*GetMetadataResponse)(nil),  // 48: clavis.v1.GetMetadataResponse
+	(*QueryByTagRequest)(nil),    // 49: clavis.v1.QueryByTagRequest
+	(*QueryByTagResponse)(nil),   // 50: clavis.v1.QueryByTagResponse
+	(*SearchRequest)(nil),        // 51: clavis.v1.SearchRequest
+	(*SearchResponse)(nil),       // 52: clavis.v1.SearchResponse
+	(*SearchHit)(nil),            // 53: clavis.v1.SearchHit
+	(*SpillHandle)(nil),          // 54: clavis.v1.SpillHandle
+	(*FetchSpillRequest)(nil),    // 55: clavis.v1.FetchSpillRequest
+	(*FetchSpillResponse)(nil),   // 56: clavis.v1.FetchSpillResponse
+	(*BatchPutRequest)(nil),      // 57: clavis.v1.BatchPutRequest
+	(*BatchPutResponse)(nil),     // 58: clavis.v1.BatchPutResponse
+	(*BatchGetRequest)(nil),      // 59: clavis.v1.BatchGetRequest
+	(*BatchGetResponse)(nil),     // 60: clavis.v1.BatchGetResponse
+	(*BatchDeleteRequest)(nil),   // 61: clavis.v1.BatchDeleteRequest
+	(*BatchDeleteResponse)(nil),  // 62: clavis.v1.BatchDeleteResponse
+	(*DiffOperand)(nil),          // 63: clavis.v1.DiffOperand
+	(*KeyVersion)(nil),           // 64: clavis.v1.KeyVersion
+	(*DiffRequest)(nil),          // 65: clavis.v1.DiffRequest
+	(*DiffChange)(nil),           // 66: clavis.v1.DiffChange
+	(*DiffResponse)(nil),         // 67: clavis.v1.DiffResponse
+	(*WatchRequest)(nil),         // 68: clavis.v1.WatchRequest
+	(*WatchEvent)(nil),           // 69: clavis.v1.WatchEvent
+	(*SubscribeRequest)(nil),     // 70: clavis.v1.SubscribeRequest
+	nil,                          // 71: clavis.v1.Warning.MetadataEntry
+}
+var file_api_proto_clavis_proto_depIdxs = []int32{
+	7,  // 0: clavis.v1.PutRequest.fencing:type_name -> clavis.v1.FencingToken
+	7,  // 1: clavis.v1.LockResponse.fencing:type_name -> clavis.v1.FencingToken
+	0,  // 2: clavis.v1.CreateUniqueRequest.kind:type_name -> clavis.v1.KeyKind
+	29, // 3: clavis.v1.CopyResponse.warnings:type_name -> clavis.v1.Warning
+	71, // 4: clavis.v1.Warning.metadata:type_name -> clavis.v1.Warning.MetadataEntry
+	29, // 5: clavis.v1.PutResponse.warnings:type_name -> clavis.v1.Warning
+	29, // 6: clavis.v1.DeleteResponse.warnings:type_name -> clavis.v1.Warning
+	34, // 7: clavis.v1.KeyValue.metadata:type_name -> clavis.v1.KeyMetadata
+	33, // 8: clavis.v1.ScanResponse.items:type_name -> clavis.v1.KeyValue
+	54, // 9: clavis.v1.ScanResponse.spill:type_name -> clavis.v1.SpillHandle
+	33, // 10: clavis.v1.RangeResponse.items:type_name -> clavis.v1.KeyValue
+	1,  // 11: clavis.v1.IncrementRequest.encoding:type_name -> clavis.v1.CounterEncoding
+	34, // 12: clavis.v1.GetMetadataResponse.metadata:type_name -> clavis.v1.KeyMetadata
+	53, // 13: clavis.v1.SearchResponse.hits:type_name -> clavis.v1.SearchHit
+	33, // 14: clavis.v1.FetchSpillResponse.items:type_name -> clavis.v1.KeyValue
+	33, // 15: clavis.v1.BatchPutRequest.items:type_name -> clavis.v1.KeyValue
+	29, // 16: clavis.v1.BatchPutResponse.warnings:type_name -> clavis.v1.Warning
+	33, // 17: clavis.v1.BatchGetResponse.items:type_name -> clavis.v1.KeyValue
+	29, // 18: clavis.v1.BatchDeleteResponse.warnings:type_name -> clavis.v1.Warning
+	64, // 19: clavis.v1.DiffOperand.key:type_name -> clavis.v1.KeyVersion
+	63, // 20: clavis.v1.DiffRequest.left:type_name -> clavis.v1.DiffOperand
+	63, // 21: clavis.v1.DiffRequest.right:type_name -> clavis.v1.DiffOperand
+	2,  // 22: clavis.v1.DiffChange.op:type_name -> clavis.v1.DiffChange.Op
+	66, // 23: clavis.v1.DiffResponse.changes:type_name -> clavis.v1.DiffChange
+	3,  // 24: clavis.v1.WatchEvent.type:type_name -> clavis.v1.WatchEvent.Type
+	4,  // 25: clavis.v1.Clavis.Get:input_type -> clavis.v1.GetRequest
+	6,  // 26: clavis.v1.Clavis.Put:input_type -> clavis.v1.PutRequest
+	31, // 27: clavis.v1.Clavis.Delete:input_type -> clavis.v1.DeleteRequest
+	35, // 28: clavis.v1.Clavis.Scan:input_type -> clavis.v1.ScanRequest
+	35, // 29: clavis.v1.Clavis.ScanStream:input_type -> clavis.v1.ScanRequest
+	57, // 30: clavis.v1.Clavis.BatchPut:input_type -> clavis.v1.BatchPutRequest
+	59, // 31: clavis.v1.Clavis.BatchGet:input_type -> clavis.v1.BatchGetRequest
+	61, // 32: clavis.v1.Clavis.BatchDelete:input_type -> clavis.v1.BatchDeleteRequest
+	65, // 33: clavis.v1.Clavis.Diff:input_type -> clavis.v1.DiffRequest
+	68, // 34: clavis.v1.Clavis.Watch:input_type -> clavis.v1.WatchRequest
+	55, // 35: clavis.v1.Clavis.FetchSpill:input_type -> clavis.v1.FetchSpillRequest
+	37, // 36: clavis.v1.Clavis.Range:input_type -> clavis.v1.RangeRequest
+	39, // 37: clavis.v1.Clavis.Exists:input_type -> clavis.v1.ExistsRequest
+	41, // 38: clavis.v1.Clavis.Count:input_type -> clavis.v1.CountRequest
+	43, // 39: clavis.v1.Clavis.Increment:input_type -> clavis.v1.IncrementRequest
+	45, // 40: clavis.v1.Clavis.Undelete:input_type -> clavis.v1.UndeleteRequest
+	47, // 41: clavis.v1.Clavis.GetMetadata:input_type -> clavis.v1.GetMetadataRequest
+	49, // 42: clavis.v1.Clavis.QueryByTag:input_type -> clavis.v1.QueryByTagRequest
+	51, // 43: clavis.v1.Clavis.Search:input_type -> clavis.v1.SearchRequest
+	70, // 44: clavis.v1.Clavis.Subscribe:input_type -> clavis.v1.SubscribeRequest
+	8,  // 45: clavis.v1.Clavis.Lock:input_type -> clavis.v1.LockRequest
+	10, // 46: clavis.v1.Clavis.RenewLock:input_type -> clavis.v1.RenewLockRequest
+	11, // 47: clavis.v1.Clavis.Unlock:input_type -> clavis.v1.UnlockRequest
+	21, // 48: clavis.v1.Clavis.GrantLease:input_type -> clavis.v1.GrantLeaseRequest
+	23, // 49: clavis.v1.Clavis.AttachLease:input_type -> clavis.v1.AttachLeaseRequest
+	25, // 50: clavis.v1.Clavis.KeepAlive:input_type -> clavis.v1.KeepAliveRequest
+	27, // 51: clavis.v1.Clavis.RevokeLease:input_type -> clavis.v1.RevokeLeaseRequest
+	13, // 52: clavis.v1.Clavis.CreateUnique:input_type -> clavis.v1.CreateUniqueRequest
+	15, // 53: clavis.v1.Clavis.NextSequence:input_type -> clavis.v1.NextSequenceRequest
+	17, // 54: clavis.v1.Clavis.DeletePrefix:input_type -> clavis.v1.DeletePrefixRequest
+	19, // 55: clavis.v1.Clavis.Copy:input_type -> clavis.v1.CopyRequest
+	5,  // 56: clavis.v1.Clavis.Get:output_type -> clavis.v1.GetResponse
+	30, // 57: clavis.v1.Clavis.Put:output_type -> clavis.v1.PutResponse
+	32, // 58: clavis.v1.Clavis.Delete:output_type -> clavis.v1.DeleteResponse
+	36, // 59: clavis.v1.Clavis.Scan:output_type -> clavis.v1.ScanResponse
+	33, // 60: clavis.v1.Clavis.ScanStream:output_type -> clavis.v1.KeyValue
+	58, // 61: clavis.v1.Clavis.BatchPut:output_type -> clavis.v1.BatchPutResponse
+	60, // 62: clavis.v1.Clavis.BatchGet:output_type -> clavis.v1.BatchGetResponse
+	62, // 63: clavis.v1.Clavis.BatchDelete:output_type -> clavis.v1.BatchDeleteResponse
+	67, // 64: clavis.v1.Clavis.Diff:output_type -> clavis.v1.DiffResponse
+	69, // 65: clavis.v1.Clavis.Watch:output_type -> clavis.v1.WatchEvent
+	56, // 66: clavis.v1.Clavis.FetchSpill:output_type -> clavis.v1.FetchSpillResponse
+	38, // 67: clavis.v1.Clavis.Range:output_type -> clavis.v1.RangeResponse
+	40, // 68: clavis.v1.Clavis.Exists:output_type -> clavis.v1.ExistsResponse
+	42, // 69: clavis.v1.Clavis.Count:output_type -> clavis.v1.CountResponse
+	44, // 70: clavis.v1.Clavis.Increment:output_type -> clavis.v1.IncrementResponse
+	46, // 71: clavis.v1.Clavis.Undelete:output_type -> clavis.v1.UndeleteResponse
+	48, // 72: clavis.v1.Clavis.GetMetadata:output_type -> clavis.v1.GetMetadataResponse
+	50, // 73: clavis.v1.Clavis.QueryByTag:output_type -> clavis.v1.QueryByTagResponse
+	52, // 74: clavis.v1.Clavis.Search:output_type -> clavis.v1.SearchResponse
+	69, // 75: clavis.v1.Clavis.Subscribe:output_type -> clavis.v1.WatchEvent
+	9,  // 76: clavis.v1.Clavis.Lock:output_type -> clavis.v1.LockResponse
+	9,  // 77: clavis.v1.Clavis.RenewLock:output_type -> clavis.v1.LockResponse
+	12, // 78: clavis.v1.Clavis.Unlock:output_type -> clavis.v1.UnlockResponse
+	22, // 79: clavis.v1.Clavis.GrantLease:output_type -> clavis.v1.GrantLeaseResponse
+	24, // 80: clavis.v1.Clavis.AttachLease:output_type -> clavis.v1.AttachLeaseResponse
+	26, // 81: clavis.v1.Clavis.KeepAlive:output_type -> clavis.v1.KeepAliveResponse
+	28, // 82: clavis.v1.Clavis.RevokeLease:output_type -> clavis.v1.RevokeLeaseResponse
+	14, // 83: clavis.v1.Clavis.CreateUnique:output_type -> clavis.v1.CreateUniqueResponse
+	16, // 84: clavis.v1.Clavis.NextSequence:output_type -> clavis.v1.NextSequenceResponse
+	18, // 85: clavis.v1.Clavis.DeletePrefix:output_type -> clavis.v1.DeletePrefixResponse
+	20, // 86: clavis.v1.Clavis.Copy:output_type -> clavis.v1.CopyResponse
+	56, // [56:87] is the sub-list for method output_type
+	25, // [25:56] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_clavis_proto_init() }
+func file_api_proto_clavis_proto_init() {
+	if File_api_proto_clavis_proto != nil {
+		return
+	}
+	file_api_proto_clavis_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_proto_clavis_proto_msgTypes[59].OneofWrappers = []any{
+		(*DiffOperand_Key)(nil),
+		(*DiffOperand_Value)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_clavis_proto_rawDesc), len(file_api_proto_clavis_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      4,
+			NumMessages:   68,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_clavis_proto_goTypes,
 		DependencyIndexes: file_api_proto_clavis_proto_depIdxs,
+		EnumInfos:         file_api_proto_clavis_proto_enumTypes,
 		MessageInfos:      file_api_proto_clavis_proto_msgTypes,
 	}.Build()
 	File_api_proto_clavis_proto = out.File
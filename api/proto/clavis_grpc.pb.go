@@ -20,9 +20,37 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Clavis_Get_FullMethodName    = "/clavis.v1.Clavis/Get"
-	Clavis_Put_FullMethodName    = "/clavis.v1.Clavis/Put"
-	Clavis_Delete_FullMethodName = "/clavis.v1.Clavis/Delete"
+	Clavis_Get_FullMethodName          = "/clavis.v1.Clavis/Get"
+	Clavis_Put_FullMethodName          = "/clavis.v1.Clavis/Put"
+	Clavis_Delete_FullMethodName       = "/clavis.v1.Clavis/Delete"
+	Clavis_Scan_FullMethodName         = "/clavis.v1.Clavis/Scan"
+	Clavis_ScanStream_FullMethodName   = "/clavis.v1.Clavis/ScanStream"
+	Clavis_BatchPut_FullMethodName     = "/clavis.v1.Clavis/BatchPut"
+	Clavis_BatchGet_FullMethodName     = "/clavis.v1.Clavis/BatchGet"
+	Clavis_BatchDelete_FullMethodName  = "/clavis.v1.Clavis/BatchDelete"
+	Clavis_Diff_FullMethodName         = "/clavis.v1.Clavis/Diff"
+	Clavis_Watch_FullMethodName        = "/clavis.v1.Clavis/Watch"
+	Clavis_FetchSpill_FullMethodName   = "/clavis.v1.Clavis/FetchSpill"
+	Clavis_Range_FullMethodName        = "/clavis.v1.Clavis/Range"
+	Clavis_Exists_FullMethodName       = "/clavis.v1.Clavis/Exists"
+	Clavis_Count_FullMethodName        = "/clavis.v1.Clavis/Count"
+	Clavis_Increment_FullMethodName    = "/clavis.v1.Clavis/Increment"
+	Clavis_Undelete_FullMethodName     = "/clavis.v1.Clavis/Undelete"
+	Clavis_GetMetadata_FullMethodName  = "/clavis.v1.Clavis/GetMetadata"
+	Clavis_QueryByTag_FullMethodName   = "/clavis.v1.Clavis/QueryByTag"
+	Clavis_Search_FullMethodName       = "/clavis.v1.Clavis/Search"
+	Clavis_Subscribe_FullMethodName    = "/clavis.v1.Clavis/Subscribe"
+	Clavis_Lock_FullMethodName         = "/clavis.v1.Clavis/Lock"
+	Clavis_RenewLock_FullMethodName    = "/clavis.v1.Clavis/RenewLock"
+	Clavis_Unlock_FullMethodName       = "/clavis.v1.Clavis/Unlock"
+	Clavis_GrantLease_FullMethodName   = "/clavis.v1.Clavis/GrantLease"
+	Clavis_AttachLease_FullMethodName  = "/clavis.v1.Clavis/AttachLease"
+	Clavis_KeepAlive_FullMethodName    = "/clavis.v1.Clavis/KeepAlive"
+	Clavis_RevokeLease_FullMethodName  = "/clavis.v1.Clavis/RevokeLease"
+	Clavis_CreateUnique_FullMethodName = "/clavis.v1.Clavis/CreateUnique"
+	Clavis_NextSequence_FullMethodName = "/clavis.v1.Clavis/NextSequence"
+	Clavis_DeletePrefix_FullMethodName = "/clavis.v1.Clavis/DeletePrefix"
+	Clavis_Copy_FullMethodName         = "/clavis.v1.Clavis/Copy"
 )
 
 // ClavisClient is the client API for Clavis service.
@@ -32,6 +60,77 @@ type ClavisClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	ScanStream(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[KeyValue], error)
+	BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+	// Returns a chunk of a scan result the server spilled to disk.
+	FetchSpill(ctx context.Context, in *FetchSpillRequest, opts ...grpc.CallOption) (*FetchSpillResponse, error)
+	// Returns the pairs whose keys are in a range, in key order or reversed.
+	Range(ctx context.Context, in *RangeRequest, opts ...grpc.CallOption) (*RangeResponse, error)
+	// Reports whether a key exists without returning its value.
+	Exists(ctx context.Context, in *ExistsRequest, opts ...grpc.CallOption) (*ExistsResponse, error)
+	// Returns the number of keys starting with a prefix without reading their
+	// values.
+	Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	// Adds to the integer counter stored at a key atomically and returns the
+	// new value.
+	Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResponse, error)
+	// Restores the value a key had when it was deleted, while the server keeps
+	// deleted values.
+	Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error)
+	// Returns when a key was created and last updated, and how many times it
+	// was written, while the server tracks key metadata.
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	// Lists the keys carrying a tag, in key order, while the server indexes
+	// tags.
+	QueryByTag(ctx context.Context, in *QueryByTagRequest, opts ...grpc.CallOption) (*QueryByTagResponse, error)
+	// Finds the keys whose values contain every term of a query, best matches
+	// first, while the server indexes values.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// Streams the changes to keys starting with a prefix, with their values,
+	// from a buffer of its own. Unlike Watch, a subscriber that falls too far
+	// behind is ended with RESOURCE_EXHAUSTED instead of missing events.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+	// Acquires a lease on a lock, failing with FAILED_PRECONDITION while
+	// another lease on it is live. The fencing token returned is accepted by
+	// Put until a newer lease on the lock is granted.
+	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error)
+	// Extends a live lease; holders renew well before it expires.
+	RenewLock(ctx context.Context, in *RenewLockRequest, opts ...grpc.CallOption) (*LockResponse, error)
+	// Releases a lease so the lock can be acquired again at once.
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
+	// Grants a lease that keys can be attached to, with Put or AttachLease.
+	// When the lease expires or is revoked, its keys are deleted.
+	GrantLease(ctx context.Context, in *GrantLeaseRequest, opts ...grpc.CallOption) (*GrantLeaseResponse, error)
+	// Attaches existing keys to a live lease.
+	AttachLease(ctx context.Context, in *AttachLeaseRequest, opts ...grpc.CallOption) (*AttachLeaseResponse, error)
+	// Keeps the leases named by the requests alive, answering each request.
+	// When the stream ends, for any reason, those leases are revoked.
+	KeepAlive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[KeepAliveRequest, KeepAliveResponse], error)
+	// Ends a lease at once, deleting its keys.
+	RevokeLease(ctx context.Context, in *RevokeLeaseRequest, opts ...grpc.CallOption) (*RevokeLeaseResponse, error)
+	// Stores a value under a new key the server generates from a prefix, and
+	// returns the key. The key did not exist before, so concurrent callers
+	// never overwrite each other.
+	CreateUnique(ctx context.Context, in *CreateUniqueRequest, opts ...grpc.CallOption) (*CreateUniqueResponse, error)
+	// Reserves the next numbers of a named sequence, which only ever grows,
+	// so clients can hand out IDs from the range without further round trips.
+	NextSequence(ctx context.Context, in *NextSequenceRequest, opts ...grpc.CallOption) (*NextSequenceResponse, error)
+	// Deletes every key starting with a prefix, such as every key of a
+	// namespace, and returns how many were deleted, or only counts them on a
+	// dry run. Like the admin DropPrefix, deleting takes two steps: a dry run
+	// issues a confirmation token, which the request deleting the keys must
+	// carry. Servers whose store allows it drop the keys straight from the
+	// data files; watchers are still sent a delete for each key.
+	DeletePrefix(ctx context.Context, in *DeletePrefixRequest, opts ...grpc.CallOption) (*DeletePrefixResponse, error)
+	// Copies the value of a key to another key on the server, so large values
+	// need not travel to the client and back. The destination can be given a
+	// time to live of its own with a lease.
+	Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*CopyResponse, error)
 }
 
 type clavisClient struct {
@@ -72,6 +171,316 @@ func (c *clavisClient) Delete(ctx context.Context, in *DeleteRequest, opts ...gr
 	return out, nil
 }
 
+func (c *clavisClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, Clavis_Scan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) ScanStream(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[KeyValue], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Clavis_ServiceDesc.Streams[0], Clavis_ScanStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ScanRequest, KeyValue]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_ScanStreamClient = grpc.ServerStreamingClient[KeyValue]
+
+func (c *clavisClient) BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchPutResponse)
+	err := c.cc.Invoke(ctx, Clavis_BatchPut_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, Clavis_BatchGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, Clavis_BatchDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, Clavis_Diff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Clavis_ServiceDesc.Streams[1], Clavis_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+func (c *clavisClient) FetchSpill(ctx context.Context, in *FetchSpillRequest, opts ...grpc.CallOption) (*FetchSpillResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FetchSpillResponse)
+	err := c.cc.Invoke(ctx, Clavis_FetchSpill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Range(ctx context.Context, in *RangeRequest, opts ...grpc.CallOption) (*RangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RangeResponse)
+	err := c.cc.Invoke(ctx, Clavis_Range_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Exists(ctx context.Context, in *ExistsRequest, opts ...grpc.CallOption) (*ExistsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExistsResponse)
+	err := c.cc.Invoke(ctx, Clavis_Exists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, Clavis_Count_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*IncrementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncrementResponse)
+	err := c.cc.Invoke(ctx, Clavis_Increment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UndeleteResponse)
+	err := c.cc.Invoke(ctx, Clavis_Undelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, Clavis_GetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) QueryByTag(ctx context.Context, in *QueryByTagRequest, opts ...grpc.CallOption) (*QueryByTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryByTagResponse)
+	err := c.cc.Invoke(ctx, Clavis_QueryByTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, Clavis_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Clavis_ServiceDesc.Streams[2], Clavis_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_SubscribeClient = grpc.ServerStreamingClient[WatchEvent]
+
+func (c *clavisClient) Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LockResponse)
+	err := c.cc.Invoke(ctx, Clavis_Lock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) RenewLock(ctx context.Context, in *RenewLockRequest, opts ...grpc.CallOption) (*LockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LockResponse)
+	err := c.cc.Invoke(ctx, Clavis_RenewLock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockResponse)
+	err := c.cc.Invoke(ctx, Clavis_Unlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) GrantLease(ctx context.Context, in *GrantLeaseRequest, opts ...grpc.CallOption) (*GrantLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantLeaseResponse)
+	err := c.cc.Invoke(ctx, Clavis_GrantLease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) AttachLease(ctx context.Context, in *AttachLeaseRequest, opts ...grpc.CallOption) (*AttachLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachLeaseResponse)
+	err := c.cc.Invoke(ctx, Clavis_AttachLease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) KeepAlive(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[KeepAliveRequest, KeepAliveResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Clavis_ServiceDesc.Streams[3], Clavis_KeepAlive_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[KeepAliveRequest, KeepAliveResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_KeepAliveClient = grpc.BidiStreamingClient[KeepAliveRequest, KeepAliveResponse]
+
+func (c *clavisClient) RevokeLease(ctx context.Context, in *RevokeLeaseRequest, opts ...grpc.CallOption) (*RevokeLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeLeaseResponse)
+	err := c.cc.Invoke(ctx, Clavis_RevokeLease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) CreateUnique(ctx context.Context, in *CreateUniqueRequest, opts ...grpc.CallOption) (*CreateUniqueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateUniqueResponse)
+	err := c.cc.Invoke(ctx, Clavis_CreateUnique_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) NextSequence(ctx context.Context, in *NextSequenceRequest, opts ...grpc.CallOption) (*NextSequenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NextSequenceResponse)
+	err := c.cc.Invoke(ctx, Clavis_NextSequence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) DeletePrefix(ctx context.Context, in *DeletePrefixRequest, opts ...grpc.CallOption) (*DeletePrefixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePrefixResponse)
+	err := c.cc.Invoke(ctx, Clavis_DeletePrefix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clavisClient) Copy(ctx context.Context, in *CopyRequest, opts ...grpc.CallOption) (*CopyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CopyResponse)
+	err := c.cc.Invoke(ctx, Clavis_Copy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ClavisServer is the server API for Clavis service.
 // All implementations must embed UnimplementedClavisServer
 // for forward compatibility.
@@ -79,6 +488,77 @@ type ClavisServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	Put(context.Context, *PutRequest) (*PutResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	ScanStream(*ScanRequest, grpc.ServerStreamingServer[KeyValue]) error
+	BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error)
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	Diff(context.Context, *DiffRequest) (*DiffResponse, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	// Returns a chunk of a scan result the server spilled to disk.
+	FetchSpill(context.Context, *FetchSpillRequest) (*FetchSpillResponse, error)
+	// Returns the pairs whose keys are in a range, in key order or reversed.
+	Range(context.Context, *RangeRequest) (*RangeResponse, error)
+	// Reports whether a key exists without returning its value.
+	Exists(context.Context, *ExistsRequest) (*ExistsResponse, error)
+	// Returns the number of keys starting with a prefix without reading their
+	// values.
+	Count(context.Context, *CountRequest) (*CountResponse, error)
+	// Adds to the integer counter stored at a key atomically and returns the
+	// new value.
+	Increment(context.Context, *IncrementRequest) (*IncrementResponse, error)
+	// Restores the value a key had when it was deleted, while the server keeps
+	// deleted values.
+	Undelete(context.Context, *UndeleteRequest) (*UndeleteResponse, error)
+	// Returns when a key was created and last updated, and how many times it
+	// was written, while the server tracks key metadata.
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	// Lists the keys carrying a tag, in key order, while the server indexes
+	// tags.
+	QueryByTag(context.Context, *QueryByTagRequest) (*QueryByTagResponse, error)
+	// Finds the keys whose values contain every term of a query, best matches
+	// first, while the server indexes values.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// Streams the changes to keys starting with a prefix, with their values,
+	// from a buffer of its own. Unlike Watch, a subscriber that falls too far
+	// behind is ended with RESOURCE_EXHAUSTED instead of missing events.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	// Acquires a lease on a lock, failing with FAILED_PRECONDITION while
+	// another lease on it is live. The fencing token returned is accepted by
+	// Put until a newer lease on the lock is granted.
+	Lock(context.Context, *LockRequest) (*LockResponse, error)
+	// Extends a live lease; holders renew well before it expires.
+	RenewLock(context.Context, *RenewLockRequest) (*LockResponse, error)
+	// Releases a lease so the lock can be acquired again at once.
+	Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error)
+	// Grants a lease that keys can be attached to, with Put or AttachLease.
+	// When the lease expires or is revoked, its keys are deleted.
+	GrantLease(context.Context, *GrantLeaseRequest) (*GrantLeaseResponse, error)
+	// Attaches existing keys to a live lease.
+	AttachLease(context.Context, *AttachLeaseRequest) (*AttachLeaseResponse, error)
+	// Keeps the leases named by the requests alive, answering each request.
+	// When the stream ends, for any reason, those leases are revoked.
+	KeepAlive(grpc.BidiStreamingServer[KeepAliveRequest, KeepAliveResponse]) error
+	// Ends a lease at once, deleting its keys.
+	RevokeLease(context.Context, *RevokeLeaseRequest) (*RevokeLeaseResponse, error)
+	// Stores a value under a new key the server generates from a prefix, and
+	// returns the key. The key did not exist before, so concurrent callers
+	// never overwrite each other.
+	CreateUnique(context.Context, *CreateUniqueRequest) (*CreateUniqueResponse, error)
+	// Reserves the next numbers of a named sequence, which only ever grows,
+	// so clients can hand out IDs from the range without further round trips.
+	NextSequence(context.Context, *NextSequenceRequest) (*NextSequenceResponse, error)
+	// Deletes every key starting with a prefix, such as every key of a
+	// namespace, and returns how many were deleted, or only counts them on a
+	// dry run. Like the admin DropPrefix, deleting takes two steps: a dry run
+	// issues a confirmation token, which the request deleting the keys must
+	// carry. Servers whose store allows it drop the keys straight from the
+	// data files; watchers are still sent a delete for each key.
+	DeletePrefix(context.Context, *DeletePrefixRequest) (*DeletePrefixResponse, error)
+	// Copies the value of a key to another key on the server, so large values
+	// need not travel to the client and back. The destination can be given a
+	// time to live of its own with a lease.
+	Copy(context.Context, *CopyRequest) (*CopyResponse, error)
 	mustEmbedUnimplementedClavisServer()
 }
 
@@ -98,6 +578,90 @@ func (UnimplementedClavisServer) Put(context.Context, *PutRequest) (*PutResponse
 func (UnimplementedClavisServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedClavisServer) Scan(context.Context, *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedClavisServer) ScanStream(*ScanRequest, grpc.ServerStreamingServer[KeyValue]) error {
+	return status.Errorf(codes.Unimplemented, "method ScanStream not implemented")
+}
+func (UnimplementedClavisServer) BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchPut not implemented")
+}
+func (UnimplementedClavisServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedClavisServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
+func (UnimplementedClavisServer) Diff(context.Context, *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (UnimplementedClavisServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedClavisServer) FetchSpill(context.Context, *FetchSpillRequest) (*FetchSpillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchSpill not implemented")
+}
+func (UnimplementedClavisServer) Range(context.Context, *RangeRequest) (*RangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Range not implemented")
+}
+func (UnimplementedClavisServer) Exists(context.Context, *ExistsRequest) (*ExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exists not implemented")
+}
+func (UnimplementedClavisServer) Count(context.Context, *CountRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
+}
+func (UnimplementedClavisServer) Increment(context.Context, *IncrementRequest) (*IncrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedClavisServer) Undelete(context.Context, *UndeleteRequest) (*UndeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Undelete not implemented")
+}
+func (UnimplementedClavisServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedClavisServer) QueryByTag(context.Context, *QueryByTagRequest) (*QueryByTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryByTag not implemented")
+}
+func (UnimplementedClavisServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedClavisServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedClavisServer) Lock(context.Context, *LockRequest) (*LockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (UnimplementedClavisServer) RenewLock(context.Context, *RenewLockRequest) (*LockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewLock not implemented")
+}
+func (UnimplementedClavisServer) Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
+func (UnimplementedClavisServer) GrantLease(context.Context, *GrantLeaseRequest) (*GrantLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantLease not implemented")
+}
+func (UnimplementedClavisServer) AttachLease(context.Context, *AttachLeaseRequest) (*AttachLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachLease not implemented")
+}
+func (UnimplementedClavisServer) KeepAlive(grpc.BidiStreamingServer[KeepAliveRequest, KeepAliveResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
+}
+func (UnimplementedClavisServer) RevokeLease(context.Context, *RevokeLeaseRequest) (*RevokeLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeLease not implemented")
+}
+func (UnimplementedClavisServer) CreateUnique(context.Context, *CreateUniqueRequest) (*CreateUniqueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUnique not implemented")
+}
+func (UnimplementedClavisServer) NextSequence(context.Context, *NextSequenceRequest) (*NextSequenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextSequence not implemented")
+}
+func (UnimplementedClavisServer) DeletePrefix(context.Context, *DeletePrefixRequest) (*DeletePrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePrefix not implemented")
+}
+func (UnimplementedClavisServer) Copy(context.Context, *CopyRequest) (*CopyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Copy not implemented")
+}
 func (UnimplementedClavisServer) mustEmbedUnimplementedClavisServer() {}
 func (UnimplementedClavisServer) testEmbeddedByValue()                {}
 
@@ -173,26 +737,616 @@ func _Clavis_Delete_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
-// Clavis_ServiceDesc is the grpc.ServiceDesc for Clavis service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Clavis_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "clavis.v1.Clavis",
-	HandlerType: (*ClavisServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _Clavis_Get_Handler,
-		},
-		{
-			MethodName: "Put",
-			Handler:    _Clavis_Put_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Clavis_Delete_Handler,
+func _Clavis_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Scan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_ScanStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClavisServer).ScanStream(m, &grpc.GenericServerStream[ScanRequest, KeyValue]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_ScanStreamServer = grpc.ServerStreamingServer[KeyValue]
+
+func _Clavis_BatchPut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).BatchPut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_BatchPut_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).BatchPut(ctx, req.(*BatchPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_BatchDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Diff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Diff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Diff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Diff(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClavisServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+func _Clavis_FetchSpill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchSpillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).FetchSpill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_FetchSpill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).FetchSpill(ctx, req.(*FetchSpillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Range_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Range(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Range_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Range(ctx, req.(*RangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Exists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Exists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Exists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Exists(ctx, req.(*ExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Count(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Count_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Count(ctx, req.(*CountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Increment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Increment(ctx, req.(*IncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Undelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Undelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Undelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Undelete(ctx, req.(*UndeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_QueryByTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryByTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).QueryByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_QueryByTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).QueryByTag(ctx, req.(*QueryByTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClavisServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_SubscribeServer = grpc.ServerStreamingServer[WatchEvent]
+
+func _Clavis_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Lock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Lock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_RenewLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).RenewLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_RenewLock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).RenewLock(ctx, req.(*RenewLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Unlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_GrantLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).GrantLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_GrantLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).GrantLease(ctx, req.(*GrantLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_AttachLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).AttachLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_AttachLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).AttachLease(ctx, req.(*AttachLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_KeepAlive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClavisServer).KeepAlive(&grpc.GenericServerStream[KeepAliveRequest, KeepAliveResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Clavis_KeepAliveServer = grpc.BidiStreamingServer[KeepAliveRequest, KeepAliveResponse]
+
+func _Clavis_RevokeLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).RevokeLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_RevokeLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).RevokeLease(ctx, req.(*RevokeLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_CreateUnique_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUniqueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).CreateUnique(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_CreateUnique_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).CreateUnique(ctx, req.(*CreateUniqueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_NextSequence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextSequenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).NextSequence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_NextSequence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).NextSequence(ctx, req.(*NextSequenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_DeletePrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).DeletePrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_DeletePrefix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).DeletePrefix(ctx, req.(*DeletePrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clavis_Copy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClavisServer).Copy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clavis_Copy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClavisServer).Copy(ctx, req.(*CopyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Clavis_ServiceDesc is the grpc.ServiceDesc for Clavis service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Clavis_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "clavis.v1.Clavis",
+	HandlerType: (*ClavisServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _Clavis_Get_Handler,
+		},
+		{
+			MethodName: "Put",
+			Handler:    _Clavis_Put_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Clavis_Delete_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _Clavis_Scan_Handler,
+		},
+		{
+			MethodName: "BatchPut",
+			Handler:    _Clavis_BatchPut_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _Clavis_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _Clavis_BatchDelete_Handler,
+		},
+		{
+			MethodName: "Diff",
+			Handler:    _Clavis_Diff_Handler,
+		},
+		{
+			MethodName: "FetchSpill",
+			Handler:    _Clavis_FetchSpill_Handler,
+		},
+		{
+			MethodName: "Range",
+			Handler:    _Clavis_Range_Handler,
+		},
+		{
+			MethodName: "Exists",
+			Handler:    _Clavis_Exists_Handler,
+		},
+		{
+			MethodName: "Count",
+			Handler:    _Clavis_Count_Handler,
+		},
+		{
+			MethodName: "Increment",
+			Handler:    _Clavis_Increment_Handler,
+		},
+		{
+			MethodName: "Undelete",
+			Handler:    _Clavis_Undelete_Handler,
+		},
+		{
+			MethodName: "GetMetadata",
+			Handler:    _Clavis_GetMetadata_Handler,
+		},
+		{
+			MethodName: "QueryByTag",
+			Handler:    _Clavis_QueryByTag_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Clavis_Search_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _Clavis_Lock_Handler,
+		},
+		{
+			MethodName: "RenewLock",
+			Handler:    _Clavis_RenewLock_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _Clavis_Unlock_Handler,
+		},
+		{
+			MethodName: "GrantLease",
+			Handler:    _Clavis_GrantLease_Handler,
+		},
+		{
+			MethodName: "AttachLease",
+			Handler:    _Clavis_AttachLease_Handler,
+		},
+		{
+			MethodName: "RevokeLease",
+			Handler:    _Clavis_RevokeLease_Handler,
+		},
+		{
+			MethodName: "CreateUnique",
+			Handler:    _Clavis_CreateUnique_Handler,
+		},
+		{
+			MethodName: "NextSequence",
+			Handler:    _Clavis_NextSequence_Handler,
+		},
+		{
+			MethodName: "DeletePrefix",
+			Handler:    _Clavis_DeletePrefix_Handler,
+		},
+		{
+			MethodName: "Copy",
+			Handler:    _Clavis_Copy_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ScanStream",
+			Handler:       _Clavis_ScanStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Clavis_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Clavis_Subscribe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "KeepAlive",
+			Handler:       _Clavis_KeepAlive_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/clavis.proto",
 }
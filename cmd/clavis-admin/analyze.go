@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// runAnalyzeValues prints the value duplication and compressibility report.
+func runAnalyzeValues(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("analyze-values", flag.ExitOnError)
+	sampleRate := flags.Float64("sample-rate", 0, "fraction of keys to sample; 0 selects the server default")
+	maxSamples := flags.Uint("max-samples", 0, "values sampled per prefix at most; 0 selects the server default")
+	timeout := flags.Duration("timeout", 10*time.Minute, "how long to wait for the analysis")
+	asJSON := flags.Bool("json", false, "print the raw report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	report, err := admin.AnalyzeValues(ctx, &proto.AnalyzeValuesRequest{
+		SampleRate:          *sampleRate,
+		MaxSamplesPerPrefix: uint32(min(*maxSamples, 1<<32-1)), // #nosec G115 -- bounded by the min above
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		data, err := protojson.MarshalOptions{Multiline: true}.Marshal(report)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Sampled %d of %d keys in %s\n\n", report.SampledKeys, report.ScannedKeys, time.Duration(report.DurationMs)*time.Millisecond)
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PREFIX\tVALUES\tBYTES\tDUPLICATED\tCOMPRESSED\tRECOMMENDATIONS")
+	for _, p := range report.Prefixes {
+		var recommendations []string
+		for _, r := range p.Recommendations {
+			name := strings.ToLower(strings.TrimPrefix(r.Kind.String(), "KIND_"))
+			recommendations = append(recommendations, fmt.Sprintf("%s (%s)", name, r.Reason))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", p.Prefix, p.SampledValues, p.SampledBytes,
+			percent(p.DuplicateBytes, p.SampledBytes), percent(p.CompressedBytes, p.SampledBytes), strings.Join(recommendations, "; "))
+	}
+	return w.Flush()
+}
+
+// percent formats part as a percentage of total.
+func percent(part, total uint64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", 100*float64(part)/float64(total))
+}
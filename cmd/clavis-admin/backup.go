@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/backup"
+)
+
+// restoreChunkSize is the most snapshot data sent in one message.
+const restoreChunkSize = 64 * 1024
+
+// lastBackupFile is the name of the file, kept next to the backups, that
+// records the last backup taken so the next one can build on it.
+const lastBackupFile = ".clavis-last-backup"
+
+// lastBackup is the content of lastBackupFile.
+type lastBackup struct {
+	File         string `json:"file"`
+	UntilVersion uint64 `json:"until_version"`
+}
+
+// runBackup saves a snapshot of the server's store to a file, restores a
+// chain of them into an empty server, or checks the integrity of some.
+// Restores are previewed and confirmed one file at a time.
+func runBackup(admin proto.ClavisAdminClient, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	timeout := flags.Duration("timeout", time.Hour, "how long the transfer may take")
+	sinceLast := flags.Bool("since-last", false, "only back up the changes since the last backup taken into the same directory")
+	token := flags.String("confirm", "", "confirmation token from an earlier preview of restoring a single file; restores without prompting")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 || (flags.Arg(0) == "create" && flags.NArg() != 2) {
+		return errors.New("usage: clavis-admin backup [-timeout d] [-since-last] create <file> | [-confirm token] restore <file>... | verify <file>...")
+	}
+	if *token != "" && (flags.Arg(0) != "restore" || flags.NArg() != 2) {
+		return errors.New("-confirm restores a single file")
+	}
+	paths := flags.Args()[1:]
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch flags.Arg(0) {
+	case "create":
+		return createBackup(ctx, admin, paths[0], *sinceLast, out)
+	case "restore":
+		return restoreBackups(ctx, admin, paths, *token, bufio.NewReader(in), out)
+	case "verify":
+		headers, err := verifyChain(paths)
+		if err != nil {
+			return err
+		}
+		for i, header := range headers {
+			if _, err := fmt.Fprintf(out, "%s: %s taken %s is intact\n", paths[i], describeBackup(header), header.CreatedAt.Format(time.RFC3339)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown backup command %q", flags.Arg(0))
+	}
+}
+
+// createBackup streams a snapshot into a temporary file next to path and
+// only moves it into place once its checksum is verified. With sinceLast, the
+// snapshot only holds the changes since the last backup recorded in path's
+// directory.
+func createBackup(ctx context.Context, admin proto.ClavisAdminClient, path string, sinceLast bool, out io.Writer) error {
+	statePath := filepath.Join(filepath.Dir(path), lastBackupFile)
+	req := &proto.BackupRequest{}
+	if sinceLast {
+		last, err := readLastBackup(statePath)
+		if err != nil {
+			return err
+		}
+		req.SinceVersion = last.UntilVersion
+	}
+
+	stream, err := admin.Backup(ctx, req)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".partial-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	var size int64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("backup failed: %w", err)
+		}
+		if _, err := file.Write(chunk.Data); err != nil {
+			_ = file.Close()
+			return err
+		}
+		size += int64(len(chunk.Data))
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	header, err := verifyBackup(file.Name())
+	if err != nil {
+		return fmt.Errorf("received snapshot is damaged: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	if err := writeLastBackup(statePath, lastBackup{File: filepath.Base(path), UntilVersion: header.Until}); err != nil {
+		return fmt.Errorf("backup written to %s but not recorded for -since-last: %w", path, err)
+	}
+	_, err = fmt.Fprintf(out, "Wrote %d bytes to %s (%s taken %s)\n", size, path, describeBackup(header), header.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// restoreBackups verifies that the snapshot files form a chain and restores
+// them in order, each once the operator confirmed its preview. A token
+// restores the single file it was issued for without prompting.
+func restoreBackups(ctx context.Context, admin proto.ClavisAdminClient, paths []string, token string, in *bufio.Reader, out io.Writer) error {
+	if _, err := verifyChain(paths); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := restoreBackup(ctx, admin, path, token, in, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreBackup previews restoring a snapshot file, asks the operator to
+// type its path back and then restores it. With a token from an earlier
+// preview, it restores the file straight away.
+func restoreBackup(ctx context.Context, admin proto.ClavisAdminClient, path, token string, in *bufio.Reader, out io.Writer) error {
+	if token == "" {
+		preview, err := sendSnapshot(ctx, admin, path, "")
+		if err != nil {
+			return fmt.Errorf("preview of %s failed: %w", path, err)
+		}
+		confirmed, err := promptConfirmation(in, out, preview.Confirmation, "file", path)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("aborted; %s was not restored", path)
+		}
+		token = preview.Confirmation.Token
+	}
+
+	resp, err := sendSnapshot(ctx, admin, path, token)
+	if err != nil {
+		return fmt.Errorf("restore of %s failed: %w", path, err)
+	}
+	_, err = fmt.Fprintf(out, "Restored %d bytes from %s in %s\n", resp.Bytes, path, time.Duration(resp.DurationMs)*time.Millisecond)
+	return err
+}
+
+// sendSnapshot streams a snapshot file to the server, with token in its
+// first chunk. Without a token, the server only previews the restore.
+func sendSnapshot(ctx context.Context, admin proto.ClavisAdminClient, path, token string) (*proto.RestoreResponse, error) {
+	file, err := os.Open(path) // #nosec G304 -- path is operator-provided
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	stream, err := admin.Restore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, restoreChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&proto.RestoreChunk{Data: buf[:n], ConfirmationToken: token}); err != nil {
+				// The server's reason is reported by CloseAndRecv
+				break
+			}
+			token = ""
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// verifyChain checks the framing and checksum of snapshot files and that
+// each one builds on the one before it.
+func verifyChain(paths []string) ([]backup.Header, error) {
+	headers := make([]backup.Header, len(paths))
+	for i, path := range paths {
+		header, err := verifyBackup(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		headers[i] = header
+	}
+	if err := backup.CheckChain(headers); err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
+
+// verifyBackup checks the framing and checksum of a snapshot file.
+func verifyBackup(path string) (backup.Header, error) {
+	file, err := os.Open(path) // #nosec G304 -- path is operator-provided
+	if err != nil {
+		return backup.Header{}, err
+	}
+	defer func() { _ = file.Close() }()
+	return backup.Verify(file)
+}
+
+// describeBackup names the kind of a snapshot and the versions it covers.
+func describeBackup(header backup.Header) string {
+	if header.Incremental() {
+		return fmt.Sprintf("incremental snapshot of versions %d-%d", header.Since, header.Until)
+	}
+	return fmt.Sprintf("full snapshot up to version %d", header.Until)
+}
+
+// readLastBackup reads the record of the last backup taken.
+func readLastBackup(path string) (lastBackup, error) {
+	var last lastBackup
+	data, err := os.ReadFile(path) // #nosec G304 -- path is operator-provided
+	if errors.Is(err, os.ErrNotExist) {
+		return last, fmt.Errorf("no backup recorded in %s; take a full backup first", filepath.Dir(path))
+	}
+	if err != nil {
+		return last, err
+	}
+	if err := json.Unmarshal(data, &last); err != nil {
+		return last, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return last, nil
+}
+
+// writeLastBackup records the last backup taken.
+func writeLastBackup(path string, last lastBackup) error {
+	data, err := json.Marshal(last)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
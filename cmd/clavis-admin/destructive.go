@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// errAborted is returned when the operator does not confirm an operation.
+var errAborted = errors.New("aborted; nothing was deleted")
+
+// destructiveCall performs one phase of a two-phase admin operation: it
+// previews when token is empty and executes otherwise.
+type destructiveCall func(ctx context.Context, token string) (confirmation *proto.Confirmation, keys uint64, err error)
+
+// runDropPrefix deletes every key under a prefix after confirmation.
+func runDropPrefix(admin proto.ClavisAdminClient, args []string, in io.Reader, out io.Writer) error {
+	return runDestructive("drop-prefix", "prefix", args, in, out, func(target string) destructiveCall {
+		return func(ctx context.Context, token string) (*proto.Confirmation, uint64, error) {
+			resp, err := admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: target, ConfirmationToken: token})
+			return resp.GetConfirmation(), resp.GetKeys(), err
+		}
+	})
+}
+
+// runDeleteNamespace deletes a namespace and its settings after confirmation.
+func runDeleteNamespace(admin proto.ClavisAdminClient, args []string, in io.Reader, out io.Writer) error {
+	return runDestructive("delete-namespace", "namespace", args, in, out, func(target string) destructiveCall {
+		return func(ctx context.Context, token string) (*proto.Confirmation, uint64, error) {
+			resp, err := admin.DeleteNamespace(ctx, &proto.DeleteNamespaceRequest{Namespace: target, ConfirmationToken: token})
+			return resp.GetConfirmation(), resp.GetKeys(), err
+		}
+	})
+}
+
+// runDestructive previews the operation, asks the operator to type the
+// target back and only then executes it. With -confirm, a token from an
+// earlier preview executes the operation without prompting.
+func runDestructive(name, targetName string, args []string, in io.Reader, out io.Writer, bind func(target string) destructiveCall) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	token := flags.String("confirm", "", "confirmation token from an earlier preview; executes without prompting")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: clavis-admin %s [-confirm token] <%s>", name, targetName)
+	}
+	target := flags.Arg(0)
+	call := bind(target)
+
+	if *token == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		confirmation, _, err := call(ctx, "")
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		confirmed, err := promptConfirmation(bufio.NewReader(in), out, confirmation, targetName, target)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errAborted
+		}
+		*token = confirmation.Token
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	_, keys, err := call(ctx, *token)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deleted %d keys.\n", keys)
+	return nil
+}
+
+// promptConfirmation shows what a previewed operation will do and asks the
+// operator to type the target back, reporting whether they did.
+func promptConfirmation(in *bufio.Reader, out io.Writer, confirmation *proto.Confirmation, targetName, target string) (bool, error) {
+	expires := time.Unix(0, confirmation.ExpiresAtUnixNano)
+	fmt.Fprintf(out, "This will %s.\n", confirmation.Summary)
+	fmt.Fprintf(out, "Confirmation token %s is valid until %s.\n", confirmation.Token, expires.Format(time.TimeOnly))
+	fmt.Fprintf(out, "Type the %s to confirm: ", targetName)
+
+	answer, err := in.ReadString('\n')
+	if strings.TrimSpace(answer) != target {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/fixtures"
+)
+
+// runFixtures seeds a server from a fixture file or verifies that it matches
+// one, failing when any check does not hold.
+func runFixtures(c proto.ClavisClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("fixtures", flag.ExitOnError)
+	timeout := flags.Duration("timeout", time.Minute, "how long to wait for the whole fixture")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 || (flags.Arg(0) != "apply" && flags.Arg(0) != "verify") {
+		return fmt.Errorf("usage: fixtures [-timeout d] apply|verify <file>")
+	}
+	fixture, err := fixtures.Load(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if flags.Arg(0) == "apply" {
+		n, err := fixture.Apply(ctx, c)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d keys\n", n)
+		return nil
+	}
+
+	report, err := fixture.Verify(ctx, c)
+	if err != nil {
+		return err
+	}
+	for _, failure := range report.Failures {
+		fmt.Fprintf(out, "FAIL %s\n", failure)
+	}
+	if !report.OK() {
+		return fmt.Errorf("%d of %d checks failed", len(report.Failures), report.Checked)
+	}
+	fmt.Fprintf(out, "All %d checks passed\n", report.Checked)
+	return nil
+}
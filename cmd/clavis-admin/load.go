@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/export"
+)
+
+// loadMessageSize is the most pairs sent in one message.
+const loadMessageSize = 1000
+
+// runLoad streams the pairs of a JSON lines or CSV export, read from a file or
+// from stdin with -, to the server's bulk loader, printing its progress.
+func runLoad(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("load", flag.ExitOnError)
+	timeout := flags.Duration("timeout", time.Hour, "how long the load may take")
+	formatName := flags.String("format", string(export.FormatJSONL), "input `format`: jsonl or csv, with base64-encoded values")
+	namespace := flags.String("namespace", "", "namespace to load the keys into")
+	skipValidation := flags.Bool("skip-validation", false, "do not check the pairs against the validation profiles")
+	sortPairs := flags.Bool("sort", false, "sort the pairs in memory first, for input not in key order")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: clavis-admin load [-timeout d] [-format f] [-namespace ns] [-skip-validation] [-sort] <file>|-")
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path) // #nosec G304 -- path is operator-provided
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		input = file
+	}
+	r, err := export.NewReader(input, format)
+	if err != nil {
+		return err
+	}
+	next := r.Read
+	if *sortPairs {
+		if next, err = sortedPairs(r); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	stream, err := admin.BulkLoad(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Progress reports arrive while the pairs are sent
+	finished := make(chan error, 1)
+	go func() {
+		for {
+			progress, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				finished <- errors.New("load ended without confirming the pairs were written")
+				return
+			}
+			if err != nil {
+				finished <- fmt.Errorf("load failed: %w", err)
+				return
+			}
+			elapsed := time.Duration(progress.DurationMs) * time.Millisecond
+			if !progress.Done {
+				fmt.Fprintf(out, "Loaded %d keys (%d bytes) in %s\n", progress.Keys, progress.Bytes, elapsed)
+				continue
+			}
+			how := "in batches"
+			if progress.Ingested {
+				how = "by ingestion"
+			}
+			fmt.Fprintf(out, "Loaded %d keys (%d bytes) %s in %s\n", progress.Keys, progress.Bytes, how, elapsed)
+			finished <- nil
+			return
+		}
+	}()
+
+	req := &proto.BulkLoadRequest{Namespace: *namespace, SkipValidation: *skipValidation}
+	send := func() error {
+		err := stream.Send(req)
+		req = &proto.BulkLoadRequest{}
+		return err
+	}
+	for {
+		key, value, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		req.Pairs = append(req.Pairs, &proto.LoadPair{Key: key, Value: value})
+		if len(req.Pairs) == loadMessageSize {
+			if err := send(); err != nil {
+				// The server's reason is reported by Recv
+				return <-finished
+			}
+		}
+	}
+	if err := send(); err != nil {
+		return <-finished
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return <-finished
+}
+
+// sortedPairs reads every pair of r and returns a function reading them back
+// in key order, as Reader.Read does.
+func sortedPairs(r *export.Reader) (func() (string, []byte, error), error) {
+	type pair struct {
+		key   string
+		value []byte
+	}
+	var pairs []pair
+	for {
+		key, value, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair{key, value})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+	return func() (string, []byte, error) {
+		if len(pairs) == 0 {
+			return "", nil, io.EOF
+		}
+		next := pairs[0]
+		pairs = pairs[1:]
+		return next.key, next.value, nil
+	}, nil
+}
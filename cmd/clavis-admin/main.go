@@ -0,0 +1,103 @@
+// Command clavis-admin operates a running Clavis server through its admin
+// service.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/tlsutil"
+	"github.com/William-Fernandes252/clavis/pkg/client"
+	"google.golang.org/grpc"
+)
+
+const usage = `Usage: clavis-admin [-addr host:port] [-token t] [-tls flags] <command>
+Commands:
+  metrics [-watch] [-interval d] [-json]
+  analyze-values [-sample-rate r] [-max-samples n] [-json]
+  drop-prefix [-confirm token] <prefix>
+  namespaces [-validation-profile p] [-max-keys n] [-max-bytes n] list|create <namespace>
+  delete-namespace [-confirm token] <namespace>
+  fixtures [-timeout d] apply|verify <file.json>
+  backup [-timeout d] [-since-last] create <file> | [-confirm token] restore <file>... | verify <file>...
+  load [-timeout d] [-format f] [-namespace ns] [-skip-validation] [-sort] <file>|-
+  mode [-reason r] [get|read-only|read-write]
+  rewrap [-rotate] [-timeout d]
+  reindex [-timeout d]
+  stats [-estimate] [-timeout d]
+  gc [-discard-ratio r] [-timeout d]
+  flush
+  log-level [level]`
+
+func main() {
+	addr := flag.String("addr", "localhost:50051", "address of the Clavis server")
+	var tlsFlags tlsutil.ClientFlags
+	tlsFlags.Register(flag.CommandLine)
+	token := flag.String("token", os.Getenv("CLAVIS_TOKEN"), "API token to authenticate with; defaults to $CLAVIS_TOKEN")
+	flag.Usage = func() { fmt.Fprintln(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal(usage)
+	}
+
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if *token != "" {
+		opts = append(opts, client.WithToken(*token, creds.Info().SecurityProtocol == "insecure"))
+	}
+	conn, err := grpc.NewClient(*addr, opts...)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Failed to close connection: %v", err)
+		}
+	}()
+
+	admin := proto.NewClavisAdminClient(conn)
+
+	switch flag.Arg(0) {
+	case "metrics":
+		err = runMetrics(admin, flag.Args()[1:], os.Stdout)
+	case "analyze-values":
+		err = runAnalyzeValues(admin, flag.Args()[1:], os.Stdout)
+	case "drop-prefix":
+		err = runDropPrefix(admin, flag.Args()[1:], os.Stdin, os.Stdout)
+	case "namespaces":
+		err = runNamespaces(admin, flag.Args()[1:], os.Stdout)
+	case "delete-namespace":
+		err = runDeleteNamespace(admin, flag.Args()[1:], os.Stdin, os.Stdout)
+	case "backup":
+		err = runBackup(admin, flag.Args()[1:], os.Stdin, os.Stdout)
+	case "load":
+		err = runLoad(admin, flag.Args()[1:], os.Stdout)
+	case "mode":
+		err = runMode(admin, flag.Args()[1:], os.Stdout)
+	case "rewrap":
+		err = runRewrap(admin, flag.Args()[1:], os.Stdout)
+	case "reindex":
+		err = runReindex(admin, flag.Args()[1:], os.Stdout)
+	case "stats":
+		err = runStats(admin, flag.Args()[1:], os.Stdout)
+	case "gc":
+		err = runGC(admin, flag.Args()[1:], os.Stdout)
+	case "flush":
+		err = runFlush(admin, flag.Args()[1:], os.Stdout)
+	case "log-level":
+		err = runLogLevel(admin, flag.Args()[1:], os.Stdout)
+	case "fixtures":
+		err = runFixtures(proto.NewClavisClient(conn), flag.Args()[1:], os.Stdout)
+	default:
+		err = fmt.Errorf("unknown command %q. %s", flag.Arg(0), usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
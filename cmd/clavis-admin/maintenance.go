@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// runStats shows the number of keys, the resource usage and the operation
+// counts of the store.
+func runStats(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	estimate := flags.Bool("estimate", false, "only show the estimated number of keys instead of counting them")
+	timeout := flags.Duration("timeout", 10*time.Minute, "how long counting the keys may take")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: stats [-estimate] [-timeout d]")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	resp, err := admin.Stats(ctx, &proto.StatsRequest{EstimateOnly: *estimate})
+	if err != nil {
+		return err
+	}
+	if !*estimate {
+		fmt.Fprintf(out, "keys: %d\n", resp.KeyCount)
+	}
+	fmt.Fprintf(out, "estimated keys: %d\ndisk usage: %d bytes\n", resp.EstimatedKeys, resp.DiskUsageBytes)
+	fmt.Fprintf(out, "lsm: %d bytes\nvalue log: %d bytes\nmemory: %d bytes\n", resp.LsmBytes, resp.ValueLogBytes, resp.MemoryBytes)
+	ops := resp.GetOperations()
+	fmt.Fprintf(out, "operations: %d gets, %d puts, %d deletes, %d scans\n", ops.GetGets(), ops.GetPuts(), ops.GetDeletes(), ops.GetScans())
+	return nil
+}
+
+// runGC reclaims the disk space of stale values.
+func runGC(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("gc", flag.ExitOnError)
+	discardRatio := flags.Float64("discard-ratio", 0, "share of stale data at which a data file is rewritten; the server default if zero")
+	timeout := flags.Duration("timeout", 30*time.Minute, "how long the collection may take")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: gc [-discard-ratio r] [-timeout d]")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	resp, err := admin.TriggerGC(ctx, &proto.TriggerGCRequest{DiscardRatio: *discardRatio})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "rewrote %d files, reclaiming %d bytes in %s\n", resp.RewrittenFiles, resp.DiskUsageBeforeBytes-resp.DiskUsageAfterBytes, time.Duration(resp.DurationMs)*time.Millisecond)
+	return nil
+}
+
+// runFlush writes the buffered writes of the store to disk.
+func runFlush(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: flush")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resp, err := admin.Flush(ctx, &proto.FlushRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "flushed in %s\n", time.Duration(resp.DurationMs)*time.Millisecond)
+	return nil
+}
+
+// runLogLevel shows or changes the minimum level the server logs at.
+func runLogLevel(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	if len(args) > 1 {
+		return fmt.Errorf("usage: log-level [level]")
+	}
+	req := &proto.SetLogLevelRequest{}
+	if len(args) == 1 {
+		req.Level = args[0]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := admin.SetLogLevel(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Level == resp.PreviousLevel {
+		fmt.Fprintln(out, resp.Level)
+	} else {
+		fmt.Fprintf(out, "%s (was %s)\n", resp.Level, resp.PreviousLevel)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// runMetrics prints a metrics snapshot once, or keeps refreshing a terminal
+// dashboard with -watch until interrupted.
+func runMetrics(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("metrics", flag.ExitOnError)
+	watch := flags.Bool("watch", false, "poll and redraw a dashboard until interrupted")
+	interval := flags.Duration("interval", 2*time.Second, "polling interval in watch mode")
+	asJSON := flags.Bool("json", false, "print the raw snapshot as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var previous *proto.MetricsSnapshotResponse
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		snapshot, err := admin.MetricsSnapshot(callCtx, &proto.MetricsSnapshotRequest{})
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if *asJSON {
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(snapshot)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(data))
+		} else {
+			if *watch {
+				fmt.Fprint(out, clearScreen)
+			}
+			renderDashboard(out, snapshot, previous)
+		}
+		if !*watch {
+			return nil
+		}
+		previous = snapshot
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// renderDashboard writes a snapshot as tables. When a previous snapshot is
+// given, counters also show their rate since then.
+func renderDashboard(out io.Writer, current, previous *proto.MetricsSnapshotResponse) {
+	taken := time.Unix(0, current.TakenAtUnixNano)
+	fmt.Fprintf(out, "Clavis metrics at %s\n\n", taken.Format(time.RFC3339))
+
+	var elapsed float64
+	prevCounters := make(map[string]uint64)
+	if previous != nil {
+		elapsed = taken.Sub(time.Unix(0, previous.TakenAtUnixNano)).Seconds()
+		for _, c := range previous.Counters {
+			prevCounters[c.Name] = c.Value
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNTER\tVALUE\tRATE/s")
+	for _, c := range current.Counters {
+		rate := "-"
+		if prev, ok := prevCounters[c.Name]; ok && elapsed > 0 && c.Value >= prev {
+			rate = fmt.Sprintf("%.2f", float64(c.Value-prev)/elapsed)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", c.Name, c.Value, rate)
+	}
+
+	fmt.Fprintln(w, "\t\t")
+	fmt.Fprintln(w, "GAUGE\tVALUE\t")
+	for _, g := range current.Gauges {
+		fmt.Fprintf(w, "%s\t%d\t\n", g.Name, g.Value)
+	}
+	_ = w.Flush()
+
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out)
+	fmt.Fprintln(w, "HISTOGRAM\tCOUNT\tMEAN\tP50\tP95\tP99")
+	for _, h := range current.Histograms {
+		mean := 0.0
+		if h.Count > 0 {
+			mean = h.Sum / float64(h.Count)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", h.Name, h.Count, formatValue(mean),
+			formatValue(quantile(h, 0.5)), formatValue(quantile(h, 0.95)), formatValue(quantile(h, 0.99)))
+	}
+	_ = w.Flush()
+}
+
+// quantile estimates the q-quantile of a histogram as the upper bound of the
+// bucket that contains it.
+func quantile(h *proto.HistogramValue, q float64) float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	for _, b := range h.Buckets {
+		if b.Count >= rank {
+			return b.UpperBound
+		}
+	}
+	return math.Inf(1)
+}
+
+// formatValue prints small values with enough precision for latencies.
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%.4g", v)
+}
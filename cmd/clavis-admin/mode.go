@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// runMode shows whether the server accepts client writes or switches it.
+func runMode(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("mode", flag.ExitOnError)
+	reason := flags.String("reason", "", "why the server is made read-only, as told to rejected clients")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mode *proto.ServerMode
+	switch {
+	case flags.NArg() == 0 || (flags.Arg(0) == "get" && flags.NArg() == 1):
+		resp, err := admin.GetServerMode(ctx, &proto.GetServerModeRequest{})
+		if err != nil {
+			return err
+		}
+		mode = resp.Mode
+	case (flags.Arg(0) == "read-only" || flags.Arg(0) == "read-write") && flags.NArg() == 1:
+		resp, err := admin.SetReadOnly(ctx, &proto.SetReadOnlyRequest{ReadOnly: flags.Arg(0) == "read-only", Reason: *reason})
+		if err != nil {
+			return err
+		}
+		mode = resp.Mode
+	default:
+		return fmt.Errorf("usage: mode [-reason r] [get|read-only|read-write]")
+	}
+
+	since := time.Unix(mode.SinceUnix, 0).Format(time.RFC3339)
+	switch {
+	case !mode.ReadOnly:
+		fmt.Fprintf(out, "read-write since %s\n", since)
+	case mode.Reason != "":
+		fmt.Fprintf(out, "read-only since %s: %s\n", since, mode.Reason)
+	default:
+		fmt.Fprintf(out, "read-only since %s\n", since)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// runNamespaces lists namespaces or creates one.
+func runNamespaces(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("namespaces", flag.ExitOnError)
+	profile := flags.String("validation-profile", "", "validation profile of a created namespace")
+	maxKeys := flags.Int64("max-keys", 0, "key quota of a created namespace; unlimited if zero")
+	maxBytes := flags.Int64("max-bytes", 0, "byte quota of a created namespace; unlimited if zero")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch {
+	case flags.Arg(0) == "list" && flags.NArg() == 1:
+		resp, err := admin.ListNamespaces(ctx, &proto.ListNamespacesRequest{})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAMESPACE\tPROFILE\tMAX KEYS\tMAX BYTES\tREAD ONLY\tVERSION")
+		for _, ns := range resp.Namespaces {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%t\t%d\n", ns.Namespace, ns.ValidationProfile, ns.MaxKeys, ns.MaxBytes, ns.ReadOnly, ns.Version)
+		}
+		return w.Flush()
+
+	case flags.Arg(0) == "create" && flags.NArg() == 2:
+		resp, err := admin.CreateNamespace(ctx, &proto.CreateNamespaceRequest{Settings: &proto.NamespaceSettings{
+			Namespace:         flags.Arg(1),
+			ValidationProfile: *profile,
+			MaxKeys:           *maxKeys,
+			MaxBytes:          *maxBytes,
+		}})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created namespace %s\n", resp.Settings.Namespace)
+		return nil
+
+	default:
+		return fmt.Errorf("usage: namespaces [flags] list|create <namespace>")
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// runReindex rebuilds the full-text index from the stored values.
+func runReindex(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 30*time.Minute, "how long indexing every value may take")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: reindex [-timeout d]")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	resp, err := admin.Reindex(ctx, &proto.ReindexRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "indexed %d values, skipped %d without words, in %s\n", resp.IndexedValues, resp.SkippedValues, time.Duration(resp.DurationMs)*time.Millisecond)
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// runRewrap re-encrypts the values not encrypted with the active
+// data-encryption key, optionally rotating to a new one first.
+func runRewrap(admin proto.ClavisAdminClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("rewrap", flag.ExitOnError)
+	rotate := flags.Bool("rotate", false, "generate a new data-encryption key to rewrap the values with")
+	timeout := flags.Duration("timeout", 30*time.Minute, "how long rewrapping every value may take")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: rewrap [-rotate] [-timeout d]")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	resp, err := admin.Rewrap(ctx, &proto.RewrapRequest{Rotate: *rotate})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "rewrapped %d of %d values with key %d in %s\n", resp.RewrappedValues, resp.ScannedValues, resp.KeyId, time.Duration(resp.DurationMs)*time.Millisecond)
+	return nil
+}
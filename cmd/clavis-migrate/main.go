@@ -0,0 +1,100 @@
+// Command clavis-migrate copies every key of one store into another, such as
+// into a store of another backend, and verifies the copy. It opens the stores
+// itself, so no server may be running on either of them.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/migrate"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const usage = `Usage: clavis-migrate [-batch n] [-concurrency n] [-progress d] [-verify] [-verify-only] <from> <to>
+Stores are given as <backend>:<argument>:
+  badger:<path>  BadgerDB directory, which no server may have open
+  memory:        empty in-memory store, discarded on exit; as <to>, checks
+                 that <from> can be read in full
+The destination must hold no key. Only the latest value of each key is
+copied, without version history or expiry times.`
+
+func main() {
+	batch := flag.Int("batch", migrate.DefaultBatchSize, "pairs written to the destination at once")
+	concurrency := flag.Int("concurrency", migrate.DefaultConcurrency, "batches written at once, for destinations that cannot ingest pairs in a single load")
+	interval := flag.Duration("progress", migrate.DefaultProgressInterval, "time between progress reports")
+	verify := flag.Bool("verify", false, "compare checksums of both stores once copied")
+	verifyOnly := flag.Bool("verify-only", false, "only compare both stores, without copying")
+	flag.Usage = func() { fmt.Fprintln(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	if flag.NArg() != 2 {
+		log.Fatal(usage)
+	}
+
+	if err := run(flag.Arg(0), flag.Arg(1), *verify, *verifyOnly, migrate.Config{
+		BatchSize:        *batch,
+		Concurrency:      *concurrency,
+		ProgressInterval: *interval,
+		Progress: func(p migrate.Progress) {
+			log.Printf("Copied %d keys (%d bytes) in %s", p.Keys, p.Bytes, p.Elapsed.Round(time.Millisecond))
+		},
+	}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run copies the store at from into the one at to, then verifies the copy if
+// asked to, returning once both stores are closed.
+func run(from, to string, verify, verifyOnly bool, config migrate.Config) (err error) {
+	src, err := migrate.Open(from)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", from, err)
+	}
+	defer closeStore(from, src, &err)
+	dst, err := migrate.Open(to)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", to, err)
+	}
+	defer closeStore(to, dst, &err)
+
+	// Interrupting stops the copy, leaving the stores to be closed cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if !verifyOnly {
+		progress, err := migrate.Copy(ctx, dst, src, config)
+		if err != nil {
+			return fmt.Errorf("copy failed after %d keys: %w", progress.Keys, err)
+		}
+		if !verify {
+			return nil
+		}
+	}
+
+	start := time.Now()
+	report, err := migrate.Verify(ctx, dst, src)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+	log.Printf("Verified %d keys in %s: checksums %016x (source) and %016x (destination)",
+		report.Keys, time.Since(start).Round(time.Millisecond), report.SourceChecksum, report.DestinationChecksum)
+	if !report.OK() {
+		return fmt.Errorf("stores differ: %d keys missing, %d extra, %d different; for example %s",
+			report.Missing, report.Extra, report.Different, strings.Join(report.Examples, ", "))
+	}
+	return nil
+}
+
+// closeStore closes s, opened from spec, reporting a failure in err unless
+// it already holds one.
+func closeStore(spec string, s store.Store, err *error) {
+	if closeErr := s.Close(); closeErr != nil && *err == nil {
+		*err = fmt.Errorf("failed to close %s: %w", spec, closeErr)
+	}
+}
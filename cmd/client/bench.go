@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/bench"
+)
+
+// runBench runs a workload of random Gets and Puts against the server and
+// prints its throughput and latency percentiles. An interrupt ends it early,
+// still printing the results so far.
+func runBench(client proto.ClavisClient, namespace string, args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	duration := flags.Duration("duration", bench.DefaultDuration, "how long the workload runs")
+	requests := flags.Int("requests", 0, "stop after this many requests, if sooner than -duration; 0 runs for the whole duration")
+	concurrency := flags.Int("concurrency", bench.DefaultConcurrency, "requests in flight at once")
+	reads := flags.Float64("reads", 0.5, "share of requests that are gets rather than puts, from 0 to 1")
+	keys := flags.Int("keys", bench.DefaultKeys, "distinct keys the requests pick from at random")
+	keySize := flags.Int("key-size", bench.DefaultKeySize, "length of keys in bytes")
+	valueSize := flags.Int("value-size", bench.DefaultValueSize, "length of values in bytes")
+	prefix := flags.String("prefix", bench.DefaultKeyPrefix, "prefix of every key the workload writes")
+	preload := flags.Bool("preload", true, "write every key once before the workload starts, so gets find values")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	config := bench.Config{
+		Concurrency: *concurrency,
+		Duration:    *duration,
+		Requests:    *requests,
+		ReadRatio:   *reads,
+		Keys:        *keys,
+		KeySize:     *keySize,
+		ValueSize:   *valueSize,
+		KeyPrefix:   *prefix,
+		Namespace:   namespace,
+		Preload:     *preload,
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("Running %v of %.0f%% gets over %d keys with %d requests in flight", *duration, *reads*100, *keys, *concurrency)
+	result, err := bench.Run(ctx, client, config)
+	if err != nil {
+		return err
+	}
+	return result.Report(os.Stdout)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/export"
+	clavis "github.com/William-Fernandes252/clavis/pkg/client"
+)
+
+// runExport writes every pair under a prefix to stdout as JSON lines or CSV,
+// optionally as of a point in time.
+func runExport(client proto.ClavisClient, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	at := flags.String("at", "", "export the data as it was at this RFC 3339 `time`, consistently even while writes continue; requires version history on the server")
+	formatName := flags.String("format", string(export.FormatJSONL), "output `format`: jsonl or csv, with base64-encoded values")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("export takes at most one prefix")
+	}
+	prefix := flags.Arg(0)
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	w, err := export.NewWriter(os.Stdout, format)
+	if err != nil {
+		return err
+	}
+	count := 0
+	visit := func(item *proto.KeyValue) error {
+		count++
+		return w.Write(item.Key, item.Value)
+	}
+	if *at == "" {
+		if err := clavis.ScanAll(context.Background(), client, prefix, visit); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		log.Printf("%d keys exported", count)
+		return nil
+	}
+
+	when, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		return fmt.Errorf("invalid -at time: %w", err)
+	}
+	version, err := clavis.ExportAt(context.Background(), client, prefix, when, visit)
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	log.Printf("%d keys exported as of version %d", count, version)
+	return nil
+}
+
+// runImport writes the pairs of a JSON lines or CSV export, read from a file
+// or from stdin with -, to the server.
+func runImport(client proto.ClavisClient, namespace string, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", string(export.FormatJSONL), "input `format`: jsonl or csv, with base64-encoded values")
+	batchSize := flags.Int("batch", export.DefaultBatchSize, "how many pairs to write per request")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("import takes one file, or - for stdin")
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path) // #nosec G304 -- the path is chosen by the user
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		input = file
+	}
+	r, err := export.NewReader(input, format)
+	if err != nil {
+		return err
+	}
+	count, err := export.Import(context.Background(), client, r, namespace, *batchSize)
+	log.Printf("%d keys imported", count)
+	return err
+}
@@ -1,64 +1,246 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/William-Fernandes252/clavis/api/proto"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/William-Fernandes252/clavis/internal/tlsutil"
+	clavis "github.com/William-Fernandes252/clavis/pkg/client"
 )
 
+const usage = `Usage: client [-addr host:port] [-namespace ns] [-timeout d] [-retries n] [-output text|json|raw] [-token t] [-tls] [-tls-ca file] [-tls-cert file -tls-key file] [-tls-server-name name] command [args]
+
+Commands:
+  put key value|-|@file   store a value, read from stdin with - or from a file with @file
+  get key                 print a value
+  delete key              remove a key
+  scan [prefix] [limit]   list the pairs under a prefix
+  copy [-overwrite] [-ttl d] source destination
+                          copy a value on the server, giving the copy a lease of its own with -ttl
+  delete-prefix [-dry-run] [-confirm token] prefix
+                          remove every key under a prefix once it is typed back or confirmed with the token of a -dry-run
+  export [-at time] [-format jsonl|csv] [prefix]
+  import [-format jsonl|csv] [-batch n] file|-
+  watch [-exact] [-template t] [-exec cmd] [prefix]
+  bench [-duration d] [-requests n] [-concurrency n] [-reads ratio] [-keys n] [-key-size n] [-value-size n] [-prefix p] [-preload=false]`
+
 func main() {
-	conn, err := grpc.NewClient("localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	var tlsFlags tlsutil.ClientFlags
+	tlsFlags.Register(flag.CommandLine)
+	addr := flag.String("addr", envOr("CLAVIS_ADDR", clavis.DefaultAddr), "address of the Clavis server; defaults to $CLAVIS_ADDR")
+	namespace := flag.String("namespace", os.Getenv("CLAVIS_NAMESPACE"), "namespace of put, get, delete, scan and import keys; defaults to $CLAVIS_NAMESPACE")
+	timeout := flag.Duration("timeout", 5*time.Second, "deadline of put, get, delete and scan")
+	retries := flag.Int("retries", clavis.DefaultRetryConfig.MaxAttempts-1, "times get, scan and delete are retried when the server is unavailable or too slow; 0 disables retries")
+	output := flag.String("output", string(outputText), "output format: text logs, json objects or raw values")
+	token := flag.String("token", os.Getenv("CLAVIS_TOKEN"), "API token to authenticate with; defaults to $CLAVIS_TOKEN")
+	flag.Usage = func() { fmt.Fprintln(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+	out, err := newPrinter(outputFormat(*output), os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	client, err := clavis.New(*addr, clavis.Config{
+		Credentials:        creds,
+		Token:              *token,
+		AllowInsecureToken: creds.Info().SecurityProtocol == "insecure",
+		Namespace:          *namespace,
+		Timeout:            *timeout,
+		Retry:              &clavis.RetryConfig{MaxAttempts: max(*retries, 0) + 1, PerAttemptTimeout: *timeout / 2},
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
 	defer func() {
-		if err := conn.Close(); err != nil {
+		if err := client.Close(); err != nil {
 			log.Printf("Failed to close connection: %v", err)
 		}
 	}()
 
-	client := proto.NewClavisClient(conn)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := context.Background()
 
-	switch os.Args[1] {
+	switch args[0] {
 	case "put":
-		_, err := client.Put(ctx, &proto.PutRequest{
-			Key:   os.Args[2],
-			Value: []byte(os.Args[3]),
-		})
+		requireArgs(args, 3)
+		value, err := readValue(args[2], os.Stdin)
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
 		}
-		log.Println("Put successful")
+		warnings, err := client.Put(ctx, args[1], value)
+		if err != nil {
+			fatal(err)
+		}
+		out.written(args[1], warnings, "Put successful")
 
 	case "get":
-		resp, err := client.Get(ctx, &proto.GetRequest{Key: os.Args[2]})
+		requireArgs(args, 2)
+		value, found, err := client.Get(ctx, args[1])
 		if err != nil {
-			log.Fatal(err)
-		}
-		if resp.Found {
-			log.Printf("Value: %s", string(resp.Value))
-		} else {
-			log.Println("Key not found")
+			fatal(err)
 		}
+		out.get(args[1], value, found)
 
 	case "delete":
-		_, err := client.Delete(ctx, &proto.DeleteRequest{Key: os.Args[2]})
+		requireArgs(args, 2)
+		warnings, err := client.Delete(ctx, args[1])
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+		out.written(args[1], warnings, "Delete successful")
+
+	case "copy":
+		flags := flag.NewFlagSet("copy", flag.ExitOnError)
+		overwrite := flags.Bool("overwrite", false, "replace the value of an existing destination")
+		ttl := flags.Duration("ttl", 0, "delete the copy after this long, through a lease granted for it")
+		_ = flags.Parse(args[1:])
+		if flags.NArg() != 2 {
+			log.Fatal(usage)
+		}
+		lease, err := client.Copy(ctx, flags.Arg(0), flags.Arg(1), clavis.CopyOptions{Overwrite: *overwrite, TTL: *ttl})
+		if err != nil {
+			fatal(err)
+		}
+		message := "Copy successful"
+		if lease != 0 {
+			message = fmt.Sprintf("Copy successful, attached to lease %d", lease)
+		}
+		out.written(flags.Arg(1), nil, message)
+
+	case "delete-prefix":
+		flags := flag.NewFlagSet("delete-prefix", flag.ExitOnError)
+		dryRun := flags.Bool("dry-run", false, "count the keys under the prefix and print a confirmation token without deleting them")
+		confirmation := flags.String("confirm", "", "confirmation token from an earlier dry run; deletes without prompting")
+		_ = flags.Parse(args[1:])
+		if flags.NArg() != 1 {
+			log.Fatal(usage)
+		}
+		prefix := flags.Arg(0)
+		if *confirmation == "" {
+			preview, err := client.PreviewDeletePrefix(ctx, prefix)
+			if err != nil {
+				fatal(err)
+			}
+			if *dryRun {
+				out.prefixPreviewed(prefix, preview)
+				return
+			}
+			// Like clavis-admin drop-prefix, the prefix must be typed back
+			fmt.Fprintf(os.Stderr, "This will delete %d keys under prefix %q.\nType the prefix to confirm: ", preview.Count, prefix)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(answer) != prefix {
+				log.Fatal("Aborted; nothing was deleted")
+			}
+			*confirmation = preview.ConfirmationToken
+		}
+		count, err := client.DeletePrefix(ctx, prefix, *confirmation)
+		if err != nil {
+			fatal(err)
+		}
+		out.prefixDeleted(prefix, count)
+
+	case "scan":
+		var prefix string
+		if len(args) > 1 {
+			prefix = args[1]
+		}
+		limit := 0
+		if len(args) > 2 {
+			limit, err = strconv.Atoi(args[2])
+			if err != nil || limit < 0 {
+				log.Fatalf("Invalid limit %q", args[2])
+			}
+		}
+
+		count := 0
+		err := client.ScanFunc(ctx, prefix, limit, func(key string, value []byte) error {
+			count++
+			out.item(key, value)
+			return nil
+		})
+		if err != nil {
+			fatal(err)
+		}
+		out.scanned(count)
+
+	case "export":
+		// Exports write one JSON object per pair to stdout and may outlast the
+		// timeout of the other commands
+		if err := runExport(client.Clavis(), args[1:]); err != nil {
+			fatal(err)
+		}
+
+	case "import":
+		// Imports may outlast the timeout of the other commands too
+		if err := runImport(client.Clavis(), *namespace, args[1:]); err != nil {
+			fatal(err)
+		}
+
+	case "watch":
+		if err := runWatch(client.Clavis(), args[1:]); err != nil {
+			fatal(err)
+		}
+
+	case "bench":
+		// Benchmarks run for their own duration; with -retries 0, every
+		// request measured is a single attempt
+		if err := runBench(client.Clavis(), *namespace, args[1:]); err != nil {
+			fatal(err)
 		}
-		log.Println("Delete successful")
 
 	default:
-		log.Fatal("Unknown command. Usage: client [put|get|delete] [key] [value]?")
+		log.Fatal("Unknown command. " + usage)
+	}
+}
+
+// requireArgs exits with the usage unless the command has n arguments,
+// counting its name.
+func requireArgs(args []string, n int) {
+	if len(args) != n {
+		log.Fatalf("%s takes %d argument(s). %s", args[0], n-1, usage)
+	}
+}
+
+// envOr returns the environment variable name, or fallback if it is unset.
+func envOr(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
+
+// logWarnings prints the warnings the server reported for a write.
+func logWarnings(warnings []*proto.Warning) {
+	for _, w := range warnings {
+		log.Printf("Warning (%s): %s", w.Code, w.Message)
+	}
+}
+
+// fatal exits with err, listing the fields the server rejected one per line
+// when the request failed validation.
+func fatal(err error) {
+	violations := clavis.FieldViolations(err)
+	if len(violations) == 0 {
+		log.Fatal(err)
+	}
+	log.Println("Invalid request:")
+	for _, v := range violations {
+		log.Printf("  %s: %s (%s)", v.Field, v.Message, v.Code)
 	}
+	os.Exit(1)
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// outputFormat selects how command results are printed.
+type outputFormat string
+
+const (
+	outputText outputFormat = "text" // Log lines on stderr, as for people
+	outputJSON outputFormat = "json" // One JSON object per result on stdout, as for scripts
+	outputRaw  outputFormat = "raw"  // Values byte for byte on stdout, for binary payloads
+)
+
+// printer prints command results in an output format.
+type printer struct {
+	format  outputFormat
+	w       io.Writer
+	encoder *json.Encoder
+}
+
+func newPrinter(format outputFormat, w io.Writer) (*printer, error) {
+	switch format {
+	case outputText, outputJSON, outputRaw:
+		return &printer{format: format, w: w, encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q; expected text, json or raw", format)
+	}
+}
+
+// writeResult is the JSON output of put and delete.
+type writeResult struct {
+	Key      string           `json:"key"`
+	Warnings []*proto.Warning `json:"warnings,omitempty"`
+}
+
+// written prints the outcome of put and delete.
+func (p *printer) written(key string, warnings []*proto.Warning, message string) {
+	switch p.format {
+	case outputJSON:
+		p.encode(writeResult{Key: key, Warnings: warnings})
+	default:
+		logWarnings(warnings)
+		if p.format == outputText {
+			log.Println(message)
+		}
+	}
+}
+
+// getResult is the JSON output of get. Values are base64-encoded.
+type getResult struct {
+	Key   string `json:"key"`
+	Found bool   `json:"found"`
+	Value []byte `json:"value,omitempty"`
+}
+
+func (p *printer) get(key string, value []byte, found bool) {
+	switch p.format {
+	case outputJSON:
+		p.encode(getResult{Key: key, Found: found, Value: value})
+	case outputRaw:
+		if !found {
+			log.Fatal("Key not found")
+		}
+		p.write(value)
+	default:
+		if found {
+			log.Printf("Value: %s", string(value))
+		} else {
+			log.Println("Key not found")
+		}
+	}
+}
+
+// item prints one scanned pair; raw output separates the key and value with
+// a tab and ends each pair with a newline.
+func (p *printer) item(key string, value []byte) {
+	switch p.format {
+	case outputJSON:
+		p.encode(&proto.KeyValue{Key: key, Value: value})
+	case outputRaw:
+		p.write([]byte(key + "\t"))
+		p.write(value)
+		p.write([]byte("\n"))
+	default:
+		log.Printf("%s: %s", key, string(value))
+	}
+}
+
+func (p *printer) scanned(count int) {
+	if p.format == outputText {
+		log.Printf("%d keys found", count)
+	}
+}
+
+// prefixResult is the JSON output of delete-prefix.
+type prefixResult struct {
+	Prefix            string     `json:"prefix"`
+	Count             int64      `json:"count"`
+	DryRun            bool       `json:"dry_run,omitempty"`
+	ConfirmationToken string     `json:"confirmation_token,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+}
+
+func (p *printer) prefixDeleted(prefix string, count int64) {
+	switch p.format {
+	case outputJSON:
+		p.encode(prefixResult{Prefix: prefix, Count: count})
+	case outputRaw:
+		p.write([]byte(strconv.FormatInt(count, 10) + "\n"))
+	default:
+		log.Printf("%d keys deleted", count)
+	}
+}
+
+// prefixPreviewed prints the count of a delete-prefix dry run with the token
+// confirming it; raw output separates them with a tab.
+func (p *printer) prefixPreviewed(prefix string, preview *proto.DeletePrefixResponse) {
+	expires := time.Unix(0, preview.ConfirmationExpiresAtUnixNano)
+	switch p.format {
+	case outputJSON:
+		p.encode(prefixResult{Prefix: prefix, Count: preview.Count, DryRun: true, ConfirmationToken: preview.ConfirmationToken, ExpiresAt: &expires})
+	case outputRaw:
+		p.write([]byte(strconv.FormatInt(preview.Count, 10) + "\t" + preview.ConfirmationToken + "\n"))
+	default:
+		log.Printf("%d keys would be deleted; delete them with -confirm %s until %s", preview.Count, preview.ConfirmationToken, expires.Format(time.TimeOnly))
+	}
+}
+
+func (p *printer) encode(v any) {
+	if err := p.encoder.Encode(v); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+}
+
+func (p *printer) write(data []byte) {
+	if _, err := p.w.Write(data); err != nil {
+		log.Fatalf("Failed to write output: %v", err)
+	}
+}
+
+// readValue resolves the value argument of put: "-" reads stdin and "@path"
+// reads a file, so binary payloads need not pass through the shell. Any other
+// argument is the value itself.
+func readValue(arg string, stdin io.Reader) ([]byte, error) {
+	switch {
+	case arg == "-":
+		value, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the value from stdin: %w", err)
+		}
+		return value, nil
+	case strings.HasPrefix(arg, "@"):
+		value, err := os.ReadFile(arg[1:]) // #nosec G304 -- the path is chosen by the user running the CLI
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the value: %w", err)
+		}
+		return value, nil
+	default:
+		return []byte(arg), nil
+	}
+}
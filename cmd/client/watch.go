@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+const defaultWatchTemplate = "{{.Time.Format \"15:04:05.000\"}} {{.Type}} {{.Key}}"
+
+// watchEvent is the data available to watch templates and hooks.
+type watchEvent struct {
+	Type  string // PUT, DELETE or EXPIRE
+	Key   string
+	Value string
+	Time  time.Time
+}
+
+// runWatch streams changes under a prefix, or to a single key, printing each
+// one through a template and optionally running a command per event. It runs
+// until interrupted or the stream ends.
+func runWatch(client proto.ClavisClient, args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	tmplText := flags.String("template", defaultWatchTemplate, "Go template rendered for each event; fields: .Type .Key .Value .Time")
+	command := flags.String("exec", "", "command run for each event, with the rendered template on stdin and CLAVIS_EVENT_* variables set")
+	exact := flags.Bool("exact", false, "watch only the key named by the argument instead of every key under it")
+	prefix, err := parseWatchArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("watch").Parse(*tmplText)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	req := &proto.WatchRequest{Prefix: prefix}
+	if *exact {
+		req = &proto.WatchRequest{Key: prefix}
+	}
+	stream, err := client.Watch(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		event := watchEvent{
+			Type:  strings.TrimPrefix(msg.Type.String(), "TYPE_"),
+			Key:   msg.Key,
+			Value: string(msg.Value),
+			Time:  time.Unix(0, msg.TimeUnixNano),
+		}
+		rendered, err := renderWatchEvent(tmpl, event)
+		if err != nil {
+			return err
+		}
+		fmt.Println(rendered)
+
+		if *command != "" {
+			if err := runWatchHook(ctx, *command, event, rendered); err != nil {
+				log.Printf("Hook failed for %s %s: %v", event.Type, event.Key, err)
+			}
+		}
+	}
+}
+
+// parseWatchArgs accepts the prefix before or after the flags.
+func parseWatchArgs(flags *flag.FlagSet, args []string) (string, error) {
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	var prefix string
+	if flags.NArg() > 0 {
+		prefix = flags.Arg(0)
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return "", err
+		}
+	}
+	if flags.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	return prefix, nil
+}
+
+// renderWatchEvent executes the template for one event.
+func renderWatchEvent(tmpl *template.Template, event watchEvent) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("failed to render event: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// runWatchHook runs command for one event, waiting for it to finish so
+// events are handled in order.
+func runWatchHook(ctx context.Context, command string, event watchEvent, rendered string) error {
+	cmd := exec.CommandContext(ctx, command) // #nosec G204 -- the command is chosen by the user running the CLI
+	cmd.Stdin = strings.NewReader(rendered + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"CLAVIS_EVENT_TYPE="+event.Type,
+		"CLAVIS_EVENT_KEY="+event.Key,
+		"CLAVIS_EVENT_TIME="+event.Time.Format(time.RFC3339Nano),
+	)
+	return cmd.Run()
+}
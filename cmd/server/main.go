@@ -1,41 +1,702 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/index"
+	"github.com/William-Fernandes252/clavis/internal/keystats"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/migrate"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/pubsub"
+	"github.com/William-Fernandes252/clavis/internal/replication"
 	proto "github.com/William-Fernandes252/clavis/internal/server/grpc"
+	httpgateway "github.com/William-Fernandes252/clavis/internal/server/http"
+	"github.com/William-Fernandes252/clavis/internal/server/resp"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
 	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/cache"
+	"github.com/William-Fernandes252/clavis/internal/store/checksum"
+	"github.com/William-Fernandes252/clavis/internal/store/compression"
+	"github.com/William-Fernandes252/clavis/internal/store/crypto"
+	"github.com/William-Fernandes252/clavis/internal/store/keymeta"
+	"github.com/William-Fernandes252/clavis/internal/store/quota"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+	"github.com/William-Fernandes252/clavis/internal/store/remote"
+	"github.com/William-Fernandes252/clavis/internal/store/shadow"
+	"github.com/William-Fernandes252/clavis/internal/store/slowlog"
+	"github.com/William-Fernandes252/clavis/internal/store/softdelete"
+	"github.com/William-Fernandes252/clavis/internal/store/tags"
+	"github.com/William-Fernandes252/clavis/internal/store/validated"
+	"github.com/William-Fernandes252/clavis/internal/tlsutil"
+	"github.com/William-Fernandes252/clavis/internal/tracing"
+	"github.com/William-Fernandes252/clavis/pkg/client"
+	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
 )
 
 const (
-	port     = ":50051"
-	dataPath = "./data"
+	port             = ":50051"
+	dataPath         = "./data"
+	replicationState = "./replication.json"
+	shadowTimeout    = 5 * time.Second // Bounds each write mirrored to a remote shadow, so a stuck one does not hold up the rest
 )
 
 func main() {
+	selfTest := flag.Bool("self-test", false, "run a self-test against the live configuration and exit")
+	auditLog := flag.Bool("audit-log", false, "log every store mutation and lifecycle event")
+	gcInterval := flag.Duration("gc-interval", badger.DefaultGCInterval, "time between garbage collection passes reclaiming the disk space of stale values; 0 disables them")
+	gcDiscardRatio := flag.Float64("gc-discard-ratio", badger.DefaultDiscardRatio, "share of stale data at which garbage collection rewrites a data file")
+	keepVersions := flag.Int("keep-versions", 1, "number of versions Badger keeps per key; above 1 enables per-namespace retention")
+	groupCommitWindow := flag.Duration("group-commit-window", 0, "time a write waits for concurrent writes to commit with it, syncing the disk once per group; 0 commits every write alone")
+	groupCommitMaxWrites := flag.Int("group-commit-max-writes", badger.DefaultGroupCommitMaxWrites, "writes that commit a group before its window ends")
+	sequenceBandwidth := flag.Uint64("sequence-bandwidth", badger.DefaultSequenceBandwidth, "numbers each named sequence leases from disk at once; the unused ones are skipped after a crash")
+	drainTimeout := flag.Duration("drain-timeout", proto.DefaultDrainTimeout, "how long shutdown waits for in-flight requests")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate to serve over TLS")
+	tlsKey := flag.String("tls-key", "", "PEM private key of the TLS certificate")
+	tokenFile := flag.String("token-file", "", "file of \"principal token\" lines; when set, every call must present one of the tokens")
+	policyFile := flag.String("policy-file", "", "JSON authorization policy mapping principals to key prefixes and operations; requires -token-file")
+	validationFile := flag.String("validation-file", "", "JSON file defining validation profiles and binding them, or the built-in strict, standard and permissive ones, to principals and namespaces")
+	tlsClientCA := flag.String("tls-client-ca", "", "PEM CA certificates; when set, clients must present a certificate they signed (mutual TLS)")
+	var filters, expirations []store.CompactionFilter
+	flag.Func("expire", "expire keys under a prefix during maintenance once older than a duration, as `prefix=duration`; repeatable", func(value string) error {
+		prefix, age, found := strings.Cut(value, "=")
+		if !found {
+			return errors.New("expected prefix=duration")
+		}
+		maxAge, err := time.ParseDuration(age)
+		if err != nil {
+			return err
+		}
+		expirations = append(expirations, store.ExpireAfter(prefix, maxAge))
+		return nil
+	})
+	flag.Func("deny-keys", "drop keys matching a regular `pattern` during maintenance; repeatable", func(value string) error {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, store.DenyKeys(pattern))
+		return nil
+	})
+	maxInFlight := flag.Int("max-in-flight", 0, "calls served at once; further calls wait in a queue or fail with UNAVAILABLE, and 0 leaves them unbounded")
+	maxQueued := flag.Int("max-queued", 0, "calls waiting for one of the -max-in-flight slots at once; defaults to -max-in-flight")
+	queueTimeout := flag.Duration("queue-timeout", 0, "how long a call waits for a slot before failing; 0 waits as long as its deadline allows")
+	pubsubBuffer := flag.Int("pubsub-buffer", pubsub.DefaultBufferSize, "changes queued for each Subscribe stream; subscribers falling further behind are ended with RESOURCE_EXHAUSTED")
+	maxSubscribers := flag.Int("max-subscribers", 0, "Subscribe streams open at once; 0 leaves them unbounded")
+	maxConcurrentStreams := flag.Uint("max-concurrent-streams", 0, "calls a client may have open at once on one connection; 0 keeps the gRPC default")
+	rateLimit := flag.Float64("rate-limit", 0, "requests per second each caller may send, averaged over bursts of -rate-limit-burst; 0 disables rate limiting")
+	rateLimitBurst := flag.Float64("rate-limit-burst", 0, "requests a caller may send at once; defaults to one second worth of -rate-limit")
+	rateLimitBy := flag.String("rate-limit-by", string(proto.RateLimitByPrincipal), "what callers are told apart by: principal, falling back on the IP address without -token-file, or ip")
+	methodRateLimits := map[string]proto.RateLimit{}
+	flag.Func("method-rate-limit", "limit each caller of an RPC, such as Put or /clavis.v1.Clavis/Put, to its own requests per second, as `method=rate`; repeatable", func(value string) error {
+		method, rate, found := strings.Cut(value, "=")
+		if !found {
+			return errors.New("expected method=rate")
+		}
+		perSecond, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return err
+		}
+		methodRateLimits[proto.MethodName(method)] = proto.RateLimit{RequestsPerSecond: perSecond}
+		return nil
+	})
+	filterInterval := flag.Duration("filter-interval", store.DefaultFilterInterval, "time between -expire and -deny-keys maintenance passes")
+	logLevel := flag.String("log-level", "info", "minimum level logged: debug, info, warn or error")
+	logFormat := flag.String("log-format", string(logging.FormatText), "log record format: text or json")
+	shedErrorRate := flag.Float64("shed-error-rate", 0, "share of calls failing with store errors, from 0 to 1, at which scans and large puts are rejected with UNAVAILABLE; 0 disables it")
+	shedMaxDiskBytes := flag.Int64("shed-max-disk-bytes", 0, "store disk usage at which scans and large puts are rejected with UNAVAILABLE; 0 disables it")
+	shedMaxPutBytes := flag.Int("shed-max-put-bytes", proto.DefaultLoadShedMaxPutBytes, "size of the values a put may write while load is shed")
+	healthInterval := flag.Duration("health-interval", proto.DefaultHealthCheckInterval, "how often the gRPC health service probes the store")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "base URL of an OTLP/HTTP collector, such as http://localhost:4318; when set, RPCs and store operations are traced")
+	spillDir := flag.String("spill-dir", "", "directory for scan results too large for one response; defaults to the system temporary directory")
+	httpAddr := flag.String("http-addr", "", "address such as :8080 to also serve a REST gateway for key operations on; disabled if empty")
+	cacheEntries := flag.Int("cache-entries", 0, "number of recently read values cached in memory in front of the store; 0 disables the cache")
+	checksumAlgorithm := flag.String("checksum", "", "store values with a crc32 or xxh64 checksum verified on every read; values stored before remain readable unverified, and empty disables checksums of new values")
+	encrypt := flag.Bool("encrypt", false, "encrypt stored values at rest with a master key read from -master-key-file or $CLAVIS_MASTER_KEY; previous master keys are read from $CLAVIS_PREVIOUS_MASTER_KEYS")
+	masterKeyFile := flag.String("master-key-file", "", "file holding the master key for -encrypt, as 64 hexadecimal digits or base64")
+	compressCodec := flag.String("compress", "", "compress stored values with snappy or zstd; values stored before remain readable, and empty disables compression of new values")
+	compressThreshold := flag.Int("compress-threshold", compression.DefaultThreshold, "size in bytes from which values are compressed")
+	keyMetadata := flag.Bool("key-metadata", false, "track when each key was created and last updated and how many times it was written, served by the GetMetadata RPC; values written before carry no metadata")
+	indexValues := flag.Bool("search-index", false, "index the words of UTF-8 values, served by the Search RPC; values stored before are searchable once clavis-admin reindex runs")
+	tagIndex := flag.Bool("tag-index", false, "let puts tag keys and index the tags, served by the QueryByTag RPC")
+	softDelete := flag.Duration("soft-delete", 0, "keep deleted values for this long, during which the Undelete RPC restores them; 0 deletes values for good")
+	leaseExpiryInterval := flag.Duration("lease-expiry-interval", lease.DefaultExpiryInterval, "time between checks for expired leases, whose attached keys are then deleted")
+	lockExpiryInterval := flag.Duration("lock-expiry-interval", lock.DefaultExpiryInterval, "time between removals of expired lock leases")
+	purgeInterval := flag.Duration("purge-interval", softdelete.DefaultPurgeInterval, "time between purges of deleted values past their -soft-delete retention")
+	slowOpThreshold := flag.Duration("slow-op-threshold", 0, "log and count store operations taking longer, with the keys and value sizes involved; 0 disables slow operation logging")
+	cacheBytes := flag.Int64("cache-bytes", cache.DefaultMaxBytes, "size of the keys and values the read cache holds at most")
+	quotaRecount := flag.Duration("quota-recount", quota.DefaultRecountAfter, "how long the key and byte usage of a namespace is trusted before it is counted again from the store")
+	respAddr := flag.String("resp-addr", "", "address such as :6379 to also serve a subset of the Redis protocol on; disabled if empty")
+	serviceName := flag.String("service-name", tracing.DefaultServiceName, "service name reported with exported traces")
+	replicationLogSize := flag.Int("replication-log-size", 0, "number of recent mutations kept for followers to replicate; 0 disables serving followers")
+	replicateFrom := flag.String("replicate-from", "", "address of a primary Clavis server to follow, applying every mutation committed on it")
+	replicateToken := flag.String("replicate-token", os.Getenv("CLAVIS_REPLICATION_TOKEN"), "API token to authenticate to the primary with; defaults to $CLAVIS_REPLICATION_TOKEN")
+	replicateTLSCA := flag.String("replicate-tls-ca", "", "PEM CA certificates that sign the primary's certificate; when set, the primary is reached over TLS")
+	readOnly := flag.Bool("read-only", false, "start rejecting client writes while serving reads; implied by -replicate-from and switched at runtime via the admin service")
+	replicateState := flag.String("replicate-state", replicationState, "file recording the last mutation applied from the primary, to resume from after a restart")
+	shadowSpec := flag.String("shadow", "", "store to mirror every write to in the background while migrating, as badger:<path> or clavis:<host:port>; disabled if empty")
+	shadowCompareRate := flag.Float64("shadow-compare-rate", 0.01, "fraction of reads also made on the -shadow store and compared, counting divergences")
+	shadowQueue := flag.Int("shadow-queue", shadow.DefaultQueueSize, "writes waiting to be mirrored to the -shadow store beyond which more are dropped")
+	shadowToken := flag.String("shadow-token", os.Getenv("CLAVIS_SHADOW_TOKEN"), "API token to authenticate to a clavis: -shadow store with; defaults to $CLAVIS_SHADOW_TOKEN")
+	shadowTLSCA := flag.String("shadow-tls-ca", "", "PEM CA certificates that sign the certificate of a clavis: -shadow store; when set, it is reached over TLS")
+	flag.Parse()
+
+	// Every subsystem logs through one structured logger, which also receives the standard log package's output;
+	// its level can be changed at runtime via the admin service
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		log.Fatalf("Invalid -log-level: %v", err)
+	}
+	var levelVar slog.LevelVar
+	levelVar.Set(level)
+	logger, err := logging.New(os.Stderr, logging.Config{Level: &levelVar, Format: logging.Format(*logFormat)})
+	if err != nil {
+		log.Fatalf("Invalid -log-format: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	// With a collector, spans of every RPC and the store operations it makes are exported over OTLP
+	if *otlpEndpoint != "" {
+		provider, err := tracing.New(tracing.Config{Endpoint: *otlpEndpoint, ServiceName: *serviceName, Logger: logger})
+		if err != nil {
+			log.Fatalf("Invalid -otlp-endpoint: %v", err)
+		}
+		tracing.Install(provider)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), tracing.DefaultExportTimeout)
+			defer cancel()
+			if err := provider.Shutdown(ctx); err != nil {
+				logger.Error("Failed to export remaining spans", "error", err)
+			}
+		}()
+	}
+
 	// Initialize storage
-	kvStore, err := badger.NewWithPath(dataPath)
+	storeConfig := badger.DefaultConfig(dataPath)
+	storeConfig.Logger = logger
+	storeConfig.NumVersionsToKeep = *keepVersions
+	storeConfig.GroupCommit = badger.GroupCommitConfig{Window: *groupCommitWindow, MaxWrites: *groupCommitMaxWrites}
+	storeConfig.SequenceBandwidth = *sequenceBandwidth
+	kvStore, err := badger.New(storeConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize storage: %v", err)
 	}
 	defer func() {
 		if err := kvStore.Close(); err != nil {
-			log.Printf("Failed to close storage: %v", err)
+			logger.Error("Failed to close storage", "error", err)
 		}
 	}()
 
+	// Route mutations and lifecycle events through the event bus
+	bus := events.NewBus(events.DefaultBufferSize)
+	defer bus.Close()
+	if *auditLog {
+		bus.Subscribe(events.AuditLogger(log.Default()))
+	}
+	bus.Subscribe(events.MetricsRecorder(metrics.Default))
+	broker := pubsub.NewBroker(bus, pubsub.Config{BufferSize: *pubsubBuffer, MaxSubscribers: *maxSubscribers}, metrics.Default)
+	defer broker.Close()
+	metrics.Default.AddCollector(metrics.CollectRuntime)
+	metrics.Default.AddCollector(kvStore.CollectMetrics)
+
+	// A shadow store receives every write exactly as the backend stores it, so it can take over from it
+	var committedStore store.Store = kvStore
+	if *shadowSpec != "" {
+		target, err := openShadow(*shadowSpec, *shadowTLSCA, *shadowToken)
+		if err != nil {
+			log.Fatalf("Invalid -shadow: %v", err)
+		}
+		shadowStore := shadow.New(kvStore, target, shadow.Config{QueueSize: *shadowQueue, CompareRate: *shadowCompareRate, Logger: logger})
+		defer func() {
+			if err := shadowStore.Stop(); err != nil {
+				logger.Error("Failed to stop mirroring", "error", err)
+			}
+		}()
+		committedStore = shadowStore
+	}
+
+	// Checksums catch stored values corrupted beneath the store
+	if *checksumAlgorithm != "" {
+		algorithm, err := checksum.ParseAlgorithm(*checksumAlgorithm)
+		if err != nil {
+			log.Fatalf("Invalid -checksum: %v", err)
+		}
+		committedStore, err = checksum.New(committedStore, checksum.Config{Algorithm: algorithm})
+		if err != nil {
+			log.Fatalf("Failed to initialize checksums: %v", err)
+		}
+	}
+
+	// Values are encrypted at rest with keys wrapped by the master key, which is never stored
+	var encryptedStore *crypto.Store
+	if *encrypt {
+		encryptionConfig := crypto.Config{Logger: logger}
+		if *masterKeyFile != "" {
+			data, err := os.ReadFile(*masterKeyFile)
+			if err != nil {
+				log.Fatalf("Failed to read -master-key-file: %v", err)
+			}
+			if encryptionConfig.MasterKey, err = crypto.ParseMasterKey(string(data)); err != nil {
+				log.Fatalf("Invalid -master-key-file: %v", err)
+			}
+		}
+		encryptedStore, err = crypto.New(committedStore, encryptionConfig)
+		if err != nil {
+			log.Fatalf("Failed to initialize encryption: %v", err)
+		}
+		committedStore = encryptedStore
+	}
+
+	// Large values are compressed before they are encrypted, and decompressed when read
+	if *compressCodec != "" {
+		codec, err := compression.ParseCodec(*compressCodec)
+		if err != nil {
+			log.Fatalf("Invalid -compress: %v", err)
+		}
+		committedStore, err = compression.New(committedStore, compression.Config{Codec: codec, Threshold: *compressThreshold})
+		if err != nil {
+			log.Fatalf("Failed to initialize compression: %v", err)
+		}
+	}
+
+	// Values carry when their key was created and last updated; tombstones count as updates
+	if *keyMetadata {
+		committedStore = keymeta.New(committedStore)
+	}
+
+	// Deletes leave tombstones that Undelete restores until a purge removes them after -soft-delete
+	var softDeleteStore *softdelete.Store
+	if *softDelete > 0 {
+		softDeleteStore = softdelete.New(committedStore, softdelete.Config{Retention: *softDelete})
+		committedStore = softDeleteStore
+	}
+
+	// Tags are indexed in the same batch as the values they are put with; deleted keys leave the index with their tombstones
+	if *tagIndex {
+		committedStore = tags.New(committedStore)
+	}
+
+	// Values are indexed right after they are written; words end up in the keys of the index, which encryption leaves readable
+	var searchIndex *index.Index
+	if *indexValues {
+		searchIndex = index.New(committedStore, index.Config{})
+		committedStore = searchIndex
+	}
+
+	// Bulk loads may only bypass the wrappers above when none of them changes what is stored
+	valuesUntransformed := committedStore == store.Store(kvStore)
+
+	// Operations slower than -slow-op-threshold are logged with the keys they worked on; cache hits are not
+	if *slowOpThreshold > 0 {
+		committedStore = slowlog.New(committedStore, slowlog.Config{Threshold: *slowOpThreshold, Logger: logger})
+	}
+
+	// Hot values are read from memory; every write goes through the cache, which invalidates it
+	if *cacheEntries > 0 {
+		committedStore = cache.New(committedStore, cache.Config{MaxEntries: *cacheEntries, MaxBytes: *cacheBytes})
+	}
+
+	// With a replication log, every mutation committed to the store can be streamed to followers
+	var replicationLog *replication.Log
+	if *replicationLogSize > 0 {
+		replicationLog = replication.NewLog(*replicationLogSize)
+		committedStore = replication.NewLoggingStore(committedStore, replicationLog)
+	}
+	publishingStore := events.NewPublishingStore(committedStore, bus)
+	var bulkLoader store.Loader
+	var prefixDeleter store.PrefixDeleter
+	if valuesUntransformed && replicationLog == nil {
+		bulkLoader = kvStore
+		// Dropped prefixes would stay readable from the cache
+		if *cacheEntries == 0 {
+			prefixDeleter = kvStore
+		}
+	}
+	fencer := lock.NewFencer(publishingStore)
+	locker := lock.NewLocker(publishingStore, fencer)
+	leases := lease.NewManager(publishingStore)
+
+	// Tokens confirming destructive calls, issued by the admin server and by dry runs of DeletePrefix
+	confirmations := confirm.NewIssuer(confirm.DefaultTTL)
+
 	// Create the gRPC server
-	grpcServer := grpc.NewServer()
+	serverConfig := &proto.GRPCServerConfig{
+		Port:                port,
+		Events:              bus,
+		PubSub:              broker,
+		Fencer:              fencer,
+		Locker:              locker,
+		Leases:              leases,
+		DrainTimeout:        *drainTimeout,
+		TLSCertFile:         *tlsCert,
+		TLSKeyFile:          *tlsKey,
+		TLSClientCAFile:     *tlsClientCA,
+		HealthCheckInterval: *healthInterval,
+		SpillDir:            *spillDir,
+		PrefixDeleter:       prefixDeleter,
+		Confirmations:       confirmations,
+		Logger:              logger,
+		Concurrency: proto.ConcurrencyConfig{
+			MaxInFlight:          *maxInFlight,
+			MaxQueued:            *maxQueued,
+			QueueTimeout:         *queueTimeout,
+			MaxConcurrentStreams: uint32(*maxConcurrentStreams),
+			Exempt:               proto.DefaultConcurrencyExemptions,
+		},
+	}
+	serverConfig.LoadShedder = proto.NewLoadShedder(proto.LoadShedConfig{
+		ErrorRate:    *shedErrorRate,
+		MaxDiskBytes: *shedMaxDiskBytes,
+		MaxPutBytes:  *shedMaxPutBytes,
+		Exempt:       proto.DefaultAuthExemptions,
+		Logger:       logger,
+	}, publishingStore, metrics.Default)
+	creds, err := serverConfig.Credentials()
+	if err != nil {
+		log.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		proto.TracingUnaryInterceptor(otel.GetTracerProvider()),
+		proto.MetricsUnaryInterceptor(metrics.Default),
+		proto.LoggingUnaryInterceptor(logger),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		proto.TracingStreamInterceptor(otel.GetTracerProvider()),
+		proto.MetricsStreamInterceptor(metrics.Default),
+		proto.LoggingStreamInterceptor(logger),
+	}
+
+	// Calls past -max-in-flight queue before any further work is done on them
+	if limiter := proto.NewConcurrencyLimiter(serverConfig.Concurrency, metrics.Default); limiter != nil {
+		unaryInterceptors = append(unaryInterceptors, limiter.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, limiter.StreamInterceptor())
+	}
+
+	// While the store is unhealthy, scans and large puts are turned away before they add to its load
+	if serverConfig.LoadShedder != nil {
+		unaryInterceptors = append(unaryInterceptors, serverConfig.LoadShedder.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, serverConfig.LoadShedder.StreamInterceptor())
+	}
+
+	// With a token file, every call outside the exemptions must authenticate
+	var tokens *auth.StaticTokenStore
+	if *tokenFile != "" {
+		tokens, err = auth.LoadTokenFile(*tokenFile)
+		if err != nil {
+			log.Fatalf("Failed to load API tokens: %v", err)
+		}
+		authConfig := proto.AuthConfig{Tokens: tokens, Exempt: proto.DefaultAuthExemptions}
+		unaryInterceptors = append(unaryInterceptors, proto.AuthUnaryInterceptor(authConfig))
+		streamInterceptors = append(streamInterceptors, proto.AuthStreamInterceptor(authConfig))
+	}
+
+	// With a policy, authenticated principals are limited to the prefixes and operations of their roles
+	var policy *auth.Policy
+	if *policyFile != "" {
+		if tokens == nil {
+			log.Fatal("-policy-file requires -token-file")
+		}
+		policy, err = auth.LoadPolicyFile(*policyFile)
+		if err != nil {
+			log.Fatalf("Failed to load authorization policy: %v", err)
+		}
+		authzConfig := proto.AuthzConfig{Policy: policy, Exempt: proto.DefaultAuthExemptions}
+		unaryInterceptors = append(unaryInterceptors, proto.AuthzUnaryInterceptor(authzConfig))
+		streamInterceptors = append(streamInterceptors, proto.AuthzStreamInterceptor(authzConfig))
+	}
 
-	server, err := proto.New(kvStore, &proto.GRPCServerConfig{Port: port}, grpcServer)
+	// Callers over their rate limit are turned away before their requests are validated
+	if *rateLimit > 0 || len(methodRateLimits) > 0 {
+		if by := proto.RateLimitKey(*rateLimitBy); by != proto.RateLimitByPrincipal && by != proto.RateLimitByIP {
+			log.Fatalf("Unknown -rate-limit-by %q (known: principal, ip)", *rateLimitBy)
+		}
+		rateLimitConfig := proto.RateLimitConfig{
+			Default: proto.RateLimit{RequestsPerSecond: *rateLimit, Burst: *rateLimitBurst},
+			Methods: methodRateLimits,
+			By:      proto.RateLimitKey(*rateLimitBy),
+			Exempt:  proto.DefaultAuthExemptions,
+		}
+		unaryInterceptors = append(unaryInterceptors, proto.RateLimitUnaryInterceptor(rateLimitConfig))
+		streamInterceptors = append(streamInterceptors, proto.RateLimitStreamInterceptor(rateLimitConfig))
+	}
+
+	// Keys the server keeps for itself, such as settings and lock records, are not written by clients
+	unaryInterceptors = append(unaryInterceptors, proto.ReservedUnaryInterceptor())
+	streamInterceptors = append(streamInterceptors, proto.ReservedStreamInterceptor())
+
+	// Requests are checked against the storage engine's limits before any handler runs; stricter
+	// profiles are applied by the validated store
+	requestValidators := proto.DefaultRequestValidators(validation.PermissiveProfile())
+	unaryInterceptors = append(unaryInterceptors, proto.ValidationUnaryInterceptor(requestValidators))
+	streamInterceptors = append(streamInterceptors, proto.ValidationStreamInterceptor(requestValidators))
+
+	serverOptions := append([]grpc.ServerOption{
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}, serverConfig.Concurrency.ServerOptions()...)
+	grpcServer := grpc.NewServer(serverOptions...)
+
+	// Namespace settings live in the store and are edited via the admin service
+	settingsManager := settings.NewManager(publishingStore, bus)
+	defer settingsManager.Close()
+	serverConfig.Namespaces = settingsManager
+
+	// Client writes are validated with the profile bound to the caller or namespace. Without bindings
+	// only the storage engine's limits apply unless a namespace's settings select a profile.
+	validationConfig := validated.Config{Default: validation.ProfilePermissive}
+	if *validationFile != "" {
+		validationConfig, err = validated.LoadConfigFile(*validationFile)
+		if err != nil {
+			log.Fatalf("Failed to load validation profile bindings: %v", err)
+		}
+	}
+	validationConfig.NamespaceProfile = settingsManager.ValidationProfile
+	validationConfig.Events = bus
+	// Namespace quotas are enforced on the writes that passed validation
+	quotaStore := quota.New(publishingStore, quota.Config{
+		Limits:       quota.LimitsFromSettings(settingsManager),
+		RecountAfter: *quotaRecount,
+		Events:       bus,
+	})
+	validatedStore, err := validated.New(quotaStore, validationConfig)
+	if err != nil {
+		log.Fatalf("Invalid validation profile bindings: %v", err)
+	}
+
+	// Clients write through a switch that can turn their writes away, as a replica always does at first
+	initialMode := readonly.Mode{ReadOnly: *readOnly}
+	if *replicateFrom != "" {
+		initialMode = readonly.Mode{ReadOnly: true, Reason: "replica of " + *replicateFrom}
+	}
+	clientStore := readonly.New(validatedStore, initialMode)
+
+	server, err := proto.New(clientStore, serverConfig, grpcServer)
 	if err != nil {
 		log.Fatalf("Failed to create gRPC server: %v", err)
 	}
 
-	if err := server.Start(func() {
-		log.Printf("Server is running on %s", port)
-	}); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// With version history enabled, prune it according to each namespace's retention settings
+	stopRetention := func() {}
+	if *keepVersions > 1 {
+		stopRetention = kvStore.StartRetention(badger.RetentionConfig{Policy: settingsManager.RetentionPolicy})
+	}
+
+	// The disk space of deleted, expired and overwritten values is only reclaimed by garbage collection
+	stopGC := func() {}
+	if *gcDiscardRatio <= 0 || *gcDiscardRatio >= 1 {
+		log.Fatalf("Invalid -gc-discard-ratio: %v is not in (0, 1)", *gcDiscardRatio)
+	}
+	if *gcInterval > 0 {
+		stopGC = kvStore.StartGC(badger.GCConfig{Interval: *gcInterval, DiscardRatio: *gcDiscardRatio})
+	}
+
+	// Expired and denied keys are removed lazily by background maintenance; watchers see expirations as such
+	stopFilter := func() {}
+	if len(filters) > 0 || len(expirations) > 0 {
+		filterConfig := store.FilterConfig{Interval: *filterInterval, Logger: logger}
+		if len(filters) > 0 {
+			filterConfig.Filter = store.AnyFilter(filters...)
+		}
+		if len(expirations) > 0 {
+			filterConfig.Expire = store.AnyFilter(expirations...)
+		}
+		stopFilter = store.StartFilter(publishingStore, filterConfig)
+	}
+
+	// Tombstones past their retention are purged in the background
+	stopPurge := func() {}
+	if softDeleteStore != nil {
+		stopPurge = softDeleteStore.StartPurge(softdelete.PurgeConfig{Interval: *purgeInterval, Logger: logger})
+	}
+
+	// Leases that were neither renewed nor released are removed in the background; watchers see them expire
+	stopLockExpiry := locker.StartExpiry(lock.ExpiryConfig{Interval: *lockExpiryInterval, Logger: logger})
+
+	// Keys attached to leases that were not kept alive are deleted as expired
+	stopLeaseExpiry := leases.StartExpiry(lease.ExpiryConfig{Interval: *leaseExpiryInterval, Logger: logger})
+
+	// Keyspace statistics are seeded from existing data and then kept up to date from writes
+	keyStats := keystats.NewTracker(keystats.DefaultSeparators, keystats.DefaultMaxPrefixes)
+	defer keyStats.Subscribe(bus)()
+	go func() {
+		if err := keyStats.Seed(kvStore); err != nil {
+			logger.Warn("Failed to seed keyspace statistics", "error", err)
+		}
+	}()
+
+	proto.NewAdminServer(publishingStore, proto.AdminServerConfig{
+		Settings:      settingsManager,
+		KeyStats:      keyStats,
+		Metrics:       metrics.Default,
+		Confirmations: confirmations,
+		Replication:   replicationLog,
+		Mode:          clientStore,
+		Encryption:    encryptedStore,
+		Index:         searchIndex,
+		LogLevel:      &levelVar,
+		Validation:    validatedStore,
+		Loader:        bulkLoader,
+	}).Register(grpcServer)
+
+	if *selfTest {
+		passed := runSelfTest(server, serverConfig, tokens, policy, grpcServer, publishingStore)
+		if !passed {
+			if err := kvStore.Close(); err != nil {
+				logger.Error("Failed to close storage", "error", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Serve until interrupted, then drain in-flight requests and close the store
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	served := make(chan error, 3)
+	go func() {
+		served <- server.Start(func() {
+			logger.Info("Server is running", "addr", port)
+		})
+	}()
+
+	// A follower applies the primary's mutations beneath every wrapper, so watchers see them as writes
+	if *replicateFrom != "" {
+		conn, err := dialPrimary(*replicateFrom, *replicateTLSCA, *replicateToken)
+		if err != nil {
+			log.Fatalf("Failed to connect to the primary: %v", err)
+		}
+		defer func() { _ = conn.Close() }()
+		follower, err := replication.NewFollower(conn, publishingStore, replication.FollowerConfig{StateFile: *replicateState, Logger: logger})
+		if err != nil {
+			log.Fatalf("Failed to start replication: %v", err)
+		}
+		go func() {
+			err := follower.Run(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("Replication stopped", "primary", *replicateFrom, "error", err)
+			}
+		}()
+	}
+
+	// Redis clients share the store and credentials of gRPC clients
+	var respServer *resp.Server
+	if *respAddr != "" {
+		respConfig := resp.Config{Addr: *respAddr, Policy: policy, Logger: logger}
+		if tokens != nil {
+			respConfig.Tokens = tokens
+		}
+		respServer = resp.New(clientStore, respConfig)
+		go func() {
+			err := respServer.Start(func() {
+				logger.Info("RESP server is running", "addr", *respAddr)
+			})
+			if err != nil {
+				served <- fmt.Errorf("RESP server: %w", err)
+			}
+		}()
+	}
+
+	// HTTP clients are served by the gRPC service through the same interceptors
+	var gateway *httpgateway.Gateway
+	if *httpAddr != "" {
+		gateway = httpgateway.New(server, httpgateway.Config{Addr: *httpAddr, Interceptors: unaryInterceptors, Logger: logger})
+		go func() {
+			err := gateway.Start(func() {
+				logger.Info("HTTP gateway is running", "addr", *httpAddr)
+			})
+			if err != nil {
+				served <- fmt.Errorf("HTTP gateway: %w", err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	case <-ctx.Done():
+		logger.Info("Shutting down server")
+	}
+
+	if gateway != nil {
+		drainCtx, cancel := context.WithTimeout(context.Background(), *drainTimeout)
+		if err := gateway.Shutdown(drainCtx); err != nil {
+			logger.Error("Unclean HTTP gateway shutdown", "error", err)
+		}
+		cancel()
+	}
+	if respServer != nil {
+		drainCtx, cancel := context.WithTimeout(context.Background(), *drainTimeout)
+		if err := respServer.Shutdown(drainCtx); err != nil {
+			logger.Error("Unclean RESP shutdown", "error", err)
+		}
+		cancel()
+	}
+	stopRetention()
+	stopFilter()
+	stopPurge()
+	stopLockExpiry()
+	stopLeaseExpiry()
+	stopGC()
+	if err := server.Shutdown(context.Background()); err != nil {
+		logger.Error("Unclean shutdown", "error", err)
+	}
+}
+
+// openShadow opens the store writes are mirrored to: a remote server for
+// clavis:<host:port>, reached with caFile and token as a primary is, and a
+// local store opened by migrate.Open otherwise.
+func openShadow(spec, caFile, token string) (store.Store, error) {
+	addr, ok := strings.CutPrefix(spec, "clavis:")
+	if !ok {
+		return migrate.Open(spec)
+	}
+	tlsFlags := tlsutil.ClientFlags{CAFile: caFile}
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	return remote.New(addr, client.Config{
+		Credentials:        creds,
+		Token:              token,
+		AllowInsecureToken: creds.Info().SecurityProtocol == "insecure",
+		Timeout:            shadowTimeout,
+	})
+}
+
+// dialPrimary connects to the primary a follower replicates from.
+func dialPrimary(addr, caFile, token string) (*grpc.ClientConn, error) {
+	tlsFlags := tlsutil.ClientFlags{CAFile: caFile}
+	creds, err := tlsFlags.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if token != "" {
+		opts = append(opts, client.WithToken(token, creds.Info().SecurityProtocol == "insecure"))
 	}
+	return grpc.NewClient(addr, opts...)
 }
@@ -0,0 +1,110 @@
+// Package auth identifies the callers of a Clavis server from the API tokens
+// they present.
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Name string
+}
+
+// TokenStore resolves API tokens to the principals they belong to.
+type TokenStore interface {
+	// Lookup returns the principal owning token, and false if the token is unknown.
+	Lookup(token string) (Principal, bool)
+}
+
+// StaticTokenStore is an in-memory TokenStore. Tokens are kept only as
+// SHA-256 digests, so lookups do not compare secrets byte by byte.
+type StaticTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[[sha256.Size]byte]Principal
+}
+
+// NewStaticTokenStore creates a token store from a map of token to principal name.
+func NewStaticTokenStore(tokens map[string]string) *StaticTokenStore {
+	s := &StaticTokenStore{tokens: make(map[[sha256.Size]byte]Principal, len(tokens))}
+	for token, name := range tokens {
+		s.Add(token, Principal{Name: name})
+	}
+	return s
+}
+
+// Add registers token for principal, replacing any previous owner.
+func (s *StaticTokenStore) Add(token string, principal Principal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[sha256.Sum256([]byte(token))] = principal
+}
+
+// Revoke removes token from the store.
+func (s *StaticTokenStore) Revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, sha256.Sum256([]byte(token)))
+}
+
+// Lookup implements TokenStore.
+func (s *StaticTokenStore) Lookup(token string) (Principal, bool) {
+	if token == "" {
+		return Principal{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	principal, ok := s.tokens[sha256.Sum256([]byte(token))]
+	return principal, ok
+}
+
+// LoadTokenFile reads a token store from path. Each non-empty line holds a
+// principal name and its token separated by whitespace; lines starting with
+// '#' are comments.
+func LoadTokenFile(path string) (*StaticTokenStore, error) {
+	file, err := os.Open(path) // #nosec G304 -- the path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a principal name and a token", path, line)
+		}
+		if _, dup := tokens[fields[1]]; dup {
+			return nil, fmt.Errorf("%s:%d: duplicate token", path, line)
+		}
+		tokens[fields[1]] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	return NewStaticTokenStore(tokens), nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx, if any.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticTokenStore(t *testing.T) {
+	s := NewStaticTokenStore(map[string]string{"secret": "alice"})
+
+	if p, ok := s.Lookup("secret"); !ok || p.Name != "alice" {
+		t.Errorf("Lookup(secret) = %v, %v; want alice, true", p, ok)
+	}
+	for _, token := range []string{"", "other", "secret "} {
+		if _, ok := s.Lookup(token); ok {
+			t.Errorf("Lookup(%q) unexpectedly succeeded", token)
+		}
+	}
+
+	s.Revoke("secret")
+	if _, ok := s.Lookup("secret"); ok {
+		t.Error("Expected revoked token to be rejected")
+	}
+}
+
+func TestLoadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	s, err := LoadTokenFile(write("tokens", "# operators\nalice  t1\n\nbob t2\n"))
+	if err != nil {
+		t.Fatalf("LoadTokenFile failed: %v", err)
+	}
+	if p, ok := s.Lookup("t2"); !ok || p.Name != "bob" {
+		t.Errorf("Lookup(t2) = %v, %v; want bob, true", p, ok)
+	}
+
+	for name, content := range map[string]string{
+		"malformed": "alice\n",
+		"duplicate": "alice t1\nbob t1\n",
+	} {
+		if _, err := LoadTokenFile(write(name, content)); err == nil {
+			t.Errorf("Expected an error for a %s token file", name)
+		}
+	}
+	if _, err := LoadTokenFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing token file")
+	}
+}
+
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFrom(context.Background()); ok {
+		t.Error("Expected no principal in an empty context")
+	}
+	ctx := WithPrincipal(context.Background(), Principal{Name: "alice"})
+	if p, ok := PrincipalFrom(ctx); !ok || p.Name != "alice" {
+		t.Errorf("PrincipalFrom = %v, %v; want alice, true", p, ok)
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Operation is a kind of access to keys.
+type Operation string
+
+// Operations that roles grant.
+const (
+	OpRead   Operation = "read"   // Get the value of a key
+	OpWrite  Operation = "write"  // Put a key
+	OpDelete Operation = "delete" // Delete a key
+	OpScan   Operation = "scan"   // List or watch the keys under a prefix
+	OpAdmin  Operation = "admin"  // Call the admin service; checked against the empty key
+)
+
+// Grant allows operations on the keys under a prefix. An empty prefix covers
+// the whole keyspace.
+type Grant struct {
+	Prefix     string      `json:"prefix"`
+	Operations []Operation `json:"operations"`
+}
+
+// covers reports whether the grant allows op on key.
+func (g Grant) covers(op Operation, key string) bool {
+	if !strings.HasPrefix(key, g.Prefix) {
+		return false
+	}
+	for _, granted := range g.Operations {
+		if granted == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy maps principals to roles and roles to grants. The zero value denies
+// everything.
+type Policy struct {
+	// Roles maps role names to the grants they hold.
+	Roles map[string][]Grant `json:"roles"`
+	// Bindings maps principal names to the roles they hold.
+	Bindings map[string][]string `json:"bindings"`
+}
+
+// Allowed reports whether principal may perform op on key. For OpScan, key is
+// the scanned prefix, so a grant must cover every key the scan could return.
+func (p *Policy) Allowed(principal Principal, op Operation, key string) bool {
+	for _, role := range p.Bindings[principal.Name] {
+		for _, grant := range p.Roles[role] {
+			if grant.covers(op, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks that bindings refer to defined roles and grants to known operations.
+func (p *Policy) Validate() error {
+	for role, grants := range p.Roles {
+		for _, grant := range grants {
+			for _, op := range grant.Operations {
+				switch op {
+				case OpRead, OpWrite, OpDelete, OpScan, OpAdmin:
+				default:
+					return fmt.Errorf("role %q grants unknown operation %q", role, op)
+				}
+			}
+		}
+	}
+	for principal, roles := range p.Bindings {
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return fmt.Errorf("principal %q is bound to undefined role %q", principal, role)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPolicyFile reads and validates a JSON encoded Policy from path.
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- the path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
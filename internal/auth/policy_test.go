@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+	policy := &Policy{
+		Roles: map[string][]Grant{
+			"tenant-a":  {{Prefix: "tenant-a/", Operations: []Operation{OpRead, OpWrite, OpDelete, OpScan}}},
+			"reader":    {{Prefix: "", Operations: []Operation{OpRead}}},
+			"operators": {{Prefix: "", Operations: []Operation{OpAdmin}}},
+		},
+		Bindings: map[string][]string{
+			"alice": {"tenant-a"},
+			"bob":   {"reader", "operators"},
+		},
+	}
+	alice, bob, eve := Principal{Name: "alice"}, Principal{Name: "bob"}, Principal{Name: "eve"}
+
+	tests := []struct {
+		principal Principal
+		op        Operation
+		key       string
+		want      bool
+	}{
+		{alice, OpRead, "tenant-a/x", true},
+		{alice, OpWrite, "tenant-a/x", true},
+		{alice, OpRead, "tenant-b/x", false},
+		{alice, OpScan, "tenant-a/", true},
+		{alice, OpScan, "tenant-", false},
+		{alice, OpScan, "", false},
+		{alice, OpAdmin, "", false},
+		{bob, OpRead, "tenant-b/x", true},
+		{bob, OpWrite, "tenant-b/x", false},
+		{bob, OpAdmin, "", true},
+		{eve, OpRead, "tenant-a/x", false},
+	}
+	for _, tt := range tests {
+		if got := policy.Allowed(tt.principal, tt.op, tt.key); got != tt.want {
+			t.Errorf("Allowed(%s, %s, %q) = %v, want %v", tt.principal.Name, tt.op, tt.key, got, tt.want)
+		}
+	}
+
+	var zero Policy
+	if zero.Allowed(alice, OpRead, "tenant-a/x") {
+		t.Error("Expected the zero policy to deny everything")
+	}
+}
+
+func TestLoadPolicyFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	policy, err := LoadPolicyFile(write("policy.json", `{
+		"roles": {"tenant-a": [{"prefix": "tenant-a/", "operations": ["read", "scan"]}]},
+		"bindings": {"alice": ["tenant-a"]}
+	}`))
+	if err != nil {
+		t.Fatalf("LoadPolicyFile failed: %v", err)
+	}
+	if !policy.Allowed(Principal{Name: "alice"}, OpScan, "tenant-a/") {
+		t.Error("Expected the loaded policy to allow alice to scan her prefix")
+	}
+
+	invalid := map[string]string{
+		"syntax":         `{"roles": `,
+		"unknown-op":     `{"roles": {"r": [{"prefix": "", "operations": ["fly"]}]}}`,
+		"undefined-role": `{"bindings": {"alice": ["ghost"]}}`,
+	}
+	for name, content := range invalid {
+		if _, err := LoadPolicyFile(write(name+".json", content)); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
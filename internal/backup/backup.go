@@ -0,0 +1,222 @@
+// Package backup frames store snapshots for transfer and storage. A snapshot
+// starts with a header naming the format version, the time it was taken and
+// the store version it covers changes after, continues with the store's own
+// dump, and ends with the store version the dump is complete up to and a
+// SHA-256 checksum of everything before it, so truncated or corrupted files
+// are detected before they are trusted.
+//
+// A snapshot covering changes after version 0 is a full backup. Others are
+// incremental and are restored on top of the chain of backups they build on,
+// each covering the changes after the version the previous one ends at.
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// Magic opens every snapshot.
+const Magic = "CLAVISBK"
+
+// FormatVersion is the version of the framing written by NewWriter. Version
+// 1 snapshots, which are always full and do not record store versions, are
+// still read.
+const FormatVersion uint16 = 2
+
+// checksumSize is the size of the trailing checksum.
+const checksumSize = sha256.Size
+
+var (
+	// ErrInvalidFormat is returned for input that is not a snapshot, or is
+	// one of an unsupported version.
+	ErrInvalidFormat = errors.New("not a clavis snapshot")
+	// ErrChecksumMismatch is returned when a snapshot is truncated or its
+	// contents do not match its checksum.
+	ErrChecksumMismatch = errors.New("snapshot checksum mismatch")
+	// ErrBrokenChain is returned for backups that do not follow each other.
+	ErrBrokenChain = errors.New("backups do not form a chain")
+)
+
+// Header describes a snapshot.
+type Header struct {
+	Version   uint16
+	CreatedAt time.Time // Second precision
+	// Since is the store version the snapshot covers changes after; 0 for
+	// full backups.
+	Since uint64
+	// Until is the store version the snapshot is complete up to. It is
+	// recorded at the end of a snapshot, so it is only known once the whole
+	// snapshot was read.
+	Until uint64
+}
+
+// Incremental reports whether the snapshot builds on earlier ones.
+func (h Header) Incremental() bool {
+	return h.Since > 0
+}
+
+// Writer frames a store dump written to it. Finish must be called to
+// complete the snapshot.
+type Writer struct {
+	w    io.Writer
+	hash hash.Hash
+}
+
+// NewWriter writes the header of a snapshot taken now of the changes after
+// store version since to w.
+func NewWriter(w io.Writer, since uint64) (*Writer, error) {
+	bw := &Writer{w: w, hash: sha256.New()}
+	var header bytes.Buffer
+	header.WriteString(Magic)
+	_ = binary.Write(&header, binary.BigEndian, FormatVersion)
+	_ = binary.Write(&header, binary.BigEndian, time.Now().Unix())
+	_ = binary.Write(&header, binary.BigEndian, since)
+	if _, err := bw.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+	return bw, nil
+}
+
+// Write adds p to the snapshot.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.hash.Write(p[:n])
+	return n, err
+}
+
+// Finish records the store version the dump is complete up to and writes
+// the checksum. It does not close the underlying writer.
+func (w *Writer) Finish(until uint64) error {
+	if err := binary.Write(w, binary.BigEndian, until); err != nil {
+		return err
+	}
+	_, err := w.w.Write(w.hash.Sum(nil))
+	return err
+}
+
+// Reader returns the store dump of a snapshot. It holds back the trailer
+// and, on reaching the end of the input, returns ErrChecksumMismatch instead
+// of io.EOF if the snapshot was not intact.
+type Reader struct {
+	r      io.Reader
+	hash   hash.Hash
+	header Header
+	// trailerSize is the size of the trailer of the snapshot's version
+	trailerSize int
+	// pending holds read bytes not yet returned, the last trailerSize of
+	// which may turn out to be the trailer
+	pending []byte
+	chunk   []byte
+	err     error // Error of the last read from r
+	done    bool  // Whether the trailer was checked
+}
+
+// NewReader reads the header of a snapshot from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := &Reader{r: r, hash: sha256.New(), chunk: make([]byte, 32*1024)}
+	fixed := make([]byte, len(Magic)+2+8)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if string(fixed[:len(Magic)]) != Magic {
+		return nil, ErrInvalidFormat
+	}
+	br.hash.Write(fixed)
+	br.header.Version = binary.BigEndian.Uint16(fixed[len(Magic):])
+	br.header.CreatedAt = time.Unix(int64(binary.BigEndian.Uint64(fixed[len(Magic)+2:])), 0) // #nosec G115 -- written from a Unix time
+
+	switch br.header.Version {
+	case 1:
+		br.trailerSize = checksumSize
+	case FormatVersion:
+		br.trailerSize = 8 + checksumSize
+		since := make([]byte, 8)
+		if _, err := io.ReadFull(r, since); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+		}
+		br.hash.Write(since)
+		br.header.Since = binary.BigEndian.Uint64(since)
+	default:
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, br.header.Version)
+	}
+	return br, nil
+}
+
+// Header returns the header of the snapshot. Its Until is only set once
+// Read has returned io.EOF.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Read reads the store dump.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	for len(r.pending) <= r.trailerSize && r.err == nil {
+		var n int
+		n, r.err = r.r.Read(r.chunk)
+		r.pending = append(r.pending, r.chunk[:n]...)
+	}
+	if len(r.pending) <= r.trailerSize {
+		if !errors.Is(r.err, io.EOF) {
+			return 0, r.err
+		}
+		return 0, r.finish()
+	}
+	n := copy(p, r.pending[:len(r.pending)-r.trailerSize])
+	r.hash.Write(p[:n])
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// finish checks the trailer left in pending once the input is exhausted.
+func (r *Reader) finish() error {
+	if len(r.pending) < r.trailerSize {
+		return ErrChecksumMismatch
+	}
+	values, checksum := r.pending[:r.trailerSize-checksumSize], r.pending[r.trailerSize-checksumSize:]
+	r.hash.Write(values)
+	if !bytes.Equal(r.hash.Sum(nil), checksum) {
+		return ErrChecksumMismatch
+	}
+	if len(values) == 8 {
+		r.header.Until = binary.BigEndian.Uint64(values)
+	}
+	r.done = true
+	return io.EOF
+}
+
+// Verify reads a whole snapshot from r, checking its framing and checksum.
+func Verify(r io.Reader) (Header, error) {
+	br, err := NewReader(r)
+	if err != nil {
+		return Header{}, err
+	}
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return Header{}, err
+	}
+	return br.Header(), nil
+}
+
+// CheckChain reports whether headers, in restore order, follow each other:
+// every backup after the first is incremental and covers the changes after
+// the version the previous one ends at. The first may be incremental too, to be
+// restored on top of a store holding the backups it builds on.
+func CheckChain(headers []Header) error {
+	for i := 1; i < len(headers); i++ {
+		switch header := headers[i]; {
+		case !header.Incremental():
+			return fmt.Errorf("%w: backup %d is a full backup", ErrBrokenChain, i+1)
+		case header.Since != headers[i-1].Until:
+			return fmt.Errorf("%w: backup %d starts after version %d but the one before ends at %d", ErrBrokenChain, i+1, header.Since, headers[i-1].Until)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func snapshot(t *testing.T, dump string) []byte {
+	t.Helper()
+	return snapshotOf(t, dump, 0, 42)
+}
+
+func snapshotOf(t *testing.T, dump string, since, until uint64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, dump); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Finish(until); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	dump := strings.Repeat("clavis", 20000)
+	r, err := NewReader(bytes.NewReader(snapshot(t, dump)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Header().Version != FormatVersion || time.Since(r.Header().CreatedAt) > time.Minute {
+		t.Errorf("Unexpected header %+v", r.Header())
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || string(got) != dump {
+		t.Errorf("Expected the dump back, got %d bytes and %v", len(got), err)
+	}
+	if r.Header().Since != 0 || r.Header().Until != 42 {
+		t.Errorf("Expected versions 0-42 once read, got %+v", r.Header())
+	}
+
+	if _, err := Verify(bytes.NewReader(snapshot(t, ""))); err != nil {
+		t.Errorf("Expected an empty dump to verify, got %v", err)
+	}
+}
+
+func TestDamagedSnapshots(t *testing.T) {
+	data := snapshot(t, "some dump of a store")
+	corrupted := bytes.Clone(data)
+	corrupted[len(Magic)+12] ^= 0xff
+	outdated := bytes.Clone(data)
+	outdated[len(Magic)+1] = 9
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"corrupted", corrupted, ErrChecksumMismatch},
+		{"truncated", data[:len(data)-5], ErrChecksumMismatch},
+		{"not a snapshot", []byte("hello world, this is plainly not a snapshot"), ErrInvalidFormat},
+		{"unsupported version", outdated, ErrInvalidFormat},
+		{"empty", nil, ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncrementalSnapshots(t *testing.T) {
+	header, err := Verify(bytes.NewReader(snapshotOf(t, "changes", 42, 50)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !header.Incremental() || header.Since != 42 || header.Until != 50 {
+		t.Errorf("Unexpected header %+v", header)
+	}
+
+	full := Header{Until: 42}
+	next := Header{Since: 42, Until: 50}
+	tests := []struct {
+		name    string
+		headers []Header
+		wantErr bool
+	}{
+		{"full and incrementals", []Header{full, next, {Since: 50, Until: 50}}, false},
+		{"incrementals only", []Header{next, {Since: 50, Until: 60}}, false},
+		{"gap", []Header{full, {Since: 43, Until: 50}}, true},
+		{"out of order", []Header{next, full}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckChain(tt.headers); (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrBrokenChain)) {
+				t.Errorf("CheckChain() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadsVersion1Snapshots(t *testing.T) {
+	dump := "an old dump"
+	var data bytes.Buffer
+	data.WriteString(Magic)
+	_ = binary.Write(&data, binary.BigEndian, uint16(1))
+	_ = binary.Write(&data, binary.BigEndian, time.Now().Unix())
+	data.WriteString(dump)
+	sum := sha256.Sum256(data.Bytes())
+	data.Write(sum[:])
+
+	r, err := NewReader(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || string(got) != dump || r.Header().Incremental() {
+		t.Errorf("Expected the full dump back, got %q, %v and %+v", got, err, r.Header())
+	}
+}
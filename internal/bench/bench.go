@@ -0,0 +1,214 @@
+// Package bench runs workloads of Gets and Puts against a live Clavis server
+// and reports their throughput and latency percentiles.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// Defaults of the fields of a Config left zero.
+const (
+	DefaultConcurrency = 16
+	DefaultDuration    = 10 * time.Second
+	DefaultKeys        = 10000
+	DefaultKeySize     = 32
+	DefaultValueSize   = 256
+	DefaultKeyPrefix   = "bench/"
+)
+
+// Config describes a workload.
+type Config struct {
+	Concurrency int           // Requests in flight at once; DefaultConcurrency if zero
+	Duration    time.Duration // How long the workload runs; DefaultDuration if zero
+	Requests    int           // Requests after which the workload stops early; unbounded if zero
+	ReadRatio   float64       // Share of requests that are Gets rather than Puts, from 0 to 1
+
+	Keys      int    // Distinct keys the requests pick from at random; DefaultKeys if zero
+	KeySize   int    // Length of keys in bytes, at least that of their prefix and number; DefaultKeySize if zero
+	ValueSize int    // Length of values in bytes; DefaultValueSize if zero
+	KeyPrefix string // Prefix of every key; DefaultKeyPrefix if empty
+	Namespace string // Namespace of the requests
+
+	// Preload writes every key once before the workload starts, so Gets find
+	// values; otherwise Gets of keys not written yet measure misses
+	Preload bool
+}
+
+// withDefaults returns c with its zero fields set to their defaults.
+func (c Config) withDefaults() Config {
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultConcurrency
+	}
+	if c.Duration <= 0 {
+		c.Duration = DefaultDuration
+	}
+	if c.Keys <= 0 {
+		c.Keys = DefaultKeys
+	}
+	if c.KeySize <= 0 {
+		c.KeySize = DefaultKeySize
+	}
+	if c.ValueSize <= 0 {
+		c.ValueSize = DefaultValueSize
+	}
+	if c.KeyPrefix == "" {
+		c.KeyPrefix = DefaultKeyPrefix
+	}
+	return c
+}
+
+// Validate reports a workload that cannot run.
+func (c Config) Validate() error {
+	switch {
+	case c.ReadRatio < 0 || c.ReadRatio > 1:
+		return fmt.Errorf("read ratio %v is not between 0 and 1", c.ReadRatio)
+	case c.Requests < 0:
+		return errors.New("requests cannot be negative")
+	}
+	return nil
+}
+
+// Run runs the workload described by config against client until its
+// duration ends, its requests are sent or ctx is done. Failed requests are
+// counted rather than stopping the run; Run only fails when the workload is
+// invalid or preloading fails.
+func Run(ctx context.Context, client proto.ClavisClient, config Config) (*Result, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config = config.withDefaults()
+	value := Value(config.ValueSize)
+	if config.Preload {
+		if err := preload(ctx, client, config, value); err != nil {
+			return nil, fmt.Errorf("preload failed: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+	var requests *budget
+	if config.Requests > 0 {
+		requests = newBudget(config.Requests)
+	}
+
+	recorders := make([]*recorder, config.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range recorders {
+		recorders[i] = newRecorder()
+		wg.Add(1)
+		go func(rec *recorder) {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(i), uint64(start.UnixNano()))) // #nosec G404 -- keys need no cryptographic randomness
+			for ctx.Err() == nil && requests.take() {
+				key := Key(config.KeyPrefix, rng.IntN(config.Keys), config.KeySize)
+				if rng.Float64() < config.ReadRatio {
+					rec.time(ctx, OpGet, func() (int, error) {
+						resp, err := client.Get(ctx, &proto.GetRequest{Key: key, Namespace: config.Namespace})
+						return len(key) + len(resp.GetValue()), err
+					})
+				} else {
+					rec.time(ctx, OpPut, func() (int, error) {
+						_, err := client.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: config.Namespace})
+						return len(key) + len(value), err
+					})
+				}
+			}
+		}(recorders[i])
+	}
+	wg.Wait()
+	return newResult(time.Since(start), recorders), nil
+}
+
+// preload writes every key of the workload once.
+func preload(ctx context.Context, client proto.ClavisClient, config Config, value []byte) error {
+	keys := make(chan int)
+	errs := make(chan error, config.Concurrency)
+	var wg sync.WaitGroup
+	for range config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range keys {
+				key := Key(config.KeyPrefix, i, config.KeySize)
+				if _, err := client.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: config.Namespace}); err != nil {
+					errs <- fmt.Errorf("key %q: %w", key, err)
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+send:
+	for i := range config.Keys {
+		select {
+		case keys <- i:
+		case err = <-errs:
+			break send
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		}
+	}
+	close(keys)
+	wg.Wait()
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return err
+}
+
+// budget hands out a bounded number of requests to the workers. A nil budget
+// is unbounded.
+type budget struct {
+	mu   sync.Mutex
+	left int
+}
+
+func newBudget(n int) *budget {
+	return &budget{left: n}
+}
+
+// take reports whether a request is left, taking it.
+func (b *budget) take() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.left == 0 {
+		return false
+	}
+	b.left--
+	return true
+}
+
+// Key returns key number n under prefix, padded with zeros to size bytes when
+// shorter, so keys of a workload share one length.
+func Key(prefix string, n, size int) string {
+	number := fmt.Sprint(n)
+	if pad := size - len(prefix) - len(number); pad > 0 {
+		number = fmt.Sprintf("%0*d", pad+len(number), n)
+	}
+	return prefix + number
+}
+
+// Value returns a value of size bytes.
+func Value(size int) []byte {
+	value := make([]byte, size)
+	for i := range value {
+		value[i] = byte(i % 256)
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeClient serves Gets and Puts from a map.
+type fakeClient struct {
+	proto.ClavisClient
+	mu     sync.Mutex
+	values map[string][]byte
+	putErr error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{values: make(map[string][]byte)}
+}
+
+func (c *fakeClient) Get(ctx context.Context, req *proto.GetRequest, opts ...grpc.CallOption) (*proto.GetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, found := c.values[req.Namespace+"/"+req.Key]
+	return &proto.GetResponse{Value: value, Found: found}, nil
+}
+
+func (c *fakeClient) Put(ctx context.Context, req *proto.PutRequest, opts ...grpc.CallOption) (*proto.PutResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.putErr != nil {
+		return nil, c.putErr
+	}
+	c.values[req.Namespace+"/"+req.Key] = req.Value
+	return &proto.PutResponse{}, nil
+}
+
+func TestRun(t *testing.T) {
+	client := newFakeClient()
+	result, err := Run(context.Background(), client, Config{
+		Concurrency: 4,
+		Duration:    time.Minute,
+		Requests:    200,
+		ReadRatio:   1,
+		Keys:        10,
+		KeySize:     16,
+		ValueSize:   100,
+		Namespace:   "ns",
+		Preload:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.values) != 10 {
+		t.Errorf("Expected 10 keys preloaded, got %d", len(client.values))
+	}
+	for key, value := range client.values {
+		if !strings.HasPrefix(key, "ns/"+DefaultKeyPrefix) || len(key) != len("ns/")+16 || len(value) != 100 {
+			t.Errorf("Unexpected preloaded pair %q of %d bytes", key, len(value))
+		}
+	}
+	if result.Total.Requests != 200 || result.Total.Errors != 0 {
+		t.Errorf("Expected 200 successful requests, got %d with %d errors", result.Total.Requests, result.Total.Errors)
+	}
+	gets, ok := result.Operations[OpGet]
+	if !ok || gets.Requests != 200 || len(gets.Latencies) != 200 {
+		t.Fatalf("Expected every request to be a get, got %+v", result.Operations)
+	}
+	if _, ok := result.Operations[OpPut]; ok {
+		t.Error("Expected no puts with a read ratio of 1")
+	}
+	if gets.Bytes != 200*(16+100) {
+		t.Errorf("Expected %d bytes read, got %d", 200*(16+100), gets.Bytes)
+	}
+	if result.Throughput(result.Total) <= 0 {
+		t.Error("Expected a positive throughput")
+	}
+}
+
+func TestRun_CountsErrors(t *testing.T) {
+	client := newFakeClient()
+	client.putErr = errors.New("unavailable")
+	result, err := Run(context.Background(), client, Config{Concurrency: 2, Requests: 50, Keys: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	puts := result.Operations[OpPut]
+	if puts == nil || puts.Requests != 50 || puts.Errors != 50 || len(puts.Latencies) != 0 || puts.FirstErr == nil {
+		t.Errorf("Expected 50 failed puts, got %+v", puts)
+	}
+
+	if _, err := Run(context.Background(), client, Config{Keys: 5, Preload: true}); err == nil {
+		t.Error("Expected a failing preload to fail the run")
+	}
+	if _, err := Run(context.Background(), client, Config{ReadRatio: 2}); err == nil {
+		t.Error("Expected an invalid read ratio to be rejected")
+	}
+}
+
+func TestRun_StopsAtDuration(t *testing.T) {
+	start := time.Now()
+	result, err := Run(context.Background(), newFakeClient(), Config{Concurrency: 2, Duration: 50 * time.Millisecond, ReadRatio: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the run to end after its duration, took %v", elapsed)
+	}
+	if result.Total.Requests == 0 || result.Total.Errors != 0 {
+		t.Errorf("Expected successful requests, got %d with %d errors", result.Total.Requests, result.Total.Errors)
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		prefix string
+		n      int
+		size   int
+		want   string
+	}{
+		{"k/", 7, 6, "k/0007"},
+		{"k/", 12345, 4, "k/12345"},
+		{"", 3, 0, "3"},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.prefix, tt.n, tt.size); got != tt.want {
+			t.Errorf("Key(%q, %d, %d) = %q, want %q", tt.prefix, tt.n, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestStats_Percentile(t *testing.T) {
+	s := &Stats{}
+	if s.Percentile(50) != 0 {
+		t.Error("Expected no latency without requests")
+	}
+	for i := 1; i <= 100; i++ {
+		s.Latencies = append(s.Latencies, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[float64]time.Duration{50: 51 * time.Millisecond, 99: 100 * time.Millisecond, 100: 100 * time.Millisecond, 0: time.Millisecond} {
+		if got := s.Percentile(p); got != want {
+			t.Errorf("Percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestResult_Report(t *testing.T) {
+	result := newResult(time.Second, []*recorder{{ops: map[string]*Stats{
+		OpGet: {Requests: 3, Latencies: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}},
+		OpPut: {Requests: 2, Errors: 1, Latencies: []time.Duration{time.Millisecond}, FirstErr: errors.New("quota exceeded")},
+	}}})
+
+	var buf bytes.Buffer
+	if err := result.Report(&buf); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	for _, want := range []string{"p99.9", "get", "put", "total", "5 requests in 1s", "First put error: quota exceeded"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+// Operations measured by a workload.
+const (
+	OpGet = "get"
+	OpPut = "put"
+)
+
+// Stats describes the requests of one operation.
+type Stats struct {
+	Requests  int
+	Errors    int
+	Bytes     int64           // Key and value bytes sent or received by successful requests
+	Latencies []time.Duration // Of the successful requests, sorted
+	FirstErr  error           // First error of a failed request, if any
+}
+
+// Percentile returns the latency under which p percent of the successful
+// requests completed, or zero without any.
+func (s *Stats) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	i := int(p / 100 * float64(len(s.Latencies)))
+	return s.Latencies[min(i, len(s.Latencies)-1)]
+}
+
+// merge adds the requests of other to s, leaving the latencies unsorted.
+func (s *Stats) merge(other *Stats) {
+	s.Requests += other.Requests
+	s.Errors += other.Errors
+	s.Bytes += other.Bytes
+	s.Latencies = append(s.Latencies, other.Latencies...)
+	if s.FirstErr == nil {
+		s.FirstErr = other.FirstErr
+	}
+}
+
+// Result reports a workload.
+type Result struct {
+	Elapsed    time.Duration
+	Operations map[string]*Stats // By operation, for the operations sent
+	Total      *Stats            // Every request
+}
+
+// Throughput returns the requests of s completed per second of the workload.
+func (r *Result) Throughput(s *Stats) float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Requests-s.Errors) / r.Elapsed.Seconds()
+}
+
+// Report writes a table of the throughput and latency percentiles of every
+// operation and of all of them, followed by the first error of each failing
+// operation.
+func (r *Result) Report(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\trequests\terrors\treq/s\tMB/s\tp50\tp90\tp99\tp99.9\tmax\t")
+	row := func(name string, s *Stats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.0f\t%.2f\t%v\t%v\t%v\t%v\t%v\t\n", name, s.Requests, s.Errors,
+			r.Throughput(s), float64(s.Bytes)/1e6/r.Elapsed.Seconds(),
+			round(s.Percentile(50)), round(s.Percentile(90)), round(s.Percentile(99)), round(s.Percentile(99.9)), round(s.Percentile(100)))
+	}
+	for _, op := range []string{OpGet, OpPut} {
+		if s, ok := r.Operations[op]; ok {
+			row(op, s)
+		}
+	}
+	row("total", r.Total)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d requests in %v\n", r.Total.Requests, r.Elapsed.Round(time.Millisecond))
+	for _, op := range []string{OpGet, OpPut} {
+		if s, ok := r.Operations[op]; ok && s.FirstErr != nil && err == nil {
+			_, err = fmt.Fprintf(w, "First %s error: %v\n", op, s.FirstErr)
+		}
+	}
+	return err
+}
+
+// round rounds latencies for display.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
+
+// recorder collects the requests of one worker, so workers record without
+// contending.
+type recorder struct {
+	ops map[string]*Stats
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: make(map[string]*Stats)}
+}
+
+// time runs a request of op returning the bytes it moved and records it. A
+// request failing because the workload ended is not recorded.
+func (r *recorder) time(ctx context.Context, op string, request func() (int, error)) {
+	start := time.Now()
+	bytes, err := request()
+	elapsed := time.Since(start)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	s, ok := r.ops[op]
+	if !ok {
+		s = &Stats{}
+		r.ops[op] = s
+	}
+	s.Requests++
+	if err != nil {
+		s.Errors++
+		if s.FirstErr == nil {
+			s.FirstErr = err
+		}
+		return
+	}
+	s.Bytes += int64(bytes)
+	s.Latencies = append(s.Latencies, elapsed)
+}
+
+// newResult merges the requests of the workers.
+func newResult(elapsed time.Duration, recorders []*recorder) *Result {
+	r := &Result{Elapsed: elapsed, Operations: make(map[string]*Stats), Total: &Stats{}}
+	for _, rec := range recorders {
+		for op, s := range rec.ops {
+			merged, ok := r.Operations[op]
+			if !ok {
+				merged = &Stats{}
+				r.Operations[op] = merged
+			}
+			merged.merge(s)
+			r.Total.merge(s)
+		}
+	}
+	for _, s := range r.Operations {
+		slices.Sort(s.Latencies)
+	}
+	slices.Sort(r.Total.Latencies)
+	return r
+}
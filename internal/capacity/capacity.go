@@ -0,0 +1,250 @@
+// Package capacity analyzes stored values to help with capacity planning: it
+// samples values per prefix, estimates how many are duplicates and how well
+// they compress, and recommends deduplication or compression where they
+// would pay off.
+package capacity
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/keystats"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// Defaults used for zero Config fields.
+const (
+	DefaultSampleRate           = 0.1
+	DefaultMaxSamples           = 10000
+	DefaultMinSamples           = 32
+	DefaultDedupThreshold       = 0.2
+	DefaultCompressionThreshold = 0.7
+)
+
+// Recommendation is a storage feature worth enabling for a prefix.
+type Recommendation string
+
+const (
+	// RecommendDedup suggests content-addressed storage of values.
+	RecommendDedup Recommendation = "dedup"
+	// RecommendCompression suggests compressing values.
+	RecommendCompression Recommendation = "compression"
+)
+
+// Config tunes an analysis. Zero fields select the defaults.
+type Config struct {
+	SampleRate  float64 // Fraction of keys sampled, chosen by key hash so runs are repeatable
+	MaxSamples  int     // Values sampled per prefix at most
+	Separators  string  // Characters ending the top-level prefix of a key; keystats.DefaultSeparators if empty
+	MaxPrefixes int     // Prefixes reported separately; the rest are merged into keystats.OverflowPrefix
+
+	MinSamples           int     // Samples a prefix needs before anything is recommended for it
+	DedupThreshold       float64 // Recommend dedup when at least this fraction of sampled bytes is duplicated
+	CompressionThreshold float64 // Recommend compression when values compress to at most this fraction of their size
+}
+
+func (c Config) withDefaults() Config {
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		c.SampleRate = DefaultSampleRate
+	}
+	if c.MaxSamples <= 0 {
+		c.MaxSamples = DefaultMaxSamples
+	}
+	if c.Separators == "" {
+		c.Separators = keystats.DefaultSeparators
+	}
+	if c.MaxPrefixes <= 0 {
+		c.MaxPrefixes = keystats.DefaultMaxPrefixes
+	}
+	if c.MinSamples <= 0 {
+		c.MinSamples = DefaultMinSamples
+	}
+	if c.DedupThreshold <= 0 {
+		c.DedupThreshold = DefaultDedupThreshold
+	}
+	if c.CompressionThreshold <= 0 {
+		c.CompressionThreshold = DefaultCompressionThreshold
+	}
+	return c
+}
+
+// PrefixReport describes the sampled values of one top-level prefix.
+// Duplicates are only detected among sampled values, so the duplicate ratio
+// is a lower bound that tightens as the sample rate grows.
+type PrefixReport struct {
+	Prefix          string
+	SampledValues   uint64
+	SampledBytes    uint64
+	DuplicateValues uint64 // Sampled values identical to an earlier sampled value
+	DuplicateBytes  uint64
+	CompressedBytes uint64 // Size of the sampled values compressed one by one
+
+	Recommendations []Recommendation
+}
+
+// DuplicateRatio returns the fraction of sampled bytes held by duplicates.
+func (p PrefixReport) DuplicateRatio() float64 {
+	if p.SampledBytes == 0 {
+		return 0
+	}
+	return float64(p.DuplicateBytes) / float64(p.SampledBytes)
+}
+
+// CompressionRatio returns the compressed size of the sampled values as a
+// fraction of their size; lower is better.
+func (p PrefixReport) CompressionRatio() float64 {
+	if p.SampledBytes == 0 {
+		return 1
+	}
+	return float64(p.CompressedBytes) / float64(p.SampledBytes)
+}
+
+// Report is the outcome of an analysis.
+type Report struct {
+	Prefixes    []PrefixReport // Sorted by sampled bytes, largest first
+	ScannedKeys uint64
+	SampledKeys uint64
+	Duration    time.Duration
+}
+
+// prefixSample accumulates the samples of one prefix.
+type prefixSample struct {
+	report  PrefixReport
+	digests map[[sha256.Size]byte]struct{}
+}
+
+// Analyze samples the values of s and reports on them. It stops early with
+// the context's error if ctx ends.
+func Analyze(ctx context.Context, s store.Store, config Config) (Report, error) {
+	config = config.withDefaults()
+	start := time.Now()
+	threshold := uint64(config.SampleRate * math.MaxUint64)
+
+	it, err := store.NewIterator(s, "")
+	if err != nil {
+		return Report{}, err
+	}
+	defer func() { _ = it.Close() }()
+
+	compressor := newCompressor()
+	prefixes := make(map[string]*prefixSample)
+	var report Report
+	for it.Next() {
+		if report.ScannedKeys%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return Report{}, err
+			}
+		}
+		report.ScannedKeys++
+
+		key := it.Key()
+		if config.SampleRate < 1 && hashKey(key) > threshold {
+			continue
+		}
+
+		prefix := topLevelPrefix(key, config.Separators)
+		sample, ok := prefixes[prefix]
+		if !ok {
+			if len(prefixes) >= config.MaxPrefixes {
+				prefix = keystats.OverflowPrefix
+				sample, ok = prefixes[prefix]
+			}
+			if !ok {
+				sample = &prefixSample{report: PrefixReport{Prefix: prefix}, digests: make(map[[sha256.Size]byte]struct{})}
+				prefixes[prefix] = sample
+			}
+		}
+		if sample.report.SampledValues >= uint64(config.MaxSamples) { // #nosec G115 -- MaxSamples is positive
+			continue
+		}
+
+		value := it.Value()
+		size := uint64(len(value))
+		report.SampledKeys++
+		sample.report.SampledValues++
+		sample.report.SampledBytes += size
+		sample.report.CompressedBytes += compressor.size(value)
+
+		digest := sha256.Sum256(value)
+		if _, seen := sample.digests[digest]; seen {
+			sample.report.DuplicateValues++
+			sample.report.DuplicateBytes += size
+		} else {
+			sample.digests[digest] = struct{}{}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return Report{}, err
+	}
+
+	report.Prefixes = make([]PrefixReport, 0, len(prefixes))
+	for _, sample := range prefixes {
+		report.Prefixes = append(report.Prefixes, recommend(sample.report, config))
+	}
+	sort.Slice(report.Prefixes, func(i, j int) bool {
+		a, b := report.Prefixes[i], report.Prefixes[j]
+		if a.SampledBytes != b.SampledBytes {
+			return a.SampledBytes > b.SampledBytes
+		}
+		return a.Prefix < b.Prefix
+	})
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// recommend fills in the recommendations for a prefix.
+func recommend(p PrefixReport, config Config) PrefixReport {
+	if p.SampledValues < uint64(config.MinSamples) { // #nosec G115 -- MinSamples is positive
+		return p
+	}
+	if p.DuplicateRatio() >= config.DedupThreshold {
+		p.Recommendations = append(p.Recommendations, RecommendDedup)
+	}
+	if p.CompressionRatio() <= config.CompressionThreshold {
+		p.Recommendations = append(p.Recommendations, RecommendCompression)
+	}
+	return p
+}
+
+// topLevelPrefix returns the part of key before its first separator.
+func topLevelPrefix(key, separators string) string {
+	if i := strings.IndexAny(key, separators); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// hashKey maps key uniformly onto uint64 for sampling.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// compressor measures compressed sizes, reusing one flate writer.
+type compressor struct {
+	buf    bytes.Buffer
+	writer *flate.Writer
+}
+
+func newCompressor() *compressor {
+	c := &compressor{}
+	c.writer, _ = flate.NewWriter(&c.buf, flate.DefaultCompression) // the level is valid
+	return c
+}
+
+// size returns the compressed size of value.
+func (c *compressor) size(value []byte) uint64 {
+	c.buf.Reset()
+	c.writer.Reset(&c.buf)
+	_, _ = c.writer.Write(value) // writes to a bytes.Buffer do not fail
+	_ = c.writer.Close()
+	return uint64(c.buf.Len())
+}
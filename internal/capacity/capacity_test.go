@@ -0,0 +1,107 @@
+package capacity
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestAnalyze(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	put := func(key string, value []byte) {
+		t.Helper()
+		if err := s.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Half of the avatars are copies of the same image
+	shared := []byte(rand.Text() + rand.Text())
+	for i := range 40 {
+		if i%2 == 0 {
+			put(fmt.Sprintf("avatars/%d", i), shared)
+		} else {
+			put(fmt.Sprintf("avatars/%d", i), []byte(rand.Text()+rand.Text()))
+		}
+	}
+	// Repetitive JSON documents compress well
+	for i := range 40 {
+		put(fmt.Sprintf("docs:%d", i), []byte(fmt.Sprintf(`{"id":%d,"body":%q}`, i, strings.Repeat("lorem ipsum ", 20))))
+	}
+	// Too few samples for a recommendation
+	put("misc", []byte(strings.Repeat("a", 100)))
+
+	report, err := Analyze(context.Background(), s, Config{SampleRate: 1})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.ScannedKeys != 81 || report.SampledKeys != 81 {
+		t.Errorf("Expected 81 keys scanned and sampled, got %d and %d", report.ScannedKeys, report.SampledKeys)
+	}
+
+	byPrefix := make(map[string]PrefixReport)
+	for _, p := range report.Prefixes {
+		byPrefix[p.Prefix] = p
+	}
+	avatars, docs, misc := byPrefix["avatars"], byPrefix["docs"], byPrefix["misc"]
+	if avatars.DuplicateValues != 19 {
+		t.Errorf("Expected 19 duplicate avatars, got %d", avatars.DuplicateValues)
+	}
+	if !reflect.DeepEqual(avatars.Recommendations, []Recommendation{RecommendDedup}) {
+		t.Errorf("Expected dedup to be recommended for avatars, got %v", avatars.Recommendations)
+	}
+	if !reflect.DeepEqual(docs.Recommendations, []Recommendation{RecommendCompression}) {
+		t.Errorf("Expected compression to be recommended for docs (ratio %.2f), got %v", docs.CompressionRatio(), docs.Recommendations)
+	}
+	if misc.SampledValues != 1 || misc.Recommendations != nil {
+		t.Errorf("Expected no recommendation for a single sample, got %+v", misc)
+	}
+	if report.Prefixes[0].SampledBytes < report.Prefixes[len(report.Prefixes)-1].SampledBytes {
+		t.Error("Expected prefixes sorted by sampled bytes")
+	}
+
+	t.Run("Sampling", func(t *testing.T) {
+		first, err := Analyze(context.Background(), s, Config{SampleRate: 0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := Analyze(context.Background(), s, Config{SampleRate: 0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.SampledKeys == 0 || first.SampledKeys >= first.ScannedKeys {
+			t.Errorf("Expected a partial sample, got %d of %d", first.SampledKeys, first.ScannedKeys)
+		}
+		if first.SampledKeys != second.SampledKeys {
+			t.Error("Expected sampling by key hash to be repeatable")
+		}
+
+		capped, err := Analyze(context.Background(), s, Config{SampleRate: 1, MaxSamples: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range capped.Prefixes {
+			if p.SampledValues > 5 {
+				t.Errorf("Expected at most 5 samples for %s, got %d", p.Prefix, p.SampledValues)
+			}
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := Analyze(ctx, s, Config{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Package confirm implements two-phase confirmation of destructive
+// operations: a first request is answered with a short-lived token, and the
+// operation only runs when it is requested again with that token.
+package confirm
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a confirmation token stays valid.
+const DefaultTTL = time.Minute
+
+var (
+	// ErrInvalidToken is returned for tokens that were never issued, were
+	// already used or were issued for a different action.
+	ErrInvalidToken = errors.New("invalid confirmation token")
+	// ErrExpiredToken is returned for tokens redeemed after their deadline.
+	ErrExpiredToken = errors.New("confirmation token expired")
+)
+
+// pending is an issued token that has not been redeemed yet.
+type pending struct {
+	action  string
+	expires time.Time
+}
+
+// Issuer hands out and redeems confirmation tokens. Tokens are kept in
+// memory, so they do not survive a restart.
+type Issuer struct {
+	ttl    time.Duration
+	now    func() time.Time
+	mu     sync.Mutex
+	tokens map[string]pending
+}
+
+// NewIssuer creates an issuer whose tokens expire after ttl, or DefaultTTL if
+// ttl is not positive.
+func NewIssuer(ttl time.Duration) *Issuer {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Issuer{ttl: ttl, now: time.Now, tokens: make(map[string]pending)}
+}
+
+// Issue returns a token confirming action, which must describe the operation
+// and all of its parameters, and the time the token expires.
+func (i *Issuer) Issue(action string) (token string, expires time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := i.now()
+	for t, p := range i.tokens {
+		if now.After(p.expires) {
+			delete(i.tokens, t)
+		}
+	}
+
+	token = rand.Text()
+	expires = now.Add(i.ttl)
+	i.tokens[token] = pending{action: action, expires: expires}
+	return token, expires
+}
+
+// Redeem consumes token if it was issued for action and has not expired.
+// A token can be redeemed once; a mismatched action does not consume it.
+func (i *Issuer) Redeem(token, action string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	p, ok := i.tokens[token]
+	if !ok || p.action != action {
+		return ErrInvalidToken
+	}
+	delete(i.tokens, token)
+	if i.now().After(p.expires) {
+		return ErrExpiredToken
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package confirm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIssuer(t *testing.T) {
+	now := time.Now()
+	issuer := NewIssuer(time.Minute)
+	issuer.now = func() time.Time { return now }
+
+	token, expires := issuer.Issue("drop-prefix:a/")
+	if !expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected token to expire after the TTL, got %v", expires)
+	}
+
+	if err := issuer.Redeem(token, "drop-prefix:b/"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for another action, got %v", err)
+	}
+	if err := issuer.Redeem(token, "drop-prefix:a/"); err != nil {
+		t.Errorf("Expected token to be redeemed, got %v", err)
+	}
+	if err := issuer.Redeem(token, "drop-prefix:a/"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a used token to be rejected, got %v", err)
+	}
+	if err := issuer.Redeem("unknown", "drop-prefix:a/"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an unknown token to be rejected, got %v", err)
+	}
+
+	token, _ = issuer.Issue("drop-prefix:a/")
+	now = now.Add(2 * time.Minute)
+	if err := issuer.Redeem(token, "drop-prefix:a/"); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("Expected ErrExpiredToken, got %v", err)
+	}
+
+	// Issuing sweeps expired tokens
+	issuer.Issue("x")
+	stale, _ := issuer.Issue("y")
+	now = now.Add(2 * time.Minute)
+	issuer.Issue("z")
+	if _, ok := issuer.tokens[stale]; ok {
+		t.Error("Expected expired tokens to be swept")
+	}
+}
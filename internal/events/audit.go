@@ -0,0 +1,17 @@
+package events
+
+import "log"
+
+// AuditLogger returns a handler that writes one line per event to logger.
+func AuditLogger(logger *log.Logger) Handler {
+	return func(e Event) {
+		switch e.Type {
+		case TypePut:
+			logger.Printf("audit: %s key=%q bytes=%d", e.Type, e.Key, len(e.Value))
+		case TypeDelete, TypeExpire:
+			logger.Printf("audit: %s key=%q", e.Type, e.Key)
+		default:
+			logger.Printf("audit: %s key=%q attributes=%v", e.Type, e.Key, e.Attributes)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package events provides an in-process publish/subscribe bus through which
+// store mutations, lifecycle events and incidents flow to interested
+// subsystems without those subsystems knowing about each other.
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+const (
+	// Store mutations
+	TypePut    Type = "put"
+	TypeDelete Type = "delete"
+	TypeExpire Type = "expire" // A key removed by expiration rather than deleted
+
+	// Server lifecycle
+	TypeServerStarted  Type = "server_started"
+	TypeServerStopping Type = "server_stopping"
+
+	// Incidents, published for every write rejected by the quota and
+	// validated store wrappers given the bus
+	TypeQuotaExceeded    Type = "quota_exceeded"
+	TypeValidationFailed Type = "validation_failed"
+)
+
+// Event is a single notification published on the bus.
+type Event struct {
+	Type       Type
+	Key        string
+	Value      []byte
+	Time       time.Time
+	Attributes map[string]string
+}
+
+// Handler consumes events delivered to a subscription.
+type Handler func(Event)
+
+// DefaultBufferSize is the number of events buffered per subscriber.
+const DefaultBufferSize = 256
+
+type subscription struct {
+	types   map[Type]bool
+	ch      chan Event
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+func (s *subscription) wants(t Type) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// Bus fans published events out to subscribers. Each subscriber receives
+// events in publish order on its own goroutine; a subscriber that falls behind
+// loses events instead of stalling publishers.
+type Bus struct {
+	mu         sync.RWMutex
+	subs       map[uint64]*subscription
+	nextID     uint64
+	bufferSize int
+	closed     bool
+}
+
+// NewBus creates a bus whose subscribers buffer up to bufferSize events.
+// A non-positive size selects DefaultBufferSize.
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		subs:       make(map[uint64]*subscription),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscribe registers handler for the given event types, or for every event
+// when no types are given. The returned function cancels the subscription and
+// waits for the handler to finish processing buffered events.
+func (b *Bus) Subscribe(handler Handler, types ...Type) (unsubscribe func()) {
+	sub := &subscription{
+		types: make(map[Type]bool, len(types)),
+		ch:    make(chan Event, b.bufferSize),
+		done:  make(chan struct{}),
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	go func() {
+		defer close(sub.done)
+		for event := range sub.ch {
+			handler(event)
+		}
+	}()
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.ch)
+		<-sub.done
+		return func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			if _, ok := b.subs[id]; ok {
+				delete(b.subs, id)
+				close(sub.ch)
+			}
+			b.mu.Unlock()
+			<-sub.done
+		})
+	}
+}
+
+// Publish delivers event to every matching subscriber without blocking.
+// A zero Time is replaced with the current time.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subs {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns the number of events discarded because a subscriber's
+// buffer was full.
+func (b *Bus) Dropped() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var total uint64
+	for _, sub := range b.subs {
+		total += sub.dropped.Load()
+	}
+	return total
+}
+
+// Close cancels every subscription and waits for handlers to drain.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[uint64]*subscription)
+	for _, sub := range subs {
+		close(sub.ch)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		<-sub.done
+	}
+}
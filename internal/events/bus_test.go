@@ -0,0 +1,180 @@
+package events
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestBus_Subscribe(t *testing.T) {
+	t.Run("DeliversInOrder", func(t *testing.T) {
+		bus := NewBus(0)
+		defer bus.Close()
+
+		var mu sync.Mutex
+		var got []string
+		unsubscribe := bus.Subscribe(func(e Event) {
+			mu.Lock()
+			got = append(got, e.Key)
+			mu.Unlock()
+		})
+
+		for _, key := range []string{"a", "b", "c"} {
+			bus.Publish(Event{Type: TypePut, Key: key})
+		}
+		unsubscribe()
+
+		if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+			t.Errorf("Expected [a b c], got %v", got)
+		}
+	})
+
+	t.Run("FiltersByType", func(t *testing.T) {
+		bus := NewBus(0)
+		defer bus.Close()
+
+		var got []Type
+		unsubscribe := bus.Subscribe(func(e Event) { got = append(got, e.Type) }, TypeDelete)
+
+		bus.Publish(Event{Type: TypePut, Key: "a"})
+		bus.Publish(Event{Type: TypeDelete, Key: "a"})
+		unsubscribe()
+
+		if len(got) != 1 || got[0] != TypeDelete {
+			t.Errorf("Expected only delete events, got %v", got)
+		}
+	})
+
+	t.Run("SlowSubscriberDropsInsteadOfBlocking", func(t *testing.T) {
+		bus := NewBus(1)
+		defer bus.Close()
+
+		release := make(chan struct{})
+		unsubscribe := bus.Subscribe(func(Event) { <-release })
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 10; i++ {
+				bus.Publish(Event{Type: TypePut})
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Publish blocked on a slow subscriber")
+		}
+		if bus.Dropped() == 0 {
+			t.Error("Expected dropped events to be counted")
+		}
+		close(release)
+		unsubscribe()
+	})
+
+	t.Run("SubscribeAfterClose", func(t *testing.T) {
+		bus := NewBus(0)
+		bus.Close()
+		unsubscribe := bus.Subscribe(func(Event) { t.Error("Unexpected delivery after close") })
+		bus.Publish(Event{Type: TypePut})
+		unsubscribe()
+	})
+}
+
+func TestPublishingStore(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := NewBus(0)
+	s := NewPublishingStore(base, bus)
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	var got []Event
+	unsubscribe := bus.Subscribe(func(e Event) { got = append(got, e) })
+
+	if err := s.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("", []byte("value")); err == nil {
+		t.Fatal("Expected error for empty key")
+	}
+	unsubscribe()
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(got))
+	}
+	if got[0].Type != TypePut || got[0].Key != "key" || string(got[0].Value) != "value" {
+		t.Errorf("Unexpected put event: %+v", got[0])
+	}
+	if got[1].Type != TypeDelete || got[1].Key != "key" {
+		t.Errorf("Unexpected delete event: %+v", got[1])
+	}
+}
+
+func TestPublishingStore_Batch(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := NewBus(0)
+	s := NewPublishingStore(base, bus)
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	counts := make(map[Type]int)
+	unsubscribe := bus.Subscribe(func(e Event) { counts[e.Type]++ })
+
+	if err := s.BatchPut(map[string][]byte{"a": []byte("1"), "b": []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchDelete([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("c", []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExpireKeys([]string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"": []byte("1")}); err == nil {
+		t.Fatal("Expected error for empty key")
+	}
+	unsubscribe()
+
+	if counts[TypePut] != 3 || counts[TypeDelete] != 2 || counts[TypeExpire] != 1 {
+		t.Errorf("Expected one event per mutation, got %v", counts)
+	}
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	registry := metrics.NewRegistry()
+	record := MetricsRecorder(registry)
+
+	record(Event{Type: TypePut, Key: "a", Value: []byte("abc")})
+	record(Event{Type: TypePut, Key: "b", Value: []byte("de")})
+	record(Event{Type: TypeDelete, Key: "a"})
+	record(Event{Type: TypeExpire, Key: "b"})
+	record(Event{Type: TypeValidationFailed, Key: "c"})
+	record(Event{Type: TypeServerStarted})
+
+	s := registry.Snapshot()
+	want := map[string]uint64{MetricStorePuts: 2, MetricStoreDeletes: 1, MetricStoreExpirations: 1, MetricStoreBytesWritten: 5, MetricIncidents: 1}
+	if !reflect.DeepEqual(s.Counters, want) {
+		t.Errorf("Counters = %v, want %v", s.Counters, want)
+	}
+}
@@ -0,0 +1,36 @@
+package events
+
+import "github.com/William-Fernandes252/clavis/internal/metrics"
+
+// Metric names recorded by MetricsRecorder.
+const (
+	MetricStorePuts         = "store_puts_total"
+	MetricStoreDeletes      = "store_deletes_total"
+	MetricStoreExpirations  = "store_expirations_total"
+	MetricStoreBytesWritten = "store_bytes_written_total"
+	MetricIncidents         = "incidents_total"
+)
+
+// MetricsRecorder returns a handler that counts store mutations and
+// incidents in registry.
+func MetricsRecorder(registry *metrics.Registry) Handler {
+	puts := registry.Counter(MetricStorePuts)
+	deletes := registry.Counter(MetricStoreDeletes)
+	expirations := registry.Counter(MetricStoreExpirations)
+	bytesWritten := registry.Counter(MetricStoreBytesWritten)
+	incidents := registry.Counter(MetricIncidents)
+
+	return func(e Event) {
+		switch e.Type {
+		case TypePut:
+			puts.Inc()
+			bytesWritten.Add(uint64(len(e.Value)))
+		case TypeDelete:
+			deletes.Inc()
+		case TypeExpire:
+			expirations.Inc()
+		case TypeQuotaExceeded, TypeValidationFailed:
+			incidents.Inc()
+		}
+	}
+}
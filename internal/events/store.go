@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// PublishingStore wraps a store and publishes an event for every successful
+// mutation.
+type PublishingStore struct {
+	store.Passthrough
+	bus *Bus
+}
+
+// NewPublishingStore wraps base so that Put and Delete are published on bus.
+func NewPublishingStore(base store.Store, bus *Bus) *PublishingStore {
+	return &PublishingStore{Passthrough: store.Passthrough{Store: base}, bus: bus}
+}
+
+// Put stores the value and publishes a TypePut event on success.
+func (ps *PublishingStore) Put(key string, value []byte) error {
+	return ps.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (ps *PublishingStore) PutContext(ctx context.Context, key string, value []byte) error {
+	if err := store.PutContext(ctx, ps.Store, key, value); err != nil {
+		return err
+	}
+	ps.publishPut(key, value)
+	return nil
+}
+
+// PutIfVersion conditionally stores the value and publishes a TypePut event on
+// success.
+func (ps *PublishingStore) PutIfVersion(key string, value []byte, expected uint64) error {
+	return ps.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (ps *PublishingStore) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	if err := store.PutIfVersionContext(ctx, ps.Store, key, value, expected); err != nil {
+		return err
+	}
+	ps.publishPut(key, value)
+	return nil
+}
+
+// Delete removes the key and publishes a TypeDelete event on success.
+func (ps *PublishingStore) Delete(key string) error {
+	return ps.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (ps *PublishingStore) DeleteContext(ctx context.Context, key string) error {
+	if err := store.DeleteContext(ctx, ps.Store, key); err != nil {
+		return err
+	}
+	ps.bus.Publish(Event{Type: TypeDelete, Key: key})
+	return nil
+}
+
+// publishPut publishes a TypePut event with a copy of value.
+func (ps *PublishingStore) publishPut(key string, value []byte) {
+	published := make([]byte, len(value))
+	copy(published, value)
+	ps.bus.Publish(Event{Type: TypePut, Key: key, Value: published})
+}
+
+var (
+	_ store.Store             = (*PublishingStore)(nil)
+	_ store.Wrapper           = (*PublishingStore)(nil)
+	_ store.Batcher           = (*PublishingStore)(nil)
+	_ store.ConditionalPutter = (*PublishingStore)(nil)
+	_ store.ContextWriter     = (*PublishingStore)(nil)
+	_ store.ContextReader     = (*PublishingStore)(nil)
+	_ store.Expirer           = (*PublishingStore)(nil)
+)
+
+// BatchPut stores the pairs and publishes a TypePut event for each of them on
+// success.
+func (ps *PublishingStore) BatchPut(pairs map[string][]byte) error {
+	return ps.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (ps *PublishingStore) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	if err := store.BatchPutContext(ctx, ps.Store, pairs); err != nil {
+		return err
+	}
+	for key, value := range pairs {
+		ps.publishPut(key, value)
+	}
+	return nil
+}
+
+// ExpireKeys removes the keys as expired and publishes a TypeExpire event for
+// each of them on success.
+func (ps *PublishingStore) ExpireKeys(keys []string) error {
+	if err := store.ExpireKeys(ps.Store, keys); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		ps.bus.Publish(Event{Type: TypeExpire, Key: key})
+	}
+	return nil
+}
+
+// BatchDelete removes the keys and publishes a TypeDelete event for each of
+// them on success.
+func (ps *PublishingStore) BatchDelete(keys []string) error {
+	if err := store.BatchDelete(ps.Store, keys); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		ps.bus.Publish(Event{Type: TypeDelete, Key: key})
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Package export writes key-value pairs to JSON lines or CSV files and reads
+// them back, so data can be moved between Clavis servers or inspected with
+// ordinary tools. Values are base64-encoded in both formats, so binary values
+// survive the round trip.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+// Format is the encoding of an export file.
+type Format string
+
+const (
+	// FormatJSONL is one {"key": ..., "value": ...} object per line, the
+	// value base64-encoded.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV is a key,value header followed by one row per pair, the
+	// value base64-encoded.
+	FormatCSV Format = "csv"
+)
+
+// DefaultBatchSize is how many pairs Import writes per request by default.
+const DefaultBatchSize = 500
+
+// csvHeader is the first row of CSV exports.
+var csvHeader = []string{"key", "value"}
+
+// ErrUnknownFormat is returned for formats other than FormatJSONL and
+// FormatCSV.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatJSONL, FormatCSV:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w %q: must be %q or %q", ErrUnknownFormat, s, FormatJSONL, FormatCSV)
+}
+
+// record is a pair as written to JSON lines. []byte fields are encoded as
+// base64 by encoding/json.
+type record struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Writer encodes pairs to an export file. Flush must be called once every
+// pair was written.
+type Writer struct {
+	format Format
+	buf    *bufio.Writer
+	json   *json.Encoder
+	csv    *csv.Writer
+}
+
+// NewWriter returns a Writer encoding pairs to w in the given format.
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	ew := &Writer{format: format}
+	switch format {
+	case FormatJSONL:
+		ew.buf = bufio.NewWriter(w)
+		ew.json = json.NewEncoder(ew.buf)
+	case FormatCSV:
+		ew.csv = csv.NewWriter(w)
+		if err := ew.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	return ew, nil
+}
+
+// Write encodes a pair.
+func (w *Writer) Write(key string, value []byte) error {
+	if w.format == FormatCSV {
+		return w.csv.Write([]string{key, base64.StdEncoding.EncodeToString(value)})
+	}
+	return w.json.Encode(record{Key: key, Value: value})
+}
+
+// Flush writes any buffered pairs to the underlying writer.
+func (w *Writer) Flush() error {
+	if w.format == FormatCSV {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.buf.Flush()
+}
+
+// Reader decodes pairs from an export file.
+type Reader struct {
+	format Format
+	json   *json.Decoder
+	csv    *csv.Reader
+	read   int // Number of JSON records read
+}
+
+// NewReader returns a Reader decoding pairs in the given format from r. CSV
+// input must start with the key,value header.
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	er := &Reader{format: format}
+	switch format {
+	case FormatJSONL:
+		er.json = json.NewDecoder(r)
+		er.json.DisallowUnknownFields()
+	case FormatCSV:
+		er.csv = csv.NewReader(r)
+		er.csv.FieldsPerRecord = len(csvHeader)
+		header, err := er.csv.Read()
+		if errors.Is(err, io.EOF) {
+			return er, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV export: %w", err)
+		}
+		if header[0] != csvHeader[0] || header[1] != csvHeader[1] {
+			return nil, fmt.Errorf("invalid CSV export: expected a %q header, got %q", csvHeader, header)
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	return er, nil
+}
+
+// Read decodes the next pair. It returns io.EOF once every pair was read.
+func (r *Reader) Read() (key string, value []byte, err error) {
+	if r.format == FormatCSV {
+		row, err := r.csv.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", nil, io.EOF
+			}
+			return "", nil, fmt.Errorf("invalid CSV export: %w", err)
+		}
+		value, err := base64.StdEncoding.DecodeString(row[1])
+		if err != nil {
+			line, _ := r.csv.FieldPos(1)
+			return "", nil, fmt.Errorf("invalid CSV export: line %d: value is not base64: %w", line, err)
+		}
+		return row[0], value, nil
+	}
+
+	var rec record
+	if err := r.json.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", nil, io.EOF
+		}
+		return "", nil, fmt.Errorf("invalid JSON lines export: record %d: %w", r.read+1, err)
+	}
+	r.read++
+	return rec.Key, rec.Value, nil
+}
+
+// Import writes every pair read from r to the server in batches of
+// batchSize, DefaultBatchSize if it is not positive, and returns how many
+// were written. Keys are written to namespace, empty for the server's
+// default. A failed batch stops the import; the batches before it stay
+// written.
+func Import(ctx context.Context, c proto.ClavisClient, r *Reader, namespace string, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	written := 0
+	batch := make([]*proto.KeyValue, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := c.BatchPut(ctx, &proto.BatchPutRequest{Items: batch, Namespace: namespace}); err != nil {
+			return fmt.Errorf("failed to write the batch starting at %q: %w", batch[0].Key, err)
+		}
+		written += len(batch)
+		batch = make([]*proto.KeyValue, 0, batchSize)
+		return nil
+	}
+
+	for {
+		key, value, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return written, err
+		}
+		if key == "" {
+			return written, errors.New("invalid export: a pair has an empty key")
+		}
+		batch = append(batch, &proto.KeyValue{Key: key, Value: value})
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, flush()
+}
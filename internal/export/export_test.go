@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// batchClient is a ClavisClient recording the batches written to it.
+type batchClient struct {
+	proto.ClavisClient
+	batches []*proto.BatchPutRequest
+}
+
+func (c *batchClient) BatchPut(ctx context.Context, req *proto.BatchPutRequest, opts ...grpc.CallOption) (*proto.BatchPutResponse, error) {
+	c.batches = append(c.batches, req)
+	return &proto.BatchPutResponse{}, nil
+}
+
+var pairs = []*proto.KeyValue{
+	{Key: "config/name", Value: []byte("clavis")},
+	{Key: "config/with,comma", Value: []byte("line one\nline two")},
+	{Key: "blob", Value: []byte{0x00, 0xff, 0x10}},
+	{Key: "empty", Value: []byte{}},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pair := range pairs {
+				if err := w.Write(pair.Key, pair.Value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := &batchClient{}
+			count, err := Import(context.Background(), client, r, "tenant", 3)
+			if err != nil || count != len(pairs) {
+				t.Fatalf("Import() = %d, %v, want %d pairs", count, err, len(pairs))
+			}
+			if len(client.batches) != 2 || client.batches[0].Namespace != "tenant" {
+				t.Fatalf("Expected 2 batches into the namespace, got %v", client.batches)
+			}
+			imported := append(client.batches[0].Items, client.batches[1].Items...)
+			for i, pair := range pairs {
+				if imported[i].Key != pair.Key || !bytes.Equal(imported[i].Value, pair.Value) {
+					t.Errorf("Pair %d: got %q=%q, want %q=%q", i, imported[i].Key, imported[i].Value, pair.Key, pair.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestReadsExistingJSONLinesExports(t *testing.T) {
+	// Exports from before the format option encoded proto.KeyValue, which
+	// omits empty values
+	r, err := NewReader(strings.NewReader(`{"key":"a","value":"dmFsdWU="}`+"\n"+`{"key":"b"}`+"\n"), FormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"a=value", "b="} {
+		key, value, err := r.Read()
+		if err != nil || key+"="+string(value) != want {
+			t.Errorf("Read() = %q, %q, %v, want %s", key, value, err, want)
+		}
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{"invalid JSON", FormatJSONL, `{"key": "a"` + "\n"},
+		{"unknown JSON field", FormatJSONL, `{"key": "a", "val": "YQ=="}` + "\n"},
+		{"value not base64", FormatCSV, "key,value\na,not base64!\n"},
+		{"missing CSV header", FormatCSV, "a,YQ==\n"},
+		{"wrong CSV column count", FormatCSV, "key,value\na,YQ==,extra\n"},
+		{"empty key", FormatJSONL, `{"key": "", "value": "YQ=="}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(tt.input), tt.format)
+			if err == nil {
+				_, err = Import(context.Background(), &batchClient{}, r, "", 0)
+			}
+			if err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+// Package fixtures describes, in JSON, the data a Clavis environment is
+// expected to hold and how its validation should treat given writes. A
+// fixture can seed a server and then be verified against it, so deployment
+// pipelines can assert that an environment matches its contract.
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Outcome is how validation is expected to treat a write.
+type Outcome string
+
+const (
+	OutcomeAccepted Outcome = "accepted"
+	OutcomeRejected Outcome = "rejected"
+)
+
+// Fixture is the expected state of an environment.
+type Fixture struct {
+	Keys   []Key    `json:"keys"`   // Pairs seeded by Apply and expected by Verify
+	Absent []string `json:"absent"` // Keys expected not to exist
+	Writes []Write  `json:"writes"` // Writes whose validation outcome Verify checks without storing them
+}
+
+// Value is the value of a key, given either as a string or as a JSON
+// document. JSON documents are compared semantically, so formatting and
+// object key order do not matter.
+type Value struct {
+	Value *string         `json:"value,omitempty"`
+	JSON  json.RawMessage `json:"json,omitempty"`
+}
+
+// Key is a pair the environment holds.
+type Key struct {
+	Key string `json:"key"`
+	Value
+}
+
+// Write is a write that validation should accept or reject. When Message is
+// set, a rejection must mention it.
+type Write struct {
+	Key string `json:"key"`
+	Value
+	Expect  Outcome `json:"expect"`
+	Message string  `json:"message,omitempty"`
+}
+
+// Load reads a fixture file.
+func Load(path string) (*Fixture, error) {
+	file, err := os.Open(path) // #nosec G304 -- the path is chosen by the operator
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	fixture, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// Parse decodes and checks a fixture. Unknown fields are rejected, so typos
+// do not silently weaken a contract.
+func Parse(r io.Reader) (*Fixture, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var fixture Fixture
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	if err := fixture.validate(); err != nil {
+		return nil, err
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) validate() error {
+	for i, key := range f.Keys {
+		if key.Key == "" {
+			return fmt.Errorf("keys[%d]: key is required", i)
+		}
+		if err := key.Value.validate(); err != nil {
+			return fmt.Errorf("keys[%d] (%s): %w", i, key.Key, err)
+		}
+	}
+	for i, key := range f.Absent {
+		if key == "" {
+			return fmt.Errorf("absent[%d]: key is required", i)
+		}
+	}
+	for i, write := range f.Writes {
+		if err := write.Value.validate(); err != nil {
+			return fmt.Errorf("writes[%d] (%s): %w", i, write.Key, err)
+		}
+		if write.Expect != OutcomeAccepted && write.Expect != OutcomeRejected {
+			return fmt.Errorf("writes[%d] (%s): expect must be %q or %q", i, write.Key, OutcomeAccepted, OutcomeRejected)
+		}
+	}
+	return nil
+}
+
+func (v Value) validate() error {
+	if (v.Value == nil) == (v.JSON == nil) {
+		return errors.New("exactly one of value and json is required")
+	}
+	if v.JSON != nil && !json.Valid(v.JSON) {
+		return errors.New("json is not a valid JSON document")
+	}
+	return nil
+}
+
+// bytes returns the value as stored, with JSON documents compacted.
+func (v Value) bytes() []byte {
+	if v.Value != nil {
+		return []byte(*v.Value)
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, v.JSON); err != nil {
+		return v.JSON
+	}
+	return compacted.Bytes()
+}
+
+// matches reports whether stored equals the value.
+func (v Value) matches(stored []byte) bool {
+	if v.Value != nil {
+		return string(stored) == *v.Value
+	}
+	var want, got any
+	if json.Unmarshal(v.JSON, &want) != nil || json.Unmarshal(stored, &got) != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// Apply writes the keys of the fixture to the server and returns how many
+// were written. Absent keys and writes are only checked by Verify.
+func (f *Fixture) Apply(ctx context.Context, c proto.ClavisClient) (int, error) {
+	for i, key := range f.Keys {
+		if _, err := c.Put(ctx, &proto.PutRequest{Key: key.Key, Value: key.bytes()}); err != nil {
+			return i, fmt.Errorf("failed to write %q: %w", key.Key, err)
+		}
+	}
+	return len(f.Keys), nil
+}
+
+// Failure is a check that did not hold.
+type Failure struct {
+	Key     string
+	Message string
+}
+
+func (f Failure) String() string {
+	return f.Key + ": " + f.Message
+}
+
+// Report is the result of Verify.
+type Report struct {
+	Checked  int
+	Failures []Failure
+}
+
+// OK reports whether every check held.
+func (r *Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+func (r *Report) fail(key, format string, args ...any) {
+	r.Failures = append(r.Failures, Failure{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// Verify checks the server against the fixture. Failed checks are collected
+// in the report; an error means the checks could not be run.
+//
+// Writes are checked with a Put conditioned on a version no key can be at,
+// so the server validates them but never stores them: a version conflict
+// means validation accepted the write, and INVALID_ARGUMENT that it rejected
+// it. This needs a store supporting conditional writes.
+func (f *Fixture) Verify(ctx context.Context, c proto.ClavisClient) (*Report, error) {
+	report := &Report{}
+	for _, key := range f.Keys {
+		report.Checked++
+		resp, err := c.Get(ctx, &proto.GetRequest{Key: key.Key})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", key.Key, err)
+		}
+		switch {
+		case !resp.Found:
+			report.fail(key.Key, "expected the key to exist")
+		case !key.matches(resp.Value):
+			report.fail(key.Key, "expected %q, got %q", key.bytes(), resp.Value)
+		}
+	}
+
+	for _, key := range f.Absent {
+		report.Checked++
+		resp, err := c.Get(ctx, &proto.GetRequest{Key: key})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", key, err)
+		}
+		if resp.Found {
+			report.fail(key, "expected the key not to exist")
+		}
+	}
+
+	for _, write := range f.Writes {
+		report.Checked++
+		impossible := uint64(math.MaxUint64)
+		_, err := c.Put(ctx, &proto.PutRequest{Key: write.Key, Value: write.bytes(), ExpectedVersion: &impossible})
+		switch code := status.Code(err); {
+		case code == codes.Aborted:
+			if write.Expect == OutcomeRejected {
+				report.fail(write.Key, "expected the write to be rejected, but validation accepted it")
+			}
+		case code == codes.InvalidArgument:
+			message := status.Convert(err).Message()
+			if write.Expect == OutcomeAccepted {
+				report.fail(write.Key, "expected the write to be accepted, but it was rejected: %s", message)
+			} else if write.Message != "" && !strings.Contains(message, write.Message) {
+				report.fail(write.Key, "expected the rejection to mention %q, got %q", write.Message, message)
+			}
+		case code == codes.OK:
+			return nil, fmt.Errorf("the server stored the probe write to %q despite its impossible version", write.Key)
+		default:
+			return nil, fmt.Errorf("failed to check the write to %q: %w", write.Key, err)
+		}
+	}
+	return report, nil
+}
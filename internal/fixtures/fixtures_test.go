@@ -0,0 +1,100 @@
+package fixtures
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validatingClient is a ClavisClient over a map whose validation rejects
+// values longer than 20 bytes.
+type validatingClient struct {
+	proto.ClavisClient
+	data map[string][]byte
+}
+
+func (c *validatingClient) Get(ctx context.Context, req *proto.GetRequest, opts ...grpc.CallOption) (*proto.GetResponse, error) {
+	value, found := c.data[req.Key]
+	return &proto.GetResponse{Value: value, Found: found}, nil
+}
+
+func (c *validatingClient) Put(ctx context.Context, req *proto.PutRequest, opts ...grpc.CallOption) (*proto.PutResponse, error) {
+	if len(req.Value) > 20 {
+		return nil, status.Error(codes.InvalidArgument, "value: too long")
+	}
+	if req.ExpectedVersion != nil {
+		return nil, status.Error(codes.Aborted, "version conflict")
+	}
+	c.data[req.Key] = req.Value
+	return &proto.PutResponse{}, nil
+}
+
+const fixture = `{
+  "keys": [
+    {"key": "config/name", "value": "clavis"},
+    {"key": "config/limits", "json": {"max": 3, "min": 1}}
+  ],
+  "absent": ["config/legacy"],
+  "writes": [
+    {"key": "config/name", "value": "short", "expect": "accepted"},
+    {"key": "config/name", "value": "a name far too long to store", "expect": "rejected", "message": "too long"}
+  ]
+}`
+
+func TestFixture_ApplyVerify(t *testing.T) {
+	f, err := Parse(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	c := &validatingClient{data: map[string][]byte{"config/legacy": []byte("x")}}
+	ctx := context.Background()
+
+	if n, err := f.Apply(ctx, c); err != nil || n != 2 {
+		t.Fatalf("Apply wrote %d keys: %v", n, err)
+	}
+	if string(c.data["config/limits"]) != `{"max":3,"min":1}` {
+		t.Errorf("Expected the JSON value to be compacted, got %s", c.data["config/limits"])
+	}
+	if string(c.data["config/name"]) != "clavis" {
+		t.Errorf("Expected the probe writes not to be stored, got %s", c.data["config/name"])
+	}
+
+	report, err := f.Verify(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 5 || len(report.Failures) != 1 || report.Failures[0].Key != "config/legacy" {
+		t.Errorf("Expected only the absent key to fail, got %+v", report)
+	}
+
+	// JSON values match regardless of formatting, but not other values
+	delete(c.data, "config/legacy")
+	c.data["config/limits"] = []byte(`{ "min": 1, "max": 3 }`)
+	if report, _ := f.Verify(ctx, c); !report.OK() {
+		t.Errorf("Expected every check to hold, got %v", report.Failures)
+	}
+	c.data["config/limits"] = []byte(`{"min": 1, "max": 4}`)
+	c.data["config/name"] = []byte("other")
+	if report, _ := f.Verify(ctx, c); len(report.Failures) != 2 {
+		t.Errorf("Expected both changed keys to fail, got %v", report.Failures)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"unknown field":  `{"keys": [{"key": "a", "value": "1", "ttl": 5}]}`,
+		"missing value":  `{"keys": [{"key": "a"}]}`,
+		"both values":    `{"keys": [{"key": "a", "value": "1", "json": 1}]}`,
+		"missing key":    `{"keys": [{"value": "1"}]}`,
+		"unknown expect": `{"writes": [{"key": "a", "value": "1", "expect": "maybe"}]}`,
+	} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
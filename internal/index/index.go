@@ -0,0 +1,518 @@
+// Package index wraps a store with a full-text index of its values, so keys
+// can be searched for by the words their values contain.
+//
+// Values are split into terms by Tokenize on every write, and each term has
+// a posting under TermPrefix pointing to the key with the number of times
+// the term occurs. The terms of each key are recorded under DocPrefix, so the
+// postings of the terms a value loses are deleted when it is overwritten,
+// along with every posting when the key is deleted. The index is updated
+// right after each write to the wrapped store; a failure in between leaves
+// it out of date for that key until it is written again or Reindex rebuilds
+// the whole index.
+//
+// Reads through the wrapper never see the reserved keys. Terms are part of
+// the keys of the postings, which wrappers beneath such as encryption leave
+// readable.
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// KeyPrefix starts the keys the index is kept in.
+	KeyPrefix = "__clavis/index/"
+	// DocPrefix starts the key of the record of the terms of each key.
+	DocPrefix = KeyPrefix + "docs/"
+	// TermPrefix starts the postings, each the term, a NUL byte and the key.
+	TermPrefix = KeyPrefix + "terms/"
+
+	// DefaultMaxTerms is the MaxTerms of a Config that leaves it zero.
+	DefaultMaxTerms = 1000
+
+	// lockStripes is the number of locks writes are spread over by key.
+	lockStripes = 64
+)
+
+var (
+	// ErrReservedKey is returned for writes to the keys under KeyPrefix,
+	// which are hidden from reads.
+	ErrReservedKey = errors.New("key is reserved for the search index")
+	// ErrNotIndexed is returned by From when no store is indexed.
+	ErrNotIndexed = errors.New("values are not indexed")
+	// ErrCorruptIndex is returned when a record or posting of the index
+	// cannot be decoded.
+	ErrCorruptIndex = errors.New("corrupt search index")
+)
+
+// Config configures an Index.
+type Config struct {
+	// MaxTerms bounds the distinct terms indexed per value, the most frequent
+	// kept; DefaultMaxTerms if zero.
+	MaxTerms int
+}
+
+// Index maintains the full-text index of the wrapped store.
+type Index struct {
+	store.Passthrough
+	maxTerms int
+
+	// writeMu is held for reading by writes and for writing by Reindex, so
+	// no value is written while its key is being reindexed.
+	writeMu sync.RWMutex
+	// locks serialize the writes to the same keys, so the record a write
+	// reads is still current when it writes the next one.
+	locks [lockStripes]sync.Mutex
+}
+
+// New wraps base.
+func New(base store.Store, config Config) *Index {
+	if config.MaxTerms == 0 {
+		config.MaxTerms = DefaultMaxTerms
+	}
+	return &Index{Passthrough: store.Passthrough{Store: base}, maxTerms: config.MaxTerms}
+}
+
+var (
+	_ store.Store                 = (*Index)(nil)
+	_ store.Wrapper               = (*Index)(nil)
+	_ store.Batcher               = (*Index)(nil)
+	_ store.Expirer               = (*Index)(nil)
+	_ store.ContextWriter         = (*Index)(nil)
+	_ store.ContextReader         = (*Index)(nil)
+	_ store.ConditionalPutter     = (*Index)(nil)
+	_ store.IteratorProvider      = (*Index)(nil)
+	_ store.RangeIteratorProvider = (*Index)(nil)
+	_ store.ForEacher             = (*Index)(nil)
+	_ store.Viewer                = (*Index)(nil)
+	_ store.VersionReader         = (*Index)(nil)
+	_ store.SnapshotReader        = (*Index)(nil)
+	_ store.FilterEvaluator       = (*Index)(nil)
+)
+
+// From returns the index beneath s, failing with ErrNotIndexed if there is
+// none.
+func From(s store.Store) (*Index, error) {
+	index, ok := store.As[*Index](s)
+	if !ok {
+		return nil, ErrNotIndexed
+	}
+	return index, nil
+}
+
+func reserved(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+func checkKeys(keys ...string) error {
+	for _, key := range keys {
+		if reserved(key) {
+			return fmt.Errorf("%w: %q", ErrReservedKey, key)
+		}
+	}
+	return nil
+}
+
+func docKey(key string) string {
+	return DocPrefix + key
+}
+
+func termKey(term, key string) string {
+	return TermPrefix + term + "\x00" + key
+}
+
+// splitPosting returns the term and key of the posting stored at entry.
+func splitPosting(entry string) (term, key string, err error) {
+	term, key, ok := strings.Cut(strings.TrimPrefix(entry, TermPrefix), "\x00")
+	if !ok {
+		return "", "", fmt.Errorf("%w: posting %q", ErrCorruptIndex, entry)
+	}
+	return term, key, nil
+}
+
+func encodeTerms(terms []string) []byte {
+	stored := binary.AppendUvarint(nil, uint64(len(terms)))
+	for _, term := range terms {
+		stored = binary.AppendUvarint(stored, uint64(len(term)))
+		stored = append(stored, term...)
+	}
+	return stored
+}
+
+func decodeTerms(key string, stored []byte) ([]string, error) {
+	corrupt := fmt.Errorf("%w: record of key %q", ErrCorruptIndex, key)
+	r := bytes.NewReader(stored)
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(len(stored)) {
+		return nil, corrupt
+	}
+	terms := make([]string, 0, n)
+	for range n {
+		length, err := binary.ReadUvarint(r)
+		if err != nil || length > uint64(r.Len()) {
+			return nil, corrupt
+		}
+		term := make([]byte, length)
+		_, _ = r.Read(term)
+		terms = append(terms, string(term))
+	}
+	return terms, nil
+}
+
+// lock holds off Reindex and locks the stripes of keys, returning the
+// function unlocking them.
+func (s *Index) lock(keys ...string) (unlock func()) {
+	stripes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		stripes = append(stripes, int(h.Sum32()%lockStripes))
+	}
+	slices.Sort(stripes)
+	stripes = slices.Compact(stripes)
+	s.writeMu.RLock()
+	for _, stripe := range stripes {
+		s.locks[stripe].Lock()
+	}
+	return func() {
+		for _, stripe := range stripes {
+			s.locks[stripe].Unlock()
+		}
+		s.writeMu.RUnlock()
+	}
+}
+
+// records reads the indexed terms of keys, which are absent for keys with
+// none.
+func (s *Index) records(keys []string) (map[string][]string, error) {
+	docKeys := make([]string, len(keys))
+	for i, key := range keys {
+		docKeys[i] = docKey(key)
+	}
+	stored, err := store.BatchGet(s.Store, docKeys)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string][]string, len(stored))
+	for _, key := range keys {
+		if value, ok := stored[docKey(key)]; ok {
+			if records[key], err = decodeTerms(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, nil
+}
+
+// update replaces the postings of the keys of docs with those of their
+// terms. The postings of lost terms are deleted first, so that if writing
+// the others fails the record still lists every posting left. The stripes of
+// the keys must be locked.
+func (s *Index) update(docs map[string]map[string]int) error {
+	records, err := s.records(slices.Collect(maps.Keys(docs)))
+	if err != nil {
+		return err
+	}
+	var deletes []string
+	puts := make(map[string][]byte)
+	for key, terms := range docs {
+		for _, term := range records[key] {
+			if _, ok := terms[term]; !ok {
+				deletes = append(deletes, termKey(term, key))
+			}
+		}
+		if len(terms) == 0 {
+			if _, ok := records[key]; ok {
+				deletes = append(deletes, docKey(key))
+			}
+			continue
+		}
+		for term, n := range terms {
+			puts[termKey(term, key)] = binary.AppendUvarint(nil, uint64(n)) // #nosec G115 -- counts are positive
+		}
+		puts[docKey(key)] = encodeTerms(slices.Sorted(maps.Keys(terms)))
+	}
+	if len(deletes) > 0 {
+		if err := store.BatchDelete(s.Store, deletes); err != nil {
+			return err
+		}
+	}
+	if len(puts) == 0 {
+		return nil
+	}
+	return store.BatchPut(s.Store, puts)
+}
+
+// index updates the postings of pairs after they were written.
+func (s *Index) index(pairs map[string][]byte) error {
+	docs := make(map[string]map[string]int, len(pairs))
+	for key, value := range pairs {
+		docs[key] = Tokenize(value, s.maxTerms)
+	}
+	return s.update(docs)
+}
+
+// unindex deletes the records and postings of keys after they were removed.
+// The stripes of the keys must be locked.
+func (s *Index) unindex(keys []string) error {
+	records, err := s.records(keys)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	var deletes []string
+	for key, terms := range records {
+		deletes = append(deletes, docKey(key))
+		for _, term := range terms {
+			deletes = append(deletes, termKey(term, key))
+		}
+	}
+	return store.BatchDelete(s.Store, deletes)
+}
+
+// Put stores and indexes the value.
+func (s *Index) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Index) PutContext(ctx context.Context, key string, value []byte) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	if err := store.PutContext(ctx, s.Store, key, value); err != nil {
+		return err
+	}
+	return s.index(map[string][]byte{key: value})
+}
+
+// PutIfVersion conditionally stores and indexes the value.
+func (s *Index) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Index) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	if err := store.PutIfVersionContext(ctx, s.Store, key, value, expected); err != nil {
+		return err
+	}
+	return s.index(map[string][]byte{key: value})
+}
+
+// BatchPut stores and indexes the pairs.
+func (s *Index) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Index) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	keys := slices.Collect(maps.Keys(pairs))
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	if err := store.BatchPutContext(ctx, s.Store, pairs); err != nil {
+		return err
+	}
+	return s.index(pairs)
+}
+
+// Delete removes the key and its postings.
+func (s *Index) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Index) DeleteContext(ctx context.Context, key string) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	if err := store.DeleteContext(ctx, s.Store, key); err != nil {
+		return err
+	}
+	return s.unindex([]string{key})
+}
+
+// BatchDelete removes the keys and their postings.
+func (s *Index) BatchDelete(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	if err := store.BatchDelete(s.Store, keys); err != nil {
+		return err
+	}
+	return s.unindex(keys)
+}
+
+// ExpireKeys removes the keys as expired, and their postings.
+func (s *Index) ExpireKeys(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	if err := store.ExpireKeys(s.Store, keys); err != nil {
+		return err
+	}
+	return s.unindex(keys)
+}
+
+// Get reads the value of key, unless it is reserved.
+func (s *Index) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Index) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	if reserved(key) {
+		return nil, false, nil
+	}
+	return store.GetContext(ctx, s.Store, key)
+}
+
+// GetView calls fn with the value of key, unless it is reserved.
+func (s *Index) GetView(key string, fn func(value []byte) error) (bool, error) {
+	if reserved(key) {
+		return false, nil
+	}
+	return store.GetView(s.Store, key, fn)
+}
+
+// Scan reads the pairs whose keys start with prefix, except the reserved
+// ones.
+func (s *Index) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Index) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return hideReserved(pairs), nil
+}
+
+// BatchGet reads the values of keys, except the reserved ones.
+func (s *Index) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return hideReserved(pairs), nil
+}
+
+// hideReserved drops the reserved keys from pairs, in place.
+func hideReserved(pairs map[string][]byte) map[string][]byte {
+	for key := range pairs {
+		if reserved(key) {
+			delete(pairs, key)
+		}
+	}
+	return pairs
+}
+
+// NewIterator streams the pairs whose keys start with prefix, except the
+// reserved ones.
+func (s *Index) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// ForEach visits the pairs whose keys start with prefix, except the reserved
+// ones.
+func (s *Index) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, value []byte) error {
+		if reserved(key) {
+			return nil
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the pairs whose keys are in r, except the
+// reserved ones.
+func (s *Index) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// GetAt reads the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Index) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	if reserved(key) {
+		return nil, 0, false, nil
+	}
+	return reader.GetAt(key, version)
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Index) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the pairs whose keys start with prefix as they were
+// at version, except the reserved ones.
+func (s *Index) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// EvaluateFilter presents the pairs of the wrapped store to filter, except
+// the reserved ones, which no filter drops.
+func (s *Index) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		if reserved(entry.Key) {
+			return false, nil
+		}
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// iterator skips the reserved keys of the wrapped iterator.
+type iterator struct {
+	store.Iterator
+}
+
+func (it *iterator) Next() bool {
+	for it.Iterator.Next() {
+		if !reserved(it.Iterator.Key()) {
+			return true
+		}
+	}
+	return false
+}
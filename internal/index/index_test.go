@@ -0,0 +1,157 @@
+package index
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newIndex(t *testing.T) (*Index, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(base, Config{}), base
+}
+
+func hitKeys(hits []Hit) []string {
+	keys := make([]string, len(hits))
+	for i, hit := range hits {
+		keys[i] = hit.Key
+	}
+	return keys
+}
+
+func assertSearch(t *testing.T, s *Index, query, prefix string, want ...string) {
+	t.Helper()
+	hits, err := s.Search(query, prefix, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hitKeys(hits); !slices.Equal(got, want) {
+		t.Errorf("Search(%q, %q) = %v, want %v", query, prefix, got, want)
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	s, _ := newIndex(t)
+	docs := map[string][]byte{
+		"docs/1": []byte("Go is fast. Go is simple."),
+		"docs/2": []byte("Rust is fast"),
+		"docs/3": []byte("Gophers love Go"),
+		"img/1":  {0xff, 0xd8, 0xff},
+	}
+	if err := s.BatchPut(docs); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSearch(t, s, "fast", "", "docs/1", "docs/2")
+	assertSearch(t, s, "GO fast", "", "docs/1")
+	assertSearch(t, s, "go", "docs/3", "docs/3")
+	// Whole words rank above the longer words a prefix matches
+	assertSearch(t, s, "go*", "", "docs/1", "docs/3")
+	hits, err := s.Search("go*", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 1 + 2.0/7; math.Abs(hits[1].Score-want) > 1e-9 {
+		t.Errorf("Score of docs/3 = %v, want a whole word and a fraction of gophers", hits[1].Score)
+	}
+	if hits, _ := s.Search("is", "", 1); !slices.Equal(hitKeys(hits), []string{"docs/1"}) {
+		t.Errorf("Search() limited to 1 = %v, want [docs/1]", hitKeys(hits))
+	}
+	if _, err := s.Search(" *, ", "", 0); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Expected ErrEmptyQuery, got %v", err)
+	}
+
+	// Overwrites and deletes update the postings
+	if err := s.Put("docs/2", []byte("Rust is safe")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("docs/1"); err != nil {
+		t.Fatal(err)
+	}
+	assertSearch(t, s, "fast", "")
+	assertSearch(t, s, "safe", "", "docs/2")
+	if err := store.PutIfAbsent(s, "docs/4", []byte("safe and fast")); !errors.Is(err, store.ErrConditionalPutUnsupported) {
+		t.Fatalf("Expected conditional writes to fail as they do beneath, got %v", err)
+	}
+	assertSearch(t, s, "and", "")
+	if err := s.ExpireKeys([]string{"docs/2", "docs/3"}); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, _ := s.Store.Scan(KeyPrefix); len(pairs) != 0 {
+		t.Errorf("Expected every record and posting to be deleted with the keys, got %v", pairs)
+	}
+}
+
+func TestIndex_HidesIndex(t *testing.T) {
+	s, _ := newIndex(t)
+	if err := s.Put("a", []byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Errorf("Scan() = %v, want only the indexed key", pairs)
+	}
+	if n, err := store.Count(context.Background(), s, ""); err != nil || n != 1 {
+		t.Errorf("Count() = %d, %v; want 1", n, err)
+	}
+	if _, found, _ := s.Get(docKey("a")); found {
+		t.Error("Expected the record to be hidden")
+	}
+	if err := s.Put(termKey("hello", "b"), []byte{1}); !errors.Is(err, ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+}
+
+func TestIndex_Reindex(t *testing.T) {
+	s, base := newIndex(t)
+	ctx := context.Background()
+	// Values written beneath the index, and a posting outliving its key
+	if err := base.BatchPut(map[string][]byte{"a": []byte("red apple"), "b": []byte("green apple"), "c": {0xff}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.Put(termKey("apple", "gone"), []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	assertSearch(t, s, "apple", "")
+
+	stats, err := s.Reindex(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Indexed != 2 || stats.Skipped != 1 {
+		t.Errorf("Reindex() = %+v, want 2 indexed and 1 skipped", stats)
+	}
+	assertSearch(t, s, "apple", "", "a", "b")
+	assertSearch(t, s, "red", "", "a")
+	if _, found, _ := base.Get(termKey("apple", "gone")); found {
+		t.Error("Expected the old index to be cleared")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.Reindex(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled reindex to stop, got %v", err)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	s, base := newIndex(t)
+	if _, err := From(base); !errors.Is(err, ErrNotIndexed) {
+		t.Errorf("Expected ErrNotIndexed without the wrapper, got %v", err)
+	}
+	if got, err := From(store.Passthrough{Store: s}); err != nil || got != s {
+		t.Errorf("From() = %v, %v; want the index beneath", got, err)
+	}
+}
@@ -0,0 +1,113 @@
+package index
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// reindexBatchSize is the number of keys Reindex clears or indexes at once,
+// while holding off writes.
+const reindexBatchSize = 1000
+
+// ReindexStats reports on a Reindex.
+type ReindexStats struct {
+	Indexed int // Values indexed
+	Skipped int // Values without terms, such as those that are not UTF-8
+}
+
+// Reindex rebuilds the index from the values in the wrapped store, such as
+// after the index was enabled on existing data or fell out of date. Writes
+// are held off while the old index is cleared and while each batch of values
+// is indexed; searches meanwhile miss the values not indexed yet.
+func (s *Index) Reindex(ctx context.Context) (ReindexStats, error) {
+	var stats ReindexStats
+	if err := s.clear(ctx); err != nil {
+		return stats, err
+	}
+	// Visit the keys before and after the reserved ones
+	for _, r := range []store.KeyRange{{End: KeyPrefix}, {Start: store.PrefixRange(KeyPrefix).End}} {
+		for {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+			last, err := s.reindexBatch(r, &stats)
+			if err != nil {
+				return stats, err
+			}
+			if last == "" {
+				break
+			}
+			r.Start = last + "\x00"
+		}
+	}
+	return stats, nil
+}
+
+// clear deletes every record and posting.
+func (s *Index) clear(ctx context.Context) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	r := store.PrefixRange(KeyPrefix)
+	r.KeysOnly = true
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		keys, err := readKeys(s.Store, r, reindexBatchSize)
+		if err != nil || len(keys) == 0 {
+			return err
+		}
+		if err := store.BatchDelete(s.Store, keys); err != nil {
+			return err
+		}
+	}
+}
+
+// reindexBatch indexes the next batch of values in r, returning the last key
+// indexed or an empty string once r is exhausted.
+func (s *Index) reindexBatch(r store.KeyRange, stats *ReindexStats) (string, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	keys, err := readKeys(s.Store, r, reindexBatchSize)
+	if err != nil || len(keys) == 0 {
+		return "", err
+	}
+	values, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return "", err
+	}
+	docs := make(map[string]map[string]int, len(values))
+	for key, value := range values {
+		terms := Tokenize(value, s.maxTerms)
+		if len(terms) == 0 {
+			stats.Skipped++
+			continue
+		}
+		docs[key] = terms
+		stats.Indexed++
+	}
+	if err := s.update(docs); err != nil {
+		return "", err
+	}
+	return keys[len(keys)-1], nil
+}
+
+// readKeys returns up to n keys of s in r, closing the iterator before
+// returning so the keys can be written to.
+func readKeys(s store.Store, r store.KeyRange, n int) (keys []string, err error) {
+	r.KeysOnly = true
+	it, err := store.NewRangeIterator(s, r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	for len(keys) < n && it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys, it.Err()
+}
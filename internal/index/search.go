@@ -0,0 +1,150 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// ErrEmptyQuery is returned by Search for a query without any term.
+var ErrEmptyQuery = errors.New("query has no terms")
+
+// Hit is a key matching a query, with how well it matches.
+type Hit struct {
+	Key   string
+	Score float64
+}
+
+// queryTerm is a term every hit must contain, or for a prefix term, contain
+// a term starting with.
+type queryTerm struct {
+	term   string
+	prefix bool
+}
+
+// parseQuery returns the terms of query, split as Tokenize splits values. A
+// word ending with * matches every term starting with its last term.
+func parseQuery(query string) ([]queryTerm, error) {
+	var terms []queryTerm
+	for _, word := range strings.Fields(query) {
+		prefix := strings.HasSuffix(word, "*")
+		words := strings.FieldsFunc(strings.TrimSuffix(word, "*"), separator)
+		for i, term := range words {
+			terms = append(terms, queryTerm{term: strings.ToLower(term), prefix: prefix && i == len(words)-1})
+		}
+	}
+	if len(terms) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	return terms, nil
+}
+
+// Search returns up to limit keys starting with prefix that contain every
+// term of query, best matches first and ties in key order; a zero limit
+// returns every match. A key scores the number of times it contains each
+// term, where the terms a prefix term matches count by the fraction of
+// them the prefix covers, so whole words rank above the longer words they
+// start. Keys whose postings outlived them are left out.
+func (s *Index) Search(query, prefix string, limit int) ([]Hit, error) {
+	terms, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	var scores map[string]float64
+	for _, term := range terms {
+		matches, err := s.match(term, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for key, score := range scores {
+			if match, ok := matches[key]; ok {
+				scores[key] = score + match
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for key, score := range scores {
+		hits = append(hits, Hit{Key: key, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return s.existing(hits, limit)
+}
+
+// match returns the keys starting with prefix that contain term, with their
+// scores for it.
+func (s *Index) match(term queryTerm, prefix string) (map[string]float64, error) {
+	r := store.PrefixRange(termKey(term.term, prefix))
+	if term.prefix {
+		r = store.PrefixRange(TermPrefix + term.term)
+	}
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	scores := make(map[string]float64)
+	for it.Next() {
+		matched, key, err := splitPosting(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		n, size := binary.Uvarint(it.Value())
+		if size <= 0 {
+			return nil, fmt.Errorf("%w: posting %q", ErrCorruptIndex, it.Key())
+		}
+		scores[key] += float64(n) * float64(len(term.term)) / float64(len(matched))
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// existing returns the first limit hits whose keys exist, all of them if
+// limit is zero.
+func (s *Index) existing(hits []Hit, limit int) ([]Hit, error) {
+	if limit == 0 {
+		limit = len(hits)
+	}
+	found := make([]Hit, 0, min(limit, len(hits)))
+	for len(hits) > 0 && len(found) < limit {
+		batch := hits[:min(limit-len(found), len(hits))]
+		hits = hits[len(batch):]
+		keys := make([]string, len(batch))
+		for i, hit := range batch {
+			keys[i] = hit.Key
+		}
+		values, err := store.BatchGet(s.Store, keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range batch {
+			if _, ok := values[hit.Key]; ok {
+				found = append(found, hit)
+			}
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,52 @@
+package index
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTermLength is the length in bytes of the longest term indexed; longer
+// words, such as encoded data, are left out.
+const MaxTermLength = 64
+
+// Tokenize returns the terms of value with the number of times each occurs.
+// Terms are the runs of letters and digits, lowercased. Values that are not
+// valid UTF-8 have no terms. When there are more than maxTerms distinct
+// terms, only the most frequent are kept, the first in order breaking ties;
+// a zero maxTerms keeps them all.
+func Tokenize(value []byte, maxTerms int) map[string]int {
+	if !utf8.Valid(value) {
+		return nil
+	}
+	terms := make(map[string]int)
+	for _, word := range strings.FieldsFunc(string(value), separator) {
+		term := strings.ToLower(word)
+		if len(term) <= MaxTermLength {
+			terms[term]++
+		}
+	}
+	if maxTerms == 0 || len(terms) <= maxTerms {
+		return terms
+	}
+	ranked := make([]string, 0, len(terms))
+	for term := range terms {
+		ranked = append(ranked, term)
+	}
+	slices.SortFunc(ranked, func(a, b string) int {
+		if terms[a] != terms[b] {
+			return terms[b] - terms[a]
+		}
+		return strings.Compare(a, b)
+	})
+	for _, term := range ranked[maxTerms:] {
+		delete(terms, term)
+	}
+	return terms
+}
+
+// separator reports whether r separates terms.
+func separator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,30 @@
+package index
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		maxTerms int
+		want     map[string]int
+	}{
+		{name: "words", value: "The quick fox, the LAZY dog.", want: map[string]int{"the": 2, "quick": 1, "fox": 1, "lazy": 1, "dog": 1}},
+		{name: "unicode", value: "Café-crème 42", want: map[string]int{"café": 1, "crème": 1, "42": 1}},
+		{name: "long words", value: "ok " + strings.Repeat("x", MaxTermLength+1), want: map[string]int{"ok": 1}},
+		{name: "most frequent kept", value: "b a c b c b", maxTerms: 2, want: map[string]int{"b": 3, "c": 2}},
+		{name: "ties in order", value: "d c b a", maxTerms: 2, want: map[string]int{"a": 1, "b": 1}},
+		{name: "binary", value: "\xff\xfe text", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize([]byte(tt.value), tt.maxTerms); !maps.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package jsondiff computes structural differences between JSON documents.
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Op describes how a path differs between two documents.
+type Op string
+
+const (
+	OpAdded   Op = "added"
+	OpRemoved Op = "removed"
+	OpChanged Op = "changed"
+)
+
+// Change is a single difference. Path is a JSON Pointer (RFC 6901); the empty
+// path refers to the whole document. Old is nil for additions and New is nil
+// for removals.
+type Change struct {
+	Op   Op
+	Path string
+	Old  any
+	New  any
+}
+
+// Diff returns the changes that turn the JSON document a into b, ordered by
+// path. Objects are compared member by member and arrays element by element;
+// any other difference, including a change of type, is reported at the path
+// where it occurs.
+func Diff(a, b []byte) ([]Change, error) {
+	left, err := decode(a)
+	if err != nil {
+		return nil, fmt.Errorf("invalid left document: %w", err)
+	}
+	right, err := decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid right document: %w", err)
+	}
+
+	var changes []Change
+	compare("", left, right, &changes)
+	return changes, nil
+}
+
+// decode parses a single JSON value, keeping numbers exact.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+	return v, nil
+}
+
+func compare(path string, a, b any, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			compareObjects(path, av, bv, changes)
+			return
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			compareArrays(path, av, bv, changes)
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Op: OpChanged, Path: path, Old: a, New: b})
+	}
+}
+
+func compareObjects(path string, a, b map[string]any, changes *[]Change) {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := path + "/" + escape(name)
+		av, inA := a[name]
+		bv, inB := b[name]
+		switch {
+		case !inA:
+			*changes = append(*changes, Change{Op: OpAdded, Path: child, New: bv})
+		case !inB:
+			*changes = append(*changes, Change{Op: OpRemoved, Path: child, Old: av})
+		default:
+			compare(child, av, bv, changes)
+		}
+	}
+}
+
+func compareArrays(path string, a, b []any, changes *[]Change) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		child := path + "/" + strconv.Itoa(i)
+		switch {
+		case i >= len(a):
+			*changes = append(*changes, Change{Op: OpAdded, Path: child, New: b[i]})
+		case i >= len(b):
+			*changes = append(*changes, Change{Op: OpRemoved, Path: child, Old: a[i]})
+		default:
+			compare(child, a[i], b[i], changes)
+		}
+	}
+}
+
+// escape encodes an object member name as a JSON Pointer reference token.
+func escape(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
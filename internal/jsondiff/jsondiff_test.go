@@ -0,0 +1,87 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Change
+	}{
+		{
+			name: "Equal",
+			a:    `{"a": 1, "b": [1, 2]}`,
+			b:    `{"b": [1, 2], "a": 1}`,
+			want: nil,
+		},
+		{
+			name: "ObjectMembers",
+			a:    `{"keep": true, "gone": "x", "port": 80}`,
+			b:    `{"keep": true, "new": null, "port": 8080}`,
+			want: []Change{
+				{Op: OpRemoved, Path: "/gone", Old: "x"},
+				{Op: OpAdded, Path: "/new", New: nil},
+				{Op: OpChanged, Path: "/port", Old: json.Number("80"), New: json.Number("8080")},
+			},
+		},
+		{
+			name: "NestedArrays",
+			a:    `{"hosts": [{"name": "a"}, {"name": "b"}]}`,
+			b:    `{"hosts": [{"name": "a"}, {"name": "c"}, {"name": "d"}]}`,
+			want: []Change{
+				{Op: OpChanged, Path: "/hosts/1/name", Old: "b", New: "c"},
+				{Op: OpAdded, Path: "/hosts/2", New: map[string]any{"name": "d"}},
+			},
+		},
+		{
+			name: "TypeChange",
+			a:    `{"limits": {"max": 1}}`,
+			b:    `{"limits": [1]}`,
+			want: []Change{
+				{Op: OpChanged, Path: "/limits", Old: map[string]any{"max": json.Number("1")}, New: []any{json.Number("1")}},
+			},
+		},
+		{
+			name: "EscapedNames",
+			a:    `{"a/b": 1, "c~d": 1}`,
+			b:    `{"a/b": 2, "c~d": 2}`,
+			want: []Change{
+				{Op: OpChanged, Path: "/a~1b", Old: json.Number("1"), New: json.Number("2")},
+				{Op: OpChanged, Path: "/c~0d", Old: json.Number("1"), New: json.Number("2")},
+			},
+		},
+		{
+			name: "Scalars",
+			a:    `"old"`,
+			b:    `"new"`,
+			want: []Change{{Op: OpChanged, Path: "", Old: "old", New: "new"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff([]byte(tt.a), []byte(tt.b))
+			if err != nil {
+				t.Fatalf("Diff failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_InvalidJSON(t *testing.T) {
+	for _, doc := range []string{`{`, `1 2`, ``} {
+		if _, err := Diff([]byte(doc), []byte(`{}`)); err == nil {
+			t.Errorf("Expected error for left document %q", doc)
+		}
+		if _, err := Diff([]byte(`{}`), []byte(doc)); err == nil {
+			t.Errorf("Expected error for right document %q", doc)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package keygen generates keys server-side, so clients can insert values
+// under unique keys without coordinating with each other.
+package keygen
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// SequencePrefix is the internal key prefix under which the counter of each
+// sequence is stored, followed by the prefix of the keys it numbers.
+const SequencePrefix = "__clavis/sequences/"
+
+// maxAttempts is the number of keys Create tries before giving up.
+const maxAttempts = 8
+
+// ErrExhausted is returned by Create when every key generated was taken.
+var ErrExhausted = errors.New("every generated key already exists")
+
+// Kind is how the unique part of a key is generated.
+type Kind int
+
+const (
+	ULID     Kind = iota // 26 characters of Crockford base32, sorting by creation time to the millisecond
+	UUID                 // Random version 4 UUID, in its canonical hexadecimal form
+	Sequence             // The next number of a counter kept per prefix, as 20 zero-padded digits
+)
+
+// String returns the name of k.
+func (k Kind) String() string {
+	switch k {
+	case ULID:
+		return "ulid"
+	case UUID:
+		return "uuid"
+	case Sequence:
+		return "sequence"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Template describes the keys to generate: Prefix followed by a unique part
+// of the given Kind.
+type Template struct {
+	Prefix string
+	Kind   Kind
+}
+
+// Create stores value in s under a new key generated from t, written only if
+// the key does not exist yet, and returns the key. Should the key exist, such
+// as one written by a client under the prefix of a sequence, another one is
+// generated. Stores without conditional writes fail with
+// store.ErrConditionalPutUnsupported.
+func Create(ctx context.Context, s store.Store, t Template, value []byte) (string, error) {
+	for range maxAttempts {
+		key, err := t.next(ctx, s)
+		if err != nil {
+			return "", err
+		}
+		err = store.PutIfAbsentContext(ctx, s, key, value)
+		if errors.Is(err, store.ErrKeyExists) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return key, nil
+	}
+	return "", fmt.Errorf("%w: %d attempts under %q", ErrExhausted, maxAttempts, t.Prefix)
+}
+
+// next returns a key generated from t.
+func (t Template) next(ctx context.Context, s store.Store) (string, error) {
+	switch t.Kind {
+	case ULID:
+		return t.Prefix + NewULID(time.Now()), nil
+	case UUID:
+		return t.Prefix + NewUUID(), nil
+	case Sequence:
+		n, err := store.Increment(ctx, s, SequencePrefix+t.Prefix, 1, store.CounterASCII)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s%020d", t.Prefix, n), nil
+	default:
+		return "", fmt.Errorf("unknown key kind %s", t.Kind)
+	}
+}
+
+// crockford is the alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID for time t: 48 bits of Unix milliseconds followed by
+// 80 random bits, encoded in Crockford base32.
+func NewULID(t time.Time) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(t.UnixMilli())<<16) // #nosec G115 -- times after 1970
+	_, _ = rand.Read(id[6:])
+
+	// 26 characters of 5 bits each encode 130 bits, so the first character
+	// only carries the top 3 bits of the timestamp
+	var out [26]byte
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// NewUUID returns a random version 4 UUID.
+func NewUUID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40 // Version 4
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	var out [36]byte
+	hex.Encode(out[0:8], id[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], id[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], id[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], id[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], id[10:])
+	return string(out[:])
+}
@@ -0,0 +1,88 @@
+package keygen
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestNewULID(t *testing.T) {
+	valid := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	at := time.UnixMilli(1469918176385)
+	first, second := NewULID(at), NewULID(at.Add(time.Millisecond))
+	for _, id := range []string{first, second} {
+		if !valid.MatchString(id) {
+			t.Errorf("NewULID() = %q, want 26 characters of Crockford base32", id)
+		}
+	}
+	// The timestamp takes the first 10 characters
+	if got := first[:10]; got != "01ARYZ6S41" {
+		t.Errorf("Timestamp of NewULID() = %q, want 01ARYZ6S41", got)
+	}
+	if first >= second {
+		t.Errorf("Expected %q to sort before the later %q", first, second)
+	}
+	if NewULID(at) == NewULID(at) {
+		t.Error("Expected ULIDs of the same millisecond to differ")
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	valid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if id := NewUUID(); !valid.MatchString(id) {
+		t.Errorf("NewUUID() = %q, want a version 4 UUID", id)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	s, err := badger.NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	ctx := context.Background()
+
+	for _, kind := range []Kind{ULID, UUID} {
+		key, err := Create(ctx, s, Template{Prefix: "orders/", Kind: kind}, []byte("x"))
+		if err != nil {
+			t.Fatalf("Create() of a %s = %v", kind, err)
+		}
+		if value, found, _ := s.Get(key); !found || string(value) != "x" || !strings.HasPrefix(key, "orders/") {
+			t.Errorf("Create() of a %s = %q, want the value stored under orders/", kind, key)
+		}
+	}
+
+	// Sequences skip the numbers taken by keys written otherwise
+	if err := s.Put("jobs/00000000000000000002", []byte("taken")); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for range 2 {
+		key, err := Create(ctx, s, Template{Prefix: "jobs/", Kind: Sequence}, []byte("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if want := []string{"jobs/00000000000000000001", "jobs/00000000000000000003"}; keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("Create() of sequences = %v, want %v", keys, want)
+	}
+	if key, err := Create(ctx, s, Template{Prefix: "users/", Kind: Sequence}, []byte("x")); err != nil || key != "users/00000000000000000001" {
+		t.Errorf("Create() under another prefix = %q, %v; want its own sequence", key, err)
+	}
+
+	unversioned, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(ctx, unversioned, Template{Kind: UUID}, []byte("x")); !errors.Is(err, store.ErrConditionalPutUnsupported) {
+		t.Errorf("Expected ErrConditionalPutUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package keystats
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+// hllPrecision gives 2^12 registers, a standard error of about 1.6%.
+const hllPrecision = 12
+
+// hyperLogLog estimates the number of distinct strings added to it using a
+// fixed amount of memory.
+type hyperLogLog struct {
+	seed      maphash.Seed
+	registers [1 << hllPrecision]uint8
+}
+
+func newHyperLogLog(seed maphash.Seed) *hyperLogLog {
+	return &hyperLogLog{seed: seed}
+}
+
+// Add records an element.
+func (h *hyperLogLog) Add(s string) {
+	x := maphash.String(h.seed, s)
+	idx := x >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Estimate returns the approximate number of distinct elements added.
+func (h *hyperLogLog) Estimate() uint64 {
+	const m = float64(len(h.registers))
+	alpha := 0.7213 / (1 + 1.079/m)
+
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha * m * m / sum
+	// Small range correction with linear counting
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
@@ -0,0 +1,163 @@
+// Package keystats maintains approximate statistics about the shape of the
+// keyspace, updated as keys are written, so operators can understand it
+// without running full scans.
+package keystats
+
+import (
+	"hash/maphash"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// OverflowPrefix collects keys once MaxPrefixes distinct prefixes are tracked.
+const OverflowPrefix = "*"
+
+// DefaultMaxPrefixes bounds the memory used by a tracker.
+const DefaultMaxPrefixes = 1024
+
+// DefaultSeparators split keys into segments.
+const DefaultSeparators = ":/"
+
+// PrefixStats describes the keys under one top-level prefix.
+type PrefixStats struct {
+	Prefix        string
+	EstimatedKeys uint64 // distinct keys written, estimated with HyperLogLog
+	Writes        uint64
+	BytesWritten  uint64
+}
+
+// Snapshot is a point-in-time view of the statistics.
+type Snapshot struct {
+	Prefixes []PrefixStats  // sorted by estimated keys, largest first
+	Depths   map[int]uint64 // writes by number of key segments
+}
+
+type prefixStats struct {
+	hll          *hyperLogLog
+	writes       uint64
+	bytesWritten uint64
+}
+
+// Tracker accumulates keyspace statistics.
+type Tracker struct {
+	mu          sync.Mutex
+	seed        maphash.Seed
+	separators  string
+	maxPrefixes int
+	prefixes    map[string]*prefixStats
+	depths      map[int]uint64
+}
+
+// NewTracker creates a tracker splitting keys on the given separator
+// characters and tracking at most maxPrefixes top-level prefixes. Empty or
+// non-positive arguments select the defaults.
+func NewTracker(separators string, maxPrefixes int) *Tracker {
+	if separators == "" {
+		separators = DefaultSeparators
+	}
+	if maxPrefixes <= 0 {
+		maxPrefixes = DefaultMaxPrefixes
+	}
+	return &Tracker{
+		seed:        maphash.MakeSeed(),
+		separators:  separators,
+		maxPrefixes: maxPrefixes,
+		prefixes:    make(map[string]*prefixStats),
+		depths:      make(map[int]uint64),
+	}
+}
+
+// Observe records a write of value under key.
+func (t *Tracker) Observe(key string, value []byte) {
+	prefix, depth := t.split(key)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.prefixes[prefix]
+	if !ok {
+		if len(t.prefixes) >= t.maxPrefixes {
+			prefix = OverflowPrefix
+			stats, ok = t.prefixes[prefix]
+		}
+		if !ok {
+			stats = &prefixStats{hll: newHyperLogLog(t.seed)}
+			t.prefixes[prefix] = stats
+		}
+	}
+	stats.hll.Add(key)
+	stats.writes++
+	stats.bytesWritten += uint64(len(value))
+	t.depths[depth]++
+}
+
+// split returns the top-level prefix of key and its number of segments.
+func (t *Tracker) split(key string) (string, int) {
+	prefix := key
+	if i := strings.IndexAny(key, t.separators); i >= 0 {
+		prefix = key[:i]
+	}
+	depth := 1
+	for _, r := range key {
+		if strings.ContainsRune(t.separators, r) {
+			depth++
+		}
+	}
+	return prefix, depth
+}
+
+// Subscribe feeds the tracker from Put events on bus. The returned function
+// stops tracking.
+func (t *Tracker) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return bus.Subscribe(func(e events.Event) { t.Observe(e.Key, e.Value) }, events.TypePut)
+}
+
+// Seed observes every key already present in s, so statistics cover data
+// written before the tracker started.
+func (t *Tracker) Seed(s store.Store) error {
+	it, err := store.NewIterator(s, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		t.Observe(it.Key(), it.Value())
+	}
+	return it.Err()
+}
+
+// Snapshot returns the current statistics.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := Snapshot{
+		Prefixes: make([]PrefixStats, 0, len(t.prefixes)),
+		Depths:   make(map[int]uint64, len(t.depths)),
+	}
+	for prefix, stats := range t.prefixes {
+		snapshot.Prefixes = append(snapshot.Prefixes, PrefixStats{
+			Prefix:        prefix,
+			EstimatedKeys: stats.hll.Estimate(),
+			Writes:        stats.writes,
+			BytesWritten:  stats.bytesWritten,
+		})
+	}
+	for depth, count := range t.depths {
+		snapshot.Depths[depth] = count
+	}
+
+	sort.Slice(snapshot.Prefixes, func(i, j int) bool {
+		a, b := snapshot.Prefixes[i], snapshot.Prefixes[j]
+		if a.EstimatedKeys != b.EstimatedKeys {
+			return a.EstimatedKeys > b.EstimatedKeys
+		}
+		return a.Prefix < b.Prefix
+	})
+	return snapshot
+}
@@ -0,0 +1,87 @@
+package keystats
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	tracker := NewTracker("", 0)
+	const n = 10000
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		tracker.Observe(key, []byte("v"))
+		// Rewrites must not inflate the estimate
+		tracker.Observe(key, []byte("v"))
+	}
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot.Prefixes) != 1 {
+		t.Fatalf("Expected 1 prefix, got %d", len(snapshot.Prefixes))
+	}
+	got := snapshot.Prefixes[0]
+	if got.Writes != 2*n || got.BytesWritten != 2*n {
+		t.Errorf("Unexpected counters: %+v", got)
+	}
+	// Precision 12 has a standard error of about 1.6%
+	if got.EstimatedKeys < n*95/100 || got.EstimatedKeys > n*105/100 {
+		t.Errorf("Estimate %d too far from %d", got.EstimatedKeys, n)
+	}
+}
+
+func TestTracker_PrefixesAndDepths(t *testing.T) {
+	tracker := NewTracker("", 2)
+	for _, key := range []string{"user:1", "user:2", "order/1/item", "noseparator", "session:1"} {
+		tracker.Observe(key, []byte("value"))
+	}
+
+	snapshot := tracker.Snapshot()
+	want := map[string]uint64{"user": 2, "order": 1, OverflowPrefix: 2}
+	if len(snapshot.Prefixes) != len(want) {
+		t.Fatalf("Expected prefixes %v, got %+v", want, snapshot.Prefixes)
+	}
+	for _, p := range snapshot.Prefixes {
+		if want[p.Prefix] != p.EstimatedKeys {
+			t.Errorf("Prefix %q: expected %d keys, got %d", p.Prefix, want[p.Prefix], p.EstimatedKeys)
+		}
+	}
+	if snapshot.Prefixes[len(snapshot.Prefixes)-1].Prefix != "order" {
+		t.Errorf("Expected prefixes sorted by size, got %+v", snapshot.Prefixes)
+	}
+
+	wantDepths := map[int]uint64{1: 1, 2: 3, 3: 1}
+	for depth, count := range wantDepths {
+		if snapshot.Depths[depth] != count {
+			t.Errorf("Depth %d: expected %d, got %d", depth, count, snapshot.Depths[depth])
+		}
+	}
+}
+
+func TestTracker_Seed(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		if err := s.Put(fmt.Sprintf("item:%d", i), []byte("abc")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracker := NewTracker("", 0)
+	if err := tracker.Seed(s); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot.Prefixes) != 1 || snapshot.Prefixes[0].EstimatedKeys != 3 || snapshot.Prefixes[0].BytesWritten != 9 {
+		t.Errorf("Unexpected snapshot after seeding: %+v", snapshot.Prefixes)
+	}
+}
@@ -0,0 +1,329 @@
+// Package lease grants leases that keys can be attached to. A lease lives
+// for its TTL from when it was granted or last kept alive; when it expires or
+// is revoked, the keys attached to it are deleted. Services register
+// themselves with keys attached to a lease they keep alive, so their
+// registrations go away shortly after they do.
+package lease
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// Leases are recorded in the store under KeyPrefix: each lease at
+// RecordPrefix followed by its ID in hexadecimal, and each key attached to it
+// at AttachPrefix followed by the ID, a slash and the key. KeyPrefix is
+// reserved, so clients can only attach keys through the lease RPCs, which
+// check that they may delete them.
+const (
+	KeyPrefix    = store.ReservedPrefix + "leases/"
+	RecordPrefix = KeyPrefix + "ids/"
+	AttachPrefix = KeyPrefix + "keys/"
+)
+
+const (
+	DefaultTTL            = time.Minute    // TTL of leases granted without one
+	MaxTTL                = 24 * time.Hour // Longest TTL granted
+	DefaultExpiryInterval = time.Second    // Time between expiries of leases
+)
+
+const (
+	recordSize      = 8 + 8   // TTL and expiry time, in nanoseconds
+	idFormat        = "%016x" // IDs in hexadecimal, padded so they sort as numbers
+	revokeBatchSize = 1000    // Attached keys removed at once
+)
+
+// Metric names recorded by background expiry.
+const (
+	MetricExpiredLeases = "lease_expired_total"      // Leases that expired
+	MetricExpiredKeys   = "lease_expired_keys_total" // Keys deleted with the leases they were attached to
+)
+
+var (
+	// ErrNotFound is returned for a lease that expired, was revoked or was
+	// never granted.
+	ErrNotFound = errors.New("lease not found")
+	// ErrInvalidTTL is returned by Grant for a negative TTL or one above
+	// MaxTTL.
+	ErrInvalidTTL = errors.New("invalid lease TTL")
+	// ErrCorruptRecord is returned when a lease record cannot be decoded.
+	ErrCorruptRecord = errors.New("corrupt lease record")
+)
+
+// Lease is a granted lease.
+type Lease struct {
+	ID      uint64
+	TTL     time.Duration
+	Expires time.Time
+}
+
+// Manager grants, keeps alive and revokes the leases recorded in a store.
+type Manager struct {
+	store store.Store
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+// NewManager creates a manager recording leases in s.
+func NewManager(s store.Store) *Manager {
+	return &Manager{store: s, now: time.Now}
+}
+
+// Grant grants a lease for ttl, DefaultTTL if zero.
+func (m *Manager) Grant(ttl time.Duration) (Lease, error) {
+	switch {
+	case ttl == 0:
+		ttl = DefaultTTL
+	case ttl < 0 || ttl > MaxTTL:
+		return Lease{}, fmt.Errorf("%w: %s is not in (0, %s]", ErrInvalidTTL, ttl, MaxTTL)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		lease := Lease{ID: rand.Uint64(), TTL: ttl, Expires: m.now().Add(ttl)} // #nosec G404 -- IDs need not be secret, only distinct
+		if lease.ID == 0 {
+			continue
+		}
+		found, err := store.Exists(m.store, recordKey(lease.ID))
+		if err != nil {
+			return Lease{}, err
+		}
+		if found {
+			continue
+		}
+		return lease, m.store.Put(recordKey(lease.ID), encodeRecord(lease))
+	}
+}
+
+// Attach attaches keys to the live lease id, so they are deleted with it.
+// A key stays attached when it is overwritten or deleted, so a key written
+// again later is deleted with the lease all the same.
+func (m *Manager) Attach(id uint64, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.live(id); err != nil {
+		return err
+	}
+	entries := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		entries[attachKey(id, key)] = nil
+	}
+	return store.BatchPut(m.store, entries)
+}
+
+// KeepAlive extends the live lease id to its TTL from now.
+func (m *Manager) KeepAlive(id uint64) (Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lease, err := m.live(id)
+	if err != nil {
+		return Lease{}, err
+	}
+	lease.Expires = m.now().Add(lease.TTL)
+	return lease, m.store.Put(recordKey(id), encodeRecord(lease))
+}
+
+// Revoke ends the lease id and deletes the keys attached to it, returning
+// how many keys were attached. A lease that expired but was not removed yet
+// is revoked all the same.
+func (m *Manager) Revoke(id uint64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	found, err := store.Exists(m.store, recordKey(id))
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return m.remove(id, store.BatchDelete)
+}
+
+// Expire ends the leases expired at now, removing the keys attached to them
+// as expired keys, and returns how many leases and keys were removed.
+func (m *Manager) Expire(now time.Time) (leases, keys int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records, err := m.store.Scan(RecordPrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	for key, record := range records {
+		id, err := strconv.ParseUint(key[len(RecordPrefix):], 16, 64)
+		if err != nil {
+			continue
+		}
+		lease, err := decodeRecord(id, record)
+		if err != nil || now.Before(lease.Expires) {
+			continue
+		}
+		n, err := m.remove(id, store.ExpireKeys)
+		if err != nil {
+			return leases, keys, err
+		}
+		leases++
+		keys += n
+	}
+	return leases, keys, nil
+}
+
+// ExpiryConfig configures background expiry of leases.
+type ExpiryConfig struct {
+	Interval time.Duration     // Time between expiries; DefaultExpiryInterval if zero
+	Metrics  *metrics.Registry // Registry for expiry metrics; metrics.Default if nil
+	Logger   *slog.Logger      // Logger for failed expiries; slog.Default() if nil
+}
+
+// StartExpiry ends expired leases in the background until the returned
+// function is called. Leases are only ended, and their keys deleted, by
+// expiry or revocation, so a lease outlives its TTL by up to the interval.
+func (m *Manager) StartExpiry(config ExpiryConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultExpiryInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	expiredLeases := registry.Counter(MetricExpiredLeases)
+	expiredKeys := registry.Counter(MetricExpiredKeys)
+	logger := logging.Or(config.Logger)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				leases, keys, err := m.Expire(now)
+				if err != nil {
+					logger.Warn("Lease expiry failed", "error", err)
+				}
+				expiredLeases.Add(uint64(leases)) // #nosec G115 -- counts are non-negative
+				expiredKeys.Add(uint64(keys))     // #nosec G115 -- counts are non-negative
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
+
+// live returns the lease id unless it expired. The caller holds m.mu.
+func (m *Manager) live(id uint64) (Lease, error) {
+	record, found, err := m.store.Get(recordKey(id))
+	if err != nil {
+		return Lease{}, err
+	}
+	if !found {
+		return Lease{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	lease, err := decodeRecord(id, record)
+	if err != nil {
+		return Lease{}, err
+	}
+	if !m.now().Before(lease.Expires) {
+		return Lease{}, fmt.Errorf("%w: %d expired", ErrNotFound, id)
+	}
+	return lease, nil
+}
+
+// remove removes the keys attached to lease id with removeKeys, then the
+// lease, and returns how many keys were attached. The caller holds m.mu.
+func (m *Manager) remove(id uint64, removeKeys func(store.Store, []string) error) (int, error) {
+	prefix := attachKey(id, "")
+	r := store.PrefixRange(prefix)
+	r.KeysOnly = true
+	removed := 0
+	for {
+		entries, err := readKeys(m.store, r, revokeBatchSize)
+		if err != nil {
+			return removed, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		keys := make([]string, len(entries))
+		for i, entry := range entries {
+			keys[i] = strings.TrimPrefix(entry, prefix)
+		}
+		if err := removeKeys(m.store, keys); err != nil {
+			return removed, err
+		}
+		if err := store.BatchDelete(m.store, entries); err != nil {
+			return removed, err
+		}
+		removed += len(keys)
+	}
+	return removed, m.store.Delete(recordKey(id))
+}
+
+// readKeys returns up to n keys of s in r, closing the iterator before
+// returning so the keys can be written to.
+func readKeys(s store.Store, r store.KeyRange, n int) (keys []string, err error) {
+	it, err := store.NewRangeIterator(s, r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	for len(keys) < n && it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys, it.Err()
+}
+
+func recordKey(id uint64) string {
+	return RecordPrefix + fmt.Sprintf(idFormat, id)
+}
+
+func attachKey(id uint64, key string) string {
+	return AttachPrefix + fmt.Sprintf(idFormat, id) + "/" + key
+}
+
+// encodeRecord returns the record of lease.
+func encodeRecord(lease Lease) []byte {
+	record := make([]byte, recordSize)
+	binary.BigEndian.PutUint64(record, uint64(lease.TTL))                    // #nosec G115 -- TTLs are positive
+	binary.BigEndian.PutUint64(record[8:], uint64(lease.Expires.UnixNano())) // #nosec G115 -- two's complement round trip
+	return record
+}
+
+// decodeRecord returns the lease id recorded in record.
+func decodeRecord(id uint64, record []byte) (Lease, error) {
+	if len(record) != recordSize {
+		return Lease{}, fmt.Errorf("%w: lease %d", ErrCorruptRecord, id)
+	}
+	return Lease{
+		ID:      id,
+		TTL:     time.Duration(binary.BigEndian.Uint64(record)),           // #nosec G115 -- two's complement round trip
+		Expires: time.Unix(0, int64(binary.BigEndian.Uint64(record[8:]))), // #nosec G115 -- two's complement round trip
+	}, nil
+}
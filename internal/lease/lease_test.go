@@ -0,0 +1,129 @@
+package lease
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newManager(t *testing.T, s store.Store) (*Manager, *time.Time) {
+	t.Helper()
+	m := NewManager(s)
+	now := time.Unix(1700000000, 0)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func newStore(t *testing.T) *memory.MemoryStore {
+	t.Helper()
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func assertExists(t *testing.T, s store.Store, key string, want bool) {
+	t.Helper()
+	found, err := store.Exists(s, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != want {
+		t.Errorf("Exists(%q) = %v, want %v", key, found, want)
+	}
+}
+
+func TestManager_Revoke(t *testing.T) {
+	s := newStore(t)
+	m, _ := newManager(t, s)
+	lease, err := m.Grant(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.ID == 0 || lease.TTL != DefaultTTL {
+		t.Errorf("Grant() = %+v, want a lease for the default TTL", lease)
+	}
+	other, err := m.Grant(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"services/a": []byte("10.0.0.1"), "services/b": []byte("10.0.0.2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Attach(lease.ID, "services/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Attach(other.ID, "services/b"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := m.Revoke(lease.ID)
+	if err != nil || n != 1 {
+		t.Fatalf("Revoke() = %d, %v; want 1 key", n, err)
+	}
+	assertExists(t, s, "services/a", false)
+	assertExists(t, s, "services/b", true)
+	if _, err := m.Revoke(lease.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound revoking twice, got %v", err)
+	}
+	if err := m.Attach(lease.ID, "services/c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound attaching to a revoked lease, got %v", err)
+	}
+	if _, err := m.Grant(-time.Second); !errors.Is(err, ErrInvalidTTL) {
+		t.Errorf("Expected ErrInvalidTTL, got %v", err)
+	}
+}
+
+func TestManager_Expire(t *testing.T) {
+	bus := events.NewBus(0)
+	defer bus.Close()
+	s := events.NewPublishingStore(newStore(t), bus)
+	m, now := newManager(t, s)
+
+	lease, err := m.Grant(10 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("services/a", []byte("10.0.0.1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Attach(lease.ID, "services/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keeping the lease alive postpones its expiry
+	*now = now.Add(8 * time.Second)
+	kept, err := m.KeepAlive(lease.ID)
+	if err != nil || !kept.Expires.Equal(now.Add(10*time.Second)) {
+		t.Fatalf("KeepAlive() = %+v, %v; want it to expire 10s from now", kept, err)
+	}
+	if leases, _, err := m.Expire(now.Add(5 * time.Second)); err != nil || leases != 0 {
+		t.Fatalf("Expire() = %d, %v; want the kept lease to live", leases, err)
+	}
+
+	expirations := make(chan string, 10)
+	unsubscribe := bus.Subscribe(func(e events.Event) { expirations <- e.Key }, events.TypeExpire)
+	defer unsubscribe()
+	leases, keys, err := m.Expire(now.Add(10 * time.Second))
+	if err != nil || leases != 1 || keys != 1 {
+		t.Fatalf("Expire() = %d, %d, %v; want 1 lease and 1 key", leases, keys, err)
+	}
+	assertExists(t, s, "services/a", false)
+	if key := <-expirations; key != "services/a" {
+		t.Errorf("Expected services/a to be reported expired, got %q", key)
+	}
+	if pairs, _ := s.Scan(KeyPrefix); len(pairs) != 0 {
+		t.Errorf("Expected the lease records to be removed, got %v", pairs)
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := m.KeepAlive(lease.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound keeping an expired lease alive, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package lock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestFencer_Guard(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+	fencer := NewFencer(s)
+
+	writes := 0
+	write := func() error { writes++; return nil }
+
+	if err := fencer.issue("orders", 5, write); err != nil {
+		t.Fatalf("Issuing a token failed: %v", err)
+	}
+	if err := fencer.Guard("orders", 5, write); err != nil {
+		t.Fatalf("Write with the latest token failed: %v", err)
+	}
+	if err := fencer.Guard("orders", 6, write); !errors.Is(err, ErrUnissuedFencingToken) {
+		t.Errorf("Expected ErrUnissuedFencingToken for a token never issued, got %v", err)
+	}
+	if err := fencer.issue("orders", 6, write); err != nil {
+		t.Fatalf("Issuing a newer token failed: %v", err)
+	}
+	if err := fencer.Guard("orders", 5, write); !errors.Is(err, ErrStaleFencingToken) {
+		t.Errorf("Expected ErrStaleFencingToken, got %v", err)
+	}
+	if writes != 3 {
+		t.Errorf("Expected 3 writes to run, got %d", writes)
+	}
+
+	latest, err := fencer.Latest("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != 6 {
+		t.Errorf("Expected latest token 6, got %d", latest)
+	}
+
+	// Locks are independent of each other, and a lock that never issued a
+	// token accepts none
+	for _, token := range []uint64{0, 1} {
+		if err := fencer.Guard("invoices", token, write); !errors.Is(err, ErrUnissuedFencingToken) {
+			t.Errorf("Write under another lock with token %d = %v, want ErrUnissuedFencingToken", token, err)
+		}
+	}
+
+	if err := fencer.Guard("", 1, write); err == nil {
+		t.Error("Expected error for empty lock name")
+	}
+
+	// A failed write does not roll back the recorded token
+	failing := errors.New("write failed")
+	if err := fencer.issue("orders", 7, func() error { return failing }); !errors.Is(err, failing) {
+		t.Errorf("Expected write error, got %v", err)
+	}
+	if latest, err := fencer.Latest("orders"); err != nil || latest != 7 {
+		t.Errorf("Latest() after a failed write = %d, %v; want 7", latest, err)
+	}
+}
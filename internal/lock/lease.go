@@ -0,0 +1,299 @@
+package lock
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// LeasePrefix is the internal key prefix under which the lease of each held lock is recorded.
+// It is reserved, so clients can only take, renew and release locks through the lock RPCs.
+const LeasePrefix = store.ReservedPrefix + "locks/lease/"
+
+const (
+	DefaultLeaseTTL       = 30 * time.Second // Lease TTL when none is requested
+	MaxLeaseTTL           = time.Hour        // Longest lease granted at once
+	DefaultExpiryInterval = 10 * time.Second // Time between removals of expired leases
+)
+
+// MetricExpiredLeases counts the leases removed by background expiry.
+const MetricExpiredLeases = "lock_expired_leases_total"
+
+// leaseHeaderSize is the size of a lease record before its owner: the token
+// and the expiry time in Unix nanoseconds.
+const leaseHeaderSize = 8 + 8
+
+var (
+	// ErrLockHeld is returned by Acquire while another lease on the lock is
+	// live.
+	ErrLockHeld = errors.New("lock is held")
+	// ErrNotHeld is returned by Renew and Release for a lease that expired,
+	// was released or was superseded.
+	ErrNotHeld = errors.New("lease is not held")
+	// ErrInvalidTTL is returned for a negative TTL or one above MaxLeaseTTL.
+	ErrInvalidTTL = errors.New("invalid lease TTL")
+	// ErrCorruptLease is returned when a lease record cannot be decoded.
+	ErrCorruptLease = errors.New("corrupt lease record")
+	// ErrTokensExhausted is returned by Acquire once a lock has issued the
+	// largest fencing token.
+	ErrTokensExhausted = errors.New("fencing tokens exhausted")
+)
+
+// Lease is a lock held until it expires, unless renewed first. Its token is
+// the fencing token of the lock while it is held.
+type Lease struct {
+	Lock    string
+	Owner   string
+	Token   uint64
+	Expires time.Time
+}
+
+// Locker grants leases on locks, recorded in the store with conditional
+// writes so that at most one lease on each lock is live. Each lease carries a
+// fencing token greater than those of every earlier lease on its lock, which
+// the Fencer records as soon as it is granted.
+type Locker struct {
+	store  store.Store
+	fencer *Fencer
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// NewLocker creates a locker recording leases in s and their tokens with
+// fencer, which should record them in s as well.
+func NewLocker(s store.Store, fencer *Fencer) *Locker {
+	return &Locker{store: s, fencer: fencer, now: time.Now}
+}
+
+// Acquire grants owner a lease on lock for ttl, DefaultLeaseTTL if zero,
+// unless another lease on it is live. An expired lease is taken over, and
+// writes guarded by its token fail from then on.
+func (l *Locker) Acquire(lock, owner string, ttl time.Duration) (Lease, error) {
+	if lock == "" {
+		return Lease{}, errors.New("lock name cannot be empty")
+	}
+	ttl, err := leaseTTL(ttl)
+	if err != nil {
+		return Lease{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current, version, found, err := l.read(lock)
+	if err != nil {
+		return Lease{}, err
+	}
+	now := l.now()
+	if found && now.Before(current.Expires) {
+		return Lease{}, fmt.Errorf("%w: lock %s is held by %q until %s", ErrLockHeld, lock, current.Owner, current.Expires.Format(time.RFC3339Nano))
+	}
+
+	latest, err := l.fencer.Latest(lock)
+	if err != nil {
+		return Lease{}, err
+	}
+	previous := max(latest, current.Token)
+	if previous == math.MaxUint64 {
+		return Lease{}, fmt.Errorf("%w: lock %s", ErrTokensExhausted, lock)
+	}
+	lease := Lease{Lock: lock, Owner: owner, Token: previous + 1, Expires: now.Add(ttl)}
+	// The token is recorded first, so the expired holder is fenced off even
+	// if the lease cannot be written
+	err = l.fencer.issue(lock, lease.Token, func() error { return l.write(lease, version) })
+	if err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Renew extends the lease on lock with token to ttl from now, DefaultLeaseTTL
+// if zero. A lease that expired cannot be renewed, even if it was not taken
+// over yet.
+func (l *Locker) Renew(lock string, token uint64, ttl time.Duration) (Lease, error) {
+	ttl, err := leaseTTL(ttl)
+	if err != nil {
+		return Lease{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lease, version, err := l.held(lock, token)
+	if err != nil {
+		return Lease{}, err
+	}
+	lease.Expires = l.now().Add(ttl)
+	if err := l.write(lease, version); err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Release ends the lease on lock with token, so the lock can be acquired
+// again at once. The token stays recorded, so writes guarded by it still
+// succeed until a newer lease is granted.
+func (l *Locker) Release(lock string, token uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, _, err := l.held(lock, token); err != nil {
+		return err
+	}
+	return l.store.Delete(LeasePrefix + lock)
+}
+
+// Expire removes the leases expired at now, as expired keys, and returns how
+// many were removed.
+func (l *Locker) Expire(now time.Time) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pairs, err := l.store.Scan(LeasePrefix)
+	if err != nil {
+		return 0, err
+	}
+	var expired []string
+	for key, record := range pairs {
+		lease, err := decodeLease(key[len(LeasePrefix):], record)
+		if err == nil && !now.Before(lease.Expires) {
+			expired = append(expired, key)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := store.ExpireKeys(l.store, expired); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// ExpiryConfig configures background expiry of leases.
+type ExpiryConfig struct {
+	Interval time.Duration     // Time between expiries; DefaultExpiryInterval if zero
+	Metrics  *metrics.Registry // Registry for expiry metrics; metrics.Default if nil
+	Logger   *slog.Logger      // Logger for failed expiries; slog.Default() if nil
+}
+
+// StartExpiry removes expired leases in the background until the returned
+// function is called. Expired leases are taken over whether or not they were
+// removed; removing them keeps the store from filling up with abandoned locks.
+func (l *Locker) StartExpiry(config ExpiryConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultExpiryInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	expiredLeases := registry.Counter(MetricExpiredLeases)
+	logger := logging.Or(config.Logger)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				n, err := l.Expire(now)
+				if err != nil {
+					logger.Warn("Lease expiry failed", "error", err)
+				}
+				expiredLeases.Add(uint64(n)) // #nosec G115 -- counts are non-negative
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
+
+// held returns the live lease on lock with token and the version of its
+// record, or ErrNotHeld.
+func (l *Locker) held(lock string, token uint64) (Lease, uint64, error) {
+	lease, version, found, err := l.read(lock)
+	if err != nil {
+		return Lease{}, 0, err
+	}
+	if !found || lease.Token != token || !l.now().Before(lease.Expires) {
+		return Lease{}, 0, fmt.Errorf("%w: lock %s token %d", ErrNotHeld, lock, token)
+	}
+	return lease, version, nil
+}
+
+// read returns the lease recorded for lock with the version of its record.
+// Leases are written conditionally, so the store must read versions.
+func (l *Locker) read(lock string) (Lease, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](l.store)
+	if !ok {
+		return Lease{}, 0, false, store.ErrConditionalPutUnsupported
+	}
+	record, version, found, err := reader.GetAt(LeasePrefix+lock, 0)
+	if err != nil || !found {
+		return Lease{}, 0, false, err
+	}
+	lease, err := decodeLease(lock, record)
+	if err != nil {
+		return Lease{}, 0, false, err
+	}
+	return lease, version, true, nil
+}
+
+// write records lease if its record is still at version, 0 if it must not
+// exist. A conflict means the lock changed hands meanwhile.
+func (l *Locker) write(lease Lease, version uint64) error {
+	err := store.PutIfVersion(l.store, LeasePrefix+lease.Lock, encodeLease(lease), version)
+	if errors.Is(err, store.ErrVersionConflict) {
+		return fmt.Errorf("%w: lock %s changed while being acquired", ErrLockHeld, lease.Lock)
+	}
+	return err
+}
+
+// leaseTTL returns the TTL of a lease requested for ttl.
+func leaseTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return DefaultLeaseTTL, nil
+	case ttl < 0 || ttl > MaxLeaseTTL:
+		return 0, fmt.Errorf("%w: %s is not in (0, %s]", ErrInvalidTTL, ttl, MaxLeaseTTL)
+	}
+	return ttl, nil
+}
+
+// encodeLease returns the record of lease.
+func encodeLease(lease Lease) []byte {
+	record := make([]byte, leaseHeaderSize, leaseHeaderSize+len(lease.Owner))
+	binary.BigEndian.PutUint64(record, lease.Token)
+	binary.BigEndian.PutUint64(record[8:], uint64(lease.Expires.UnixNano())) // #nosec G115 -- two's complement round trip
+	return append(record, lease.Owner...)
+}
+
+// decodeLease returns the lease on lock recorded in record.
+func decodeLease(lock string, record []byte) (Lease, error) {
+	if len(record) < leaseHeaderSize {
+		return Lease{}, fmt.Errorf("%w: lock %s", ErrCorruptLease, lock)
+	}
+	return Lease{
+		Lock:    lock,
+		Owner:   string(record[leaseHeaderSize:]),
+		Token:   binary.BigEndian.Uint64(record),
+		Expires: time.Unix(0, int64(binary.BigEndian.Uint64(record[8:]))), // #nosec G115 -- two's complement round trip
+	}, nil
+}
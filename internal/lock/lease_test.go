@@ -0,0 +1,143 @@
+package lock
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newLocker(t *testing.T) (*Locker, *Fencer, *time.Time) {
+	t.Helper()
+	s, err := badger.NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	fencer := NewFencer(s)
+	l := NewLocker(s, fencer)
+	now := time.Unix(1700000000, 0)
+	l.now = func() time.Time { return now }
+	return l, fencer, &now
+}
+
+func TestLocker_Acquire(t *testing.T) {
+	l, fencer, now := newLocker(t)
+
+	first, err := l.Acquire("orders", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Token != 1 || first.Owner != "worker-1" || !first.Expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("Acquire() = %+v, want token 1 for a minute", first)
+	}
+	if _, err := l.Acquire("orders", "worker-2", 0); !errors.Is(err, ErrLockHeld) {
+		t.Errorf("Expected ErrLockHeld while the lease is live, got %v", err)
+	}
+	if lease, err := l.Acquire("invoices", "worker-2", 0); err != nil || !lease.Expires.Equal(now.Add(DefaultLeaseTTL)) {
+		t.Errorf("Acquire() of another lock = %+v, %v; want the default TTL", lease, err)
+	}
+
+	// An expired lease is taken over and its holder fenced off
+	*now = now.Add(time.Minute)
+	second, err := l.Acquire("orders", "worker-2", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Token <= first.Token {
+		t.Errorf("Token = %d, want it greater than %d", second.Token, first.Token)
+	}
+	if err := fencer.Guard("orders", first.Token, func() error { return nil }); !errors.Is(err, ErrStaleFencingToken) {
+		t.Errorf("Expected the expired holder to be fenced off, got %v", err)
+	}
+
+	// Tokens keep increasing across releases
+	if err := l.Release("orders", second.Token); err != nil {
+		t.Fatal(err)
+	}
+	third, err := l.Acquire("orders", "worker-1", time.Minute)
+	if err != nil || third.Token <= second.Token {
+		t.Errorf("Acquire() after a release = %+v, %v; want a token greater than %d", third, err, second.Token)
+	}
+
+	// The largest token is never wrapped around to reuse smaller ones
+	if err := fencer.issue("exhausted", math.MaxUint64, func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire("exhausted", "worker-1", time.Minute); !errors.Is(err, ErrTokensExhausted) {
+		t.Errorf("Expected ErrTokensExhausted after the largest token, got %v", err)
+	}
+
+	for _, ttl := range []time.Duration{-time.Second, MaxLeaseTTL + 1} {
+		if _, err := l.Acquire("other", "worker-1", ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("Expected ErrInvalidTTL for %s, got %v", ttl, err)
+		}
+	}
+}
+
+func TestLocker_RenewRelease(t *testing.T) {
+	l, _, now := newLocker(t)
+	lease, err := l.Acquire("orders", "worker-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	*now = now.Add(50 * time.Second)
+	renewed, err := l.Renew("orders", lease.Token, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.Token != lease.Token || !renewed.Expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("Renew() = %+v, want the same token for another minute", renewed)
+	}
+	if _, err := l.Renew("orders", lease.Token+1, 0); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld for another token, got %v", err)
+	}
+	if err := l.Release("orders", lease.Token+1); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld releasing with another token, got %v", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := l.Renew("orders", lease.Token, 0); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected an expired lease not to be renewed, got %v", err)
+	}
+	if err := l.Release("orders", lease.Token); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected an expired lease not to be released, got %v", err)
+	}
+}
+
+func TestLocker_Expire(t *testing.T) {
+	l, _, now := newLocker(t)
+	if _, err := l.Acquire("short", "worker-1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire("long", "worker-1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := l.Expire(now.Add(time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("Expire() = %d, %v; want 1 lease removed", n, err)
+	}
+	if found, _ := store.Exists(l.store, LeasePrefix+"short"); found {
+		t.Error("Expected the expired lease to be removed")
+	}
+	if found, _ := store.Exists(l.store, LeasePrefix+"long"); !found {
+		t.Error("Expected the live lease to be kept")
+	}
+}
+
+func TestLocker_Unversioned(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLocker(s, NewFencer(s))
+	if _, err := l.Acquire("orders", "worker-1", 0); !errors.Is(err, store.ErrConditionalPutUnsupported) {
+		t.Errorf("Expected ErrConditionalPutUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package logging builds the structured logger shared by the server and its
+// subsystems, and carries request-scoped loggers through contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Format selects how log records are encoded.
+type Format string
+
+const (
+	FormatText Format = "text" // key=value pairs, for people
+	FormatJSON Format = "json" // one JSON object per record, for log pipelines
+)
+
+// Config configures a logger.
+type Config struct {
+	Level  slog.Leveler // Minimum level logged, such as a *slog.LevelVar to change it at runtime; slog.LevelInfo if nil
+	Format Format       // Record encoding; FormatText if empty
+}
+
+// New returns a logger writing records at or above the configured level to w.
+func New(w io.Writer, config Config) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: config.Level}
+	switch config.Format {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error",
+// optionally with an offset such as "info+2".
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// Or returns logger, or slog.Default() if it is nil.
+func Or(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, which is annotated with the
+// fields of the request being served, or slog.Default() if there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
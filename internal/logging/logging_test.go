@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Config{Level: slog.LevelWarn, Format: FormatJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("dropped")
+	logger.Warn("kept", "key", "a/b")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "kept" || record["key"] != "a/b" {
+		t.Errorf("Unexpected record %v", record)
+	}
+
+	buf.Reset()
+	logger, err = New(&buf, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("hello", "n", 1)
+	if !strings.Contains(buf.String(), "msg=hello n=1") {
+		t.Errorf("Expected a text record, got %q", buf.String())
+	}
+
+	if _, err := New(&buf, Config{Format: "xml"}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error+1": slog.LevelError + 1} {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("Expected the default logger without a request logger")
+	}
+	logger := slog.New(slog.DiscardHandler)
+	if FromContext(WithLogger(context.Background(), logger)) != logger {
+		t.Error("Expected the logger carried by the context")
+	}
+	if Or(nil) != slog.Default() || Or(logger) != logger {
+		t.Error("Or should fall back to the default logger only when nil")
+	}
+}
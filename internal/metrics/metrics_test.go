@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	t.Run("CountersAreShared", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Counter("requests").Inc()
+			}()
+		}
+		wg.Wait()
+		r.Counter("requests").Add(5)
+
+		if got := r.Counter("requests").Value(); got != 15 {
+			t.Errorf("Expected 15, got %d", got)
+		}
+	})
+
+	t.Run("Gauge", func(t *testing.T) {
+		g := r.Gauge("in_flight")
+		g.Add(3)
+		g.Add(-1)
+		if g.Value() != 2 {
+			t.Errorf("Expected 2, got %d", g.Value())
+		}
+		g.Set(10)
+		if r.Gauge("in_flight").Value() != 10 {
+			t.Errorf("Expected 10, got %d", g.Value())
+		}
+	})
+
+	t.Run("Histogram", func(t *testing.T) {
+		h := r.Histogram("latency", []float64{1, 0.1})
+		for _, v := range []float64{0.05, 0.5, 5} {
+			h.Observe(v)
+		}
+		if h.Count() != 3 {
+			t.Errorf("Expected 3 observations, got %d", h.Count())
+		}
+		if h.Sum() != 5.55 {
+			t.Errorf("Expected sum 5.55, got %f", h.Sum())
+		}
+		for i, expected := range []uint64{1, 1, 1} {
+			if got := h.counts[i].Load(); got != expected {
+				t.Errorf("Bucket %d: expected %d, got %d", i, expected, got)
+			}
+		}
+	})
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("requests").Add(3)
+	r.Gauge("in_flight").Set(-2)
+	h := r.Histogram("latency", []float64{0.1, 1})
+	for _, v := range []float64{0.05, 0.5, 0.7, 5} {
+		h.Observe(v)
+	}
+
+	s := r.Snapshot()
+	if s.Counters["requests"] != 3 || s.Gauges["in_flight"] != -2 {
+		t.Errorf("Unexpected counters or gauges: %+v %+v", s.Counters, s.Gauges)
+	}
+	hs := s.Histograms["latency"]
+	want := []Bucket{{0.1, 1}, {1, 3}, {math.Inf(1), 4}}
+	if !reflect.DeepEqual(hs.Buckets, want) || hs.Count != 4 || hs.Sum != 6.25 {
+		t.Errorf("Unexpected histogram snapshot: %+v", hs)
+	}
+
+	r.Counter("requests").Inc()
+	if s.Counters["requests"] != 3 {
+		t.Error("Snapshot changed after the registry was updated")
+	}
+}
+
+func TestCollectors(t *testing.T) {
+	r := NewRegistry()
+	var calls int64
+	r.AddCollector(func(r *Registry) {
+		calls++
+		r.Gauge("sampled").Set(calls)
+	})
+	r.AddCollector(CollectRuntime)
+
+	if got := r.Snapshot().Gauges["sampled"]; got != 1 {
+		t.Errorf("Expected the collector to run before the first snapshot, got %d", got)
+	}
+	s := r.Snapshot()
+	if s.Gauges["sampled"] != 2 {
+		t.Errorf("Expected the collector to run on every snapshot, got %d", s.Gauges["sampled"])
+	}
+	if s.Gauges[MetricGoroutines] < 1 {
+		t.Errorf("Expected at least one goroutine, got %d", s.Gauges[MetricGoroutines])
+	}
+	if s.Gauges[MetricHeapAllocBytes] <= 0 {
+		t.Errorf("Expected a positive heap size, got %d", s.Gauges[MetricHeapAllocBytes])
+	}
+}
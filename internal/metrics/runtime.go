@@ -0,0 +1,47 @@
+package metrics
+
+import "runtime"
+
+// Go runtime metrics set by CollectRuntime.
+const (
+	MetricGoroutines     = "go_goroutines"
+	MetricHeapAllocBytes = "go_heap_alloc_bytes"
+	MetricHeapInuseBytes = "go_heap_inuse_bytes"
+	MetricHeapObjects    = "go_heap_objects"
+	MetricSysBytes       = "go_sys_bytes"
+	MetricGCCycles       = "go_gc_cycles"
+	MetricGCPauseTotalNs = "go_gc_pause_total_ns"
+	MetricGCPauseLastNs  = "go_gc_pause_last_ns"
+	MetricGCPauseMaxNs   = "go_gc_pause_recent_max_ns"
+	MetricGCCPUPermille  = "go_gc_cpu_permille"
+)
+
+// recentGCPauseSamples is the length of the runtime.MemStats.PauseNs ring.
+const recentGCPauseSamples = 256
+
+// CollectRuntime is a Collector that samples goroutine, heap and garbage
+// collector statistics. Reading them briefly stops the world, which is cheap
+// at the rate snapshots are taken.
+func CollectRuntime(r *Registry) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	r.Gauge(MetricGoroutines).Set(int64(runtime.NumGoroutine()))
+	r.Gauge(MetricHeapAllocBytes).Set(int64(m.HeapAlloc)) // #nosec G115 -- heap sizes fit in int64
+	r.Gauge(MetricHeapInuseBytes).Set(int64(m.HeapInuse)) // #nosec G115 -- heap sizes fit in int64
+	r.Gauge(MetricHeapObjects).Set(int64(m.HeapObjects))  // #nosec G115 -- object counts fit in int64
+	r.Gauge(MetricSysBytes).Set(int64(m.Sys))             // #nosec G115 -- memory sizes fit in int64
+	r.Gauge(MetricGCCycles).Set(int64(m.NumGC))
+	r.Gauge(MetricGCPauseTotalNs).Set(int64(m.PauseTotalNs)) // #nosec G115 -- pause totals fit in int64
+	r.Gauge(MetricGCCPUPermille).Set(int64(m.GCCPUFraction * 1000))
+
+	var last, longest uint64
+	if m.NumGC > 0 {
+		last = m.PauseNs[(m.NumGC+recentGCPauseSamples-1)%recentGCPauseSamples]
+	}
+	for _, pause := range m.PauseNs {
+		longest = max(longest, pause)
+	}
+	r.Gauge(MetricGCPauseLastNs).Set(int64(last))   // #nosec G115 -- pauses fit in int64
+	r.Gauge(MetricGCPauseMaxNs).Set(int64(longest)) // #nosec G115 -- pauses fit in int64
+}
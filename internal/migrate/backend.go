@@ -0,0 +1,36 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+// Backends lists the kinds of store Open knows, as written before the colon
+// of a spec.
+var Backends = []string{"badger", "memory"}
+
+// Open opens the store described by spec: "badger:<path>" for a BadgerDB
+// directory, which no server may have open, or "memory:" for an empty
+// in-memory store, such as to check that a store can be read in full.
+func Open(spec string) (store.Store, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "badger":
+		if arg == "" {
+			return nil, fmt.Errorf("invalid store %q: badger needs a path, as in badger:/var/lib/clavis", spec)
+		}
+		config := badger.DefaultConfig(arg)
+		// Copies are checked by Verify and can be run again, so batches need
+		// not wait for the disk
+		config.SyncWrites = false
+		return badger.New(config)
+	case "memory":
+		return memory.NewWithDefaults()
+	default:
+		return nil, fmt.Errorf("invalid store %q: the backend must be one of %s", spec, strings.Join(Backends, ", "))
+	}
+}
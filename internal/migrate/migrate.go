@@ -0,0 +1,338 @@
+// Package migrate copies every key of one store into another, such as from
+// one backend to another, and verifies that the two hold the same data.
+// Only the latest value of each key is copied; version history and expiry
+// times stay behind.
+package migrate
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	DefaultBatchSize        = 1000        // Pairs written to the destination at once
+	DefaultConcurrency      = 4           // Batches written at once
+	DefaultProgressInterval = time.Second // Time between progress reports
+	maxExamples             = 10          // Differing keys a Report lists
+)
+
+// ErrDestinationNotEmpty is returned by Copy into a store that holds keys.
+var ErrDestinationNotEmpty = errors.New("destination store is not empty")
+
+// Config controls how pairs are copied.
+type Config struct {
+	BatchSize   int // Pairs written at once; DefaultBatchSize if zero
+	Concurrency int // Batches written at once; DefaultConcurrency if zero
+
+	// Progress, if set, is called every ProgressInterval, or
+	// DefaultProgressInterval if zero, and once copying is done
+	Progress         func(Progress)
+	ProgressInterval time.Duration
+}
+
+// Progress reports how far a copy got.
+type Progress struct {
+	Keys    int64 // Pairs read from the source
+	Bytes   int64 // Size of their keys and values
+	Elapsed time.Duration
+}
+
+// Copy copies every pair of src into dst, which must hold no key. The source
+// is read in key order; destinations implementing store.Loader ingest the
+// pairs in a single load, and others get them in batches written by
+// Concurrency workers. A failed copy leaves the batches already written.
+func Copy(ctx context.Context, dst, src store.Store, config Config) (Progress, error) {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.ProgressInterval <= 0 {
+		config.ProgressInterval = DefaultProgressInterval
+	}
+	keys, err := store.Keys(dst, store.KeyRange{}, 1)
+	if err != nil {
+		return Progress{}, err
+	}
+	if len(keys) > 0 {
+		return Progress{}, ErrDestinationNotEmpty
+	}
+
+	var w writer
+	if _, ok := dst.(store.Loader); ok {
+		load, err := store.NewLoad(dst, config.BatchSize)
+		if err != nil {
+			return Progress{}, err
+		}
+		w = &loadWriter{load: load}
+	} else {
+		w = newBatchWriter(ctx, dst, config)
+	}
+
+	start := time.Now()
+	progress, err := readAll(ctx, src, w, config, start)
+	if err != nil {
+		w.cancel()
+		return progress, err
+	}
+	if err := w.finish(); err != nil {
+		return progress, err
+	}
+	progress.Elapsed = time.Since(start)
+	if config.Progress != nil {
+		config.Progress(progress)
+	}
+	return progress, nil
+}
+
+// readAll hands every pair of src to w, reporting progress on the way.
+func readAll(ctx context.Context, src store.Store, w writer, config Config, start time.Time) (progress Progress, err error) {
+	it, err := store.NewIterator(src, "")
+	if err != nil {
+		return progress, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	lastReport := start
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+		key, value := it.Key(), it.Value()
+		if err := w.add(key, value); err != nil {
+			return progress, err
+		}
+		progress.Keys++
+		progress.Bytes += int64(len(key) + len(value))
+		if config.Progress != nil && time.Since(lastReport) >= config.ProgressInterval {
+			progress.Elapsed = time.Since(start)
+			config.Progress(progress)
+			lastReport = time.Now()
+		}
+	}
+	return progress, it.Err()
+}
+
+// writer writes the pairs read from the source to the destination.
+type writer interface {
+	add(key string, value []byte) error
+	finish() error
+	cancel()
+}
+
+// loadWriter writes pairs with a store.Load.
+type loadWriter struct {
+	load store.Load
+}
+
+func (w *loadWriter) add(key string, value []byte) error { return w.load.Add(key, value) }
+func (w *loadWriter) finish() error                      { return w.load.Finish() }
+func (w *loadWriter) cancel()                            { w.load.Cancel() }
+
+// batchWriter hands batches of pairs to workers writing them with
+// store.BatchPut. The first failed batch stops the others.
+type batchWriter struct {
+	batches chan map[string][]byte
+	batch   map[string][]byte
+	size    int
+	wg      sync.WaitGroup
+	ctx     context.Context
+	stop    context.CancelFunc
+
+	mu  sync.Mutex
+	err error
+}
+
+func newBatchWriter(ctx context.Context, dst store.Store, config Config) *batchWriter {
+	w := &batchWriter{
+		batches: make(chan map[string][]byte, config.Concurrency),
+		size:    config.BatchSize,
+	}
+	w.ctx, w.stop = context.WithCancel(ctx)
+	for range config.Concurrency {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for batch := range w.batches {
+				if w.ctx.Err() != nil {
+					continue
+				}
+				if err := store.BatchPut(dst, batch); err != nil {
+					w.fail(err)
+				}
+			}
+		}()
+	}
+	return w
+}
+
+func (w *batchWriter) add(key string, value []byte) error {
+	if w.batch == nil {
+		w.batch = make(map[string][]byte, w.size)
+	}
+	w.batch[key] = value
+	if len(w.batch) < w.size {
+		return nil
+	}
+	return w.send()
+}
+
+// send hands the current batch to the workers.
+func (w *batchWriter) send() error {
+	select {
+	case w.batches <- w.batch:
+		w.batch = nil
+		return nil
+	case <-w.ctx.Done():
+		return w.failure()
+	}
+}
+
+func (w *batchWriter) finish() error {
+	var err error
+	if len(w.batch) > 0 {
+		err = w.send()
+	}
+	close(w.batches)
+	w.wg.Wait()
+	w.mu.Lock()
+	failure := w.err
+	w.mu.Unlock()
+	w.stop()
+	if failure != nil {
+		return failure
+	}
+	return err
+}
+
+func (w *batchWriter) cancel() {
+	w.stop()
+	close(w.batches)
+	w.wg.Wait()
+}
+
+// fail records the first error of a worker and stops the copy.
+func (w *batchWriter) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+		w.stop()
+	}
+}
+
+// failure returns the first error of a worker, or the reason the copy was
+// stopped otherwise.
+func (w *batchWriter) failure() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	return w.ctx.Err()
+}
+
+// Report is the outcome of Verify.
+type Report struct {
+	Keys                int64  // Keys of the source
+	SourceChecksum      uint64 // Checksum of every pair of the source, in key order
+	DestinationChecksum uint64 // Same for the destination
+	Missing             int64  // Keys of the source absent from the destination
+	Extra               int64  // Keys of the destination absent from the source
+	Different           int64  // Keys whose values differ
+	// Examples lists up to 10 of the keys missing, extra or different
+	Examples []string
+}
+
+// OK reports whether both stores hold the same pairs.
+func (r Report) OK() bool {
+	return r.Missing == 0 && r.Extra == 0 && r.Different == 0
+}
+
+// Verify walks src and dst side by side in key order, checksumming every
+// pair of each, and reports the keys they disagree on.
+func Verify(ctx context.Context, dst, src store.Store) (report Report, err error) {
+	srcIt, err := store.NewIterator(src, "")
+	if err != nil {
+		return report, err
+	}
+	defer func() {
+		if closeErr := srcIt.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	dstIt, err := store.NewIterator(dst, "")
+	if err != nil {
+		return report, err
+	}
+	defer func() {
+		if closeErr := dstIt.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	srcDigest, dstDigest := xxhash.New(), xxhash.New()
+	differ := func(key, what string) {
+		if len(report.Examples) < maxExamples {
+			report.Examples = append(report.Examples, fmt.Sprintf("%s: %q", what, key))
+		}
+	}
+	srcOK, dstOK := srcIt.Next(), dstIt.Next()
+	for srcOK || dstOK {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		switch {
+		case !dstOK || (srcOK && srcIt.Key() < dstIt.Key()):
+			report.Keys++
+			report.Missing++
+			differ(srcIt.Key(), "missing")
+			digest(srcDigest, srcIt.Key(), srcIt.Value())
+			srcOK = srcIt.Next()
+		case !srcOK || dstIt.Key() < srcIt.Key():
+			report.Extra++
+			differ(dstIt.Key(), "extra")
+			digest(dstDigest, dstIt.Key(), dstIt.Value())
+			dstOK = dstIt.Next()
+		default:
+			report.Keys++
+			srcSum := digest(srcDigest, srcIt.Key(), srcIt.Value())
+			if digest(dstDigest, dstIt.Key(), dstIt.Value()) != srcSum {
+				report.Different++
+				differ(srcIt.Key(), "different")
+			}
+			srcOK, dstOK = srcIt.Next(), dstIt.Next()
+		}
+	}
+	if err := errors.Join(srcIt.Err(), dstIt.Err()); err != nil {
+		return report, err
+	}
+	report.SourceChecksum, report.DestinationChecksum = srcDigest.Sum64(), dstDigest.Sum64()
+	return report, nil
+}
+
+// digest adds a pair to the checksum of its store, each part prefixed with its
+// length so pairs cannot run into each other, and returns the checksum of the
+// value alone.
+func digest(d *xxhash.Digest, key string, value []byte) uint64 {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(key))) // #nosec G115 -- lengths are non-negative
+	_, _ = d.Write(length[:])
+	_, _ = d.WriteString(key)
+	binary.BigEndian.PutUint64(length[:], uint64(len(value))) // #nosec G115 -- lengths are non-negative
+	_, _ = d.Write(length[:])
+	_, _ = d.Write(value)
+	return xxhash.Sum64(value)
+}
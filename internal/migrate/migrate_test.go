@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// openStore opens spec, closing it at the end of the test.
+func openStore(t *testing.T, spec string) store.Store {
+	t.Helper()
+	s, err := Open(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// fill stores n pairs in s.
+func fill(t *testing.T, s store.Store, n int) {
+	t.Helper()
+	pairs := make(map[string][]byte, n)
+	for i := range n {
+		pairs[fmt.Sprintf("key-%05d", i)] = []byte(fmt.Sprintf("value-%d", i))
+	}
+	if err := store.BatchPut(s, pairs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		src, dst func(t *testing.T) store.Store
+	}{
+		// A BadgerDB destination ingests the pairs in one load
+		{"MemoryToBadger", func(t *testing.T) store.Store { return openStore(t, "memory:") }, func(t *testing.T) store.Store {
+			return openStore(t, "badger:"+filepath.Join(t.TempDir(), "dst"))
+		}},
+		// Other destinations get concurrent batches
+		{"BadgerToMemory", func(t *testing.T) store.Store {
+			return openStore(t, "badger:"+filepath.Join(t.TempDir(), "src"))
+		}, func(t *testing.T) store.Store { return openStore(t, "memory:") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := tt.src(t), tt.dst(t)
+			fill(t, src, 2500)
+
+			var reports int
+			progress, err := Copy(ctx, dst, src, Config{BatchSize: 100, Concurrency: 3, Progress: func(Progress) { reports++ }})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if progress.Keys != 2500 || reports == 0 {
+				t.Errorf("Copy() = %+v with %d reports, want 2500 keys reported at least once", progress, reports)
+			}
+			report, err := Verify(ctx, dst, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !report.OK() || report.Keys != 2500 || report.SourceChecksum != report.DestinationChecksum {
+				t.Errorf("Verify() after Copy() = %+v, want identical stores", report)
+			}
+
+			if _, err := Copy(ctx, dst, src, Config{}); !errors.Is(err, ErrDestinationNotEmpty) {
+				t.Errorf("Expected ErrDestinationNotEmpty copying again, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	src, dst := openStore(t, "memory:"), openStore(t, "memory:")
+	fill(t, src, 10)
+	fill(t, dst, 10)
+	if err := src.Delete("key-00003"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Delete("key-00007"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Put("key-00005", []byte("changed")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Verify(ctx, dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || report.Missing != 1 || report.Extra != 1 || report.Different != 1 || report.Keys != 9 {
+		t.Errorf("Verify() = %+v, want one key missing, one extra and one different", report)
+	}
+	if report.SourceChecksum == report.DestinationChecksum {
+		t.Error("Expected the checksums of different stores to differ")
+	}
+	want := []string{`extra: "key-00003"`, `different: "key-00005"`, `missing: "key-00007"`}
+	if fmt.Sprint(report.Examples) != fmt.Sprint(want) {
+		t.Errorf("Examples = %q, want %q", report.Examples, want)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	for _, spec := range []string{"badger:", "bolt:/tmp/x", "nothing"} {
+		if _, err := Open(spec); err == nil {
+			t.Errorf("Expected Open(%q) to fail", spec)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"context"
+	"time"
+)
+
+// AsyncValidator checks a single value with work that may block, such as a
+// lookup in an external service, returning nil when it is valid. It should
+// return once ctx is done.
+type AsyncValidator[T any] interface {
+	ValidateAsync(ctx context.Context, vctx Context, value T) *ValidationError
+}
+
+// AsyncValidatorFunc adapts a function to the AsyncValidator interface.
+type AsyncValidatorFunc[T any] func(ctx context.Context, vctx Context, value T) *ValidationError
+
+// ValidateAsync calls f.
+func (f AsyncValidatorFunc[T]) ValidateAsync(ctx context.Context, vctx Context, value T) *ValidationError {
+	return f(ctx, vctx, value)
+}
+
+// Async adapts a validator to run in an AsyncChain.
+func Async[T any](v Validator[T]) AsyncValidator[T] {
+	return AsyncValidatorFunc[T](func(_ context.Context, vctx Context, value T) *ValidationError {
+		return v.Validate(vctx, value)
+	})
+}
+
+// softValidator marks the failures of a validator as advisory.
+type softValidator[T any] struct {
+	AsyncValidator[T]
+}
+
+// Soft marks the failures of v as soft: they are reported, but do not cancel
+// the other validators of an AsyncChain, and a hard failure does not cancel v.
+func Soft[T any](v AsyncValidator[T]) AsyncValidator[T] {
+	return softValidator[T]{v}
+}
+
+// AsyncChain runs validators in parallel and collects their failures in the
+// order of the validators. The first hard failure, from any validator not
+// marked Soft, cancels the other hard validators still running, whose results
+// are dropped; soft validators are still waited for. A nil chain accepts
+// everything.
+type AsyncChain[T any] struct {
+	validators []AsyncValidator[T]
+	timeout    time.Duration
+}
+
+// ParallelChain returns a chain running validators in parallel. Validators
+// that have not finished after timeout fail with CodeTimeout; zero leaves
+// them bounded by the context alone.
+func ParallelChain[T any](timeout time.Duration, validators ...AsyncValidator[T]) *AsyncChain[T] {
+	return &AsyncChain[T]{validators: validators, timeout: timeout}
+}
+
+// Validate runs every validator of the chain against value and waits for
+// them, or only for the soft ones after the first hard failure, until ctx or
+// the timeout of the chain ends. Validators that have not finished by then
+// fail with CodeTimeout.
+func (c *AsyncChain[T]) Validate(ctx context.Context, vctx Context, value T) ValidationResult {
+	var result ValidationResult
+	if c == nil || len(c.validators) == 0 {
+		return result
+	}
+	var cancel context.CancelFunc
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	// Soft validators run until they finish even after a hard failure, so
+	// their failures are reported along with it
+	hardCtx, cancelHard := context.WithCancel(ctx)
+	defer cancelHard()
+
+	type outcome struct {
+		index int
+		err   *ValidationError
+	}
+	// Buffered so validators still running after a hard failure can exit
+	outcomes := make(chan outcome, len(c.validators))
+	for i, v := range c.validators {
+		run := hardCtx
+		if c.soft(i) {
+			run = ctx
+		}
+		go func() {
+			outcomes <- outcome{index: i, err: v.ValidateAsync(run, vctx, value)}
+		}()
+	}
+
+	errs := make([]*ValidationError, len(c.validators))
+	waiting := make([]bool, len(c.validators))
+	for i := range waiting {
+		waiting[i] = true
+	}
+	remaining := len(c.validators)
+wait:
+	for remaining > 0 {
+		select {
+		case o := <-outcomes:
+			if !waiting[o.index] {
+				continue
+			}
+			errs[o.index], waiting[o.index] = o.err, false
+			remaining--
+			if o.err != nil && !c.soft(o.index) {
+				cancelHard()
+				for i := range waiting {
+					if waiting[i] && !c.soft(i) {
+						waiting[i] = false
+						remaining--
+					}
+				}
+			}
+		case <-ctx.Done():
+			for i := range waiting {
+				if waiting[i] {
+					errs[i] = NewError(vctx, CodeTimeout, "did not finish: %v", ctx.Err())
+				}
+			}
+			break wait
+		}
+	}
+
+	for _, err := range errs {
+		result.Add(err)
+	}
+	return result
+}
+
+// soft reports whether the i-th validator of the chain is marked Soft.
+func (c *AsyncChain[T]) soft(i int) bool {
+	_, soft := c.validators[i].(softValidator[T])
+	return soft
+}
+
+// Len returns the number of validators in the chain.
+func (c *AsyncChain[T]) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.validators)
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// itemContext returns ctx pointing at the item of a slice at index, such as
+// "tags[2]".
+func itemContext(ctx Context, index int) Context {
+	return ctx.At(ctx.Target + "[" + strconv.Itoa(index) + "]")
+}
+
+// keyContext returns ctx pointing at the entry of a map under key, such as
+// "labels.env".
+func keyContext[K comparable](ctx Context, key K) Context {
+	if ctx.Target == "" {
+		return ctx.At(fmt.Sprint(key))
+	}
+	return ctx.At(ctx.Target + "." + fmt.Sprint(key))
+}
+
+// MinItems rejects slices with fewer than limit items.
+func MinItems[E any](limit int) Validator[[]E] {
+	return ValidatorFunc[[]E](func(ctx Context, value []E) *ValidationError {
+		if len(value) < limit {
+			return NewError(ctx, CodeTooShort, "too few items: %d is under %d", len(value), limit).
+				WithMetadata("limit", strconv.Itoa(limit))
+		}
+		return nil
+	})
+}
+
+// MaxItems rejects slices with more than limit items.
+func MaxItems[E any](limit int) Validator[[]E] {
+	return ValidatorFunc[[]E](func(ctx Context, value []E) *ValidationError {
+		if len(value) > limit {
+			return NewError(ctx, CodeTooLong, "too many items: %d exceeds %d", len(value), limit).
+				WithMetadata("limit", strconv.Itoa(limit))
+		}
+		return nil
+	})
+}
+
+// UniqueItems rejects slices holding an item more than once, targeting the
+// first repetition.
+func UniqueItems[E comparable]() Validator[[]E] {
+	return ValidatorFunc[[]E](func(ctx Context, value []E) *ValidationError {
+		seen := make(map[E]int, len(value))
+		for i, item := range value {
+			if first, ok := seen[item]; ok {
+				return NewError(itemContext(ctx, i), CodeDuplicate, "repeats item %d", first).
+					WithMetadata("first", strconv.Itoa(first))
+			}
+			seen[item] = i
+		}
+		return nil
+	})
+}
+
+// Each checks every item of a slice with inner, returning the failure of the
+// first invalid item, which targets it, with the number of invalid items in
+// its metadata.
+func Each[E any](inner Validator[E]) Validator[[]E] {
+	return ValidatorFunc[[]E](func(ctx Context, value []E) *ValidationError {
+		var first *ValidationError
+		failed := 0
+		for i, item := range value {
+			if err := inner.Validate(itemContext(ctx, i), item); err != nil {
+				if first == nil {
+					first = err
+				}
+				failed++
+			}
+		}
+		if first == nil {
+			return nil
+		}
+		return first.WithMetadata("failed_items", strconv.Itoa(failed))
+	})
+}
+
+// RequiredKeys rejects maps missing any of keys, targeting the first one
+// missing.
+func RequiredKeys[K comparable, V any](keys ...K) Validator[map[K]V] {
+	return ValidatorFunc[map[K]V](func(ctx Context, value map[K]V) *ValidationError {
+		for _, key := range keys {
+			if _, ok := value[key]; !ok {
+				return NewError(keyContext(ctx, key), CodeRequired, "is required")
+			}
+		}
+		return nil
+	})
+}
+
+// ForbiddenKeys rejects maps holding any of keys, targeting the first one
+// present.
+func ForbiddenKeys[K comparable, V any](keys ...K) Validator[map[K]V] {
+	return ValidatorFunc[map[K]V](func(ctx Context, value map[K]V) *ValidationError {
+		for _, key := range keys {
+			if _, ok := value[key]; ok {
+				return NewError(keyContext(ctx, key), CodeForbidden, "is not allowed")
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"cmp"
+	"time"
+)
+
+// The validators below compare the value with a field next to it, read from
+// Context.Fields. They accept the value when that field is missing or of
+// another type, which NotEmpty or the type of the field should rule out.
+
+// EqualsField rejects values different from the field called name, such as
+// a password confirmation.
+func EqualsField[T comparable](name string) Validator[T] {
+	return ValidatorFunc[T](func(ctx Context, value T) *ValidationError {
+		if other, ok := FieldAs[T](ctx, name); ok && value != other {
+			return NewError(ctx, CodeMismatch, "must equal %s", name).WithMetadata("field", name)
+		}
+		return nil
+	})
+}
+
+// GreaterThanField rejects values that are not greater than the field
+// called name.
+func GreaterThanField[T cmp.Ordered](name string) Validator[T] {
+	return ValidatorFunc[T](func(ctx Context, value T) *ValidationError {
+		if other, ok := FieldAs[T](ctx, name); ok && value <= other {
+			return NewError(ctx, CodeTooSmall, "must be greater than %s", name).WithMetadata("field", name)
+		}
+		return nil
+	})
+}
+
+// AfterField rejects times that are not after the field called name, such as
+// the end of a period before its start.
+func AfterField(name string) Validator[time.Time] {
+	return ValidatorFunc[time.Time](func(ctx Context, value time.Time) *ValidationError {
+		if other, ok := FieldAs[time.Time](ctx, name); ok && !value.After(other) {
+			return NewError(ctx, CodeTooSmall, "must be after %s", name).WithMetadata("field", name)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSchemaViolations bounds the violations reported for one document, so a
+// large invalid array does not produce an equally large error.
+const maxSchemaViolations = 20
+
+// Schema is a JSON Schema limited to the keywords that describe the shape of
+// a document: type, enum, properties, required, additionalProperties, items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum and maximum.
+// Other keywords are ignored.
+type Schema struct {
+	types      []string
+	enum       []any
+	properties map[string]*Schema
+	required   []string
+	additional *Schema // Schema of the properties not listed; nil allows any
+	closed     bool    // additionalProperties is false
+	items      *Schema
+
+	minItems, maxItems   *int
+	minLength, maxLength *int
+	minimum, maximum     *float64
+	pattern              *regexp.Regexp
+}
+
+// rawSchema is the JSON form of a Schema.
+type rawSchema struct {
+	Type                 json.RawMessage            `json:"type"`
+	Enum                 []any                      `json:"enum"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	Pattern              *string                    `json:"pattern"`
+}
+
+// schemaTypes are the names the type keyword accepts.
+var schemaTypes = []string{"null", "boolean", "number", "integer", "string", "array", "object"}
+
+// ParseSchema parses a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var raw rawSchema
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	s := &Schema{
+		enum:      raw.Enum,
+		required:  raw.Required,
+		minItems:  raw.MinItems,
+		maxItems:  raw.MaxItems,
+		minLength: raw.MinLength,
+		maxLength: raw.MaxLength,
+		minimum:   raw.Minimum,
+		maximum:   raw.Maximum,
+	}
+
+	if len(raw.Type) > 0 {
+		var name string
+		if err := json.Unmarshal(raw.Type, &name); err == nil {
+			s.types = []string{name}
+		} else if err := json.Unmarshal(raw.Type, &s.types); err != nil {
+			return nil, fmt.Errorf("invalid JSON schema type %s", raw.Type)
+		}
+		for _, name := range s.types {
+			if !slices.Contains(schemaTypes, name) {
+				return nil, fmt.Errorf("unknown JSON schema type %q", name)
+			}
+		}
+	}
+	if len(raw.Properties) > 0 {
+		s.properties = make(map[string]*Schema, len(raw.Properties))
+		for name, data := range raw.Properties {
+			property, err := ParseSchema(data)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", name, err)
+			}
+			s.properties[name] = property
+		}
+	}
+	if len(raw.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(raw.AdditionalProperties, &allowed); err == nil {
+			s.closed = !allowed
+		} else if s.additional, err = ParseSchema(raw.AdditionalProperties); err != nil {
+			return nil, fmt.Errorf("additionalProperties: %w", err)
+		}
+	}
+	if len(raw.Items) > 0 {
+		items, err := ParseSchema(raw.Items)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		s.items = items
+	}
+	if raw.Pattern != nil {
+		pattern, err := regexp.Compile(*raw.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON schema pattern: %w", err)
+		}
+		s.pattern = pattern
+	}
+	return s, nil
+}
+
+// Violations returns where document, as decoded by encoding/json into an
+// any, breaks the schema, as "<JSON pointer>: <reason>".
+func (s *Schema) Violations(document any) []string {
+	var violations []string
+	s.check("", document, &violations)
+	if len(violations) > maxSchemaViolations {
+		violations = append(violations[:maxSchemaViolations], fmt.Sprintf("and %d more", len(violations)-maxSchemaViolations))
+	}
+	return violations
+}
+
+func (s *Schema) check(path string, value any, violations *[]string) {
+	fail := func(path, format string, args ...any) {
+		if path == "" {
+			path = "/"
+		}
+		*violations = append(*violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(name string) bool { return hasType(value, name) }) {
+		fail(path, "must be of type %s", strings.Join(s.types, " or "))
+		return
+	}
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(allowed any) bool { return reflect.DeepEqual(allowed, value) }) {
+		fail(path, "must be one of the enumerated values")
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if s.minLength != nil && length < *s.minLength {
+			fail(path, "must be at least %d characters long", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail(path, "must be at most %d characters long", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			fail(path, "must match %s", s.pattern)
+		}
+	case float64:
+		if s.minimum != nil && value < *s.minimum {
+			fail(path, "must be at least %s", formatNumber(*s.minimum))
+		}
+		if s.maximum != nil && value > *s.maximum {
+			fail(path, "must be at most %s", formatNumber(*s.maximum))
+		}
+	case []any:
+		if s.minItems != nil && len(value) < *s.minItems {
+			fail(path, "must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(value) > *s.maxItems {
+			fail(path, "must have at most %d items", *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range value {
+				s.items.check(path+"/"+strconv.Itoa(i), item, violations)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := value[name]; !ok {
+				fail(path, "missing required property %q", name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			propertyPath := path + "/" + escapePointer(name)
+			switch property, ok := s.properties[name]; {
+			case ok:
+				property.check(propertyPath, value[name], violations)
+			case s.closed:
+				fail(propertyPath, "is not allowed")
+			case s.additional != nil:
+				s.additional.check(propertyPath, value[name], violations)
+			}
+		}
+	}
+}
+
+// hasType reports whether a decoded JSON value is of the named schema type.
+func hasType(value any, name string) bool {
+	switch value := value.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case float64:
+		return name == "number" || name == "integer" && value == math.Trunc(value)
+	case string:
+		return name == "string"
+	case []any:
+		return name == "array"
+	case map[string]any:
+		return name == "object"
+	default:
+		return false
+	}
+}
+
+// escapePointer escapes a property name as a JSON pointer token.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'g', -1, 64)
+}
+
+// SchemaError lists the violations of a document against a schema.
+type SchemaError struct {
+	Violations []string
+}
+
+// Error joins the violations.
+func (e *SchemaError) Error() string {
+	return "does not match the schema: " + strings.Join(e.Violations, "; ")
+}
+
+// Metadata returns each violation under "violation.<index>".
+func (e *SchemaError) Metadata() map[string]string {
+	metadata := make(map[string]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		metadata["violation."+strconv.Itoa(i)] = violation
+	}
+	return metadata
+}
+
+// JSONSchema accepts values that are JSON documents valid against schema.
+// The violations are reported in the metadata of the ValidationError.
+func JSONSchema(schema *Schema) ValueContentValidator {
+	return ValueContentValidator{Name: "json-schema", Check: func(value []byte) error {
+		var document any
+		if err := json.Unmarshal(value, &document); err != nil {
+			return err
+		}
+		if violations := schema.Violations(document); len(violations) > 0 {
+			return &SchemaError{Violations: violations}
+		}
+		return nil
+	}}
+}
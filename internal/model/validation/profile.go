@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Names of the built-in profiles.
+const (
+	ProfileStrict     = "strict"
+	ProfileStandard   = "standard"
+	ProfilePermissive = "permissive"
+)
+
+// Profile is a named set of checks applied to every write.
+type Profile struct {
+	Name  string
+	Key   *ValidatorChain[string]
+	Value *ValidatorChain[[]byte]
+}
+
+// Validate checks a key and the value written to it.
+func (p *Profile) Validate(ctx Context, key string, value []byte) ValidationResult {
+	result := p.Key.Validate(ctx.At("key"), key)
+	result.Merge(p.Value.Validate(ctx.At("value"), value))
+	return result
+}
+
+// strictKeyPattern limits strict keys to path-like ASCII names.
+var strictKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._~/:-]+$`)
+
+// StrictProfile accepts short path-like ASCII keys and UTF-8 values of up to
+// 64 KiB, for tenants that want to catch sloppy data early.
+func StrictProfile() *Profile {
+	return &Profile{
+		Name:  ProfileStrict,
+		Key:   Chain(NotEmpty(), MaxLength(256), MatchesPattern(strictKeyPattern)),
+		Value: Chain[[]byte](MaxSize(64<<10), UTF8()),
+	}
+}
+
+// StandardProfile accepts keys of up to 1 KiB and values of up to 1 MiB.
+func StandardProfile() *Profile {
+	return &Profile{
+		Name:  ProfileStandard,
+		Key:   Chain(NotEmpty(), MaxLength(1<<10)),
+		Value: Chain(MaxSize(1 << 20)),
+	}
+}
+
+// PermissiveProfile only enforces what the storage engine itself requires:
+// keys under 64000 bytes and values of up to 64 MiB.
+func PermissiveProfile() *Profile {
+	return &Profile{
+		Name:  ProfilePermissive,
+		Key:   Chain(NotEmpty(), MaxLength(64000)),
+		Value: Chain(MaxSize(64 << 20)),
+	}
+}
+
+// Profiles holds profiles by name.
+type Profiles map[string]*Profile
+
+// DefaultProfiles returns the built-in strict, standard and permissive
+// profiles.
+func DefaultProfiles() Profiles {
+	profiles := Profiles{}
+	for _, p := range []*Profile{StrictProfile(), StandardProfile(), PermissiveProfile()} {
+		profiles[p.Name] = p
+	}
+	return profiles
+}
+
+// Lookup returns the profile called name.
+func (p Profiles) Lookup(name string) (*Profile, error) {
+	profile, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown validation profile %q (known: %v)", name, p.names())
+	}
+	return profile, nil
+}
+
+func (p Profiles) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
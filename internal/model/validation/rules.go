@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rules declares the checks of a profile, such as read from a configuration
+// file, so limits can be tuned without recompiling.
+type Rules struct {
+	Key   KeyRules   `json:"key"`
+	Value ValueRules `json:"value"`
+}
+
+// KeyRules declares the checks of keys; keys are never allowed to be empty.
+// Zero limits are not checked.
+type KeyRules struct {
+	MinLength           int      `json:"min_length,omitempty"`
+	MaxLength           int      `json:"max_length,omitempty"`
+	Pattern             string   `json:"pattern,omitempty"`
+	AllowedPrefixes     []string `json:"allowed_prefixes,omitempty"`
+	UTF8                bool     `json:"utf8,omitempty"`
+	NFC                 bool     `json:"nfc,omitempty"`
+	NoControlCharacters bool     `json:"no_control_characters,omitempty"`
+}
+
+// ValueRules declares the checks of values. A zero MaxSize is not checked.
+type ValueRules struct {
+	MaxSize int    `json:"max_size,omitempty"`
+	Content string `json:"content,omitempty"` // "utf-8" or "json", if set
+}
+
+// Compile builds the profile called name from the rules, failing on negative
+// limits, invalid patterns and unknown content kinds.
+func (r Rules) Compile(name string) (*Profile, error) {
+	key := []Validator[string]{NotEmpty()}
+	if r.Key.MinLength < 0 || r.Key.MaxLength < 0 || r.Value.MaxSize < 0 {
+		return nil, fmt.Errorf("profile %s: limits cannot be negative", name)
+	}
+	if r.Key.MinLength > 0 {
+		key = append(key, MinLength(r.Key.MinLength))
+	}
+	if r.Key.MaxLength > 0 {
+		key = append(key, MaxLength(r.Key.MaxLength))
+	}
+	if r.Key.UTF8 {
+		key = append(key, ValidUTF8())
+	}
+	if r.Key.NoControlCharacters {
+		key = append(key, NoControlCharacters())
+	}
+	if r.Key.NFC {
+		key = append(key, NFC())
+	}
+	if len(r.Key.AllowedPrefixes) > 0 {
+		key = append(key, AllowedPrefixes(r.Key.AllowedPrefixes...))
+	}
+	if r.Key.Pattern != "" {
+		pattern, err := regexp.Compile(r.Key.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: invalid key pattern: %w", name, err)
+		}
+		key = append(key, MatchesPattern(pattern))
+	}
+
+	var value []Validator[[]byte]
+	if r.Value.MaxSize > 0 {
+		value = append(value, MaxSize(r.Value.MaxSize))
+	}
+	switch r.Value.Content {
+	case "":
+	case "utf-8":
+		value = append(value, UTF8())
+	case "json":
+		value = append(value, JSON())
+	default:
+		return nil, fmt.Errorf("profile %s: unknown value content %q (known: utf-8, json)", name, r.Value.Content)
+	}
+	return &Profile{Name: name, Key: Chain(key...), Value: Chain(value...)}, nil
+}
@@ -0,0 +1,340 @@
+package validation
+
+import (
+	"cmp"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Email rejects strings that are not a bare email address, such as
+// "ada@example.com".
+func Email() Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			return NewError(ctx, CodeInvalidContent, "invalid email address").WithMetadata("content", "email")
+		}
+		return nil
+	})
+}
+
+// StructChain builds a chain checking the fields of structs of type T, or of
+// the structs T points to, from their validate tags, such as
+//
+//	Name  string `json:"name" validate:"notempty,max=50"`
+//	Email string `json:"email" validate:"email"`
+//
+// The rules of a tag are separated by commas:
+//
+//	notempty     the field is not empty, nil or zero
+//	min=N, max=N bounds the length of strings in bytes, the number of items
+//	             of slices, arrays and maps, and the value of numbers
+//	pattern=RE   strings match RE, which cannot contain commas
+//	prefix=P|Q   strings start with one of the prefixes separated by |
+//	email        strings are an email address
+//	utf8, nfc, nocontrol
+//	             strings are valid UTF-8, in NFC, or free of control characters
+//	json         strings and byte slices hold a JSON document
+//	eqfield=F    the field equals the field named F of the same struct
+//	gtfield=F    the field is greater than, or for times after, the field F
+//
+// Fields are named by their JSON name, else their Go name. Failures target
+// that name below the target of the context; fields of nested structs are checked too, at paths
+// such as "address.city". A tag of "-" skips the field. StructChain fails on
+// rules it does not know or that do not apply to the type of their field.
+func StructChain[T any]() (*ValidatorChain[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validation: %s is not a struct", t)
+	}
+	rules, err := structRules(t, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	validators := make([]Validator[T], len(rules))
+	for i, rule := range rules {
+		validators[i] = ValidatorFunc[T](func(ctx Context, value T) *ValidationError {
+			v := reflect.ValueOf(value)
+			if v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					return nil
+				}
+				v = v.Elem()
+			}
+			field, err := v.FieldByIndexErr(rule.index)
+			if err != nil {
+				return nil // The field is below a nil embedded pointer
+			}
+			target := rule.path
+			if ctx.Target != "" {
+				target = ctx.Target + "." + target
+			}
+			ctx = ctx.At(target)
+			if rule.crossField {
+				parent, _ := v.FieldByIndexErr(rule.index[:len(rule.index)-1])
+				ctx.Fields = fieldValues(parent)
+			}
+			return rule.check(ctx, field)
+		})
+	}
+	return Chain(validators...), nil
+}
+
+// fieldRule is one rule of a validate tag, bound to its field.
+type fieldRule struct {
+	index      []int
+	path       string
+	check      func(ctx Context, field reflect.Value) *ValidationError
+	crossField bool // The check reads the other fields of the struct
+}
+
+// structRules compiles the validate tags of the fields of t and of its
+// nested structs.
+func structRules(t reflect.Type, index []int, prefix string) ([]fieldRule, error) {
+	var rules []fieldRule
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("validate")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		path := prefix + fieldName(field)
+
+		if tag != "" {
+			for _, spec := range strings.Split(tag, ",") {
+				name, arg, _ := strings.Cut(strings.TrimSpace(spec), "=")
+				crossField := name == "eqfield" || name == "gtfield"
+				var check func(Context, reflect.Value) *ValidationError
+				var err error
+				if crossField {
+					check, err = compileFieldRule(t, field.Type, name, arg)
+				} else {
+					check, err = compileRule(field.Type, name, arg)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("validation: field %s of %s: %w", field.Name, t, err)
+				}
+				rules = append(rules, fieldRule{index: fieldIndex, path: path, check: check, crossField: crossField})
+			}
+		}
+		if field.Type.Kind() == reflect.Struct {
+			nested, err := structRules(field.Type, fieldIndex, path+".")
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, nested...)
+		}
+	}
+	return rules, nil
+}
+
+// fieldName returns the JSON name of field, else its Go name.
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// fieldValues returns the exported fields of a struct by name.
+func fieldValues(v reflect.Value) map[string]any {
+	fields := make(map[string]any, v.NumField())
+	for i := range v.NumField() {
+		if field := v.Type().Field(i); field.IsExported() {
+			fields[fieldName(field)] = v.Field(i).Interface()
+		}
+	}
+	return fields
+}
+
+// timeType is the type gtfield compares chronologically.
+var timeType = reflect.TypeFor[time.Time]()
+
+// compileFieldRule compiles an eqfield or gtfield rule for a field of type t,
+// comparing it with the field called other of the struct parent.
+func compileFieldRule(parent, t reflect.Type, name, other string) (func(Context, reflect.Value) *ValidationError, error) {
+	var otherField *reflect.StructField
+	for i := range parent.NumField() {
+		if field := parent.Field(i); field.IsExported() && fieldName(field) == other {
+			otherField = &field
+		}
+	}
+	if otherField == nil {
+		return nil, fmt.Errorf("%s refers to unknown field %q", name, other)
+	}
+	if otherField.Type != t {
+		return nil, fmt.Errorf("%s compares %s with %s of type %s", name, t, other, otherField.Type)
+	}
+
+	if name == "eqfield" {
+		if !t.Comparable() {
+			return nil, fmt.Errorf("eqfield does not apply to %s", t)
+		}
+		return func(ctx Context, field reflect.Value) *ValidationError {
+			if value, ok := ctx.Fields[other]; ok && field.Interface() != value {
+				return NewError(ctx, CodeMismatch, "must equal %s", other).WithMetadata("field", other)
+			}
+			return nil
+		}, nil
+	}
+
+	var compare func(a, b reflect.Value) int
+	relation := "greater than"
+	switch {
+	case t == timeType:
+		compare = func(a, b reflect.Value) int { return a.Interface().(time.Time).Compare(b.Interface().(time.Time)) }
+		relation = "after"
+	case t.Kind() == reflect.String:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) }
+	case isNumber(t):
+		compare = func(a, b reflect.Value) int { return cmp.Compare(numberOf(a), numberOf(b)) }
+	default:
+		return nil, fmt.Errorf("gtfield does not apply to %s", t)
+	}
+	return func(ctx Context, field reflect.Value) *ValidationError {
+		if value, ok := ctx.Fields[other]; ok && compare(field, reflect.ValueOf(value)) <= 0 {
+			return NewError(ctx, CodeTooSmall, "must be %s %s", relation, other).WithMetadata("field", other)
+		}
+		return nil
+	}, nil
+}
+
+// compileRule compiles one rule of a validate tag for a field of type t.
+func compileRule(t reflect.Type, name, arg string) (func(Context, reflect.Value) *ValidationError, error) {
+	isString := t.Kind() == reflect.String
+	onString := func(v Validator[string]) (func(Context, reflect.Value) *ValidationError, error) {
+		if !isString {
+			return nil, fmt.Errorf("%s applies to strings, not %s", name, t)
+		}
+		return func(ctx Context, field reflect.Value) *ValidationError { return v.Validate(ctx, field.String()) }, nil
+	}
+
+	switch name {
+	case "notempty":
+		return func(ctx Context, field reflect.Value) *ValidationError {
+			if isEmpty(field) {
+				return NewError(ctx, CodeRequired, "cannot be empty")
+			}
+			return nil
+		}, nil
+	case "min", "max":
+		return boundRule(t, name, arg)
+	case "pattern":
+		pattern, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return onString(MatchesPattern(pattern))
+	case "prefix":
+		return onString(AllowedPrefixes(strings.Split(arg, "|")...))
+	case "email":
+		return onString(Email())
+	case "utf8":
+		return onString(ValidUTF8())
+	case "nfc":
+		return onString(NFC())
+	case "nocontrol":
+		return onString(NoControlCharacters())
+	case "json":
+		switch {
+		case isString:
+			return func(ctx Context, field reflect.Value) *ValidationError {
+				return JSON().Validate(ctx, []byte(field.String()))
+			}, nil
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			return func(ctx Context, field reflect.Value) *ValidationError {
+				return JSON().Validate(ctx, field.Bytes())
+			}, nil
+		default:
+			return nil, fmt.Errorf("json applies to strings and byte slices, not %s", t)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule %q", name)
+	}
+}
+
+// boundRule compiles a min or max rule: a length for strings and
+// collections, a value for numbers.
+func boundRule(t reflect.Type, name, arg string) (func(Context, reflect.Value) *ValidationError, error) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		limit, err := strconv.Atoi(arg)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("%s needs a length, got %q", name, arg)
+		}
+		unit := "items"
+		if t.Kind() == reflect.String {
+			unit = "bytes"
+		}
+		return func(ctx Context, field reflect.Value) *ValidationError {
+			switch n := field.Len(); {
+			case name == "min" && n < limit:
+				return NewError(ctx, CodeTooShort, "too short: %d %s is under %d", n, unit, limit).WithMetadata("limit", arg)
+			case name == "max" && n > limit:
+				return NewError(ctx, CodeTooLong, "too long: %d %s exceeds %d", n, unit, limit).WithMetadata("limit", arg)
+			}
+			return nil
+		}, nil
+	}
+	if !isNumber(t) {
+		return nil, fmt.Errorf("%s does not apply to %s", name, t)
+	}
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s needs a number, got %q", name, arg)
+	}
+	return func(ctx Context, field reflect.Value) *ValidationError {
+		n := numberOf(field)
+		switch {
+		case name == "min" && n < limit:
+			return NewError(ctx, CodeTooSmall, "too small: %v is under %s", n, arg).WithMetadata("limit", arg)
+		case name == "max" && n > limit:
+			return NewError(ctx, CodeTooLarge, "too large: %v exceeds %s", n, arg).WithMetadata("limit", arg)
+		}
+		return nil
+	}, nil
+}
+
+// isNumber reports whether t is an integer or floating-point type.
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// numberOf returns the value of a numeric field as a float64.
+func numberOf(field reflect.Value) float64 {
+	switch {
+	case field.CanInt():
+		return float64(field.Int())
+	case field.CanUint():
+		return float64(field.Uint())
+	default:
+		return field.Float()
+	}
+}
+
+// isEmpty reports whether a field holds no value: an empty string or
+// collection, a nil pointer or interface, or the zero value.
+func isEmpty(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return field.Len() == 0
+	default:
+		return field.IsZero()
+	}
+}
@@ -0,0 +1,203 @@
+// Package validation checks keys and values before they are written. Small
+// validators are composed into chains, and chains into named profiles that
+// can be selected per caller or namespace. StructChain builds chains for the
+// structs of applications embedding the package from their field tags.
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code identifies why a value failed validation, independently of the
+// human-readable message.
+type Code string
+
+const (
+	CodeRequired         Code = "required"
+	CodeTooShort         Code = "too_short"
+	CodeTooLong          Code = "too_long"
+	CodeTooSmall         Code = "too_small"
+	CodeTooLarge         Code = "too_large"
+	CodePatternMismatch  Code = "pattern_mismatch"
+	CodePrefixNotAllowed Code = "prefix_not_allowed"
+	CodeInvalidContent   Code = "invalid_content"
+	CodeInvalidEncoding  Code = "invalid_encoding"
+	CodeNotNormalized    Code = "not_normalized"
+	CodeControlCharacter Code = "control_character"
+	CodeTimeout          Code = "timeout"
+	CodeDuplicate        Code = "duplicate"
+	CodeForbidden        Code = "forbidden"
+	CodeMismatch         Code = "mismatch"
+)
+
+// Context describes what is being validated.
+type Context struct {
+	Target    string // Path of the value being validated, such as "key" or "value"
+	Namespace string // Namespace of the key being written, if any
+
+	// Fields holds the values of the fields next to the target by name, so
+	// validators can compare with them; StructChain sets them for rules that
+	// refer to another field
+	Fields map[string]any
+}
+
+// At returns a copy of the context pointing at target.
+func (c Context) At(target string) Context {
+	c.Target = target
+	return c
+}
+
+// FieldAs returns the value of the field next to the target called name, if
+// ctx holds one of type T.
+func FieldAs[T any](ctx Context, name string) (T, bool) {
+	value, ok := ctx.Fields[name].(T)
+	return value, ok
+}
+
+// ValidationError reports a single failed check.
+type ValidationError struct {
+	Target   string            // Path of the value that failed, from Context.Target
+	Code     Code              // Machine-readable reason
+	Message  string            // Human-readable reason
+	Metadata map[string]string // Details such as the limit that was exceeded
+}
+
+// Error formats the error as "<target>: <message>".
+func (e *ValidationError) Error() string {
+	if e.Target == "" {
+		return e.Message
+	}
+	return e.Target + ": " + e.Message
+}
+
+// NewError returns a ValidationError for the target of ctx.
+func NewError(ctx Context, code Code, format string, args ...any) *ValidationError {
+	return &ValidationError{Target: ctx.Target, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// WithMetadata returns e with key set to value in its metadata.
+func (e *ValidationError) WithMetadata(key, value string) *ValidationError {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+// ValidationResult collects the failures of every check in a chain.
+type ValidationResult struct {
+	Errors []*ValidationError
+}
+
+// Add records a failure; nil errors are ignored.
+func (r *ValidationResult) Add(err *ValidationError) {
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+// Merge records every failure of other.
+func (r *ValidationResult) Merge(other ValidationResult) {
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+// Valid reports whether every check passed.
+func (r ValidationResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Err returns the result as an error, or nil if every check passed.
+func (r ValidationResult) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return &r
+}
+
+// Error joins the messages of every failure.
+func (r *ValidationResult) Error() string {
+	messages := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the failures, so errors.Is and errors.As find them inside
+// the result.
+func (r *ValidationResult) Unwrap() []error {
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// As returns every ValidationError in the tree of err, depth first,
+// including those of results and of errors combined by errors.Join. It
+// returns nil when there are none.
+func As(err error) []*ValidationError {
+	var errs []*ValidationError
+	var walk func(err error)
+	walk = func(err error) {
+		switch err := err.(type) {
+		case *ValidationError:
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case interface{ Unwrap() []error }:
+			for _, inner := range err.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(err.Unwrap())
+		}
+	}
+	walk(err)
+	return errs
+}
+
+// Validator checks a single value, returning nil when it is valid.
+type Validator[T any] interface {
+	Validate(ctx Context, value T) *ValidationError
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc[T any] func(ctx Context, value T) *ValidationError
+
+// Validate calls f.
+func (f ValidatorFunc[T]) Validate(ctx Context, value T) *ValidationError {
+	return f(ctx, value)
+}
+
+// ValidatorChain runs validators in order and collects every failure. A nil
+// chain accepts everything.
+type ValidatorChain[T any] struct {
+	validators []Validator[T]
+}
+
+// Chain returns a chain running validators in order.
+func Chain[T any](validators ...Validator[T]) *ValidatorChain[T] {
+	return &ValidatorChain[T]{validators: validators}
+}
+
+// Validate runs every validator of the chain against value.
+func (c *ValidatorChain[T]) Validate(ctx Context, value T) ValidationResult {
+	var result ValidationResult
+	if c == nil {
+		return result
+	}
+	for _, v := range c.validators {
+		result.Add(v.Validate(ctx, value))
+	}
+	return result
+}
+
+// Len returns the number of validators in the chain.
+func (c *ValidatorChain[T]) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.validators)
+}
@@ -0,0 +1,486 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidators(t *testing.T) {
+	ctx := Context{Target: "key"}
+	tests := []struct {
+		name string
+		err  *ValidationError
+		code Code
+	}{
+		{"NotEmpty rejects empty", NotEmpty().Validate(ctx, ""), CodeRequired},
+		{"NotEmpty accepts text", NotEmpty().Validate(ctx, "a"), ""},
+		{"MaxLength rejects long", MaxLength(3).Validate(ctx, "abcd"), CodeTooLong},
+		{"MaxLength accepts limit", MaxLength(3).Validate(ctx, "abc"), ""},
+		{"MinLength rejects short", MinLength(2).Validate(ctx, "a"), CodeTooShort},
+		{"MatchesPattern rejects mismatch", MatchesPattern(regexp.MustCompile(`^[a-z]+$`)).Validate(ctx, "A1"), CodePatternMismatch},
+		{"AllowedPrefixes rejects others", AllowedPrefixes("a/", "b/").Validate(ctx, "c/1"), CodePrefixNotAllowed},
+		{"AllowedPrefixes accepts listed", AllowedPrefixes("a/", "b/").Validate(ctx, "b/1"), ""},
+		{"ValidUTF8 rejects invalid", ValidUTF8().Validate(ctx, "a\xff"), CodeInvalidEncoding},
+		{"ValidUTF8 accepts text", ValidUTF8().Validate(ctx, "café"), ""},
+		{"NFC rejects decomposed", NFC().Validate(ctx, "cafe\u0301"), CodeNotNormalized},
+		{"NFC accepts composed", NFC().Validate(ctx, "caf\u00e9"), ""},
+		{"NoControlCharacters rejects newline", NoControlCharacters().Validate(ctx, "a\nb"), CodeControlCharacter},
+		{"NoControlCharacters accepts text", NoControlCharacters().Validate(ctx, "a b/é"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.code == "" {
+				if tt.err != nil {
+					t.Errorf("Expected no error, got %v", tt.err)
+				}
+				return
+			}
+			if tt.err == nil || tt.err.Code != tt.code || tt.err.Target != "key" {
+				t.Errorf("Expected a %s error on key, got %#v", tt.code, tt.err)
+			}
+		})
+	}
+
+	valueCtx := Context{Target: "value"}
+	if err := MaxSize(2).Validate(valueCtx, []byte("abc")); err == nil || err.Code != CodeTooLarge || err.Metadata["limit"] != "2" {
+		t.Errorf("Expected a too_large error with its limit, got %#v", err)
+	}
+	if err := JSON().Validate(valueCtx, []byte(`{"a":`)); err == nil || err.Code != CodeInvalidContent || err.Metadata["content"] != "json" {
+		t.Errorf("Expected invalid JSON to be rejected, got %#v", err)
+	}
+	if err := JSON().Validate(valueCtx, []byte(`{"a":1}`)); err != nil {
+		t.Errorf("Expected valid JSON to be accepted, got %v", err)
+	}
+	if err := UTF8().Validate(valueCtx, []byte{0xff}); err == nil {
+		t.Error("Expected invalid UTF-8 to be rejected")
+	}
+}
+
+func TestValidatorChain(t *testing.T) {
+	chain := Chain(NotEmpty(), MaxLength(3), MatchesPattern(regexp.MustCompile(`^[a-z]*$`)))
+	result := chain.Validate(Context{Target: "key"}, "ABCD")
+	if result.Valid() || len(result.Errors) != 2 {
+		t.Fatalf("Expected two failures, got %v", result.Errors)
+	}
+	err := result.Err()
+	var got *ValidationResult
+	if !errors.As(err, &got) || len(got.Errors) != 2 {
+		t.Fatalf("Expected the result as an error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "key: too long") || !strings.Contains(err.Error(), "; key: does not match") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	if result := chain.Validate(Context{}, "abc"); !result.Valid() || result.Err() != nil {
+		t.Errorf("Expected a valid result, got %v", result.Errors)
+	}
+	var empty *ValidatorChain[string]
+	if !empty.Validate(Context{}, "").Valid() || empty.Len() != 0 {
+		t.Error("Expected a nil chain to accept everything")
+	}
+}
+
+func TestAs(t *testing.T) {
+	var first, second ValidationResult
+	first.Add(NewError(Context{Target: "key"}, CodeRequired, "cannot be empty"))
+	first.Add(NewError(Context{Target: "value"}, CodeTooLarge, "too large"))
+	single := NewError(Context{Target: "tags[0]"}, CodeDuplicate, "duplicate")
+	second.Add(single)
+
+	err := fmt.Errorf("batch rejected: %w", errors.Join(first.Err(), errors.New("disk full"), second.Err()))
+	errs := As(err)
+	if len(errs) != 3 || errs[0].Target != "key" || errs[1].Target != "value" || errs[2] != single {
+		t.Fatalf("As = %v, want the failures of both results in order", errs)
+	}
+	if !errors.Is(err, single) {
+		t.Error("Expected errors.Is to find a failure inside a result")
+	}
+	var target *ValidationError
+	if !errors.As(first.Err(), &target) || target.Code != CodeRequired {
+		t.Errorf("errors.As = %v, want the first failure of the result", target)
+	}
+
+	if As(errors.New("disk full")) != nil || As(nil) != nil {
+		t.Error("Expected no failures outside validation errors")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	profiles := DefaultProfiles()
+	strict, err := profiles.Lookup(ProfileStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standard, _ := profiles.Lookup(ProfileStandard)
+	permissive, _ := profiles.Lookup(ProfilePermissive)
+	if _, err := profiles.Lookup("lenient"); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+
+	largeKey := strings.Repeat("k", 2000)
+	tests := []struct {
+		name    string
+		key     string
+		value   []byte
+		strict  bool
+		std     bool
+		permits bool
+	}{
+		{"plain write", "users/1", []byte("alice"), true, true, true},
+		{"empty key", "", []byte("v"), false, false, false},
+		{"spaces in key", "users/alice smith", []byte("v"), false, true, true},
+		{"long key", largeKey, []byte("v"), false, false, true},
+		{"binary value", "blobs/1", []byte{0xff, 0xfe}, false, true, true},
+		{"large value", "blobs/2", make([]byte, 2<<20), false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct {
+				profile *Profile
+				valid   bool
+			}{{strict, tt.strict}, {standard, tt.std}, {permissive, tt.permits}} {
+				result := c.profile.Validate(Context{}, tt.key, tt.value)
+				if result.Valid() != c.valid {
+					t.Errorf("%s: valid = %v, want %v (%v)", c.profile.Name, result.Valid(), c.valid, result.Errors)
+				}
+			}
+		})
+	}
+
+	result := strict.Validate(Context{}, "", []byte{0xff})
+	if len(result.Errors) < 2 || result.Errors[0].Target != "key" || result.Errors[len(result.Errors)-1].Target != "value" {
+		t.Errorf("Expected key and value failures to be targeted, got %v", result.Errors)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"required": ["name", "age"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "maxItems": 2, "items": {"enum": ["a", "b"]}},
+			"a/b": {"type": ["string", "null"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		want     []string
+	}{
+		{"valid", `{"name": "ada", "age": 36, "tags": ["a"], "a/b": null}`, nil},
+		{"missing property", `{"name": "ada"}`, []string{`/: missing required property "age"`}},
+		{"wrong type", `{"name": "ada", "age": 1.5}`, []string{"/age: must be of type integer"}},
+		{"bounds", `{"name": "", "age": -1}`, []string{"/age: must be at least 0", "/name: must be at least 1 characters long", "/name: must match ^[a-z]+$"}},
+		{"items", `{"name": "ada", "age": 1, "tags": ["a", "c", "b"]}`, []string{"/tags: must have at most 2 items", "/tags/1: must be one of the enumerated values"}},
+		{"additional property", `{"name": "ada", "age": 1, "extra": true}`, []string{"/extra: is not allowed"}},
+		{"escaped name", `{"name": "ada", "age": 1, "a/b": 1}`, []string{"/a~1b: must be of type string or null"}},
+		{"not an object", `[]`, []string{"/: must be of type object"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JSONSchema(schema).Validate(Context{Target: "value"}, []byte(tt.document))
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Code != CodeInvalidContent || err.Metadata["content"] != "json-schema" {
+				t.Fatalf("Expected an invalid_content error, got %#v", err)
+			}
+			for i, want := range tt.want {
+				if got := err.Metadata["violation."+strconv.Itoa(i)]; got != want {
+					t.Errorf("Violation %d = %q, want %q", i, got, want)
+				}
+			}
+			if extra, ok := err.Metadata["violation."+strconv.Itoa(len(tt.want))]; ok {
+				t.Errorf("Unexpected violation %q", extra)
+			}
+		})
+	}
+
+	if err := JSONSchema(schema).Validate(Context{}, []byte("{")); err == nil {
+		t.Error("Expected malformed JSON to be rejected")
+	}
+	for _, invalid := range []string{`{"type": "thing"}`, `{"pattern": "("}`, `{"properties": {"a": {"type": 1}}}`, `[]`} {
+		if _, err := ParseSchema([]byte(invalid)); err == nil {
+			t.Errorf("Expected schema %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestAsyncChain(t *testing.T) {
+	ctx := Context{Target: "value"}
+	pass := AsyncValidatorFunc[string](func(context.Context, Context, string) *ValidationError { return nil })
+	fail := func(code Code) AsyncValidator[string] {
+		return AsyncValidatorFunc[string](func(_ context.Context, vctx Context, _ string) *ValidationError {
+			return NewError(vctx, code, "failed")
+		})
+	}
+	// blocked waits until its context ends and records that it was canceled
+	canceled := make(chan struct{}, 1)
+	blocked := AsyncValidatorFunc[string](func(ctx context.Context, _ Context, _ string) *ValidationError {
+		<-ctx.Done()
+		canceled <- struct{}{}
+		return nil
+	})
+
+	result := ParallelChain(0, pass, Async(MaxLength(1)), Soft(fail(CodeInvalidContent))).Validate(context.Background(), ctx, "abc")
+	if len(result.Errors) != 2 || result.Errors[0].Code != CodeTooLong || result.Errors[1].Code != CodeInvalidContent {
+		t.Errorf("Expected the failures in the order of the validators, got %v", result.Errors)
+	}
+
+	// Soft validators are waited for after a hard failure, however the
+	// validators are scheduled
+	failed := make(chan struct{})
+	hard := AsyncValidatorFunc[string](func(_ context.Context, vctx Context, _ string) *ValidationError {
+		defer close(failed)
+		return NewError(vctx, CodeRequired, "failed")
+	})
+	late := AsyncValidatorFunc[string](func(ctx context.Context, vctx Context, _ string) *ValidationError {
+		<-failed
+		if ctx.Err() != nil {
+			return NewError(vctx, CodeTimeout, "canceled")
+		}
+		return NewError(vctx, CodeInvalidContent, "failed")
+	})
+	result = ParallelChain(0, hard, Soft(late)).Validate(context.Background(), ctx, "")
+	if len(result.Errors) != 2 || result.Errors[0].Code != CodeRequired || result.Errors[1].Code != CodeInvalidContent {
+		t.Errorf("Expected the hard and soft failures, got %v", result.Errors)
+	}
+
+	result = ParallelChain(0, blocked, fail(CodeRequired)).Validate(context.Background(), ctx, "")
+	if len(result.Errors) != 1 || result.Errors[0].Code != CodeRequired {
+		t.Errorf("Expected the hard failure alone, got %v", result.Errors)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a hard failure to cancel the other validators")
+	}
+
+	result = ParallelChain(10*time.Millisecond, blocked, pass).Validate(context.Background(), ctx, "")
+	if len(result.Errors) != 1 || result.Errors[0].Code != CodeTimeout || result.Errors[0].Target != "value" {
+		t.Errorf("Expected the blocked validator to time out, got %v", result.Errors)
+	}
+	<-canceled
+
+	var empty *AsyncChain[string]
+	if !empty.Validate(context.Background(), ctx, "").Valid() || empty.Len() != 0 {
+		t.Error("Expected a nil chain to accept everything")
+	}
+}
+
+func TestRules(t *testing.T) {
+	profile, err := Rules{
+		Key:   KeyRules{MaxLength: 8, AllowedPrefixes: []string{"orders/"}, Pattern: `^[a-z/0-9]+$`},
+		Value: ValueRules{MaxSize: 16, Content: "json"},
+	}.Compile("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Name != "orders" {
+		t.Errorf("Name = %s, want orders", profile.Name)
+	}
+	if result := profile.Validate(Context{}, "orders/1", []byte(`{"a":1}`)); !result.Valid() {
+		t.Errorf("Expected a valid write, got %v", result.Errors)
+	}
+	result := profile.Validate(Context{}, "Orders/12", []byte(`{"a":`))
+	var codes []Code
+	for _, err := range result.Errors {
+		codes = append(codes, err.Code)
+	}
+	want := []Code{CodeTooLong, CodePrefixNotAllowed, CodePatternMismatch, CodeInvalidContent}
+	if !slices.Equal(codes, want) {
+		t.Errorf("Codes = %v, want %v", codes, want)
+	}
+	if result := profile.Validate(Context{}, "", nil); len(result.Errors) == 0 || result.Errors[0].Code != CodeRequired {
+		t.Errorf("Expected empty keys to be rejected, got %v", result.Errors)
+	}
+
+	for _, invalid := range []Rules{
+		{Key: KeyRules{MaxLength: -1}},
+		{Key: KeyRules{Pattern: "("}},
+		{Value: ValueRules{Content: "xml"}},
+	} {
+		if _, err := invalid.Compile("bad"); err == nil {
+			t.Errorf("Expected rules %+v to be rejected", invalid)
+		}
+	}
+}
+
+func TestStructChain(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"notempty"`
+	}
+	type user struct {
+		Name    string            `json:"name" validate:"notempty,max=5"`
+		Email   string            `json:"email,omitempty" validate:"email"`
+		Age     int               `validate:"min=0,max=150"`
+		Tags    []string          `json:"tags" validate:"max=2"`
+		Profile []byte            `json:"profile" validate:"json"`
+		Home    address           `json:"home"`
+		Labels  map[string]string `json:"-"`
+		Skipped string            `validate:"-"`
+		private string
+	}
+	chain, err := StructChain[*user]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := &user{Name: "ada", Email: "ada@example.com", Age: 36, Profile: []byte(`{}`), Home: address{City: "London"}}
+	if result := chain.Validate(Context{}, valid); !result.Valid() {
+		t.Errorf("Expected a valid user, got %v", result.Errors)
+	}
+	if result := chain.Validate(Context{}, nil); !result.Valid() {
+		t.Errorf("Expected a nil user to be skipped, got %v", result.Errors)
+	}
+
+	invalid := &user{Name: "grace hopper", Email: "Grace <grace@example.com>", Age: -1, Tags: []string{"a", "b", "c"}, Profile: []byte("{")}
+	result := chain.Validate(Context{Target: "value"}, invalid)
+	var got []string
+	for _, err := range result.Errors {
+		got = append(got, err.Target+":"+string(err.Code))
+	}
+	want := []string{
+		"value.name:too_long", "value.email:invalid_content", "value.Age:too_small",
+		"value.tags:too_long", "value.profile:invalid_content", "value.home.city:required",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Failures = %v, want %v", got, want)
+	}
+
+	if _, err := StructChain[string](); err == nil {
+		t.Error("Expected a non-struct type to be rejected")
+	}
+	type unknownRule struct {
+		A string `validate:"shiny"`
+	}
+	if _, err := StructChain[unknownRule](); err == nil {
+		t.Error("Expected an unknown rule to be rejected")
+	}
+	type mismatchedRule struct {
+		A int `validate:"email"`
+	}
+	if _, err := StructChain[mismatchedRule](); err == nil {
+		t.Error("Expected a string rule on a number to be rejected")
+	}
+}
+
+func TestCollectionValidators(t *testing.T) {
+	ctx := Context{Target: "tags"}
+	tests := []struct {
+		name   string
+		err    *ValidationError
+		code   Code
+		target string
+	}{
+		{"MinItems rejects short", MinItems[string](2).Validate(ctx, []string{"a"}), CodeTooShort, "tags"},
+		{"MaxItems rejects long", MaxItems[string](1).Validate(ctx, []string{"a", "b"}), CodeTooLong, "tags"},
+		{"MaxItems accepts limit", MaxItems[string](2).Validate(ctx, []string{"a", "b"}), "", ""},
+		{"UniqueItems rejects repeats", UniqueItems[string]().Validate(ctx, []string{"a", "b", "a"}), CodeDuplicate, "tags[2]"},
+		{"UniqueItems accepts distinct", UniqueItems[int]().Validate(ctx, []int{1, 2}), "", ""},
+		{"Each targets the first failure", Each(NotEmpty()).Validate(ctx, []string{"a", "", ""}), CodeRequired, "tags[1]"},
+		{"Each accepts valid items", Each(MaxLength(1)).Validate(ctx, []string{"a", "b"}), "", ""},
+		{"RequiredKeys rejects missing", RequiredKeys[string, int]("env", "team").Validate(Context{Target: "labels"}, map[string]int{"env": 1}), CodeRequired, "labels.team"},
+		{"ForbiddenKeys rejects present", ForbiddenKeys[string, string]("password").Validate(Context{}, map[string]string{"password": "x"}), CodeForbidden, "password"},
+		{"ForbiddenKeys accepts absent", ForbiddenKeys[string, string]("password").Validate(Context{}, nil), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.code == "" {
+				if tt.err != nil {
+					t.Errorf("Expected no error, got %v", tt.err)
+				}
+				return
+			}
+			if tt.err == nil || tt.err.Code != tt.code || tt.err.Target != tt.target {
+				t.Errorf("Expected a %s error on %s, got %#v", tt.code, tt.target, tt.err)
+			}
+		})
+	}
+
+	if err := Each(NotEmpty()).Validate(ctx, []string{"", "a", ""}); err == nil || err.Metadata["failed_items"] != "2" {
+		t.Errorf("Expected the number of invalid items in the metadata, got %#v", err)
+	}
+	if err := UniqueItems[string]().Validate(ctx, []string{"a", "a"}); err == nil || err.Metadata["first"] != "0" {
+		t.Errorf("Expected the index of the first occurrence in the metadata, got %#v", err)
+	}
+}
+
+func TestFieldValidators(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := Context{Target: "end", Fields: map[string]any{"password": "s3cret", "min": 3, "start": start}}
+
+	if err := EqualsField[string]("password").Validate(ctx, "secret"); err == nil || err.Code != CodeMismatch || err.Metadata["field"] != "password" {
+		t.Errorf("Expected a mismatch, got %#v", err)
+	}
+	if err := EqualsField[string]("password").Validate(ctx, "s3cret"); err != nil {
+		t.Errorf("Expected equal values to be accepted, got %v", err)
+	}
+	if err := GreaterThanField[int]("min").Validate(ctx, 3); err == nil || err.Code != CodeTooSmall {
+		t.Errorf("Expected an equal value to be rejected, got %#v", err)
+	}
+	if err := AfterField("start").Validate(ctx, start.Add(-time.Hour)); err == nil || err.Target != "end" {
+		t.Errorf("Expected an earlier time to be rejected, got %#v", err)
+	}
+	if err := AfterField("start").Validate(ctx, start.Add(time.Hour)); err != nil {
+		t.Errorf("Expected a later time to be accepted, got %v", err)
+	}
+	if err := GreaterThanField[int]("missing").Validate(ctx, 0); err != nil {
+		t.Errorf("Expected a missing field to be ignored, got %v", err)
+	}
+	if err := EqualsField[int]("password").Validate(ctx, 1); err != nil {
+		t.Errorf("Expected a field of another type to be ignored, got %v", err)
+	}
+
+	type period struct {
+		Start    time.Time `json:"start"`
+		End      time.Time `json:"end" validate:"gtfield=start"`
+		Password string    `json:"password"`
+		Confirm  string    `json:"password_confirmation" validate:"eqfield=password"`
+	}
+	type booking struct {
+		Period period `json:"period"`
+	}
+	chain, err := StructChain[booking]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := chain.Validate(Context{}, booking{period{Start: start, End: start.Add(time.Hour), Password: "a", Confirm: "a"}}); !result.Valid() {
+		t.Errorf("Expected a valid booking, got %v", result.Errors)
+	}
+	result := chain.Validate(Context{}, booking{period{Start: start, End: start, Password: "a", Confirm: "b"}})
+	if len(result.Errors) != 2 || result.Errors[0].Target != "period.end" || result.Errors[1].Target != "period.password_confirmation" {
+		t.Errorf("Expected the end and the confirmation to be rejected, got %v", result.Errors)
+	}
+
+	type unknownField struct {
+		A string `validate:"eqfield=b"`
+	}
+	if _, err := StructChain[unknownField](); err == nil {
+		t.Error("Expected a reference to an unknown field to be rejected")
+	}
+	type otherType struct {
+		A string `validate:"gtfield=B"`
+		B int
+	}
+	if _, err := StructChain[otherType](); err == nil {
+		t.Error("Expected a comparison of different types to be rejected")
+	}
+}
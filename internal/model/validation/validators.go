@@ -0,0 +1,163 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NotEmpty rejects empty strings.
+func NotEmpty() Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if value == "" {
+			return NewError(ctx, CodeRequired, "cannot be empty")
+		}
+		return nil
+	})
+}
+
+// MaxLength rejects strings longer than limit bytes.
+func MaxLength(limit int) Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if len(value) > limit {
+			return NewError(ctx, CodeTooLong, "too long: %d bytes exceeds %d", len(value), limit).
+				WithMetadata("limit", strconv.Itoa(limit))
+		}
+		return nil
+	})
+}
+
+// MinLength rejects strings shorter than limit bytes.
+func MinLength(limit int) Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if len(value) < limit {
+			return NewError(ctx, CodeTooShort, "too short: %d bytes is under %d", len(value), limit).
+				WithMetadata("limit", strconv.Itoa(limit))
+		}
+		return nil
+	})
+}
+
+// MatchesPattern rejects strings that do not match pattern.
+func MatchesPattern(pattern *regexp.Regexp) Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if !pattern.MatchString(value) {
+			return NewError(ctx, CodePatternMismatch, "does not match %s", pattern).
+				WithMetadata("pattern", pattern.String())
+		}
+		return nil
+	})
+}
+
+// AllowedPrefixes rejects strings that start with none of prefixes.
+func AllowedPrefixes(prefixes ...string) Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(value, prefix) {
+				return nil
+			}
+		}
+		return NewError(ctx, CodePrefixNotAllowed, "must start with one of %q", prefixes).
+			WithMetadata("prefixes", strings.Join(prefixes, ","))
+	})
+}
+
+// ValidUTF8 rejects strings that are not valid UTF-8.
+func ValidUTF8() Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if !utf8.ValidString(value) {
+			return NewError(ctx, CodeInvalidEncoding, "not valid UTF-8")
+		}
+		return nil
+	})
+}
+
+// NFC rejects strings not in Unicode Normalization Form C, so keys that look
+// the same are also spelled with the same code points.
+func NFC() Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		if !norm.NFC.IsNormalString(value) {
+			return NewError(ctx, CodeNotNormalized, "not in Unicode NFC").WithMetadata("form", "NFC")
+		}
+		return nil
+	})
+}
+
+// NoControlCharacters rejects strings containing control characters, such as
+// newlines, escapes or NUL, which are invisible when keys are printed.
+func NoControlCharacters() Validator[string] {
+	return ValidatorFunc[string](func(ctx Context, value string) *ValidationError {
+		for offset, r := range value {
+			if unicode.IsControl(r) {
+				return NewError(ctx, CodeControlCharacter, "contains control character %U at byte %d", r, offset).
+					WithMetadata("offset", strconv.Itoa(offset))
+			}
+		}
+		return nil
+	})
+}
+
+// MaxSize rejects values larger than limit bytes.
+func MaxSize(limit int) Validator[[]byte] {
+	return ValidatorFunc[[]byte](func(ctx Context, value []byte) *ValidationError {
+		if len(value) > limit {
+			return NewError(ctx, CodeTooLarge, "too large: %d bytes exceeds %d", len(value), limit).
+				WithMetadata("limit", strconv.Itoa(limit))
+		}
+		return nil
+	})
+}
+
+// ValueContentValidator checks what a value contains rather than its size.
+// Check returns a description of the problem, or nil if the content is
+// acceptable.
+type ValueContentValidator struct {
+	Name  string // Kind of content expected, such as "json"
+	Check func(value []byte) error
+}
+
+// Validate runs the content check. Errors of the check with a Metadata
+// method, such as SchemaError, add their metadata to the ValidationError.
+func (v ValueContentValidator) Validate(ctx Context, value []byte) *ValidationError {
+	err := v.Check(value)
+	if err == nil {
+		return nil
+	}
+	verr := NewError(ctx, CodeInvalidContent, "invalid %s: %v", v.Name, err).WithMetadata("content", v.Name)
+	var detailed interface{ Metadata() map[string]string }
+	if errors.As(err, &detailed) {
+		for key, value := range detailed.Metadata() {
+			verr.WithMetadata(key, value)
+		}
+	}
+	return verr
+}
+
+// errInvalid is returned by content checks without further detail.
+type errInvalid string
+
+func (e errInvalid) Error() string { return string(e) }
+
+// UTF8 accepts values that are valid UTF-8 text.
+func UTF8() ValueContentValidator {
+	return ValueContentValidator{Name: "utf-8", Check: func(value []byte) error {
+		if !utf8.Valid(value) {
+			return errInvalid("not valid UTF-8")
+		}
+		return nil
+	}}
+}
+
+// JSON accepts values that are a single well-formed JSON document.
+func JSON() ValueContentValidator {
+	return ValueContentValidator{Name: "json", Check: func(value []byte) error {
+		var document json.RawMessage
+		return json.Unmarshal(value, &document)
+	}}
+}
@@ -0,0 +1,187 @@
+// Package pubsub delivers the store mutations published on an events.Bus to
+// subscribers of key prefixes. Unlike a plain bus subscription, each
+// subscriber gets its own bounded buffer and a subscriber that lets it fill
+// up is evicted, so it learns that it missed events instead of silently
+// skipping them.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+)
+
+// Metric names recorded by a Broker.
+const (
+	MetricSubscribers     = "pubsub_subscribers"            // Subscriptions open
+	MetricEventsDelivered = "pubsub_events_delivered_total" // Events taken by subscribers
+	MetricEvictions       = "pubsub_evictions_total"        // Subscribers evicted for falling behind
+)
+
+// DefaultBufferSize is the number of events queued for a subscriber before
+// it is evicted.
+const DefaultBufferSize = 1024
+
+var (
+	// ErrTooManySubscribers is returned by Subscribe when the broker has as
+	// many subscriptions open as it allows.
+	ErrTooManySubscribers = errors.New("too many subscribers")
+	// ErrSlowConsumer is returned by Next once the subscriber was evicted
+	// for falling behind and the events queued before were taken.
+	ErrSlowConsumer = errors.New("subscriber fell behind and was evicted")
+	// ErrClosed is returned by Subscribe and Next once the broker is closed.
+	ErrClosed = errors.New("broker is closed")
+)
+
+// Config configures a Broker.
+type Config struct {
+	BufferSize     int // Events queued per subscriber; DefaultBufferSize if zero
+	MaxSubscribers int // Subscriptions open at once; unlimited if zero
+}
+
+// Broker fans the puts, deletes and expirations published on a bus out to
+// the subscribers of the prefixes of their keys.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+
+	bufferSize     int
+	maxSubscribers int
+	unsubscribe    func()
+
+	subscribers *metrics.Gauge
+	delivered   *metrics.Counter
+	evictions   *metrics.Counter
+}
+
+// NewBroker creates a broker for the mutations published on bus, recording
+// its deliveries in registry. Close releases its subscription to bus.
+func NewBroker(bus *events.Bus, config Config, registry *metrics.Registry) *Broker {
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultBufferSize
+	}
+	b := &Broker{
+		subs:           make(map[*Subscription]struct{}),
+		bufferSize:     config.BufferSize,
+		maxSubscribers: config.MaxSubscribers,
+		subscribers:    registry.Gauge(MetricSubscribers),
+		delivered:      registry.Counter(MetricEventsDelivered),
+		evictions:      registry.Counter(MetricEvictions),
+	}
+	b.unsubscribe = bus.Subscribe(b.dispatch, events.TypePut, events.TypeDelete, events.TypeExpire)
+	return b
+}
+
+// Subscribe opens a subscription to the mutations of the keys starting with
+// prefix, every key if empty. Events published before it returns are not
+// delivered.
+func (b *Broker) Subscribe(prefix string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if b.maxSubscribers > 0 && len(b.subs) >= b.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+	sub := &Subscription{
+		broker: b,
+		prefix: prefix,
+		events: make(chan events.Event, b.bufferSize),
+		done:   make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	b.subscribers.Add(1)
+	return sub, nil
+}
+
+// Close stops the broker, ending every subscription with ErrClosed once the
+// events queued for it are taken.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub, ErrClosed)
+	}
+	b.mu.Unlock()
+	b.unsubscribe()
+}
+
+// dispatch queues e for the subscribers of its key without blocking,
+// evicting those whose buffer is full.
+func (b *Broker) dispatch(e events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if !strings.HasPrefix(e.Key, sub.prefix) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			b.remove(sub, ErrSlowConsumer)
+			b.evictions.Inc()
+		}
+	}
+}
+
+// remove ends sub with err. The caller holds b.mu.
+func (b *Broker) remove(sub *Subscription, err error) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	sub.err = err
+	close(sub.done)
+	b.subscribers.Add(-1)
+}
+
+// Subscription receives the mutations of the keys starting with a prefix, in
+// the order they were published.
+type Subscription struct {
+	broker *Broker
+	prefix string
+	events chan events.Event
+	done   chan struct{} // Closed once no more events are queued
+	err    error         // Why the subscription ended, set before done is closed
+}
+
+// Next returns the next event, waiting for one until ctx is done. Once the
+// subscription ended, it returns the events still queued and then the reason:
+// ErrSlowConsumer if the subscriber was evicted, ErrClosed if it or the
+// broker was closed.
+func (s *Subscription) Next(ctx context.Context) (events.Event, error) {
+	select {
+	case e := <-s.events:
+		s.broker.delivered.Inc()
+		return e, nil
+	case <-s.done:
+		// Nothing is queued after done is closed, but events may have been
+		// queued just before
+		select {
+		case e := <-s.events:
+			s.broker.delivered.Inc()
+			return e, nil
+		default:
+			return events.Event{}, s.err
+		}
+	case <-ctx.Done():
+		return events.Event{}, ctx.Err()
+	}
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s, ErrClosed)
+}
@@ -0,0 +1,139 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+)
+
+func newBroker(t *testing.T, config Config) (*Broker, *metrics.Registry) {
+	t.Helper()
+	bus := events.NewBus(0)
+	t.Cleanup(bus.Close)
+	registry := metrics.NewRegistry()
+	b := NewBroker(bus, config, registry)
+	t.Cleanup(b.Close)
+	return b, registry
+}
+
+func next(t *testing.T, sub *Subscription) (events.Event, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return sub.Next(ctx)
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	b, registry := newBroker(t, Config{})
+	users, err := b.Subscribe("user:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := b.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.dispatch(events.Event{Type: events.TypePut, Key: "order:1", Value: []byte("x")})
+	b.dispatch(events.Event{Type: events.TypePut, Key: "user:1", Value: []byte("alice")})
+	b.dispatch(events.Event{Type: events.TypeDelete, Key: "user:1"})
+
+	for _, want := range []string{"order:1", "user:1", "user:1"} {
+		if e, err := next(t, all); err != nil || e.Key != want {
+			t.Fatalf("Next() = %v, %v; want %q", e.Key, err, want)
+		}
+	}
+	if e, err := next(t, users); err != nil || e.Type != events.TypePut || string(e.Value) != "alice" {
+		t.Fatalf("Next() = %+v, %v; want the put of user:1 with its value", e, err)
+	}
+	if e, err := next(t, users); err != nil || e.Type != events.TypeDelete {
+		t.Fatalf("Next() = %+v, %v; want the delete of user:1", e, err)
+	}
+	if got := registry.Counter(MetricEventsDelivered).Value(); got != 5 {
+		t.Errorf("%s = %d, want 5", MetricEventsDelivered, got)
+	}
+	if got := registry.Gauge(MetricSubscribers).Value(); got != 2 {
+		t.Errorf("%s = %d, want 2", MetricSubscribers, got)
+	}
+
+	users.Close()
+	if _, err := next(t, users); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+	if got := registry.Gauge(MetricSubscribers).Value(); got != 1 {
+		t.Errorf("%s = %d after Close, want 1", MetricSubscribers, got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := all.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Next to stop with its context, got %v", err)
+	}
+}
+
+func TestBroker_EvictsSlowConsumers(t *testing.T) {
+	b, registry := newBroker(t, Config{BufferSize: 2})
+	slow, err := b.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		b.dispatch(events.Event{Type: events.TypePut, Key: key})
+	}
+
+	// The events queued before the eviction are still delivered
+	for _, want := range []string{"a", "b"} {
+		if e, err := next(t, slow); err != nil || e.Key != want {
+			t.Fatalf("Next() = %v, %v; want %q", e.Key, err, want)
+		}
+	}
+	if _, err := next(t, slow); !errors.Is(err, ErrSlowConsumer) {
+		t.Errorf("Expected ErrSlowConsumer, got %v", err)
+	}
+	if got := registry.Counter(MetricEvictions).Value(); got != 1 {
+		t.Errorf("%s = %d, want 1", MetricEvictions, got)
+	}
+	if got := registry.Gauge(MetricSubscribers).Value(); got != 0 {
+		t.Errorf("%s = %d, want 0", MetricSubscribers, got)
+	}
+}
+
+func TestBroker_MaxSubscribers(t *testing.T) {
+	b, _ := newBroker(t, Config{MaxSubscribers: 1})
+	sub, err := b.Subscribe("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Subscribe("b"); !errors.Is(err, ErrTooManySubscribers) {
+		t.Errorf("Expected ErrTooManySubscribers, got %v", err)
+	}
+	sub.Close()
+	if _, err := b.Subscribe("b"); err != nil {
+		t.Errorf("Expected a closed subscription to free its place, got %v", err)
+	}
+
+	b.Close()
+	if _, err := b.Subscribe("c"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from a closed broker, got %v", err)
+	}
+}
+
+func TestBroker_DeliversFromBus(t *testing.T) {
+	bus := events.NewBus(0)
+	defer bus.Close()
+	b := NewBroker(bus, Config{}, metrics.NewRegistry())
+	defer b.Close()
+	sub, err := b.Subscribe("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(events.Event{Type: events.TypeServerStarted})
+	bus.Publish(events.Event{Type: events.TypeExpire, Key: "k1"})
+	if e, err := next(t, sub); err != nil || e.Type != events.TypeExpire || e.Key != "k1" {
+		t.Errorf("Next() = %+v, %v; want only the expiration of k1", e, err)
+	}
+}
@@ -0,0 +1,152 @@
+// Package ratelimit provides a token bucket rate limiter.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket refilled at a constant rate up to a burst size.
+// Requests larger than the burst are admitted once the bucket is full and
+// leave it in debt, so they are slowed down rather than rejected forever.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// New creates a limiter allowing rate tokens per second with the given burst.
+// A non-positive burst defaults to one second worth of tokens.
+func New(rate, burst float64) *Limiter {
+	if burst <= 0 {
+		burst = rate
+	}
+	return &Limiter{rate: rate, burst: burst, tokens: burst, last: time.Now(), now: time.Now}
+}
+
+// refill adds the tokens accumulated since the last call. Callers must hold mu.
+func (l *Limiter) refill() {
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// Allow takes n tokens if they are available now and reports whether it did.
+func (l *Limiter) Allow(n float64) bool {
+	_, ok := l.Take(n)
+	return ok
+}
+
+// Take takes n tokens if they are available now and reports whether it did.
+// Otherwise it takes none and returns how long until they will be available.
+func (l *Limiter) Take(n float64) (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	needed := min(n, l.burst)
+	if l.tokens < needed {
+		return time.Duration((needed - l.tokens) / l.rate * float64(time.Second)), false
+	}
+	l.tokens -= n
+	return 0, true
+}
+
+// Wait takes n tokens, blocking until they are available or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, n float64) error {
+	l.mu.Lock()
+	l.refill()
+	// Oversized requests wait for a full bucket instead of an impossible balance
+	needed := min(n, l.burst)
+	if l.tokens >= needed {
+		l.tokens -= n
+		l.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration((needed - l.tokens) / l.rate * float64(time.Second))
+	// Reserve the tokens now so later callers queue behind this one
+	l.tokens -= n
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += n
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Keyed holds a limiter per key, such as per client, each created on first
+// use with the same rate and burst. Limiters idle long enough to have
+// refilled completely are dropped, since a new one starts out the same.
+type Keyed struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	limiters  map[string]*keyedLimiter
+	idle      time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// keyedLimiter is a limiter of a Keyed with the time it was last used.
+type keyedLimiter struct {
+	*Limiter
+	used time.Time
+}
+
+// NewKeyed creates limiters allowing rate tokens per second with the given
+// burst to each key. A non-positive burst defaults to one second worth of
+// tokens.
+func NewKeyed(rate, burst float64) *Keyed {
+	if burst <= 0 {
+		burst = rate
+	}
+	// Never sweep more than once a minute, however fast the buckets refill
+	idle := max(time.Duration(burst/rate*float64(time.Second)), time.Minute)
+	return &Keyed{
+		rate:      rate,
+		burst:     burst,
+		limiters:  make(map[string]*keyedLimiter),
+		idle:      idle,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Limiter returns the limiter of key.
+func (k *Keyed) Limiter(key string) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	if now.Sub(k.lastSweep) >= k.idle {
+		for key, limiter := range k.limiters {
+			if now.Sub(limiter.used) >= k.idle {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastSweep = now
+	}
+	limiter, ok := k.limiters[key]
+	if !ok {
+		l := New(k.rate, k.burst)
+		l.now, l.last = k.now, now
+		limiter = &keyedLimiter{Limiter: l}
+		k.limiters[key] = limiter
+	}
+	limiter.used = now
+	return limiter.Limiter
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a limiter whose time only moves when advance is called
+func fakeClock(l *Limiter) (advance func(time.Duration)) {
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+	l.last = now
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l := New(10, 5)
+	advance := fakeClock(l)
+
+	for i := 0; i < 5; i++ {
+		if !l.Allow(1) {
+			t.Fatalf("Expected burst request %d to be allowed", i)
+		}
+	}
+	if l.Allow(1) {
+		t.Fatal("Expected request beyond the burst to be rejected")
+	}
+
+	advance(200 * time.Millisecond)
+	if !l.Allow(2) || l.Allow(1) {
+		t.Error("Expected exactly two tokens after 200ms at 10/s")
+	}
+
+	advance(time.Hour)
+	if !l.Allow(50) {
+		t.Error("Expected an oversized request to be admitted from a full bucket")
+	}
+	advance(time.Second)
+	if l.Allow(1) {
+		t.Error("Expected the oversized request to leave the bucket in debt")
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := New(100, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected waits to be spread out, took %v", elapsed)
+	}
+
+	slow := New(0.001, 1)
+	slow.Allow(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Wait(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestLimiter_Take(t *testing.T) {
+	l := New(10, 2)
+	advance := fakeClock(l)
+
+	l.Allow(2)
+	wait, ok := l.Take(1)
+	if ok || wait != 100*time.Millisecond {
+		t.Errorf("Take = %v, %v, want a 100ms wait", wait, ok)
+	}
+	advance(wait)
+	if _, ok := l.Take(1); !ok {
+		t.Error("Expected a token after the wait")
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	k := NewKeyed(1, 1)
+	now := time.Unix(0, 0)
+	k.now, k.lastSweep = func() time.Time { return now }, now
+
+	if !k.Limiter("alice").Allow(1) || k.Limiter("alice").Allow(1) {
+		t.Error("Expected alice to get a single token")
+	}
+	if !k.Limiter("bob").Allow(1) {
+		t.Error("Expected bob to have a bucket of their own")
+	}
+
+	now = now.Add(30 * time.Second)
+	k.Limiter("alice")
+	now = now.Add(45 * time.Second)
+	k.Limiter("alice")
+	if _, ok := k.limiters["bob"]; ok || len(k.limiters) != 1 {
+		t.Errorf("Expected the idle limiter of bob to be dropped, have %d", len(k.limiters))
+	}
+}
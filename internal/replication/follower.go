@@ -0,0 +1,180 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRetryInterval is the time a follower waits before reconnecting to
+// its primary.
+const DefaultRetryInterval = 5 * time.Second
+
+// ErrReseedRequired is returned by Follower.Run when the primary can no
+// longer resume from the follower's position.
+var ErrReseedRequired = errors.New("follower must be reseeded from the primary")
+
+// Position is the last mutation a follower applied.
+type Position struct {
+	LogID    string `json:"log_id"`
+	Sequence uint64 `json:"sequence"`
+}
+
+// FollowerConfig configures a Follower.
+type FollowerConfig struct {
+	// StateFile persists the follower's position, so it resumes where it
+	// stopped when restarted. Without one it starts from the oldest mutation
+	// the primary retains every time.
+	StateFile string
+	// RetryInterval defaults to DefaultRetryInterval.
+	RetryInterval time.Duration
+	Logger        *slog.Logger
+}
+
+// Follower applies the mutations streamed by a primary to a store, in order.
+// A mutation applied again after a crash before its position was saved has
+// the same outcome, so the follower's store ends up as the primary's.
+type Follower struct {
+	primary  proto.ClavisAdminClient
+	store    store.Store
+	config   FollowerConfig
+	position Position
+}
+
+// NewFollower creates a follower replicating from the primary at the other
+// end of conn into s, resuming from the position in the state file if there
+// is one.
+func NewFollower(conn grpc.ClientConnInterface, s store.Store, config FollowerConfig) (*Follower, error) {
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	f := &Follower{primary: proto.NewClavisAdminClient(conn), store: s, config: config}
+	if config.StateFile != "" {
+		data, err := os.ReadFile(config.StateFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &f.position); err != nil {
+				return nil, fmt.Errorf("invalid replication state file %s: %w", config.StateFile, err)
+			}
+		}
+	}
+	return f, nil
+}
+
+// Run replicates until ctx is done, reconnecting whenever the stream breaks.
+// It returns an error wrapping ErrReseedRequired if the primary no longer
+// has the mutations following the follower's position.
+func (f *Follower) Run(ctx context.Context) error {
+	for {
+		err := f.replicate(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if status.Code(err) == codes.FailedPrecondition {
+			return fmt.Errorf("%w: %s", ErrReseedRequired, status.Convert(err).Message())
+		}
+		f.config.Logger.Warn("Replication stream broke, reconnecting", "error", err, "log_id", f.position.LogID, "sequence", f.position.Sequence, "retry_in", f.config.RetryInterval)
+
+		select {
+		case <-time.After(f.config.RetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replicate streams from the primary until the stream fails.
+func (f *Follower) replicate(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := f.primary.Replicate(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&proto.ReplicateRequest{LogId: f.position.LogID, AfterSequence: f.position.Sequence}); err != nil {
+		return err
+	}
+	f.config.Logger.Info("Replicating from primary", "log_id", f.position.LogID, "after_sequence", f.position.Sequence)
+
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := f.apply(batch); err != nil {
+			return err
+		}
+		if err := stream.Send(&proto.ReplicateRequest{AckedSequence: f.position.Sequence}); err != nil {
+			return err
+		}
+	}
+}
+
+// apply applies the mutations of batch in order and saves the position they
+// reach.
+func (f *Follower) apply(batch *proto.ReplicateBatch) error {
+	for _, m := range batch.Mutations {
+		// A follower that has not replicated yet starts wherever the log does
+		if f.position.LogID != "" && (batch.LogId != f.position.LogID || m.Sequence != f.position.Sequence+1) {
+			return fmt.Errorf("primary sent mutation %d of log %s after %d of log %s", m.Sequence, batch.LogId, f.position.Sequence, f.position.LogID)
+		}
+		if err := f.applyMutation(m); err != nil {
+			return fmt.Errorf("applying mutation %d: %w", m.Sequence, err)
+		}
+		f.position = Position{LogID: batch.LogId, Sequence: m.Sequence}
+	}
+	return f.save()
+}
+
+func (f *Follower) applyMutation(m *proto.Mutation) error {
+	switch m.Op {
+	case proto.Mutation_OP_PUT:
+		return f.store.Put(m.Key, m.Value)
+	case proto.Mutation_OP_DELETE:
+		return f.store.Delete(m.Key)
+	case proto.Mutation_OP_EXPIRE:
+		return store.ExpireKeys(f.store, []string{m.Key})
+	}
+	return fmt.Errorf("unknown operation %v", m.Op)
+}
+
+// save writes the position to the state file, replacing it atomically.
+func (f *Follower) save() error {
+	if f.config.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(f.position)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(f.config.StateFile), filepath.Base(f.config.StateFile)+".partial-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), f.config.StateFile)
+}
@@ -0,0 +1,155 @@
+// Package replication copies the mutations committed on a primary Clavis
+// server to followers asynchronously. The primary records every committed
+// Put, Delete and expiration in a Log with a sequence number; followers
+// stream the log over the admin service's Replicate RPC, apply it in order
+// and resume after the last mutation they applied when reconnecting.
+//
+// The log lives in memory and holds a bounded number of mutations. A follower
+// that falls further behind, or that followed a primary which has since
+// restarted, cannot resume and must be reseeded, such as from a backup.
+package replication
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Op is the kind of a mutation.
+type Op int
+
+const (
+	OpPut Op = iota + 1
+	OpDelete
+	OpExpire // A key removed by expiration rather than deleted
+)
+
+// Mutation is a committed change to one key.
+type Mutation struct {
+	Sequence uint64
+	Op       Op
+	Key      string
+	Value    []byte // New value for OpPut
+}
+
+// DefaultLogSize is the number of mutations a Log retains by default.
+const DefaultLogSize = 100_000
+
+var (
+	// ErrUnknownLog is returned when resuming from a position in another log,
+	// such as one of a primary that has since restarted.
+	ErrUnknownLog = errors.New("unknown replication log")
+	// ErrTruncated is returned when resuming after mutations the log no
+	// longer retains.
+	ErrTruncated = errors.New("replication log no longer retains the mutations to resume from")
+)
+
+// Log is a bounded, in-memory sequence of the mutations committed on a
+// primary. Sequence numbers start at 1 and have no gaps.
+type Log struct {
+	id string
+
+	mu        sync.Mutex
+	mutations []Mutation // Ring buffer of the retained mutations
+	start     int        // Index of the oldest retained mutation
+	count     int
+	last      uint64        // Sequence number of the newest mutation
+	appended  chan struct{} // Closed and replaced on every append
+}
+
+// NewLog creates an empty log retaining up to size mutations, DefaultLogSize
+// if size is not positive. Every log has a random ID, so followers can tell
+// it from the log of an earlier run of the primary.
+func NewLog(size int) *Log {
+	if size <= 0 {
+		size = DefaultLogSize
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return &Log{
+		id:        hex.EncodeToString(id),
+		mutations: make([]Mutation, size),
+		appended:  make(chan struct{}),
+	}
+}
+
+// ID returns the identifier of the log.
+func (l *Log) ID() string {
+	return l.id
+}
+
+// Append records mutations, in order, assigning their sequence numbers.
+func (l *Log) Append(mutations ...Mutation) {
+	if len(mutations) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, m := range mutations {
+		l.last++
+		m.Sequence = l.last
+		end := (l.start + l.count) % len(l.mutations)
+		l.mutations[end] = m
+		if l.count < len(l.mutations) {
+			l.count++
+		} else {
+			l.start = (l.start + 1) % len(l.mutations)
+		}
+	}
+	close(l.appended)
+	l.appended = make(chan struct{})
+}
+
+// Last returns the sequence number of the newest mutation, 0 if there is
+// none yet.
+func (l *Log) Last() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// Read returns up to limit mutations following sequence number after,
+// waiting until there is at least one or ctx is done. logID must be the ID
+// of the log, or empty to read from the oldest retained mutation regardless
+// of after.
+func (l *Log) Read(ctx context.Context, logID string, after uint64, limit int) ([]Mutation, error) {
+	if logID != "" && logID != l.id {
+		return nil, fmt.Errorf("%w %q: this server's log is %q", ErrUnknownLog, logID, l.id)
+	}
+	for {
+		l.mu.Lock()
+		oldest := l.last - uint64(l.count) + 1
+		if logID == "" {
+			logID, after = l.id, oldest-1
+		}
+		if after > l.last {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("%w: sequence %d is ahead of the log, which ends at %d", ErrUnknownLog, after, l.last)
+		}
+		if after+1 < oldest {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("%w: resuming after %d, oldest retained is %d", ErrTruncated, after, oldest)
+		}
+		if after < l.last {
+			n := min(int(l.last-after), limit)
+			result := make([]Mutation, n)
+			first := l.start + int(after+1-oldest)
+			for i := range result {
+				result[i] = l.mutations[(first+i)%len(l.mutations)]
+			}
+			l.mu.Unlock()
+			return result, nil
+		}
+		appended := l.appended
+		l.mu.Unlock()
+
+		select {
+		case <-appended:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLog_Read(t *testing.T) {
+	ctx := context.Background()
+	log := NewLog(3)
+	log.Append(Mutation{Op: OpPut, Key: "a"}, Mutation{Op: OpPut, Key: "b"})
+
+	t.Run("FromTheStart", func(t *testing.T) {
+		got, err := log.Read(ctx, "", 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Sequence != 1 || got[0].Key != "a" || got[1].Sequence != 2 {
+			t.Errorf("Read = %+v, want mutations 1 and 2", got)
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		got, err := log.Read(ctx, log.ID(), 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Sequence != 1 {
+			t.Errorf("Read = %+v, want mutation 1", got)
+		}
+	})
+
+	t.Run("WaitsForAppends", func(t *testing.T) {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			log.Append(Mutation{Op: OpDelete, Key: "a"})
+		}()
+		got, err := log.Read(ctx, log.ID(), 2, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Sequence != 3 || got[0].Op != OpDelete {
+			t.Errorf("Read = %+v, want the delete as mutation 3", got)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		log.Append(Mutation{Op: OpPut, Key: "c"})
+		if _, err := log.Read(ctx, log.ID(), 0, 10); !errors.Is(err, ErrTruncated) {
+			t.Errorf("Read after 0 = %v, want ErrTruncated", err)
+		}
+		got, err := log.Read(ctx, log.ID(), 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 3 || got[0].Sequence != 2 || got[2].Key != "c" {
+			t.Errorf("Read = %+v, want mutations 2 to 4", got)
+		}
+	})
+
+	t.Run("UnknownLog", func(t *testing.T) {
+		if _, err := log.Read(ctx, "other", 1, 10); !errors.Is(err, ErrUnknownLog) {
+			t.Errorf("Read of another log = %v, want ErrUnknownLog", err)
+		}
+		if _, err := log.Read(ctx, log.ID(), 10, 10); !errors.Is(err, ErrUnknownLog) {
+			t.Errorf("Read ahead of the log = %v, want ErrUnknownLog", err)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := log.Read(ctx, log.ID(), log.Last(), 10); !errors.Is(err, context.Canceled) {
+			t.Errorf("Read = %v, want context.Canceled", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package replication
+
+import (
+	"context"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// LoggingStore wraps a store and appends every successful mutation to a Log.
+// Writes are serialized, so the log orders them as the store committed them.
+type LoggingStore struct {
+	store.Passthrough
+	log *Log
+	mu  sync.Mutex
+}
+
+// NewLoggingStore wraps base so that its mutations are recorded in log.
+func NewLoggingStore(base store.Store, log *Log) *LoggingStore {
+	return &LoggingStore{Passthrough: store.Passthrough{Store: base}, log: log}
+}
+
+// Put stores the value and logs it on success.
+func (ls *LoggingStore) Put(key string, value []byte) error {
+	return ls.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (ls *LoggingStore) PutContext(ctx context.Context, key string, value []byte) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err := store.PutContext(ctx, ls.Store, key, value); err != nil {
+		return err
+	}
+	ls.log.Append(put(key, value))
+	return nil
+}
+
+// PutIfVersion conditionally stores the value and logs it on success.
+func (ls *LoggingStore) PutIfVersion(key string, value []byte, expected uint64) error {
+	return ls.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (ls *LoggingStore) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err := store.PutIfVersionContext(ctx, ls.Store, key, value, expected); err != nil {
+		return err
+	}
+	ls.log.Append(put(key, value))
+	return nil
+}
+
+// Delete removes the key and logs it on success.
+func (ls *LoggingStore) Delete(key string) error {
+	return ls.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (ls *LoggingStore) DeleteContext(ctx context.Context, key string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err := store.DeleteContext(ctx, ls.Store, key); err != nil {
+		return err
+	}
+	ls.log.Append(Mutation{Op: OpDelete, Key: key})
+	return nil
+}
+
+// BatchPut stores the pairs and logs each of them on success.
+func (ls *LoggingStore) BatchPut(pairs map[string][]byte) error {
+	return ls.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (ls *LoggingStore) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err := store.BatchPutContext(ctx, ls.Store, pairs); err != nil {
+		return err
+	}
+	mutations := make([]Mutation, 0, len(pairs))
+	for key, value := range pairs {
+		mutations = append(mutations, put(key, value))
+	}
+	ls.log.Append(mutations...)
+	return nil
+}
+
+// BatchDelete removes the keys and logs each of them on success.
+func (ls *LoggingStore) BatchDelete(keys []string) error {
+	return ls.remove(keys, OpDelete, func() error { return store.BatchDelete(ls.Store, keys) })
+}
+
+// ExpireKeys removes the keys as expired and logs each of them on success.
+func (ls *LoggingStore) ExpireKeys(keys []string) error {
+	return ls.remove(keys, OpExpire, func() error { return store.ExpireKeys(ls.Store, keys) })
+}
+
+// remove runs a removal of keys and logs it with op on success.
+func (ls *LoggingStore) remove(keys []string, op Op, run func() error) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if err := run(); err != nil {
+		return err
+	}
+	mutations := make([]Mutation, len(keys))
+	for i, key := range keys {
+		mutations[i] = Mutation{Op: op, Key: key}
+	}
+	ls.log.Append(mutations...)
+	return nil
+}
+
+// put returns a put mutation with a copy of value.
+func put(key string, value []byte) Mutation {
+	logged := make([]byte, len(value))
+	copy(logged, value)
+	return Mutation{Op: OpPut, Key: key, Value: logged}
+}
+
+var (
+	_ store.Store             = (*LoggingStore)(nil)
+	_ store.Wrapper           = (*LoggingStore)(nil)
+	_ store.Batcher           = (*LoggingStore)(nil)
+	_ store.ConditionalPutter = (*LoggingStore)(nil)
+	_ store.ContextWriter     = (*LoggingStore)(nil)
+	_ store.ContextReader     = (*LoggingStore)(nil)
+	_ store.Expirer           = (*LoggingStore)(nil)
+)
@@ -0,0 +1,54 @@
+package replication
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestLoggingStore(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := NewLog(0)
+	s := NewLoggingStore(base, log)
+
+	value := []byte("1")
+	if err := s.Put("a", value); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = '2'
+	if err := store.BatchPut(s, map[string][]byte{"b": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ExpireKeys(s, []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutIfVersion(s, "c", nil, 1); err == nil {
+		t.Fatal("PutIfVersion of a missing key at version 1 succeeded")
+	}
+
+	want := []Mutation{
+		{Sequence: 1, Op: OpPut, Key: "a", Value: []byte("1")},
+		{Sequence: 2, Op: OpPut, Key: "b", Value: []byte("3")},
+		{Sequence: 3, Op: OpDelete, Key: "a"},
+		{Sequence: 4, Op: OpExpire, Key: "b"},
+	}
+	if log.Last() != uint64(len(want)) {
+		t.Fatalf("Last = %d, want %d; failed writes must not be logged", log.Last(), len(want))
+	}
+	got, err := log.Read(t.Context(), log.ID(), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if got[i].Sequence != want[i].Sequence || got[i].Op != want[i].Op || got[i].Key != want[i].Key || string(got[i].Value) != string(want[i].Value) {
+			t.Errorf("mutation %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
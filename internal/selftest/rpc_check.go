@@ -0,0 +1,34 @@
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// RPCCheck dials the server at addr and performs a Get on a scratch key,
+// proving that the listener accepts connections with the given dial options.
+func RPCCheck(addr string, opts ...grpc.DialOption) Check {
+	return Check{
+		Name: "rpc loopback",
+		Run: func(ctx context.Context) error {
+			conn, err := grpc.NewClient(addr, opts...)
+			if err != nil {
+				return fmt.Errorf("failed to create client for %s: %w", addr, err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			client := proto.NewClavisClient(conn)
+			if _, err := client.Get(ctx, &proto.GetRequest{Key: ScratchPrefix + "rpc"}, grpc.WaitForReady(true)); err != nil {
+				return fmt.Errorf("get over %s failed: %w", addr, err)
+			}
+			return nil
+		},
+	}
+}
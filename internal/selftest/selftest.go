@@ -0,0 +1,93 @@
+// Package selftest runs a quick suite of checks against a live Clavis
+// configuration so deployments can fail fast before serving traffic.
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+)
+
+// Check is a named probe run by the self-test.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result records the outcome of a single check.
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Report is the structured outcome of a self-test run.
+type Report struct {
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Run executes the checks in order and returns a report. Every check runs even
+// if an earlier one fails, so the report lists all problems at once.
+func Run(ctx context.Context, checks ...Check) Report {
+	report := Report{Passed: true}
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run(ctx)
+
+		result := Result{Name: check.Name, Status: StatusPass, Duration: time.Since(start)}
+		if err != nil {
+			result.Status = StatusFail
+			result.Error = err.Error()
+			report.Passed = false
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// DirWritableCheck verifies that a file can be created and removed in dir.
+func DirWritableCheck(name, dir string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			f, err := os.CreateTemp(dir, ".clavis-selftest-*")
+			if err != nil {
+				return fmt.Errorf("directory %s is not writable: %w", dir, err)
+			}
+			path := f.Name()
+			if _, err := f.WriteString("clavis"); err != nil {
+				_ = f.Close()
+				_ = os.Remove(path)
+				return fmt.Errorf("failed to write to %s: %w", dir, err)
+			}
+			if err := f.Close(); err != nil {
+				_ = os.Remove(path)
+				return fmt.Errorf("failed to close %s: %w", filepath.Base(path), err)
+			}
+			return os.Remove(path)
+		},
+	}
+}
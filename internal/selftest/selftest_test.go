@@ -0,0 +1,103 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"github.com/William-Fernandes252/clavis/internal/store/softdelete"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("AllChecksPass", func(t *testing.T) {
+		report := Run(context.Background(),
+			Check{Name: "ok", Run: func(context.Context) error { return nil }},
+		)
+		if !report.Passed {
+			t.Error("Expected report to pass")
+		}
+		if len(report.Results) != 1 || report.Results[0].Status != StatusPass {
+			t.Errorf("Unexpected results: %+v", report.Results)
+		}
+	})
+
+	t.Run("FailureDoesNotStopLaterChecks", func(t *testing.T) {
+		ran := false
+		report := Run(context.Background(),
+			Check{Name: "broken", Run: func(context.Context) error { return errors.New("boom") }},
+			Check{Name: "later", Run: func(context.Context) error { ran = true; return nil }},
+		)
+		if report.Passed {
+			t.Error("Expected report to fail")
+		}
+		if !ran {
+			t.Error("Expected later check to run after a failure")
+		}
+		if report.Results[0].Status != StatusFail || report.Results[0].Error != "boom" {
+			t.Errorf("Unexpected first result: %+v", report.Results[0])
+		}
+	})
+}
+
+func TestStoreCheck(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	if err := StoreCheck(s).Run(context.Background()); err != nil {
+		t.Fatalf("StoreCheck failed: %v", err)
+	}
+
+	leftovers, err := s.Scan(ScratchPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("Expected scratch namespace to be empty, found %d keys", len(leftovers))
+	}
+
+	// Soft-delete keeps a tombstone of deleted keys in the store it wraps
+	if err := StoreCheck(softdelete.New(s, softdelete.Config{Retention: time.Hour})).Run(context.Background()); err != nil {
+		t.Fatalf("StoreCheck through soft-delete failed: %v", err)
+	}
+	if leftovers, err := s.Scan(ScratchPrefix); err != nil || len(leftovers) != 0 {
+		t.Errorf("Expected no tombstone left in the scratch namespace, found %d keys (%v)", len(leftovers), err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreCheck(s).Run(context.Background()); err == nil {
+		t.Error("Expected StoreCheck to fail on a closed store")
+	}
+}
+
+func TestDirWritableCheck(t *testing.T) {
+	dir := t.TempDir()
+	if err := DirWritableCheck("tmp", dir).Run(context.Background()); err != nil {
+		t.Errorf("Expected writable directory, got %v", err)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	if err := DirWritableCheck("missing", missing).Run(context.Background()); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected probe files to be removed, found %d entries", len(entries))
+	}
+}
@@ -0,0 +1,73 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// ScratchPrefix is the key prefix under which self-test data is written.
+const ScratchPrefix = "__clavis/selftest/"
+
+// StoreCheck exercises put/get/scan/delete against s inside a scratch
+// namespace and removes everything it wrote. Wrappers such as soft-delete
+// keep a tombstone of the deleted key, so it is deleted from the innermost
+// store as well.
+func StoreCheck(s store.Store) Check {
+	return Check{
+		Name: "store round trip",
+		Run: func(ctx context.Context) error {
+			prefix := fmt.Sprintf("%s%d/", ScratchPrefix, time.Now().UnixNano())
+			key := prefix + "probe"
+			value := []byte("clavis-selftest")
+
+			if err := s.Put(key, value); err != nil {
+				return fmt.Errorf("put failed: %w", err)
+			}
+			defer func() {
+				_ = s.Delete(key)
+				_ = innermost(s).Delete(key)
+			}()
+
+			got, found, err := s.Get(key)
+			if err != nil {
+				return fmt.Errorf("get failed: %w", err)
+			}
+			if !found || !bytes.Equal(got, value) {
+				return fmt.Errorf("get returned unexpected value (found=%t)", found)
+			}
+
+			results, err := s.Scan(prefix)
+			if err != nil {
+				return fmt.Errorf("scan failed: %w", err)
+			}
+			if len(results) != 1 {
+				return fmt.Errorf("scan returned %d keys, expected 1", len(results))
+			}
+
+			if err := s.Delete(key); err != nil {
+				return fmt.Errorf("delete failed: %w", err)
+			}
+			if _, found, err := s.Get(key); err != nil || found {
+				return fmt.Errorf("key still present after delete (err=%v)", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// innermost returns the store at the end of the chain of wrappers starting
+// at s.
+func innermost(s store.Store) store.Store {
+	for {
+		wrapper, ok := s.(store.Wrapper)
+		if !ok {
+			return s
+		}
+		s = wrapper.Unwrap()
+	}
+}
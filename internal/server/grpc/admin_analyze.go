@@ -0,0 +1,68 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/capacity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AnalyzeValues samples stored values and reports, per prefix, how many are
+// duplicated and how well they compress, with recommendations.
+func (a *AdminServer) AnalyzeValues(ctx context.Context, req *proto.AnalyzeValuesRequest) (*proto.AnalyzeValuesResponse, error) {
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		return nil, status.Error(codes.InvalidArgument, "sample rate must be between 0 and 1")
+	}
+
+	config := capacity.Config{SampleRate: req.SampleRate, MaxSamples: int(req.MaxSamplesPerPrefix)}
+	report, err := capacity.Analyze(ctx, a.store, config)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.FromContextError(err).Err()
+		}
+		return nil, convertError(err)
+	}
+
+	resp := &proto.AnalyzeValuesResponse{
+		Prefixes:    make([]*proto.PrefixValueReport, 0, len(report.Prefixes)),
+		ScannedKeys: report.ScannedKeys,
+		SampledKeys: report.SampledKeys,
+		DurationMs:  report.Duration.Milliseconds(),
+	}
+	for _, p := range report.Prefixes {
+		out := &proto.PrefixValueReport{
+			Prefix:          p.Prefix,
+			SampledValues:   p.SampledValues,
+			SampledBytes:    p.SampledBytes,
+			DuplicateValues: p.DuplicateValues,
+			DuplicateBytes:  p.DuplicateBytes,
+			CompressedBytes: p.CompressedBytes,
+		}
+		for _, r := range p.Recommendations {
+			out.Recommendations = append(out.Recommendations, recommendationToProto(r, p))
+		}
+		resp.Prefixes = append(resp.Prefixes, out)
+	}
+	return resp, nil
+}
+
+func recommendationToProto(r capacity.Recommendation, p capacity.PrefixReport) *proto.ValueRecommendation {
+	switch r {
+	case capacity.RecommendDedup:
+		return &proto.ValueRecommendation{
+			Kind:   proto.ValueRecommendation_KIND_DEDUP,
+			Reason: fmt.Sprintf("%.0f%% of sampled bytes are duplicates", 100*p.DuplicateRatio()),
+		}
+	case capacity.RecommendCompression:
+		return &proto.ValueRecommendation{
+			Kind:   proto.ValueRecommendation_KIND_COMPRESSION,
+			Reason: fmt.Sprintf("sampled values compress to %.0f%% of their size", 100*p.CompressionRatio()),
+		}
+	default:
+		return &proto.ValueRecommendation{Reason: string(r)}
+	}
+}
@@ -0,0 +1,46 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_AnalyzeValues(t *testing.T) {
+	s := newMockStore()
+	for i := range 50 {
+		s.data[fmt.Sprintf("docs/%d", i)] = []byte(strings.Repeat("same document ", 10))
+	}
+	admin := NewAdminServer(s, AdminServerConfig{})
+
+	if _, err := admin.AnalyzeValues(context.Background(), &proto.AnalyzeValuesRequest{SampleRate: 2}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a sample rate above 1, got %v", err)
+	}
+
+	resp, err := admin.AnalyzeValues(context.Background(), &proto.AnalyzeValuesRequest{SampleRate: 1})
+	if err != nil {
+		t.Fatalf("AnalyzeValues failed: %v", err)
+	}
+	if resp.ScannedKeys != 50 || len(resp.Prefixes) != 1 {
+		t.Fatalf("Unexpected report: %+v", resp)
+	}
+	docs := resp.Prefixes[0]
+	if docs.Prefix != "docs" || docs.DuplicateValues != 49 {
+		t.Errorf("Unexpected prefix report: %+v", docs)
+	}
+	kinds := make(map[proto.ValueRecommendation_Kind]bool)
+	for _, r := range docs.Recommendations {
+		kinds[r.Kind] = true
+		if r.Reason == "" {
+			t.Errorf("Expected a reason for %v", r.Kind)
+		}
+	}
+	if !kinds[proto.ValueRecommendation_KIND_DEDUP] || !kinds[proto.ValueRecommendation_KIND_COMPRESSION] {
+		t.Errorf("Expected dedup and compression to be recommended, got %v", docs.Recommendations)
+	}
+}
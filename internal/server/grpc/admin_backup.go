@@ -0,0 +1,208 @@
+package proto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/backup"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// backupChunkSize is the most snapshot data sent in one message.
+const backupChunkSize = 64 * 1024
+
+// Backup streams a consistent snapshot of the store, or of its changes since
+// the version requested.
+func (a *AdminServer) Backup(req *proto.BackupRequest, stream grpc.ServerStreamingServer[proto.BackupChunk]) error {
+	ctx := stream.Context()
+	start := time.Now()
+	counter := &countingWriter{w: chunkSender(func(data []byte) error {
+		return stream.Send(&proto.BackupChunk{Data: data})
+	})}
+	buffered := bufio.NewWriterSize(counter, backupChunkSize)
+
+	w, err := backup.NewWriter(buffered, req.SinceVersion)
+	if err != nil {
+		return convertBackupError(err)
+	}
+	until, err := store.Backup(a.store, w, req.SinceVersion)
+	if err != nil {
+		return convertBackupError(err)
+	}
+	if err := w.Finish(until); err != nil {
+		return convertBackupError(err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return convertBackupError(err)
+	}
+	logging.FromContext(ctx).Info("Backed up store", "since_version", req.SinceVersion, "until_version", until, "bytes", counter.n, "duration", time.Since(start), "requested_by", callerIdentity(ctx))
+	return nil
+}
+
+// Restore loads a snapshot streamed by the client into the store, which must
+// hold no keys for a full snapshot and the changes an incremental one builds
+// on otherwise. Without a confirmation token in the first chunk, the snapshot
+// is only checked and a token issued for restoring it. The snapshot's
+// checksum is only known at its end, so a snapshot corrupted since its
+// preview fails after its keys were loaded, leaving a store that should be
+// discarded.
+func (a *AdminServer) Restore(stream grpc.ClientStreamingServer[proto.RestoreChunk, proto.RestoreResponse]) error {
+	ctx := stream.Context()
+	if a.config.Confirmations == nil {
+		return errSubsystemDisabled("destructive operations")
+	}
+	if _, ok := store.As[store.Backuper](a.store); !ok {
+		return convertBackupError(store.ErrBackupsUnsupported)
+	}
+	start := time.Now()
+	var token string
+	first := true
+	counter := &countingReader{r: &chunkReader{recv: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			token, first = chunk.ConfirmationToken, false
+		}
+		return chunk.Data, nil
+	}}}
+
+	r, err := backup.NewReader(counter)
+	if err != nil {
+		return convertBackupError(err)
+	}
+	keys, err := store.Count(ctx, a.store, "")
+	if err != nil {
+		return convertError(err)
+	}
+	// The token is bound to the snapshot by its header, as its checksum is
+	// only read at its end
+	header := r.Header()
+	action := fmt.Sprintf("restore:%d:%d", header.Since, header.CreatedAt.Unix())
+	if token == "" {
+		return a.previewRestore(stream, r, counter, action, uint64(keys), start) // #nosec G115 -- counts are non-negative
+	}
+
+	if err := a.config.Confirmations.Redeem(token, action); err != nil {
+		return convertConfirmError(err)
+	}
+	if err := store.Restore(a.store, r, header.Since); err != nil {
+		return convertBackupError(err)
+	}
+	header = r.Header()
+	logging.FromContext(ctx).Info("Restored store", "since_version", header.Since, "until_version", header.Until, "bytes", counter.n, "created_at", header.CreatedAt, "duration", time.Since(start), "requested_by", callerIdentity(ctx))
+	return stream.SendAndClose(&proto.RestoreResponse{
+		Bytes:         counter.n,
+		CreatedAtUnix: header.CreatedAt.Unix(),
+		DurationMs:    time.Since(start).Milliseconds(),
+		SinceVersion:  header.Since,
+		UntilVersion:  header.Until,
+		Keys:          uint64(keys), // #nosec G115 -- counts are non-negative
+		Executed:      true,
+	})
+}
+
+// previewRestore reads the rest of the snapshot, checking its checksum, and
+// issues a token for restoring it. A full snapshot is refused if the store
+// holds keys, as restoring it would be.
+func (a *AdminServer) previewRestore(stream grpc.ClientStreamingServer[proto.RestoreChunk, proto.RestoreResponse], r *backup.Reader, counter *countingReader, action string, keys uint64, start time.Time) error {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return convertBackupError(err)
+	}
+	header := r.Header()
+	summary := fmt.Sprintf("restore a full snapshot up to version %d, taken %s, into the empty store", header.Until, header.CreatedAt.UTC().Format(time.RFC3339))
+	if header.Incremental() {
+		summary = fmt.Sprintf("restore an incremental snapshot of versions %d-%d, taken %s, over the %d keys of the store, overwriting or deleting those it changed",
+			header.Since, header.Until, header.CreatedAt.UTC().Format(time.RFC3339), keys)
+	} else if keys > 0 {
+		return convertBackupError(store.ErrStoreNotEmpty)
+	}
+	return stream.SendAndClose(&proto.RestoreResponse{
+		Bytes:         counter.n,
+		CreatedAtUnix: header.CreatedAt.Unix(),
+		DurationMs:    time.Since(start).Milliseconds(),
+		SinceVersion:  header.Since,
+		UntilVersion:  header.Until,
+		Confirmation:  a.confirmation(action, summary),
+		Keys:          keys,
+	})
+}
+
+// chunkSender sends each write as one message. The data is copied, as
+// writers may reuse their buffers once Write returns.
+type chunkSender func(data []byte) error
+
+func (send chunkSender) Write(p []byte) (int, error) {
+	if err := send(append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// chunkReader reads the data of received messages in turn, until recv
+// returns io.EOF.
+type chunkReader struct {
+	recv    func() ([]byte, error)
+	pending []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		data, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.pending = data
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func convertBackupError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrBackupsUnsupported):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, store.ErrStoreNotEmpty), errors.Is(err, store.ErrBackupGap):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, backup.ErrInvalidFormat):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, backup.ErrChecksumMismatch):
+		return status.Error(codes.DataLoss, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return convertError(err)
+}
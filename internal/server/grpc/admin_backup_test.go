@@ -0,0 +1,143 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// startAdmin serves admin and returns a client of it.
+func startAdmin(t *testing.T, admin *AdminServer) proto.ClavisAdminClient {
+	t.Helper()
+	return proto.NewClavisAdminClient(serveAdmin(t, admin))
+}
+
+// serveAdmin serves admin and returns a connection to it.
+func serveAdmin(t *testing.T, admin *AdminServer) *grpc.ClientConn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	admin.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func newBadgerStore(t *testing.T) *badger.BadgerStore {
+	t.Helper()
+	config := badger.DefaultConfig(t.TempDir())
+	config.SyncWrites = false
+	s, err := badger.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestAdminServer_BackupRestore(t *testing.T) {
+	ctx := context.Background()
+	source := newBadgerStore(t)
+	for _, key := range []string{"a", "b", "c"} {
+		if err := source.Put(key, []byte("value-"+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream, err := startAdmin(t, NewAdminServer(source, AdminServerConfig{})).Backup(ctx, &proto.BackupRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot []byte
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Backup failed: %v", err)
+		}
+		snapshot = append(snapshot, chunk.Data...)
+	}
+
+	restore := func(admin proto.ClavisAdminClient, data []byte, token string) (*proto.RestoreResponse, error) {
+		stream, err := admin.Restore(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for len(data) > 0 {
+			n := min(len(data), 100)
+			if err := stream.Send(&proto.RestoreChunk{Data: data[:n], ConfirmationToken: token}); err != nil {
+				break
+			}
+			data, token = data[n:], ""
+		}
+		return stream.CloseAndRecv()
+	}
+	newAdmin := func(s store.Store) proto.ClavisAdminClient {
+		return startAdmin(t, NewAdminServer(s, AdminServerConfig{Confirmations: confirm.NewIssuer(0)}))
+	}
+
+	target := newBadgerStore(t)
+	targetAdmin := newAdmin(target)
+	preview, err := restore(targetAdmin, snapshot, "")
+	if err != nil {
+		t.Fatalf("Restore preview failed: %v", err)
+	}
+	if preview.Executed || preview.Confirmation.GetToken() == "" || preview.Bytes != int64(len(snapshot)) || preview.UntilVersion == 0 {
+		t.Errorf("Unexpected preview %+v for a %d byte snapshot", preview, len(snapshot))
+	}
+	if found, _ := store.Exists(target, "c"); found {
+		t.Error("Expected the preview not to restore any key")
+	}
+	if _, err := restore(targetAdmin, snapshot, "unknown"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a token never issued, got %v", err)
+	}
+
+	resp, err := restore(targetAdmin, snapshot, preview.Confirmation.Token)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if !resp.Executed || resp.Bytes != int64(len(snapshot)) || resp.CreatedAtUnix == 0 {
+		t.Errorf("Unexpected response %+v for a %d byte snapshot", resp, len(snapshot))
+	}
+	if value, found, _ := target.Get("c"); !found || string(value) != "value-c" {
+		t.Errorf("Expected the restored key, got %q %v", value, found)
+	}
+	if _, err := restore(targetAdmin, snapshot, preview.Confirmation.Token); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition reusing a token, got %v", err)
+	}
+
+	if _, err := restore(targetAdmin, snapshot, ""); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition previewing a full restore into a store with keys, got %v", err)
+	}
+	if _, err := restore(newAdmin(newBadgerStore(t)), snapshot[:len(snapshot)-1], ""); status.Code(err) != codes.DataLoss {
+		t.Errorf("Expected DataLoss previewing a truncated snapshot, got %v", err)
+	}
+	if _, err := restore(newAdmin(newMockStore()), snapshot, ""); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented for a store without backups, got %v", err)
+	}
+	if _, err := restore(startAdmin(t, NewAdminServer(newBadgerStore(t), AdminServerConfig{})), snapshot, ""); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without confirmations, got %v", err)
+	}
+}
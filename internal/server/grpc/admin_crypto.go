@@ -0,0 +1,35 @@
+package proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+)
+
+// Rewrap re-encrypts the values not encrypted with the active
+// data-encryption key, after rotating to a new one if requested. Writes are
+// held off while each batch of values is re-encrypted.
+func (a *AdminServer) Rewrap(ctx context.Context, req *proto.RewrapRequest) (*proto.RewrapResponse, error) {
+	if a.config.Encryption == nil {
+		return nil, errSubsystemDisabled("encryption keys")
+	}
+	start := time.Now()
+	if req.Rotate {
+		if _, err := a.config.Encryption.Rotate(); err != nil {
+			return nil, convertError(err)
+		}
+	}
+	stats, err := a.config.Encryption.Rewrap(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	logging.FromContext(ctx).Info("Rewrapped values", "key_id", stats.KeyID, "rotated", req.Rotate, "scanned", stats.Scanned, "rewrapped", stats.Rewrapped, "duration", time.Since(start), "requested_by", callerIdentity(ctx))
+	return &proto.RewrapResponse{
+		KeyId:           stats.KeyID,
+		ScannedValues:   uint64(stats.Scanned),
+		RewrappedValues: uint64(stats.Rewrapped),
+		DurationMs:      time.Since(start).Milliseconds(),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package proto
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store/crypto"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := base.Put("legacy", []byte("plain")); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := crypto.New(base, crypto.Config{MasterKey: bytes.Repeat([]byte{1}, crypto.KeySize)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := encrypted.Put("a", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	admin := startAdmin(t, NewAdminServer(encrypted, AdminServerConfig{Encryption: encrypted}))
+
+	resp, err := admin.Rewrap(ctx, &proto.RewrapRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.KeyId != 1 || resp.ScannedValues != 2 || resp.RewrappedValues != 1 {
+		t.Errorf("Rewrap = %+v, want the legacy value encrypted with key 1", resp)
+	}
+
+	resp, err = admin.Rewrap(ctx, &proto.RewrapRequest{Rotate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.KeyId != 2 || resp.RewrappedValues != 2 {
+		t.Errorf("Rewrap with rotation = %+v, want both values encrypted with key 2", resp)
+	}
+	if value, _, _ := encrypted.Get("legacy"); string(value) != "plain" {
+		t.Errorf("Expected the rewrapped value to read back, got %q", value)
+	}
+}
+
+func TestAdminServer_Rewrap_Disabled(t *testing.T) {
+	admin := startAdmin(t, NewAdminServer(newMockStore(), AdminServerConfig{}))
+	if _, err := admin.Rewrap(context.Background(), &proto.RewrapRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Rewrap = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,141 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DropPrefix deletes every key under a prefix. Without a confirmation token
+// it only reports what would be deleted and issues a token for doing so.
+func (a *AdminServer) DropPrefix(ctx context.Context, req *proto.DropPrefixRequest) (*proto.DropPrefixResponse, error) {
+	if a.config.Confirmations == nil {
+		return nil, errSubsystemDisabled("destructive operations")
+	}
+	if req.Prefix == "" {
+		return nil, status.Error(codes.InvalidArgument, "prefix is required")
+	}
+
+	action := "drop-prefix:" + req.Prefix
+	if req.ConfirmationToken == "" {
+		keys, bytes, err := prefixUsage(a.store, req.Prefix)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		summary := fmt.Sprintf("delete %d keys (%d bytes) under prefix %q", keys, bytes, req.Prefix)
+		return &proto.DropPrefixResponse{Confirmation: a.confirmation(action, summary), Keys: keys, Bytes: bytes}, nil
+	}
+
+	if err := a.config.Confirmations.Redeem(req.ConfirmationToken, action); err != nil {
+		return nil, convertConfirmError(err)
+	}
+	keys, bytes, err := dropPrefix(a.store, req.Prefix)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	logging.FromContext(ctx).Info("Dropped prefix", "prefix", req.Prefix, "keys", keys, "requested_by", callerIdentity(ctx))
+	return &proto.DropPrefixResponse{Keys: keys, Bytes: bytes, Executed: true}, nil
+}
+
+// DeleteNamespace deletes every key of a namespace and its settings. Without
+// a confirmation token it only reports what would be deleted and issues a
+// token for doing so.
+func (a *AdminServer) DeleteNamespace(ctx context.Context, req *proto.DeleteNamespaceRequest) (*proto.DeleteNamespaceResponse, error) {
+	if a.config.Confirmations == nil {
+		return nil, errSubsystemDisabled("destructive operations")
+	}
+	if err := settings.ValidateNamespace(req.Namespace); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	prefix := req.Namespace + settings.NamespaceSeparator
+	action := "delete-namespace:" + req.Namespace
+	if req.ConfirmationToken == "" {
+		keys, bytes, err := prefixUsage(a.store, prefix)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		summary := fmt.Sprintf("delete namespace %q with its settings and %d keys (%d bytes)", req.Namespace, keys, bytes)
+		return &proto.DeleteNamespaceResponse{Confirmation: a.confirmation(action, summary), Keys: keys, Bytes: bytes}, nil
+	}
+
+	if err := a.config.Confirmations.Redeem(req.ConfirmationToken, action); err != nil {
+		return nil, convertConfirmError(err)
+	}
+	keys, bytes, err := dropPrefix(a.store, prefix)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if a.config.Settings != nil {
+		if err := a.config.Settings.Delete(req.Namespace); err != nil {
+			return nil, convertSettingsError(err)
+		}
+	}
+	logging.FromContext(ctx).Info("Deleted namespace", "namespace", req.Namespace, "keys", keys, "requested_by", callerIdentity(ctx))
+	return &proto.DeleteNamespaceResponse{Keys: keys, Bytes: bytes, Executed: true}, nil
+}
+
+// confirmation issues a token for action.
+func (a *AdminServer) confirmation(action, summary string) *proto.Confirmation {
+	token, expires := a.config.Confirmations.Issue(action)
+	return &proto.Confirmation{Token: token, ExpiresAtUnixNano: expires.UnixNano(), Summary: summary}
+}
+
+func convertConfirmError(err error) error {
+	if errors.Is(err, confirm.ErrInvalidToken) || errors.Is(err, confirm.ErrExpiredToken) {
+		return status.Errorf(codes.FailedPrecondition, "%v; request a new one by repeating the call without a token", err)
+	}
+	return convertError(err)
+}
+
+// prefixUsage counts the keys under prefix and the bytes of their values.
+func prefixUsage(s store.Store, prefix string) (keys, bytes uint64, err error) {
+	_, keys, bytes, err = listPrefix(s, prefix)
+	return keys, bytes, err
+}
+
+// dropPrefix deletes every key under prefix in batches and reports what was
+// deleted.
+func dropPrefix(s store.Store, prefix string) (keys, bytes uint64, err error) {
+	listed, _, total, err := listPrefix(s, prefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	for start := 0; start < len(listed); start += MaxBatchSize {
+		batch := listed[start:min(start+MaxBatchSize, len(listed))]
+		if err := store.BatchDelete(s, batch); err != nil {
+			return keys, bytes, err
+		}
+		keys += uint64(len(batch))
+	}
+	return keys, total, nil
+}
+
+// listPrefix returns the keys under prefix with their count and total value
+// size. The iterator is closed before returning, so the keys can be deleted
+// right away.
+func listPrefix(s store.Store, prefix string) (listed []string, keys, bytes uint64, err error) {
+	it, err := store.NewIterator(s, prefix)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for it.Next() {
+		listed = append(listed, it.Key())
+		bytes += uint64(len(it.Value()))
+	}
+	return listed, uint64(len(listed)), bytes, it.Err()
+}
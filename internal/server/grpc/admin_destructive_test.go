@@ -0,0 +1,111 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_DropPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Disabled", func(t *testing.T) {
+		admin := NewAdminServer(newMockStore(), AdminServerConfig{})
+		if _, err := admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: "a/"}); status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected Unimplemented without confirmations, got %v", err)
+		}
+	})
+
+	s := newMockStore()
+	s.data = map[string][]byte{"a/1": []byte("xx"), "a/2": []byte("yyy"), "b/1": []byte("z")}
+	admin := NewAdminServer(s, AdminServerConfig{Confirmations: confirm.NewIssuer(0)})
+
+	if _, err := admin.DropPrefix(ctx, &proto.DropPrefixRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an empty prefix, got %v", err)
+	}
+
+	preview, err := admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: "a/"})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.Executed || preview.Keys != 2 || preview.Bytes != 5 || preview.Confirmation.GetToken() == "" {
+		t.Fatalf("Unexpected preview: %+v", preview)
+	}
+	if len(s.data) != 3 {
+		t.Fatal("Expected the preview not to delete anything")
+	}
+
+	// A token only confirms the operation it was issued for
+	_, err = admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: "b/", ConfirmationToken: preview.Confirmation.Token})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a token of another prefix, got %v", err)
+	}
+
+	dropped, err := admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: "a/", ConfirmationToken: preview.Confirmation.Token})
+	if err != nil {
+		t.Fatalf("DropPrefix failed: %v", err)
+	}
+	if !dropped.Executed || dropped.Keys != 2 {
+		t.Errorf("Unexpected result: %+v", dropped)
+	}
+	if _, ok := s.data["b/1"]; !ok || len(s.data) != 1 {
+		t.Errorf("Expected only b/1 to remain, got %v", s.data)
+	}
+
+	_, err = admin.DropPrefix(ctx, &proto.DropPrefixRequest{Prefix: "a/", ConfirmationToken: preview.Confirmation.Token})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected a used token to be rejected, got %v", err)
+	}
+}
+
+func TestAdminServer_DeleteNamespace(t *testing.T) {
+	ctx := context.Background()
+	s := newMockStore()
+	manager := settings.NewManager(s, nil)
+	defer manager.Close()
+	admin := NewAdminServer(s, AdminServerConfig{Settings: manager, Confirmations: confirm.NewIssuer(0)})
+
+	if _, err := manager.Set("tenant", settings.NamespaceSettings{MaxKeys: 5}, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	s.data["tenant/1"] = []byte("a")
+	s.data["tenant-b/1"] = []byte("b")
+
+	if _, err := admin.DeleteNamespace(ctx, &proto.DeleteNamespaceRequest{Namespace: "bad/name"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid namespace, got %v", err)
+	}
+
+	preview, err := admin.DeleteNamespace(ctx, &proto.DeleteNamespaceRequest{Namespace: "tenant"})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.Keys != 1 || preview.Confirmation.GetToken() == "" {
+		t.Fatalf("Unexpected preview: %+v", preview)
+	}
+
+	deleted, err := admin.DeleteNamespace(ctx, &proto.DeleteNamespaceRequest{Namespace: "tenant", ConfirmationToken: preview.Confirmation.Token})
+	if err != nil {
+		t.Fatalf("DeleteNamespace failed: %v", err)
+	}
+	if !deleted.Executed || deleted.Keys != 1 {
+		t.Errorf("Unexpected result: %+v", deleted)
+	}
+	if _, ok := s.data["tenant/1"]; ok {
+		t.Error("Expected the namespace keys to be deleted")
+	}
+	if _, ok := s.data["tenant-b/1"]; !ok {
+		t.Error("Expected other namespaces to be kept")
+	}
+	current, err := manager.Get("tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Version != 0 {
+		t.Errorf("Expected the namespace settings to be deleted, got %+v", current)
+	}
+}
@@ -0,0 +1,28 @@
+package proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+)
+
+// Reindex rebuilds the full-text index from the stored values. Writes are
+// held off while each batch of values is indexed.
+func (a *AdminServer) Reindex(ctx context.Context, _ *proto.ReindexRequest) (*proto.ReindexResponse, error) {
+	if a.config.Index == nil {
+		return nil, errSubsystemDisabled("search indexes")
+	}
+	start := time.Now()
+	stats, err := a.config.Index.Reindex(ctx)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	logging.FromContext(ctx).Info("Reindexed values", "indexed", stats.Indexed, "skipped", stats.Skipped, "duration", time.Since(start), "requested_by", callerIdentity(ctx))
+	return &proto.ReindexResponse{
+		IndexedValues: uint64(stats.Indexed),
+		SkippedValues: uint64(stats.Skipped),
+		DurationMs:    time.Since(start).Milliseconds(),
+	}, nil
+}
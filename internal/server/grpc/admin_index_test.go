@@ -0,0 +1,43 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/index"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_Reindex(t *testing.T) {
+	ctx := context.Background()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := base.BatchPut(map[string][]byte{"a": []byte("hello world"), "b": {0xff}}); err != nil {
+		t.Fatal(err)
+	}
+	idx := index.New(base, index.Config{})
+	admin := startAdmin(t, NewAdminServer(idx, AdminServerConfig{Index: idx}))
+
+	resp, err := admin.Reindex(ctx, &proto.ReindexRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IndexedValues != 1 || resp.SkippedValues != 1 {
+		t.Errorf("Reindex = %+v, want 1 value indexed and 1 skipped", resp)
+	}
+	if hits, err := idx.Search("hello", "", 0); err != nil || len(hits) != 1 {
+		t.Errorf("Search() = %v, %v after Reindex; want the value found", hits, err)
+	}
+}
+
+func TestAdminServer_Reindex_Disabled(t *testing.T) {
+	admin := startAdmin(t, NewAdminServer(newMockStore(), AdminServerConfig{}))
+	if _, err := admin.Reindex(context.Background(), &proto.ReindexRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Reindex = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,96 @@
+package proto
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+)
+
+// bulkLoadProgressInterval is the time between the progress reports of a
+// bulk load.
+const bulkLoadProgressInterval = time.Second
+
+// BulkLoad writes the pairs streamed by the client, which come in increasing
+// key order, ingesting them with the configured Loader while the store is
+// empty and in batches through the store otherwise. Pairs are validated as
+// client writes are unless the first message skips it. The pairs of a failed
+// load that were already written stay.
+func (a *AdminServer) BulkLoad(stream grpc.BidiStreamingServer[proto.BulkLoadRequest, proto.BulkLoadProgress]) error {
+	ctx := stream.Context()
+	start := time.Now()
+	req, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		req = &proto.BulkLoadRequest{}
+	} else if err != nil {
+		return err
+	}
+	namespace, validate := req.Namespace, !req.SkipValidation && a.config.Validation != nil
+
+	load, ingested, err := a.newLoad()
+	if err != nil {
+		return convertError(err)
+	}
+	defer load.Cancel()
+
+	progress := &proto.BulkLoadProgress{Ingested: ingested}
+	lastReport := start
+	for req != nil {
+		for _, pair := range req.Pairs {
+			key := inNamespace(namespace, pair.Key)
+			if validate {
+				if err := a.config.Validation.Validate(ctx, key, pair.Value); err != nil {
+					return convertError(err)
+				}
+			}
+			if err := load.Add(key, pair.Value); err != nil {
+				return convertError(err)
+			}
+			progress.Keys++
+			progress.Bytes += int64(len(key) + len(pair.Value))
+		}
+		if time.Since(lastReport) >= bulkLoadProgressInterval {
+			progress.DurationMs = time.Since(start).Milliseconds()
+			if err := stream.Send(progress); err != nil {
+				return err
+			}
+			lastReport = time.Now()
+		}
+
+		req, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := load.Finish(); err != nil {
+		return convertError(err)
+	}
+	progress.DurationMs = time.Since(start).Milliseconds()
+	progress.Done = true
+	logging.FromContext(ctx).Info("Bulk loaded store", "keys", progress.Keys, "bytes", progress.Bytes, "ingested", ingested, "namespace", namespace, "validated", validate, "duration", time.Since(start), "requested_by", callerIdentity(ctx))
+	return stream.Send(progress)
+}
+
+// newLoad starts a load with the configured Loader if there is one and the
+// store is empty, reporting true, and in batches through the store otherwise.
+func (a *AdminServer) newLoad() (store.Load, bool, error) {
+	if a.config.Loader != nil {
+		load, err := a.config.Loader.NewLoad()
+		if err == nil {
+			return load, true, nil
+		}
+		if !errors.Is(err, store.ErrStoreNotEmpty) {
+			return nil, false, err
+		}
+	}
+	load, err := store.NewLoad(a.store, 0)
+	return load, false, err
+}
@@ -0,0 +1,97 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/validated"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bulkLoad streams requests to admin and returns the last progress report.
+func bulkLoad(admin proto.ClavisAdminClient, requests ...*proto.BulkLoadRequest) (*proto.BulkLoadProgress, error) {
+	stream, err := admin.BulkLoad(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			break
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	var last *proto.BulkLoadProgress
+	for {
+		progress, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return last, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		last = progress
+	}
+}
+
+// loadPairs returns n pairs with keys from prefix00000 up.
+func loadPairs(prefix string, n int) []*proto.LoadPair {
+	pairs := make([]*proto.LoadPair, n)
+	for i := range pairs {
+		pairs[i] = &proto.LoadPair{Key: fmt.Sprintf("%s%05d", prefix, i), Value: []byte("v")}
+	}
+	return pairs
+}
+
+func TestAdminServer_BulkLoad(t *testing.T) {
+	s := newBadgerStore(t)
+	validator, err := validated.New(s, validated.Config{Default: validation.ProfileStrict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Writes reach the store through a wrapper, as on the server
+	admin := startAdmin(t, NewAdminServer(store.Passthrough{Store: s}, AdminServerConfig{Loader: s, Validation: validator}))
+
+	// Into the empty store, pairs are ingested
+	progress, err := bulkLoad(admin,
+		&proto.BulkLoadRequest{Pairs: loadPairs("a/", 100), Namespace: "tenant"},
+		&proto.BulkLoadRequest{Pairs: loadPairs("b/", 100)},
+	)
+	if err != nil {
+		t.Fatalf("BulkLoad() = %v", err)
+	}
+	if !progress.Done || !progress.Ingested || progress.Keys != 200 {
+		t.Errorf("BulkLoad() reported %+v, want 200 keys ingested", progress)
+	}
+	if _, found, _ := s.Get("tenant/b/00099"); !found {
+		t.Error("Expected the keys loaded under the namespace of the first message")
+	}
+
+	// Once the store holds keys, pairs are written in batches
+	progress, err = bulkLoad(admin, &proto.BulkLoadRequest{Pairs: loadPairs("c/", 10)})
+	if err != nil || !progress.Done || progress.Ingested || progress.Keys != 10 {
+		t.Errorf("BulkLoad() into a store with keys = %+v, %v; want 10 keys written in batches", progress, err)
+	}
+
+	unsorted := []*proto.LoadPair{{Key: "d/2"}, {Key: "d/1"}}
+	if _, err := bulkLoad(admin, &proto.BulkLoadRequest{Pairs: unsorted}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unsorted keys, got %v", err)
+	}
+
+	// The strict profile rejects keys with spaces, unless validation is skipped
+	invalid := []*proto.LoadPair{{Key: "e/with space", Value: []byte("v")}}
+	if _, err := bulkLoad(admin, &proto.BulkLoadRequest{Pairs: invalid}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid key, got %v", err)
+	}
+	if _, err := bulkLoad(admin, &proto.BulkLoadRequest{Pairs: invalid, SkipValidation: true}); err != nil {
+		t.Errorf("BulkLoad() skipping validation = %v", err)
+	}
+}
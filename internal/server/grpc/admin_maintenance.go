@@ -0,0 +1,115 @@
+package proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Stats reports the statistics of the store and, unless only the estimate is
+// requested, counts its keys by walking all of them. Disk usage is reported
+// when the store keeps data files.
+func (a *AdminServer) Stats(ctx context.Context, req *proto.StatsRequest) (*proto.StatsResponse, error) {
+	start := time.Now()
+	stats, err := a.store.Stats()
+	if err != nil {
+		return nil, convertError(err)
+	}
+	resp := &proto.StatsResponse{
+		EstimatedKeys: stats.EstimatedKeys,
+		MemoryBytes:   stats.MemoryBytes,
+		LsmBytes:      stats.LSMBytes,
+		ValueLogBytes: stats.ValueLogBytes,
+		Operations: &proto.OperationCounts{
+			Gets:    stats.Operations.Gets,
+			Puts:    stats.Operations.Puts,
+			Deletes: stats.Operations.Deletes,
+			Scans:   stats.Operations.Scans,
+		},
+	}
+
+	if !req.EstimateOnly {
+		it, err := store.NewIterator(a.store, "")
+		if err != nil {
+			return nil, convertError(err)
+		}
+		defer it.Close()
+		for it.Next() {
+			if resp.KeyCount%1000 == 0 && ctx.Err() != nil {
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+			resp.KeyCount++
+		}
+		if err := it.Err(); err != nil {
+			return nil, convertError(err)
+		}
+	}
+	if maintainer, ok := store.As[store.Maintainer](a.store); ok {
+		resp.DiskUsageBytes = maintainer.DiskUsage()
+	}
+	resp.DurationMs = time.Since(start).Milliseconds()
+	return resp, nil
+}
+
+// TriggerGC rewrites the data files with enough stale data right away.
+func (a *AdminServer) TriggerGC(ctx context.Context, req *proto.TriggerGCRequest) (*proto.TriggerGCResponse, error) {
+	maintainer, ok := store.As[store.Maintainer](a.store)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "the configured store does not support garbage collection")
+	}
+	if req.DiscardRatio < 0 || req.DiscardRatio >= 1 {
+		return nil, status.Error(codes.InvalidArgument, "discard ratio must be in (0, 1)")
+	}
+
+	start := time.Now()
+	before := maintainer.DiskUsage()
+	rewritten, err := maintainer.RunGC(req.DiscardRatio)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	after := maintainer.DiskUsage()
+	logging.FromContext(ctx).Info("Collected garbage", "rewritten_files", rewritten, "reclaimed_bytes", before-after, "requested_by", callerIdentity(ctx))
+	return &proto.TriggerGCResponse{
+		RewrittenFiles:       uint32(rewritten), // #nosec G115 -- file counts are small
+		DiskUsageBeforeBytes: before,
+		DiskUsageAfterBytes:  after,
+		DurationMs:           time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// Flush writes every buffered write of the store to disk.
+func (a *AdminServer) Flush(ctx context.Context, req *proto.FlushRequest) (*proto.FlushResponse, error) {
+	maintainer, ok := store.As[store.Maintainer](a.store)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "the configured store does not support flushing")
+	}
+	start := time.Now()
+	if err := maintainer.Flush(); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.FlushResponse{DurationMs: time.Since(start).Milliseconds()}, nil
+}
+
+// SetLogLevel changes the minimum level the server logs at, or only reports
+// it when no level is given.
+func (a *AdminServer) SetLogLevel(ctx context.Context, req *proto.SetLogLevelRequest) (*proto.SetLogLevelResponse, error) {
+	if a.config.LogLevel == nil {
+		return nil, errSubsystemDisabled("runtime log levels")
+	}
+	previous := a.config.LogLevel.Level()
+	if req.Level == "" {
+		return &proto.SetLogLevelResponse{Level: previous.String(), PreviousLevel: previous.String()}, nil
+	}
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	a.config.LogLevel.Set(level)
+	logging.FromContext(ctx).Warn("Changed log level", "level", level, "previous_level", previous, "requested_by", callerIdentity(ctx))
+	return &proto.SetLogLevelResponse{Level: level.String(), PreviousLevel: previous.String()}, nil
+}
@@ -0,0 +1,118 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maintainedStore records the maintenance run on a mock store.
+type maintainedStore struct {
+	*mockStore
+	usage        int64
+	discardRatio float64
+	flushes      int
+	gcErr        error
+}
+
+func (s *maintainedStore) DiskUsage() int64 { return s.usage }
+
+func (s *maintainedStore) RunGC(discardRatio float64) (int, error) {
+	s.discardRatio = discardRatio
+	if s.gcErr != nil {
+		return 0, s.gcErr
+	}
+	s.usage /= 2
+	return 2, nil
+}
+
+func (s *maintainedStore) Flush() error {
+	s.flushes++
+	return nil
+}
+
+var _ store.Maintainer = (*maintainedStore)(nil)
+
+func TestAdminServer_Maintenance(t *testing.T) {
+	ctx := context.Background()
+	s := &maintainedStore{mockStore: newMockStore(), usage: 1000}
+	for _, key := range []string{"a", "b", "c"} {
+		if err := s.Put(key, []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	admin := startAdmin(t, NewAdminServer(s, AdminServerConfig{}))
+
+	stats, err := admin.Stats(ctx, &proto.StatsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.KeyCount != 3 || stats.DiskUsageBytes != 1000 {
+		t.Errorf("Stats = %+v, want 3 keys in 1000 bytes", stats)
+	}
+	if stats, err := admin.Stats(ctx, &proto.StatsRequest{EstimateOnly: true}); err != nil || stats.KeyCount != 0 || stats.EstimatedKeys != 3 {
+		t.Errorf("Stats estimate = %+v, %v; want only the estimate of 3 keys", stats, err)
+	}
+
+	gc, err := admin.TriggerGC(ctx, &proto.TriggerGCRequest{DiscardRatio: 0.7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gc.RewrittenFiles != 2 || gc.DiskUsageBeforeBytes != 1000 || gc.DiskUsageAfterBytes != 500 || s.discardRatio != 0.7 {
+		t.Errorf("TriggerGC = %+v with ratio %v, want 2 files rewritten at 0.7", gc, s.discardRatio)
+	}
+	if _, err := admin.TriggerGC(ctx, &proto.TriggerGCRequest{DiscardRatio: 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("TriggerGC with ratio 1 = %v, want InvalidArgument", err)
+	}
+	s.gcErr = errors.New("another collection is running")
+	if _, err := admin.TriggerGC(ctx, &proto.TriggerGCRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("TriggerGC while rejected = %v, want FailedPrecondition", err)
+	}
+
+	if _, err := admin.Flush(ctx, &proto.FlushRequest{}); err != nil || s.flushes != 1 {
+		t.Errorf("Flush = %v with %d flushes, want one flush", err, s.flushes)
+	}
+}
+
+func TestAdminServer_Maintenance_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	admin := startAdmin(t, NewAdminServer(newMockStore(), AdminServerConfig{}))
+	if stats, err := admin.Stats(ctx, &proto.StatsRequest{}); err != nil || stats.DiskUsageBytes != 0 {
+		t.Errorf("Stats = %+v, %v; want keys counted without disk usage", stats, err)
+	}
+	if _, err := admin.TriggerGC(ctx, &proto.TriggerGCRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("TriggerGC = %v, want Unimplemented", err)
+	}
+	if _, err := admin.Flush(ctx, &proto.FlushRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Flush = %v, want Unimplemented", err)
+	}
+	if _, err := admin.SetLogLevel(ctx, &proto.SetLogLevelRequest{Level: "debug"}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("SetLogLevel = %v, want Unimplemented", err)
+	}
+}
+
+func TestAdminServer_SetLogLevel(t *testing.T) {
+	ctx := context.Background()
+	var level slog.LevelVar
+	admin := startAdmin(t, NewAdminServer(newMockStore(), AdminServerConfig{LogLevel: &level}))
+
+	resp, err := admin.SetLogLevel(ctx, &proto.SetLogLevelRequest{Level: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Level != "DEBUG" || resp.PreviousLevel != "INFO" || level.Level() != slog.LevelDebug {
+		t.Errorf("SetLogLevel = %+v, want DEBUG after INFO", resp)
+	}
+	if resp, err := admin.SetLogLevel(ctx, &proto.SetLogLevelRequest{}); err != nil || resp.Level != "DEBUG" {
+		t.Errorf("SetLogLevel without a level = %+v, %v; want the current level", resp, err)
+	}
+	if _, err := admin.SetLogLevel(ctx, &proto.SetLogLevelRequest{Level: "loud"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetLogLevel with an unknown level = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,36 @@
+package proto
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+)
+
+// GetServerMode tells whether the server accepts client writes.
+func (a *AdminServer) GetServerMode(ctx context.Context, req *proto.GetServerModeRequest) (*proto.GetServerModeResponse, error) {
+	if a.config.Mode == nil {
+		return nil, errSubsystemDisabled("server modes")
+	}
+	return &proto.GetServerModeResponse{Mode: modeToProto(a.config.Mode.Mode())}, nil
+}
+
+// SetReadOnly switches the server to rejecting client writes, or back to
+// accepting them. It returns once no write is in progress anymore.
+func (a *AdminServer) SetReadOnly(ctx context.Context, req *proto.SetReadOnlyRequest) (*proto.SetReadOnlyResponse, error) {
+	if a.config.Mode == nil {
+		return nil, errSubsystemDisabled("server modes")
+	}
+	mode := a.config.Mode.SetReadOnly(req.ReadOnly, req.Reason)
+	logging.FromContext(ctx).Info("Changed server mode", "read_only", mode.ReadOnly, "reason", mode.Reason, "requested_by", callerIdentity(ctx))
+	return &proto.SetReadOnlyResponse{Mode: modeToProto(mode)}, nil
+}
+
+func modeToProto(mode readonly.Mode) *proto.ServerMode {
+	return &proto.ServerMode{
+		ReadOnly:  mode.ReadOnly,
+		Reason:    mode.Reason,
+		SinceUnix: mode.Since.Unix(),
+	}
+}
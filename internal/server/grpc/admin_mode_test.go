@@ -0,0 +1,57 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_SetReadOnly(t *testing.T) {
+	ctx := context.Background()
+	clientStore := readonly.New(newMockStore(), readonly.Mode{})
+	s := &GRPCServer{store: clientStore, config: &GRPCServerConfig{}}
+	admin := startAdmin(t, NewAdminServer(clientStore, AdminServerConfig{Mode: clientStore}))
+
+	set, err := admin.SetReadOnly(ctx, &proto.SetReadOnlyRequest{ReadOnly: true, Reason: "maintenance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set.Mode.ReadOnly || set.Mode.Reason != "maintenance" {
+		t.Errorf("SetReadOnly = %+v, want read-only for maintenance", set.Mode)
+	}
+	got, err := admin.GetServerMode(ctx, &proto.GetServerModeRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Mode.ReadOnly || got.Mode.SinceUnix != set.Mode.SinceUnix {
+		t.Errorf("GetServerMode = %+v, want %+v", got.Mode, set.Mode)
+	}
+
+	if _, err := s.Put(ctx, &proto.PutRequest{Key: "k", Value: []byte("v")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Put while read-only = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Delete(ctx, &proto.DeleteRequest{Key: "k"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Delete while read-only = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.Get(ctx, &proto.GetRequest{Key: "k"}); err != nil && status.Code(err) != codes.NotFound {
+		t.Errorf("Get while read-only = %v, want it served", err)
+	}
+
+	if _, err := admin.SetReadOnly(ctx, &proto.SetReadOnlyRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(ctx, &proto.PutRequest{Key: "k", Value: []byte("v")}); err != nil {
+		t.Errorf("Put after switching back = %v", err)
+	}
+}
+
+func TestAdminServer_SetReadOnly_Disabled(t *testing.T) {
+	admin := startAdmin(t, NewAdminServer(newMockStore(), AdminServerConfig{}))
+	if _, err := admin.SetReadOnly(context.Background(), &proto.SetReadOnlyRequest{ReadOnly: true}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("SetReadOnly = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,78 @@
+package proto
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/replication"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// replicationBatchSize is the most mutations sent in one message.
+const replicationBatchSize = 1000
+
+var replicationOps = map[replication.Op]proto.Mutation_Op{
+	replication.OpPut:    proto.Mutation_OP_PUT,
+	replication.OpDelete: proto.Mutation_OP_DELETE,
+	replication.OpExpire: proto.Mutation_OP_EXPIRE,
+}
+
+// Replicate streams the mutations committed on this server to a follower,
+// starting after the position in its first message, until the follower goes
+// away. The follower's acknowledgements are only used to report its lag.
+func (a *AdminServer) Replicate(stream grpc.BidiStreamingServer[proto.ReplicateRequest, proto.ReplicateBatch]) error {
+	if a.config.Replication == nil {
+		return errSubsystemDisabled("replication streams")
+	}
+	log := a.config.Replication
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	var acked atomic.Uint64
+	acked.Store(first.AfterSequence)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			acked.Store(req.AckedSequence)
+		}
+	}()
+
+	logger := logging.FromContext(ctx).With("follower", callerIdentity(ctx))
+	logger.Info("Follower connected", "log_id", first.LogId, "after_sequence", first.AfterSequence, "last_sequence", log.Last())
+	defer func() {
+		logger.Info("Follower disconnected", "acked_sequence", acked.Load(), "last_sequence", log.Last())
+	}()
+
+	logID, after := first.LogId, first.AfterSequence
+	for {
+		mutations, err := log.Read(ctx, logID, after, replicationBatchSize)
+		if err != nil {
+			return convertReplicationError(err)
+		}
+		batch := &proto.ReplicateBatch{LogId: log.ID(), Mutations: make([]*proto.Mutation, len(mutations))}
+		for i, m := range mutations {
+			batch.Mutations[i] = &proto.Mutation{Sequence: m.Sequence, Op: replicationOps[m.Op], Key: m.Key, Value: m.Value}
+		}
+		if err := stream.Send(batch); err != nil {
+			return err
+		}
+		logID, after = log.ID(), mutations[len(mutations)-1].Sequence
+	}
+}
+
+func convertReplicationError(err error) error {
+	if errors.Is(err, replication.ErrUnknownLog) || errors.Is(err, replication.ErrTruncated) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.FromContextError(err).Err()
+}
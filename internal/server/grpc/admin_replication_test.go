@@ -0,0 +1,124 @@
+package proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/replication"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_Replicate(t *testing.T) {
+	newStore := func() store.Store {
+		s, err := memory.NewWithDefaults()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	log := replication.NewLog(0)
+	primary := replication.NewLoggingStore(newStore(), log)
+	conn := serveAdmin(t, NewAdminServer(primary, AdminServerConfig{Replication: log}))
+
+	if err := primary.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Put("b", []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+
+	stateFile := filepath.Join(t.TempDir(), "replication.json")
+	follow := func(s store.Store) (stop func() error) {
+		follower, err := replication.NewFollower(conn, s, replication.FollowerConfig{StateFile: stateFile, RetryInterval: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- follower.Run(ctx) }()
+		return func() error {
+			cancel()
+			return <-done
+		}
+	}
+	waitFor := func(s store.Store, key, want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			value, found, err := s.Get(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (want == "" && !found) || (found && string(value) == want) {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("%s never became %q on the follower", key, want)
+	}
+
+	followerStore := newStore()
+	stop := follow(followerStore)
+	waitFor(followerStore, "b", "2")
+	if err := primary.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(followerStore, "a", "")
+	if err := stop(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+
+	// A restarted follower resumes after the last mutation it applied
+	if err := primary.Put("c", []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := followerStore.Put("b", []byte("changed on the follower")); err != nil {
+		t.Fatal(err)
+	}
+	stop = follow(followerStore)
+	waitFor(followerStore, "c", "3")
+	if err := stop(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	if value, _, _ := followerStore.Get("b"); string(value) != "changed on the follower" {
+		t.Errorf("b = %q; the follower replayed mutations it had applied", value)
+	}
+
+	// A position in a log the primary no longer has cannot be resumed
+	data, err := json.Marshal(replication.Position{LogID: "restarted", Sequence: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stateFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	follower, err := replication.NewFollower(conn, followerStore, replication.FollowerConfig{StateFile: stateFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.Run(context.Background()); !errors.Is(err, replication.ErrReseedRequired) {
+		t.Errorf("Run = %v, want ErrReseedRequired", err)
+	}
+}
+
+func TestAdminServer_Replicate_Disabled(t *testing.T) {
+	stream, err := startAdmin(t, NewAdminServer(newBadgerStore(t), AdminServerConfig{})).Replicate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&proto.ReplicateRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Recv = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,298 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/index"
+	"github.com/William-Fernandes252/clavis/internal/keystats"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/replication"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/crypto"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+	"github.com/William-Fernandes252/clavis/internal/store/validated"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AdminServerConfig wires the subsystems the admin service operates on.
+// Subsystems left nil make their RPCs return codes.Unimplemented.
+type AdminServerConfig struct {
+	Settings *settings.Manager
+	KeyStats *keystats.Tracker
+	Metrics  *metrics.Registry
+	// Confirmations enables destructive operations, which must be confirmed
+	// with a token issued by a preview call.
+	Confirmations *confirm.Issuer
+	// Replication is the log of committed mutations followers replicate.
+	Replication *replication.Log
+	// Mode switches the store clients write to between read-only and
+	// read-write.
+	Mode *readonly.Store
+	// Encryption rotates and rewraps the keys values are encrypted at rest
+	// with.
+	Encryption *crypto.Store
+	// Index is the full-text index Reindex rebuilds.
+	Index *index.Index
+	// LogLevel is the minimum level of the server's logger, changed with
+	// SetLogLevel.
+	LogLevel *slog.LevelVar
+	// Validation checks bulk-loaded pairs against the profile bound to the
+	// caller or namespace, unless the load skips it.
+	Validation *validated.Store
+	// Loader ingests bulk loads into an empty store straight into its data
+	// files, bypassing the store wrappers; set only when none of them
+	// transforms values or records writes. Loads are written in batches
+	// through the store otherwise.
+	Loader store.Loader
+}
+
+// AdminServer implements the ClavisAdmin gRPC service for runtime operations.
+type AdminServer struct {
+	proto.UnimplementedClavisAdminServer
+	store  store.Store
+	config AdminServerConfig
+}
+
+// NewAdminServer creates an admin service operating on the given store.
+func NewAdminServer(store store.Store, config AdminServerConfig) *AdminServer {
+	return &AdminServer{store: store, config: config}
+}
+
+// Register adds the admin service to server.
+func (a *AdminServer) Register(server *grpc.Server) {
+	proto.RegisterClavisAdminServer(server, a)
+}
+
+// GetNamespaceSettings returns the current settings of a namespace.
+func (a *AdminServer) GetNamespaceSettings(ctx context.Context, req *proto.GetNamespaceSettingsRequest) (*proto.GetNamespaceSettingsResponse, error) {
+	if a.config.Settings == nil {
+		return nil, errSubsystemDisabled("settings")
+	}
+	current, err := a.config.Settings.Get(req.Namespace)
+	if err != nil {
+		return nil, convertSettingsError(err)
+	}
+	return &proto.GetNamespaceSettingsResponse{Settings: settingsToProto(current)}, nil
+}
+
+// SetNamespaceSettings replaces the settings of a namespace.
+func (a *AdminServer) SetNamespaceSettings(ctx context.Context, req *proto.SetNamespaceSettingsRequest) (*proto.SetNamespaceSettingsResponse, error) {
+	if a.config.Settings == nil {
+		return nil, errSubsystemDisabled("settings")
+	}
+	if req.Settings == nil {
+		return nil, status.Error(codes.InvalidArgument, "settings are required")
+	}
+
+	updated, err := a.config.Settings.Set(req.Settings.Namespace, settingsFromProto(req.Settings), req.ExpectedVersion, callerIdentity(ctx))
+	if err != nil {
+		return nil, convertSettingsError(err)
+	}
+	return &proto.SetNamespaceSettingsResponse{Settings: settingsToProto(updated)}, nil
+}
+
+// GetNamespaceSettingsHistory returns every recorded version of a namespace's settings.
+func (a *AdminServer) GetNamespaceSettingsHistory(ctx context.Context, req *proto.GetNamespaceSettingsHistoryRequest) (*proto.GetNamespaceSettingsHistoryResponse, error) {
+	if a.config.Settings == nil {
+		return nil, errSubsystemDisabled("settings")
+	}
+	history, err := a.config.Settings.History(req.Namespace)
+	if err != nil {
+		return nil, convertSettingsError(err)
+	}
+
+	resp := &proto.GetNamespaceSettingsHistoryResponse{History: make([]*proto.NamespaceSettings, 0, len(history))}
+	for _, entry := range history {
+		resp.History = append(resp.History, settingsToProto(entry))
+	}
+	return resp, nil
+}
+
+// CreateNamespace creates a namespace with its initial settings.
+func (a *AdminServer) CreateNamespace(ctx context.Context, req *proto.CreateNamespaceRequest) (*proto.CreateNamespaceResponse, error) {
+	if a.config.Settings == nil {
+		return nil, errSubsystemDisabled("settings")
+	}
+	if req.Settings == nil {
+		return nil, status.Error(codes.InvalidArgument, "settings are required")
+	}
+
+	created, err := a.config.Settings.Create(req.Settings.Namespace, settingsFromProto(req.Settings), callerIdentity(ctx))
+	if err != nil {
+		return nil, convertSettingsError(err)
+	}
+	logging.FromContext(ctx).Info("Created namespace", "namespace", created.Namespace, "requested_by", callerIdentity(ctx))
+	return &proto.CreateNamespaceResponse{Settings: settingsToProto(created)}, nil
+}
+
+// ListNamespaces returns every namespace with its settings.
+func (a *AdminServer) ListNamespaces(ctx context.Context, req *proto.ListNamespacesRequest) (*proto.ListNamespacesResponse, error) {
+	if a.config.Settings == nil {
+		return nil, errSubsystemDisabled("settings")
+	}
+	namespaces, err := a.config.Settings.List()
+	if err != nil {
+		return nil, convertSettingsError(err)
+	}
+
+	resp := &proto.ListNamespacesResponse{Namespaces: make([]*proto.NamespaceSettings, 0, len(namespaces))}
+	for _, entry := range namespaces {
+		resp.Namespaces = append(resp.Namespaces, settingsToProto(entry))
+	}
+	return resp, nil
+}
+
+// RelocateDataDir moves the store's data directory while it keeps serving reads.
+func (a *AdminServer) RelocateDataDir(ctx context.Context, req *proto.RelocateDataDirRequest) (*proto.RelocateDataDirResponse, error) {
+	relocator, ok := store.As[store.Relocator](a.store)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "the configured store does not support relocation")
+	}
+	if req.NewPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "new path is required")
+	}
+
+	start := time.Now()
+	retiredPath, err := relocator.Relocate(req.NewPath)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	logging.FromContext(ctx).Info("Relocated data directory", "new_path", req.NewPath, "retired_path", retiredPath, "requested_by", callerIdentity(ctx))
+
+	return &proto.RelocateDataDirResponse{
+		NewPath:     req.NewPath,
+		RetiredPath: retiredPath,
+		DurationMs:  time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// KeyspaceStats returns approximate statistics about the shape of the keyspace.
+func (a *AdminServer) KeyspaceStats(ctx context.Context, req *proto.KeyspaceStatsRequest) (*proto.KeyspaceStatsResponse, error) {
+	if a.config.KeyStats == nil {
+		return nil, errSubsystemDisabled("keyspace statistics")
+	}
+
+	snapshot := a.config.KeyStats.Snapshot()
+	prefixes := snapshot.Prefixes
+	if req.TopPrefixes > 0 && int(req.TopPrefixes) < len(prefixes) {
+		prefixes = prefixes[:req.TopPrefixes]
+	}
+
+	resp := &proto.KeyspaceStatsResponse{
+		Prefixes: make([]*proto.PrefixStats, 0, len(prefixes)),
+		Depths:   make([]*proto.DepthCount, 0, len(snapshot.Depths)),
+	}
+	for _, p := range prefixes {
+		resp.Prefixes = append(resp.Prefixes, &proto.PrefixStats{
+			Prefix:        p.Prefix,
+			EstimatedKeys: p.EstimatedKeys,
+			Writes:        p.Writes,
+			BytesWritten:  p.BytesWritten,
+		})
+	}
+	for depth, count := range snapshot.Depths {
+		resp.Depths = append(resp.Depths, &proto.DepthCount{Depth: uint32(depth), Writes: count}) // #nosec G115 -- key depth is small
+	}
+	sort.Slice(resp.Depths, func(i, j int) bool { return resp.Depths[i].Depth < resp.Depths[j].Depth })
+	return resp, nil
+}
+
+// MetricsSnapshot returns the current value of every metric.
+func (a *AdminServer) MetricsSnapshot(ctx context.Context, req *proto.MetricsSnapshotRequest) (*proto.MetricsSnapshotResponse, error) {
+	if a.config.Metrics == nil {
+		return nil, errSubsystemDisabled("metrics")
+	}
+
+	snapshot := a.config.Metrics.Snapshot()
+	resp := &proto.MetricsSnapshotResponse{
+		Counters:        make([]*proto.CounterValue, 0, len(snapshot.Counters)),
+		Gauges:          make([]*proto.GaugeValue, 0, len(snapshot.Gauges)),
+		Histograms:      make([]*proto.HistogramValue, 0, len(snapshot.Histograms)),
+		TakenAtUnixNano: time.Now().UnixNano(),
+	}
+	for name, value := range snapshot.Counters {
+		resp.Counters = append(resp.Counters, &proto.CounterValue{Name: name, Value: value})
+	}
+	for name, value := range snapshot.Gauges {
+		resp.Gauges = append(resp.Gauges, &proto.GaugeValue{Name: name, Value: value})
+	}
+	for name, h := range snapshot.Histograms {
+		value := &proto.HistogramValue{Name: name, Count: h.Count, Sum: h.Sum}
+		for _, b := range h.Buckets {
+			value.Buckets = append(value.Buckets, &proto.HistogramBucket{UpperBound: b.UpperBound, Count: b.Count})
+		}
+		resp.Histograms = append(resp.Histograms, value)
+	}
+	sort.Slice(resp.Counters, func(i, j int) bool { return resp.Counters[i].Name < resp.Counters[j].Name })
+	sort.Slice(resp.Gauges, func(i, j int) bool { return resp.Gauges[i].Name < resp.Gauges[j].Name })
+	sort.Slice(resp.Histograms, func(i, j int) bool { return resp.Histograms[i].Name < resp.Histograms[j].Name })
+	return resp, nil
+}
+
+// errSubsystemDisabled reports an admin RPC whose subsystem is not configured.
+func errSubsystemDisabled(name string) error {
+	return status.Errorf(codes.Unimplemented, "%s are not enabled on this server", name)
+}
+
+// callerIdentity describes who issued the request, for audit trails.
+func callerIdentity(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+func convertSettingsError(err error) error {
+	switch {
+	case errors.Is(err, settings.ErrInvalidNamespace), errors.Is(err, settings.ErrInvalidSettings):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, settings.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, settings.ErrNamespaceExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return convertError(err)
+	}
+}
+
+func settingsToProto(s settings.NamespaceSettings) *proto.NamespaceSettings {
+	out := &proto.NamespaceSettings{
+		Namespace:         s.Namespace,
+		DefaultTtlSeconds: int64(s.DefaultTTL / time.Second),
+		MaxKeys:           s.MaxKeys,
+		MaxBytes:          s.MaxBytes,
+		ValidationProfile: s.ValidationProfile,
+		ReadOnly:          s.ReadOnly,
+		Version:           s.Version,
+		UpdatedBy:         s.UpdatedBy,
+		RetainVersions:    int64(s.RetainVersions),
+		RetainForSeconds:  int64(s.RetainFor / time.Second),
+	}
+	if !s.UpdatedAt.IsZero() {
+		out.UpdatedAtUnix = s.UpdatedAt.Unix()
+	}
+	return out
+}
+
+func settingsFromProto(s *proto.NamespaceSettings) settings.NamespaceSettings {
+	return settings.NamespaceSettings{
+		DefaultTTL:        time.Duration(s.DefaultTtlSeconds) * time.Second,
+		MaxKeys:           s.MaxKeys,
+		MaxBytes:          s.MaxBytes,
+		ValidationProfile: s.ValidationProfile,
+		ReadOnly:          s.ReadOnly,
+		RetainVersions:    int(s.RetainVersions),
+		RetainFor:         time.Duration(s.RetainForSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,156 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/keystats"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminServer_NamespaceSettings(t *testing.T) {
+	manager := settings.NewManager(newMockStore(), nil)
+	defer manager.Close()
+	admin := NewAdminServer(newMockStore(), AdminServerConfig{Settings: manager})
+	ctx := context.Background()
+
+	set, err := admin.SetNamespaceSettings(ctx, &proto.SetNamespaceSettingsRequest{
+		Settings: &proto.NamespaceSettings{Namespace: "tenant", MaxKeys: 5, DefaultTtlSeconds: 60},
+	})
+	if err != nil {
+		t.Fatalf("SetNamespaceSettings failed: %v", err)
+	}
+	if set.Settings.Version != 1 || set.Settings.UpdatedAtUnix == 0 {
+		t.Errorf("Unexpected settings: %+v", set.Settings)
+	}
+
+	got, err := admin.GetNamespaceSettings(ctx, &proto.GetNamespaceSettingsRequest{Namespace: "tenant"})
+	if err != nil {
+		t.Fatalf("GetNamespaceSettings failed: %v", err)
+	}
+	if got.Settings.MaxKeys != 5 || got.Settings.DefaultTtlSeconds != 60 {
+		t.Errorf("Unexpected settings: %+v", got.Settings)
+	}
+
+	_, err = admin.SetNamespaceSettings(ctx, &proto.SetNamespaceSettingsRequest{
+		Settings:        &proto.NamespaceSettings{Namespace: "tenant"},
+		ExpectedVersion: 42,
+	})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted for stale version, got %v", err)
+	}
+
+	_, err = admin.GetNamespaceSettings(ctx, &proto.GetNamespaceSettingsRequest{Namespace: ""})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for empty namespace, got %v", err)
+	}
+
+	history, err := admin.GetNamespaceSettingsHistory(ctx, &proto.GetNamespaceSettingsHistoryRequest{Namespace: "tenant"})
+	if err != nil {
+		t.Fatalf("GetNamespaceSettingsHistory failed: %v", err)
+	}
+	if len(history.History) != 1 {
+		t.Errorf("Expected 1 history entry, got %d", len(history.History))
+	}
+}
+
+func TestAdminServer_RelocateDataDir(t *testing.T) {
+	admin := NewAdminServer(newMockStore(), AdminServerConfig{})
+
+	_, err := admin.RelocateDataDir(context.Background(), &proto.RelocateDataDirRequest{NewPath: "/tmp/elsewhere"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented for a store without relocation, got %v", err)
+	}
+}
+
+func TestAdminServer_KeyspaceStats(t *testing.T) {
+	ctx := context.Background()
+
+	disabled := NewAdminServer(newMockStore(), AdminServerConfig{})
+	if _, err := disabled.KeyspaceStats(ctx, &proto.KeyspaceStatsRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without a tracker, got %v", err)
+	}
+
+	tracker := keystats.NewTracker("", 0)
+	for _, key := range []string{"user:1", "user:2", "user:3", "order:1:item:1"} {
+		tracker.Observe(key, []byte("v"))
+	}
+	admin := NewAdminServer(newMockStore(), AdminServerConfig{KeyStats: tracker})
+
+	resp, err := admin.KeyspaceStats(ctx, &proto.KeyspaceStatsRequest{TopPrefixes: 1})
+	if err != nil {
+		t.Fatalf("KeyspaceStats failed: %v", err)
+	}
+	if len(resp.Prefixes) != 1 || resp.Prefixes[0].Prefix != "user" || resp.Prefixes[0].EstimatedKeys != 3 {
+		t.Errorf("Unexpected prefixes: %v", resp.Prefixes)
+	}
+	if len(resp.Depths) != 2 || resp.Depths[0].Depth != 2 || resp.Depths[0].Writes != 3 || resp.Depths[1].Depth != 4 {
+		t.Errorf("Unexpected depths: %v", resp.Depths)
+	}
+}
+
+func TestAdminServer_MetricsSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	disabled := NewAdminServer(newMockStore(), AdminServerConfig{})
+	if _, err := disabled.MetricsSnapshot(ctx, &proto.MetricsSnapshotRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without a registry, got %v", err)
+	}
+
+	registry := metrics.NewRegistry()
+	registry.Counter("b_total").Add(2)
+	registry.Counter("a_total").Inc()
+	registry.Gauge("in_flight").Set(4)
+	registry.Histogram("latency", []float64{1}).Observe(0.5)
+	admin := NewAdminServer(newMockStore(), AdminServerConfig{Metrics: registry})
+
+	resp, err := admin.MetricsSnapshot(ctx, &proto.MetricsSnapshotRequest{})
+	if err != nil {
+		t.Fatalf("MetricsSnapshot failed: %v", err)
+	}
+	if len(resp.Counters) != 2 || resp.Counters[0].Name != "a_total" || resp.Counters[1].Value != 2 {
+		t.Errorf("Unexpected counters: %v", resp.Counters)
+	}
+	if len(resp.Gauges) != 1 || resp.Gauges[0].Value != 4 {
+		t.Errorf("Unexpected gauges: %v", resp.Gauges)
+	}
+	if len(resp.Histograms) != 1 || resp.Histograms[0].Count != 1 || len(resp.Histograms[0].Buckets) != 2 {
+		t.Errorf("Unexpected histograms: %v", resp.Histograms)
+	}
+	if resp.TakenAtUnixNano == 0 {
+		t.Error("Expected the snapshot time to be set")
+	}
+}
+
+func TestAdminServer_Namespaces(t *testing.T) {
+	manager := settings.NewManager(newMockStore(), nil)
+	defer manager.Close()
+	admin := NewAdminServer(newMockStore(), AdminServerConfig{Settings: manager})
+	ctx := context.Background()
+
+	for _, name := range []string{"tenant-b", "tenant-a"} {
+		if _, err := admin.CreateNamespace(ctx, &proto.CreateNamespaceRequest{Settings: &proto.NamespaceSettings{Namespace: name}}); err != nil {
+			t.Fatalf("CreateNamespace failed: %v", err)
+		}
+	}
+	_, err := admin.CreateNamespace(ctx, &proto.CreateNamespaceRequest{Settings: &proto.NamespaceSettings{Namespace: "tenant-a"}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", err)
+	}
+	_, err = admin.CreateNamespace(ctx, &proto.CreateNamespaceRequest{Settings: &proto.NamespaceSettings{Namespace: "a/b"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid name, got %v", err)
+	}
+
+	list, err := admin.ListNamespaces(ctx, &proto.ListNamespacesRequest{})
+	if err != nil {
+		t.Fatalf("ListNamespaces failed: %v", err)
+	}
+	if len(list.Namespaces) != 2 || list.Namespaces[0].Namespace != "tenant-a" {
+		t.Errorf("Expected both namespaces in name order, got %v", list.Namespaces)
+	}
+}
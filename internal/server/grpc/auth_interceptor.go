@@ -0,0 +1,114 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys the authentication interceptors read credentials from.
+const (
+	AuthorizationMetadataKey = "authorization" // "Bearer <token>"
+	APIKeyMetadataKey        = "x-api-key"     // "<token>"
+)
+
+// DefaultAuthExemptions are the methods callable without credentials.
+var DefaultAuthExemptions = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// AuthConfig configures the authentication interceptors.
+type AuthConfig struct {
+	Tokens auth.TokenStore // Resolves presented tokens to principals
+	Exempt []string        // Full method names that skip authentication
+}
+
+// authenticator checks the credentials of incoming calls.
+type authenticator struct {
+	tokens auth.TokenStore
+	exempt map[string]bool
+}
+
+func newAuthenticator(config AuthConfig) *authenticator {
+	a := &authenticator{tokens: config.Tokens, exempt: make(map[string]bool, len(config.Exempt))}
+	for _, method := range config.Exempt {
+		a.exempt[method] = true
+	}
+	return a
+}
+
+// authenticate returns ctx with the caller's principal attached, or an
+// Unauthenticated error if the call carries no valid token.
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if a.exempt[method] {
+		return ctx, nil
+	}
+	token, err := tokenFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	principal, ok := a.tokens.Lookup(token)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid API token")
+	}
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+// tokenFromMetadata extracts a bearer token or API key from the incoming metadata.
+func tokenFromMetadata(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(AuthorizationMetadataKey); len(values) > 0 {
+		scheme, token, found := strings.Cut(values[0], " ")
+		if !found || !strings.EqualFold(scheme, "bearer") {
+			return "", status.Error(codes.Unauthenticated, "authorization metadata must use the Bearer scheme")
+		}
+		return strings.TrimSpace(token), nil
+	}
+	if values := md.Get(APIKeyMetadataKey); len(values) > 0 {
+		return values[0], nil
+	}
+	return "", status.Error(codes.Unauthenticated, "missing API token")
+}
+
+// AuthUnaryInterceptor rejects unary calls that do not present a valid token
+// and makes the caller available to handlers through auth.PrincipalFrom.
+func AuthUnaryInterceptor(config AuthConfig) grpc.UnaryServerInterceptor {
+	a := newAuthenticator(config)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor rejects streaming calls that do not present a valid
+// token and makes the caller available to handlers through auth.PrincipalFrom.
+func AuthStreamInterceptor(config AuthConfig) grpc.StreamServerInterceptor {
+	a := newAuthenticator(config)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
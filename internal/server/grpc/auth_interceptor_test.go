@@ -0,0 +1,78 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServerStream is a grpc.ServerStream carrying only a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestAuthInterceptors(t *testing.T) {
+	config := AuthConfig{
+		Tokens: auth.NewStaticTokenStore(map[string]string{"secret": "alice"}),
+		Exempt: DefaultAuthExemptions,
+	}
+	unary := AuthUnaryInterceptor(config)
+	stream := AuthStreamInterceptor(config)
+
+	withMD := func(pairs ...string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	}
+	caller := func(ctx context.Context, _ any) (any, error) {
+		principal, _ := auth.PrincipalFrom(ctx)
+		return principal.Name, nil
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+		wantName string
+	}{
+		{name: "bearer token", ctx: withMD("authorization", "Bearer secret"), method: "/clavis.v1.Clavis/Get", wantName: "alice"},
+		{name: "lowercase scheme", ctx: withMD("authorization", "bearer secret"), method: "/clavis.v1.Clavis/Get", wantName: "alice"},
+		{name: "api key", ctx: withMD("x-api-key", "secret"), method: "/clavis.v1.Clavis/Get", wantName: "alice"},
+		{name: "missing token", ctx: context.Background(), method: "/clavis.v1.Clavis/Get", wantCode: codes.Unauthenticated},
+		{name: "invalid token", ctx: withMD("x-api-key", "wrong"), method: "/clavis.v1.Clavis/Get", wantCode: codes.Unauthenticated},
+		{name: "wrong scheme", ctx: withMD("authorization", "Basic secret"), method: "/clavis.v1.Clavis/Get", wantCode: codes.Unauthenticated},
+		{name: "exempt method", ctx: context.Background(), method: "/grpc.health.v1.Health/Check"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := unary(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, caller)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Unary: expected code %v, got %v", tt.wantCode, err)
+			}
+			if err == nil && resp != tt.wantName {
+				t.Errorf("Unary: expected principal %q, got %q", tt.wantName, resp)
+			}
+
+			var streamed string
+			err = stream(nil, &fakeServerStream{ctx: tt.ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(_ any, ss grpc.ServerStream) error {
+				principal, _ := auth.PrincipalFrom(ss.Context())
+				streamed = principal.Name
+				return nil
+			})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Stream: expected code %v, got %v", tt.wantCode, err)
+			}
+			if streamed != tt.wantName {
+				t.Errorf("Stream: expected principal %q, got %q", tt.wantName, streamed)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminServicePrefix prefixes the full method names of the admin service.
+var adminServicePrefix = "/" + proto.ClavisAdmin_ServiceDesc.ServiceName + "/"
+
+// AuthzConfig configures the authorization interceptors, which must run after
+// the authentication interceptors.
+type AuthzConfig struct {
+	Policy *auth.Policy // Grants of each principal
+	Exempt []string     // Full method names that skip authorization
+}
+
+// access is one operation a request performs on a key or prefix.
+type access struct {
+	op  auth.Operation
+	key string
+}
+
+// requestAccesses lists the operations req performs. Requests it does not
+// know are reported with ok false, so new RPCs are denied until mapped. Keys
+// are checked as stored, within the namespace of the request.
+func requestAccesses(method string, req any) (accesses []access, ok bool) {
+	if strings.HasPrefix(method, adminServicePrefix) {
+		return []access{{op: auth.OpAdmin}}, true
+	}
+
+	switch req := req.(type) {
+	case *proto.GetRequest:
+		return []access{{auth.OpRead, inNamespace(req.Namespace, req.Key)}}, true
+	case *proto.PutRequest:
+		key := inNamespace(req.Namespace, req.Key)
+		accesses = []access{{auth.OpWrite, key}}
+		if req.Lease != 0 {
+			// The key is deleted when the lease ends
+			accesses = append(accesses, access{auth.OpDelete, key})
+		}
+		if req.Fencing != nil {
+			// Writing under a lock is checked as taking it is
+			accesses = append(accesses, access{auth.OpWrite, inNamespace(req.Namespace, req.Fencing.Lock)})
+		}
+		return accesses, true
+	case *proto.CreateUniqueRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.NextSequenceRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Name)}}, true
+	case *proto.DeleteRequest:
+		return []access{{auth.OpDelete, inNamespace(req.Namespace, req.Key)}}, true
+	case *proto.ScanRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.CopyRequest:
+		destination := inNamespace(req.Namespace, req.Destination)
+		accesses = []access{{auth.OpRead, inNamespace(req.Namespace, req.Source)}, {auth.OpWrite, destination}}
+		if req.Lease != 0 || req.TtlMs != 0 {
+			// The copy is deleted when the lease ends
+			accesses = append(accesses, access{auth.OpDelete, destination})
+		}
+		return accesses, true
+	case *proto.DeletePrefixRequest:
+		if req.DryRun {
+			return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+		}
+		return []access{{auth.OpDelete, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.RangeRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, rangePrefix(req.Start, req.End))}}, true
+	case *proto.ExistsRequest:
+		return []access{{auth.OpRead, inNamespace(req.Namespace, req.Key)}}, true
+	case *proto.CountRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.QueryByTagRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.SearchRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.IncrementRequest:
+		key := inNamespace(req.Namespace, req.Key)
+		return []access{{auth.OpRead, key}, {auth.OpWrite, key}}, true
+	case *proto.GetMetadataRequest:
+		return []access{{auth.OpRead, inNamespace(req.Namespace, req.Key)}}, true
+	case *proto.UndeleteRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Key)}}, true
+	case *proto.WatchRequest:
+		if req.Key != "" {
+			return []access{{auth.OpRead, inNamespace(req.Namespace, req.Key)}}, true
+		}
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.LockRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Name)}}, true
+	case *proto.RenewLockRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Name)}}, true
+	case *proto.UnlockRequest:
+		return []access{{auth.OpWrite, inNamespace(req.Namespace, req.Name)}}, true
+	case *proto.AttachLeaseRequest:
+		for _, key := range req.Keys {
+			accesses = append(accesses, access{auth.OpDelete, inNamespace(req.Namespace, key)})
+		}
+		return accesses, true
+	case *proto.GrantLeaseRequest, *proto.KeepAliveRequest, *proto.RevokeLeaseRequest:
+		// Leases are not in a keyspace; their IDs are known only to those
+		// granted them and those they shared them with
+		return nil, true
+	case *proto.SubscribeRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.FetchSpillRequest:
+		return []access{{auth.OpScan, inNamespace(req.Namespace, req.Prefix)}}, true
+	case *proto.BatchPutRequest:
+		for _, item := range req.Items {
+			accesses = append(accesses, access{auth.OpWrite, inNamespace(req.Namespace, item.Key)})
+		}
+		return accesses, true
+	case *proto.BatchGetRequest:
+		for _, key := range req.Keys {
+			accesses = append(accesses, access{auth.OpRead, inNamespace(req.Namespace, key)})
+		}
+		return accesses, true
+	case *proto.BatchDeleteRequest:
+		for _, key := range req.Keys {
+			accesses = append(accesses, access{auth.OpDelete, inNamespace(req.Namespace, key)})
+		}
+		return accesses, true
+	case *proto.DiffRequest:
+		for _, operand := range []*proto.DiffOperand{req.Left, req.Right} {
+			if kv := operand.GetKey(); kv != nil {
+				accesses = append(accesses, access{auth.OpRead, kv.Key})
+			}
+		}
+		return accesses, true
+	default:
+		return nil, false
+	}
+}
+
+// authorizer checks incoming requests against a policy.
+type authorizer struct {
+	policy *auth.Policy
+	exempt map[string]bool
+}
+
+func newAuthorizer(config AuthzConfig) *authorizer {
+	a := &authorizer{policy: config.Policy, exempt: make(map[string]bool, len(config.Exempt))}
+	for _, method := range config.Exempt {
+		a.exempt[method] = true
+	}
+	return a
+}
+
+// authorize returns a PermissionDenied error unless the caller may perform
+// every operation of req.
+func (a *authorizer) authorize(ctx context.Context, method string, req any) error {
+	if a.exempt[method] {
+		return nil
+	}
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	accesses, ok := requestAccesses(method, req)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "%s is not covered by the authorization policy", method)
+	}
+	for _, access := range accesses {
+		if !a.policy.Allowed(principal, access.op, access.key) {
+			return status.Errorf(codes.PermissionDenied, "%s may not %s %q", principal.Name, access.op, access.key)
+		}
+	}
+	return nil
+}
+
+// AuthzUnaryInterceptor rejects unary calls the caller's roles do not allow.
+func AuthzUnaryInterceptor(config AuthzConfig) grpc.UnaryServerInterceptor {
+	a := newAuthorizer(config)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthzStreamInterceptor rejects streaming calls the caller's roles do not
+// allow. Requests are checked as the handler receives them.
+func AuthzStreamInterceptor(config AuthzConfig) grpc.StreamServerInterceptor {
+	a := newAuthorizer(config)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.exempt[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, authorizer: a, method: info.FullMethod})
+	}
+}
+
+// authorizedStream authorizes every message received on a server stream.
+type authorizedStream struct {
+	grpc.ServerStream
+	authorizer *authorizer
+	method     string
+}
+
+// RecvMsg receives a message and checks it against the policy.
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authorizer.authorize(s.Context(), s.method, m)
+}
@@ -0,0 +1,130 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recvStream is a server stream that receives a single request
+type recvStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *proto.ScanRequest
+}
+
+func (s *recvStream) Context() context.Context { return s.ctx }
+
+func (s *recvStream) RecvMsg(m any) error {
+	m.(*proto.ScanRequest).Prefix = s.req.Prefix
+	return nil
+}
+
+func TestAuthzInterceptors(t *testing.T) {
+	config := AuthzConfig{
+		Policy: &auth.Policy{
+			Roles: map[string][]auth.Grant{
+				"tenant-a": {{Prefix: "tenant-a/", Operations: []auth.Operation{auth.OpRead, auth.OpWrite, auth.OpScan}}},
+			},
+			Bindings: map[string][]string{"alice": {"tenant-a"}},
+		},
+		Exempt: DefaultAuthExemptions,
+	}
+	unary := AuthzUnaryInterceptor(config)
+	stream := AuthzStreamInterceptor(config)
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{Name: "alice"})
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		req      any
+		wantCode codes.Code
+	}{
+		{name: "own key", ctx: alice, method: "/clavis.v1.Clavis/Get", req: &proto.GetRequest{Key: "tenant-a/x"}},
+		{name: "other tenant", ctx: alice, method: "/clavis.v1.Clavis/Get", req: &proto.GetRequest{Key: "tenant-b/x"}, wantCode: codes.PermissionDenied},
+		{name: "own namespace", ctx: alice, method: "/clavis.v1.Clavis/Get", req: &proto.GetRequest{Namespace: "tenant-a", Key: "x"}},
+		{name: "other namespace", ctx: alice, method: "/clavis.v1.Clavis/Put", req: &proto.PutRequest{Namespace: "tenant-b", Key: "x"}, wantCode: codes.PermissionDenied},
+		{name: "operation not granted", ctx: alice, method: "/clavis.v1.Clavis/Delete", req: &proto.DeleteRequest{Key: "tenant-a/x"}, wantCode: codes.PermissionDenied},
+		{
+			name: "batch with one foreign key", ctx: alice, method: "/clavis.v1.Clavis/BatchPut",
+			req:      &proto.BatchPutRequest{Items: []*proto.KeyValue{{Key: "tenant-a/x"}, {Key: "tenant-b/x"}}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "diff of own keys", ctx: alice, method: "/clavis.v1.Clavis/Diff",
+			req: &proto.DiffRequest{
+				Left:  &proto.DiffOperand{Source: &proto.DiffOperand_Key{Key: &proto.KeyVersion{Key: "tenant-a/x"}}},
+				Right: &proto.DiffOperand{Source: &proto.DiffOperand_Value{Value: []byte("{}")}},
+			},
+		},
+		{name: "spill of own prefix", ctx: alice, method: "/clavis.v1.Clavis/FetchSpill", req: &proto.FetchSpillRequest{Prefix: "tenant-a/"}},
+		{name: "spill of other prefix", ctx: alice, method: "/clavis.v1.Clavis/FetchSpill", req: &proto.FetchSpillRequest{Prefix: "tenant-"}, wantCode: codes.PermissionDenied},
+		{name: "range within own prefix", ctx: alice, method: "/clavis.v1.Clavis/Range", req: &proto.RangeRequest{Start: "tenant-a/b", End: "tenant-a/d"}},
+		{name: "range across tenants", ctx: alice, method: "/clavis.v1.Clavis/Range", req: &proto.RangeRequest{Start: "tenant-a/", End: "tenant-b/"}, wantCode: codes.PermissionDenied},
+		{name: "unbounded range", ctx: alice, method: "/clavis.v1.Clavis/Range", req: &proto.RangeRequest{Start: "tenant-a/"}, wantCode: codes.PermissionDenied},
+		{name: "unbounded range of own namespace", ctx: alice, method: "/clavis.v1.Clavis/Range", req: &proto.RangeRequest{Namespace: "tenant-a"}},
+		{name: "exists of own key", ctx: alice, method: "/clavis.v1.Clavis/Exists", req: &proto.ExistsRequest{Key: "tenant-a/x"}},
+		{name: "exists of other key", ctx: alice, method: "/clavis.v1.Clavis/Exists", req: &proto.ExistsRequest{Key: "tenant-b/x"}, wantCode: codes.PermissionDenied},
+		{name: "count of own prefix", ctx: alice, method: "/clavis.v1.Clavis/Count", req: &proto.CountRequest{Namespace: "tenant-a"}},
+		{name: "tag query of own prefix", ctx: alice, method: "/clavis.v1.Clavis/QueryByTag", req: &proto.QueryByTagRequest{Tag: "red", Namespace: "tenant-a"}},
+		{name: "tag query of other prefix", ctx: alice, method: "/clavis.v1.Clavis/QueryByTag", req: &proto.QueryByTagRequest{Tag: "red"}, wantCode: codes.PermissionDenied},
+		{name: "search of own prefix", ctx: alice, method: "/clavis.v1.Clavis/Search", req: &proto.SearchRequest{Query: "red", Namespace: "tenant-a"}},
+		{name: "search of other prefix", ctx: alice, method: "/clavis.v1.Clavis/Search", req: &proto.SearchRequest{Query: "red", Prefix: "tenant-b/"}, wantCode: codes.PermissionDenied},
+		{name: "subscription to own prefix", ctx: alice, method: "/clavis.v1.Clavis/Subscribe", req: &proto.SubscribeRequest{Namespace: "tenant-a"}},
+		{name: "subscription to every key", ctx: alice, method: "/clavis.v1.Clavis/Subscribe", req: &proto.SubscribeRequest{}, wantCode: codes.PermissionDenied},
+		{name: "lock in own namespace", ctx: alice, method: "/clavis.v1.Clavis/Lock", req: &proto.LockRequest{Name: "jobs", Namespace: "tenant-a"}},
+		{name: "unlock in other namespace", ctx: alice, method: "/clavis.v1.Clavis/Unlock", req: &proto.UnlockRequest{Name: "jobs", Namespace: "tenant-b"}, wantCode: codes.PermissionDenied},
+		{name: "put under a lock of another namespace", ctx: alice, method: "/clavis.v1.Clavis/Put", req: &proto.PutRequest{Key: "tenant-a/x", Fencing: &proto.FencingToken{Lock: "tenant-b/orders", Token: 1}}, wantCode: codes.PermissionDenied},
+		{name: "put attached to a lease without delete", ctx: alice, method: "/clavis.v1.Clavis/Put", req: &proto.PutRequest{Key: "tenant-a/x", Lease: 1}, wantCode: codes.PermissionDenied},
+		{name: "attach of other key", ctx: alice, method: "/clavis.v1.Clavis/AttachLease", req: &proto.AttachLeaseRequest{Id: 1, Keys: []string{"x"}, Namespace: "tenant-b"}, wantCode: codes.PermissionDenied},
+		{name: "lease grant", ctx: alice, method: "/clavis.v1.Clavis/GrantLease", req: &proto.GrantLeaseRequest{}},
+		{name: "creation under own prefix", ctx: alice, method: "/clavis.v1.Clavis/CreateUnique", req: &proto.CreateUniqueRequest{Prefix: "jobs/", Namespace: "tenant-a"}},
+		{name: "creation under other prefix", ctx: alice, method: "/clavis.v1.Clavis/CreateUnique", req: &proto.CreateUniqueRequest{Prefix: "tenant-b/jobs/"}, wantCode: codes.PermissionDenied},
+		{name: "sequence in own namespace", ctx: alice, method: "/clavis.v1.Clavis/NextSequence", req: &proto.NextSequenceRequest{Name: "orders", Namespace: "tenant-a"}},
+		{name: "sequence in other namespace", ctx: alice, method: "/clavis.v1.Clavis/NextSequence", req: &proto.NextSequenceRequest{Name: "orders", Namespace: "tenant-b"}, wantCode: codes.PermissionDenied},
+		{name: "dry run of own namespace deletion", ctx: alice, method: "/clavis.v1.Clavis/DeletePrefix", req: &proto.DeletePrefixRequest{Namespace: "tenant-a", DryRun: true}},
+		{name: "deletion of own namespace without delete grant", ctx: alice, method: "/clavis.v1.Clavis/DeletePrefix", req: &proto.DeletePrefixRequest{Namespace: "tenant-a"}, wantCode: codes.PermissionDenied},
+		{name: "dry run of other prefix", ctx: alice, method: "/clavis.v1.Clavis/DeletePrefix", req: &proto.DeletePrefixRequest{Prefix: "tenant-", DryRun: true}, wantCode: codes.PermissionDenied},
+		{name: "copy within own namespace", ctx: alice, method: "/clavis.v1.Clavis/Copy", req: &proto.CopyRequest{Source: "a", Destination: "b", Namespace: "tenant-a"}},
+		{name: "copy from other namespace", ctx: alice, method: "/clavis.v1.Clavis/Copy", req: &proto.CopyRequest{Source: "tenant-b/a", Destination: "tenant-a/b"}, wantCode: codes.PermissionDenied},
+		{name: "copy with ttl without delete grant", ctx: alice, method: "/clavis.v1.Clavis/Copy", req: &proto.CopyRequest{Source: "a", Destination: "b", Namespace: "tenant-a", TtlMs: 1000}, wantCode: codes.PermissionDenied},
+		{name: "count of other prefix", ctx: alice, method: "/clavis.v1.Clavis/Count", req: &proto.CountRequest{Prefix: "tenant-"}, wantCode: codes.PermissionDenied},
+		{name: "increment of own key", ctx: alice, method: "/clavis.v1.Clavis/Increment", req: &proto.IncrementRequest{Key: "tenant-a/hits"}},
+		{name: "increment of other key", ctx: alice, method: "/clavis.v1.Clavis/Increment", req: &proto.IncrementRequest{Key: "tenant-b/hits"}, wantCode: codes.PermissionDenied},
+		{name: "metadata of own key", ctx: alice, method: "/clavis.v1.Clavis/GetMetadata", req: &proto.GetMetadataRequest{Key: "tenant-a/x"}},
+		{name: "metadata of other key", ctx: alice, method: "/clavis.v1.Clavis/GetMetadata", req: &proto.GetMetadataRequest{Key: "tenant-b/x"}, wantCode: codes.PermissionDenied},
+		{name: "undelete of own key", ctx: alice, method: "/clavis.v1.Clavis/Undelete", req: &proto.UndeleteRequest{Key: "x", Namespace: "tenant-a"}},
+		{name: "undelete of other key", ctx: alice, method: "/clavis.v1.Clavis/Undelete", req: &proto.UndeleteRequest{Key: "tenant-b/x"}, wantCode: codes.PermissionDenied},
+		{name: "admin", ctx: alice, method: "/clavis.v1.ClavisAdmin/MetricsSnapshot", req: &proto.MetricsSnapshotRequest{}, wantCode: codes.PermissionDenied},
+		{name: "unauthenticated", ctx: context.Background(), method: "/clavis.v1.Clavis/Get", req: &proto.GetRequest{Key: "tenant-a/x"}, wantCode: codes.Unauthenticated},
+		{name: "unmapped request", ctx: alice, method: "/clavis.v1.Clavis/Future", req: &proto.KeyValue{}, wantCode: codes.PermissionDenied},
+		{name: "exempt method", ctx: context.Background(), method: "/grpc.health.v1.Health/Check", req: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := unary(tt.ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, ok); status.Code(err) != tt.wantCode {
+				t.Errorf("Expected code %v, got %v", tt.wantCode, err)
+			}
+		})
+	}
+
+	t.Run("Stream", func(t *testing.T) {
+		info := &grpc.StreamServerInfo{FullMethod: "/clavis.v1.Clavis/ScanStream"}
+		handler := func(_ any, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&proto.ScanRequest{})
+		}
+		for prefix, want := range map[string]codes.Code{"tenant-a/": codes.OK, "tenant-": codes.PermissionDenied, "": codes.PermissionDenied} {
+			err := stream(nil, &recvStream{ctx: alice, req: &proto.ScanRequest{Prefix: prefix}}, info, handler)
+			if status.Code(err) != want {
+				t.Errorf("Scan of %q: expected code %v, got %v", prefix, want, err)
+			}
+		}
+	})
+}
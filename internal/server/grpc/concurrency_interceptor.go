@@ -0,0 +1,172 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Metric names recorded by the concurrency limiter.
+const (
+	MetricRPCRunning    = "rpc_running"            // Calls holding a slot
+	MetricRPCQueued     = "rpc_queued"             // Calls waiting for a slot
+	MetricRPCQueueWait  = "rpc_queue_wait_seconds" // Time calls waited for a slot
+	MetricRPCOverloaded = "rpc_overloaded_total"   // Calls turned away for lack of a slot
+)
+
+// DefaultConcurrencyExemptions are the methods that never wait for a slot:
+// health probes, which must answer under load, and streams that stay open
+// while idle.
+var DefaultConcurrencyExemptions = append([]string{
+	proto.Clavis_Watch_FullMethodName,
+	proto.Clavis_Subscribe_FullMethodName,
+	proto.Clavis_KeepAlive_FullMethodName,
+	proto.ClavisAdmin_Replicate_FullMethodName,
+}, DefaultAuthExemptions...)
+
+// ConcurrencyConfig bounds the calls the server works on at once, so a slow
+// disk queues or rejects requests instead of piling up goroutines waiting on
+// the store.
+type ConcurrencyConfig struct {
+	MaxInFlight int // Calls whose handlers run at once; unlimited if zero
+
+	// Calls past MaxInFlight wait for a slot, up to MaxQueued of them at
+	// once (as many as MaxInFlight if zero) and for at most QueueTimeout
+	// (as long as their deadline allows if zero). Other calls fail with
+	// UNAVAILABLE.
+	MaxQueued    int
+	QueueTimeout time.Duration
+
+	// Streams a client may open at once on one connection, passed to
+	// grpc.MaxConcurrentStreams; the gRPC default if zero
+	MaxConcurrentStreams uint32
+
+	Exempt []string // Full method names that never wait for a slot
+}
+
+// ServerOptions returns the options to pass to grpc.NewServer for the
+// configuration.
+func (c ConcurrencyConfig) ServerOptions() []grpc.ServerOption {
+	if c.MaxConcurrentStreams == 0 {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.MaxConcurrentStreams(c.MaxConcurrentStreams)}
+}
+
+// ConcurrencyLimiter holds the slots shared by its unary and stream
+// interceptors.
+type ConcurrencyLimiter struct {
+	slots        chan struct{}
+	queued       atomic.Int64
+	maxQueued    int64
+	queueTimeout time.Duration
+	exempt       map[string]bool
+	registry     *metrics.Registry
+}
+
+// NewConcurrencyLimiter creates a limiter recording its queue in registry.
+// It returns nil, which limits nothing, if config sets no MaxInFlight.
+func NewConcurrencyLimiter(config ConcurrencyConfig, registry *metrics.Registry) *ConcurrencyLimiter {
+	if config.MaxInFlight <= 0 {
+		return nil
+	}
+	maxQueued := config.MaxQueued
+	if maxQueued <= 0 {
+		maxQueued = config.MaxInFlight
+	}
+	l := &ConcurrencyLimiter{
+		slots:        make(chan struct{}, config.MaxInFlight),
+		maxQueued:    int64(maxQueued),
+		queueTimeout: config.QueueTimeout,
+		exempt:       make(map[string]bool, len(config.Exempt)),
+		registry:     registry,
+	}
+	for _, method := range config.Exempt {
+		l.exempt[method] = true
+	}
+	return l
+}
+
+// acquire takes a slot for a call of method, waiting in the queue if there is
+// room, and returns the function releasing it.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, method string) (release func(), err error) {
+	if l == nil || l.exempt[method] {
+		return func() {}, nil
+	}
+	running := l.registry.Gauge(MetricRPCRunning)
+	release = func() {
+		<-l.slots
+		running.Add(-1)
+	}
+	select {
+	case l.slots <- struct{}{}:
+		running.Add(1)
+		return release, nil
+	default:
+	}
+
+	queued := l.registry.Gauge(MetricRPCQueued)
+	if l.queued.Add(1) > l.maxQueued {
+		l.queued.Add(-1)
+		return nil, l.overloaded(method, "the queue is full")
+	}
+	queued.Add(1)
+	defer func() {
+		l.queued.Add(-1)
+		queued.Add(-1)
+	}()
+
+	start := time.Now()
+	if l.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+	}
+	select {
+	case l.slots <- struct{}{}:
+		running.Add(1)
+		l.registry.Histogram(MetricRPCQueueWait, metrics.DefaultLatencyBounds).Observe(time.Since(start).Seconds())
+		return release, nil
+	case <-ctx.Done():
+		l.registry.Histogram(MetricRPCQueueWait, metrics.DefaultLatencyBounds).Observe(time.Since(start).Seconds())
+		return nil, l.overloaded(method, fmt.Sprintf("no slot freed up within %v", time.Since(start).Round(time.Millisecond)))
+	}
+}
+
+// overloaded counts a call turned away and returns its OVERLOADED error.
+func (l *ConcurrencyLimiter) overloaded(method, reason string) error {
+	l.registry.Counter(MetricRPCOverloaded).Inc()
+	l.registry.Counter(fmt.Sprintf("%s{method=%q}", MetricRPCOverloaded, method)).Inc()
+	return claviserrors.New(claviserrors.CodeOverloaded, "server overloaded: "+reason).GRPCStatus().Err()
+}
+
+// UnaryInterceptor runs unary calls once they hold a slot.
+func (l *ConcurrencyLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := l.acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor runs streaming calls once they hold a slot, which they
+// keep until they end.
+func (l *ConcurrencyLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
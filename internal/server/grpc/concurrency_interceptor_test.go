@@ -0,0 +1,108 @@
+package proto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	registry := metrics.NewRegistry()
+	limiter := NewConcurrencyLimiter(ConcurrencyConfig{
+		MaxInFlight:  1,
+		MaxQueued:    1,
+		QueueTimeout: 50 * time.Millisecond,
+		Exempt:       DefaultConcurrencyExemptions,
+	}, registry)
+	interceptor := limiter.UnaryInterceptor()
+	get := &grpc.UnaryServerInfo{FullMethod: proto.Clavis_Get_FullMethodName}
+
+	// The first call holds the only slot until released
+	started, release := make(chan struct{}), make(chan struct{})
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, get, func(context.Context, any) (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		firstDone <- err
+	}()
+	<-started
+
+	// The second waits in the queue, the third finds it full
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, get, func(context.Context, any) (any, error) { return nil, nil })
+		secondDone <- err
+	}()
+	for registry.Gauge(MetricRPCQueued).Value() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	_, err := interceptor(context.Background(), nil, get, func(context.Context, any) (any, error) { return nil, nil })
+	if status.Code(err) != codes.Unavailable || claviserrors.CodeOf(err) != claviserrors.CodeOverloaded {
+		t.Errorf("Expected the call past the queue to be rejected, got %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+		func(context.Context, any) (any, error) { return nil, nil }); err != nil {
+		t.Errorf("Expected health checks to skip the limit, got %v", err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-secondDone; err != nil {
+		t.Errorf("Expected the queued call to run once the slot was freed, got %v", err)
+	}
+	if running, queued := registry.Gauge(MetricRPCRunning).Value(), registry.Gauge(MetricRPCQueued).Value(); running != 0 || queued != 0 {
+		t.Errorf("running = %d, queued = %d after every call ended", running, queued)
+	}
+	if n := registry.Counter(MetricRPCOverloaded).Value(); n != 1 {
+		t.Errorf("%s = %d, want 1", MetricRPCOverloaded, n)
+	}
+}
+
+func TestConcurrencyLimiter_QueueTimeout(t *testing.T) {
+	registry := metrics.NewRegistry()
+	limiter := NewConcurrencyLimiter(ConcurrencyConfig{MaxInFlight: 1, QueueTimeout: 10 * time.Millisecond}, registry)
+	stream := limiter.StreamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: proto.Clavis_ScanStream_FullMethodName}
+	ss := &recvStream{ctx: context.Background()}
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go func() {
+		_ = stream(nil, ss, info, func(any, grpc.ServerStream) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	err := stream(nil, ss, info, func(any, grpc.ServerStream) error { return nil })
+	if claviserrors.CodeOf(err) != claviserrors.CodeOverloaded {
+		t.Errorf("Expected the stream to give up waiting, got %v", err)
+	}
+	if n := registry.Histogram(MetricRPCQueueWait, metrics.DefaultLatencyBounds).Count(); n != 1 {
+		t.Errorf("Expected one queue wait to be observed, got %d", n)
+	}
+}
+
+func TestConcurrencyConfig(t *testing.T) {
+	if NewConcurrencyLimiter(ConcurrencyConfig{}, metrics.NewRegistry()) != nil {
+		t.Error("Expected no limiter without MaxInFlight")
+	}
+	if len(ConcurrencyConfig{}.ServerOptions()) != 0 || len(ConcurrencyConfig{MaxConcurrentStreams: 8}.ServerOptions()) != 1 {
+		t.Error("Expected a server option only for MaxConcurrentStreams")
+	}
+}
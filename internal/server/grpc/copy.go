@@ -0,0 +1,86 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Copy writes the value of the source key to the destination key without it
+// leaving the server, and attaches the destination to the requested lease or
+// to a new one granted for ttl_ms.
+func (s *GRPCServer) Copy(ctx context.Context, req *proto.CopyRequest) (*proto.CopyResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	if req.Source == req.Destination {
+		return nil, status.Error(codes.InvalidArgument, "source and destination must differ")
+	}
+	if req.Lease != 0 && req.TtlMs != 0 {
+		return nil, status.Error(codes.InvalidArgument, "lease and ttl_ms are exclusive")
+	}
+	var leases *lease.Manager
+	if req.Lease != 0 || req.TtlMs != 0 {
+		var err error
+		if leases, err = s.leases(); err != nil {
+			return nil, err
+		}
+	}
+	source := inNamespace(req.Namespace, req.Source)
+	destination := inNamespace(req.Namespace, req.Destination)
+
+	value, found, err := store.GetContext(ctx, s.store, source)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "source key %q not found", req.Source)
+	}
+
+	resp := &proto.CopyResponse{}
+	leaseID := req.Lease
+	if req.TtlMs != 0 {
+		granted, err := leases.Grant(time.Duration(req.TtlMs) * time.Millisecond)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		leaseID = granted.ID
+		resp.Lease, resp.ExpiresUnixNano = granted.ID, granted.Expires.UnixNano()
+	}
+
+	ctx, collected := warnings.NewContext(ctx)
+	ctx, version := store.WithVersionRecord(ctx)
+	if req.Overwrite {
+		err = store.PutContext(ctx, s.store, destination, value)
+	} else {
+		err = store.PutIfAbsentContext(ctx, s.store, destination, value)
+	}
+	if err == nil && leaseID != 0 {
+		// Unlike Put, the key is attached once written, since keys stay
+		// attached for good and an existing destination that is not
+		// overwritten must not end with the lease. A copy that cannot be
+		// attached, such as to a lease that just ended, is deleted.
+		if err = leases.Attach(leaseID, destination); err != nil {
+			if deleteErr := store.DeleteContext(ctx, s.store, destination); deleteErr != nil {
+				err = errors.Join(err, deleteErr)
+			}
+		}
+	}
+	if err != nil {
+		if resp.Lease != 0 {
+			if _, revokeErr := leases.Revoke(resp.Lease); revokeErr != nil {
+				s.logger().Warn("Failed to revoke lease", "lease", resp.Lease, "error", revokeErr)
+			}
+		}
+		return nil, convertError(err)
+	}
+	resp.Warnings, resp.Version = protoWarnings(collected), version()
+	return resp, nil
+}
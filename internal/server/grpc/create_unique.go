@@ -0,0 +1,36 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/keygen"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var keyKinds = map[proto.KeyKind]keygen.Kind{
+	proto.KeyKind_KEY_KIND_ULID:     keygen.ULID,
+	proto.KeyKind_KEY_KIND_UUID:     keygen.UUID,
+	proto.KeyKind_KEY_KIND_SEQUENCE: keygen.Sequence,
+}
+
+// CreateUnique stores the value under a new key generated from the requested
+// prefix and returns the key.
+func (s *GRPCServer) CreateUnique(ctx context.Context, req *proto.CreateUniqueRequest) (*proto.CreateUniqueResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	kind, ok := keyKinds[req.Kind]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown key kind %d", req.Kind)
+	}
+	ctx, version := store.WithVersionRecord(ctx)
+	key, err := keygen.Create(ctx, s.store, keygen.Template{Prefix: inNamespace(req.Namespace, req.Prefix), Kind: kind}, req.Value)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.CreateUniqueResponse{Key: strings.TrimPrefix(key, inNamespace(req.Namespace, "")), Version: version()}, nil
+}
@@ -0,0 +1,41 @@
+package proto
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// encodeCursor turns the last key of a page into an opaque resume token.
+func encodeCursor(lastKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastKey))
+}
+
+// decodeCursor recovers the key a page ended at. An empty cursor starts from
+// the beginning.
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor: %w", err)
+	}
+	return string(key), nil
+}
+
+// resumeRange narrows r to the keys after the key a page ended at, in the
+// order r selects. The result never leaves r, whatever the cursor holds.
+func resumeRange(r store.KeyRange, lastKey string) store.KeyRange {
+	if r.Reverse {
+		if r.End == "" || lastKey < r.End {
+			r.End = lastKey
+		}
+		return r
+	}
+	if next := lastKey + "\x00"; next > r.Start {
+		r.Start = next
+	}
+	return r
+}
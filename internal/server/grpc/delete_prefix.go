@@ -0,0 +1,104 @@
+package proto
+
+import (
+	"context"
+	"errors"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/quota"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeletePrefix deletes every key starting with the requested prefix within
+// its namespace, or only counts them on a dry run. A dry run issues the
+// confirmation token that deleting the keys requires.
+func (s *GRPCServer) DeletePrefix(ctx context.Context, req *proto.DeletePrefixRequest) (*proto.DeletePrefixResponse, error) {
+	if s.config.Confirmations == nil {
+		return nil, errSubsystemDisabled("destructive operations")
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	prefix := inNamespace(req.Namespace, req.Prefix)
+	if prefix == "" {
+		return nil, status.Error(codes.InvalidArgument, "prefix is required outside of a namespace")
+	}
+	// Prefixes such as "__" would reach the server's own keys as well
+	if err := store.CheckReservedPrefix(prefix); err != nil {
+		return nil, convertError(err)
+	}
+
+	action := "delete-prefix:" + prefix
+	if req.DryRun {
+		n, err := store.Count(ctx, s.store, prefix)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, status.FromContextError(ctxErr).Err()
+			}
+			return nil, convertError(err)
+		}
+		token, expires := s.config.Confirmations.Issue(action)
+		return &proto.DeletePrefixResponse{Count: n, ConfirmationToken: token, ConfirmationExpiresAtUnixNano: expires.UnixNano()}, nil
+	}
+
+	if req.ConfirmationToken == "" {
+		return nil, status.Error(codes.FailedPrecondition, "a confirmation token is required; request one with a dry run")
+	}
+	if err := s.config.Confirmations.Redeem(req.ConfirmationToken, action); err != nil {
+		if errors.Is(err, confirm.ErrInvalidToken) || errors.Is(err, confirm.ErrExpiredToken) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v; request a new one with a dry run", err)
+		}
+		return nil, convertError(err)
+	}
+	n, err := s.deletePrefix(prefix)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	logging.FromContext(ctx).Info("Deleted prefix", "prefix", prefix, "keys", n, "requested_by", callerIdentity(ctx))
+	return &proto.DeletePrefixResponse{Count: n}, nil
+}
+
+// deletePrefix deletes the keys under prefix with the configured
+// PrefixDeleter, unless the store is read-only, and through the store
+// otherwise. Since the PrefixDeleter bypasses the store wrappers, the keys it
+// deletes are then published on the event bus and recounted by the quota
+// wrapper, as deleting them through the store would have.
+func (s *GRPCServer) deletePrefix(prefix string) (int64, error) {
+	if s.config.PrefixDeleter == nil {
+		return store.DeletePrefix(s.store, prefix)
+	}
+	var (
+		n       int64
+		deleted []string
+	)
+	apply := func() (err error) {
+		n, err = s.config.PrefixDeleter.DeletePrefix(prefix, func(key string) {
+			deleted = append(deleted, key)
+		})
+		return err
+	}
+	var err error
+	if mode, ok := store.As[*readonly.Store](s.store); ok {
+		err = mode.Guard(apply)
+	} else {
+		err = apply()
+	}
+	if err != nil {
+		return n, err
+	}
+	if quotas, ok := store.As[*quota.Store](s.store); ok {
+		quotas.RecountKeys(deleted)
+	}
+	if s.config.Events != nil {
+		for _, key := range deleted {
+			s.config.Events.Publish(events.Event{Type: events.TypeDelete, Key: key})
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package proto
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/jsondiff"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var diffOps = map[jsondiff.Op]proto.DiffChange_Op{
+	jsondiff.OpAdded:   proto.DiffChange_OP_ADDED,
+	jsondiff.OpRemoved: proto.DiffChange_OP_REMOVED,
+	jsondiff.OpChanged: proto.DiffChange_OP_CHANGED,
+}
+
+// Diff compares two JSON documents, each read from a key (optionally at a
+// past version) or given inline, and returns their structural differences.
+func (s *GRPCServer) Diff(ctx context.Context, req *proto.DiffRequest) (*proto.DiffResponse, error) {
+	left, leftVersion, err := s.resolveDiffOperand("left", req.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, rightVersion, err := s.resolveDiffOperand("right", req.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := jsondiff.Diff(left, right)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &proto.DiffResponse{
+		Changes:      make([]*proto.DiffChange, 0, len(changes)),
+		LeftVersion:  leftVersion,
+		RightVersion: rightVersion,
+	}
+	for _, change := range changes {
+		converted := &proto.DiffChange{Op: diffOps[change.Op], Path: change.Path}
+		if change.Op != jsondiff.OpAdded {
+			if converted.OldValue, err = json.Marshal(change.Old); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		if change.Op != jsondiff.OpRemoved {
+			if converted.NewValue, err = json.Marshal(change.New); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		resp.Changes = append(resp.Changes, converted)
+	}
+	return resp, nil
+}
+
+// resolveDiffOperand returns the document an operand refers to and, for
+// stored keys on versioned stores, the version it was read at.
+func (s *GRPCServer) resolveDiffOperand(side string, operand *proto.DiffOperand) ([]byte, uint64, error) {
+	switch source := operand.GetSource().(type) {
+	case *proto.DiffOperand_Value:
+		return source.Value, 0, nil
+	case *proto.DiffOperand_Key:
+		ref := source.Key
+		var value []byte
+		var version uint64
+		var found bool
+		var err error
+
+		if reader, ok := store.As[store.VersionReader](s.store); ok {
+			value, version, found, err = reader.GetAt(ref.Key, ref.Version)
+		} else if ref.Version != 0 {
+			return nil, 0, status.Error(codes.Unimplemented, "the store does not retain previous versions")
+		} else {
+			value, found, err = s.store.Get(ref.Key)
+		}
+		if err != nil {
+			return nil, 0, convertError(err)
+		}
+		if !found {
+			return nil, 0, status.Errorf(codes.NotFound, "%s key %q not found", side, ref.Key)
+		}
+		return value, version, nil
+	default:
+		return nil, 0, status.Errorf(codes.InvalidArgument, "%s operand is required", side)
+	}
+}
@@ -0,0 +1,103 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// versionedStore serves fixed historical values for GetAt
+type versionedStore struct {
+	*mockStore
+	history map[uint64][]byte
+}
+
+func (v *versionedStore) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	value, found := v.history[version]
+	return value, version, found, nil
+}
+
+func keyOperand(key string, version uint64) *proto.DiffOperand {
+	return &proto.DiffOperand{Source: &proto.DiffOperand_Key{Key: &proto.KeyVersion{Key: key, Version: version}}}
+}
+
+func valueOperand(value string) *proto.DiffOperand {
+	return &proto.DiffOperand{Source: &proto.DiffOperand_Value{Value: []byte(value)}}
+}
+
+func TestGRPCServer_Diff(t *testing.T) {
+	ctx := context.Background()
+	mockStore := newMockStore()
+	mockStore.data["config"] = []byte(`{"port": 80, "debug": true}`)
+	s := &GRPCServer{store: mockStore, config: &GRPCServerConfig{}}
+
+	t.Run("KeyAgainstValue", func(t *testing.T) {
+		resp, err := s.Diff(ctx, &proto.DiffRequest{
+			Left:  keyOperand("config", 0),
+			Right: valueOperand(`{"port": 8080, "hosts": ["a"]}`),
+		})
+		if err != nil {
+			t.Fatalf("Diff failed: %v", err)
+		}
+		want := []struct {
+			op       proto.DiffChange_Op
+			path     string
+			old, new string
+		}{
+			{proto.DiffChange_OP_REMOVED, "/debug", "true", ""},
+			{proto.DiffChange_OP_ADDED, "/hosts", "", `["a"]`},
+			{proto.DiffChange_OP_CHANGED, "/port", "80", "8080"},
+		}
+		if len(resp.Changes) != len(want) {
+			t.Fatalf("Expected %d changes, got %v", len(want), resp.Changes)
+		}
+		for i, w := range want {
+			got := resp.Changes[i]
+			if got.Op != w.op || got.Path != w.path || string(got.OldValue) != w.old || string(got.NewValue) != w.new {
+				t.Errorf("Change %d: got %v, want %+v", i, got, w)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			req  *proto.DiffRequest
+			code codes.Code
+		}{
+			{"MissingOperand", &proto.DiffRequest{Left: keyOperand("config", 0)}, codes.InvalidArgument},
+			{"MissingKey", &proto.DiffRequest{Left: keyOperand("nope", 0), Right: valueOperand(`{}`)}, codes.NotFound},
+			{"InvalidJSON", &proto.DiffRequest{Left: valueOperand(`{`), Right: valueOperand(`{}`)}, codes.InvalidArgument},
+			{"VersionsUnsupported", &proto.DiffRequest{Left: keyOperand("config", 3), Right: valueOperand(`{}`)}, codes.Unimplemented},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := s.Diff(ctx, tt.req); status.Code(err) != tt.code {
+					t.Errorf("Expected %v, got %v", tt.code, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Versions", func(t *testing.T) {
+		versioned := &versionedStore{mockStore: mockStore, history: map[uint64][]byte{
+			3: []byte(`{"port": 80}`),
+			7: []byte(`{"port": 81}`),
+		}}
+		s := &GRPCServer{store: versioned, config: &GRPCServerConfig{}}
+
+		resp, err := s.Diff(ctx, &proto.DiffRequest{Left: keyOperand("config", 3), Right: keyOperand("config", 7)})
+		if err != nil {
+			t.Fatalf("Diff failed: %v", err)
+		}
+		if resp.LeftVersion != 3 || resp.RightVersion != 7 {
+			t.Errorf("Unexpected resolved versions %d and %d", resp.LeftVersion, resp.RightVersion)
+		}
+		if len(resp.Changes) != 1 || resp.Changes[0].Path != "/port" {
+			t.Errorf("Unexpected changes: %v", resp.Changes)
+		}
+	})
+}
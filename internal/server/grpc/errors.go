@@ -0,0 +1,123 @@
+package proto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/index"
+	"github.com/William-Fernandes252/clavis/internal/keygen"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/pubsub"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/checksum"
+	"github.com/William-Fernandes252/clavis/internal/store/crypto"
+	"github.com/William-Fernandes252/clavis/internal/store/keymeta"
+	"github.com/William-Fernandes252/clavis/internal/store/quota"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+	"github.com/William-Fernandes252/clavis/internal/store/softdelete"
+	"github.com/William-Fernandes252/clavis/internal/store/tags"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+)
+
+// convertError converts the errors of the store and the domain packages to
+// gRPC status errors carrying their stable code from pkg/errors. Validation
+// failures also describe each violation, see validationError, and errors it
+// does not know are reported as INTERNAL.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errs := validation.As(err); len(errs) > 0 {
+		return validationError(err, errs)
+	}
+	return claviserrors.New(errorCode(err), err.Error()).GRPCStatus().Err()
+}
+
+// errorCode returns the stable code of a domain error.
+func errorCode(err error) claviserrors.Code {
+	var corruptionErr *checksum.CorruptionError
+	switch {
+	case errors.Is(err, lock.ErrStaleFencingToken):
+		return claviserrors.CodeStaleFencingToken
+	case errors.Is(err, lock.ErrLockHeld):
+		return claviserrors.CodeLockHeld
+	case errors.Is(err, lock.ErrNotHeld):
+		return claviserrors.CodeLeaseNotHeld
+	case errors.Is(err, lock.ErrUnissuedFencingToken):
+		return claviserrors.CodeInvalidArgument
+	case errors.Is(err, lock.ErrTokensExhausted):
+		return claviserrors.CodePreconditionFailed
+	case errors.Is(err, lease.ErrNotFound):
+		return claviserrors.CodeLeaseNotFound
+	case errors.Is(err, store.ErrVersionConflict):
+		return claviserrors.CodeVersionConflict
+	case errors.Is(err, keygen.ErrExhausted):
+		return claviserrors.CodePreconditionFailed
+	case errors.Is(err, store.ErrConditionalPutUnsupported) || errors.Is(err, store.ErrSnapshotsUnsupported) ||
+		errors.Is(err, store.ErrSequencesUnsupported):
+		return claviserrors.CodeUnsupported
+	case errors.Is(err, store.ErrSnapshotUnavailable):
+		return claviserrors.CodeSnapshotUnavailable
+	case errors.Is(err, store.ErrPreconditionFailed):
+		return claviserrors.CodePreconditionFailed
+	case errors.Is(err, readonly.ErrReadOnly):
+		return claviserrors.CodeReadOnly
+	case errors.Is(err, store.ErrNotCounter):
+		return claviserrors.CodeNotCounter
+	case errors.Is(err, store.ErrCounterOverflow):
+		return claviserrors.CodeCounterOverflow
+	case errors.Is(err, quota.ErrQuotaExceeded):
+		return claviserrors.CodeQuotaExceeded
+	case errors.Is(err, pubsub.ErrTooManySubscribers):
+		return claviserrors.CodeTooManySubscribers
+	case errors.Is(err, pubsub.ErrSlowConsumer):
+		return claviserrors.CodeSlowConsumer
+	case errors.Is(err, softdelete.ErrNotDeleted):
+		return claviserrors.CodeNotFound
+	case errors.Is(err, softdelete.ErrNotEnabled) || errors.Is(err, keymeta.ErrNotTracked) ||
+		errors.Is(err, tags.ErrNotIndexed) || errors.Is(err, index.ErrNotIndexed):
+		return claviserrors.CodeUnsupported
+	case errors.Is(err, store.ErrReservedKey) || errors.Is(err, crypto.ErrReservedKey) || errors.Is(err, tags.ErrReservedKey) || errors.Is(err, index.ErrReservedKey) ||
+		errors.Is(err, tags.ErrInvalidTag) || errors.Is(err, index.ErrEmptyQuery) ||
+		errors.Is(err, lock.ErrInvalidTTL) || errors.Is(err, lease.ErrInvalidTTL) ||
+		errors.Is(err, store.ErrInvalidSequenceRange) || errors.Is(err, store.ErrUnsortedLoad):
+		return claviserrors.CodeInvalidArgument
+	case errors.Is(err, crypto.ErrDecryptFailed) || errors.As(err, &corruptionErr):
+		return claviserrors.CodeDataLoss
+	case errors.Is(err, keymeta.ErrCorruptValue) || errors.Is(err, softdelete.ErrCorruptValue) ||
+		errors.Is(err, tags.ErrCorruptRecord) || errors.Is(err, index.ErrCorruptIndex) ||
+		errors.Is(err, lock.ErrCorruptLease) || errors.Is(err, lease.ErrCorruptRecord):
+		return claviserrors.CodeDataLoss
+	}
+
+	// Validation errors of the stores; Badger capitalizes its own
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "key cannot be empty") ||
+		strings.Contains(msg, "key too long") ||
+		strings.Contains(msg, "value too large"):
+		return claviserrors.CodeInvalidArgument
+	case strings.Contains(msg, "not found"):
+		return claviserrors.CodeNotFound
+	default:
+		return claviserrors.CodeInternal
+	}
+}
+
+// validationError returns a VALIDATION_FAILED status error for err with a
+// violation per validation error.
+func validationError(err error, errs []*validation.ValidationError) error {
+	e := claviserrors.New(claviserrors.CodeValidationFailed, err.Error())
+	for _, verr := range errs {
+		e.Violations = append(e.Violations, claviserrors.Violation{
+			Field:    verr.Target,
+			Code:     strings.ToUpper(string(verr.Code)),
+			Message:  verr.Message,
+			Metadata: verr.Metadata,
+		})
+	}
+	return e.GRPCStatus().Err()
+}
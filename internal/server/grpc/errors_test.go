@@ -0,0 +1,85 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/pubsub"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/quota"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConvertError_ValidationDetails(t *testing.T) {
+	var result validation.ValidationResult
+	result.Add(validation.NewError(validation.Context{Target: "key"}, validation.CodeTooLong, "key is too long").WithMetadata("max", "16"))
+	result.Add(validation.NewError(validation.Context{Target: "value"}, validation.CodeInvalidContent, "value is not JSON"))
+
+	err := convertError(fmt.Errorf("put rejected: %w", result.Err()))
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Code = %v, want InvalidArgument", st.Code())
+	}
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.FieldViolations...)
+		}
+	}
+	if len(violations) != 2 || violations[0].Field != "key" || violations[0].Reason != "TOO_LONG" || violations[1].Description != "value is not JSON" {
+		t.Errorf("Field violations = %v, want one for the key and one for the value", violations)
+	}
+
+	decoded := claviserrors.FromError(err)
+	if decoded.Code != claviserrors.CodeValidationFailed || len(decoded.Violations) != 2 {
+		t.Fatalf("Decoded error = %+v, want a validation failure with 2 violations", decoded)
+	}
+	if metadata := decoded.Violations[0].Metadata; metadata["max"] != "16" || metadata["field"] != "key" {
+		t.Errorf("Metadata of the key violation = %v, want its limit and field", metadata)
+	}
+
+	joined := errors.Join(result.Err(), errors.New("disk full"))
+	if decoded := claviserrors.FromError(convertError(joined)); decoded.Code != claviserrors.CodeValidationFailed || len(decoded.Violations) != 2 {
+		t.Errorf("Joined validation errors = %+v, want a validation failure with 2 violations", decoded)
+	}
+
+	single := validation.NewError(validation.Context{Target: "key"}, validation.CodeRequired, "key is required")
+	if decoded := claviserrors.FromError(convertError(single)); decoded.Code != claviserrors.CodeValidationFailed || len(decoded.Violations) != 1 {
+		t.Errorf("Single validation error = %+v, want a validation failure with 1 violation", decoded)
+	}
+}
+
+func TestConvertError_Codes(t *testing.T) {
+	tests := []struct {
+		err      error
+		want     codes.Code
+		wantCode claviserrors.Code
+	}{
+		{errors.New("key not found"), codes.NotFound, claviserrors.CodeNotFound},
+		{errors.New("Key cannot be empty"), codes.InvalidArgument, claviserrors.CodeInvalidArgument},
+		{fmt.Errorf("put: %w", quota.ErrQuotaExceeded), codes.ResourceExhausted, claviserrors.CodeQuotaExceeded},
+		{pubsub.ErrSlowConsumer, codes.ResourceExhausted, claviserrors.CodeSlowConsumer},
+		{fmt.Errorf("acquire: %w", lock.ErrLockHeld), codes.FailedPrecondition, claviserrors.CodeLockHeld},
+		{store.ErrKeyExists, codes.FailedPrecondition, claviserrors.CodePreconditionFailed},
+		{store.ErrVersionConflict, codes.Aborted, claviserrors.CodeVersionConflict},
+		{errors.New("disk on fire"), codes.Internal, claviserrors.CodeInternal},
+	}
+	for _, tt := range tests {
+		err := convertError(tt.err)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("convertError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+		if got := claviserrors.CodeOf(err); got != tt.wantCode {
+			t.Errorf("Code of convertError(%v) = %s, want %s", tt.err, got, tt.wantCode)
+		}
+	}
+	if convertError(nil) != nil {
+		t.Error("Expected no error for nil")
+	}
+}
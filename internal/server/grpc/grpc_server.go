@@ -2,36 +2,140 @@ package proto
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
-	"os"
-	"os/signal"
 	"strings"
-	"syscall"
+	"sync"
+	"time"
 
 	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/confirm"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/pubsub"
 	"github.com/William-Fernandes252/clavis/internal/server"
 	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/softdelete"
+	"github.com/William-Fernandes252/clavis/internal/tlsutil"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
 // GRPCServerConfig defines the configuration for the gRPC server.
 type GRPCServerConfig struct {
-	Port string
+	Port         string
+	Events       *events.Bus       // Optional bus for lifecycle events
+	PubSub       *pubsub.Broker    // Optional broker serving Subscribe
+	Fencer       *lock.Fencer      // Optional fencer checking tokens on lock-protected writes
+	Locker       *lock.Locker      // Optional locker serving Lock, RenewLock and Unlock
+	Leases       *lease.Manager    // Optional manager of the leases keys are attached to
+	Namespaces   NamespaceRegistry // Optional registry failing requests for namespaces that were not created with NOT_FOUND
+	DrainTimeout time.Duration     // How long Shutdown waits for in-flight requests; DefaultDrainTimeout if zero
+	// PrefixDeleter, if set, serves DeletePrefix straight from the backend,
+	// bypassing the store wrappers; set only when none of them transforms or
+	// caches values. The keys it deletes are published on Events and
+	// recounted by the quota wrapper of the store afterwards. Keys are
+	// deleted in batches through the store otherwise.
+	PrefixDeleter store.PrefixDeleter
+	// Confirmations enables DeletePrefix, whose dry runs issue the tokens
+	// that deleting the keys requires; it may be shared with the admin server
+	Confirmations *confirm.Issuer
+	// How often the health service probes the store; DefaultHealthCheckInterval if zero
+	HealthCheckInterval time.Duration
+	Logger              *slog.Logger // Logger for server lifecycle records; slog.Default() if nil
+
+	// Concurrency bounds the calls served at once, through the interceptors
+	// of a ConcurrencyLimiter and its ServerOptions
+	Concurrency ConcurrencyConfig
+
+	// LoadShedder, if set, is checked with the health of the store and its
+	// state reported under FullServiceHealthName
+	LoadShedder *LoadShedder
+
+	// Scans that allow spilling are written to SpillDir (os.TempDir() if
+	// empty) once their results exceed MaxScanResponseBytes
+	// (DefaultMaxScanResponseBytes if zero), and kept until SpillTTL
+	// (DefaultSpillTTL if zero) passes without a fetch.
+	SpillDir             string
+	SpillTTL             time.Duration
+	MaxScanResponseBytes int
+
+	// TLS is enabled when TLSCertFile and TLSKeyFile are set. Setting
+	// TLSClientCAFile as well requires clients to present a certificate
+	// signed by one of its CAs.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+}
+
+// TLSEnabled reports whether the configuration serves over TLS.
+func (c *GRPCServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != ""
+}
+
+// Credentials returns the transport credentials to pass to grpc.Creds when
+// creating the gRPC server: TLS when configured, insecure otherwise.
+func (c *GRPCServerConfig) Credentials() (credentials.TransportCredentials, error) {
+	if !c.TLSEnabled() {
+		if c.TLSClientCAFile != "" {
+			return nil, errors.New("client certificate verification requires a server certificate")
+		}
+		return insecure.NewCredentials(), nil
+	}
+	config, err := tlsutil.ServerConfig(c.TLSCertFile, c.TLSKeyFile, c.TLSClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
 }
 
 var DefaultConfig = GRPCServerConfig{
-	Port: ":50051",
+	Port:         ":50051",
+	DrainTimeout: DefaultDrainTimeout,
 }
 
+// DefaultDrainTimeout bounds how long Shutdown waits for in-flight requests.
+const DefaultDrainTimeout = 30 * time.Second
+
+// DefaultHealthCheckInterval is how often the health service probes the store.
+const DefaultHealthCheckInterval = 5 * time.Second
+
+const (
+	// DefaultScanLimit is the page size used when a Scan request does not set one.
+	DefaultScanLimit = 1000
+	// MaxScanLimit caps the page size a client may request.
+	MaxScanLimit = 10000
+	// MaxBatchSize caps the number of operations in a single batch request.
+	MaxBatchSize = 10000
+)
+
 // GRPCServer implements the server.Server interface for gRPC.
 type GRPCServer struct {
 	proto.UnimplementedClavisServer
 	store  store.Store
 	config *GRPCServerConfig
 	server *grpc.Server
+
+	// health serves grpc.health.v1.Health and stopHealth ends the store
+	// probe; both are created on first use
+	healthOnce sync.Once
+	health     *health.Server
+	stopHealth chan struct{}
+	stopOnce   sync.Once
+
+	// spills holds scan results spilled to disk; created on first use
+	spillsOnce sync.Once
+	spills     *spills
 }
 
 // New creates a new instance of GRPCServer with the provided store, configuration, and gRPC server.
@@ -43,9 +147,26 @@ func New(store store.Store, config *GRPCServerConfig, server *grpc.Server) (*GRP
 	}, nil
 }
 
-// Get retrieves the value associated with the key from the store.
+// Get retrieves the value associated with the key from the store, along with
+// its version when requested, or as it was at a past version.
 func (s *GRPCServer) Get(ctx context.Context, req *proto.GetRequest) (*proto.GetResponse, error) {
-	value, found, err := s.store.Get(req.Key)
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	key := inNamespace(req.Namespace, req.Key)
+	if req.WithVersion || req.AtVersion != 0 {
+		reader, ok := store.As[store.VersionReader](s.store)
+		if !ok {
+			return nil, status.Error(codes.Unimplemented, "versions are not tracked by this store")
+		}
+		value, version, found, err := reader.GetAt(key, req.AtVersion)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		return &proto.GetResponse{Value: value, Found: found, Version: version}, nil
+	}
+
+	value, found, err := store.GetContext(ctx, s.store, key)
 	if err != nil {
 		return nil, convertError(err)
 	}
@@ -53,23 +174,459 @@ func (s *GRPCServer) Get(ctx context.Context, req *proto.GetRequest) (*proto.Get
 }
 
 // Put stores the value associated with the key in the store.
+// If the request carries a fencing token, the write is rejected unless the
+// token is the latest one issued for its lock, named within the namespace of
+// the request. If it carries an expected
+// version, the write is rejected when the key has moved on since, and if it
+// must not exist, when the key exists. The response carries the version of the
+// write for stores that track versions. Tags it carries replace those of the key.
 func (s *GRPCServer) Put(ctx context.Context, req *proto.PutRequest) (*proto.PutResponse, error) {
-	if err := s.store.Put(req.Key, req.Value); err != nil {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	if req.MustNotExist && req.ExpectedVersion != nil {
+		return nil, status.Error(codes.InvalidArgument, "must_not_exist and expected_version are exclusive")
+	}
+	key := inNamespace(req.Namespace, req.Key)
+	ctx, err := s.tagWrite(ctx, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	// The key is attached first, so it cannot outlive a lease that ends while
+	// it is written
+	if req.Lease != 0 {
+		leases, err := s.leases()
+		if err != nil {
+			return nil, err
+		}
+		if err := leases.Attach(req.Lease, key); err != nil {
+			return nil, convertError(err)
+		}
+	}
+	ctx, collected := warnings.NewContext(ctx)
+	ctx, version := store.WithVersionRecord(ctx)
+	write := func() error { return store.PutContext(ctx, s.store, key, req.Value) }
+	switch {
+	case req.ExpectedVersion != nil:
+		write = func() error { return store.PutIfVersionContext(ctx, s.store, key, req.Value, *req.ExpectedVersion) }
+	case req.MustNotExist:
+		write = func() error { return store.PutIfAbsentContext(ctx, s.store, key, req.Value) }
+	}
+
+	if req.Fencing != nil {
+		if s.config == nil || s.config.Fencer == nil {
+			return nil, status.Error(codes.FailedPrecondition, "fencing tokens are not enabled on this server")
+		}
+		err = s.config.Fencer.Guard(inNamespace(req.Namespace, req.Fencing.Lock), req.Fencing.Token, write)
+	} else {
+		err = write()
+	}
+	if err != nil {
 		return nil, convertError(err)
 	}
-	return &proto.PutResponse{}, nil
+	return &proto.PutResponse{Warnings: protoWarnings(collected), Version: version()}, nil
 }
 
 // Delete removes the key-value pair associated with the key from the store.
 func (s *GRPCServer) Delete(ctx context.Context, req *proto.DeleteRequest) (*proto.DeleteResponse, error) {
-	if err := s.store.Delete(req.Key); err != nil {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	ctx, collected := warnings.NewContext(ctx)
+	if err := store.DeleteContext(ctx, s.store, inNamespace(req.Namespace, req.Key)); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.DeleteResponse{Warnings: protoWarnings(collected)}, nil
+}
+
+// Scan returns a page of key-value pairs whose keys start with the requested
+// prefix, in key order, with a cursor to resume from. Results of requests that
+// allow spilling are spilled to disk when too large for one response, and
+// requests for a point in time read every key as of one store version.
+// Pages of the latest data are read from an iterator that seeks past the
+// cursor, so each page only reads its own keys.
+func (s *GRPCServer) Scan(ctx context.Context, req *proto.ScanRequest) (*proto.ScanResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	req = scanInNamespace(req)
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	version, err := s.scanVersion(req)
+	if err != nil {
+		return nil, err
+	}
+	tracker, err := s.scanMetadata(req)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(req.Limit)
+	if !req.AllowSpill {
+		limit, err = scanLimit(req.Limit)
+	} else if req.Limit < 0 {
+		err = status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.iteratorScan(ctx, req, tracker, version, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	resp.AsOfVersion = version
+	outOfNamespace(req.Namespace, resp.Items)
+	return resp, nil
+}
+
+// ScanStream streams the key-value pairs whose keys start with the requested
+// prefix, in key order, without buffering the whole result set. A zero limit
+// streams every match; the cursor resumes after a previously seen key.
+// Requests for a point in time stream every key as of one store version.
+func (s *GRPCServer) ScanStream(req *proto.ScanRequest, stream grpc.ServerStreamingServer[proto.KeyValue]) error {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return err
+	}
+	req = scanInNamespace(req)
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Limit < 0 {
+		return status.Error(codes.InvalidArgument, "limit cannot be negative")
+	}
+	version, err := s.scanVersion(req)
+	if err != nil {
+		return err
+	}
+	tracker, err := s.scanMetadata(req)
+	if err != nil {
+		return err
+	}
+
+	// Send copies values into the message before returning, so the values
+	// ForEach visits in place can be sent as they are
+	sent := 0
+	var sendErr error
+	send := func(key string, value []byte) error {
+		if (after != "" && key <= after) || store.IsReserved(key) {
+			return nil
+		}
+		if err := stream.Context().Err(); err != nil {
+			sendErr = status.FromContextError(err).Err()
+			return sendErr
+		}
+		item := &proto.KeyValue{Key: key}
+		if !req.KeysOnly {
+			item.Value = value
+		}
+		if sendErr = annotate(tracker, []*proto.KeyValue{item}, version); sendErr != nil {
+			return sendErr
+		}
+		outOfNamespace(req.Namespace, []*proto.KeyValue{item})
+		if sendErr = stream.Send(item); sendErr != nil {
+			return sendErr
+		}
+		sent++
+		if req.Limit > 0 && sent >= int(req.Limit) {
+			return store.ErrStopIteration
+		}
+		return nil
+	}
+
+	// Metadata is read while visiting, which ForEach does not allow
+	if version == 0 && !req.KeysOnly && tracker == nil {
+		err = store.ForEach(s.store, req.Prefix, send)
+	} else {
+		var it store.Iterator
+		if it, err = s.scanIterator(req, after, version); err == nil {
+			err = store.VisitIterator(it, send)
+		}
+	}
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case err != nil:
+		return convertError(err)
+	}
+	return nil
+}
+
+// Range returns a page of pairs whose keys are in [start, end), in key order
+// or, when reversed, in descending order from the end of the range, with a
+// cursor to resume from. Stores that can seek start at the range instead of
+// walking every key.
+func (s *GRPCServer) Range(ctx context.Context, req *proto.RangeRequest) (*proto.RangeResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	if req.End != "" && req.Start > req.End {
+		return nil, status.Error(codes.InvalidArgument, "range start cannot be after its end")
+	}
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	limit, err := scanLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rangeInNamespace(req)
+	if after != "" {
+		r = resumeRange(r, after)
+	}
+	// One pair past the page tells whether there is another page
+	pairs, err := listRange(s.store, r, limit+1)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	resp := &proto.RangeResponse{}
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+		resp.NextCursor = encodeCursor(pairs[limit-1].Key)
+	}
+	resp.Items = make([]*proto.KeyValue, 0, len(pairs))
+	for _, pair := range pairs {
+		resp.Items = append(resp.Items, &proto.KeyValue{Key: pair.Key, Value: pair.Value})
+	}
+	outOfNamespace(req.Namespace, resp.Items)
+	return resp, nil
+}
+
+// Exists reports whether the key is in the store, without reading its value.
+func (s *GRPCServer) Exists(ctx context.Context, req *proto.ExistsRequest) (*proto.ExistsResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	found, err := store.Exists(s.store, inNamespace(req.Namespace, req.Key))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.ExistsResponse{Found: found}, nil
+}
+
+// Count returns the number of keys starting with the requested prefix,
+// without reading their values.
+func (s *GRPCServer) Count(ctx context.Context, req *proto.CountRequest) (*proto.CountResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	prefix := inNamespace(req.Namespace, req.Prefix)
+	n, err := store.Count(ctx, s.store, prefix)
+	if err == nil && strings.HasPrefix(store.ReservedPrefix, prefix) {
+		// Prefixes such as "" reach the server's own keys, which are not
+		// counted as they are not listed
+		var reserved int64
+		reserved, err = store.Count(ctx, s.store, store.ReservedPrefix)
+		n = max(n-reserved, 0)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, convertError(err)
+	}
+	return &proto.CountResponse{Count: n}, nil
+}
+
+// Increment adds the requested delta to the counter stored at the key
+// atomically and returns the new value.
+func (s *GRPCServer) Increment(ctx context.Context, req *proto.IncrementRequest) (*proto.IncrementResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	var encoding store.CounterEncoding
+	switch req.Encoding {
+	case proto.CounterEncoding_COUNTER_ENCODING_ASCII:
+		encoding = store.CounterASCII
+	case proto.CounterEncoding_COUNTER_ENCODING_LITTLE_ENDIAN:
+		encoding = store.CounterLittleEndian
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown counter encoding %d", req.Encoding)
+	}
+	value, err := store.Increment(ctx, s.store, inNamespace(req.Namespace, req.Key), req.Delta, encoding)
+	if err != nil {
 		return nil, convertError(err)
 	}
-	return &proto.DeleteResponse{}, nil
+	return &proto.IncrementResponse{Value: value}, nil
+}
+
+// Undelete restores the value the key had when it was deleted, for stores
+// that keep deleted values.
+func (s *GRPCServer) Undelete(ctx context.Context, req *proto.UndeleteRequest) (*proto.UndeleteResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	if err := softdelete.Undelete(ctx, s.store, inNamespace(req.Namespace, req.Key)); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.UndeleteResponse{}, nil
+}
+
+// BatchPut stores all the requested key-value pairs in one store operation.
+// When a key is repeated, its last value is stored and a warning is returned.
+func (s *GRPCServer) BatchPut(ctx context.Context, req *proto.BatchPutRequest) (*proto.BatchPutResponse, error) {
+	if err := checkBatchSize(len(req.Items)); err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	ctx, collected := warnings.NewContext(ctx)
+	pairs := make(map[string][]byte, len(req.Items))
+	for _, item := range req.Items {
+		key := inNamespace(req.Namespace, item.Key)
+		if _, repeated := pairs[key]; repeated {
+			warnings.Add(ctx, warnings.Warning{Code: warnings.CodeDuplicateKey, Key: item.Key, Message: "key is repeated in the batch; its last value was stored"})
+		}
+		pairs[key] = item.Value
+	}
+	if err := store.BatchPutContext(ctx, s.store, pairs); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.BatchPutResponse{Warnings: protoWarnings(collected)}, nil
+}
+
+// BatchGet retrieves the values of the requested keys in one store operation,
+// listing the keys that do not exist.
+func (s *GRPCServer) BatchGet(ctx context.Context, req *proto.BatchGetRequest) (*proto.BatchGetResponse, error) {
+	if err := checkBatchSize(len(req.Keys)); err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(req.Keys))
+	for i, key := range req.Keys {
+		keys[i] = inNamespace(req.Namespace, key)
+	}
+	values, err := store.BatchGet(s.store, keys)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	resp := &proto.BatchGetResponse{Items: make([]*proto.KeyValue, 0, len(values))}
+	reported := make(map[string]bool, len(keys)) // report duplicated keys once
+	for i, key := range keys {
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		if value, found := values[key]; found {
+			resp.Items = append(resp.Items, &proto.KeyValue{Key: req.Keys[i], Value: value})
+		} else {
+			resp.Missing = append(resp.Missing, req.Keys[i])
+		}
+	}
+	return resp, nil
+}
+
+// BatchDelete removes the requested keys in one store operation, warning
+// about keys that are repeated.
+func (s *GRPCServer) BatchDelete(ctx context.Context, req *proto.BatchDeleteRequest) (*proto.BatchDeleteResponse, error) {
+	if err := checkBatchSize(len(req.Keys)); err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	ctx, collected := warnings.NewContext(ctx)
+	keys := make([]string, len(req.Keys))
+	seen := make(map[string]bool, len(req.Keys))
+	for i, key := range req.Keys {
+		if seen[key] {
+			warnings.Add(ctx, warnings.Warning{Code: warnings.CodeDuplicateKey, Key: key, Message: "key is repeated in the batch"})
+		}
+		seen[key] = true
+		keys[i] = inNamespace(req.Namespace, key)
+	}
+	if err := store.BatchDelete(s.store, keys); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.BatchDeleteResponse{Warnings: protoWarnings(collected)}, nil
+}
+
+// scanVersion resolves the point in time a scan asks for to a store version,
+// returning 0 for scans of the latest data.
+func (s *GRPCServer) scanVersion(req *proto.ScanRequest) (uint64, error) {
+	switch {
+	case req.AsOfVersion != 0 && req.AsOfUnixNano != 0:
+		return 0, status.Error(codes.InvalidArgument, "as_of_version and as_of_unix_nano are exclusive")
+	case req.AsOfVersion != 0:
+		return req.AsOfVersion, nil
+	case req.AsOfUnixNano != 0:
+		version, err := store.VersionAt(s.store, time.Unix(0, req.AsOfUnixNano))
+		if err != nil {
+			return 0, convertError(err)
+		}
+		return version, nil
+	}
+	return 0, nil
+}
+
+// scanIterator returns an iterator over the prefix of req at version, or over
+// the latest data for version 0, without the reserved keys. A scan of the
+// latest data seeks past the cursor key, and reads no values if keys-only.
+func (s *GRPCServer) scanIterator(req *proto.ScanRequest, after string, version uint64) (it store.Iterator, err error) {
+	if version != 0 {
+		it, err = store.NewIteratorAt(s.store, req.Prefix, version)
+	} else {
+		r := store.PrefixRange(req.Prefix)
+		r.KeysOnly = req.KeysOnly
+		if after >= r.Start {
+			r.Start = after + "\x00"
+		}
+		it, err = store.NewRangeIterator(s.store, r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store.WithoutReserved(it), nil
+}
+
+// listRange returns up to limit pairs of s in r, in the order r selects,
+// without the reserved keys.
+func listRange(s store.Store, r store.KeyRange, limit int) (pairs []store.KeyValue, err error) {
+	it, err := store.NewRangeIterator(s, r)
+	if err != nil {
+		return nil, err
+	}
+	err = store.VisitIterator(store.WithoutReserved(it), func(key string, value []byte) error {
+		pairs = append(pairs, store.KeyValue{Key: key, Value: value})
+		if len(pairs) == limit {
+			return store.ErrStopIteration
+		}
+		return nil
+	})
+	return pairs, err
+}
+
+// checkBatchSize rejects batches larger than MaxBatchSize.
+func checkBatchSize(n int) error {
+	if n > MaxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "batch of %d operations exceeds the limit of %d", n, MaxBatchSize)
+	}
+	return nil
+}
+
+// scanLimit validates a requested page size, applying the default when unset.
+func scanLimit(requested int32) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, status.Error(codes.InvalidArgument, "limit cannot be negative")
+	case requested == 0:
+		return DefaultScanLimit, nil
+	case requested > MaxScanLimit:
+		return MaxScanLimit, nil
+	default:
+		return int(requested), nil
+	}
 }
 
 // Start initializes the gRPC server and starts listening for incoming connections.
-// It also registers the server and sets up a shutdown handler.
+// It blocks serving requests until Shutdown is called.
 // If any callbacks are provided, they will be executed after the server starts.
 // The first callback, if provided, is executed immediately after the server starts.
 func (s *GRPCServer) Start(callbacks ...func()) error {
@@ -79,13 +636,15 @@ func (s *GRPCServer) Start(callbacks ...func()) error {
 	}
 	defer func() {
 		if err := listener.Close(); err != nil {
-			log.Printf("Failed to close listener: %v", err)
+			s.logger().Warn("Failed to close listener", "error", err)
 		}
 	}()
 
 	s.register()
+	s.checkHealth()
+	go s.probeHealth()
 
-	go s.Shutdown()
+	s.publish(events.Event{Type: events.TypeServerStarted, Attributes: map[string]string{"addr": listener.Addr().String()}})
 
 	if len(callbacks) > 0 && callbacks[0] != nil {
 		callbacks[0]()
@@ -108,45 +667,135 @@ func (s *GRPCServer) listen(port string) (net.Listener, error) {
 
 func (s *GRPCServer) register() {
 	proto.RegisterClavisServer(s.server, s)
+	healthpb.RegisterHealthServer(s.server, s.healthServer())
 }
 
-// Shutdown gracefully stops the gRPC server when a termination signal is received.
-// It listens for SIGINT and SIGTERM signals and stops the server gracefully.
-func (s *GRPCServer) Shutdown() {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
-	log.Println("Shutting down server...") // TODO: Use a logger instead of fmt
-	s.server.GracefulStop()
+// logger returns the configured logger.
+func (s *GRPCServer) logger() *slog.Logger {
+	if s.config == nil {
+		return slog.Default()
+	}
+	return logging.Or(s.config.Logger)
 }
 
-// GetStore returns the store associated with the gRPC server.
-func (s *GRPCServer) GetStore() (store.Store, error) {
-	return s.store, nil
+// spillFiles returns the spills of the server, creating them on first use.
+func (s *GRPCServer) spillFiles() *spills {
+	s.spillsOnce.Do(func() {
+		var dir string
+		var ttl time.Duration
+		if s.config != nil {
+			dir, ttl = s.config.SpillDir, s.config.SpillTTL
+		}
+		s.spills = newSpills(dir, ttl)
+	})
+	return s.spills
 }
 
-// convertError converts Go errors to gRPC status errors
-func convertError(err error) error {
-	if err == nil {
-		return nil
+// healthServer returns the health service, creating it on first use.
+func (s *GRPCServer) healthServer() *health.Server {
+	s.healthOnce.Do(func() {
+		s.health = health.NewServer()
+		s.stopHealth = make(chan struct{})
+	})
+	return s.health
+}
+
+// checkHealth reports the server as serving while its store can serve
+// requests, and as serving in full while it does not shed load.
+func (s *GRPCServer) checkHealth() {
+	status := healthpb.HealthCheckResponse_SERVING
+	if pinger, ok := store.As[store.Pinger](s.store); ok {
+		if err := pinger.Ping(); err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	s.healthServer().SetServingStatus("", status)
+	s.healthServer().SetServingStatus(proto.Clavis_ServiceDesc.ServiceName, status)
+
+	if s.config != nil && s.config.LoadShedder != nil {
+		if shedding, _ := s.config.LoadShedder.Check(); shedding {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		s.healthServer().SetServingStatus(FullServiceHealthName, status)
+	}
+}
+
+// probeHealth re-checks the store periodically until Shutdown.
+func (s *GRPCServer) probeHealth() {
+	interval := DefaultHealthCheckInterval
+	if s.config != nil && s.config.HealthCheckInterval > 0 {
+		interval = s.config.HealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopHealth:
+			return
+		case <-ticker.C:
+			s.checkHealth()
+		}
+	}
+}
+
+// Shutdown stops the gRPC server and closes the store. In-flight requests are
+// given until the drain timeout or the end of ctx, whichever comes first, to
+// finish; after that remaining connections are closed forcibly and an error
+// is returned.
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.publish(events.Event{Type: events.TypeServerStopping})
+
+	// Report NOT_SERVING from now on so probes take the server out of rotation while it drains
+	s.healthServer().Shutdown()
+	s.stopOnce.Do(func() { close(s.stopHealth) })
+
+	timeout := DefaultDrainTimeout
+	if s.config != nil && s.config.DrainTimeout > 0 {
+		timeout = s.config.DrainTimeout
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	errMsg := err.Error()
+	var drainErr error
+	if s.server != nil {
+		drained := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(drained)
+		}()
+
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			// Stop closes every connection; handlers that ignore cancellation
+			// keep running, but no longer hold up the shutdown
+			s.server.Stop()
+			drainErr = fmt.Errorf("in-flight requests did not drain: %w", ctx.Err())
+		}
+	}
+	if err := s.spillFiles().removeAll(); err != nil {
+		s.logger().Warn("Failed to remove scan spills", "error", err)
+	}
 
-	// Convert validation errors to InvalidArgument
-	if strings.Contains(errMsg, "key cannot be empty") ||
-		strings.Contains(errMsg, "key too long") ||
-		strings.Contains(errMsg, "value too large") {
-		return status.Error(codes.InvalidArgument, errMsg)
+	if s.store != nil {
+		if err := s.store.Close(); err != nil {
+			return errors.Join(drainErr, fmt.Errorf("failed to close store: %w", err))
+		}
 	}
+	return drainErr
+}
 
-	// Convert other known errors
-	if strings.Contains(errMsg, "not found") {
-		return status.Error(codes.NotFound, errMsg)
+// publish sends e on the configured event bus, if any.
+func (s *GRPCServer) publish(e events.Event) {
+	if s.config != nil && s.config.Events != nil {
+		s.config.Events.Publish(e)
 	}
+}
 
-	// Default to Unknown for unrecognized errors
-	return status.Error(codes.Unknown, errMsg)
+// GetStore returns the store associated with the gRPC server.
+func (s *GRPCServer) GetStore() (store.Store, error) {
+	return s.store, nil
 }
 
 var _ server.Server = (*GRPCServer)(nil)
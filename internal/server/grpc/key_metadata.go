@@ -0,0 +1,94 @@
+package proto
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/keymeta"
+)
+
+// GetMetadata returns when the key was created and last updated, and how
+// many times it was written, for stores that track key metadata.
+func (s *GRPCServer) GetMetadata(ctx context.Context, req *proto.GetMetadataRequest) (*proto.GetMetadataResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	tracker, err := keymeta.From(s.store)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	key := inNamespace(req.Namespace, req.Key)
+	meta, found, err := tracker.Metadata(key, 0)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if found {
+		// The stores above the tracker may hide the key, as soft deletes do
+		if found, err = store.Exists(s.store, key); err != nil {
+			return nil, convertError(err)
+		}
+	}
+	if !found {
+		return &proto.GetMetadataResponse{}, nil
+	}
+	return &proto.GetMetadataResponse{Found: true, Metadata: protoMetadata(meta)}, nil
+}
+
+// scanMetadata returns the metadata tracker of the store for scans requesting
+// metadata, and nil for other scans.
+func (s *GRPCServer) scanMetadata(req *proto.ScanRequest) (*keymeta.Store, error) {
+	if !req.WithMetadata {
+		return nil, nil
+	}
+	tracker, err := keymeta.From(s.store)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return tracker, nil
+}
+
+// annotate sets the metadata of items as of version, the latest one if zero.
+// Items must still carry their keys as stored; a nil tracker does nothing.
+func annotate(tracker *keymeta.Store, items []*proto.KeyValue, version uint64) error {
+	if tracker == nil || len(items) == 0 {
+		return nil
+	}
+	if version == 0 {
+		keys := make([]string, len(items))
+		for i, item := range items {
+			keys[i] = item.Key
+		}
+		metas, err := tracker.BatchMetadata(keys)
+		if err != nil {
+			return convertError(err)
+		}
+		for _, item := range items {
+			if meta, ok := metas[item.Key]; ok {
+				item.Metadata = protoMetadata(meta)
+			}
+		}
+		return nil
+	}
+	for _, item := range items {
+		meta, found, err := tracker.Metadata(item.Key, version)
+		if err != nil {
+			return convertError(err)
+		}
+		if found {
+			item.Metadata = protoMetadata(meta)
+		}
+	}
+	return nil
+}
+
+// protoMetadata converts metadata to its message, leaving the times of
+// untracked keys zero.
+func protoMetadata(meta keymeta.Metadata) *proto.KeyMetadata {
+	msg := &proto.KeyMetadata{UpdateCount: meta.Updates}
+	if !meta.Created.IsZero() {
+		msg.CreatedUnixNano = meta.Created.UnixNano()
+		msg.UpdatedUnixNano = meta.Updated.UnixNano()
+	}
+	return msg
+}
@@ -0,0 +1,102 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// leases returns the manager serving lease requests.
+func (s *GRPCServer) leases() (*lease.Manager, error) {
+	if s.config == nil || s.config.Leases == nil {
+		return nil, status.Error(codes.Unimplemented, "leases are not enabled on this server")
+	}
+	return s.config.Leases, nil
+}
+
+// GrantLease grants a lease keys can be attached to.
+func (s *GRPCServer) GrantLease(ctx context.Context, req *proto.GrantLeaseRequest) (*proto.GrantLeaseResponse, error) {
+	leases, err := s.leases()
+	if err != nil {
+		return nil, err
+	}
+	granted, err := leases.Grant(time.Duration(req.TtlMs) * time.Millisecond)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.GrantLeaseResponse{Id: granted.ID, ExpiresUnixNano: granted.Expires.UnixNano()}, nil
+}
+
+// AttachLease attaches the requested keys to a live lease.
+func (s *GRPCServer) AttachLease(ctx context.Context, req *proto.AttachLeaseRequest) (*proto.AttachLeaseResponse, error) {
+	leases, err := s.leases()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(req.Keys))
+	for i, key := range req.Keys {
+		keys[i] = inNamespace(req.Namespace, key)
+	}
+	if err := leases.Attach(req.Id, keys...); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.AttachLeaseResponse{}, nil
+}
+
+// KeepAlive keeps the leases named on the stream alive, and revokes them
+// when the stream ends, such as when the client goes away.
+func (s *GRPCServer) KeepAlive(stream grpc.BidiStreamingServer[proto.KeepAliveRequest, proto.KeepAliveResponse]) error {
+	leases, err := s.leases()
+	if err != nil {
+		return err
+	}
+	kept := make(map[uint64]bool)
+	defer func() {
+		for id := range kept {
+			if _, err := leases.Revoke(id); err != nil && !errors.Is(err, lease.ErrNotFound) {
+				s.logger().Warn("Failed to revoke lease", "lease", id, "error", err)
+			}
+		}
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		alive, err := leases.KeepAlive(req.Id)
+		if err != nil {
+			return convertError(err)
+		}
+		kept[req.Id] = true
+		if err := stream.Send(&proto.KeepAliveResponse{Id: alive.ID, ExpiresUnixNano: alive.Expires.UnixNano()}); err != nil {
+			return err
+		}
+	}
+}
+
+// RevokeLease ends a lease, deleting the keys attached to it.
+func (s *GRPCServer) RevokeLease(ctx context.Context, req *proto.RevokeLeaseRequest) (*proto.RevokeLeaseResponse, error) {
+	leases, err := s.leases()
+	if err != nil {
+		return nil, err
+	}
+	n, err := leases.Revoke(req.Id)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.RevokeLeaseResponse{AttachedKeys: int64(n)}, nil
+}
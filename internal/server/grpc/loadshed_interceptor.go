@@ -0,0 +1,273 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Metric names recorded by the load shedder.
+const (
+	MetricLoadShedding = "rpc_load_shedding" // 1 while non-essential calls are rejected, 0 otherwise
+	MetricRPCShed      = "rpc_shed_total"    // Calls rejected while shedding load
+)
+
+// FullServiceHealthName is the health service reported NOT_SERVING while the
+// server sheds load. The server as a whole keeps reporting SERVING, since
+// small Gets and Puts still succeed; clients needing every operation probe
+// this name instead.
+const FullServiceHealthName = "clavis.v1.Clavis/full"
+
+// Defaults of the fields of a LoadShedConfig left zero.
+const (
+	DefaultLoadShedWindow      = 10 * time.Second
+	DefaultLoadShedMinCalls    = 20
+	DefaultLoadShedMaxPutBytes = 64 << 10
+)
+
+// DefaultSheddableMethods are the calls rejected while shedding load: those
+// reading many keys at once.
+var DefaultSheddableMethods = []string{
+	proto.Clavis_Scan_FullMethodName,
+	proto.Clavis_ScanStream_FullMethodName,
+	proto.Clavis_Range_FullMethodName,
+	proto.Clavis_Count_FullMethodName,
+	proto.Clavis_Diff_FullMethodName,
+}
+
+// LoadShedConfig configures when the server sheds load. It sheds while the
+// share of calls failing with a store error over the last window reaches
+// ErrorRate, or while the store uses MaxDiskBytes of disk or more; either is
+// not watched if zero. Shedding rejects the Sheddable methods and Puts of
+// more than MaxPutBytes with UNAVAILABLE, keeping small Gets and Puts served.
+type LoadShedConfig struct {
+	ErrorRate    float64       // Share of failed calls from 0 to 1
+	Window       time.Duration // Span the error rate is measured over; DefaultLoadShedWindow if zero
+	MinCalls     int           // Calls a window needs for its error rate to count; DefaultLoadShedMinCalls if zero
+	MaxDiskBytes int64         // Disk usage of the store, checked with the health of the server
+
+	MaxPutBytes int      // Size of the values a Put or BatchPut may write while shedding; DefaultLoadShedMaxPutBytes if zero
+	Sheddable   []string // Full method names rejected while shedding; DefaultSheddableMethods if nil
+	Exempt      []string // Full method names neither shed nor counted in the error rate
+
+	Logger *slog.Logger // Receives a record when shedding starts or stops; slog.Default() if nil
+}
+
+// LoadShedder tracks the health of the store and rejects non-essential calls
+// while it is degraded.
+type LoadShedder struct {
+	config    LoadShedConfig
+	store     store.Store
+	registry  *metrics.Registry
+	sheddable map[string]bool
+	exempt    map[string]bool
+	now       func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	calls       int
+	failures    int
+	errorRate   float64 // Of the last full window, or 0 if it had too few calls
+	diskUsage   int64   // At the last Check
+	reason      string  // Why load is being shed; empty if it is not
+}
+
+// NewLoadShedder creates a shedder watching the health of s and recording its
+// state in registry. It returns nil, which sheds nothing, if config sets no
+// threshold.
+func NewLoadShedder(config LoadShedConfig, s store.Store, registry *metrics.Registry) *LoadShedder {
+	if config.ErrorRate <= 0 && config.MaxDiskBytes <= 0 {
+		return nil
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultLoadShedWindow
+	}
+	if config.MinCalls <= 0 {
+		config.MinCalls = DefaultLoadShedMinCalls
+	}
+	if config.MaxPutBytes <= 0 {
+		config.MaxPutBytes = DefaultLoadShedMaxPutBytes
+	}
+	if config.Sheddable == nil {
+		config.Sheddable = DefaultSheddableMethods
+	}
+	l := &LoadShedder{
+		config:      config,
+		store:       s,
+		registry:    registry,
+		sheddable:   make(map[string]bool, len(config.Sheddable)),
+		exempt:      make(map[string]bool, len(config.Exempt)),
+		now:         time.Now,
+		windowStart: time.Now(),
+	}
+	for _, method := range config.Sheddable {
+		l.sheddable[method] = true
+	}
+	for _, method := range config.Exempt {
+		l.exempt[method] = true
+	}
+	registry.Gauge(MetricLoadShedding).Set(0)
+	return l
+}
+
+// Check measures the disk usage of the store and re-evaluates whether load is
+// shed, reporting the reason if it is.
+func (l *LoadShedder) Check() (shedding bool, reason string) {
+	if l == nil {
+		return false, ""
+	}
+	var usage int64
+	if l.config.MaxDiskBytes > 0 {
+		if maintainer, ok := store.As[store.Maintainer](l.store); ok {
+			usage = maintainer.DiskUsage()
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.diskUsage = usage
+	l.rotate()
+	l.update()
+	return l.reason != "", l.reason
+}
+
+// Shedding reports whether load is being shed, and why.
+func (l *LoadShedder) Shedding() (shedding bool, reason string) {
+	if l == nil {
+		return false, ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.reason != "", l.reason
+}
+
+// observe counts the outcome of a call towards the error rate.
+func (l *LoadShedder) observe(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rotate()
+	l.calls++
+	if storeFailure(err) {
+		l.failures++
+	}
+}
+
+// rotate closes the current window once it has lasted Window, keeping its
+// error rate. Callers hold mu.
+func (l *LoadShedder) rotate() {
+	now := l.now()
+	if now.Sub(l.windowStart) < l.config.Window {
+		return
+	}
+	l.errorRate = 0
+	// A window followed by one without calls is stale
+	if l.calls >= l.config.MinCalls && now.Sub(l.windowStart) < 2*l.config.Window {
+		l.errorRate = float64(l.failures) / float64(l.calls)
+	}
+	l.windowStart, l.calls, l.failures = now, 0, 0
+	l.update()
+}
+
+// update derives the reason to shed load from the last measurements, logging
+// and recording changes. Callers hold mu.
+func (l *LoadShedder) update() {
+	var reason string
+	switch {
+	case l.config.MaxDiskBytes > 0 && l.diskUsage >= l.config.MaxDiskBytes:
+		reason = fmt.Sprintf("store disk usage of %d bytes reached the limit of %d", l.diskUsage, l.config.MaxDiskBytes)
+	case l.config.ErrorRate > 0 && l.errorRate >= l.config.ErrorRate:
+		reason = fmt.Sprintf("%.0f%% of calls failed with store errors", l.errorRate*100)
+	}
+	if (reason != "") == (l.reason != "") {
+		l.reason = reason
+		return
+	}
+	l.reason = reason
+	if reason != "" {
+		l.registry.Gauge(MetricLoadShedding).Set(1)
+		logging.Or(l.config.Logger).Warn("Shedding load", "reason", reason)
+	} else {
+		l.registry.Gauge(MetricLoadShedding).Set(0)
+		logging.Or(l.config.Logger).Info("Stopped shedding load")
+	}
+}
+
+// storeFailure reports whether err is a failure of the store rather than of
+// the request.
+func storeFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// shed returns the UNAVAILABLE error of a call of method with req rejected
+// while load is shed, or nil if it may run.
+func (l *LoadShedder) shed(method string, req any) error {
+	shedding, reason := l.Shedding()
+	if !shedding || !l.sheddable[method] && putBytes(req) <= l.config.MaxPutBytes {
+		return nil
+	}
+	l.registry.Counter(MetricRPCShed).Inc()
+	l.registry.Counter(fmt.Sprintf("%s{method=%q}", MetricRPCShed, method)).Inc()
+	return claviserrors.New(claviserrors.CodeOverloaded, "server shedding load: "+reason).GRPCStatus().Err()
+}
+
+// putBytes returns the size of the values written by a Put or BatchPut, or 0
+// for other requests.
+func putBytes(req any) int {
+	switch r := req.(type) {
+	case *proto.PutRequest:
+		return len(r.GetValue())
+	case *proto.BatchPutRequest:
+		n := 0
+		for _, item := range r.GetItems() {
+			n += len(item.GetValue())
+		}
+		return n
+	}
+	return 0
+}
+
+// UnaryInterceptor rejects non-essential calls while load is shed and counts
+// the outcome of the others.
+func (l *LoadShedder) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if l.exempt[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		if err := l.shed(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		l.observe(err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor rejects non-essential streams while load is shed and
+// counts the outcome of the others.
+func (l *LoadShedder) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l.exempt[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		if err := l.shed(info.FullMethod, nil); err != nil {
+			return err
+		}
+		err := handler(srv, ss)
+		l.observe(err)
+		return err
+	}
+}
@@ -0,0 +1,144 @@
+package proto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// diskStore reports a set disk usage.
+type diskStore struct {
+	store.Store
+	usage int64
+}
+
+func (s *diskStore) DiskUsage() int64           { return s.usage }
+func (s *diskStore) RunGC(float64) (int, error) { return 0, nil }
+func (s *diskStore) Flush() error               { return nil }
+
+func TestLoadShedder_ErrorRate(t *testing.T) {
+	registry := metrics.NewRegistry()
+	shedder := NewLoadShedder(LoadShedConfig{ErrorRate: 0.5, Window: time.Minute, MinCalls: 4}, newMockStore(), registry)
+	now := time.Now()
+	shedder.now = func() time.Time { return now }
+	interceptor := shedder.UnaryInterceptor()
+	call := func(method string, req any, err error) error {
+		_, got := interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(context.Context, any) (any, error) { return nil, err })
+		return got
+	}
+	failure := status.Error(codes.Internal, "disk I/O error")
+
+	// Three failures out of four trip the shedder once their window closes
+	for _, err := range []error{failure, failure, failure, nil} {
+		_ = call(proto.Clavis_Get_FullMethodName, &proto.GetRequest{}, err)
+	}
+	if shedding, _ := shedder.Shedding(); shedding {
+		t.Fatal("Expected no shedding before the window closes")
+	}
+	now = now.Add(time.Minute)
+	if shedding, reason := shedder.Check(); !shedding || reason == "" {
+		t.Fatalf("Expected shedding with a 75%% error rate, got %v", reason)
+	}
+	if registry.Gauge(MetricLoadShedding).Value() != 1 {
+		t.Errorf("Expected %s to be 1 while shedding", MetricLoadShedding)
+	}
+
+	tests := []struct {
+		method string
+		req    any
+		shed   bool
+	}{
+		{proto.Clavis_Scan_FullMethodName, &proto.ScanRequest{}, true},
+		{proto.Clavis_Put_FullMethodName, &proto.PutRequest{Value: bytes.Repeat([]byte("x"), DefaultLoadShedMaxPutBytes+1)}, true},
+		{proto.Clavis_BatchPut_FullMethodName, &proto.BatchPutRequest{Items: []*proto.KeyValue{
+			{Value: make([]byte, DefaultLoadShedMaxPutBytes/2)}, {Value: make([]byte, DefaultLoadShedMaxPutBytes/2+1)},
+		}}, true},
+		{proto.Clavis_Put_FullMethodName, &proto.PutRequest{Value: []byte("small")}, false},
+		{proto.Clavis_Get_FullMethodName, &proto.GetRequest{}, false},
+	}
+	for _, tt := range tests {
+		err := call(tt.method, tt.req, nil)
+		if shed := status.Code(err) == codes.Unavailable && claviserrors.CodeOf(err) == claviserrors.CodeOverloaded; shed != tt.shed {
+			t.Errorf("%s shed = %v, want %v (err %v)", tt.method, shed, tt.shed, err)
+		}
+	}
+	if n := registry.Counter(MetricRPCShed).Value(); n != 3 {
+		t.Errorf("%s = %d, want 3", MetricRPCShed, n)
+	}
+
+	// A window of successes ends the shedding
+	for range 4 {
+		_ = call(proto.Clavis_Get_FullMethodName, &proto.GetRequest{}, nil)
+	}
+	now = now.Add(time.Minute)
+	if shedding, reason := shedder.Check(); shedding {
+		t.Errorf("Expected shedding to stop after a healthy window, got %v", reason)
+	}
+	if err := call(proto.Clavis_Scan_FullMethodName, &proto.ScanRequest{}, nil); err != nil {
+		t.Errorf("Expected scans to be served again, got %v", err)
+	}
+}
+
+func TestLoadShedder_MinCalls(t *testing.T) {
+	shedder := NewLoadShedder(LoadShedConfig{ErrorRate: 0.1, Window: time.Minute}, newMockStore(), metrics.NewRegistry())
+	now := time.Now()
+	shedder.now = func() time.Time { return now }
+	stream := shedder.StreamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: proto.Clavis_ScanStream_FullMethodName}
+	ss := &recvStream{ctx: context.Background()}
+
+	// Too few calls to judge the store by
+	_ = stream(nil, ss, info, func(any, grpc.ServerStream) error { return errors.New("unknown failure") })
+	now = now.Add(time.Minute)
+	if shedding, reason := shedder.Check(); shedding {
+		t.Errorf("Expected a lone failure not to trip the shedder, got %v", reason)
+	}
+	if NewLoadShedder(LoadShedConfig{}, newMockStore(), metrics.NewRegistry()) != nil {
+		t.Error("Expected no shedder without thresholds")
+	}
+}
+
+func TestLoadShedder_DiskPressure(t *testing.T) {
+	disk := &diskStore{Store: newMockStore(), usage: 100}
+	shedder := NewLoadShedder(LoadShedConfig{MaxDiskBytes: 1000}, disk, metrics.NewRegistry())
+	s := &GRPCServer{store: disk, config: &GRPCServerConfig{LoadShedder: shedder}}
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := s.healthServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Status
+	}
+
+	s.checkHealth()
+	if got := check(FullServiceHealthName); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected full service with room on disk, got %v", got)
+	}
+
+	disk.usage = 1000
+	s.checkHealth()
+	if got := check(FullServiceHealthName); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING for the full service with the disk full, got %v", got)
+	}
+	if got := check(""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected the server to keep serving while shedding load, got %v", got)
+	}
+	_, err := shedder.UnaryInterceptor()(context.Background(), &proto.ScanRequest{}, &grpc.UnaryServerInfo{FullMethod: proto.Clavis_Scan_FullMethodName},
+		func(context.Context, any) (any, error) { return nil, nil })
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected scans to be rejected with the disk full, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// locker returns the locker serving lease requests.
+func (s *GRPCServer) locker() (*lock.Locker, error) {
+	if s.config == nil || s.config.Locker == nil {
+		return nil, status.Error(codes.Unimplemented, "locks are not enabled on this server")
+	}
+	return s.config.Locker, nil
+}
+
+// lockResponse returns the response granting lease on the lock named name
+// within its namespace.
+func lockResponse(name string, lease lock.Lease) *proto.LockResponse {
+	return &proto.LockResponse{
+		Fencing:         &proto.FencingToken{Lock: name, Token: lease.Token},
+		ExpiresUnixNano: lease.Expires.UnixNano(),
+	}
+}
+
+// Lock acquires a lease on the named lock for the caller.
+func (s *GRPCServer) Lock(ctx context.Context, req *proto.LockRequest) (*proto.LockResponse, error) {
+	locker, err := s.locker()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	lease, err := locker.Acquire(inNamespace(req.Namespace, req.Name), req.Owner, time.Duration(req.TtlMs)*time.Millisecond)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return lockResponse(req.Name, lease), nil
+}
+
+// RenewLock extends the caller's lease on the named lock.
+func (s *GRPCServer) RenewLock(ctx context.Context, req *proto.RenewLockRequest) (*proto.LockResponse, error) {
+	locker, err := s.locker()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	lease, err := locker.Renew(inNamespace(req.Namespace, req.Name), req.Token, time.Duration(req.TtlMs)*time.Millisecond)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return lockResponse(req.Name, lease), nil
+}
+
+// Unlock releases the caller's lease on the named lock.
+func (s *GRPCServer) Unlock(ctx context.Context, req *proto.UnlockRequest) (*proto.UnlockResponse, error) {
+	locker, err := s.locker()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	if err := locker.Release(inNamespace(req.Namespace, req.Name), req.Token); err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.UnlockResponse{}, nil
+}
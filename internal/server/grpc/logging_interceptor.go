@@ -0,0 +1,113 @@
+package proto
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// serverFaults are the status codes logged as errors; other failures are
+// usually the caller's and are logged as warnings.
+var serverFaults = map[codes.Code]bool{
+	codes.Unknown:     true,
+	codes.Internal:    true,
+	codes.DataLoss:    true,
+	codes.Unavailable: true,
+}
+
+// requestLogger annotates logger with the method and peer of the call in ctx.
+func requestLogger(ctx context.Context, logger *slog.Logger, method string) *slog.Logger {
+	logger = logger.With("method", method)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		logger = logger.With("peer", p.Addr.String())
+	}
+	return logger
+}
+
+// requestKey returns the key or prefix a request targets, if any.
+func requestKey(req any) (string, bool) {
+	switch r := req.(type) {
+	case interface{ GetKey() string }:
+		return r.GetKey(), true
+	case interface{ GetPrefix() string }:
+		return r.GetPrefix(), true
+	}
+	return "", false
+}
+
+// logRPC records the outcome of a call.
+func logRPC(ctx context.Context, logger *slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch {
+	case serverFaults[code]:
+		level = slog.LevelError
+	case err != nil:
+		level = slog.LevelWarn
+	}
+	attrs := []any{"latency", time.Since(start), "code", code.String()}
+	if err != nil {
+		attrs = append(attrs, "error", status.Convert(err).Message())
+	}
+	logger.Log(ctx, level, "rpc finished", attrs...)
+}
+
+// LoggingUnaryInterceptor logs every unary call with its method, key, peer,
+// latency and status code. Handlers find a logger carrying the same fields
+// with logging.FromContext.
+func LoggingUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	logger = logging.Or(logger)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		reqLogger := requestLogger(ctx, logger, info.FullMethod)
+		if key, ok := requestKey(req); ok {
+			reqLogger = reqLogger.With("key", key)
+		}
+		resp, err := handler(logging.WithLogger(ctx, reqLogger), req)
+		logRPC(ctx, reqLogger, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor logs every streaming call like
+// LoggingUnaryInterceptor. The key is taken from the first request received
+// and only appears on the final record.
+func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	logger = logging.Or(logger)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+		reqLogger := requestLogger(ctx, logger, info.FullMethod)
+		stream := &loggingStream{contextStream: contextStream{ServerStream: ss, ctx: logging.WithLogger(ctx, reqLogger)}}
+		err := handler(srv, stream)
+		if stream.keyed {
+			reqLogger = reqLogger.With("key", stream.key)
+		}
+		logRPC(ctx, reqLogger, start, err)
+		return err
+	}
+}
+
+// loggingStream remembers the key of the first request received.
+type loggingStream struct {
+	contextStream
+	key   string
+	keyed bool
+}
+
+// RecvMsg receives a request and records its key.
+func (s *loggingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.keyed {
+		s.key, s.keyed = requestKey(m)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package proto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// decodeRecords parses JSON log records, one per line.
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLoggingInterceptors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	unary := LoggingUnaryInterceptor(logger)
+	stream := LoggingStreamInterceptor(logger)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4000}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/clavis.v1.Clavis/Get"}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		logging.FromContext(ctx).Info("inside handler")
+		return nil, status.Error(codes.NotFound, "missing")
+	}
+	if _, err := unary(ctx, &proto.GetRequest{Key: "users/1"}, info, handler); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected the handler error to pass through, got %v", err)
+	}
+	internal := func(context.Context, any) (any, error) { return nil, status.Error(codes.Internal, "boom") }
+	if _, err := unary(ctx, &proto.PutRequest{Key: "users/2"}, info, internal); status.Code(err) != codes.Internal {
+		t.Fatalf("Expected the handler error to pass through, got %v", err)
+	}
+	err := stream(nil, &recvStream{ctx: ctx, req: &proto.ScanRequest{Prefix: "users/"}}, &grpc.StreamServerInfo{FullMethod: "/clavis.v1.Clavis/ScanStream"},
+		func(_ any, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&proto.ScanRequest{})
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d: %v", len(records), records)
+	}
+	want := []struct{ msg, level, key, code string }{
+		{"inside handler", "INFO", "users/1", ""},
+		{"rpc finished", "WARN", "users/1", "NotFound"},
+		{"rpc finished", "ERROR", "users/2", "Internal"},
+		{"rpc finished", "INFO", "users/", "OK"},
+	}
+	for i, w := range want {
+		r := records[i]
+		if r["msg"] != w.msg || r["level"] != w.level || r["key"] != w.key || r["peer"] != "10.0.0.1:4000" {
+			t.Errorf("Record %d = %v, want msg %q level %s key %q from the peer", i, r, w.msg, w.level, w.key)
+		}
+		if w.code != "" {
+			if r["code"] != w.code || r["latency"] == nil {
+				t.Errorf("Record %d = %v, want code %s and a latency", i, r, w.code)
+			}
+		}
+	}
+	if records[3]["method"] != "/clavis.v1.Clavis/ScanStream" {
+		t.Errorf("Expected the stream method, got %v", records[3]["method"])
+	}
+}
@@ -0,0 +1,63 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metric names recorded by the metrics interceptors. Per-method series are
+// suffixed with the method and, for errors, the status code.
+const (
+	MetricRPCRequests = "rpc_requests_total"
+	MetricRPCErrors   = "rpc_errors_total"
+	MetricRPCInFlight = "rpc_in_flight"
+	MetricRPCLatency  = "rpc_latency_seconds"
+)
+
+// rpcMetrics records one call in registry and returns a function that
+// completes the record with the call's outcome.
+func rpcMetrics(registry *metrics.Registry, method string) func(err error) {
+	start := time.Now()
+	registry.Counter(MetricRPCRequests).Inc()
+	registry.Counter(fmt.Sprintf("%s{method=%q}", MetricRPCRequests, method)).Inc()
+	inFlight := registry.Gauge(MetricRPCInFlight)
+	inFlight.Add(1)
+
+	return func(err error) {
+		inFlight.Add(-1)
+		elapsed := time.Since(start).Seconds()
+		registry.Histogram(MetricRPCLatency, metrics.DefaultLatencyBounds).Observe(elapsed)
+		registry.Histogram(fmt.Sprintf("%s{method=%q}", MetricRPCLatency, method), metrics.DefaultLatencyBounds).Observe(elapsed)
+		if err != nil {
+			registry.Counter(MetricRPCErrors).Inc()
+			registry.Counter(fmt.Sprintf("%s{method=%q,code=%q}", MetricRPCErrors, method, status.Code(err))).Inc()
+		}
+	}
+}
+
+// MetricsUnaryInterceptor records request counts, errors and latency of
+// unary calls in registry.
+func MetricsUnaryInterceptor(registry *metrics.Registry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := rpcMetrics(registry, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records request counts, errors and duration of
+// streaming calls in registry.
+func MetricsStreamInterceptor(registry *metrics.Registry) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := rpcMetrics(registry, info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+		return err
+	}
+}
@@ -0,0 +1,54 @@
+package proto
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsInterceptors(t *testing.T) {
+	registry := metrics.NewRegistry()
+	unary := MetricsUnaryInterceptor(registry)
+	stream := MetricsStreamInterceptor(registry)
+	info := &grpc.UnaryServerInfo{FullMethod: "/clavis.v1.Clavis/Get"}
+
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+	notFound := func(context.Context, any) (any, error) { return nil, status.Error(codes.NotFound, "missing") }
+
+	if _, err := unary(context.Background(), nil, info, ok); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unary(context.Background(), nil, info, notFound); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected the handler error to pass through, got %v", err)
+	}
+	streamErr := errors.New("broken")
+	err := stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/clavis.v1.Clavis/Watch"}, func(any, grpc.ServerStream) error { return streamErr })
+	if !errors.Is(err, streamErr) {
+		t.Fatalf("Expected the stream error to pass through, got %v", err)
+	}
+
+	s := registry.Snapshot()
+	counters := map[string]uint64{
+		MetricRPCRequests: 3,
+		`rpc_requests_total{method="/clavis.v1.Clavis/Get"}`: 2,
+		MetricRPCErrors: 2,
+		`rpc_errors_total{method="/clavis.v1.Clavis/Get",code="NotFound"}`:  1,
+		`rpc_errors_total{method="/clavis.v1.Clavis/Watch",code="Unknown"}`: 1,
+	}
+	for name, want := range counters {
+		if got := s.Counters[name]; got != want {
+			t.Errorf("Counter %s = %d, want %d", name, got, want)
+		}
+	}
+	if s.Gauges[MetricRPCInFlight] != 0 {
+		t.Errorf("Expected no calls in flight, got %d", s.Gauges[MetricRPCInFlight])
+	}
+	if s.Histograms[MetricRPCLatency].Count != 3 {
+		t.Errorf("Expected 3 latency observations, got %d", s.Histograms[MetricRPCLatency].Count)
+	}
+}
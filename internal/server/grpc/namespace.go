@@ -0,0 +1,99 @@
+package proto
+
+import (
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// NamespaceRegistry tells which namespaces have been created.
+// settings.Manager implements it.
+type NamespaceRegistry interface {
+	Exists(namespace string) (bool, error)
+}
+
+// inNamespace returns the stored form of a key or prefix of namespace. Keys
+// outside any namespace are stored as they are.
+func inNamespace(namespace, key string) string {
+	if namespace == "" {
+		return key
+	}
+	return namespace + settings.NamespaceSeparator + key
+}
+
+// outOfNamespace returns stored keys relative to namespace, in place.
+func outOfNamespace(namespace string, items []*proto.KeyValue) {
+	if namespace == "" {
+		return
+	}
+	for _, item := range items {
+		item.Key = strings.TrimPrefix(item.Key, namespace+settings.NamespaceSeparator)
+	}
+}
+
+// checkNamespace rejects invalid namespace names and, when the server has a
+// registry, namespaces that were not created.
+func (s *GRPCServer) checkNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if err := settings.ValidateNamespace(namespace); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if s.config == nil || s.config.Namespaces == nil {
+		return nil
+	}
+	exists, err := s.config.Namespaces.Exists(namespace)
+	if err != nil {
+		return convertError(err)
+	}
+	if !exists {
+		return status.Errorf(codes.NotFound, "namespace %q does not exist", namespace)
+	}
+	return nil
+}
+
+// rangeInNamespace returns the stored keys req ranges over. An unbounded
+// range ends with the last key of the namespace.
+func rangeInNamespace(req *proto.RangeRequest) store.KeyRange {
+	r := store.KeyRange{Start: req.Start, End: req.End, Reverse: req.Reverse, KeysOnly: req.KeysOnly}
+	if req.Namespace == "" {
+		return r
+	}
+	r.Start = inNamespace(req.Namespace, req.Start)
+	if req.End != "" {
+		r.End = inNamespace(req.Namespace, req.End)
+	} else {
+		// The separator is followed by the next byte value
+		r.End = req.Namespace + string(settings.NamespaceSeparator[0]+1)
+	}
+	return r
+}
+
+// rangePrefix returns the longest prefix shared by every key in [start, end).
+func rangePrefix(start, end string) string {
+	if end == "" {
+		return ""
+	}
+	n := 0
+	for n < len(start) && n < len(end) && start[n] == end[n] {
+		n++
+	}
+	return start[:n]
+}
+
+// scanInNamespace returns req with its prefix in its namespace, leaving req
+// itself untouched.
+func scanInNamespace(req *proto.ScanRequest) *proto.ScanRequest {
+	if req.Namespace == "" {
+		return req
+	}
+	qualified := protobuf.Clone(req).(*proto.ScanRequest)
+	qualified.Prefix = inNamespace(req.Namespace, req.Prefix)
+	return qualified
+}
@@ -0,0 +1,72 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_Namespaces(t *testing.T) {
+	mockStore := newMockStore()
+	manager := settings.NewManager(mockStore, nil)
+	defer manager.Close()
+	if _, err := manager.Create("tenant", settings.NamespaceSettings{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	s := &GRPCServer{store: mockStore, config: &GRPCServerConfig{Namespaces: manager}}
+	ctx := context.Background()
+
+	for _, key := range []string{"user/1", "user/2"} {
+		if _, err := s.Put(ctx, &proto.PutRequest{Namespace: "tenant", Key: key, Value: []byte("v")}); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if _, err := s.Put(ctx, &proto.PutRequest{Key: "user/1", Value: []byte("outside")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := mockStore.data["tenant/user/1"]; !found {
+		t.Errorf("Expected the key to be stored under its namespace, got %v", mockStore.data)
+	}
+
+	got, err := s.Get(ctx, &proto.GetRequest{Namespace: "tenant", Key: "user/1"})
+	if err != nil || string(got.Value) != "v" {
+		t.Errorf("Expected the namespaced value, got %v %v", got, err)
+	}
+
+	scan, err := s.Scan(ctx, &proto.ScanRequest{Namespace: "tenant", Prefix: "user/", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scan.Items) != 1 || scan.Items[0].Key != "user/1" || scan.NextCursor == "" {
+		t.Fatalf("Expected user/1 relative to the namespace, got %v", scan)
+	}
+	scan, err = s.Scan(ctx, &proto.ScanRequest{Namespace: "tenant", Prefix: "user/", Cursor: scan.NextCursor})
+	if err != nil || len(scan.Items) != 1 || scan.Items[0].Key != "user/2" {
+		t.Errorf("Expected the cursor to resume within the namespace, got %v %v", scan, err)
+	}
+
+	batch, err := s.BatchGet(ctx, &proto.BatchGetRequest{Namespace: "tenant", Keys: []string{"user/2", "user/3"}})
+	if err != nil || len(batch.Items) != 1 || batch.Items[0].Key != "user/2" {
+		t.Errorf("Expected user/2 relative to the namespace, got %v %v", batch, err)
+	}
+
+	if _, err := s.Delete(ctx, &proto.DeleteRequest{Namespace: "tenant", Key: "user/1"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(mockStore.data["user/1"]) != "outside" {
+		t.Error("Expected the key outside the namespace to be left alone")
+	}
+
+	_, err = s.Get(ctx, &proto.GetRequest{Namespace: "missing", Key: "k"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a namespace that was not created, got %v", err)
+	}
+	_, err = s.Put(ctx, &proto.PutRequest{Namespace: "a/b", Key: "k"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid namespace, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/ratelimit"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RetryAfterMetadataKey is the header telling rate limited callers how many
+// seconds to wait before retrying. The error carries the same hint under the
+// retry_after metadata of its code.
+const RetryAfterMetadataKey = "retry-after"
+
+// RateLimitKey selects what requests are counted by.
+type RateLimitKey string
+
+const (
+	RateLimitByPrincipal RateLimitKey = "principal" // The authenticated principal, else the IP address
+	RateLimitByIP        RateLimitKey = "ip"        // The IP address of the caller
+)
+
+// RateLimit is a token bucket admitting RequestsPerSecond requests on average,
+// in bursts of up to Burst. A zero rate is unlimited.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             float64 // Defaults to one second worth of requests
+}
+
+// RateLimitConfig configures the rate limiting interceptors, which must run
+// after the authentication interceptors to count requests by principal.
+// Every caller has a bucket for each method with a limit of its own, and one
+// shared by the other methods.
+type RateLimitConfig struct {
+	Default RateLimit            // Limit of the methods without one of their own
+	Methods map[string]RateLimit // Limits by full method name
+	By      RateLimitKey         // Defaults to RateLimitByPrincipal
+	Exempt  []string             // Full method names that are never limited
+}
+
+// MethodName returns the full name of the Clavis RPC called name, such as
+// "/clavis.v1.Clavis/Put" for "Put". Full names are returned unchanged.
+func MethodName(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return "/" + proto.Clavis_ServiceDesc.ServiceName + "/" + name
+}
+
+// rateLimiter counts the requests of each caller.
+type rateLimiter struct {
+	fallback *ratelimit.Keyed            // Nil if the default limit is unlimited
+	methods  map[string]*ratelimit.Keyed // Nil values are unlimited
+	by       RateLimitKey
+	exempt   map[string]bool
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	keyed := func(limit RateLimit) *ratelimit.Keyed {
+		if limit.RequestsPerSecond <= 0 {
+			return nil
+		}
+		return ratelimit.NewKeyed(limit.RequestsPerSecond, limit.Burst)
+	}
+	r := &rateLimiter{
+		fallback: keyed(config.Default),
+		methods:  make(map[string]*ratelimit.Keyed, len(config.Methods)),
+		by:       config.By,
+		exempt:   make(map[string]bool, len(config.Exempt)),
+	}
+	for method, limit := range config.Methods {
+		r.methods[method] = keyed(limit)
+	}
+	for _, method := range config.Exempt {
+		r.exempt[method] = true
+	}
+	return r
+}
+
+// caller identifies the caller of ctx.
+func (r *rateLimiter) caller(ctx context.Context) string {
+	if r.by != RateLimitByIP {
+		if principal, ok := auth.PrincipalFrom(ctx); ok {
+			return "principal:" + principal.Name
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+	return ""
+}
+
+// limit takes a request of the caller of ctx to method, returning a
+// RATE_LIMITED error and the retry-after header to send if none is left.
+func (r *rateLimiter) limit(ctx context.Context, method string) (metadata.MD, error) {
+	if r.exempt[method] {
+		return nil, nil
+	}
+	limiters, ok := r.methods[method]
+	if !ok {
+		limiters = r.fallback
+	}
+	if limiters == nil {
+		return nil, nil
+	}
+	wait, ok := limiters.Limiter(r.caller(ctx)).Take(1)
+	if ok {
+		return nil, nil
+	}
+
+	retryAfter := strconv.Itoa(int(math.Ceil(wait.Seconds())))
+	err := claviserrors.New(claviserrors.CodeRateLimited, fmt.Sprintf("rate limit of %s exceeded; retry in %v", method, wait.Round(time.Millisecond)))
+	err.Metadata = map[string]string{"retry_after": retryAfter}
+	return metadata.Pairs(RetryAfterMetadataKey, retryAfter), err.GRPCStatus().Err()
+}
+
+// RateLimitUnaryInterceptor rejects unary calls of callers over their rate
+// limit with RESOURCE_EXHAUSTED.
+func RateLimitUnaryInterceptor(config RateLimitConfig) grpc.UnaryServerInterceptor {
+	r := newRateLimiter(config)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if header, err := r.limit(ctx, info.FullMethod); err != nil {
+			// Fails outside a gRPC transport, such as in the HTTP gateway
+			_ = grpc.SetHeader(ctx, header)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor rejects the messages of streaming calls of
+// callers over their rate limit with RESOURCE_EXHAUSTED. Every message
+// received counts as a request.
+func RateLimitStreamInterceptor(config RateLimitConfig) grpc.StreamServerInterceptor {
+	r := newRateLimiter(config)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if r.exempt[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		return handler(srv, &rateLimitedStream{ServerStream: ss, limiter: r, method: info.FullMethod})
+	}
+}
+
+// rateLimitedStream takes a request for every message received on a server
+// stream.
+type rateLimitedStream struct {
+	grpc.ServerStream
+	limiter *rateLimiter
+	method  string
+}
+
+// RecvMsg receives a message and rejects it if the caller has no request
+// left.
+func (s *rateLimitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	header, err := s.limiter.limit(s.Context(), s.method)
+	if err != nil {
+		// Fails once the headers were sent; the error still carries the hint
+		_ = s.SetHeader(header)
+	}
+	return err
+}
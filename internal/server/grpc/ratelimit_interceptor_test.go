@@ -0,0 +1,107 @@
+package proto
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// headerStream is a recvStream recording the headers set on it
+type headerStream struct {
+	recvStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	interceptor := RateLimitUnaryInterceptor(RateLimitConfig{
+		Default: RateLimit{RequestsPerSecond: 1, Burst: 2},
+		Methods: map[string]RateLimit{
+			MethodName("Put"):   {RequestsPerSecond: 0.1, Burst: 1},
+			MethodName("Count"): {},
+		},
+		Exempt: DefaultAuthExemptions,
+	})
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, ok)
+		return err
+	}
+	alice := auth.WithPrincipal(context.Background(), auth.Principal{Name: "alice"})
+	bob := auth.WithPrincipal(context.Background(), auth.Principal{Name: "bob"})
+
+	// Get and Delete share the default bucket of alice
+	if call(alice, proto.Clavis_Get_FullMethodName) != nil || call(alice, proto.Clavis_Delete_FullMethodName) != nil {
+		t.Fatal("Expected the burst to be served")
+	}
+	err := call(alice, proto.Clavis_Get_FullMethodName)
+	if status.Code(err) != codes.ResourceExhausted || !claviserrors.IsRateLimited(err) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if retryAfter := claviserrors.FromError(err).Metadata["retry_after"]; retryAfter != "1" {
+		t.Errorf("retry_after = %q, want 1", retryAfter)
+	}
+
+	if err := call(bob, proto.Clavis_Get_FullMethodName); err != nil {
+		t.Errorf("Expected bob to have a bucket of their own, got %v", err)
+	}
+	if call(alice, proto.Clavis_Put_FullMethodName) != nil {
+		t.Error("Expected Put to have a bucket of its own")
+	}
+	if err := call(alice, proto.Clavis_Put_FullMethodName); claviserrors.FromError(err).Metadata["retry_after"] != "10" {
+		t.Errorf("Expected the Put limit to apply, got %v", err)
+	}
+	for range 5 {
+		if call(alice, proto.Clavis_Count_FullMethodName) != nil || call(alice, "/grpc.health.v1.Health/Check") != nil {
+			t.Fatal("Expected unlimited and exempt methods to be served")
+		}
+	}
+}
+
+func TestRateLimitInterceptors_ByIP(t *testing.T) {
+	config := RateLimitConfig{Default: RateLimit{RequestsPerSecond: 1}, By: RateLimitByIP}
+	unary := RateLimitUnaryInterceptor(config)
+	stream := RateLimitStreamInterceptor(config)
+	withPeer := func(name, addr string) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Name: name})
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 50000}})
+	}
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+
+	if _, err := unary(withPeer("alice", "10.0.0.1"), nil, &grpc.UnaryServerInfo{FullMethod: proto.Clavis_Get_FullMethodName}, ok); err != nil {
+		t.Fatal(err)
+	}
+	// Another principal behind the same address shares its bucket
+	_, err := unary(withPeer("bob", "10.0.0.1"), nil, &grpc.UnaryServerInfo{FullMethod: proto.Clavis_Get_FullMethodName}, ok)
+	if !claviserrors.IsRateLimited(err) {
+		t.Errorf("Expected the address to be limited, got %v", err)
+	}
+
+	handler := func(srv any, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&proto.ScanRequest{})
+	}
+	info := &grpc.StreamServerInfo{FullMethod: proto.Clavis_Scan_FullMethodName}
+	ss := &headerStream{recvStream: recvStream{ctx: withPeer("carol", "10.0.0.2"), req: &proto.ScanRequest{}}}
+	if err := stream(nil, ss, info, handler); err != nil {
+		t.Errorf("Expected the first stream of another address to be served, got %v", err)
+	}
+	if err := stream(nil, ss, info, handler); !claviserrors.IsRateLimited(err) {
+		t.Errorf("Expected the second message to be limited, got %v", err)
+	}
+	if retryAfter := ss.header.Get(RetryAfterMetadataKey); len(retryAfter) != 1 || retryAfter[0] != "1" {
+		t.Errorf("Header %s = %v, want 1", RetryAfterMetadataKey, retryAfter)
+	}
+}
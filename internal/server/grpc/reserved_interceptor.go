@@ -0,0 +1,76 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clavisServicePrefix prefixes the full method names of the key-value
+// service.
+var clavisServicePrefix = "/" + proto.Clavis_ServiceDesc.ServiceName + "/"
+
+// checkReserved returns an INVALID_ARGUMENT error if req writes, deletes or
+// lists keys under store.ReservedPrefix, which only the server's own
+// components may change. Single keys stay readable, so operators can inspect
+// settings, locks and leases, and listings of prefixes such as "" leave them
+// out. Calls of the key-value service whose keys are unknown are rejected;
+// those of other services, such as admin calls, are not checked.
+func checkReserved(method string, req any) error {
+	if !strings.HasPrefix(method, clavisServicePrefix) {
+		return nil
+	}
+	accesses, ok := requestAccesses(method, req)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "%s is not covered by the reserved key check", method)
+	}
+	for _, access := range accesses {
+		if access.op == auth.OpRead {
+			continue
+		}
+		if err := store.CheckReserved(access.key); err != nil {
+			return convertError(err)
+		}
+	}
+	return nil
+}
+
+// ReservedUnaryInterceptor rejects unary calls that write, delete or list
+// reserved keys, before any handler runs.
+func ReservedUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkReserved(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ReservedStreamInterceptor rejects the messages of streaming calls that
+// write, delete or list reserved keys as the handler receives them.
+func ReservedStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &reservedStream{ServerStream: ss, method: info.FullMethod})
+	}
+}
+
+// reservedStream checks every message received on a server stream against
+// the reserved keys.
+type reservedStream struct {
+	grpc.ServerStream
+	method string
+}
+
+// RecvMsg receives a message and checks the keys it addresses.
+func (s *reservedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkReserved(s.method, m)
+}
@@ -0,0 +1,72 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/lease"
+	"github.com/William-Fernandes252/clavis/internal/lock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReservedInterceptors(t *testing.T) {
+	unary := ReservedUnaryInterceptor()
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+	settingsKey := "__clavis/settings/current/tenant-a"
+
+	tests := []struct {
+		name     string
+		method   string
+		req      any
+		wantCode codes.Code
+	}{
+		{name: "put", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: settingsKey}, wantCode: codes.InvalidArgument},
+		{name: "put through a namespace", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: "settings/current/tenant-a", Namespace: "__clavis"}, wantCode: codes.InvalidArgument},
+		{name: "delete", method: proto.Clavis_Delete_FullMethodName, req: &proto.DeleteRequest{Key: settingsKey}, wantCode: codes.InvalidArgument},
+		{name: "batch put", method: proto.Clavis_BatchPut_FullMethodName, req: &proto.BatchPutRequest{Items: []*proto.KeyValue{{Key: "a"}, {Key: settingsKey}}}, wantCode: codes.InvalidArgument},
+		{name: "batch delete", method: proto.Clavis_BatchDelete_FullMethodName, req: &proto.BatchDeleteRequest{Keys: []string{settingsKey}}, wantCode: codes.InvalidArgument},
+		{name: "copy onto", method: proto.Clavis_Copy_FullMethodName, req: &proto.CopyRequest{Source: "a", Destination: settingsKey}, wantCode: codes.InvalidArgument},
+		{name: "scan", method: proto.Clavis_Scan_FullMethodName, req: &proto.ScanRequest{Prefix: "__clavis/settings/"}, wantCode: codes.InvalidArgument},
+		{name: "range", method: proto.Clavis_Range_FullMethodName, req: &proto.RangeRequest{Start: "__clavis/a", End: "__clavis/z"}, wantCode: codes.InvalidArgument},
+		{name: "delete prefix", method: proto.Clavis_DeletePrefix_FullMethodName, req: &proto.DeletePrefixRequest{Prefix: "__clavis/"}, wantCode: codes.InvalidArgument},
+		{name: "fence record", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: lock.FencePrefix + "orders", Value: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}, wantCode: codes.InvalidArgument},
+		{name: "fence reset", method: proto.Clavis_Delete_FullMethodName, req: &proto.DeleteRequest{Key: lock.FencePrefix + "orders"}, wantCode: codes.InvalidArgument},
+		{name: "reserved fencing lock", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: "a", Fencing: &proto.FencingToken{Lock: "__clavis/x", Token: 1}}, wantCode: codes.InvalidArgument},
+		{name: "forged lock", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: lock.LeasePrefix + "orders", ExpectedVersion: new(uint64)}, wantCode: codes.InvalidArgument},
+		{name: "stolen lock", method: proto.Clavis_Delete_FullMethodName, req: &proto.DeleteRequest{Key: lock.LeasePrefix + "orders"}, wantCode: codes.InvalidArgument},
+		{name: "lease attachments", method: proto.Clavis_BatchDelete_FullMethodName, req: &proto.BatchDeleteRequest{Keys: []string{lease.AttachPrefix + "1/tenant-a/x"}}, wantCode: codes.InvalidArgument},
+		{name: "foreign key attached", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: lease.AttachPrefix + "1/tenant-b/x"}, wantCode: codes.InvalidArgument},
+		{name: "get", method: proto.Clavis_Get_FullMethodName, req: &proto.GetRequest{Key: settingsKey}},
+		{name: "copy from", method: proto.Clavis_Copy_FullMethodName, req: &proto.CopyRequest{Source: settingsKey, Destination: "a"}},
+		{name: "scan of everything", method: proto.Clavis_Scan_FullMethodName, req: &proto.ScanRequest{}},
+		{name: "range of everything", method: proto.Clavis_Range_FullMethodName, req: &proto.RangeRequest{Start: "a", End: "z"}},
+		{name: "unknown request", method: proto.Clavis_Get_FullMethodName, req: &proto.GetResponse{}, wantCode: codes.PermissionDenied},
+		{name: "health check", method: "/grpc.health.v1.Health/Check", req: nil},
+		{name: "other key", method: proto.Clavis_Put_FullMethodName, req: &proto.PutRequest{Key: "tenant-a/__clavis/x"}},
+		{name: "admin", method: proto.ClavisAdmin_DeleteNamespace_FullMethodName, req: &proto.DeleteNamespaceRequest{Namespace: "tenant-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := unary(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, ok); status.Code(err) != tt.wantCode {
+				t.Errorf("Expected code %v, got %v", tt.wantCode, err)
+			}
+		})
+	}
+
+	t.Run("Stream", func(t *testing.T) {
+		stream := ReservedStreamInterceptor()
+		info := &grpc.StreamServerInfo{FullMethod: proto.Clavis_ScanStream_FullMethodName}
+		handler := func(_ any, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&proto.ScanRequest{})
+		}
+		for prefix, want := range map[string]codes.Code{"tenant-a/": codes.OK, "__clavis/locks/": codes.InvalidArgument} {
+			err := stream(nil, &recvStream{ctx: context.Background(), req: &proto.ScanRequest{Prefix: prefix}}, info, handler)
+			if status.Code(err) != want {
+				t.Errorf("Scan of %q: expected code %v, got %v", prefix, want, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/index"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+)
+
+// Search returns the keys whose values contain every term of the query,
+// best matches first, reading the full-text index of the store.
+func (s *GRPCServer) Search(ctx context.Context, req *proto.SearchRequest) (*proto.SearchResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	idx, err := index.From(s.store)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	limit, err := scanLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := idx.Search(req.Query, inNamespace(req.Namespace, req.Prefix), limit)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	resp := &proto.SearchResponse{Hits: make([]*proto.SearchHit, len(hits))}
+	for i, hit := range hits {
+		key := hit.Key
+		if req.Namespace != "" {
+			key = strings.TrimPrefix(key, req.Namespace+settings.NamespaceSeparator)
+		}
+		resp.Hits[i] = &proto.SearchHit{Key: key, Score: hit.Score}
+	}
+	return resp, nil
+}
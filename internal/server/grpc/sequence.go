@@ -0,0 +1,22 @@
+package proto
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// NextSequence reserves the next numbers of the named sequence for the
+// caller, one unless a bandwidth is requested.
+func (s *GRPCServer) NextSequence(ctx context.Context, req *proto.NextSequenceRequest) (*proto.NextSequenceResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	count := max(req.Bandwidth, 1)
+	first, err := store.NextSequence(s.store, inNamespace(req.Namespace, req.Name), count)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &proto.NextSequenceResponse{First: first, Count: count}, nil
+}
@@ -0,0 +1,307 @@
+package proto
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store/keymeta"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultMaxScanResponseBytes is the encoded size above which a Scan
+	// that allows spilling is spilled to disk. It leaves headroom below the
+	// 4 MiB that gRPC clients accept by default.
+	DefaultMaxScanResponseBytes = 4<<20 - 64<<10
+	// DefaultSpillChunkBytes is the size of FetchSpill chunks when the request does not set one.
+	DefaultSpillChunkBytes = 1 << 20
+	// DefaultSpillTTL is how long a spill is kept after it was last fetched from.
+	DefaultSpillTTL = 15 * time.Minute
+)
+
+// spill is a scan result written to a temporary file of length-delimited
+// KeyValue messages.
+type spill struct {
+	path      string
+	prefix    string
+	principal string
+	items     int64
+	bytes     int64
+	expires   time.Time
+}
+
+// spills tracks the spill files of a server and removes them once they
+// expire.
+type spills struct {
+	dir string
+	ttl time.Duration
+
+	mu    sync.Mutex
+	files map[string]*spill
+}
+
+func newSpills(dir string, ttl time.Duration) *spills {
+	if ttl <= 0 {
+		ttl = DefaultSpillTTL
+	}
+	return &spills{dir: dir, ttl: ttl, files: make(map[string]*spill)}
+}
+
+// spillWriter writes one scan result to a spill file.
+type spillWriter struct {
+	spills *spills
+	file   *os.File
+	buf    *bufio.Writer
+	spill  *spill
+}
+
+// create starts a spill of a scan of prefix made on behalf of the request in ctx.
+func (s *spills) create(ctx context.Context, prefix string) (*spillWriter, error) {
+	s.removeExpired()
+	file, err := os.CreateTemp(s.dir, "clavis-spill-*")
+	if err != nil {
+		return nil, err
+	}
+	sp := &spill{path: file.Name(), prefix: prefix}
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		sp.principal = principal.Name
+	}
+	return &spillWriter{spills: s, file: file, buf: bufio.NewWriter(file), spill: sp}, nil
+}
+
+// write appends an item to the spill.
+func (w *spillWriter) write(item *proto.KeyValue) error {
+	n, err := protodelim.MarshalTo(w.buf, item)
+	if err != nil {
+		return err
+	}
+	w.spill.items++
+	w.spill.bytes += int64(n)
+	return nil
+}
+
+// commit closes the spill file and registers it under a new handle.
+func (w *spillWriter) commit() (*proto.SpillHandle, error) {
+	if err := w.buf.Flush(); err != nil {
+		w.abort()
+		return nil, err
+	}
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(w.spill.path)
+		return nil, err
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		_ = os.Remove(w.spill.path)
+		return nil, err
+	}
+	handle := hex.EncodeToString(id[:])
+
+	w.spills.mu.Lock()
+	defer w.spills.mu.Unlock()
+	w.spill.expires = time.Now().Add(w.spills.ttl)
+	w.spills.files[handle] = w.spill
+	return &proto.SpillHandle{Id: handle, Items: w.spill.items, Bytes: w.spill.bytes, ExpiresUnixNano: w.spill.expires.UnixNano()}, nil
+}
+
+// abort discards a spill that was not committed.
+func (w *spillWriter) abort() {
+	_ = w.file.Close()
+	_ = os.Remove(w.spill.path)
+}
+
+// fetch reads the items starting at offset, stopping before the chunk
+// exceeds maxBytes unless it would be empty.
+func (s *spills) fetch(ctx context.Context, req *proto.FetchSpillRequest, maxBytes int) (*proto.FetchSpillResponse, error) {
+	s.removeExpired()
+	s.mu.Lock()
+	sp, ok := s.files[req.Id]
+	if ok {
+		sp.expires = time.Now().Add(s.ttl)
+	}
+	s.mu.Unlock()
+
+	if !ok || sp.prefix != inNamespace(req.Namespace, req.Prefix) {
+		return nil, status.Error(codes.NotFound, "spill not found or expired")
+	}
+	if principal, _ := auth.PrincipalFrom(ctx); principal.Name != sp.principal {
+		return nil, status.Error(codes.NotFound, "spill not found or expired")
+	}
+	if req.Offset < 0 || req.Offset > sp.bytes {
+		return nil, status.Errorf(codes.OutOfRange, "offset %d is outside the spill of %d bytes", req.Offset, sp.bytes)
+	}
+
+	file, err := os.Open(sp.path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.Seek(req.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(file)
+	resp := &proto.FetchSpillResponse{NextOffset: req.Offset}
+	size := 0
+	for resp.NextOffset < sp.bytes {
+		item := &proto.KeyValue{}
+		if err := (protodelim.UnmarshalOptions{MaxSize: -1}).UnmarshalFrom(reader, item); err != nil {
+			return nil, fmt.Errorf("corrupt spill: %w", err)
+		}
+		n := delimitedSize(item)
+		if len(resp.Items) > 0 && size+n > maxBytes {
+			break
+		}
+		resp.Items = append(resp.Items, item)
+		resp.NextOffset += int64(n)
+		size += n
+	}
+	resp.Done = resp.NextOffset == sp.bytes
+	outOfNamespace(req.Namespace, resp.Items)
+	return resp, nil
+}
+
+// delimitedSize is the encoded size of item in a spill file, which is also
+// its size in a response apart from the field tag.
+func delimitedSize(item *proto.KeyValue) int {
+	return protowire.SizeBytes(protobuf.Size(item))
+}
+
+// removeExpired deletes the spills that were not fetched from within the TTL.
+func (s *spills) removeExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for handle, sp := range s.files {
+		if now.After(sp.expires) {
+			_ = os.Remove(sp.path)
+			delete(s.files, handle)
+		}
+	}
+}
+
+// removeAll deletes every spill.
+func (s *spills) removeAll() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for handle, sp := range s.files {
+		if err := os.Remove(sp.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		delete(s.files, handle)
+	}
+	return errors.Join(errs...)
+}
+
+// iteratorScan scans prefix with an iterator at version, returning the items
+// in the response with their metadata when tracker is set. When the request allows spilling, the whole result is
+// spilled to disk once the items exceed the response size limit. A positive
+// limit pages the result, stopping after one page with a cursor to resume
+// from; a zero limit selects every match.
+func (s *GRPCServer) iteratorScan(ctx context.Context, req *proto.ScanRequest, tracker *keymeta.Store, version uint64, after string, limit int) (*proto.ScanResponse, error) {
+	it, err := s.scanIterator(req, after, version)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	defer func() {
+		if err := it.Close(); err != nil {
+			logging.FromContext(ctx).Warn("Failed to close iterator", "error", err)
+		}
+	}()
+
+	maxBytes := DefaultMaxScanResponseBytes
+	if s.config != nil && s.config.MaxScanResponseBytes > 0 {
+		maxBytes = s.config.MaxScanResponseBytes
+	}
+
+	resp := &proto.ScanResponse{}
+	var writer *spillWriter
+	defer func() {
+		if writer != nil && resp.Spill == nil {
+			writer.abort()
+		}
+	}()
+
+	size, count, last := 0, 0, ""
+	for it.Next() {
+		if after != "" && it.Key() <= after {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		if limit > 0 && count == limit {
+			resp.NextCursor = encodeCursor(last)
+			break
+		}
+		item := &proto.KeyValue{Key: it.Key()}
+		if !req.KeysOnly {
+			item.Value = it.Value()
+		}
+		if err := annotate(tracker, []*proto.KeyValue{item}, version); err != nil {
+			return nil, err
+		}
+		count, last = count+1, item.Key
+
+		if writer != nil {
+			if err := writer.write(item); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		resp.Items = append(resp.Items, item)
+		size += delimitedSize(item)
+		if size <= maxBytes || !req.AllowSpill {
+			continue
+		}
+		if writer, err = s.spillFiles().create(ctx, req.Prefix); err != nil {
+			return nil, err
+		}
+		for _, buffered := range resp.Items {
+			if err := writer.write(buffered); err != nil {
+				return nil, err
+			}
+		}
+		resp.Items = nil
+	}
+	if err := it.Err(); err != nil {
+		return nil, convertError(err)
+	}
+
+	if writer != nil {
+		if resp.Spill, err = writer.commit(); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// FetchSpill returns a chunk of a scan result spilled by Scan. Spills are
+// only visible to the principal that scanned, under the same prefix.
+func (s *GRPCServer) FetchSpill(ctx context.Context, req *proto.FetchSpillRequest) (*proto.FetchSpillResponse, error) {
+	maxBytes := int(req.MaxBytes)
+	switch {
+	case req.MaxBytes < 0:
+		return nil, status.Error(codes.InvalidArgument, "max_bytes cannot be negative")
+	case req.MaxBytes == 0:
+		maxBytes = DefaultSpillChunkBytes
+	}
+	return s.spillFiles().fetch(ctx, req, maxBytes)
+}
@@ -0,0 +1,163 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_ScanSpill(t *testing.T) {
+	mockStore := newMockStore()
+	dir := t.TempDir()
+	s := &GRPCServer{
+		store:  mockStore,
+		config: &GRPCServerConfig{SpillDir: dir, MaxScanResponseBytes: 200},
+	}
+	ctx := context.Background()
+
+	var want []string
+	for i := range 20 {
+		key := fmt.Sprintf("export/%02d", i)
+		want = append(want, key)
+		if err := mockStore.Put(key, []byte(strings.Repeat("v", 20))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mockStore.Put("other", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+
+	fetchAll := func(t *testing.T, ctx context.Context, prefix string, handle *proto.SpillHandle) []string {
+		t.Helper()
+		var keys []string
+		req := &proto.FetchSpillRequest{Id: handle.Id, Prefix: prefix, MaxBytes: 100}
+		for chunks := 1; ; chunks++ {
+			resp, err := s.FetchSpill(ctx, req)
+			if err != nil {
+				t.Fatalf("FetchSpill failed: %v", err)
+			}
+			for _, item := range resp.Items {
+				keys = append(keys, item.Key)
+			}
+			if resp.Done {
+				if chunks < 2 {
+					t.Errorf("Expected the spill to be served in several chunks")
+				}
+				return keys
+			}
+			req.Offset = resp.NextOffset
+		}
+	}
+
+	t.Run("SmallResultsAreNotSpilled", func(t *testing.T) {
+		resp, err := s.Scan(ctx, &proto.ScanRequest{Prefix: "other", AllowSpill: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Spill != nil || len(resp.Items) != 1 {
+			t.Errorf("Expected one item inline, got %v", resp)
+		}
+	})
+
+	t.Run("SpillsEveryMatch", func(t *testing.T) {
+		resp, err := s.Scan(ctx, &proto.ScanRequest{Prefix: "export/", AllowSpill: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Spill == nil || len(resp.Items) != 0 || resp.NextCursor != "" {
+			t.Fatalf("Expected the result to be spilled, got %v", resp)
+		}
+		if resp.Spill.Items != 20 {
+			t.Errorf("Expected 20 spilled items, got %d", resp.Spill.Items)
+		}
+		if keys := fetchAll(t, ctx, "export/", resp.Spill); !reflect.DeepEqual(keys, want) {
+			t.Errorf("Unexpected keys: %v", keys)
+		}
+	})
+
+	t.Run("HonorsLimitAndCursor", func(t *testing.T) {
+		resp, err := s.Scan(ctx, &proto.ScanRequest{Prefix: "export/", AllowSpill: true, Limit: 15})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Spill == nil || resp.Spill.Items != 15 || resp.NextCursor == "" {
+			t.Fatalf("Expected a spilled page of 15 with a cursor, got %v", resp)
+		}
+		rest, err := s.Scan(ctx, &proto.ScanRequest{Prefix: "export/", AllowSpill: true, Cursor: resp.NextCursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rest.Spill != nil || len(rest.Items) != 5 || rest.Items[0].Key != "export/15" {
+			t.Errorf("Expected the last 5 items inline, got %v", rest)
+		}
+	})
+
+	t.Run("OnlyVisibleToTheScanner", func(t *testing.T) {
+		alice := auth.WithPrincipal(ctx, auth.Principal{Name: "alice"})
+		resp, err := s.Scan(alice, &proto.ScanRequest{Prefix: "export/", AllowSpill: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob := auth.WithPrincipal(ctx, auth.Principal{Name: "bob"})
+		for name, req := range map[string]struct {
+			ctx    context.Context
+			prefix string
+		}{"other principal": {bob, "export/"}, "other prefix": {alice, "exp"}, "anonymous": {ctx, "export/"}} {
+			_, err := s.FetchSpill(req.ctx, &proto.FetchSpillRequest{Id: resp.Spill.Id, Prefix: req.prefix})
+			if status.Code(err) != codes.NotFound {
+				t.Errorf("%s: expected NotFound, got %v", name, err)
+			}
+		}
+		if keys := fetchAll(t, alice, "export/", resp.Spill); len(keys) != 20 {
+			t.Errorf("Expected the scanner to fetch the spill, got %v", keys)
+		}
+	})
+
+	t.Run("RemovedOnShutdown", func(t *testing.T) {
+		if err := s.spillFiles().removeAll(); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("Expected no spill files left, got %v", entries)
+		}
+	})
+}
+
+func TestGRPCServer_ScanSpill_Expires(t *testing.T) {
+	mockStore := newMockStore()
+	dir := t.TempDir()
+	s := &GRPCServer{
+		store:  mockStore,
+		config: &GRPCServerConfig{SpillDir: dir, MaxScanResponseBytes: 1, SpillTTL: 10 * time.Millisecond},
+	}
+	for _, key := range []string{"a", "b"} {
+		if err := mockStore.Put(key, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := s.Scan(context.Background(), &proto.ScanRequest{AllowSpill: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := s.FetchSpill(context.Background(), &proto.FetchSpillRequest{Id: resp.Spill.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected an expired spill to be gone, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the expired spill file to be removed, got %v", entries)
+	}
+}
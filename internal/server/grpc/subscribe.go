@@ -0,0 +1,52 @@
+package proto
+
+import (
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Subscribe streams changes to keys under the requested prefix until the
+// client goes away, or until it falls behind by more than the broker buffers
+// and is evicted.
+func (s *GRPCServer) Subscribe(req *proto.SubscribeRequest, stream grpc.ServerStreamingServer[proto.WatchEvent]) error {
+	if s.config == nil || s.config.PubSub == nil {
+		return status.Error(codes.Unimplemented, "subscriptions are not enabled on this server")
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return err
+	}
+	sub, err := s.config.PubSub.Subscribe(inNamespace(req.Namespace, req.Prefix))
+	if err != nil {
+		return convertError(err)
+	}
+	defer sub.Close()
+	root := inNamespace(req.Namespace, "")
+
+	ctx := stream.Context()
+	for {
+		e, err := sub.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return convertError(err)
+		}
+		if store.IsReserved(e.Key) {
+			continue
+		}
+		err = stream.Send(&proto.WatchEvent{
+			Type:         watchEventTypes[e.Type],
+			Key:          strings.TrimPrefix(e.Key, root),
+			Value:        e.Value,
+			TimeUnixNano: e.Time.UnixNano(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store/tags"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QueryByTag returns a page of the keys carrying the requested tag, in key
+// order, with a cursor to resume from, reading the tag index of the store.
+func (s *GRPCServer) QueryByTag(ctx context.Context, req *proto.QueryByTagRequest) (*proto.QueryByTagResponse, error) {
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+	index, err := tags.From(s.store)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	after, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	limit, err := scanLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// One key more than the page tells whether there is a next one
+	keys, err := index.Keys(req.Tag, inNamespace(req.Namespace, req.Prefix), after, limit+1)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	resp := &proto.QueryByTagResponse{}
+	if len(keys) > limit {
+		keys = keys[:limit]
+		resp.NextCursor = encodeCursor(keys[len(keys)-1])
+	}
+	if req.Namespace != "" {
+		for i, key := range keys {
+			keys[i] = strings.TrimPrefix(key, req.Namespace+settings.NamespaceSeparator)
+		}
+	}
+	resp.Keys = keys
+	return resp, nil
+}
+
+// tagWrite returns ctx tagging the keys written with it with names, failing
+// if the store indexes no tags. Without names, ctx is returned as it is.
+func (s *GRPCServer) tagWrite(ctx context.Context, names []string) (context.Context, error) {
+	if len(names) == 0 {
+		return ctx, nil
+	}
+	if _, err := tags.From(s.store); err != nil {
+		return nil, convertError(err)
+	}
+	return tags.NewContext(ctx, names), nil
+}
@@ -0,0 +1,92 @@
+package proto
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tracerName is the instrumentation scope of server spans.
+const tracerName = "github.com/William-Fernandes252/clavis/internal/server/grpc"
+
+// metadataCarrier adapts incoming gRPC metadata to a propagation carrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startRPCSpan starts a server span for a call, continuing the caller's trace
+// when its metadata carries one.
+func startRPCSpan(ctx context.Context, tracer trace.Tracer, method string) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = propagation.TraceContext{}.Extract(ctx, metadataCarrier(md))
+	}
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return tracer.Start(ctx, strings.TrimPrefix(method, "/"), trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", name),
+	))
+}
+
+// endRPCSpan records the status of a call and ends its span. Like the logging
+// interceptors, only server faults mark the span as failed.
+func endRPCSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if serverFaults[code] {
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
+
+// TracingUnaryInterceptor records a span for every unary call with tracers
+// from provider. Handlers, and the stores they call with the request context,
+// record their spans as its children.
+func TracingUnaryInterceptor(provider trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := provider.Tracer(tracerName)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startRPCSpan(ctx, tracer, info.FullMethod)
+		if key, ok := requestKey(req); ok {
+			span.SetAttributes(attribute.String("clavis.key", key))
+		}
+		resp, err := handler(ctx, req)
+		endRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// TracingStreamInterceptor records a span for every streaming call like
+// TracingUnaryInterceptor.
+func TracingStreamInterceptor(provider trace.TracerProvider) grpc.StreamServerInterceptor {
+	tracer := provider.Tracer(tracerName)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startRPCSpan(ss.Context(), tracer, info.FullMethod)
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		endRPCSpan(span, err)
+		return err
+	}
+}
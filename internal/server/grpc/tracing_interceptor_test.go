@@ -0,0 +1,63 @@
+package proto
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracingInterceptors(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer collector.Close()
+	provider, err := tracing.New(tracing.Config{Endpoint: collector.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	unary := TracingUnaryInterceptor(provider)
+	stream := TracingStreamInterceptor(provider)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01"))
+
+	var handled trace.SpanContext
+	handler := func(ctx context.Context, req any) (any, error) {
+		handled = trace.SpanContextFromContext(ctx)
+		if !trace.SpanFromContext(ctx).IsRecording() {
+			t.Error("Expected the handler to run inside a recording span")
+		}
+		return nil, nil
+	}
+	if _, err := unary(incoming, &proto.GetRequest{Key: "k"}, &grpc.UnaryServerInfo{FullMethod: "/clavis.v1.Clavis/Get"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if handled.TraceID().String() != traceID || handled.SpanID().String() == "00f067aa0ba902b7" {
+		t.Errorf("Expected a child span of the caller's trace, got %v", handled)
+	}
+
+	if _, err := unary(context.Background(), &proto.GetRequest{Key: "k"}, &grpc.UnaryServerInfo{FullMethod: "/clavis.v1.Clavis/Get"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if !handled.IsValid() || handled.TraceID().String() == traceID {
+		t.Errorf("Expected calls without trace context to start a new trace, got %v", handled)
+	}
+
+	err = stream(nil, &recvStream{ctx: incoming, req: &proto.ScanRequest{}}, &grpc.StreamServerInfo{FullMethod: "/clavis.v1.Clavis/ScanStream"},
+		func(_ any, ss grpc.ServerStream) error {
+			handled = trace.SpanContextFromContext(ss.Context())
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handled.TraceID().String() != traceID {
+		t.Errorf("Expected streams to continue the caller's trace, got %v", handled)
+	}
+}
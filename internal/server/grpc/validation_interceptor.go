@@ -0,0 +1,133 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"google.golang.org/grpc"
+)
+
+// RequestValidator checks the shape of a request before it reaches the
+// handler, independently of the caller and of the store.
+type RequestValidator func(req any) validation.ValidationResult
+
+// RequestValidators holds the validator of each RPC by full method name.
+// Requests of methods without one are passed through unchecked.
+type RequestValidators map[string]RequestValidator
+
+// Register sets the validator of method, replacing any previous one.
+func (v RequestValidators) Register(method string, validator RequestValidator) {
+	v[method] = validator
+}
+
+// validate returns an InvalidArgument error with the failures of the
+// validator of method, if any.
+func (v RequestValidators) validate(method string, req any) error {
+	validator, ok := v[method]
+	if !ok {
+		return nil
+	}
+	result := validator(req)
+	return convertError(result.Err())
+}
+
+// forRequest adapts a validator of requests of type T, ignoring requests of
+// any other type.
+func forRequest[T any](validate func(T) validation.ValidationResult) RequestValidator {
+	return func(req any) validation.ValidationResult {
+		if req, ok := req.(T); ok {
+			return validate(req)
+		}
+		return validation.ValidationResult{}
+	}
+}
+
+// DefaultRequestValidators checks the keys and values of the requests of the
+// Clavis service against profile, usually validation.PermissiveProfile so
+// only what no store accepts is rejected early. Batch items are reported at
+// paths such as "items[2].key".
+func DefaultRequestValidators(profile *validation.Profile) RequestValidators {
+	key := func(namespace, key string) validation.ValidationResult {
+		return profile.Key.Validate(validation.Context{Target: "key", Namespace: namespace}, key)
+	}
+	keys := func(namespace string, keys []string) validation.ValidationResult {
+		var result validation.ValidationResult
+		for i, key := range keys {
+			result.Merge(profile.Key.Validate(validation.Context{Target: fmt.Sprintf("keys[%d]", i), Namespace: namespace}, key))
+		}
+		return result
+	}
+
+	return RequestValidators{
+		proto.Clavis_Get_FullMethodName:          forRequest(func(req *proto.GetRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_Delete_FullMethodName:       forRequest(func(req *proto.DeleteRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_Exists_FullMethodName:       forRequest(func(req *proto.ExistsRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_Increment_FullMethodName:    forRequest(func(req *proto.IncrementRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_Undelete_FullMethodName:     forRequest(func(req *proto.UndeleteRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_GetMetadata_FullMethodName:  forRequest(func(req *proto.GetMetadataRequest) validation.ValidationResult { return key(req.Namespace, req.Key) }),
+		proto.Clavis_Lock_FullMethodName:         forRequest(func(req *proto.LockRequest) validation.ValidationResult { return key(req.Namespace, req.Name) }),
+		proto.Clavis_NextSequence_FullMethodName: forRequest(func(req *proto.NextSequenceRequest) validation.ValidationResult { return key(req.Namespace, req.Name) }),
+		proto.Clavis_Put_FullMethodName: forRequest(func(req *proto.PutRequest) validation.ValidationResult {
+			return profile.Validate(validation.Context{Namespace: req.Namespace}, req.Key, req.Value)
+		}),
+		proto.Clavis_CreateUnique_FullMethodName: forRequest(func(req *proto.CreateUniqueRequest) validation.ValidationResult {
+			return profile.Value.Validate(validation.Context{Target: "value", Namespace: req.Namespace}, req.Value)
+		}),
+		proto.Clavis_Copy_FullMethodName: forRequest(func(req *proto.CopyRequest) validation.ValidationResult {
+			ctx := validation.Context{Namespace: req.Namespace}
+			result := profile.Key.Validate(ctx.At("source"), req.Source)
+			result.Merge(profile.Key.Validate(ctx.At("destination"), req.Destination))
+			return result
+		}),
+		proto.Clavis_BatchPut_FullMethodName: forRequest(func(req *proto.BatchPutRequest) validation.ValidationResult {
+			var result validation.ValidationResult
+			ctx := validation.Context{Namespace: req.Namespace}
+			for i, item := range req.Items {
+				result.Merge(profile.Key.Validate(ctx.At(fmt.Sprintf("items[%d].key", i)), item.Key))
+				result.Merge(profile.Value.Validate(ctx.At(fmt.Sprintf("items[%d].value", i)), item.Value))
+			}
+			return result
+		}),
+		proto.Clavis_BatchGet_FullMethodName:    forRequest(func(req *proto.BatchGetRequest) validation.ValidationResult { return keys(req.Namespace, req.Keys) }),
+		proto.Clavis_BatchDelete_FullMethodName: forRequest(func(req *proto.BatchDeleteRequest) validation.ValidationResult { return keys(req.Namespace, req.Keys) }),
+	}
+}
+
+// ValidationUnaryInterceptor rejects unary calls whose requests fail the
+// validator of their method with InvalidArgument, before any handler runs.
+func ValidationUnaryInterceptor(validators RequestValidators) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validators.validate(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ValidationStreamInterceptor rejects the messages of streaming calls that
+// fail the validator of their method as the handler receives them.
+func ValidationStreamInterceptor(validators RequestValidators) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := validators[info.FullMethod]; !ok {
+			return handler(srv, ss)
+		}
+		return handler(srv, &validatedStream{ServerStream: ss, validators: validators, method: info.FullMethod})
+	}
+}
+
+// validatedStream validates every message received on a server stream.
+type validatedStream struct {
+	grpc.ServerStream
+	validators RequestValidators
+	method     string
+}
+
+// RecvMsg receives a message and checks it with the validator of the method.
+func (s *validatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.validators.validate(s.method, m)
+}
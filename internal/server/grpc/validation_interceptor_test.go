@@ -0,0 +1,88 @@
+package proto
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidationUnaryInterceptor(t *testing.T) {
+	interceptor := ValidationUnaryInterceptor(DefaultRequestValidators(validation.StandardProfile()))
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+	longKey := strings.Repeat("k", 2000)
+
+	tests := []struct {
+		name       string
+		method     string
+		req        any
+		wantCode   codes.Code
+		wantFields []string
+	}{
+		{name: "valid get", method: proto.Clavis_Get_FullMethodName, req: &proto.GetRequest{Key: "a"}},
+		{name: "empty key", method: proto.Clavis_Get_FullMethodName, req: &proto.GetRequest{}, wantCode: codes.InvalidArgument, wantFields: []string{"key"}},
+		{name: "long key", method: proto.Clavis_Delete_FullMethodName, req: &proto.DeleteRequest{Key: longKey}, wantCode: codes.InvalidArgument, wantFields: []string{"key"}},
+		{
+			name: "empty key and large value", method: proto.Clavis_Put_FullMethodName,
+			req:      &proto.PutRequest{Value: make([]byte, 2<<20)},
+			wantCode: codes.InvalidArgument, wantFields: []string{"key", "value"},
+		},
+		{
+			name: "batch item", method: proto.Clavis_BatchPut_FullMethodName,
+			req:      &proto.BatchPutRequest{Items: []*proto.KeyValue{{Key: "a"}, {Key: ""}}},
+			wantCode: codes.InvalidArgument, wantFields: []string{"items[1].key"},
+		},
+		{name: "batch key", method: proto.Clavis_BatchGet_FullMethodName, req: &proto.BatchGetRequest{Keys: []string{"a", longKey}}, wantCode: codes.InvalidArgument, wantFields: []string{"keys[1]"}},
+		{name: "copy destination", method: proto.Clavis_Copy_FullMethodName, req: &proto.CopyRequest{Source: "a", Destination: longKey}, wantCode: codes.InvalidArgument, wantFields: []string{"destination"}},
+		{name: "method without validator", method: proto.Clavis_Scan_FullMethodName, req: &proto.ScanRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, ok)
+			st := status.Convert(err)
+			if st.Code() != tt.wantCode {
+				t.Fatalf("Code = %v, want %v (%v)", st.Code(), tt.wantCode, err)
+			}
+			var fields []string
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range badRequest.FieldViolations {
+						fields = append(fields, v.Field)
+					}
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("Rejected fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidationStreamInterceptor(t *testing.T) {
+	validators := RequestValidators{}
+	validators.Register(proto.Clavis_ScanStream_FullMethodName, forRequest(func(req *proto.ScanRequest) validation.ValidationResult {
+		var result validation.ValidationResult
+		result.Add(validation.AllowedPrefixes("public/").Validate(validation.Context{Target: "prefix"}, req.Prefix))
+		return result
+	}))
+	interceptor := ValidationStreamInterceptor(validators)
+	handler := func(_ any, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&proto.ScanRequest{})
+	}
+	info := &grpc.StreamServerInfo{FullMethod: proto.Clavis_ScanStream_FullMethodName}
+
+	ss := &recvStream{ctx: context.Background(), req: &proto.ScanRequest{Prefix: "public/a"}}
+	if err := interceptor(nil, ss, info, handler); err != nil {
+		t.Errorf("Expected a valid prefix to pass, got %v", err)
+	}
+	ss.req.Prefix = "private/"
+	if err := interceptor(nil, ss, info, handler); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a rejected prefix, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package proto
+
+import (
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+)
+
+// protoWarnings converts the warnings gathered while serving a write.
+func protoWarnings(collector *warnings.Collector) []*proto.Warning {
+	collected := collector.Warnings()
+	if len(collected) == 0 {
+		return nil
+	}
+	out := make([]*proto.Warning, len(collected))
+	for i, w := range collected {
+		out[i] = &proto.Warning{Code: string(w.Code), Message: w.Message, Key: w.Key, Metadata: w.Metadata}
+	}
+	return out
+}
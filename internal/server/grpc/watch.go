@@ -0,0 +1,75 @@
+package proto
+
+import (
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchBufferSize is the number of events queued for a watcher before the
+// bus starts dropping events for it.
+const watchBufferSize = 64
+
+var watchEventTypes = map[events.Type]proto.WatchEvent_Type{
+	events.TypePut:    proto.WatchEvent_TYPE_PUT,
+	events.TypeDelete: proto.WatchEvent_TYPE_DELETE,
+	events.TypeExpire: proto.WatchEvent_TYPE_EXPIRE,
+}
+
+// Watch streams changes to the requested key, or to keys under the requested
+// prefix, until the client goes away. Watchers that fall behind miss events
+// rather than slowing down writers.
+func (s *GRPCServer) Watch(req *proto.WatchRequest, stream grpc.ServerStreamingServer[proto.WatchEvent]) error {
+	if s.config == nil || s.config.Events == nil {
+		return status.Error(codes.Unimplemented, "watching is not enabled on this server")
+	}
+
+	if req.Key != "" && req.Prefix != "" {
+		return status.Error(codes.InvalidArgument, "watch either a key or a prefix")
+	}
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return err
+	}
+	prefix := inNamespace(req.Namespace, req.Prefix)
+	matches := func(key string) bool { return strings.HasPrefix(key, prefix) && !store.IsReserved(key) }
+	if req.Key != "" {
+		key := inNamespace(req.Namespace, req.Key)
+		matches = func(k string) bool { return k == key }
+	}
+	root := inNamespace(req.Namespace, "")
+
+	ctx := stream.Context()
+	pending := make(chan events.Event, watchBufferSize)
+	unsubscribe := s.config.Events.Subscribe(func(e events.Event) {
+		if !matches(e.Key) {
+			return
+		}
+		select {
+		case pending <- e:
+		case <-ctx.Done():
+		}
+	}, events.TypePut, events.TypeDelete, events.TypeExpire)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case e := <-pending:
+			err := stream.Send(&proto.WatchEvent{
+				Type:         watchEventTypes[e.Type],
+				Key:          strings.TrimPrefix(e.Key, root),
+				Value:        e.Value,
+				TimeUnixNano: e.Time.UnixNano(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/pubsub"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockWatchStream forwards sent events to a channel
+type mockWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *proto.WatchEvent
+}
+
+func (m *mockWatchStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockWatchStream) Send(e *proto.WatchEvent) error {
+	m.events <- e
+	return nil
+}
+
+func TestGRPCServer_Watch(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		s := &GRPCServer{store: newMockStore(), config: &GRPCServerConfig{}}
+		stream := &mockWatchStream{ctx: context.Background()}
+		if err := s.Watch(&proto.WatchRequest{}, stream); status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected Unimplemented without an event bus, got %v", err)
+		}
+	})
+
+	t.Run("StreamsMatchingChanges", func(t *testing.T) {
+		bus := events.NewBus(0)
+		defer bus.Close()
+		publishing := events.NewPublishingStore(newMockStore(), bus)
+		s := &GRPCServer{store: publishing, config: &GRPCServerConfig{Events: bus}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &mockWatchStream{ctx: ctx, events: make(chan *proto.WatchEvent, 10)}
+		done := make(chan error, 1)
+		go func() { done <- s.Watch(&proto.WatchRequest{Prefix: "user:"}, stream) }()
+
+		// Writes made before the subscription is in place would be missed
+		deadline := time.Now().Add(5 * time.Second)
+		var first *proto.WatchEvent
+		for first == nil && time.Now().Before(deadline) {
+			if err := publishing.Put("user:sync", []byte("x")); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case first = <-stream.events:
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		if first == nil {
+			t.Fatal("Watch never delivered an event")
+		}
+		for len(stream.events) > 0 {
+			<-stream.events
+		}
+
+		if err := publishing.Put("order:1", []byte("ignored")); err != nil {
+			t.Fatal(err)
+		}
+		if err := publishing.Put("user:1", []byte("alice")); err != nil {
+			t.Fatal(err)
+		}
+		if err := publishing.Delete("user:1"); err != nil {
+			t.Fatal(err)
+		}
+
+		put, del := <-stream.events, <-stream.events
+		if put.Type != proto.WatchEvent_TYPE_PUT || put.Key != "user:1" || string(put.Value) != "alice" || put.TimeUnixNano == 0 {
+			t.Errorf("Unexpected put event: %v", put)
+		}
+		if del.Type != proto.WatchEvent_TYPE_DELETE || del.Key != "user:1" {
+			t.Errorf("Unexpected delete event: %v", del)
+		}
+
+		cancel()
+		if err := <-done; status.Code(err) != codes.Canceled {
+			t.Errorf("Expected Canceled after the client left, got %v", err)
+		}
+	})
+
+	t.Run("StreamsExpirationsOfAKey", func(t *testing.T) {
+		bus := events.NewBus(0)
+		defer bus.Close()
+		publishing := events.NewPublishingStore(newMockStore(), bus)
+		s := &GRPCServer{store: publishing, config: &GRPCServerConfig{Events: bus}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream := &mockWatchStream{ctx: ctx, events: make(chan *proto.WatchEvent, 10)}
+		go func() { _ = s.Watch(&proto.WatchRequest{Key: "session:1"}, stream) }()
+
+		deadline := time.Now().Add(5 * time.Second)
+		var first *proto.WatchEvent
+		for first == nil && time.Now().Before(deadline) {
+			if err := publishing.Put("session:1", []byte("x")); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case first = <-stream.events:
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		if first == nil {
+			t.Fatal("Watch never delivered an event")
+		}
+		for len(stream.events) > 0 {
+			<-stream.events
+		}
+
+		if err := publishing.Put("session:10", []byte("ignored")); err != nil {
+			t.Fatal(err)
+		}
+		if err := publishing.ExpireKeys([]string{"session:10", "session:1"}); err != nil {
+			t.Fatal(err)
+		}
+		if e := <-stream.events; e.Type != proto.WatchEvent_TYPE_EXPIRE || e.Key != "session:1" || len(e.Value) != 0 {
+			t.Errorf("Unexpected expire event: %v", e)
+		}
+	})
+
+	t.Run("RejectsKeyAndPrefix", func(t *testing.T) {
+		bus := events.NewBus(0)
+		defer bus.Close()
+		s := &GRPCServer{store: newMockStore(), config: &GRPCServerConfig{Events: bus}}
+		stream := &mockWatchStream{ctx: context.Background()}
+		if err := s.Watch(&proto.WatchRequest{Key: "a", Prefix: "a"}, stream); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestGRPCServer_Subscribe(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		s := &GRPCServer{store: newMockStore(), config: &GRPCServerConfig{}}
+		stream := &mockWatchStream{ctx: context.Background()}
+		if err := s.Subscribe(&proto.SubscribeRequest{}, stream); status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected Unimplemented without a broker, got %v", err)
+		}
+	})
+
+	bus := events.NewBus(0)
+	defer bus.Close()
+	publishing := events.NewPublishingStore(newMockStore(), bus)
+	broker := pubsub.NewBroker(bus, pubsub.Config{BufferSize: 2}, metrics.NewRegistry())
+	defer broker.Close()
+	s := &GRPCServer{store: publishing, config: &GRPCServerConfig{PubSub: broker}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Events are not buffered by the stream, so the subscriber falls behind
+	// as soon as it is not read from
+	stream := &mockWatchStream{ctx: ctx, events: make(chan *proto.WatchEvent)}
+	done := make(chan error, 1)
+	go func() { done <- s.Subscribe(&proto.SubscribeRequest{Namespace: "tenant"}, stream) }()
+
+	// Writes made before the subscription is in place would be missed
+	deadline := time.Now().Add(5 * time.Second)
+	var first *proto.WatchEvent
+	for first == nil && time.Now().Before(deadline) {
+		if err := publishing.Put("tenant/sync", []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case first = <-stream.events:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if first == nil {
+		t.Fatal("Subscribe never delivered an event")
+	}
+	if first.Type != proto.WatchEvent_TYPE_PUT || first.Key != "sync" || string(first.Value) != "x" {
+		t.Errorf("Unexpected event: %v", first)
+	}
+
+	for i := range 10 {
+		if err := publishing.Put(fmt.Sprintf("tenant/%d", i), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for {
+		select {
+		case <-stream.events:
+			continue
+		case err := <-done:
+			if claviserrors.CodeOf(err) != claviserrors.CodeSlowConsumer {
+				t.Errorf("Expected a slow consumer to be evicted, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Subscribe never evicted the slow consumer")
+		}
+		break
+	}
+}
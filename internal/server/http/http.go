@@ -0,0 +1,326 @@
+// Package http exposes the key operations of the gRPC API over plain HTTP,
+// so curl and browsers can use Clavis:
+//
+//	GET    /v1/keys/{key}               value of a key, with its version in ETag
+//	PUT    /v1/keys/{key}               store the request body; If-Match and If-None-Match: * make it conditional
+//	DELETE /v1/keys/{key}               remove a key
+//	GET    /v1/keys?prefix=&limit=&cursor=  a page of pairs, as JSON
+//
+// Requests are served by the gRPC service implementation through the same
+// unary interceptors as gRPC calls, so authentication, authorization,
+// validation and observability behave alike on both.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxValueBytes bounds the request bodies of PUT.
+const DefaultMaxValueBytes = 64 << 20
+
+// forwardedHeaders are copied into the incoming gRPC metadata of a call, for
+// authentication and trace propagation.
+var forwardedHeaders = []string{"Authorization", "X-Api-Key", "Traceparent", "Tracestate"}
+
+// Config configures an HTTP gateway.
+type Config struct {
+	Addr          string                        // Address to listen on, such as ":8080"
+	Interceptors  []grpc.UnaryServerInterceptor // Run around every call in order, as on the gRPC server
+	MaxValueBytes int64                         // Largest accepted PUT body; DefaultMaxValueBytes if zero
+	Logger        *slog.Logger                  // Logger for failed responses; slog.Default() if nil
+}
+
+// Gateway serves the REST API by calling a gRPC service implementation.
+type Gateway struct {
+	backend proto.ClavisServer
+	config  Config
+	logger  *slog.Logger
+	mux     *http.ServeMux
+	server  *http.Server
+}
+
+// New creates a gateway serving backend, typically the GRPCServer that also
+// serves gRPC clients.
+func New(backend proto.ClavisServer, config Config) *Gateway {
+	if config.MaxValueBytes <= 0 {
+		config.MaxValueBytes = DefaultMaxValueBytes
+	}
+	g := &Gateway{backend: backend, config: config, logger: logging.Or(config.Logger), mux: http.NewServeMux()}
+	g.mux.HandleFunc("GET /v1/keys/{key...}", g.get)
+	g.mux.HandleFunc("PUT /v1/keys/{key...}", g.put)
+	g.mux.HandleFunc("DELETE /v1/keys/{key...}", g.delete)
+	g.mux.HandleFunc("GET /v1/keys", g.scan)
+	g.server = &http.Server{Addr: config.Addr, Handler: g.mux, ReadHeaderTimeout: 10 * time.Second}
+	return g
+}
+
+// ServeHTTP serves one request, so the gateway can be mounted in another
+// server or tested with httptest.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Start listens on the configured address and serves requests until Shutdown
+// is called. Callbacks are run once the gateway is listening.
+func (g *Gateway) Start(callbacks ...func()) error {
+	listener, err := net.Listen("tcp", g.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", g.config.Addr, err)
+	}
+	for _, callback := range callbacks {
+		callback()
+	}
+	return g.Serve(listener)
+}
+
+// Serve serves requests on listener until Shutdown is called, then returns nil.
+func (g *Gateway) Serve(listener net.Listener) error {
+	if err := g.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for in-flight ones until ctx
+// ends. The backend is left running.
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	return g.server.Shutdown(ctx)
+}
+
+// call invokes the handler of a gRPC method through the interceptors, with
+// the forwarded headers as incoming metadata.
+func (g *Gateway) call(r *http.Request, method string, req any, handler grpc.UnaryHandler) (any, error) {
+	md := metadata.MD{}
+	for _, header := range forwardedHeaders {
+		if values := r.Header.Values(header); len(values) > 0 {
+			md.Append(strings.ToLower(header), values...)
+		}
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: remoteAddr(r.RemoteAddr)})
+
+	info := &grpc.UnaryServerInfo{Server: g.backend, FullMethod: method}
+	chained := handler
+	for i := len(g.config.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := g.config.Interceptors[i], chained
+		chained = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return chained(ctx, req)
+}
+
+// remoteAddr is the address of an HTTP client, as reported to interceptors.
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
+
+func (g *Gateway) get(w http.ResponseWriter, r *http.Request) {
+	req := &proto.GetRequest{Key: r.PathValue("key"), WithVersion: true}
+	resp, err := g.call(r, proto.Clavis_Get_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return g.backend.Get(ctx, req.(*proto.GetRequest))
+	})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	got := resp.(*proto.GetResponse)
+	if !got.Found {
+		g.writeError(w, status.Error(codes.NotFound, "key not found"))
+		return
+	}
+	if got.Version != 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatUint(got.Version, 10)))
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(got.Value)))
+	_, _ = w.Write(got.Value)
+}
+
+func (g *Gateway) put(w http.ResponseWriter, r *http.Request) {
+	value, err := io.ReadAll(http.MaxBytesReader(w, r.Body, g.config.MaxValueBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			g.writeError(w, status.Errorf(codes.InvalidArgument, "value exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		g.writeError(w, status.Error(codes.InvalidArgument, "failed to read the request body"))
+		return
+	}
+
+	req := &proto.PutRequest{Key: r.PathValue("key"), Value: value}
+	if expected, ok, err := expectedVersion(r); err != nil {
+		g.writeError(w, err)
+		return
+	} else if ok {
+		req.ExpectedVersion = &expected
+	}
+	resp, err := g.call(r, proto.Clavis_Put_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return g.backend.Put(ctx, req.(*proto.PutRequest))
+	})
+	if err != nil {
+		if req.ExpectedVersion != nil && status.Code(err) == codes.Aborted {
+			err = status.Error(codes.FailedPrecondition, status.Convert(err).Message())
+		}
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp.(*proto.PutResponse))
+}
+
+// expectedVersion reads the version a PUT is conditioned on from If-Match,
+// or requires the key not to exist for If-None-Match: *.
+func expectedVersion(r *http.Request) (uint64, bool, error) {
+	if r.Header.Get("If-None-Match") == "*" {
+		return 0, true, nil
+	}
+	match := r.Header.Get("If-Match")
+	if match == "" {
+		return 0, false, nil
+	}
+	tag, err := strconv.Unquote(strings.TrimPrefix(match, "W/"))
+	if err != nil {
+		tag = match
+	}
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil {
+		return 0, false, status.Errorf(codes.InvalidArgument, "If-Match must be an ETag returned by GET, got %q", match)
+	}
+	return version, true, nil
+}
+
+func (g *Gateway) delete(w http.ResponseWriter, r *http.Request) {
+	req := &proto.DeleteRequest{Key: r.PathValue("key")}
+	resp, err := g.call(r, proto.Clavis_Delete_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return g.backend.Delete(ctx, req.(*proto.DeleteRequest))
+	})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp.(*proto.DeleteResponse))
+}
+
+func (g *Gateway) scan(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.ScanRequest{Prefix: query.Get("prefix"), Cursor: query.Get("cursor")}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil {
+			g.writeError(w, status.Errorf(codes.InvalidArgument, "invalid limit %q", limit))
+			return
+		}
+		req.Limit = int32(n)
+	}
+	resp, err := g.call(r, proto.Clavis_Scan_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return g.backend.Scan(ctx, req.(*proto.ScanRequest))
+	})
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp.(*proto.ScanResponse))
+}
+
+// jsonOptions encode responses with the field names of the proto files.
+var jsonOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+func (g *Gateway) writeJSON(w http.ResponseWriter, code int, resp protobuf.Message) {
+	data, err := jsonOptions.Marshal(resp)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+// errorBody is the JSON body of failed responses.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError responds with the HTTP equivalent of a gRPC status, passing on
+// the retry hint of rate limited calls as Retry-After.
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	code := httpStatus(st.Code())
+	if code >= http.StatusInternalServerError {
+		g.logger.Error("HTTP gateway request failed", "code", st.Code().String(), "error", st.Message())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if retryAfter := claviserrors.FromStatus(st).Metadata["retry_after"]; retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorBody{Code: strings.ToUpper(toSnake(st.Code().String())), Message: st.Message()})
+}
+
+// httpStatus maps gRPC codes to HTTP statuses as grpc-gateway does.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// toSnake converts a code name such as InvalidArgument to Invalid_Argument.
+func toSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
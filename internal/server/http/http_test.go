@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	proto "github.com/William-Fernandes252/clavis/internal/server/grpc"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"google.golang.org/grpc"
+)
+
+func startGateway(t *testing.T, interceptors ...grpc.UnaryServerInterceptor) *httptest.Server {
+	t.Helper()
+	kv, err := badger.NewWithPath(filepath.Join(t.TempDir(), "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = kv.Close() })
+	backend, err := proto.New(kv, &proto.GRPCServerConfig{}, grpc.NewServer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(New(backend, Config{Interceptors: interceptors, MaxValueBytes: 16}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, method, url, body string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func TestGateway_Keys(t *testing.T) {
+	server := startGateway(t)
+	url := server.URL + "/v1/keys/"
+
+	if resp, body := do(t, http.MethodGet, url+"user/1", "", nil); resp.StatusCode != http.StatusNotFound || !strings.Contains(body, `"NOT_FOUND"`) {
+		t.Errorf("Expected 404 for a missing key, got %d %s", resp.StatusCode, body)
+	}
+	if resp, body := do(t, http.MethodPut, url+"user/1", "alice", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT failed: %d %s", resp.StatusCode, body)
+	}
+	do(t, http.MethodPut, url+"user/2", "bob", nil)
+
+	resp, body := do(t, http.MethodGet, url+"user/1", "", nil)
+	if resp.StatusCode != http.StatusOK || body != "alice" {
+		t.Fatalf("Expected alice, got %d %q", resp.StatusCode, body)
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag with the version")
+	}
+
+	// Conditional writes
+	if resp, _ := do(t, http.MethodPut, url+"user/1", "x", http.Header{"If-None-Match": {"*"}}); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 creating an existing key, got %d", resp.StatusCode)
+	}
+	if resp, body := do(t, http.MethodPut, url+"user/1", "carol", http.Header{"If-Match": {etag}}); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the write at the current version to succeed, got %d %s", resp.StatusCode, body)
+	}
+	if resp, _ := do(t, http.MethodPut, url+"user/1", "dave", http.Header{"If-Match": {etag}}); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale ETag, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, http.MethodPut, url+"user/1", "a value longer than the limit", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an oversized value, got %d", resp.StatusCode)
+	}
+
+	// Scan
+	resp, body = do(t, http.MethodGet, server.URL+"/v1/keys?prefix=user/&limit=10", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Scan failed: %d %s", resp.StatusCode, body)
+	}
+	var page struct {
+		Items []struct {
+			Key   string `json:"key"`
+			Value []byte `json:"value"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Items[0].Key != "user/1" || string(page.Items[0].Value) != "carol" {
+		t.Errorf("Unexpected scan page %s", body)
+	}
+	if resp, _ := do(t, http.MethodGet, server.URL+"/v1/keys?limit=many", "", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid limit, got %d", resp.StatusCode)
+	}
+
+	if resp, body := do(t, http.MethodDelete, url+"user/1", "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("DELETE failed: %d %s", resp.StatusCode, body)
+	}
+	if resp, _ := do(t, http.MethodGet, url+"user/1", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the deleted key to be gone, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_Auth(t *testing.T) {
+	tokens := auth.NewStaticTokenStore(map[string]string{"secret": "reader"})
+	policy := &auth.Policy{
+		Roles:    map[string][]auth.Grant{"read-users": {{Prefix: "user/", Operations: []auth.Operation{auth.OpRead}}}},
+		Bindings: map[string][]string{"reader": {"read-users"}},
+	}
+	server := startGateway(t,
+		proto.AuthUnaryInterceptor(proto.AuthConfig{Tokens: tokens}),
+		proto.AuthzUnaryInterceptor(proto.AuthzConfig{Policy: policy}),
+	)
+	url := server.URL + "/v1/keys/user/1"
+	bearer := http.Header{"Authorization": {"Bearer secret"}}
+
+	if resp, _ := do(t, http.MethodGet, url, "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, http.MethodGet, url, "", bearer); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the reader to get 404, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, http.MethodGet, url, "", http.Header{"X-Api-Key": {"secret"}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected an API key to authenticate, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(t, http.MethodPut, url, "v", bearer); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a write by the reader, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_RateLimit(t *testing.T) {
+	server := startGateway(t, proto.RateLimitUnaryInterceptor(proto.RateLimitConfig{
+		Default: proto.RateLimit{RequestsPerSecond: 0.5, Burst: 1},
+		By:      proto.RateLimitByIP,
+	}))
+	url := server.URL + "/v1/keys/user/1"
+
+	if resp, _ := do(t, http.MethodGet, url, "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected the first request to be served, got %d", resp.StatusCode)
+	}
+	resp, body := do(t, http.MethodGet, url, "", nil)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("Expected 429 with Retry-After: 2, got %d %q %s", resp.StatusCode, resp.Header.Get("Retry-After"), body)
+	}
+}
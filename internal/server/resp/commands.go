@@ -0,0 +1,559 @@
+package resp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/readonly"
+)
+
+// DefaultScanCount is the number of keys SCAN examines when COUNT is not given.
+const DefaultScanCount = 10
+
+// maxScanCursors bounds the SCAN cursors a connection keeps open.
+const maxScanCursors = 64
+
+// client is the state of one connection.
+type client struct {
+	server        *Server
+	w             writer
+	authenticated bool
+	principal     auth.Principal
+
+	// SCAN cursors are numbers, as Redis clients expect; each maps to the
+	// last key returned under it
+	cursors    map[uint64]string
+	nextCursor uint64
+}
+
+// context returns the context store operations are made with, carrying the
+// authenticated principal.
+func (c *client) context() context.Context {
+	ctx := context.Background()
+	if c.principal.Name != "" {
+		ctx = auth.WithPrincipal(ctx, c.principal)
+	}
+	return ctx
+}
+
+// run executes one command and reports whether the connection should close.
+func (c *client) run(args [][]byte) (quit bool) {
+	name := strings.ToUpper(string(args[0]))
+	args = args[1:]
+
+	switch name {
+	case "PING":
+		switch len(args) {
+		case 0:
+			c.w.simple("PONG")
+		case 1:
+			c.w.bulk(args[0])
+		default:
+			c.wrongArity(name)
+		}
+		return false
+	case "QUIT":
+		c.w.simple("OK")
+		return true
+	case "AUTH":
+		c.auth(args)
+		return false
+	}
+
+	if !c.authenticated {
+		c.w.error("NOAUTH Authentication required.")
+		return false
+	}
+	switch name {
+	case "GET":
+		c.get(args)
+	case "SET":
+		c.set(args)
+	case "DEL":
+		c.del(args)
+	case "SCAN":
+		c.scan(args)
+	case "EXPIRE":
+		c.expire(args)
+	case "TTL":
+		c.ttl(args)
+	default:
+		c.w.error(singleLine("ERR unknown command '" + strings.ToLower(name) + "'"))
+	}
+	return false
+}
+
+func (c *client) wrongArity(name string) {
+	c.w.error("ERR wrong number of arguments for '" + strings.ToLower(name) + "' command")
+}
+
+// storeError reports a failed store operation. Rejected values are reported
+// with their validation message and writes to a read-only server as Redis
+// replicas do; other failures are not detailed.
+func (c *client) storeError(err error) {
+	if errors.Is(err, readonly.ErrReadOnly) {
+		c.w.error("READONLY " + singleLine(err.Error()))
+		return
+	}
+	if len(validation.As(err)) > 0 {
+		c.w.error("ERR " + singleLine(err.Error()))
+		return
+	}
+	c.server.logger.Error("RESP store operation failed", "error", err)
+	c.w.error("ERR internal error")
+}
+
+// singleLine makes message safe to send as a simple string.
+func singleLine(message string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(message)
+}
+
+// allowed checks op on key against the policy and the keys reserved for the
+// server, which may be read but not changed or listed, replying when it is
+// denied.
+func (c *client) allowed(op auth.Operation, key string) bool {
+	if op != auth.OpRead && store.IsReserved(key) {
+		c.w.error(singleLine("ERR key '" + key + "' is reserved for the server"))
+		return false
+	}
+	policy := c.server.config.Policy
+	if policy == nil || policy.Allowed(c.principal, op, key) {
+		return true
+	}
+	c.w.error(singleLine("NOPERM principal '" + c.principal.Name + "' may not " + string(op) + " '" + key + "'"))
+	return false
+}
+
+// auth accepts AUTH token and AUTH username token; the username is ignored,
+// since tokens identify principals.
+func (c *client) auth(args [][]byte) {
+	if len(args) < 1 || len(args) > 2 {
+		c.wrongArity("AUTH")
+		return
+	}
+	if c.server.config.Tokens == nil {
+		c.w.error("ERR AUTH called without any tokens configured")
+		return
+	}
+	principal, ok := c.server.config.Tokens.Lookup(string(args[len(args)-1]))
+	if !ok {
+		c.w.error("WRONGPASS invalid token")
+		return
+	}
+	c.authenticated, c.principal = true, principal
+	c.w.simple("OK")
+}
+
+// get replies with the value of a key, removing it first if it has expired.
+func (c *client) get(args [][]byte) {
+	if len(args) != 1 {
+		c.wrongArity("GET")
+		return
+	}
+	key := string(args[0])
+	if !c.allowed(auth.OpRead, key) {
+		return
+	}
+	if c.expireIfDue(key) {
+		c.w.null()
+		return
+	}
+	// The value is written out in place rather than copied first
+	found, err := store.GetView(c.server.store, key, func(value []byte) error {
+		c.w.bulk(value)
+		return nil
+	})
+	if err != nil {
+		c.storeError(err)
+		return
+	}
+	if !found {
+		c.w.null()
+	}
+}
+
+// set accepts SET key value [EX seconds | PX milliseconds] [NX].
+func (c *client) set(args [][]byte) {
+	if len(args) < 2 {
+		c.wrongArity("SET")
+		return
+	}
+	key, value := string(args[0]), args[1]
+
+	var ttl time.Duration
+	var nx bool
+	for i := 2; i < len(args); i++ {
+		switch option := strings.ToUpper(string(args[i])); option {
+		case "NX":
+			nx = true
+		case "EX", "PX":
+			if ttl != 0 || i+1 == len(args) {
+				c.w.error("ERR syntax error")
+				return
+			}
+			i++
+			n, err := strconv.ParseInt(string(args[i]), 10, 64)
+			if err != nil || n <= 0 {
+				c.w.error("ERR invalid expire time in 'set' command")
+				return
+			}
+			unit := time.Second
+			if option == "PX" {
+				unit = time.Millisecond
+			}
+			ttl = time.Duration(n) * unit
+		default:
+			c.w.error("ERR syntax error")
+			return
+		}
+	}
+	if !c.allowed(auth.OpWrite, key) {
+		return
+	}
+
+	ctx := c.context()
+	var err error
+	if nx {
+		c.expireIfDue(key)
+		err = store.PutIfAbsentContext(ctx, c.server.store, key, value)
+		switch {
+		case errors.Is(err, store.ErrKeyExists):
+			c.w.null()
+			return
+		case errors.Is(err, store.ErrConditionalPutUnsupported):
+			c.w.error("ERR NX is not supported by this store")
+			return
+		}
+	} else {
+		err = store.PutContext(ctx, c.server.store, key, value)
+	}
+	if err != nil {
+		c.storeError(err)
+		return
+	}
+	if ttl > 0 {
+		c.server.expiries.set(key, time.Now().Add(ttl))
+	} else {
+		c.server.expiries.clear(key)
+	}
+	c.w.simple("OK")
+}
+
+// del removes keys and replies with the number that existed.
+func (c *client) del(args [][]byte) {
+	if len(args) == 0 {
+		c.wrongArity("DEL")
+		return
+	}
+	for _, arg := range args {
+		if !c.allowed(auth.OpDelete, string(arg)) {
+			return
+		}
+	}
+
+	ctx := c.context()
+	var deleted int64
+	for _, arg := range args {
+		key := string(arg)
+		if c.expireIfDue(key) {
+			continue
+		}
+		_, found, err := store.GetContext(ctx, c.server.store, key)
+		if err != nil {
+			c.storeError(err)
+			return
+		}
+		if !found {
+			continue
+		}
+		if err := store.DeleteContext(ctx, c.server.store, key); err != nil {
+			c.storeError(err)
+			return
+		}
+		c.server.expiries.clear(key)
+		deleted++
+	}
+	c.w.integer(deleted)
+}
+
+// expire accepts EXPIRE key seconds and replies 1 if the key exists. A
+// non-positive timeout expires the key right away.
+func (c *client) expire(args [][]byte) {
+	if len(args) != 2 {
+		c.wrongArity("EXPIRE")
+		return
+	}
+	key := string(args[0])
+	seconds, err := strconv.ParseInt(string(args[1]), 10, 64)
+	if err != nil {
+		c.w.error("ERR value is not an integer or out of range")
+		return
+	}
+	if !c.allowed(auth.OpDelete, key) {
+		return
+	}
+	if c.expireIfDue(key) {
+		c.w.integer(0)
+		return
+	}
+	_, found, err := store.GetContext(c.context(), c.server.store, key)
+	if err != nil {
+		c.storeError(err)
+		return
+	}
+	if !found {
+		c.w.integer(0)
+		return
+	}
+
+	if seconds <= 0 {
+		c.server.expiries.clear(key)
+		if err := store.ExpireKeys(c.server.store, []string{key}); err != nil {
+			c.storeError(err)
+			return
+		}
+	} else {
+		c.server.expiries.set(key, time.Now().Add(time.Duration(seconds)*time.Second))
+	}
+	c.w.integer(1)
+}
+
+// ttl replies with the seconds left before a key expires, -1 if it does not
+// expire and -2 if it does not exist.
+func (c *client) ttl(args [][]byte) {
+	if len(args) != 1 {
+		c.wrongArity("TTL")
+		return
+	}
+	key := string(args[0])
+	if !c.allowed(auth.OpRead, key) {
+		return
+	}
+	if c.expireIfDue(key) {
+		c.w.integer(-2)
+		return
+	}
+	_, found, err := store.GetContext(c.context(), c.server.store, key)
+	if err != nil {
+		c.storeError(err)
+		return
+	}
+	if !found {
+		c.w.integer(-2)
+		return
+	}
+	deadline, ok := c.server.expiries.deadline(key)
+	if !ok {
+		c.w.integer(-1)
+		return
+	}
+	c.w.integer(int64((time.Until(deadline) + time.Second - 1) / time.Second))
+}
+
+// expireIfDue removes key as expired if its deadline has passed, reporting
+// whether it did.
+func (c *client) expireIfDue(key string) bool {
+	if !c.server.expiries.due(key, time.Now()) {
+		return false
+	}
+	c.server.expiries.clear(key)
+	if err := store.ExpireKeys(c.server.store, []string{key}); err != nil {
+		c.server.logger.Warn("Failed to remove expired key", "key", key, "error", err)
+	}
+	return true
+}
+
+// scan accepts SCAN cursor [MATCH pattern] [COUNT count]. Cursors other than
+// 0 are only valid on the connection that received them.
+func (c *client) scan(args [][]byte) {
+	if len(args) == 0 {
+		c.wrongArity("SCAN")
+		return
+	}
+	cursor, err := strconv.ParseUint(string(args[0]), 10, 64)
+	if err != nil {
+		c.w.error("ERR invalid cursor")
+		return
+	}
+	pattern, count := "*", DefaultScanCount
+	for i := 1; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			c.w.error("ERR syntax error")
+			return
+		}
+		switch strings.ToUpper(string(args[i])) {
+		case "MATCH":
+			pattern = string(args[i+1])
+		case "COUNT":
+			count, err = strconv.Atoi(string(args[i+1]))
+			if err != nil || count < 1 {
+				c.w.error("ERR syntax error")
+				return
+			}
+		default:
+			c.w.error("ERR syntax error")
+			return
+		}
+	}
+
+	after := ""
+	if cursor != 0 {
+		var ok bool
+		if after, ok = c.cursors[cursor]; !ok {
+			c.w.error("ERR invalid cursor")
+			return
+		}
+		delete(c.cursors, cursor)
+	}
+	prefix, match := compileGlob(pattern)
+	if !c.allowed(auth.OpScan, prefix) {
+		return
+	}
+
+	keys, expired, last, more, err := c.scanPage(prefix, after, count, match)
+	if err != nil {
+		c.storeError(err)
+		return
+	}
+	for _, key := range expired {
+		c.expireIfDue(key)
+	}
+	next := "0"
+	if more {
+		next = strconv.FormatUint(c.saveCursor(last), 10)
+	}
+	c.w.array(2)
+	c.w.bulk([]byte(next))
+	c.w.array(len(keys))
+	for _, key := range keys {
+		c.w.bulk([]byte(key))
+	}
+}
+
+// scanPage examines up to count keys under prefix after the given key and
+// returns those matching, those past their deadline, the last key examined
+// and whether any are left. Expired keys are left for the caller to remove
+// once the iterator is closed.
+func (c *client) scanPage(prefix, after string, count int, match *regexp.Regexp) (keys, expired []string, last string, more bool, err error) {
+	it, err := store.NewIterator(c.server.store, prefix)
+	if err != nil {
+		return nil, nil, "", false, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	now := time.Now()
+	examined := 0
+	for it.Next() {
+		key := it.Key()
+		if after != "" && key <= after {
+			continue
+		}
+		if examined == count {
+			more = true
+			break
+		}
+		examined++
+		last = key
+		if c.server.expiries.due(key, now) {
+			expired = append(expired, key)
+			continue
+		}
+		if match == nil || match.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, "", false, err
+	}
+	return keys, expired, last, more, nil
+}
+
+// saveCursor stores the position of an unfinished scan under a new cursor,
+// forgetting the oldest cursors beyond maxScanCursors.
+func (c *client) saveCursor(last string) uint64 {
+	if c.cursors == nil {
+		c.cursors = make(map[uint64]string)
+	}
+	c.nextCursor++
+	c.cursors[c.nextCursor] = last
+	delete(c.cursors, c.nextCursor-maxScanCursors)
+	return c.nextCursor
+}
+
+// compileGlob converts a Redis glob pattern into the literal prefix every
+// match starts with and a regular expression, or nil when the prefix alone
+// decides a match.
+func compileGlob(pattern string) (prefix string, match *regexp.Regexp) {
+	var literal strings.Builder
+	i := 0
+	for ; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '\\' && i+1 < len(pattern) {
+			i++
+			literal.WriteByte(pattern[i])
+			continue
+		}
+		if ch == '*' || ch == '?' || ch == '[' {
+			break
+		}
+		literal.WriteByte(ch)
+	}
+	prefix = literal.String()
+	if pattern[i:] == "*" {
+		return prefix, nil
+	}
+	if i == len(pattern) {
+		return prefix, regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "$")
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^" + regexp.QuoteMeta(prefix))
+	for ; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			expr.WriteString("(?s:.*)")
+		case '?':
+			expr.WriteString("(?s:.)")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			// QuoteMeta leaves '-', so ranges such as a-z keep their meaning
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	match, err := regexp.Compile(expr.String())
+	if err != nil {
+		// An unusable class matches nothing, as in Redis
+		return prefix, regexp.MustCompile(`[^\x00-\x{10FFFF}]`)
+	}
+	return prefix, match
+}
@@ -0,0 +1,59 @@
+package resp
+
+import (
+	"sync"
+	"time"
+)
+
+// expiries holds the deadlines set with EXPIRE and SET EX. They live in
+// memory only: after a restart keys no longer expire.
+type expiries struct {
+	mu        sync.Mutex
+	deadlines map[string]time.Time
+}
+
+func newExpiries() *expiries {
+	return &expiries{deadlines: make(map[string]time.Time)}
+}
+
+// set makes key expire at deadline.
+func (e *expiries) set(key string, deadline time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deadlines[key] = deadline
+}
+
+// clear removes the deadline of key, if any.
+func (e *expiries) clear(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.deadlines, key)
+}
+
+// deadline returns the deadline of key, if it has one.
+func (e *expiries) deadline(key string) (time.Time, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	deadline, ok := e.deadlines[key]
+	return deadline, ok
+}
+
+// due reports whether key has a deadline at or before now.
+func (e *expiries) due(key string, now time.Time) bool {
+	deadline, ok := e.deadline(key)
+	return ok && !deadline.After(now)
+}
+
+// take removes and returns the keys whose deadline is at or before now.
+func (e *expiries) take(now time.Time) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var keys []string
+	for key, deadline := range e.deadlines {
+		if !deadline.After(now) {
+			keys = append(keys, key)
+			delete(e.deadlines, key)
+		}
+	}
+	return keys
+}
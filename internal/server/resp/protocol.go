@@ -0,0 +1,110 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// errProtocol marks malformed input, after which the connection is closed.
+var errProtocol = errors.New("protocol error")
+
+// readCommand reads one command, either as an array of bulk strings or as an
+// inline command line. It returns no arguments for blank inline lines.
+func readCommand(r *bufio.Reader, maxBulkBytes int) ([][]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return bytes.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(string(line[1:]))
+	if err != nil || n < 0 || n > maxArgs {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errProtocol)
+	}
+	args := make([][]byte, n)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(header) == 0 || header[0] != '$' {
+			return nil, fmt.Errorf("%w: expected '$', got %q", errProtocol, header)
+		}
+		size, err := strconv.Atoi(string(header[1:]))
+		if err != nil || size < 0 || size > maxBulkBytes {
+			return nil, fmt.Errorf("%w: invalid bulk length", errProtocol)
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		if arg[size] != '\r' || arg[size+1] != '\n' {
+			return nil, fmt.Errorf("%w: bulk string is not terminated by CRLF", errProtocol)
+		}
+		args[i] = arg[:size]
+	}
+	return args, nil
+}
+
+// maxArgs bounds the number of arguments of a command.
+const maxArgs = 1 << 20
+
+// maxLineBytes bounds headers and inline commands.
+const maxLineBytes = 64 << 10
+
+// readLine reads a line terminated by CRLF or LF, without the terminator.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxLineBytes {
+			return nil, fmt.Errorf("%w: line too long", errProtocol)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// writer encodes replies.
+type writer struct {
+	*bufio.Writer
+}
+
+func (w writer) simple(s string) {
+	_, _ = w.WriteString("+" + s + "\r\n")
+}
+
+// error writes an error reply. Messages start with an error code such as
+// ERR or NOAUTH.
+func (w writer) error(message string) {
+	_, _ = w.WriteString("-" + message + "\r\n")
+}
+
+func (w writer) integer(n int64) {
+	_, _ = w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+func (w writer) bulk(b []byte) {
+	_, _ = w.WriteString("$" + strconv.Itoa(len(b)) + "\r\n")
+	_, _ = w.Write(b)
+	_, _ = w.WriteString("\r\n")
+}
+
+func (w writer) null() {
+	_, _ = w.WriteString("$-1\r\n")
+}
+
+func (w writer) array(n int) {
+	_, _ = w.WriteString("*" + strconv.Itoa(n) + "\r\n")
+}
@@ -0,0 +1,245 @@
+// Package resp serves a subset of the Redis protocol (RESP2) on top of a
+// store.Store, so existing Redis clients can read and write Clavis keys
+// without gRPC. It supports GET, SET, DEL, SCAN, EXPIRE and TTL, plus PING,
+// AUTH and QUIT for connection handling.
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// DefaultExpiryInterval is the time between sweeps removing expired keys.
+	DefaultExpiryInterval = time.Second
+	// DefaultMaxBulkBytes is the largest bulk string a command may carry.
+	DefaultMaxBulkBytes = 64 << 20
+)
+
+// Config configures a RESP server.
+type Config struct {
+	Addr           string          // Address to listen on, such as ":6379"
+	Tokens         auth.TokenStore // When set, clients must AUTH with one of the tokens before other commands
+	Policy         *auth.Policy    // When set, authenticated principals are limited to the operations it grants
+	ExpiryInterval time.Duration   // Time between expiry sweeps; DefaultExpiryInterval if zero
+	MaxBulkBytes   int             // Largest accepted bulk string; DefaultMaxBulkBytes if zero
+	Logger         *slog.Logger    // Logger for connection errors; slog.Default() if nil
+}
+
+// Server serves RESP clients. Unlike the gRPC server, it does not own the
+// store: Shutdown leaves it open.
+type Server struct {
+	store    store.Store
+	config   Config
+	logger   *slog.Logger
+	expiries *expiries
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closing  bool
+	active   sync.WaitGroup
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// New creates a RESP server for s.
+func New(s store.Store, config Config) *Server {
+	if config.ExpiryInterval <= 0 {
+		config.ExpiryInterval = DefaultExpiryInterval
+	}
+	if config.MaxBulkBytes <= 0 {
+		config.MaxBulkBytes = DefaultMaxBulkBytes
+	}
+	return &Server{
+		store:    s,
+		config:   config,
+		logger:   logging.Or(config.Logger),
+		expiries: newExpiries(),
+		conns:    make(map[net.Conn]struct{}),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start listens on the configured address and serves connections until
+// Shutdown is called. Callbacks are run once the server is listening.
+func (s *Server) Start(callbacks ...func()) error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+	for _, callback := range callbacks {
+		callback()
+	}
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on listener until Shutdown is called, then
+// returns nil.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		_ = listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	go s.sweep()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closing := s.closing
+			s.mu.Unlock()
+			if closing {
+				return nil
+			}
+			return err
+		}
+		if !s.track(conn) {
+			_ = conn.Close()
+			return nil
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Addr returns the address the server listens on, or nil before Serve.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// track registers an accepted connection, unless the server is closing.
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.active.Add(1)
+	return true
+}
+
+// Shutdown stops accepting connections and lets each open connection finish
+// the command it is running. Connections still open when ctx ends are closed
+// forcibly and an error is returned. The store is left open.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closing = true
+	if s.listener != nil {
+		_ = s.listener.Close()
+		close(s.stop)
+	} else {
+		close(s.stopped)
+	}
+	// Unblock connections waiting for their next command
+	for conn := range s.conns {
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+	<-s.stopped
+
+	drained := make(chan struct{})
+	go func() {
+		s.active.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("connections did not drain: %w", ctx.Err())
+	}
+}
+
+// sweep removes expired keys until Shutdown.
+func (s *Server) sweep() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(s.config.ExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			if keys := s.expiries.take(now); len(keys) > 0 {
+				if err := store.ExpireKeys(s.store, keys); err != nil {
+					s.logger.Warn("Failed to remove expired keys", "keys", len(keys), "error", err)
+				}
+			}
+		}
+	}
+}
+
+// serveConn runs the commands of one connection until it quits or fails.
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.active.Done()
+	}()
+
+	c := &client{server: s, w: writer{bufio.NewWriter(conn)}}
+	if s.config.Tokens == nil {
+		c.authenticated = true
+	}
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r, s.config.MaxBulkBytes)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				c.w.error("ERR " + err.Error())
+				_ = c.w.Flush()
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) && !errors.Is(err, os.ErrDeadlineExceeded) {
+				s.logger.Debug("RESP connection failed", "remote", conn.RemoteAddr().String(), "error", err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := c.run(args)
+		// Replies to pipelined commands are flushed together
+		if r.Buffered() == 0 || quit {
+			if err := c.w.Flush(); err != nil {
+				return
+			}
+		}
+		if quit {
+			return
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+)
+
+// testClient speaks RESP to a server over TCP.
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// do sends a command and decodes the reply into a string, an int64, nil, a
+// slice of replies or an error reply prefixed with "-".
+func (c *testClient) do(args ...string) any {
+	c.t.Helper()
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		c.t.Fatal(err)
+	}
+	return c.read()
+}
+
+func (c *testClient) read() any {
+	c.t.Helper()
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return line
+	case ':':
+		n, _ := strconv.ParseInt(line[1:], 10, 64)
+		return n
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			c.t.Fatal(err)
+		}
+		return string(buf[:n])
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		items := make([]any, n)
+		for i := range items {
+			items[i] = c.read()
+		}
+		return items
+	}
+	c.t.Fatalf("Unexpected reply %q", line)
+	return nil
+}
+
+func startServer(t *testing.T, config Config) (*Server, *events.Bus, func() *testClient) {
+	t.Helper()
+	kv, err := badger.NewWithPath(filepath.Join(t.TempDir(), "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = kv.Close() })
+	bus := events.NewBus(events.DefaultBufferSize)
+	t.Cleanup(bus.Close)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := New(events.NewPublishingStore(kv, bus), config)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	dial := func() *testClient {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+	}
+	return server, bus, dial
+}
+
+func TestServer_Commands(t *testing.T) {
+	_, _, dial := startServer(t, Config{})
+	c := dial()
+
+	steps := []struct {
+		args []string
+		want any
+	}{
+		{[]string{"PING"}, "PONG"},
+		{[]string{"SET", "user:1", "alice"}, "OK"},
+		{[]string{"SET", "user:2", "bob"}, "OK"},
+		{[]string{"SET", "order:1", "x"}, "OK"},
+		{[]string{"GET", "user:1"}, "alice"},
+		{[]string{"GET", "missing"}, nil},
+		{[]string{"SET", "user:1", "other", "NX"}, nil},
+		{[]string{"SET", "user:3", "carol", "NX"}, "OK"},
+		{[]string{"TTL", "user:1"}, int64(-1)},
+		{[]string{"TTL", "missing"}, int64(-2)},
+		{[]string{"DEL", "user:3", "missing"}, int64(1)},
+		{[]string{"SCAN", "0", "MATCH", "user:*", "COUNT", "100"}, []any{"0", []any{"user:1", "user:2"}}},
+		{[]string{"SCAN", "0", "MATCH", "*:1"}, []any{"0", []any{"order:1", "user:1"}}},
+		{[]string{"SET", "k", "v", "EX"}, "-ERR syntax error"},
+		{[]string{"GET"}, "-ERR wrong number of arguments for 'get' command"},
+		{[]string{"FLUSHALL"}, "-ERR unknown command 'flushall'"},
+		{[]string{"SET", "__clavis/settings/current/x", "{}"}, "-ERR key '__clavis/settings/current/x' is reserved for the server"},
+		{[]string{"DEL", "__clavis/settings/current/x"}, "-ERR key '__clavis/settings/current/x' is reserved for the server"},
+		{[]string{"GET", "__clavis/settings/current/x"}, nil},
+	}
+	for _, step := range steps {
+		if got := c.do(step.args...); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%v: expected %#v, got %#v", step.args, step.want, got)
+		}
+	}
+
+	// Inline commands, as typed into telnet
+	if _, err := c.conn.Write([]byte("get user:2\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.read(); got != "bob" {
+		t.Errorf("Expected bob from an inline GET, got %#v", got)
+	}
+}
+
+func TestServer_ScanCursors(t *testing.T) {
+	_, _, dial := startServer(t, Config{})
+	c := dial()
+	var want []string
+	for i := range 25 {
+		key := fmt.Sprintf("key:%02d", i)
+		want = append(want, key)
+		c.do("SET", key, "v")
+	}
+
+	var got []string
+	cursor := "0"
+	for pages := 1; ; pages++ {
+		reply := c.do("SCAN", cursor, "COUNT", "10").([]any)
+		for _, key := range reply[1].([]any) {
+			got = append(got, key.(string))
+		}
+		if cursor = reply[0].(string); cursor == "0" {
+			if pages != 3 {
+				t.Errorf("Expected 3 pages, got %d", pages)
+			}
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := c.do("SCAN", "12345"); got != "-ERR invalid cursor" {
+		t.Errorf("Expected an unknown cursor to be rejected, got %#v", got)
+	}
+}
+
+func TestServer_Expire(t *testing.T) {
+	_, bus, dial := startServer(t, Config{ExpiryInterval: 10 * time.Millisecond})
+	expired := make(chan string, 10)
+	bus.Subscribe(func(e events.Event) { expired <- e.Key }, events.TypeExpire)
+	c := dial()
+
+	c.do("SET", "session", "s")
+	if got := c.do("EXPIRE", "session", "1"); got != int64(1) {
+		t.Fatalf("Expected EXPIRE to find the key, got %#v", got)
+	}
+	if got := c.do("TTL", "session"); got != int64(1) {
+		t.Errorf("Expected a TTL of 1, got %#v", got)
+	}
+	if got := c.do("EXPIRE", "missing", "1"); got != int64(0) {
+		t.Errorf("Expected EXPIRE of a missing key to return 0, got %#v", got)
+	}
+
+	c.do("SET", "short", "s", "PX", "20")
+	select {
+	case key := <-expired:
+		if key != "short" {
+			t.Errorf("Expected short to expire first, got %q", key)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the key to be expired by the sweeper")
+	}
+	if got := c.do("GET", "short"); got != nil {
+		t.Errorf("Expected the expired key to be gone, got %#v", got)
+	}
+
+	// Overwriting a key clears its deadline
+	c.do("SET", "session", "renewed")
+	if got := c.do("TTL", "session"); got != int64(-1) {
+		t.Errorf("Expected SET to clear the deadline, got %#v", got)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	tokens := auth.NewStaticTokenStore(map[string]string{"secret": "reader"})
+	policy := &auth.Policy{
+		Roles:    map[string][]auth.Grant{"read-users": {{Prefix: "user:", Operations: []auth.Operation{auth.OpRead, auth.OpScan}}}},
+		Bindings: map[string][]string{"reader": {"read-users"}},
+	}
+	_, _, dial := startServer(t, Config{Tokens: tokens, Policy: policy})
+	c := dial()
+
+	steps := []struct {
+		args []string
+		want any
+	}{
+		{[]string{"PING"}, "PONG"},
+		{[]string{"GET", "user:1"}, "-NOAUTH Authentication required."},
+		{[]string{"AUTH", "wrong"}, "-WRONGPASS invalid token"},
+		{[]string{"AUTH", "default", "secret"}, "OK"},
+		{[]string{"GET", "user:1"}, nil},
+		{[]string{"SCAN", "0", "MATCH", "user:*"}, []any{"0", []any{}}},
+		{[]string{"SET", "user:1", "v"}, "-NOPERM principal 'reader' may not write 'user:1'"},
+		{[]string{"SCAN", "0"}, "-NOPERM principal 'reader' may not scan ''"},
+	}
+	for _, step := range steps {
+		if got := c.do(step.args...); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%v: expected %#v, got %#v", step.args, step.want, got)
+		}
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	server, _, dial := startServer(t, Config{})
+	c := dial()
+	c.do("PING")
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := c.r.ReadByte(); err == nil {
+		t.Error("Expected idle connections to be closed")
+	}
+	if _, err := net.Dial("tcp", server.Addr().String()); err == nil {
+		t.Error("Expected the listener to be closed")
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		prefix    string
+		matches   []string
+		unmatches []string
+	}{
+		{pattern: "*", prefix: ""},
+		{pattern: "user:*", prefix: "user:"},
+		{pattern: "user:?", prefix: "user:", matches: []string{"user:1"}, unmatches: []string{"user:10"}},
+		{pattern: "*:1", prefix: "", matches: []string{"a/b:1"}, unmatches: []string{"a:2"}},
+		{pattern: "h[ae]llo", prefix: "h", matches: []string{"hallo", "hello"}, unmatches: []string{"hillo"}},
+		{pattern: "h[^e]llo", prefix: "h", matches: []string{"hallo"}, unmatches: []string{"hello"}},
+		{pattern: "v[0-9]", prefix: "v", matches: []string{"v7"}, unmatches: []string{"vx"}},
+		{pattern: `a\*b`, prefix: "a*b", matches: []string{"a*b"}, unmatches: []string{"a*bc"}},
+	}
+	for _, tt := range tests {
+		prefix, match := compileGlob(tt.pattern)
+		if prefix != tt.prefix {
+			t.Errorf("%q: expected prefix %q, got %q", tt.pattern, tt.prefix, prefix)
+		}
+		for _, key := range tt.matches {
+			if match != nil && !match.MatchString(key) {
+				t.Errorf("%q: expected %q to match", tt.pattern, key)
+			}
+		}
+		for _, key := range tt.unmatches {
+			if match == nil || match.MatchString(key) {
+				t.Errorf("%q: expected %q not to match", tt.pattern, key)
+			}
+		}
+	}
+}
@@ -1,14 +1,18 @@
 package server
 
-import "github.com/William-Fernandes252/clavis/internal/store"
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
 
 // Interface for a key-value store server.
 type Server interface {
 	// Start the server and listen for incoming requests
 	Start(callback ...func()) error
 
-	// Stop the server gracefully
-	Shutdown()
+	// Stop the server, draining in-flight requests until ctx ends, and close the store
+	Shutdown(ctx context.Context) error
 
 	// Get the underlying store instance
 	GetStore() (store.Store, error)
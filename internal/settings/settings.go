@@ -0,0 +1,330 @@
+// Package settings manages typed per-namespace settings that are stored in
+// the key-value store itself under an internal prefix.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// Prefix is the internal key prefix under which settings are stored.
+	Prefix = store.ReservedPrefix + "settings/"
+
+	currentPrefix = Prefix + "current/"
+	historyPrefix = Prefix + "history/"
+)
+
+var (
+	// ErrInvalidNamespace is returned for namespace names that cannot be used as settings keys.
+	ErrInvalidNamespace = errors.New("invalid namespace name")
+	// ErrVersionConflict is returned when an update is based on a stale version.
+	ErrVersionConflict = errors.New("settings version conflict")
+	// ErrInvalidSettings is returned when an update carries out-of-range values.
+	ErrInvalidSettings = errors.New("invalid settings")
+	// ErrNamespaceExists is returned when creating a namespace that already exists.
+	ErrNamespaceExists = errors.New("namespace already exists")
+)
+
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// NamespaceSettings holds the tunables for a single namespace.
+type NamespaceSettings struct {
+	Namespace         string        `json:"namespace"`
+	DefaultTTL        time.Duration `json:"default_ttl,omitempty"`
+	MaxKeys           int64         `json:"max_keys,omitempty"`
+	MaxBytes          int64         `json:"max_bytes,omitempty"`
+	ValidationProfile string        `json:"validation_profile,omitempty"`
+	ReadOnly          bool          `json:"read_only,omitempty"`
+	RetainVersions    int           `json:"retain_versions,omitempty"`
+	RetainFor         time.Duration `json:"retain_for,omitempty"`
+	Version           uint64        `json:"version"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	UpdatedBy         string        `json:"updated_by,omitempty"`
+}
+
+// NamespaceSeparator ends the namespace part of a key. A key belongs to the
+// namespace named by everything before its first separator.
+const NamespaceSeparator = "/"
+
+// NamespaceOf returns the namespace key belongs to, if any.
+func NamespaceOf(key string) (string, bool) {
+	namespace, _, found := strings.Cut(key, NamespaceSeparator)
+	if !found || ValidateNamespace(namespace) != nil {
+		return "", false
+	}
+	return namespace, true
+}
+
+// ValidateNamespace reports whether name can be used as a namespace. The
+// keys of the server are not a namespace, so clients cannot reach them
+// through one.
+func ValidateNamespace(name string) error {
+	if !namespacePattern.MatchString(name) || store.IsReserved(name+NamespaceSeparator) {
+		return fmt.Errorf("%w: %q", ErrInvalidNamespace, name)
+	}
+	return nil
+}
+
+// Manager reads and writes namespace settings, caching them in memory.
+// The cache is invalidated by watching mutation events on the settings prefix,
+// so edits made through any path that publishes to the bus are picked up.
+type Manager struct {
+	store       store.Store
+	mu          sync.RWMutex
+	cache       map[string]NamespaceSettings
+	unsubscribe func()
+}
+
+// NewManager creates a manager backed by s. If bus is not nil the cache is
+// invalidated whenever a settings key is written or deleted.
+func NewManager(s store.Store, bus *events.Bus) *Manager {
+	m := &Manager{
+		store:       s,
+		cache:       make(map[string]NamespaceSettings),
+		unsubscribe: func() {},
+	}
+	if bus != nil {
+		m.unsubscribe = bus.Subscribe(m.invalidate, events.TypePut, events.TypeDelete, events.TypeExpire)
+	}
+	return m
+}
+
+// Close stops watching for invalidations.
+func (m *Manager) Close() {
+	m.unsubscribe()
+}
+
+func (m *Manager) invalidate(e events.Event) {
+	if !strings.HasPrefix(e.Key, currentPrefix) {
+		return
+	}
+	m.mu.Lock()
+	delete(m.cache, strings.TrimPrefix(e.Key, currentPrefix))
+	m.mu.Unlock()
+}
+
+// RetentionPolicy returns the version retention configured for the namespace
+// key belongs to. Keys outside any namespace, and keys whose settings cannot
+// be read, keep their whole history.
+func (m *Manager) RetentionPolicy(key string) store.RetentionPolicy {
+	namespace, ok := NamespaceOf(key)
+	if !ok {
+		return store.RetentionPolicy{}
+	}
+	current, err := m.Get(namespace)
+	if err != nil {
+		return store.RetentionPolicy{}
+	}
+	return store.RetentionPolicy{KeepVersions: current.RetainVersions, MaxAge: current.RetainFor}
+}
+
+// ValidationProfile returns the name of the validation profile configured for
+// namespace, or "" if none is configured or its settings cannot be read.
+func (m *Manager) ValidationProfile(namespace string) string {
+	current, err := m.Get(namespace)
+	if err != nil {
+		return ""
+	}
+	return current.ValidationProfile
+}
+
+// Get returns the settings for namespace. A namespace that was never
+// configured yields zero settings with Version 0.
+func (m *Manager) Get(namespace string) (NamespaceSettings, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return NamespaceSettings{}, err
+	}
+
+	m.mu.RLock()
+	cached, ok := m.cache[namespace]
+	m.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	current, err := m.load(namespace)
+	if err != nil {
+		return NamespaceSettings{}, err
+	}
+
+	m.mu.Lock()
+	m.cache[namespace] = current
+	m.mu.Unlock()
+	return current, nil
+}
+
+func (m *Manager) load(namespace string) (NamespaceSettings, error) {
+	raw, found, err := m.store.Get(currentPrefix + namespace)
+	if err != nil {
+		return NamespaceSettings{}, fmt.Errorf("failed to read settings for %s: %w", namespace, err)
+	}
+	if !found {
+		return NamespaceSettings{Namespace: namespace}, nil
+	}
+
+	var current NamespaceSettings
+	if err := json.Unmarshal(raw, &current); err != nil {
+		return NamespaceSettings{}, fmt.Errorf("corrupt settings for %s: %w", namespace, err)
+	}
+	return current, nil
+}
+
+// Set replaces the settings for namespace. If expectedVersion is not zero the
+// update only succeeds when it matches the stored version. Every accepted
+// update bumps the version and is appended to the namespace history.
+func (m *Manager) Set(namespace string, next NamespaceSettings, expectedVersion uint64, updatedBy string) (NamespaceSettings, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return NamespaceSettings{}, err
+	}
+	if next.RetainVersions < 0 || next.RetainFor < 0 {
+		return NamespaceSettings{}, fmt.Errorf("%w: retention limits cannot be negative", ErrInvalidSettings)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, err := m.load(namespace)
+	if err != nil {
+		return NamespaceSettings{}, err
+	}
+	if expectedVersion != 0 && expectedVersion != current.Version {
+		return NamespaceSettings{}, fmt.Errorf("%w: expected version %d, current is %d", ErrVersionConflict, expectedVersion, current.Version)
+	}
+	return m.write(namespace, current, next, updatedBy)
+}
+
+// write stores next as the settings following current. The caller holds mu.
+func (m *Manager) write(namespace string, current, next NamespaceSettings, updatedBy string) (NamespaceSettings, error) {
+	next.Namespace = namespace
+	next.Version = current.Version + 1
+	next.UpdatedAt = time.Now().UTC()
+	next.UpdatedBy = updatedBy
+
+	raw, err := json.Marshal(next)
+	if err != nil {
+		return NamespaceSettings{}, err
+	}
+	if err := m.store.Put(historyKey(namespace, next.Version), raw); err != nil {
+		return NamespaceSettings{}, fmt.Errorf("failed to record settings history for %s: %w", namespace, err)
+	}
+	if err := m.store.Put(currentPrefix+namespace, raw); err != nil {
+		return NamespaceSettings{}, fmt.Errorf("failed to write settings for %s: %w", namespace, err)
+	}
+
+	m.cache[namespace] = next
+	return next, nil
+}
+
+// Create creates namespace with the given initial settings. A namespace
+// exists once it has settings, whether set here or with Set.
+func (m *Manager) Create(namespace string, initial NamespaceSettings, createdBy string) (NamespaceSettings, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return NamespaceSettings{}, err
+	}
+	if initial.RetainVersions < 0 || initial.RetainFor < 0 {
+		return NamespaceSettings{}, fmt.Errorf("%w: retention limits cannot be negative", ErrInvalidSettings)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, err := m.load(namespace)
+	if err != nil {
+		return NamespaceSettings{}, err
+	}
+	if current.Version != 0 {
+		return NamespaceSettings{}, fmt.Errorf("%w: %s", ErrNamespaceExists, namespace)
+	}
+	return m.write(namespace, current, initial, createdBy)
+}
+
+// Exists reports whether namespace has been created.
+func (m *Manager) Exists(namespace string) (bool, error) {
+	current, err := m.Get(namespace)
+	if err != nil {
+		return false, err
+	}
+	return current.Version != 0, nil
+}
+
+// List returns the settings of every namespace, in name order.
+func (m *Manager) List() ([]NamespaceSettings, error) {
+	entries, err := m.store.Scan(currentPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaces := make([]NamespaceSettings, 0, len(entries))
+	for key, raw := range entries {
+		var entry NamespaceSettings
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, fmt.Errorf("corrupt settings for %s: %w", strings.TrimPrefix(key, currentPrefix), err)
+		}
+		namespaces = append(namespaces, entry)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Namespace < namespaces[j].Namespace })
+	return namespaces, nil
+}
+
+// Delete removes the settings of namespace along with their history.
+func (m *Manager) Delete(namespace string) error {
+	if err := ValidateNamespace(namespace); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history, err := m.store.Scan(historyPrefix + namespace + "/")
+	if err != nil {
+		return fmt.Errorf("failed to read settings history for %s: %w", namespace, err)
+	}
+	keys := make([]string, 0, len(history)+1)
+	keys = append(keys, currentPrefix+namespace)
+	for key := range history {
+		keys = append(keys, key)
+	}
+	if err := store.BatchDelete(m.store, keys); err != nil {
+		return fmt.Errorf("failed to delete settings for %s: %w", namespace, err)
+	}
+
+	delete(m.cache, namespace)
+	return nil
+}
+
+// History returns every recorded version of the settings for namespace,
+// oldest first.
+func (m *Manager) History(namespace string) ([]NamespaceSettings, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	entries, err := m.store.Scan(historyPrefix + namespace + "/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings history for %s: %w", namespace, err)
+	}
+
+	history := make([]NamespaceSettings, 0, len(entries))
+	for key, raw := range entries {
+		var entry NamespaceSettings
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, fmt.Errorf("corrupt settings history entry %s: %w", key, err)
+		}
+		history = append(history, entry)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Version < history[j].Version })
+	return history, nil
+}
+
+func historyKey(namespace string, version uint64) string {
+	return fmt.Sprintf("%s%s/%020d", historyPrefix, namespace, version)
+}
@@ -0,0 +1,222 @@
+package settings
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func createTestManager(t *testing.T) (*Manager, *events.PublishingStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := events.NewBus(0)
+	s := events.NewPublishingStore(base, bus)
+	m := NewManager(s, bus)
+	t.Cleanup(func() {
+		m.Close()
+		bus.Close()
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	})
+	return m, s
+}
+
+func TestManager_GetAndSet(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	t.Run("UnconfiguredNamespace", func(t *testing.T) {
+		got, err := m.Get("tenant-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Version != 0 || got.Namespace != "tenant-a" {
+			t.Errorf("Expected zero settings, got %+v", got)
+		}
+	})
+
+	t.Run("SetBumpsVersion", func(t *testing.T) {
+		first, err := m.Set("tenant-a", NamespaceSettings{MaxKeys: 10, DefaultTTL: time.Minute}, 0, "tester")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.Version != 1 || first.UpdatedBy != "tester" {
+			t.Errorf("Unexpected settings after first update: %+v", first)
+		}
+
+		second, err := m.Set("tenant-a", NamespaceSettings{MaxKeys: 20, ReadOnly: true}, 1, "tester")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.Version != 2 {
+			t.Errorf("Expected version 2, got %d", second.Version)
+		}
+
+		got, err := m.Get("tenant-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.MaxKeys != 20 || !got.ReadOnly {
+			t.Errorf("Unexpected settings: %+v", got)
+		}
+	})
+
+	t.Run("StaleVersionRejected", func(t *testing.T) {
+		_, err := m.Set("tenant-a", NamespaceSettings{MaxKeys: 30}, 1, "tester")
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("Expected ErrVersionConflict, got %v", err)
+		}
+	})
+
+	t.Run("InvalidNamespace", func(t *testing.T) {
+		if _, err := m.Get("bad/name"); !errors.Is(err, ErrInvalidNamespace) {
+			t.Errorf("Expected ErrInvalidNamespace, got %v", err)
+		}
+		// The server's own keys would be its keys
+		if _, err := m.Get("__clavis"); !errors.Is(err, ErrInvalidNamespace) {
+			t.Errorf("Expected ErrInvalidNamespace for the reserved prefix, got %v", err)
+		}
+	})
+}
+
+func TestManager_History(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	for i := int64(1); i <= 3; i++ {
+		if _, err := m.Set("ns", NamespaceSettings{MaxKeys: i}, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := m.History("ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(history))
+	}
+	for i, entry := range history {
+		if entry.Version != uint64(i+1) || entry.MaxKeys != int64(i+1) {
+			t.Errorf("Unexpected history entry %d: %+v", i, entry)
+		}
+	}
+}
+
+func TestManager_InvalidatesOnExternalWrite(t *testing.T) {
+	m, s := createTestManager(t)
+
+	if _, err := m.Set("ns", NamespaceSettings{MaxKeys: 1}, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(currentPrefix+"ns", []byte(`{"namespace":"ns","max_keys":99,"version":7}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		got, err := m.Get("ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.MaxKeys == 99 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected cache to be invalidated by the external write")
+}
+
+func TestManager_RetentionPolicy(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	if _, err := m.Set("orders", NamespaceSettings{RetainVersions: 3, RetainFor: time.Hour}, 0, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Set("orders", NamespaceSettings{RetainVersions: -1}, 0, "test"); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("Expected ErrInvalidSettings for a negative limit, got %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want store.RetentionPolicy
+	}{
+		{"orders/1", store.RetentionPolicy{KeepVersions: 3, MaxAge: time.Hour}},
+		{"users/1", store.RetentionPolicy{}},
+		{"orders", store.RetentionPolicy{}},
+		{"bad name/1", store.RetentionPolicy{}},
+	}
+	for _, tt := range tests {
+		if got := m.RetentionPolicy(tt.key); got != tt.want {
+			t.Errorf("RetentionPolicy(%q) = %+v, want %+v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestManager_Delete(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	for i := int64(1); i <= 2; i++ {
+		if _, err := m.Set("ns", NamespaceSettings{MaxKeys: i}, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Delete("ns"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	got, err := m.Get("ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 0 || got.MaxKeys != 0 {
+		t.Errorf("Expected zero settings after delete, got %+v", got)
+	}
+	history, err := m.History("ns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Errorf("Expected history to be deleted, got %d entries", len(history))
+	}
+	if err := m.Delete("bad/name"); !errors.Is(err, ErrInvalidNamespace) {
+		t.Errorf("Expected ErrInvalidNamespace, got %v", err)
+	}
+}
+
+func TestManager_CreateAndList(t *testing.T) {
+	m, _ := createTestManager(t)
+
+	created, err := m.Create("orders", NamespaceSettings{MaxKeys: 10}, "admin")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Version != 1 || created.MaxKeys != 10 || created.UpdatedBy != "admin" {
+		t.Errorf("Unexpected settings: %+v", created)
+	}
+	if _, err := m.Create("orders", NamespaceSettings{}, ""); !errors.Is(err, ErrNamespaceExists) {
+		t.Errorf("Expected ErrNamespaceExists, got %v", err)
+	}
+	if _, err := m.Set("billing", NamespaceSettings{}, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{"orders": true, "billing": true, "users": false} {
+		if exists, err := m.Exists(name); err != nil || exists != want {
+			t.Errorf("Exists(%s): expected %v, got %v (%v)", name, want, exists, err)
+		}
+	}
+	namespaces, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 2 || namespaces[0].Namespace != "billing" || namespaces[1].Namespace != "orders" {
+		t.Errorf("Expected billing and orders, got %+v", namespaces)
+	}
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrBackupsUnsupported is returned for backups and restores of stores
+	// that do not implement Backuper.
+	ErrBackupsUnsupported = errors.New("backups are not supported by this store")
+	// ErrStoreNotEmpty is returned when restoring into a store that already
+	// holds keys, which the restored data would silently mix with.
+	ErrStoreNotEmpty = errors.New("store is not empty")
+	// ErrBackupGap is returned when restoring an incremental backup into a
+	// store missing changes made before it was taken.
+	ErrBackupGap = errors.New("store is missing changes the incremental backup builds on")
+)
+
+// Backup writes a snapshot of the changes to s after version since to
+// w with the first store in the chain that implements Backuper, returning the
+// version it is complete up to.
+func Backup(s Store, w io.Writer, since uint64) (uint64, error) {
+	if backuper, ok := As[Backuper](s); ok {
+		return backuper.Backup(w, since)
+	}
+	return 0, ErrBackupsUnsupported
+}
+
+// Restore loads a snapshot written by Backup into s with the first store in
+// the chain that implements Backuper. The restored keys bypass the wrappers in
+// between, so they are neither validated nor published as events.
+func Restore(s Store, r io.Reader, since uint64) error {
+	if backuper, ok := As[Backuper](s); ok {
+		return backuper.Restore(r, since)
+	}
+	return ErrBackupsUnsupported
+}
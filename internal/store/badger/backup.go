@@ -0,0 +1,79 @@
+package badger
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Backup writes every key changed after version since to w with BadgerDB's
+// own backup encoding, as of the moment it is called. Deletions are included,
+// so a dump with since above 0 replays onto the state it builds on. Writes
+// keep flowing while it runs.
+//
+// The returned version is the newest one committed when the dump started.
+// Writes committed while it ran may be included as well and are then dumped
+// again by the next incremental backup, which is harmless as loading a change
+// twice leaves the same state.
+func (bs *BadgerStore) Backup(w io.Writer, since uint64) (uint64, error) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	until := bs.db.MaxVersion()
+	if _, err := bs.db.Backup(w, since); err != nil {
+		return 0, fmt.Errorf("failed to back up BadgerDB: %w", err)
+	}
+	if until < since {
+		// Nothing changed since the backup being built on
+		until = since
+	}
+	return until, nil
+}
+
+// Restore loads a stream written by Backup into the database. A full dump
+// needs a database that holds no keys; an incremental one needs one holding
+// every version up to since, such as one the dumps it builds on were
+// restored into. Writers are held off until it finishes, as BadgerDB
+// requires for loads; if it fails, the keys already loaded stay and the
+// store should be discarded.
+func (bs *BadgerStore) Restore(r io.Reader, since uint64) error {
+	bs.writeMu.Lock()
+	defer bs.writeMu.Unlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	if since == 0 {
+		empty, err := bs.isEmpty()
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return store.ErrStoreNotEmpty
+		}
+	} else if bs.db.MaxVersion() < since {
+		return fmt.Errorf("%w: restored up to version %d, backup starts after %d", store.ErrBackupGap, bs.db.MaxVersion(), since)
+	}
+
+	if err := bs.db.Load(r, maxPendingLoadWrites); err != nil {
+		return fmt.Errorf("failed to restore BadgerDB: %w", err)
+	}
+	return nil
+}
+
+// isEmpty reports whether the database holds no keys. bs.mu must be held.
+func (bs *BadgerStore) isEmpty() (bool, error) {
+	empty := true
+	err := bs.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		it.Rewind()
+		empty = !it.Valid()
+		return nil
+	})
+	return empty, err
+}
+
+var _ store.Backuper = (*BadgerStore)(nil)
@@ -0,0 +1,108 @@
+package badger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_BackupRestore(t *testing.T) {
+	source := createTestStore(t)
+	defer func() { _ = source.Close() }()
+	for i := range 50 {
+		if err := source.Put(fmt.Sprintf("key-%02d", i), []byte(fmt.Sprintf("value-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.Delete("key-07"); err != nil {
+		t.Fatal(err)
+	}
+
+	var dump bytes.Buffer
+	if _, err := source.Backup(&dump, 0); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	target := createTestStore(t)
+	defer func() { _ = target.Close() }()
+	if err := target.Restore(bytes.NewReader(dump.Bytes()), 0); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	pairs, err := target.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 49 || string(pairs["key-42"]) != "value-42" {
+		t.Errorf("Expected the 49 live keys to be restored, got %d", len(pairs))
+	}
+	if _, found := pairs["key-07"]; found {
+		t.Error("Expected the deleted key not to be restored")
+	}
+
+	if err := target.Restore(bytes.NewReader(dump.Bytes()), 0); !errors.Is(err, store.ErrStoreNotEmpty) {
+		t.Errorf("Expected ErrStoreNotEmpty restoring into a store with keys, got %v", err)
+	}
+}
+
+func TestBadgerStore_IncrementalBackup(t *testing.T) {
+	source := createTestStore(t)
+	defer func() { _ = source.Close() }()
+	for i := range 10 {
+		if err := source.Put(fmt.Sprintf("key-%d", i), []byte("v1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var full bytes.Buffer
+	until, err := source.Backup(&full, 0)
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	if err := source.Put("key-1", []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Delete("key-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Put("key-10", []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	var incremental bytes.Buffer
+	next, err := source.Backup(&incremental, until)
+	if err != nil {
+		t.Fatalf("Incremental backup failed: %v", err)
+	}
+	if next <= until {
+		t.Errorf("Expected the incremental backup to end after version %d, got %d", until, next)
+	}
+
+	target := createTestStore(t)
+	defer func() { _ = target.Close() }()
+	if err := target.Restore(bytes.NewReader(incremental.Bytes()), until); !errors.Is(err, store.ErrBackupGap) {
+		t.Errorf("Expected ErrBackupGap restoring an incremental backup first, got %v", err)
+	}
+	if err := target.Restore(bytes.NewReader(full.Bytes()), 0); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if err := target.Restore(bytes.NewReader(incremental.Bytes()), until); err != nil {
+		t.Fatalf("Incremental restore failed: %v", err)
+	}
+	pairs, err := target.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 10 || string(pairs["key-1"]) != "v2" || string(pairs["key-10"]) != "v1" {
+		t.Errorf("Expected the changes to be applied, got %d keys and key-1 = %q", len(pairs), pairs["key-1"])
+	}
+	if _, found := pairs["key-2"]; found {
+		t.Error("Expected the deletion to be applied")
+	}
+
+	var empty bytes.Buffer
+	if last, err := source.Backup(&empty, next); err != nil || last != next {
+		t.Errorf("Expected an empty incremental backup to end at %d, got %d and %v", next, last, err)
+	}
+}
@@ -0,0 +1,77 @@
+package badger
+
+import (
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// writeBatch applies fn to a WriteBatch on the current database and flushes
+// it, waiting for any in-progress relocation to finish first
+func (bs *BadgerStore) writeBatch(fn func(wb *badger.WriteBatch) error) error {
+	bs.writeMu.RLock()
+	defer bs.writeMu.RUnlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	wb := bs.db.NewWriteBatch()
+	defer wb.Cancel()
+	if err := fn(wb); err != nil {
+		return err
+	}
+	return wb.Flush()
+}
+
+// BatchPut stores all pairs in a single WriteBatch
+func (bs *BadgerStore) BatchPut(pairs map[string][]byte) error {
+	bs.ops.CountPuts(len(pairs))
+	return bs.writeBatch(func(wb *badger.WriteBatch) error {
+		for key, value := range pairs {
+			if err := wb.Set([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BatchGet retrieves the values of keys in a single read-only transaction
+func (bs *BadgerStore) BatchGet(keys []string) (map[string][]byte, error) {
+	bs.ops.CountGets(len(keys))
+	result := make(map[string][]byte, len(keys))
+	err := bs.view(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			item, err := txn.Get([]byte(key))
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			result[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchDelete removes keys in a single WriteBatch
+func (bs *BadgerStore) BatchDelete(keys []string) error {
+	bs.ops.CountDeletes(len(keys))
+	return bs.writeBatch(func(wb *badger.WriteBatch) error {
+		for _, key := range keys {
+			if err := wb.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var _ store.Batcher = (*BadgerStore)(nil)
@@ -0,0 +1,188 @@
+package badger
+
+import (
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// badgerIterator streams pairs from a read-only transaction. It holds the
+// store's read lock until closed, so a relocation waits for open iterators.
+type badgerIterator struct {
+	bs      *BadgerStore
+	txn     *badger.Txn
+	it      *badger.Iterator
+	prefix  []byte
+	started bool
+	key     string
+	value   []byte
+	err     error
+	closed  bool
+
+	// version, when set, selects the newest version of each key at or
+	// below it; the iterator then visits every version and skips the rest
+	version uint64
+	decided []byte
+
+	// keyRange, when set, replaces prefix as the keys visited
+	keyRange *store.KeyRange
+}
+
+// NewIterator returns an iterator over the pairs whose keys start with prefix
+func (bs *BadgerStore) NewIterator(prefix string) (store.Iterator, error) {
+	bs.ops.CountScan()
+	bs.mu.RLock()
+
+	txn := bs.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 10
+	opts.Prefix = []byte(prefix)
+
+	return &badgerIterator{
+		bs:     bs,
+		txn:    txn,
+		it:     txn.NewIterator(opts),
+		prefix: []byte(prefix),
+	}, nil
+}
+
+// NewRangeIterator returns an iterator over the pairs whose keys are in r,
+// seeking straight to the start of the range
+func (bs *BadgerStore) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	bs.ops.CountScan()
+	bs.mu.RLock()
+
+	txn := bs.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 10
+	opts.Reverse = r.Reverse
+	opts.PrefetchValues = !r.KeysOnly
+
+	return &badgerIterator{
+		bs:       bs,
+		txn:      txn,
+		it:       txn.NewIterator(opts),
+		keyRange: &r,
+	}, nil
+}
+
+// seek positions the iterator on the first key to visit
+func (bi *badgerIterator) seek() {
+	r := bi.keyRange
+	switch {
+	case r == nil:
+		bi.it.Seek(bi.prefix)
+	case r.Reverse && r.End != "":
+		// A reverse seek lands on the last key at or before End
+		bi.it.Seek([]byte(r.End))
+	case r.Reverse:
+		bi.it.Rewind()
+	default:
+		bi.it.Seek([]byte(r.Start))
+	}
+}
+
+// valid reports whether the iterator is on a key to visit, stepping over
+// End itself at the start of a reverse range
+func (bi *badgerIterator) valid() bool {
+	r := bi.keyRange
+	if r == nil {
+		return bi.it.ValidForPrefix(bi.prefix)
+	}
+	for bi.it.Valid() {
+		key := string(bi.it.Item().Key())
+		if r.Contains(key) {
+			return true
+		}
+		if !r.Reverse || key < r.End {
+			return false
+		}
+		bi.it.Next()
+	}
+	return false
+}
+
+func (bi *badgerIterator) Next() bool {
+	if bi.closed || bi.err != nil {
+		return false
+	}
+
+	if !bi.started {
+		bi.seek()
+		bi.started = true
+	} else {
+		bi.it.Next()
+	}
+
+	if !bi.valid() {
+		return false
+	}
+
+	if bi.version != 0 {
+		return bi.nextAt()
+	}
+	item := bi.it.Item()
+	bi.key = string(item.Key())
+	if bi.keyRange != nil && bi.keyRange.KeysOnly {
+		// The value log is never read for the keys alone
+		bi.value = nil
+		return true
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		bi.err = err
+		return false
+	}
+	bi.value = value
+	return true
+}
+
+func (bi *badgerIterator) Key() string {
+	return bi.key
+}
+
+func (bi *badgerIterator) Value() []byte {
+	return bi.value
+}
+
+func (bi *badgerIterator) Err() error {
+	return bi.err
+}
+
+func (bi *badgerIterator) Close() error {
+	if bi.closed {
+		return nil
+	}
+	bi.closed = true
+	bi.it.Close()
+	bi.txn.Discard()
+	bi.bs.mu.RUnlock()
+	return nil
+}
+
+// ForEach calls fn with every pair whose key starts with prefix, handing it
+// the value in place instead of copying it
+func (bs *BadgerStore) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	bs.ops.CountScan()
+	return bs.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchSize = 10
+		opts.Prefix = []byte(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(opts.Prefix); it.ValidForPrefix(opts.Prefix); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+			if err := item.Value(func(value []byte) error { return fn(key, value) }); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var (
+	_ store.IteratorProvider      = (*BadgerStore)(nil)
+	_ store.RangeIteratorProvider = (*BadgerStore)(nil)
+	_ store.ForEacher             = (*BadgerStore)(nil)
+)
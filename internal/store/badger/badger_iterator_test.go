@@ -0,0 +1,174 @@
+package badger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_NewIterator(t *testing.T) {
+	store := createTestStore(t)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	for i := 9; i >= 0; i-- {
+		if err := store.Put(fmt.Sprintf("item:%d", i), []byte(fmt.Sprintf("value-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put("other", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := store.NewIterator("item:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for it.Next() {
+		if expected := fmt.Sprintf("item:%d", count); it.Key() != expected {
+			t.Errorf("Expected key %s, got %s", expected, it.Key())
+		}
+		if expected := fmt.Sprintf("value-%d", count); string(it.Value()) != expected {
+			t.Errorf("Expected value %s, got %s", expected, it.Value())
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 {
+		t.Errorf("Expected 10 pairs, got %d", count)
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() {
+		t.Error("Expected Next to return false after Close")
+	}
+
+	// The read lock must be released so writes and Close proceed
+	if err := store.Put("after", []byte("close")); err != nil {
+		t.Errorf("Put after iterator close failed: %v", err)
+	}
+}
+
+func TestBadgerStore_NewRangeIterator(t *testing.T) {
+	bs := createTestStore(t)
+	defer bs.Close()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := bs.Put(key, []byte("v"+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		r    store.KeyRange
+		want []string
+	}{
+		{"Forward", store.KeyRange{Start: "b", End: "d"}, []string{"b", "c"}},
+		{"ForwardUnbounded", store.KeyRange{Start: "bb"}, []string{"c", "d"}},
+		{"ReverseSkipsEnd", store.KeyRange{Start: "b", End: "d", Reverse: true}, []string{"c", "b"}},
+		{"ReverseBetweenKeys", store.KeyRange{End: "bb", Reverse: true}, []string{"b", "a"}},
+		{"ReverseUnbounded", store.KeyRange{Start: "c", Reverse: true}, []string{"d", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := bs.NewRangeIterator(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer it.Close()
+			var keys []string
+			for it.Next() {
+				keys = append(keys, it.Key())
+				if string(it.Value()) != "v"+it.Key() {
+					t.Errorf("Value of %s = %q", it.Key(), it.Value())
+				}
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(keys) != fmt.Sprint(tt.want) {
+				t.Errorf("Keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestBadgerStore_NewRangeIterator_KeysOnly(t *testing.T) {
+	bs := createTestStore(t)
+	defer bs.Close()
+	if err := bs.BatchPut(map[string][]byte{"a": []byte("1"), "b": make([]byte, 1<<20), "c": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := bs.NewRangeIterator(store.KeyRange{Start: "b", KeysOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Key())
+		if it.Value() != nil {
+			t.Errorf("Expected no value for %s, got %d bytes", it.Key(), len(it.Value()))
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(keys) != "[b c]" {
+		t.Errorf("Keys = %v, want b and c", keys)
+	}
+}
+
+func TestBadgerStore_ForEach(t *testing.T) {
+	s := createTestStore(t)
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	for i := 9; i >= 0; i-- {
+		if err := s.Put(fmt.Sprintf("item:%d", i), []byte(fmt.Sprintf("value-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put("other", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	err := store.ForEach(s, "item:", func(key string, value []byte) error {
+		if expected := fmt.Sprintf("item:%d", count); key != expected {
+			t.Errorf("Expected key %s, got %s", expected, key)
+		}
+		if expected := fmt.Sprintf("value-%d", count); string(value) != expected {
+			t.Errorf("Expected value %s, got %s", expected, value)
+		}
+		count++
+		if count == 5 {
+			return store.ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("Expected the visit to stop after 5 pairs, got %d", count)
+	}
+
+	// The read lock must be released so writes proceed
+	if err := s.Put("after", []byte("visit")); err != nil {
+		t.Errorf("Put after ForEach failed: %v", err)
+	}
+}
@@ -1,14 +1,42 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/William-Fernandes252/clavis/internal/store"
 	"github.com/dgraph-io/badger/v4"
 )
 
 type BadgerStore struct {
-	db *badger.DB
+	// mu guards db and path, which change when the data directory is relocated
+	mu   sync.RWMutex
+	db   *badger.DB
+	path string
+	opts badger.Options
+
+	// writeMu is held shared by writers and exclusively while data is copied
+	// to a new directory, so reads keep flowing while writes wait
+	writeMu sync.RWMutex
+	// relocateMu serializes relocations
+	relocateMu sync.Mutex
+
+	// clock maps commit versions to wall time for age-based retention
+	clock versionClock
+	// logger receives background maintenance records; slog.Default() if nil
+	logger *slog.Logger
+	// ops counts the operations served, for Stats
+	ops store.OperationCounters
+	// committer groups Puts into shared commits; nil unless configured
+	committer *committer
+
+	// sequences holds the named sequences handed out by NextSequence, guarded
+	// by seqMu, each leasing sequenceBandwidth numbers from disk at once
+	seqMu             sync.Mutex
+	sequences         map[string]*badger.Sequence
+	sequenceBandwidth uint64
 }
 
 func New(config *BadgerStoreConfig) (*BadgerStore, error) {
@@ -23,24 +51,65 @@ func New(config *BadgerStoreConfig) (*BadgerStore, error) {
 		return nil, fmt.Errorf("failed to open BadgerDB: %w", err)
 	}
 
-	return &BadgerStore{db: db}, nil
+	bs := &BadgerStore{db: db, path: config.Path, opts: opts, logger: config.Logger, sequenceBandwidth: config.SequenceBandwidth}
+	if bs.sequenceBandwidth == 0 {
+		bs.sequenceBandwidth = DefaultSequenceBandwidth
+	}
+	if config.GroupCommit.Window > 0 {
+		bs.committer = newCommitter(bs, config.GroupCommit)
+	}
+	return bs, nil
 }
 
 func NewWithPath(path string) (*BadgerStore, error) {
 	return New(DefaultConfig(path))
 }
 
-// Close the BadgerDB instance
+// Close the BadgerDB instance, once the grouped Puts in flight are committed
+// and the numbers leased by sequences but not handed out are given back
 func (bs *BadgerStore) Close() error {
-	return bs.db.Close()
+	if bs.committer != nil {
+		bs.committer.stop()
+	}
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return errors.Join(bs.releaseSequences(), bs.db.Close())
+}
+
+// Ping reports whether the database is still open
+func (bs *BadgerStore) Ping() error {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	if bs.db.IsClosed() {
+		return fmt.Errorf("store is closed")
+	}
+	return nil
+}
+
+// view runs fn in a read-only transaction on the current database
+func (bs *BadgerStore) view(fn func(txn *badger.Txn) error) error {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.db.View(fn)
+}
+
+// update runs fn in a read-write transaction on the current database,
+// waiting for any in-progress relocation to finish first
+func (bs *BadgerStore) update(fn func(txn *badger.Txn) error) error {
+	bs.writeMu.RLock()
+	defer bs.writeMu.RUnlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.db.Update(fn)
 }
 
 // Get retrieves the value associated with the key
 func (bs *BadgerStore) Get(key string) ([]byte, bool, error) {
+	bs.ops.CountGets(1)
 	var value []byte
 	var found bool
 
-	err := bs.db.View(func(txn *badger.Txn) error {
+	err := bs.view(func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(key))
 		if err != nil {
 			if err == badger.ErrKeyNotFound {
@@ -57,26 +126,54 @@ func (bs *BadgerStore) Get(key string) ([]byte, bool, error) {
 	return value, found, err
 }
 
-// Put stores the value associated with the key
+// GetView calls fn with the value associated with the key, handing it the
+// value in place instead of copying it
+func (bs *BadgerStore) GetView(key string, fn func(value []byte) error) (bool, error) {
+	bs.ops.CountGets(1)
+	found := false
+	err := bs.view(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				return nil
+			}
+			return err
+		}
+		found = true
+		return item.Value(fn)
+	})
+	return found, err
+}
+
+// Put stores the value associated with the key, in a group of concurrent
+// Puts when group commit is configured
 func (bs *BadgerStore) Put(key string, value []byte) error {
-	return bs.db.Update(func(txn *badger.Txn) error {
+	bs.ops.CountPuts(1)
+	if bs.committer != nil {
+		if grouped, err := bs.committer.put(key, value, nil); grouped {
+			return err
+		}
+	}
+	return bs.update(func(txn *badger.Txn) error {
 		return txn.Set([]byte(key), value)
 	})
 }
 
 // Delete removes the key and its associated value from the store
 func (bs *BadgerStore) Delete(key string) error {
-	return bs.db.Update(func(txn *badger.Txn) error {
+	bs.ops.CountDeletes(1)
+	return bs.update(func(txn *badger.Txn) error {
 		return txn.Delete([]byte(key))
 	})
 }
 
 // Scan retrieves all key-value pairs that start with the given prefix
 func (bs *BadgerStore) Scan(prefix string) (map[string][]byte, error) {
+	bs.ops.CountScan()
 	result := make(map[string][]byte)
 	prefixBytes := []byte(prefix)
 
-	err := bs.db.View(func(txn *badger.Txn) error {
+	err := bs.view(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
 		opts.PrefetchSize = 10
 		it := txn.NewIterator(opts)
@@ -117,4 +214,9 @@ func hasPrefix(key, prefix []byte) bool {
 	return true
 }
 
-var _ store.Store = (*BadgerStore)(nil)
+var (
+	_ store.Store     = (*BadgerStore)(nil)
+	_ store.Relocator = (*BadgerStore)(nil)
+	_ store.Pinger    = (*BadgerStore)(nil)
+	_ store.Viewer    = (*BadgerStore)(nil)
+)
@@ -10,6 +10,12 @@ type BadgerStoreConfig struct {
 	store.StoreConfig        // Embedded struct with common config
 	Path              string // BadgerDB-specific: database path
 	SyncWrites        bool   // BadgerDB-specific: sync writes to disk
+
+	// GroupCommit groups concurrent Puts into one commit; off by default
+	GroupCommit GroupCommitConfig
+	// SequenceBandwidth is how many numbers each named sequence leases from
+	// disk at once; DefaultSequenceBandwidth if zero
+	SequenceBandwidth uint64
 }
 
 // DefaultConfig returns a BadgerConfig with sensible defaults
@@ -42,6 +48,9 @@ func (c *BadgerStoreConfig) ToBadgerOptions() badger.Options {
 	default:
 		opts = opts.WithLoggingLevel(badger.ERROR)
 	}
+	if c.Logger != nil {
+		opts = opts.WithLogger(newBadgerLogger(c.Logger, c.LoggingLevel))
+	}
 
 	return opts
 }
@@ -1,6 +1,7 @@
 package badger
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -607,3 +608,39 @@ func createTestStore(t *testing.T) *BadgerStore {
 
 	return store
 }
+
+func TestBadgerStore_GetView(t *testing.T) {
+	store := createTestStore(t)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	if err := store.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	var viewed string
+	found, err := store.GetView("key", func(value []byte) error {
+		viewed = string(value)
+		return nil
+	})
+	if err != nil || !found || viewed != "value" {
+		t.Errorf("GetView = %v, %v viewing %q; want the stored value", found, err, viewed)
+	}
+
+	called := false
+	found, err = store.GetView("missing", func([]byte) error {
+		called = true
+		return nil
+	})
+	if err != nil || found || called {
+		t.Errorf("GetView of a missing key = %v, %v, called %v; want not found without a call", found, err, called)
+	}
+
+	failed := errors.New("callback failed")
+	if _, err := store.GetView("key", func([]byte) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package badger
+
+import (
+	"context"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records a span around the transaction of every operation made on
+// behalf of a request.
+var tracer = otel.Tracer("github.com/William-Fernandes252/clavis/internal/store/badger")
+
+var (
+	_ store.ContextWriter = (*BadgerStore)(nil)
+	_ store.ContextReader = (*BadgerStore)(nil)
+)
+
+// traced runs fn in a span named after the Badger operation, as a child of
+// the span in ctx.
+func traced(ctx context.Context, operation string, fn func() error, attributes ...attribute.KeyValue) error {
+	_, span := tracer.Start(ctx, "badger."+operation, trace.WithAttributes(append(attributes,
+		attribute.String("db.system", "badger"),
+		attribute.String("db.operation", operation),
+	)...))
+	defer span.End()
+	if err := fn(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetContext is Get, traced as part of the request in ctx.
+func (bs *BadgerStore) GetContext(ctx context.Context, key string) (value []byte, found bool, err error) {
+	err = traced(ctx, "Get", func() error {
+		value, found, err = bs.Get(key)
+		return err
+	}, attribute.String("clavis.key", key))
+	return value, found, err
+}
+
+// PutContext is Put, traced as part of the request in ctx. When ctx asks for
+// it, the version of the write is recorded in ctx; with group commit, the
+// write is grouped all the same and its version looked up once committed.
+func (bs *BadgerStore) PutContext(ctx context.Context, key string, value []byte) error {
+	return traced(ctx, "Put", func() error {
+		if !store.RecordsVersion(ctx) {
+			return bs.Put(key, value)
+		}
+		if bs.committer != nil {
+			var version uint64
+			if grouped, err := bs.committer.put(key, value, &version); grouped {
+				bs.ops.CountPuts(1)
+				if err == nil {
+					store.RecordVersion(ctx, version)
+				}
+				return err
+			}
+		}
+		return bs.putRecorded(ctx, key, value, nil)
+	}, attribute.String("clavis.key", key), attribute.Int("clavis.value_size", len(value)))
+}
+
+// PutIfVersionContext is PutIfVersion, traced as part of the request in ctx.
+// When ctx asks for it, the version of the write is recorded in ctx.
+func (bs *BadgerStore) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return traced(ctx, "PutIfVersion", func() error {
+		if store.RecordsVersion(ctx) {
+			return bs.putRecorded(ctx, key, value, &expected)
+		}
+		return bs.PutIfVersion(key, value, expected)
+	}, attribute.String("clavis.key", key), attribute.Int64("clavis.expected_version", int64(expected)))
+}
+
+// DeleteContext is Delete, traced as part of the request in ctx.
+func (bs *BadgerStore) DeleteContext(ctx context.Context, key string) error {
+	return traced(ctx, "Delete", func() error { return bs.Delete(key) }, attribute.String("clavis.key", key))
+}
+
+// ScanContext is Scan, traced as part of the request in ctx.
+func (bs *BadgerStore) ScanContext(ctx context.Context, prefix string) (pairs map[string][]byte, err error) {
+	err = traced(ctx, "Scan", func() error {
+		pairs, err = bs.Scan(prefix)
+		return err
+	}, attribute.String("clavis.prefix", prefix))
+	return pairs, err
+}
+
+// BatchPutContext is BatchPut, traced as part of the request in ctx.
+func (bs *BadgerStore) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	return traced(ctx, "BatchPut", func() error { return bs.BatchPut(pairs) }, attribute.Int("clavis.batch_size", len(pairs)))
+}
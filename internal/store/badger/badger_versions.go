@@ -0,0 +1,185 @@
+package badger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// GetAt retrieves the value of key as of the given commit version. Versions
+// older than NumVersionsToKeep may already have been discarded by compaction.
+func (bs *BadgerStore) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	bs.ops.CountGets(1)
+	var value []byte
+	var at uint64
+	var found bool
+	keyBytes := []byte(key)
+
+	err := bs.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.AllVersions = true
+		opts.PrefetchValues = false
+		opts.Prefix = keyBytes
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// Versions of a key are visited newest first
+		for it.Seek(keyBytes); it.Valid(); it.Next() {
+			item := it.Item()
+			if !bytes.Equal(item.Key(), keyBytes) {
+				break
+			}
+			if version != 0 && item.Version() > version {
+				continue
+			}
+			if item.IsDeletedOrExpired() {
+				return nil
+			}
+
+			var err error
+			value, err = item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			at = item.Version()
+			found = true
+			return nil
+		}
+		return nil
+	})
+
+	return value, at, found, err
+}
+
+var (
+	_ store.VersionReader     = (*BadgerStore)(nil)
+	_ store.ConditionalPutter = (*BadgerStore)(nil)
+)
+
+// PutIfVersion stores the value only if the latest version of key is
+// expected, or if key does not exist when expected is 0. The check and the
+// write happen in one transaction, so a concurrent write to the key makes the
+// commit fail with ErrVersionConflict as well.
+func (bs *BadgerStore) PutIfVersion(key string, value []byte, expected uint64) error {
+	bs.ops.CountPuts(1)
+	_, err := bs.putChecked(key, value, &expected)
+	return err
+}
+
+// putChecked stores the value in a transaction that reads key first, and
+// only if key is at expected when it is not nil. Returns the read version of
+// the transaction. As the key is read, a write to it committed after that
+// version fails the transaction with ErrVersionConflict, so no version of
+// the key falls between the read version and the version of the write.
+func (bs *BadgerStore) putChecked(key string, value []byte, expected *uint64) (uint64, error) {
+	var readTs uint64
+	err := bs.update(func(txn *badger.Txn) error {
+		readTs = txn.ReadTs()
+		var current uint64
+		item, err := txn.Get([]byte(key))
+		switch {
+		case errors.Is(err, badger.ErrKeyNotFound):
+		case err != nil:
+			return err
+		default:
+			current = item.Version()
+		}
+		if expected != nil && current != *expected {
+			return store.ErrVersionConflict
+		}
+		return txn.Set([]byte(key), value)
+	})
+	if errors.Is(err, badger.ErrConflict) {
+		return 0, store.ErrVersionConflict
+	}
+	return readTs, err
+}
+
+// putRecorded stores the value as putChecked does and records the version
+// it was committed at in ctx. A write without an expected version that
+// loses to a concurrent one is retried, as a plain Put would not conflict.
+func (bs *BadgerStore) putRecorded(ctx context.Context, key string, value []byte, expected *uint64) error {
+	bs.ops.CountPuts(1)
+	for {
+		readTs, err := bs.putChecked(key, value, expected)
+		if errors.Is(err, store.ErrVersionConflict) && expected == nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		version, err := bs.versionAfter(key, readTs)
+		if err != nil {
+			return err
+		}
+		store.RecordVersion(ctx, version)
+		return nil
+	}
+}
+
+// versionAfter returns the oldest version of key newer than readTs, which
+// after putChecked is the version of its write.
+func (bs *BadgerStore) versionAfter(key string, readTs uint64) (uint64, error) {
+	var version uint64
+	keyBytes := []byte(key)
+	err := bs.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.AllVersions = true
+		opts.PrefetchValues = false
+		opts.Prefix = keyBytes
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// Versions of a key are visited newest first
+		for it.Seek(keyBytes); it.Valid(); it.Next() {
+			item := it.Item()
+			if !bytes.Equal(item.Key(), keyBytes) || item.Version() <= readTs {
+				break
+			}
+			version = item.Version()
+		}
+		return nil
+	})
+	return version, err
+}
+
+// readTs returns the version of the latest commit to the database. Writes
+// committed after it have newer versions. The caller must hold bs.mu.
+func (bs *BadgerStore) readTs() uint64 {
+	txn := bs.db.NewTransaction(false)
+	defer txn.Discard()
+	return txn.ReadTs()
+}
+
+// versionWritten returns the newest version of key after readTs holding
+// value, or 0 if there is none.
+func versionWritten(txn *badger.Txn, key, value []byte, readTs uint64) (uint64, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.AllVersions = true
+	opts.PrefetchValues = false
+	opts.Prefix = key
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	// Versions of a key are visited newest first
+	for it.Seek(key); it.Valid(); it.Next() {
+		item := it.Item()
+		if !bytes.Equal(item.Key(), key) || item.Version() <= readTs {
+			break
+		}
+		if item.IsDeletedOrExpired() {
+			continue
+		}
+		written, err := item.ValueCopy(nil)
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(written, value) {
+			return item.Version(), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,152 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_GetAt(t *testing.T) {
+	store := createTestStore(t)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	// Write another key first so the key's first version is above 1
+	if err := store.Put("config-other", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("config", []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	_, first, found, err := store.GetAt("config", 0)
+	if err != nil || !found {
+		t.Fatalf("GetAt latest failed: found=%v err=%v", found, err)
+	}
+	if err := store.Put("config", []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("config-other", []byte("y")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, at, found, err := store.GetAt("config", 0)
+	if err != nil || !found || string(value) != "v2" || at <= first {
+		t.Errorf("Expected latest v2 after version %d, got %q at %d (found=%v, err=%v)", first, value, at, found, err)
+	}
+
+	value, at, found, err = store.GetAt("config", first)
+	if err != nil || !found || string(value) != "v1" || at != first {
+		t.Errorf("Expected v1 at version %d, got %q at %d (found=%v, err=%v)", first, value, at, found, err)
+	}
+
+	if _, _, found, _ := store.GetAt("config", first-1); found {
+		t.Error("Expected no value before the first write")
+	}
+
+	if err := store.Delete("config"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, found, _ := store.GetAt("config", 0); found {
+		t.Error("Expected no latest value after delete")
+	}
+	if value, _, found, _ := store.GetAt("config", first); !found || string(value) != "v1" {
+		t.Errorf("Expected v1 to remain readable at version %d, got %q", first, value)
+	}
+}
+
+func TestBadgerStore_PutIfVersion(t *testing.T) {
+	bs := createTestStore(t)
+	defer func() { _ = bs.Close() }()
+
+	if err := bs.PutIfVersion("k", []byte("v1"), 1); !errors.Is(err, store.ErrVersionConflict) {
+		t.Errorf("Expected a conflict writing a missing key at version 1, got %v", err)
+	}
+	if err := bs.PutIfVersion("k", []byte("v1"), 0); err != nil {
+		t.Fatalf("Expected to create the key, got %v", err)
+	}
+	if err := bs.PutIfVersion("k", []byte("again"), 0); !errors.Is(err, store.ErrVersionConflict) {
+		t.Errorf("Expected a conflict creating an existing key, got %v", err)
+	}
+	if err := store.PutIfAbsent(bs, "k", []byte("again")); !errors.Is(err, store.ErrKeyExists) || !errors.Is(err, store.ErrPreconditionFailed) {
+		t.Errorf("Expected PutIfAbsent of an existing key to fail with ErrKeyExists, got %v", err)
+	}
+
+	_, version, _, err := bs.GetAt("k", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.PutIfVersion("k", []byte("v2"), version); err != nil {
+		t.Fatalf("Expected to update at the current version, got %v", err)
+	}
+	if err := bs.PutIfVersion("k", []byte("v3"), version); !errors.Is(err, store.ErrVersionConflict) {
+		t.Errorf("Expected a conflict at a stale version, got %v", err)
+	}
+
+	if err := bs.Delete("k"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.PutIfVersion("k", []byte("v4"), 0); err != nil {
+		t.Errorf("Expected a deleted key to count as missing, got %v", err)
+	}
+	value, _, _ := bs.Get("k")
+	if string(value) != "v4" {
+		t.Errorf("Expected v4, got %q", value)
+	}
+}
+
+func TestBadgerStore_RecordsWrittenVersion(t *testing.T) {
+	bs := createTestStoreWithVersions(t, 10)
+
+	var versions []uint64
+	for _, value := range []string{"v1", "v2", "v3"} {
+		ctx, version := store.WithVersionRecord(context.Background())
+		if err := bs.PutContext(ctx, "k", []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) > 0 && version() <= versions[len(versions)-1] {
+			t.Errorf("Expected versions to increase, got %d after %v", version(), versions)
+		}
+		versions = append(versions, version())
+	}
+	for i, want := range []string{"v1", "v2", "v3"} {
+		if value, at, found, err := bs.GetAt("k", versions[i]); err != nil || !found || at != versions[i] || string(value) != want {
+			t.Errorf("GetAt(%d) = %q at %d, %v, %v; want %q", versions[i], value, at, found, err, want)
+		}
+	}
+
+	ctx, version := store.WithVersionRecord(context.Background())
+	if err := bs.PutIfVersionContext(ctx, "k", []byte("v4"), versions[2]); err != nil {
+		t.Fatal(err)
+	}
+	if _, at, _, _ := bs.GetAt("k", 0); version() != at {
+		t.Errorf("Recorded version %d, want the latest version %d", version(), at)
+	}
+
+	// Concurrent writers to one key each get the version of their own write
+	var wg sync.WaitGroup
+	recorded := make([]uint64, 8)
+	for i := range recorded {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, version := store.WithVersionRecord(context.Background())
+			if err := bs.PutContext(ctx, "hot", []byte{byte(i)}); err != nil {
+				t.Error(err)
+				return
+			}
+			recorded[i] = version()
+		}()
+	}
+	wg.Wait()
+	for i, version := range recorded {
+		if value, _, found, err := bs.GetAt("hot", version); err != nil || !found || len(value) != 1 || value[0] != byte(i) {
+			t.Errorf("GetAt(%d) = %v, %v, %v; want the write of writer %d", version, value, found, err, i)
+		}
+	}
+}
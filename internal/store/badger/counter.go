@@ -0,0 +1,41 @@
+package badger
+
+import (
+	"errors"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var _ store.Incrementer = (*BadgerStore)(nil)
+
+// Increment adds delta to the counter at key in one transaction, starting
+// over when a concurrent write to the key makes its commit conflict.
+func (bs *BadgerStore) Increment(key string, delta int64, encoding store.CounterEncoding) (int64, error) {
+	bs.ops.CountPuts(1)
+	for {
+		var next int64
+		err := bs.update(func(txn *badger.Txn) error {
+			var value []byte
+			item, err := txn.Get([]byte(key))
+			switch {
+			case errors.Is(err, badger.ErrKeyNotFound):
+			case err != nil:
+				return err
+			default:
+				if value, err = item.ValueCopy(nil); err != nil {
+					return err
+				}
+			}
+			var encoded []byte
+			if next, encoded, err = store.AddCounter(encoding, value, delta); err != nil {
+				return err
+			}
+			return txn.Set([]byte(key), encoded)
+		})
+		if errors.Is(err, badger.ErrConflict) {
+			continue
+		}
+		return next, err
+	}
+}
@@ -0,0 +1,55 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_Increment(t *testing.T) {
+	bs := createTestStore(t)
+	defer func() { _ = bs.Close() }()
+
+	tests := []struct {
+		name string
+		s    store.Store
+	}{
+		{"Transaction", bs},
+		// A wrapper falls back to conditional writes
+		{"ConditionalWrites", store.Passthrough{Store: bs}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "counter-" + tt.name
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 10 {
+						if _, err := store.Increment(context.Background(), tt.s, key, 2, store.CounterLittleEndian); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			next, err := store.Increment(context.Background(), tt.s, key, -10, store.CounterLittleEndian)
+			if err != nil || next != 150 {
+				t.Errorf("Increment = %d, %v; want 150 after 80 concurrent increments", next, err)
+			}
+			value, _, _ := bs.Get(key)
+			if n, err := store.CounterLittleEndian.Decode(value); err != nil || n != 150 {
+				t.Errorf("Stored counter = %d, %v; want 150", n, err)
+			}
+			if _, err := store.Increment(context.Background(), tt.s, key, 1, store.CounterASCII); !errors.Is(err, store.ErrNotCounter) {
+				t.Errorf("Increment in another encoding = %v, want ErrNotCounter", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package badger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var _ store.FilterEvaluator = (*BadgerStore)(nil)
+
+// EvaluateFilter returns the keys that filter drops. Write times are
+// estimated from the version clock, so pairs look younger than they are by up
+// to the time between clock samples; like age-based retention, pairs written
+// before the store was opened are treated as written when first evaluated.
+func (bs *BadgerStore) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	var dropped []string
+	err := bs.view(func(txn *badger.Txn) error {
+		bs.clock.record(now, bs.db.MaxVersion())
+
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			entry := store.FilterEntry{Key: string(item.Key()), Value: value, ModTime: now}
+			if at, ok := bs.clock.committedBy(item.Version()); ok {
+				entry.ModTime = at
+			}
+
+			drop, err := filter.Drop(entry, now)
+			if err != nil {
+				return fmt.Errorf("filter failed on key %q: %w", entry.Key, err)
+			}
+			if drop {
+				dropped = append(dropped, entry.Key)
+			}
+		}
+		return nil
+	})
+	return dropped, err
+}
@@ -0,0 +1,58 @@
+package badger
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_EvaluateFilter(t *testing.T) {
+	bs := createTestStore(t)
+	defer func() { _ = bs.Close() }()
+
+	for _, key := range []string{"cache/old", "users/old"} {
+		if err := bs.Put(key, []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs.clock.record(time.Now().Add(-time.Hour), bs.db.MaxVersion())
+	if err := bs.Put("cache/new", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := store.ApplyFilter(bs, store.ExpireAfter("cache/", 30*time.Minute), time.Now())
+	if err != nil {
+		t.Fatalf("ApplyFilter failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 key dropped, got %d", n)
+	}
+
+	pairs, err := bs.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	if len(keys) != 2 || pairs["cache/new"] == nil || pairs["users/old"] == nil {
+		t.Errorf("Expected cache/new and users/old to remain, got %v", keys)
+	}
+
+	// Keys written before any clock sample count as written now
+	fresh := createTestStore(t)
+	defer func() { _ = fresh.Close() }()
+	if err := fresh.Put("cache/a", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	dropped, err := fresh.EvaluateFilter(store.ExpireAfter("cache/", time.Minute), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dropped, []string(nil)) {
+		t.Errorf("Expected no keys dropped from an unsampled store, got %v", dropped)
+	}
+}
@@ -0,0 +1,195 @@
+package badger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// DefaultGroupCommitMaxWrites is the MaxWrites of a GroupCommitConfig that
+// leaves it zero.
+const DefaultGroupCommitMaxWrites = 256
+
+// Group commit counts set by CollectMetrics.
+const (
+	MetricGroupCommits      = "badger_group_commits"       // Groups committed
+	MetricGroupCommitWrites = "badger_group_commit_writes" // Puts committed in groups
+)
+
+// GroupCommitConfig configures the grouping of concurrent Puts into one
+// commit. With SyncWrites, every commit waits for the disk to sync, so
+// grouping Puts arriving together trades up to Window of latency for one sync
+// per group instead of one per Put.
+type GroupCommitConfig struct {
+	Window    time.Duration // Time a group waits for more Puts after its first; grouping is off if zero
+	MaxWrites int           // Puts that commit a group before its window ends; DefaultGroupCommitMaxWrites if zero
+}
+
+// groupWrite is a Put waiting for its group to commit.
+type groupWrite struct {
+	key   []byte
+	value []byte
+	done  chan error
+	// version receives the version of the write before done does, if not nil
+	version *uint64
+}
+
+// committer gathers Puts into groups and commits each group in one
+// WriteBatch. A Put returns once its group is committed, with the error of
+// its own write or of the commit.
+type committer struct {
+	bs        *BadgerStore
+	window    time.Duration
+	maxWrites int
+	writes    chan groupWrite
+	finished  chan struct{}
+
+	// mu is held shared while sending on writes and exclusively to close it
+	mu     sync.RWMutex
+	closed bool
+
+	groups, grouped atomic.Uint64
+}
+
+func newCommitter(bs *BadgerStore, config GroupCommitConfig) *committer {
+	maxWrites := config.MaxWrites
+	if maxWrites <= 0 {
+		maxWrites = DefaultGroupCommitMaxWrites
+	}
+	c := &committer{
+		bs:        bs,
+		window:    config.Window,
+		maxWrites: maxWrites,
+		writes:    make(chan groupWrite, maxWrites),
+		finished:  make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// put queues a write and waits for its group to commit, storing the version
+// of the write in version if not nil. It reports false, without writing, once
+// the committer is stopped.
+func (c *committer) put(key string, value []byte, version *uint64) (grouped bool, err error) {
+	w := groupWrite{key: []byte(key), value: value, done: make(chan error, 1), version: version}
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return false, nil
+	}
+	c.writes <- w
+	c.mu.RUnlock()
+	return true, <-w.done
+}
+
+// run commits groups until writes is closed and drained.
+func (c *committer) run() {
+	defer close(c.finished)
+	for first := range c.writes {
+		group := []groupWrite{first}
+		timer := time.NewTimer(c.window)
+	collect:
+		for len(group) < c.maxWrites {
+			select {
+			case w, ok := <-c.writes:
+				if !ok {
+					break collect
+				}
+				group = append(group, w)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+		c.commit(group)
+	}
+}
+
+// commit writes a group in one WriteBatch. A write the batch rejects, such as
+// one with an oversized value, fails alone; a failed flush fails the group.
+//
+// A WriteBatch does not tell the version it committed at, so the versions
+// asked for are looked up once it is committed, among those written after a
+// read version taken before it.
+func (c *committer) commit(group []groupWrite) {
+	failed := make([]error, len(group))
+	var readTs uint64
+	err := c.bs.writeBatch(func(wb *badger.WriteBatch) error {
+		readTs = c.bs.readTs()
+		for i, w := range group {
+			failed[i] = wb.Set(w.key, w.value)
+		}
+		return nil
+	})
+	c.groups.Add(1)
+	c.grouped.Add(uint64(len(group)))
+	for i := range group {
+		if failed[i] == nil {
+			failed[i] = err
+		}
+	}
+	if err == nil {
+		c.resolveVersions(group, failed, readTs)
+	}
+	for i, w := range group {
+		w.done <- failed[i]
+	}
+}
+
+// resolveVersions stores the versions of the committed writes of group that
+// ask for one, in a single read transaction. The version of a write is the
+// newest version of its key after readTs holding its value: an older one
+// holding another value was written by a concurrent writer committed before
+// the group, and a newer one holding the same value is indistinguishable from
+// the write. Writes overwritten by a later one in the same group were
+// committed with it, so they share its version.
+func (c *committer) resolveVersions(group []groupWrite, failed []error, readTs uint64) {
+	last := make(map[string]int)
+	for i, w := range group {
+		if failed[i] == nil {
+			last[string(w.key)] = i
+		}
+	}
+	versions := make(map[string]uint64)
+	err := c.bs.view(func(txn *badger.Txn) error {
+		for i, w := range group {
+			if w.version == nil || failed[i] != nil {
+				continue
+			}
+			if _, ok := versions[string(w.key)]; ok {
+				continue
+			}
+			latest := group[last[string(w.key)]]
+			version, err := versionWritten(txn, latest.key, latest.value, readTs)
+			if err != nil {
+				return err
+			}
+			versions[string(w.key)] = version
+		}
+		return nil
+	})
+	for i, w := range group {
+		if w.version == nil || failed[i] != nil {
+			continue
+		}
+		if err != nil {
+			failed[i] = err
+			continue
+		}
+		*w.version = versions[string(w.key)]
+	}
+}
+
+// stop commits the Puts already queued and waits for them. Later Puts are
+// not grouped.
+func (c *committer) stop() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.writes)
+	}
+	c.mu.Unlock()
+	<-c.finished
+}
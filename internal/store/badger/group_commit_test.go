@@ -0,0 +1,186 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func createGroupCommitStore(t *testing.T, config GroupCommitConfig) *BadgerStore {
+	t.Helper()
+	storeConfig := DefaultConfig(t.TempDir())
+	storeConfig.SyncWrites = false
+	storeConfig.GroupCommit = config
+	bs, err := New(storeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = bs.Close() })
+	return bs
+}
+
+func TestGroupCommit(t *testing.T) {
+	bs := createGroupCommitStore(t, GroupCommitConfig{Window: 20 * time.Millisecond, MaxWrites: 8})
+
+	const writers = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- bs.Put(fmt.Sprintf("key:%02d", i), []byte(fmt.Sprintf("value-%d", i)))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	for i := range writers {
+		if value, found, err := bs.Get(fmt.Sprintf("key:%02d", i)); err != nil || !found || string(value) != fmt.Sprintf("value-%d", i) {
+			t.Errorf("Get(key:%02d) = %q, %v, %v; want the written value", i, value, found, err)
+		}
+	}
+
+	registry := metrics.NewRegistry()
+	bs.CollectMetrics(registry)
+	groups, grouped := registry.Gauge(MetricGroupCommits).Value(), registry.Gauge(MetricGroupCommitWrites).Value()
+	if grouped != writers {
+		t.Errorf("Expected %d grouped writes, got %d", writers, grouped)
+	}
+	if groups >= writers || groups < writers/8 {
+		t.Errorf("Expected the writes to share between %d and %d groups, got %d", writers/8, writers-1, groups)
+	}
+}
+
+func TestGroupCommit_RecordsVersions(t *testing.T) {
+	bs := createGroupCommitStore(t, GroupCommitConfig{Window: 20 * time.Millisecond, MaxWrites: 8})
+
+	// Written as the server writes, asking for the version of each Put
+	const writers = 32
+	versions := make([]uint64, writers)
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, version := store.WithVersionRecord(context.Background())
+			errs <- bs.PutContext(ctx, fmt.Sprintf("key:%02d", i), []byte(fmt.Sprintf("value-%d", i)))
+			versions[i] = version()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("PutContext failed: %v", err)
+		}
+	}
+
+	registry := metrics.NewRegistry()
+	bs.CollectMetrics(registry)
+	if grouped := registry.Gauge(MetricGroupCommitWrites).Value(); grouped != writers {
+		t.Errorf("Expected %d grouped writes, got %d", writers, grouped)
+	}
+	if groups := registry.Gauge(MetricGroupCommits).Value(); groups >= writers {
+		t.Errorf("Expected the writes to share groups, got %d groups", groups)
+	}
+
+	// Each version is the one holding the value of its write
+	for i, version := range versions {
+		value, at, found, err := bs.GetAt(fmt.Sprintf("key:%02d", i), version)
+		if err != nil || !found || at != version || string(value) != fmt.Sprintf("value-%d", i) {
+			t.Errorf("GetAt(key:%02d, %d) = %q at %d, %v, %v; want value-%d", i, version, value, at, found, err, i)
+		}
+	}
+
+	// Writes of a key overwritten in the same group share the version of the
+	// last one
+	var first, second uint64
+	wg.Add(2)
+	for i, version := range []*uint64{&first, &second} {
+		go func() {
+			defer wg.Done()
+			ctx, recorded := store.WithVersionRecord(context.Background())
+			if err := bs.PutContext(ctx, "shared", []byte(fmt.Sprint(i))); err != nil {
+				t.Error(err)
+			}
+			*version = recorded()
+		}()
+	}
+	wg.Wait()
+	if _, latest, _, err := bs.GetAt("shared", 0); err != nil || max(first, second) != latest || first == 0 || second == 0 {
+		t.Errorf("Versions of the writes of one key = %d and %d, want nonzero and the latest %d", first, second, latest)
+	}
+}
+
+func TestGroupCommit_FailsWritesAlone(t *testing.T) {
+	bs := createGroupCommitStore(t, GroupCommitConfig{Window: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	var goodErr, badErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		goodErr = bs.Put("good", []byte("value"))
+	}()
+	go func() {
+		defer wg.Done()
+		badErr = bs.Put(strings.Repeat("k", 70000), []byte("value")) // Over Badger's key size limit
+	}()
+	wg.Wait()
+
+	if goodErr != nil {
+		t.Errorf("Expected the valid write to succeed, got %v", goodErr)
+	}
+	if badErr == nil {
+		t.Error("Expected the oversized key to be rejected")
+	}
+	if value, found, _ := bs.Get("good"); !found || string(value) != "value" {
+		t.Errorf("Expected the valid write to be committed, got %q", value)
+	}
+}
+
+func TestGroupCommit_Close(t *testing.T) {
+	bs := createGroupCommitStore(t, GroupCommitConfig{Window: time.Hour, MaxWrites: 2})
+
+	// A full group commits without waiting for its window
+	done := make(chan error, 2)
+	for _, key := range []string{"a", "b"} {
+		go func() { done <- bs.Put(key, []byte(key)) }()
+	}
+	for range 2 {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected a full group to commit before its window ends")
+		}
+	}
+
+	// Close commits the writes waiting for their group
+	go func() { done <- bs.Put("c", []byte("c")) }()
+	time.Sleep(50 * time.Millisecond)
+	if err := bs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected the pending write to commit on Close, got %v", err)
+	}
+	if err := bs.Put("d", []byte("d")); err == nil {
+		t.Error("Expected Put after Close to fail")
+	}
+}
@@ -0,0 +1,121 @@
+package badger
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/badger/v4/pb"
+	"github.com/dgraph-io/ristretto/v2/z"
+)
+
+var _ store.Loader = (*BadgerStore)(nil)
+
+// loadBufferSize is how much encoded pairs a load hands to the StreamWriter
+// at once.
+const loadBufferSize = 16 << 20
+
+// loadVersion is the version the loaded keys are written at. The StreamWriter
+// moves the versions of later writes past it.
+const loadVersion = 1
+
+// NewLoad starts a load with Badger's StreamWriter, which builds the tables
+// of the LSM tree straight from the sorted pairs instead of sending them
+// through the memtables and compactions. Writers are held off until the load
+// ends, as the StreamWriter requires; a canceled or failed load leaves data
+// files behind that only a new load discards, so the store should be
+// discarded too.
+func (bs *BadgerStore) NewLoad() (store.Load, error) {
+	bs.writeMu.Lock()
+	bs.mu.RLock()
+	unlock := func() {
+		bs.mu.RUnlock()
+		bs.writeMu.Unlock()
+	}
+
+	empty, err := bs.isEmpty()
+	if err == nil && !empty {
+		err = store.ErrStoreNotEmpty
+	}
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	sw := bs.db.NewStreamWriter()
+	// The database is empty, so Prepare drops nothing
+	if err := sw.Prepare(); err != nil {
+		sw.Cancel()
+		unlock()
+		return nil, fmt.Errorf("failed to prepare BadgerDB for loading: %w", err)
+	}
+	return &streamLoad{bs: bs, sw: sw, buf: z.NewBuffer(loadBufferSize, "clavis.load"), unlock: unlock}, nil
+}
+
+// streamLoad loads pairs into a BadgerStore with a StreamWriter. The pairs
+// are encoded into buf and written once it fills up.
+type streamLoad struct {
+	bs     *BadgerStore
+	sw     *badger.StreamWriter
+	buf    *z.Buffer
+	last   []byte
+	added  int
+	unlock func()
+	// once ends the load, by Finish or Cancel
+	once sync.Once
+}
+
+func (l *streamLoad) Add(key string, value []byte) error {
+	if l.added > 0 && key <= string(l.last) {
+		return fmt.Errorf("%w: %q after %q", store.ErrUnsortedLoad, key, l.last)
+	}
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	badger.KVToBuffer(&pb.KV{Key: []byte(key), Value: value, Version: loadVersion}, l.buf)
+	l.last = append(l.last[:0], key...)
+	l.added++
+	if l.buf.LenNoPadding() < loadBufferSize {
+		return nil
+	}
+	return l.flush()
+}
+
+func (l *streamLoad) Finish() error {
+	err := l.flush()
+	l.once.Do(func() {
+		defer l.release()
+		if err != nil {
+			l.sw.Cancel()
+			return
+		}
+		if err = l.sw.Flush(); err != nil {
+			err = fmt.Errorf("failed to finish loading BadgerDB: %w", err)
+			return
+		}
+		l.bs.ops.CountPuts(l.added)
+	})
+	return err
+}
+
+func (l *streamLoad) Cancel() {
+	l.once.Do(func() {
+		l.sw.Cancel()
+		l.release()
+	})
+}
+
+// flush writes the pairs encoded since the last flush.
+func (l *streamLoad) flush() error {
+	if err := l.sw.Write(l.buf); err != nil {
+		return fmt.Errorf("failed to load into BadgerDB: %w", err)
+	}
+	l.buf.Reset()
+	return nil
+}
+
+// release releases the buffer and lets writers in again.
+func (l *streamLoad) release() {
+	_ = l.buf.Release()
+	l.unlock()
+}
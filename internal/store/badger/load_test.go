@@ -0,0 +1,72 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_NewLoad(t *testing.T) {
+	bs := createTestStoreWithVersions(t, 1)
+
+	load, err := store.NewLoad(bs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5000 {
+		if err := load.Add(fmt.Sprintf("users/%05d", i), []byte(fmt.Sprintf("user %d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := load.Add("users/00000", nil); !errors.Is(err, store.ErrUnsortedLoad) {
+		t.Fatalf("Expected ErrUnsortedLoad adding a key out of order, got %v", err)
+	}
+	if err := load.Finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := store.Count(context.Background(), bs, "users/"); err != nil || n != 5000 {
+		t.Errorf("Count() after the load = %d, %v; want 5000", n, err)
+	}
+	if value, found, err := bs.Get("users/04321"); err != nil || !found || string(value) != "user 4321" {
+		t.Errorf("Get() of a loaded key = %q, %v, %v", value, found, err)
+	}
+	// Later writes are versioned after the loaded keys
+	_, loaded, _, err := bs.GetAt("users/00001", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.PutIfVersion("users/00001", []byte("updated"), loaded); err != nil {
+		t.Errorf("PutIfVersion() at the loaded version = %v", err)
+	}
+	if _, version, _, _ := bs.GetAt("users/00001", 0); version <= loaded {
+		t.Errorf("Expected a write after the load to get a version above %d, got %d", loaded, version)
+	}
+
+	if _, err := store.NewLoad(bs, 0); !errors.Is(err, store.ErrStoreNotEmpty) {
+		t.Errorf("Expected ErrStoreNotEmpty loading into a store with keys, got %v", err)
+	}
+}
+
+func TestBadgerStore_NewLoad_Cancel(t *testing.T) {
+	bs := createTestStoreWithVersions(t, 1)
+	load, err := store.NewLoad(bs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := load.Add("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	load.Cancel()
+
+	// Writers get in again once the load is canceled
+	if err := bs.Put("b", []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := bs.Get("a"); found {
+		t.Error("Expected the pairs of a canceled load not to be visible")
+	}
+}
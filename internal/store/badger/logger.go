@@ -0,0 +1,38 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// badgerLogger routes Badger's internal logging to a structured logger,
+// keeping the store's LoggingLevel as the threshold.
+type badgerLogger struct {
+	logger *slog.Logger
+	min    slog.Level
+}
+
+// newBadgerLogger adapts logger for Badger. loggingLevel uses the
+// store.StoreConfig scale, where 0 is DEBUG and 3 is ERROR.
+func newBadgerLogger(logger *slog.Logger, loggingLevel int) badgerLogger {
+	levels := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+	threshold := slog.LevelError
+	if loggingLevel >= 0 && loggingLevel < len(levels) {
+		threshold = levels[loggingLevel]
+	}
+	return badgerLogger{logger: logger.With("component", "badger"), min: threshold}
+}
+
+func (l badgerLogger) log(level slog.Level, format string, args ...any) {
+	if level < l.min {
+		return
+	}
+	l.logger.Log(context.Background(), level, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (l badgerLogger) Errorf(format string, args ...any)   { l.log(slog.LevelError, format, args...) }
+func (l badgerLogger) Warningf(format string, args ...any) { l.log(slog.LevelWarn, format, args...) }
+func (l badgerLogger) Infof(format string, args ...any)    { l.log(slog.LevelInfo, format, args...) }
+func (l badgerLogger) Debugf(format string, args ...any)   { l.log(slog.LevelDebug, format, args...) }
@@ -0,0 +1,37 @@
+package badger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestBadgerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newBadgerLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), 2)
+
+	logger.Infof("opened %d tables\n", 3)
+	logger.Warningf("value log %s is large", "000001.vlog")
+	logger.Errorf("compaction failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected records at or above WARNING only, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `level=WARN msg="value log 000001.vlog is large" component=badger`) {
+		t.Errorf("Unexpected warning record %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "level=ERROR msg=\"compaction failed\"") {
+		t.Errorf("Unexpected error record %q", lines[1])
+	}
+
+	config := DefaultConfig(t.TempDir())
+	if _, ok := config.ToBadgerOptions().Logger.(badgerLogger); ok {
+		t.Error("Expected Badger's own logger without a configured logger")
+	}
+	config.Logger = slog.Default()
+	if _, ok := config.ToBadgerOptions().Logger.(badgerLogger); !ok {
+		t.Error("Expected a configured logger to replace Badger's default logger")
+	}
+}
@@ -0,0 +1,129 @@
+package badger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+const (
+	// MetricGCRuns counts background garbage collection passes.
+	MetricGCRuns = "store_gc_runs_total"
+	// MetricGCRewrittenFiles counts value log files rewritten by background
+	// garbage collection.
+	MetricGCRewrittenFiles = "store_gc_rewritten_files_total"
+	// MetricGCReclaimedBytes counts the disk space background garbage
+	// collection reclaimed.
+	MetricGCReclaimedBytes = "store_gc_reclaimed_bytes_total"
+
+	// DefaultDiscardRatio is the share of stale data at which a value log
+	// file is worth rewriting, as recommended by Badger.
+	DefaultDiscardRatio = 0.5
+	// DefaultGCInterval is the time between garbage collection passes.
+	DefaultGCInterval = 10 * time.Minute
+)
+
+// GCConfig configures background garbage collection of the value log.
+type GCConfig struct {
+	Interval     time.Duration     // Time between passes; DefaultGCInterval if zero
+	DiscardRatio float64           // Share of stale data at which a file is rewritten; DefaultDiscardRatio if zero
+	Metrics      *metrics.Registry // Registry for GC metrics; metrics.Default if nil
+}
+
+var _ store.Maintainer = (*BadgerStore)(nil)
+
+// DiskUsage returns the size of the LSM tree and the value log in bytes.
+func (bs *BadgerStore) DiskUsage() int64 {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	lsm, vlog := bs.db.Size()
+	return lsm + vlog
+}
+
+// RunGC rewrites value log files until none has at least discardRatio of
+// stale data, DefaultDiscardRatio if zero, returning the number of files
+// rewritten. Only one collection runs at a time; badger.ErrRejected is
+// returned while another one does.
+func (bs *BadgerStore) RunGC(discardRatio float64) (int, error) {
+	if discardRatio == 0 {
+		discardRatio = DefaultDiscardRatio
+	}
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	rewritten := 0
+	for {
+		err := bs.db.RunValueLogGC(discardRatio)
+		switch {
+		case err == nil:
+			rewritten++
+		case errors.Is(err, badger.ErrNoRewrite):
+			return rewritten, nil
+		default:
+			return rewritten, err
+		}
+	}
+}
+
+// Flush syncs the value log and the memtables to disk.
+func (bs *BadgerStore) Flush() error {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.db.Sync()
+}
+
+// StartGC garbage collects the value log in the background until the returned
+// function is called, which waits for a pass in progress to finish. Without
+// it, the space of deleted, expired and overwritten values is never
+// reclaimed. A pass is skipped while a collection started with RunGC runs.
+func (bs *BadgerStore) StartGC(config GCConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultGCInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	runs := registry.Counter(MetricGCRuns)
+	rewrittenFiles := registry.Counter(MetricGCRewrittenFiles)
+	reclaimedBytes := registry.Counter(MetricGCReclaimedBytes)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				before := bs.DiskUsage()
+				rewritten, err := bs.RunGC(config.DiscardRatio)
+				if err != nil && !errors.Is(err, badger.ErrRejected) {
+					logging.Or(bs.logger).Warn("Value log garbage collection failed", "error", err)
+				}
+				runs.Inc()
+				rewrittenFiles.Add(uint64(rewritten)) // #nosec G115 -- counts are non-negative
+				if reclaimed := before - bs.DiskUsage(); reclaimed > 0 {
+					reclaimedBytes.Add(uint64(reclaimed))
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
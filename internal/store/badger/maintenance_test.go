@@ -0,0 +1,52 @@
+package badger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+)
+
+func TestBadgerStore_Maintenance(t *testing.T) {
+	bs := createTestStore(t)
+	defer bs.Close()
+	for i := range 100 {
+		if err := bs.Put("k", []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := bs.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if usage := bs.DiskUsage(); usage < 0 {
+		t.Errorf("DiskUsage = %d, want a size", usage)
+	}
+	// Nothing is worth rewriting in a single value log file that is still written to
+	if rewritten, err := bs.RunGC(DefaultDiscardRatio); err != nil || rewritten != 0 {
+		t.Errorf("RunGC = %d, %v; want nothing rewritten", rewritten, err)
+	}
+}
+
+func TestBadgerStore_StartGC(t *testing.T) {
+	bs := createTestStore(t)
+	defer func() { _ = bs.Close() }()
+
+	registry := metrics.NewRegistry()
+	stop := bs.StartGC(GCConfig{Interval: 10 * time.Millisecond, Metrics: registry})
+	deadline := time.Now().Add(5 * time.Second)
+	for registry.Counter(MetricGCRuns).Value() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	stop()
+
+	runs := registry.Counter(MetricGCRuns).Value()
+	if runs < 2 {
+		t.Errorf("Expected at least 2 passes, got %d", runs)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got := registry.Counter(MetricGCRuns).Value(); got != runs {
+		t.Errorf("Expected no pass after stopping, got %d more", got-runs)
+	}
+}
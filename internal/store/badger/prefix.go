@@ -0,0 +1,49 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var _ store.PrefixDeleter = (*BadgerStore)(nil)
+
+// DeletePrefix counts the keys under prefix and drops them with Badger's
+// DropPrefix, which discards them from the memtables and data files rather
+// than writing a tombstone per key. Writers are held off until it returns.
+// An empty prefix drops every key. The keys are passed to deleted, if not
+// nil, as they are counted.
+func (bs *BadgerStore) DeletePrefix(prefix string, deleted func(key string)) (int64, error) {
+	bs.writeMu.Lock()
+	defer bs.writeMu.Unlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	var keys int64
+	err := bs.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = []byte(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			keys++
+			if deleted != nil {
+				deleted(string(it.Item().Key()))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if keys == 0 {
+		return 0, nil
+	}
+	if err := bs.db.DropPrefix([]byte(prefix)); err != nil {
+		return 0, fmt.Errorf("failed to drop prefix %q: %w", prefix, err)
+	}
+	bs.ops.CountDeletes(int(keys))
+	return keys, nil
+}
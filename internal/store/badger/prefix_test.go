@@ -0,0 +1,47 @@
+package badger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_DeletePrefix(t *testing.T) {
+	bs, err := NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = bs.Close() })
+
+	pairs := map[string][]byte{"tenant-b/x": []byte("kept")}
+	for i := range 100 {
+		pairs[fmt.Sprintf("tenant-a/%03d", i)] = []byte("v")
+	}
+	if err := bs.BatchPut(pairs); err != nil {
+		t.Fatal(err)
+	}
+	// Deleted keys are not counted again
+	if err := bs.Delete("tenant-a/000"); err != nil {
+		t.Fatal(err)
+	}
+
+	var deleted []string
+	n, err := bs.DeletePrefix("tenant-a/", func(key string) { deleted = append(deleted, key) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 99 || len(deleted) != 99 || deleted[0] != "tenant-a/001" {
+		t.Errorf("DeletePrefix() = %d, reporting %d keys; want 99 from tenant-a/001", n, len(deleted))
+	}
+	if left, err := bs.Scan(""); err != nil || len(left) != 1 || string(left["tenant-b/x"]) != "kept" {
+		t.Errorf("Scan() after DeletePrefix() = %q, %v; want only tenant-b/x", left, err)
+	}
+	// Writes go on once the prefix is dropped
+	if err := bs.Put("tenant-a/new", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.DeletePrefix(bs, "tenant-c/"); err != nil || n != 0 {
+		t.Errorf("DeletePrefix() of an empty prefix = %d, %v; want 0", n, err)
+	}
+}
@@ -0,0 +1,111 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// maxPendingLoadWrites bounds the memory used while loading a relocation stream
+const maxPendingLoadWrites = 256
+
+// Relocate moves the database to newPath while continuing to serve reads.
+//
+// The procedure opens a fresh database at newPath, blocks new writes, streams
+// every live key across, switches readers and writers over to the new
+// database, and finally closes the old one and renames its directory with a
+// ".retired-<timestamp>" suffix so it can be inspected or removed later.
+// Reads are only paused for the instant of the switch. Only the latest
+// version of each key is carried over.
+//
+// The store's configuration is not persisted anywhere, so the caller must
+// point future starts at newPath.
+func (bs *BadgerStore) Relocate(newPath string) (string, error) {
+	bs.relocateMu.Lock()
+	defer bs.relocateMu.Unlock()
+
+	bs.mu.RLock()
+	oldPath := bs.path
+	bs.mu.RUnlock()
+
+	if newPath == "" || newPath == oldPath {
+		return "", fmt.Errorf("relocation target must differ from the current path %q", oldPath)
+	}
+	if err := ensureEmptyDir(newPath); err != nil {
+		return "", err
+	}
+
+	newDB, err := badger.Open(bs.opts.WithDir(newPath).WithValueDir(newPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to open BadgerDB at %s: %w", newPath, err)
+	}
+
+	// Hold off writers until the switch so nothing is lost in the copy
+	bs.writeMu.Lock()
+	defer bs.writeMu.Unlock()
+
+	// Sequences record how far they were really used before being copied,
+	// and lease anew from the new database
+	if err := bs.releaseSequences(); err != nil {
+		_ = newDB.Close()
+		return "", fmt.Errorf("failed to release sequences: %w", err)
+	}
+
+	bs.mu.RLock()
+	err = copyDB(newDB, bs.db)
+	bs.mu.RUnlock()
+	if err != nil {
+		_ = newDB.Close()
+		return "", fmt.Errorf("failed to copy data to %s: %w", newPath, err)
+	}
+
+	bs.mu.Lock()
+	oldDB := bs.db
+	bs.db = newDB
+	bs.path = newPath
+	bs.opts = bs.opts.WithDir(newPath).WithValueDir(newPath)
+	bs.mu.Unlock()
+
+	if err := oldDB.Close(); err != nil {
+		return "", fmt.Errorf("switched to %s but failed to close old database: %w", newPath, err)
+	}
+
+	retiredPath := fmt.Sprintf("%s.retired-%d", oldPath, time.Now().Unix())
+	if err := os.Rename(oldPath, retiredPath); err != nil {
+		return "", fmt.Errorf("switched to %s but failed to retire %s: %w", newPath, oldPath, err)
+	}
+	return retiredPath, nil
+}
+
+// copyDB streams a backup of src straight into dst.
+func copyDB(dst, src *badger.DB) error {
+	reader, writer := io.Pipe()
+
+	go func() {
+		_, err := src.Backup(writer, 0)
+		writer.CloseWithError(err)
+	}()
+
+	err := dst.Load(reader, maxPendingLoadWrites)
+	_ = reader.CloseWithError(err)
+	return err
+}
+
+// ensureEmptyDir fails unless path does not exist or is an empty directory.
+func ensureEmptyDir(path string) error {
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot use %s as relocation target: %w", path, err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("relocation target %s is not empty", path)
+	}
+	return nil
+}
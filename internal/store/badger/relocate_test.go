@@ -0,0 +1,118 @@
+package badger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBadgerStore_Relocate(t *testing.T) {
+	t.Run("MovesDataAndRetiresOldDirectory", func(t *testing.T) {
+		store := createTestStore(t)
+		defer func() {
+			if err := store.Close(); err != nil {
+				t.Logf("Failed to close store: %v", err)
+			}
+		}()
+		oldPath := store.path
+
+		for i := 0; i < 100; i++ {
+			if err := store.Put(fmt.Sprintf("key-%03d", i), []byte(fmt.Sprintf("value-%d", i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		newPath := filepath.Join(t.TempDir(), "relocated")
+		retired, err := store.Relocate(newPath)
+		if err != nil {
+			t.Fatalf("Relocate failed: %v", err)
+		}
+		t.Cleanup(func() { _ = os.RemoveAll(retired) })
+
+		if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+			t.Errorf("Expected old path %s to be moved away, stat error: %v", oldPath, err)
+		}
+		if _, err := os.Stat(retired); err != nil {
+			t.Errorf("Expected retired directory %s to exist: %v", retired, err)
+		}
+
+		results, err := store.Scan("key-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 100 {
+			t.Errorf("Expected 100 keys after relocation, got %d", len(results))
+		}
+
+		if err := store.Put("after", []byte("relocation")); err != nil {
+			t.Fatalf("Put after relocation failed: %v", err)
+		}
+		value, found, err := store.Get("after")
+		if err != nil || !found || string(value) != "relocation" {
+			t.Errorf("Unexpected Get after relocation: %q %t %v", value, found, err)
+		}
+	})
+
+	t.Run("ServesReadsDuringRelocation", func(t *testing.T) {
+		store := createTestStore(t)
+		defer func() {
+			if err := store.Close(); err != nil {
+				t.Logf("Failed to close store: %v", err)
+			}
+		}()
+
+		if err := store.Put("hot", []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+
+		var stop atomic.Bool
+		var failures atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for !stop.Load() {
+					if _, found, err := store.Get("hot"); err != nil || !found {
+						failures.Add(1)
+					}
+				}
+			}()
+		}
+
+		retired, err := store.Relocate(filepath.Join(t.TempDir(), "relocated"))
+		stop.Store(true)
+		wg.Wait()
+		if err != nil {
+			t.Fatalf("Relocate failed: %v", err)
+		}
+		t.Cleanup(func() { _ = os.RemoveAll(retired) })
+
+		if failures.Load() != 0 {
+			t.Errorf("Expected reads to keep succeeding, got %d failures", failures.Load())
+		}
+	})
+
+	t.Run("RejectsNonEmptyTarget", func(t *testing.T) {
+		store := createTestStore(t)
+		defer func() {
+			if err := store.Close(); err != nil {
+				t.Logf("Failed to close store: %v", err)
+			}
+		}()
+
+		target := t.TempDir()
+		if err := os.WriteFile(filepath.Join(target, "occupied"), []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Relocate(target); err == nil {
+			t.Error("Expected error when relocating into a non-empty directory")
+		}
+		if _, err := store.Relocate(store.path); err == nil {
+			t.Error("Expected error when relocating onto the current path")
+		}
+	})
+}
@@ -0,0 +1,328 @@
+package badger
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+const (
+	// MetricPrunedVersions counts versions removed by retention pruning.
+	MetricPrunedVersions = "store_retention_pruned_versions_total"
+	// MetricPrunedBytes counts the estimated size of pruned versions, which
+	// is reclaimed from disk once Badger compacts and garbage collects them.
+	MetricPrunedBytes = "store_retention_pruned_bytes_total"
+
+	// DefaultRetentionInterval is the time between pruning passes.
+	DefaultRetentionInterval = time.Minute
+
+	// maxClockSamples bounds the memory used by the version clock.
+	maxClockSamples = 4096
+)
+
+// RetentionConfig configures background version pruning.
+type RetentionConfig struct {
+	Interval time.Duration             // Time between pruning passes; DefaultRetentionInterval if zero
+	Policy   store.RetentionPolicyFunc // Retention that applies to each key
+	Metrics  *metrics.Registry         // Registry for pruning metrics; metrics.Default if nil
+}
+
+// PruneResult summarizes a pruning pass.
+type PruneResult struct {
+	Keys     int   // Keys whose history was pruned
+	Versions int   // Versions removed
+	Bytes    int64 // Estimated size of the removed versions
+}
+
+// clockSample records that every version up to version was committed by at.
+type clockSample struct {
+	at      time.Time
+	version uint64
+}
+
+// versionClock maps Badger commit versions, which are counters, to wall
+// time by sampling the latest version periodically. Samples are kept in
+// memory only, so after a restart age-based retention treats existing
+// versions as new until enough time has been observed.
+type versionClock struct {
+	mu      sync.Mutex
+	samples []clockSample
+}
+
+// record adds a sample, thinning out older samples when the clock is full.
+func (c *versionClock) record(at time.Time, version uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.samples) >= maxClockSamples {
+		thinned := c.samples[:0]
+		for i := 0; i < len(c.samples); i += 2 {
+			thinned = append(thinned, c.samples[i])
+		}
+		c.samples = thinned
+	}
+	c.samples = append(c.samples, clockSample{at: at, version: version})
+}
+
+// watermark returns the highest version known to have been committed at or
+// before cutoff, or 0 if no sample is that old.
+func (c *versionClock) watermark(cutoff time.Time) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := sort.Search(len(c.samples), func(i int) bool { return c.samples[i].at.After(cutoff) })
+	if i == 0 {
+		return 0
+	}
+	return c.samples[i-1].version
+}
+
+// committedBy returns the time of the oldest sample covering version, which
+// is when the version is known to have been committed by, and false if the
+// version is newer than every sample.
+func (c *versionClock) committedBy(version uint64) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := sort.Search(len(c.samples), func(i int) bool { return c.samples[i].version >= version })
+	if i == len(c.samples) {
+		return time.Time{}, false
+	}
+	return c.samples[i].at, true
+}
+
+// StartRetention prunes version history in the background according to the
+// configured policy until the returned function is called.
+func (bs *BadgerStore) StartRetention(config RetentionConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultRetentionInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	prunedVersions := registry.Counter(MetricPrunedVersions)
+	prunedBytes := registry.Counter(MetricPrunedBytes)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				result, err := bs.Prune(config.Policy)
+				if err != nil {
+					logging.Or(bs.logger).Warn("Version retention pass failed", "error", err)
+				}
+				prunedVersions.Add(uint64(result.Versions)) // #nosec G115 -- counts are non-negative
+				prunedBytes.Add(uint64(result.Bytes))       // #nosec G115 -- sizes are non-negative
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
+
+// versionInfo describes one stored version of a key.
+type versionInfo struct {
+	version uint64
+	size    int64
+}
+
+// pruneCandidate is a key with versions to remove.
+type pruneCandidate struct {
+	key    []byte
+	latest uint64 // current version, which must not change before rewriting
+	keep   int    // number of newest versions to keep
+	pruned []versionInfo
+}
+
+// Prune removes the versions that policy no longer retains. Kept versions are
+// rewritten in order with the oldest marked to discard everything before it,
+// so their version numbers change but their values and order do not.
+// Histories ending in a delete are left for Badger's compaction to drop.
+func (bs *BadgerStore) Prune(policy store.RetentionPolicyFunc) (PruneResult, error) {
+	var result PruneResult
+	if policy == nil {
+		return result, nil
+	}
+
+	bs.mu.RLock()
+	now := time.Now()
+	bs.clock.record(now, bs.db.MaxVersion())
+	bs.mu.RUnlock()
+
+	candidates, err := bs.findPruneCandidates(policy, now)
+	if err != nil {
+		return result, err
+	}
+
+	for _, candidate := range candidates {
+		rewritten, err := bs.rewriteHistory(candidate)
+		if err != nil {
+			return result, err
+		}
+		if !rewritten {
+			continue
+		}
+		result.Keys++
+		for _, v := range candidate.pruned {
+			result.Versions++
+			result.Bytes += v.size
+		}
+	}
+	return result, nil
+}
+
+// findPruneCandidates walks every version of every key and selects the keys
+// with versions outside their retention policy.
+func (bs *BadgerStore) findPruneCandidates(policy store.RetentionPolicyFunc, now time.Time) ([]pruneCandidate, error) {
+	var candidates []pruneCandidate
+
+	err := bs.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.AllVersions = true
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		var key []byte
+		var versions []versionInfo
+		var deleted, complete bool
+		flush := func() {
+			if len(versions) > 0 && !deleted {
+				if candidate, ok := selectPruned(key, versions, policy(string(key)), bs.clock.watermark, now); ok {
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			if !bytes.Equal(item.Key(), key) {
+				flush()
+				key = item.KeyCopy(nil)
+				versions = versions[:0]
+				deleted = item.IsDeletedOrExpired()
+				complete = false
+			}
+			// Versions are visited newest first; those below a tombstone or a
+			// discard marker are already on their way out
+			if complete || deleted {
+				continue
+			}
+			if item.IsDeletedOrExpired() {
+				complete = true
+				continue
+			}
+			versions = append(versions, versionInfo{version: item.Version(), size: item.EstimatedSize()})
+			complete = item.DiscardEarlierVersions()
+		}
+		flush()
+		return nil
+	})
+
+	return candidates, err
+}
+
+// selectPruned applies policy to the versions of key, newest first.
+func selectPruned(key []byte, versions []versionInfo, policy store.RetentionPolicy, watermark func(time.Time) uint64, now time.Time) (pruneCandidate, bool) {
+	if policy.IsZero() {
+		return pruneCandidate{}, false
+	}
+
+	keep := len(versions)
+	if policy.KeepVersions > 0 && policy.KeepVersions < keep {
+		keep = policy.KeepVersions
+	}
+	if policy.MaxAge > 0 {
+		if mark := watermark(now.Add(-policy.MaxAge)); mark > 0 {
+			for i := 1; i < keep; i++ {
+				if versions[i].version <= mark {
+					keep = i
+					break
+				}
+			}
+		}
+	}
+	if keep == len(versions) {
+		return pruneCandidate{}, false
+	}
+
+	return pruneCandidate{
+		key:    key,
+		latest: versions[0].version,
+		keep:   keep,
+		pruned: append([]versionInfo(nil), versions[keep:]...),
+	}, true
+}
+
+// rewriteHistory rewrites the kept versions of a candidate, oldest first.
+// Writers are paused while a key is rewritten so the kept versions stay the
+// newest ones. Returns false if the key changed since it was selected.
+func (bs *BadgerStore) rewriteHistory(candidate pruneCandidate) (bool, error) {
+	bs.writeMu.Lock()
+	defer bs.writeMu.Unlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	var kept []*badger.Entry
+	err := bs.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.AllVersions = true
+		opts.Prefix = candidate.key
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(candidate.key); it.Valid() && len(kept) < candidate.keep; it.Next() {
+			item := it.Item()
+			if !bytes.Equal(item.Key(), candidate.key) {
+				break
+			}
+			if len(kept) == 0 && item.Version() != candidate.latest {
+				return nil
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			entry := badger.NewEntry(item.KeyCopy(nil), value).WithMeta(item.UserMeta())
+			entry.ExpiresAt = item.ExpiresAt()
+			kept = append(kept, entry)
+		}
+		return nil
+	})
+	if err != nil || len(kept) != candidate.keep {
+		return false, err
+	}
+
+	for i := len(kept) - 1; i >= 0; i-- {
+		entry := kept[i]
+		if i == len(kept)-1 {
+			entry = entry.WithDiscard()
+		}
+		if err := bs.db.Update(func(txn *badger.Txn) error { return txn.SetEntry(entry) }); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,175 @@
+package badger
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// liveVersions returns the values of key that pruning has not discarded, newest first
+func liveVersions(t *testing.T, bs *BadgerStore, key string) []string {
+	t.Helper()
+	var values []string
+	err := bs.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.AllVersions = true
+		opts.Prefix = []byte(key)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek([]byte(key)); it.Valid(); it.Next() {
+			item := it.Item()
+			if !bytes.Equal(item.Key(), []byte(key)) {
+				break
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			values = append(values, string(value))
+			if item.DiscardEarlierVersions() {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func putVersions(t *testing.T, bs *BadgerStore, key string, from, to int) {
+	t.Helper()
+	for i := from; i <= to; i++ {
+		if err := bs.Put(key, []byte(fmt.Sprintf("v%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBadgerStore_Prune(t *testing.T) {
+	t.Run("KeepVersions", func(t *testing.T) {
+		bs := createTestStore(t)
+		defer func() { _ = bs.Close() }()
+
+		putVersions(t, bs, "ns/config", 1, 5)
+		putVersions(t, bs, "other/config", 1, 3)
+		policy := func(key string) store.RetentionPolicy {
+			if key == "ns/config" {
+				return store.RetentionPolicy{KeepVersions: 2}
+			}
+			return store.RetentionPolicy{}
+		}
+
+		result, err := bs.Prune(policy)
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Keys != 1 || result.Versions != 3 || result.Bytes <= 0 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if got := liveVersions(t, bs, "ns/config"); !reflect.DeepEqual(got, []string{"v5", "v4"}) {
+			t.Errorf("Expected v5 and v4 to remain, got %v", got)
+		}
+		if got := liveVersions(t, bs, "other/config"); len(got) != 3 {
+			t.Errorf("Expected untouched history for other key, got %v", got)
+		}
+		if value, _, _ := bs.Get("ns/config"); string(value) != "v5" {
+			t.Errorf("Expected current value v5, got %q", value)
+		}
+
+		result, err = bs.Prune(policy)
+		if err != nil || result.Versions != 0 {
+			t.Errorf("Expected second pass to prune nothing, got %+v (err=%v)", result, err)
+		}
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		bs := createTestStore(t)
+		defer func() { _ = bs.Close() }()
+
+		putVersions(t, bs, "ns/config", 1, 3)
+		bs.clock.record(time.Now().Add(-time.Hour), bs.db.MaxVersion())
+		putVersions(t, bs, "ns/config", 4, 5)
+
+		result, err := bs.Prune(func(string) store.RetentionPolicy {
+			return store.RetentionPolicy{MaxAge: 30 * time.Minute}
+		})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Versions != 3 {
+			t.Errorf("Expected 3 old versions pruned, got %+v", result)
+		}
+		if got := liveVersions(t, bs, "ns/config"); !reflect.DeepEqual(got, []string{"v5", "v4"}) {
+			t.Errorf("Expected v5 and v4 to remain, got %v", got)
+		}
+	})
+
+	t.Run("KeepsCurrentVersion", func(t *testing.T) {
+		bs := createTestStore(t)
+		defer func() { _ = bs.Close() }()
+
+		putVersions(t, bs, "ns/config", 1, 2)
+		bs.clock.record(time.Now().Add(-time.Hour), bs.db.MaxVersion())
+
+		if _, err := bs.Prune(func(string) store.RetentionPolicy {
+			return store.RetentionPolicy{MaxAge: time.Minute}
+		}); err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if got := liveVersions(t, bs, "ns/config"); !reflect.DeepEqual(got, []string{"v2"}) {
+			t.Errorf("Expected only the current version to remain, got %v", got)
+		}
+	})
+
+	t.Run("SkipsDeletedKeys", func(t *testing.T) {
+		bs := createTestStore(t)
+		defer func() { _ = bs.Close() }()
+
+		putVersions(t, bs, "ns/config", 1, 3)
+		if err := bs.Delete("ns/config"); err != nil {
+			t.Fatal(err)
+		}
+		result, err := bs.Prune(func(string) store.RetentionPolicy { return store.RetentionPolicy{KeepVersions: 1} })
+		if err != nil || result.Versions != 0 {
+			t.Errorf("Expected deleted key to be skipped, got %+v (err=%v)", result, err)
+		}
+		if _, found, _ := bs.Get("ns/config"); found {
+			t.Error("Pruning resurrected a deleted key")
+		}
+	})
+}
+
+func TestBadgerStore_StartRetention(t *testing.T) {
+	bs := createTestStore(t)
+	defer func() { _ = bs.Close() }()
+	putVersions(t, bs, "ns/config", 1, 4)
+
+	registry := metrics.NewRegistry()
+	stop := bs.StartRetention(RetentionConfig{
+		Interval: 10 * time.Millisecond,
+		Policy:   func(string) store.RetentionPolicy { return store.RetentionPolicy{KeepVersions: 1} },
+		Metrics:  registry,
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for registry.Counter(MetricPrunedVersions).Value() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	stop()
+
+	if got := registry.Counter(MetricPrunedVersions).Value(); got != 3 {
+		t.Errorf("Expected 3 pruned versions, got %d", got)
+	}
+	if registry.Counter(MetricPrunedBytes).Value() == 0 {
+		t.Error("Expected pruned bytes to be recorded")
+	}
+}
@@ -0,0 +1,76 @@
+package badger
+
+import (
+	"errors"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var _ store.Sequencer = (*BadgerStore)(nil)
+
+// DefaultSequenceBandwidth is the count of numbers each sequence leases from
+// disk at once unless configured otherwise.
+const DefaultSequenceBandwidth = 1000
+
+// sequencePrefix is the internal key prefix under which Badger records how
+// far each named sequence has been leased.
+const sequencePrefix = "__clavis/named-sequences/"
+
+// NextSequence reserves the next n numbers of the sequence called name and
+// returns the first of them. Sequences lease their numbers from disk in
+// blocks of the configured bandwidth, so most calls touch no disk at all;
+// the numbers leased but not handed out are given back on Close and before
+// a relocation, and skipped after a crash.
+func (bs *BadgerStore) NextSequence(name string, n uint64) (uint64, error) {
+	bs.ops.CountPuts(1)
+	bs.writeMu.RLock()
+	defer bs.writeMu.RUnlock()
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	bs.seqMu.Lock()
+	defer bs.seqMu.Unlock()
+
+	seq, ok := bs.sequences[name]
+	if !ok {
+		var err error
+		if seq, err = bs.db.GetSequence([]byte(sequencePrefix+name), bs.sequenceBandwidth); err != nil {
+			return 0, err
+		}
+		if bs.sequences == nil {
+			bs.sequences = make(map[string]*badger.Sequence)
+		}
+		bs.sequences[name] = seq
+	}
+
+	// Badger sequences start at 0, which is skipped so that 0 never names a
+	// number handed out
+	first, err := seq.Next()
+	if err == nil && first == 0 {
+		first, err = seq.Next()
+	}
+	if err != nil {
+		return 0, err
+	}
+	// Holding seqMu keeps the numbers of the range consecutive
+	for range n - 1 {
+		if _, err := seq.Next(); err != nil {
+			return 0, err
+		}
+	}
+	return first, nil
+}
+
+// releaseSequences gives the numbers leased but not handed out back to the
+// database the sequences were opened on. The caller keeps NextSequence from
+// running, by holding writeMu or mu exclusively.
+func (bs *BadgerStore) releaseSequences() error {
+	bs.seqMu.Lock()
+	defer bs.seqMu.Unlock()
+	var errs []error
+	for name, seq := range bs.sequences {
+		errs = append(errs, seq.Release())
+		delete(bs.sequences, name)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package badger
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_NextSequence(t *testing.T) {
+	config := DefaultConfig(t.TempDir())
+	config.SequenceBandwidth = 10
+	bs, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent ranges never overlap, and new sequences start at 1
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				first, err := store.NextSequence(bs, "orders", 3)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				for n := first; n < first+3; n++ {
+					if seen[n] {
+						t.Errorf("Number %d handed out twice", n)
+					}
+					seen[n] = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for n := uint64(1); n <= 240; n++ {
+		if !seen[n] {
+			t.Fatalf("Expected numbers 1 to 240 to be handed out, %d was not", n)
+		}
+	}
+	if first, err := store.NextSequence(bs, "users", 1); err != nil || first != 1 {
+		t.Errorf("NextSequence() of another sequence = %d, %v; want 1", first, err)
+	}
+
+	// Numbers leased but not handed out are given back on relocation and
+	// on Close
+	if _, err := bs.Relocate(filepath.Join(t.TempDir(), "relocated")); err != nil {
+		t.Fatal(err)
+	}
+	if first, err := store.NextSequence(bs, "orders", 1); err != nil || first != 241 {
+		t.Errorf("NextSequence() after relocation = %d, %v; want 241", first, err)
+	}
+	path := bs.path
+	if err := bs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewWithPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = reopened.Close() })
+	if first, err := store.NextSequence(reopened, "orders", 1); err != nil || first != 242 {
+		t.Errorf("NextSequence() after reopening = %d, %v; want 242", first, err)
+	}
+
+	if _, err := store.NextSequence(reopened, "orders", 0); !errors.Is(err, store.ErrInvalidSequenceRange) {
+		t.Errorf("Expected ErrInvalidSequenceRange, got %v", err)
+	}
+}
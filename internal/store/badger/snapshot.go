@@ -0,0 +1,97 @@
+package badger
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/dgraph-io/badger/v4"
+)
+
+var _ store.SnapshotReader = (*BadgerStore)(nil)
+
+// VersionAt returns the latest version known from the version clock to have
+// been committed at or before t. The clock is sampled on every call, by
+// retention and by compaction filters, so the version may predate t by up to
+// the time between samples; times at or after now resolve to the latest
+// version. Past times require version history (NumVersionsToKeep above 1)
+// and a sample at least that old.
+func (bs *BadgerStore) VersionAt(t time.Time) (uint64, error) {
+	now := time.Now()
+	bs.mu.RLock()
+	latest := bs.db.MaxVersion()
+	bs.mu.RUnlock()
+	bs.clock.record(now, latest)
+
+	if !t.Before(now) {
+		return latest, nil
+	}
+	if bs.opts.NumVersionsToKeep <= 1 {
+		return 0, fmt.Errorf("%w: version history is disabled", store.ErrSnapshotUnavailable)
+	}
+	version := bs.clock.watermark(t)
+	if version == 0 {
+		return 0, fmt.Errorf("%w: no version is known to have been committed by %s", store.ErrSnapshotUnavailable, t.Format(time.RFC3339))
+	}
+	return version, nil
+}
+
+// NewIteratorAt returns an iterator over the pairs whose keys start with
+// prefix as of version. Keys whose history before version was pruned by
+// retention fail the iteration with ErrSnapshotUnavailable rather than being
+// reported with a newer value or as missing.
+func (bs *BadgerStore) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	if version == 0 {
+		return bs.NewIterator(prefix)
+	}
+	bs.ops.CountScan()
+	bs.mu.RLock()
+
+	txn := bs.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 10
+	opts.Prefix = []byte(prefix)
+	opts.AllVersions = true
+
+	return &badgerIterator{
+		bs:      bs,
+		txn:     txn,
+		it:      txn.NewIterator(opts),
+		prefix:  []byte(prefix),
+		version: version,
+	}, nil
+}
+
+// nextAt advances a versioned iterator from its current position to the next
+// key that existed at the iterator's version. Versions of a key are visited
+// newest first, so the first one at or below the version decides the key.
+func (bi *badgerIterator) nextAt() bool {
+	for ; bi.it.ValidForPrefix(bi.prefix); bi.it.Next() {
+		item := bi.it.Item()
+		if bytes.Equal(item.Key(), bi.decided) {
+			continue
+		}
+		if item.Version() > bi.version {
+			if item.DiscardEarlierVersions() {
+				bi.err = fmt.Errorf("%w: history of key %q before version %d was pruned", store.ErrSnapshotUnavailable, item.Key(), bi.version)
+				return false
+			}
+			continue
+		}
+		bi.decided = item.KeyCopy(bi.decided[:0])
+		if item.IsDeletedOrExpired() {
+			continue
+		}
+
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			bi.err = err
+			return false
+		}
+		bi.key = string(item.Key())
+		bi.value = value
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package badger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// snapshotPairs reads every pair under prefix at version.
+func snapshotPairs(t *testing.T, bs *BadgerStore, prefix string, version uint64) (map[string]string, error) {
+	t.Helper()
+	it, err := bs.NewIteratorAt(prefix, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = it.Close() }()
+	pairs := make(map[string]string)
+	for it.Next() {
+		pairs[it.Key()] = string(it.Value())
+	}
+	return pairs, it.Err()
+}
+
+func TestBadgerStore_NewIteratorAt(t *testing.T) {
+	bs := createTestStoreWithVersions(t, 10)
+
+	for key, value := range map[string]string{"a": "a1", "b": "b1", "c": "c1"} {
+		if err := bs.Put(key, []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bs.clock.record(time.Now(), bs.db.MaxVersion())
+	version, err := bs.VersionAt(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes after the snapshot version are not visible at it
+	if err := bs.Put("a", []byte("a2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Put("d", []byte("d1")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := snapshotPairs(t, bs, "", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "a1", "b": "b1", "c": "c1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v at version %d, got %v", want, version, got)
+	}
+	got, err = snapshotPairs(t, bs, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "a2", "c": "c1", "d": "d1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v at the latest version, got %v", want, got)
+	}
+
+	// Once retention prunes the history of a key, older snapshots fail
+	// instead of reporting its newer value
+	if _, err := bs.Prune(func(string) store.RetentionPolicy { return store.RetentionPolicy{KeepVersions: 1} }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := snapshotPairs(t, bs, "", version); !errors.Is(err, store.ErrSnapshotUnavailable) {
+		t.Errorf("Expected ErrSnapshotUnavailable after pruning, got %v", err)
+	}
+}
+
+func TestBadgerStore_VersionAt(t *testing.T) {
+	t.Run("WithoutHistory", func(t *testing.T) {
+		bs := createTestStore(t)
+		defer func() { _ = bs.Close() }()
+		if _, err := bs.VersionAt(time.Now().Add(time.Hour)); err != nil {
+			t.Errorf("Expected the latest version without history, got %v", err)
+		}
+		if _, err := bs.VersionAt(time.Now().Add(-time.Minute)); !errors.Is(err, store.ErrSnapshotUnavailable) {
+			t.Errorf("Expected ErrSnapshotUnavailable for a past time, got %v", err)
+		}
+	})
+
+	t.Run("FromClock", func(t *testing.T) {
+		bs := createTestStoreWithVersions(t, 10)
+		if _, err := bs.VersionAt(time.Now().Add(-time.Hour)); !errors.Is(err, store.ErrSnapshotUnavailable) {
+			t.Errorf("Expected ErrSnapshotUnavailable before the first clock sample, got %v", err)
+		}
+
+		if err := bs.Put("a", []byte("a1")); err != nil {
+			t.Fatal(err)
+		}
+		bs.clock.record(time.Now().Add(-time.Minute), bs.db.MaxVersion())
+		sampled := bs.db.MaxVersion()
+		if err := bs.Put("a", []byte("a2")); err != nil {
+			t.Fatal(err)
+		}
+
+		version, err := bs.VersionAt(time.Now().Add(-30 * time.Second))
+		if err != nil || version != sampled {
+			t.Errorf("Expected version %d, got %d (err=%v)", sampled, version, err)
+		}
+	})
+}
+
+func createTestStoreWithVersions(t *testing.T, keep int) *BadgerStore {
+	t.Helper()
+	config := DefaultConfig(t.TempDir())
+	config.NumVersionsToKeep = keep
+	config.SyncWrites = false
+	bs, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = bs.Close() })
+	return bs
+}
@@ -0,0 +1,94 @@
+package badger
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestBadgerStore_CollectMetrics(t *testing.T) {
+	bs := createTestStore(t)
+	if err := bs.Put("k", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := metrics.NewRegistry()
+	registry.AddCollector(bs.CollectMetrics)
+	s := registry.Snapshot()
+	if s.Gauges[MetricMaxVersion] == 0 {
+		t.Error("Expected the max version to be recorded")
+	}
+	if _, ok := s.Gauges[MetricLevelSizeBytes+`{level="0"}`]; !ok {
+		t.Errorf("Expected per-level sizes, got %v", s.Gauges)
+	}
+	for _, name := range []string{MetricBlockCacheHits, MetricCompactionPending, MetricCompactionBacklog, MetricLSMSizeBytes} {
+		if _, ok := s.Gauges[name]; !ok {
+			t.Errorf("Expected gauge %s to be recorded", name)
+		}
+	}
+
+	if err := bs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	closed := metrics.NewRegistry()
+	closed.AddCollector(bs.CollectMetrics)
+	if s := closed.Snapshot(); len(s.Gauges) != 0 {
+		t.Errorf("Expected nothing collected from a closed store, got %v", s.Gauges)
+	}
+}
+
+func TestBadgerStore_Stats(t *testing.T) {
+	dir := t.TempDir()
+	bs, err := NewWithPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.BatchPut(map[string][]byte{"a": []byte("1"), "b": []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Put("c", []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := bs.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bs.Scan(""); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := bs.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := store.OperationCounts{Gets: 1, Puts: 3, Deletes: 1, Scans: 1}
+	if stats.Operations != want {
+		t.Errorf("Operations = %+v, want %+v", stats.Operations, want)
+	}
+	if err := bs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bs.Stats(); err == nil {
+		t.Error("Expected Stats to fail on a closed store")
+	}
+
+	// Closing flushes the memtable to an SSTable, where keys are counted
+	bs, err = NewWithPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bs.Close()
+	stats, err = bs.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.EstimatedKeys < 2 || stats.LSMBytes == 0 {
+		t.Errorf("Stats = %+v, want the flushed keys counted", stats)
+	}
+	if stats.Operations != (store.OperationCounts{}) {
+		t.Errorf("Expected the counts to start over, got %+v", stats.Operations)
+	}
+}
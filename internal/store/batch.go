@@ -0,0 +1,50 @@
+package store
+
+// BatchPut stores all pairs in s, in one call when s implements Batcher and
+// one Put at a time otherwise. Only s itself is checked for Batcher, not the
+// stores it wraps, so wrappers that act on individual writes are not bypassed.
+func BatchPut(s Store, pairs map[string][]byte) error {
+	if batcher, ok := s.(Batcher); ok {
+		return batcher.BatchPut(pairs)
+	}
+	for key, value := range pairs {
+		if err := s.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchGet retrieves the values of keys from s, in one call when s implements
+// Batcher and one Get at a time otherwise. Missing keys are absent from the
+// result.
+func BatchGet(s Store, keys []string) (map[string][]byte, error) {
+	if batcher, ok := s.(Batcher); ok {
+		return batcher.BatchGet(keys)
+	}
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, found, err := s.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// BatchDelete removes keys from s, in one call when s implements Batcher and
+// one Delete at a time otherwise.
+func BatchDelete(s Store, keys []string) error {
+	if batcher, ok := s.(Batcher); ok {
+		return batcher.BatchDelete(keys)
+	}
+	for _, key := range keys {
+		if err := s.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
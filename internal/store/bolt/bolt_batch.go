@@ -0,0 +1,72 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BatchPut stores all pairs in a single read-write transaction
+func (bs *BoltStore) BatchPut(pairs map[string][]byte) error {
+	bs.ops.CountPuts(len(pairs))
+	for key := range pairs {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	return bs.update(func(b *bolt.Bucket) error {
+		for key, value := range pairs {
+			if err := b.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BatchGet retrieves the values of keys in a single read transaction
+func (bs *BoltStore) BatchGet(keys []string) (map[string][]byte, error) {
+	bs.ops.CountGets(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	result := make(map[string][]byte, len(keys))
+	err := bs.view(func(b *bolt.Bucket) error {
+		for _, key := range keys {
+			if value, found := lookup(b, []byte(key)); found {
+				result[key] = clone(value)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchDelete removes keys in a single read-write transaction
+func (bs *BoltStore) BatchDelete(keys []string) error {
+	bs.ops.CountDeletes(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	return bs.update(func(b *bolt.Bucket) error {
+		for _, key := range keys {
+			if err := b.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var _ store.Batcher = (*BoltStore)(nil)
@@ -0,0 +1,136 @@
+package bolt
+
+import (
+	"bytes"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// iteratorPageSize is how many pairs an iterator reads in each transaction.
+const iteratorPageSize = 256
+
+// boltIterator reads the pairs of a key range a page at a time, each page in
+// a read transaction of its own: a read transaction left open for a whole
+// iteration would hold back writers that need to grow the file. Pairs written
+// in the part of the range not read yet are seen.
+type boltIterator struct {
+	bs   *BoltStore
+	r    store.KeyRange
+	page []store.KeyValue
+	pos  int
+	last []byte // Key of the last pair read, nil before the first page
+	done bool   // Whether the range holds no pair beyond the page
+	err  error
+}
+
+// NewIterator returns an iterator over the pairs whose keys start with prefix
+func (bs *BoltStore) NewIterator(prefix string) (store.Iterator, error) {
+	return bs.NewRangeIterator(store.PrefixRange(prefix))
+}
+
+// NewRangeIterator returns an iterator over the pairs whose keys are in r
+func (bs *BoltStore) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	bs.ops.CountScan()
+	if err := bs.Ping(); err != nil {
+		return nil, err
+	}
+	return &boltIterator{bs: bs, r: r}, nil
+}
+
+func (it *boltIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	if it.pos < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+	if it.err = it.fill(); it.err != nil {
+		return false
+	}
+	it.pos = 0
+	return len(it.page) > 0
+}
+
+// fill reads the next page of pairs
+func (it *boltIterator) fill() error {
+	it.page = it.page[:0]
+	return it.bs.view(func(b *bolt.Bucket) error {
+		c := b.Cursor()
+		step := c.Next
+		if it.r.Reverse {
+			step = c.Prev
+		}
+		for k, v := it.seek(c); len(it.page) < iteratorPageSize; k, v = step() {
+			// Keys come in order, so the first one out of the range ends it
+			if k == nil || !it.r.Contains(string(k)) {
+				it.done = true
+				break
+			}
+			pair := store.KeyValue{Key: string(k)}
+			if !it.r.KeysOnly {
+				pair.Value = clone(v)
+			}
+			it.page = append(it.page, pair)
+		}
+		if len(it.page) > 0 {
+			it.last = []byte(it.page[len(it.page)-1].Key)
+		}
+		return nil
+	})
+}
+
+// seek positions c on the first pair of the next page
+func (it *boltIterator) seek(c *bolt.Cursor) ([]byte, []byte) {
+	if !it.r.Reverse {
+		if it.last == nil {
+			return c.Seek([]byte(it.r.Start))
+		}
+		k, v := c.Seek(it.last)
+		if bytes.Equal(k, it.last) {
+			return c.Next()
+		}
+		return k, v
+	}
+
+	// In reverse, pages start at the last key before the end of the range
+	// or before the last key read
+	before := it.last
+	if before == nil {
+		if it.r.End == "" {
+			return c.Last()
+		}
+		before = []byte(it.r.End)
+	}
+	if k, _ := c.Seek(before); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+func (it *boltIterator) Key() string {
+	return it.page[it.pos].Key
+}
+
+func (it *boltIterator) Value() []byte {
+	return it.page[it.pos].Value
+}
+
+func (it *boltIterator) Err() error {
+	return it.err
+}
+
+func (it *boltIterator) Close() error {
+	it.page = nil
+	it.done = true
+	return nil
+}
+
+var (
+	_ store.IteratorProvider      = (*BoltStore)(nil)
+	_ store.RangeIteratorProvider = (*BoltStore)(nil)
+)
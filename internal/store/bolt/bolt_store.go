@@ -0,0 +1,253 @@
+package bolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore keeps the pairs in one bucket of a single bbolt file, a B+tree
+// that is memory-mapped rather than cached, for deployments that favor a
+// small memory footprint over write throughput. Writes are serialized by
+// bbolt, and every write is a transaction of its own.
+type BoltStore struct {
+	db     *bolt.DB
+	bucket []byte
+	// ops counts the operations served, for Stats
+	ops store.OperationCounters
+}
+
+func New(config *BoltStoreConfig) (*BoltStore, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	bucket := config.Bucket
+	if bucket == "" {
+		bucket = DefaultBucket
+	}
+
+	db, err := bolt.Open(config.Path, 0o600, config.ToBoltOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database: %w", err)
+	}
+	bs := &BoltStore{db: db, bucket: []byte(bucket)}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bs.bucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create bucket %q: %w", bucket, err)
+	}
+	return bs, nil
+}
+
+func NewWithPath(path string) (*BoltStore, error) {
+	return New(DefaultConfig(path))
+}
+
+// Close the database file, once the transactions in flight are done
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+// Ping reports whether the database is still open
+func (bs *BoltStore) Ping() error {
+	return bs.view(func(*bolt.Bucket) error { return nil })
+}
+
+// view runs fn with the bucket in a read-only transaction
+func (bs *BoltStore) view(fn func(b *bolt.Bucket) error) error {
+	return closedError(bs.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(bs.bucket))
+	}))
+}
+
+// update runs fn with the bucket in a read-write transaction, committed if
+// fn succeeds
+func (bs *BoltStore) update(fn func(b *bolt.Bucket) error) error {
+	return closedError(bs.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(bs.bucket))
+	}))
+}
+
+// closedError reports calls on a closed database the way the other stores do
+func closedError(err error) error {
+	if errors.Is(err, bolt.ErrDatabaseNotOpen) {
+		return fmt.Errorf("store is closed")
+	}
+	return err
+}
+
+// lookup returns the value stored at key in b, which is only valid for the
+// transaction. Unlike Bucket.Get, it tells empty values from missing keys.
+func lookup(b *bolt.Bucket, key []byte) ([]byte, bool) {
+	k, v := b.Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, false
+	}
+	return v, true
+}
+
+// clone copies a value out of the memory-mapped file, keeping empty values
+// apart from missing ones
+func clone(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return bytes.Clone(value)
+}
+
+// Get the value associated with the key
+func (bs *BoltStore) Get(key string) ([]byte, bool, error) {
+	var value []byte
+	found, err := bs.GetView(key, func(v []byte) error {
+		value = clone(v)
+		return nil
+	})
+	return value, found, err
+}
+
+// GetView calls fn with the value associated with the key within the read
+// transaction, without copying it out of the memory-mapped file
+func (bs *BoltStore) GetView(key string, fn func(value []byte) error) (bool, error) {
+	bs.ops.CountGets(1)
+	if key == "" {
+		return false, fmt.Errorf("key cannot be empty")
+	}
+
+	var found bool
+	err := bs.view(func(b *bolt.Bucket) error {
+		var value []byte
+		if value, found = lookup(b, []byte(key)); !found {
+			return nil
+		}
+		return fn(value)
+	})
+	return found, err
+}
+
+// Store the value associated with the key
+func (bs *BoltStore) Put(key string, value []byte) error {
+	bs.ops.CountPuts(1)
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	return bs.update(func(b *bolt.Bucket) error {
+		return b.Put([]byte(key), value)
+	})
+}
+
+// Remove the key and its associated value from the store
+func (bs *BoltStore) Delete(key string) error {
+	bs.ops.CountDeletes(1)
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	return bs.update(func(b *bolt.Bucket) error {
+		return b.Delete([]byte(key))
+	})
+}
+
+// Retrieve all key-value pairs that start with the given prefix
+func (bs *BoltStore) Scan(prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	err := bs.ForEach(prefix, func(key string, value []byte) error {
+		result[key] = clone(value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ForEach calls fn with every pair whose key starts with prefix, in key
+// order, within a single read transaction
+func (bs *BoltStore) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	bs.ops.CountScan()
+	return bs.view(func(b *bolt.Bucket) error {
+		c := b.Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			if err := fn(string(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stats reports the number of keys and the operations served. The keys and
+// values are read from the memory-mapped file, so no size is reported.
+func (bs *BoltStore) Stats() (store.Stats, error) {
+	var stats store.Stats
+	err := bs.view(func(b *bolt.Bucket) error {
+		stats.EstimatedKeys = int64(b.Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return store.Stats{}, err
+	}
+	stats.Operations = bs.ops.OperationCounts()
+	return stats, nil
+}
+
+// Increment adds delta to the counter at key within a single read-write
+// transaction
+func (bs *BoltStore) Increment(key string, delta int64, encoding store.CounterEncoding) (int64, error) {
+	bs.ops.CountPuts(1)
+	if key == "" {
+		return 0, fmt.Errorf("key cannot be empty")
+	}
+
+	var next int64
+	err := bs.update(func(b *bolt.Bucket) error {
+		current, _ := lookup(b, []byte(key))
+		value, encoded, err := store.AddCounter(encoding, current, delta)
+		if err != nil {
+			return err
+		}
+		next = value
+		return b.Put([]byte(key), encoded)
+	})
+	return next, err
+}
+
+// DeletePrefix deletes every key under prefix in a single read-write
+// transaction. An empty prefix deletes every key.
+func (bs *BoltStore) DeletePrefix(prefix string, deleted func(key string)) (int64, error) {
+	var keys [][]byte
+	err := bs.update(func(b *bolt.Bucket) error {
+		// Keys read from a cursor are only valid until the bucket changes
+		c := b.Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+			if deleted != nil {
+				deleted(string(k))
+			}
+		}
+		for _, key := range keys {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	bs.ops.CountDeletes(len(keys))
+	return int64(len(keys)), nil
+}
+
+var (
+	_ store.Store         = (*BoltStore)(nil)
+	_ store.Pinger        = (*BoltStore)(nil)
+	_ store.Incrementer   = (*BoltStore)(nil)
+	_ store.Viewer        = (*BoltStore)(nil)
+	_ store.ForEacher     = (*BoltStore)(nil)
+	_ store.PrefixDeleter = (*BoltStore)(nil)
+)
@@ -0,0 +1,45 @@
+package bolt
+
+import (
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBucket is the bucket the pairs are kept in when none is configured.
+const DefaultBucket = "clavis"
+
+// BoltStoreConfig holds the configuration options for bbolt
+type BoltStoreConfig struct {
+	store.StoreConfig        // Embedded struct with common config
+	Path              string // bbolt-specific: database file path
+	Bucket            string // bbolt-specific: bucket holding the pairs; DefaultBucket if empty
+	SyncWrites        bool   // bbolt-specific: fsync the file after every commit
+
+	// OpenTimeout is how long New waits for another process to release the
+	// lock on the file; forever if zero
+	OpenTimeout time.Duration
+}
+
+// DefaultConfig returns a BoltStoreConfig with sensible defaults
+func DefaultConfig(path string) *BoltStoreConfig {
+	return &BoltStoreConfig{
+		StoreConfig: store.StoreConfig{
+			LoggingLevel:      3, // ERROR level
+			NumVersionsToKeep: 1,
+		},
+		Path:        path,
+		Bucket:      DefaultBucket,
+		SyncWrites:  true,
+		OpenTimeout: time.Second,
+	}
+}
+
+// ToBoltOptions converts BoltStoreConfig to bolt.Options
+func (c *BoltStoreConfig) ToBoltOptions() *bolt.Options {
+	return &bolt.Options{
+		Timeout: c.OpenTimeout,
+		NoSync:  !c.SyncWrites,
+	}
+}
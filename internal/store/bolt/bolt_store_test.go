@@ -0,0 +1,196 @@
+package bolt
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func createTestStore(t *testing.T) *BoltStore {
+	t.Helper()
+	bs, err := NewWithPath(filepath.Join(t.TempDir(), "clavis.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = bs.Close() })
+	return bs
+}
+
+func TestBoltStore_Configuration(t *testing.T) {
+	t.Run("NilConfigurationError", func(t *testing.T) {
+		if _, err := New(nil); err == nil {
+			t.Error("Expected error for nil config")
+		}
+	})
+
+	t.Run("BucketsAreSeparate", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clavis.db")
+		first, err := New(&BoltStoreConfig{Path: path, Bucket: "first"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Put("key", []byte("first")); err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Without fsync, as tests and bulk loads may prefer
+		second, err := New(&BoltStoreConfig{Path: path, Bucket: "second", SyncWrites: false})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = second.Close() }()
+		if _, found, err := second.Get("key"); err != nil || found {
+			t.Errorf("Expected the key of another bucket to be missing, got %v %v", found, err)
+		}
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clavis.db")
+		bs, err := NewWithPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bs.Put("key", []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+		if err := bs.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened, err := NewWithPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = reopened.Close() }()
+		if value, found, err := reopened.Get("key"); err != nil || !found || string(value) != "value" {
+			t.Errorf("Expected the value to survive reopening, got %q %v %v", value, found, err)
+		}
+	})
+}
+
+func TestBoltStore_Operations(t *testing.T) {
+	bs := createTestStore(t)
+
+	if err := bs.Put("user:1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Put("user:2", []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Put("product:1", []byte("book")); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found, err := bs.Get("user:1"); err != nil || !found || string(value) != "alice" {
+		t.Errorf("Get() = %q, %v, %v; want alice", value, found, err)
+	}
+	if value, found, err := bs.Get("user:2"); err != nil || !found || value == nil || len(value) != 0 {
+		t.Errorf("Get() of an empty value = %q, %v, %v; want an empty value", value, found, err)
+	}
+	if _, found, err := bs.Get("user:3"); err != nil || found {
+		t.Errorf("Get() of a missing key = %v, %v; want not found", found, err)
+	}
+	if _, _, err := bs.Get(""); err == nil {
+		t.Error("Expected error for empty key")
+	}
+
+	pairs, err := bs.Scan("user:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || string(pairs["user:1"]) != "alice" {
+		t.Errorf("Scan() = %q, want both users", pairs)
+	}
+
+	if err := bs.Delete("user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := bs.Get("user:1"); found {
+		t.Error("Expected the deleted key to be missing")
+	}
+
+	stats, err := bs.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.EstimatedKeys != 2 || stats.Operations.Puts != 3 || stats.Operations.Deletes != 1 {
+		t.Errorf("Stats() = %+v, want 2 keys after 3 puts and 1 delete", stats)
+	}
+
+	if err := bs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.Ping(); err == nil {
+		t.Error("Expected Ping to fail on a closed store")
+	}
+	if err := bs.Put("key", []byte("value")); err == nil {
+		t.Error("Expected error when writing to a closed store")
+	}
+}
+
+func TestBoltStore_Batch(t *testing.T) {
+	bs := createTestStore(t)
+
+	if err := bs.BatchPut(map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := bs.BatchGet([]string{"a", "c", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || string(values["a"]) != "1" || string(values["c"]) != "3" {
+		t.Errorf("BatchGet() = %q, want a and c", values)
+	}
+	if err := bs.BatchDelete([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, err := bs.Scan(""); err != nil || len(pairs) != 1 {
+		t.Errorf("Scan() after BatchDelete() = %q, %v; want only c", pairs, err)
+	}
+	if err := bs.BatchPut(map[string][]byte{"d": nil, "": nil}); err == nil {
+		t.Error("Expected a batch with an empty key to be rejected")
+	}
+	if _, found, _ := bs.Get("d"); found {
+		t.Error("Expected no pair of a rejected batch to be stored")
+	}
+}
+
+func TestBoltStore_Increment(t *testing.T) {
+	bs := createTestStore(t)
+
+	for want := int64(1); want <= 3; want++ {
+		if n, err := store.Increment(t.Context(), bs, "hits", 1, store.CounterASCII); err != nil || n != want {
+			t.Fatalf("Increment() = %d, %v; want %d", n, err, want)
+		}
+	}
+	if err := bs.Put("name", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bs.Increment("name", 1, store.CounterASCII); !errors.Is(err, store.ErrNotCounter) {
+		t.Errorf("Increment() of a value that is not a counter = %v, want ErrNotCounter", err)
+	}
+}
+
+func TestBoltStore_DeletePrefix(t *testing.T) {
+	bs := createTestStore(t)
+	if err := bs.BatchPut(map[string][]byte{"tenant-a/1": nil, "tenant-a/2": nil, "tenant-b/1": []byte("kept")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var deleted []string
+	n, err := bs.DeletePrefix("tenant-a/", func(key string) { deleted = append(deleted, key) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(deleted) != 2 || deleted[0] != "tenant-a/1" {
+		t.Errorf("DeletePrefix() = %d reporting %q, want tenant-a/1 and tenant-a/2", n, deleted)
+	}
+	if left, err := bs.Scan(""); err != nil || len(left) != 1 || string(left["tenant-b/1"]) != "kept" {
+		t.Errorf("Scan() after DeletePrefix() = %q, %v; want only tenant-b/1", left, err)
+	}
+}
@@ -0,0 +1,301 @@
+// Package cache wraps a store with an in-memory LRU cache of the values read
+// with Get, so hot keys are served without reaching the wrapped store. Every
+// write made through the cache invalidates the keys it touches, so the cache
+// must sit beneath every other wrapper that writes, with nothing writing to
+// the wrapped store directly.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// MetricHits counts reads served from the cache.
+	MetricHits = "store_cache_hits_total"
+	// MetricMisses counts reads passed on to the wrapped store.
+	MetricMisses = "store_cache_misses_total"
+	// MetricEvictions counts values evicted to stay within the limits.
+	MetricEvictions = "store_cache_evictions_total"
+	// MetricEntries is the number of cached values.
+	MetricEntries = "store_cache_entries"
+	// MetricBytes is the size of the cached keys and values.
+	MetricBytes = "store_cache_bytes"
+
+	// DefaultMaxEntries is the MaxEntries of a Config that leaves it zero.
+	DefaultMaxEntries = 10_000
+	// DefaultMaxBytes is the MaxBytes of a Config that leaves it zero.
+	DefaultMaxBytes = 64 << 20
+)
+
+// Config bounds the cache. The least recently read values are evicted once
+// either limit is reached.
+type Config struct {
+	MaxEntries int               // Values cached at most; DefaultMaxEntries if zero
+	MaxBytes   int64             // Size of the cached keys and values at most; DefaultMaxBytes if zero
+	Metrics    *metrics.Registry // Registry for cache metrics; metrics.Default if nil
+}
+
+// Store caches the values read from the wrapped store.
+type Store struct {
+	store.Passthrough
+	config Config
+
+	mu      sync.Mutex
+	lru     *list.List // Of *entry, most recently read first
+	entries map[string]*list.Element
+	bytes   int64
+	// writes counts invalidations, so a value read from the wrapped store
+	// while a write to it was in progress is not cached.
+	writes uint64
+
+	hits, misses, evictions *metrics.Counter
+	entryCount, byteCount   *metrics.Gauge
+}
+
+type entry struct {
+	key   string
+	value []byte
+}
+
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// New wraps base.
+func New(base store.Store, config Config) *Store {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultMaxEntries
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultMaxBytes
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	return &Store{
+		Passthrough: store.Passthrough{Store: base},
+		config:      config,
+		lru:         list.New(),
+		entries:     make(map[string]*list.Element),
+		hits:        registry.Counter(MetricHits),
+		misses:      registry.Counter(MetricMisses),
+		evictions:   registry.Counter(MetricEvictions),
+		entryCount:  registry.Gauge(MetricEntries),
+		byteCount:   registry.Gauge(MetricBytes),
+	}
+}
+
+var (
+	_ store.Store             = (*Store)(nil)
+	_ store.Wrapper           = (*Store)(nil)
+	_ store.Batcher           = (*Store)(nil)
+	_ store.Expirer           = (*Store)(nil)
+	_ store.ContextWriter     = (*Store)(nil)
+	_ store.ContextReader     = (*Store)(nil)
+	_ store.ConditionalPutter = (*Store)(nil)
+	_ store.Viewer            = (*Store)(nil)
+	_ store.Backuper          = (*Store)(nil)
+)
+
+// Get returns the cached value of key, reading it from the wrapped store on
+// a miss.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	if element, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(element)
+		value := clone(element.Value.(*entry).value)
+		s.mu.Unlock()
+		s.hits.Inc()
+		return value, true, nil
+	}
+	writes := s.writes
+	s.mu.Unlock()
+	s.misses.Inc()
+
+	value, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return value, found, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writes == writes {
+		s.add(&entry{key: key, value: clone(value)})
+	}
+	return value, true, nil
+}
+
+// GetView calls fn with the cached value of key, reading it from the wrapped
+// store on a miss. Cached values are never modified, so fn is handed them in
+// place.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	s.mu.Lock()
+	if element, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(element)
+		value := element.Value.(*entry).value
+		s.mu.Unlock()
+		s.hits.Inc()
+		return true, fn(value)
+	}
+	writes := s.writes
+	s.mu.Unlock()
+	s.misses.Inc()
+
+	var fetched *entry
+	found, err := store.GetView(s.Store, key, func(value []byte) error {
+		fetched = &entry{key: key, value: clone(value)}
+		return fn(value)
+	})
+	if err != nil || !found {
+		return found, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writes == writes {
+		s.add(fetched)
+	}
+	return true, nil
+}
+
+// add caches e, evicting the least recently read values beyond the limits.
+// s.mu must be held.
+func (s *Store) add(e *entry) {
+	if e.size() > s.config.MaxBytes {
+		return
+	}
+	if _, ok := s.entries[e.key]; ok {
+		return
+	}
+	s.entries[e.key] = s.lru.PushFront(e)
+	s.bytes += e.size()
+	for s.lru.Len() > s.config.MaxEntries || s.bytes > s.config.MaxBytes {
+		s.remove(s.lru.Back())
+		s.evictions.Inc()
+	}
+	s.updateGauges()
+}
+
+// remove drops a cached value. s.mu must be held.
+func (s *Store) remove(element *list.Element) {
+	e := s.lru.Remove(element).(*entry)
+	delete(s.entries, e.key)
+	s.bytes -= e.size()
+}
+
+func (s *Store) updateGauges() {
+	s.entryCount.Set(int64(s.lru.Len()))
+	s.byteCount.Set(s.bytes)
+}
+
+// invalidate drops the cached values of keys after a write to them, whether
+// it succeeded or not.
+func (s *Store) invalidate(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes++
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.remove(element)
+		}
+	}
+	s.updateGauges()
+}
+
+// Purge drops every cached value, such as after the wrapped store was
+// written to directly.
+func (s *Store) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes++
+	s.lru.Init()
+	clear(s.entries)
+	s.bytes = 0
+	s.updateGauges()
+}
+
+// Put stores the value and invalidates the key.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	defer s.invalidate(key)
+	return store.PutContext(ctx, s.Store, key, value)
+}
+
+// PutIfVersion conditionally stores the value and invalidates the key.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	defer s.invalidate(key)
+	return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+}
+
+// Delete removes the key and invalidates it.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	defer s.invalidate(key)
+	return store.DeleteContext(ctx, s.Store, key)
+}
+
+// BatchPut stores the pairs and invalidates their keys.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	defer s.invalidate(keys...)
+	return store.BatchPutContext(ctx, s.Store, pairs)
+}
+
+// BatchDelete removes the keys and invalidates them.
+func (s *Store) BatchDelete(keys []string) error {
+	defer s.invalidate(keys...)
+	return store.BatchDelete(s.Store, keys)
+}
+
+// ExpireKeys removes the keys as expired and invalidates them.
+func (s *Store) ExpireKeys(keys []string) error {
+	defer s.invalidate(keys...)
+	return store.ExpireKeys(s.Store, keys)
+}
+
+// Backup dumps the wrapped store.
+func (s *Store) Backup(w io.Writer, since uint64) (uint64, error) {
+	return store.Backup(s.Store, w, since)
+}
+
+// Restore loads a dump into the wrapped store and purges the cache, as the
+// restored keys bypass it.
+func (s *Store) Restore(r io.Reader, since uint64) error {
+	defer s.Purge()
+	return store.Restore(s.Store, r, since)
+}
+
+func clone(value []byte) []byte {
+	return append([]byte(nil), value...)
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T, config Config) (*Store, *memory.MemoryStore, *metrics.Registry) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Metrics = metrics.NewRegistry()
+	return New(base, config), base, config.Metrics
+}
+
+func TestStore_Get(t *testing.T) {
+	s, base, registry := newStore(t, Config{})
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		value, found, err := s.Get("a")
+		if err != nil || !found || string(value) != "1" {
+			t.Fatalf("Get = %q, %v, %v; want the stored value", value, found, err)
+		}
+		value[0] = 'x' // Callers own the values they get
+	}
+	if hits, misses := registry.Counter(MetricHits).Value(), registry.Counter(MetricMisses).Value(); hits != 2 || misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %d and %d", hits, misses)
+	}
+
+	// Writes made beneath the cache are not seen until the key is invalidated
+	if err := base.Put("a", []byte("direct")); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := s.Get("a"); string(value) != "1" {
+		t.Errorf("Expected the cached value, got %q", value)
+	}
+
+	if _, found, _ := s.Get("missing"); found {
+		t.Error("Expected a missing key not to be found")
+	}
+	if err := s.Put("missing", []byte("now")); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, _ := s.Get("missing"); !found || string(value) != "now" {
+		t.Errorf("Expected missing keys not to be cached, got %q", value)
+	}
+}
+
+func TestStore_Invalidation(t *testing.T) {
+	s, _, _ := newStore(t, Config{})
+	writes := map[string]func() error{
+		"Put":          func() error { return s.Put("k", []byte("new")) },
+		"Delete":       func() error { return s.Delete("k") },
+		"BatchPut":     func() error { return store.BatchPut(s, map[string][]byte{"k": []byte("new")}) },
+		"BatchDelete":  func() error { return store.BatchDelete(s, []string{"k"}) },
+		"ExpireKeys":   func() error { return store.ExpireKeys(s, []string{"k"}) },
+	}
+	for name, write := range writes {
+		if err := s.Put("k", []byte("old")); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := s.Get("k"); err != nil {
+			t.Fatal(err)
+		}
+		if err := write(); err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		want, wantFound, _ := s.Store.Get("k")
+		if value, found, _ := s.Get("k"); found != wantFound || string(value) != string(want) {
+			t.Errorf("After %s, Get = %q, %v; want %q, %v", name, value, found, want, wantFound)
+		}
+	}
+}
+
+func TestStore_Eviction(t *testing.T) {
+	t.Run("MaxEntries", func(t *testing.T) {
+		s, _, registry := newStore(t, Config{MaxEntries: 2})
+		for _, key := range []string{"a", "b", "c"} {
+			if err := s.Put(key, []byte(key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, _, _ = s.Get("a")
+		_, _, _ = s.Get("b")
+		_, _, _ = s.Get("a") // b is now the least recently read
+		_, _, _ = s.Get("c")
+		if n := registry.Gauge(MetricEntries).Value(); n != 2 {
+			t.Errorf("Expected 2 cached entries, got %d", n)
+		}
+		if _, ok := s.entries["b"]; ok {
+			t.Error("Expected the least recently read key to be evicted")
+		}
+		if n := registry.Counter(MetricEvictions).Value(); n != 1 {
+			t.Errorf("Expected 1 eviction, got %d", n)
+		}
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		s, _, registry := newStore(t, Config{MaxBytes: 10})
+		if err := s.Put("a", []byte("12345")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Put("b", []byte("12345")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Put("huge", []byte("12345678901")); err != nil {
+			t.Fatal(err)
+		}
+		_, _, _ = s.Get("a")
+		_, _, _ = s.Get("b")
+		_, _, _ = s.Get("huge")
+		if n := registry.Gauge(MetricBytes).Value(); n != 6 {
+			t.Errorf("Expected only b within the byte limit, got %d bytes", n)
+		}
+		if _, ok := s.entries["huge"]; ok {
+			t.Error("Expected a value larger than the cache not to be cached")
+		}
+	})
+}
+
+func TestStore_GetView(t *testing.T) {
+	s, _, registry := newStore(t, Config{})
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		var viewed string
+		found, err := s.GetView("a", func(value []byte) error {
+			viewed = string(value)
+			return nil
+		})
+		if err != nil || !found || viewed != "1" {
+			t.Fatalf("GetView = %v, %v viewing %q; want the stored value", found, err, viewed)
+		}
+	}
+	if value, _, _ := s.Get("a"); string(value) != "1" {
+		t.Errorf("Expected the viewed value to be cached, got %q", value)
+	}
+	if hits, misses := registry.Counter(MetricHits).Value(), registry.Counter(MetricMisses).Value(); hits != 2 || misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %d and %d", hits, misses)
+	}
+	if found, err := s.GetView("missing", func([]byte) error { return nil }); err != nil || found {
+		t.Errorf("GetView of a missing key = %v, %v; want not found", found, err)
+	}
+}
@@ -0,0 +1,379 @@
+// Package checksum wraps a store so that every value is stored with a
+// checksum, verified whenever the value is read, to catch corruption of the
+// stored data that the wrapped store does not detect itself.
+//
+// A checksummed value starts with a header: three magic bytes, the algorithm
+// and the checksum of the value that follows. Values stored before checksums
+// were enabled are read as they are, unverified, unless they happen to start
+// with the magic bytes, in which case they fail verification.
+package checksum
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/cespare/xxhash/v2"
+)
+
+// Algorithm is a checksum algorithm.
+type Algorithm string
+
+const (
+	CRC32 Algorithm = "crc32" // CRC-32 with the Castagnoli polynomial, 4 bytes per value
+	XXH64 Algorithm = "xxh64" // xxHash64, 8 bytes per value
+)
+
+// MetricFailures counts values that failed verification.
+const MetricFailures = "store_checksum_failures_total"
+
+// ErrUnknownAlgorithm is returned for algorithms other than CRC32 and XXH64.
+var ErrUnknownAlgorithm = errors.New("unknown checksum algorithm")
+
+// CorruptionError is returned when reading a stored value that does not match
+// its checksum.
+type CorruptionError struct {
+	Key       string
+	Algorithm Algorithm // Empty if the header itself is damaged
+	Expected  uint64
+	Actual    uint64
+}
+
+func (e *CorruptionError) Error() string {
+	if e.Algorithm == "" {
+		return fmt.Sprintf("value of key %q is corrupt: damaged checksum header", e.Key)
+	}
+	return fmt.Sprintf("value of key %q is corrupt: %s checksum %x, want %x", e.Key, e.Algorithm, e.Actual, e.Expected)
+}
+
+// magic starts every checksummed value.
+var magic = []byte{0xff, 'C', 'S'}
+
+// Algorithm identifiers in the header.
+const (
+	headerCRC32 byte = iota + 1
+	headerXXH64
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ParseAlgorithm returns the algorithm named name.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch algorithm := Algorithm(name); algorithm {
+	case CRC32, XXH64:
+		return algorithm, nil
+	}
+	return "", fmt.Errorf("%w %q: use crc32 or xxh64", ErrUnknownAlgorithm, name)
+}
+
+// Config configures checksums.
+type Config struct {
+	Algorithm Algorithm         // Algorithm of new values; CRC32 if empty
+	Metrics   *metrics.Registry // Registry for the failure metric; metrics.Default if nil
+}
+
+// Store checksums the values written to the wrapped store and verifies the
+// values read from it.
+type Store struct {
+	store.Passthrough
+	header   byte
+	failures *metrics.Counter
+}
+
+// New wraps base.
+func New(base store.Store, config Config) (*Store, error) {
+	s := &Store{Passthrough: store.Passthrough{Store: base}}
+	switch config.Algorithm {
+	case CRC32, "":
+		s.header = headerCRC32
+	case XXH64:
+		s.header = headerXXH64
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, config.Algorithm)
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	s.failures = registry.Counter(MetricFailures)
+	return s, nil
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+)
+
+// encode returns value as it is stored.
+func (s *Store) encode(value []byte) []byte {
+	var stored []byte
+	switch s.header {
+	case headerCRC32:
+		stored = make([]byte, 0, len(magic)+1+4+len(value))
+		stored = append(append(stored, magic...), headerCRC32)
+		stored = binary.BigEndian.AppendUint32(stored, crc32.Checksum(value, castagnoli))
+	case headerXXH64:
+		stored = make([]byte, 0, len(magic)+1+8+len(value))
+		stored = append(append(stored, magic...), headerXXH64)
+		stored = binary.BigEndian.AppendUint64(stored, xxhash.Sum64(value))
+	}
+	return append(stored, value...)
+}
+
+// decode verifies the value stored at key and returns it.
+func (s *Store) decode(key string, stored []byte) ([]byte, error) {
+	if !bytes.HasPrefix(stored, magic) {
+		return stored, nil
+	}
+	rest := stored[len(magic):]
+	err := &CorruptionError{Key: key}
+	if len(rest) > 0 {
+		switch rest[0] {
+		case headerCRC32:
+			if len(rest) >= 1+4 {
+				value := rest[1+4:]
+				err.Algorithm = CRC32
+				err.Expected = uint64(binary.BigEndian.Uint32(rest[1:]))
+				err.Actual = uint64(crc32.Checksum(value, castagnoli))
+				if err.Actual == err.Expected {
+					return value, nil
+				}
+			}
+		case headerXXH64:
+			if len(rest) >= 1+8 {
+				value := rest[1+8:]
+				err.Algorithm = XXH64
+				err.Expected = binary.BigEndian.Uint64(rest[1:])
+				err.Actual = xxhash.Sum64(value)
+				if err.Actual == err.Expected {
+					return value, nil
+				}
+			}
+		}
+	}
+	s.failures.Inc()
+	return nil, err
+}
+
+func (s *Store) decodeAll(pairs map[string][]byte) (map[string][]byte, error) {
+	for key, stored := range pairs {
+		value, err := s.decode(key, stored)
+		if err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// Put stores the value with its checksum.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return store.PutContext(ctx, s.Store, key, s.encode(value))
+}
+
+// PutIfVersion conditionally stores the value with its checksum.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return store.PutIfVersionContext(ctx, s.Store, key, s.encode(value), expected)
+}
+
+// BatchPut stores the pairs with their checksums.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	encoded := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		encoded[key] = s.encode(value)
+	}
+	return store.BatchPutContext(ctx, s.Store, encoded)
+}
+
+// Get reads and verifies the value of key.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	stored, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return stored, found, err
+	}
+	value, err := s.decode(key, stored)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// GetView verifies the value of key in place and calls fn with it.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	return store.GetView(s.Store, key, func(stored []byte) error {
+		value, err := s.decode(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(value)
+	})
+}
+
+// Scan reads and verifies the pairs whose keys start with prefix.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(pairs)
+}
+
+// BatchGet reads and verifies the values of keys.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(pairs)
+}
+
+// NewIterator streams the verified pairs whose keys start with prefix.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// ForEach visits the verified pairs whose keys start with prefix.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, stored []byte) error {
+		value, err := s.decode(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the verified pairs whose keys are in r. A keys-only
+// range is streamed from the wrapped store as is, since there are no values
+// to decode.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil || r.KeysOnly {
+		return it, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// GetAt reads and verifies the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	stored, at, found, err := reader.GetAt(key, version)
+	if err != nil || !found {
+		return stored, at, found, err
+	}
+	value, err := s.decode(key, stored)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return value, at, true, nil
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the verified pairs whose keys start with prefix as
+// they were at version.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// EvaluateFilter presents the verified values to filter, with the write
+// times of the wrapped store when it tracks them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		value, err := s.decode(entry.Key, entry.Value)
+		if err != nil {
+			return false, err
+		}
+		entry.Value = value
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// iterator verifies the values of the wrapped iterator. A value that fails
+// verification stops the iteration.
+type iterator struct {
+	store.Iterator
+	store *Store
+	value []byte
+	err   error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	it.value, it.err = it.store.decode(it.Iterator.Key(), it.Iterator.Value())
+	return it.err == nil
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
@@ -0,0 +1,129 @@
+package checksum
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T, algorithm Algorithm) (*Store, *memory.MemoryStore, *metrics.Registry) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := metrics.NewRegistry()
+	s, err := New(base, Config{Algorithm: algorithm, Metrics: registry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, base, registry
+}
+
+func TestStore_Verification(t *testing.T) {
+	for _, algorithm := range []Algorithm{CRC32, XXH64} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			s, base, registry := newStore(t, algorithm)
+			if err := s.Put("a", []byte("value")); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.BatchPut(map[string][]byte{"b": {}, "c": []byte("other")}); err != nil {
+				t.Fatal(err)
+			}
+			if value, found, err := s.Get("a"); err != nil || !found || string(value) != "value" {
+				t.Errorf("Get = %q, %v, %v; want the stored value", value, found, err)
+			}
+			var viewed string
+			found, err := s.GetView("a", func(value []byte) error {
+				viewed = string(value)
+				return nil
+			})
+			if err != nil || !found || viewed != "value" {
+				t.Errorf("GetView = %v, %v viewing %q; want the stored value", found, err, viewed)
+			}
+			if pairs, err := s.Scan(""); err != nil || len(pairs) != 3 || string(pairs["c"]) != "other" {
+				t.Errorf("Scan = %q, %v; want the stored pairs", pairs, err)
+			}
+
+			// Flip a bit of the stored value
+			stored, _, _ := base.Get("a")
+			stored[len(stored)-1] ^= 1
+			if err := base.Put("a", stored); err != nil {
+				t.Fatal(err)
+			}
+
+			var corruption *CorruptionError
+			if _, _, err := s.Get("a"); !errors.As(err, &corruption) || corruption.Key != "a" || corruption.Algorithm != algorithm {
+				t.Errorf("Get error = %v, want a CorruptionError for key a", err)
+			}
+			if _, err := s.GetView("a", func([]byte) error { return nil }); !errors.As(err, &corruption) {
+				t.Errorf("GetView error = %v, want a CorruptionError", err)
+			}
+			if _, err := s.Scan(""); !errors.As(err, &corruption) {
+				t.Errorf("Scan error = %v, want a CorruptionError", err)
+			}
+			it, err := s.NewIterator("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer it.Close()
+			for it.Next() {
+			}
+			if !errors.As(it.Err(), &corruption) {
+				t.Errorf("Iterator error = %v, want a CorruptionError", it.Err())
+			}
+			if failures := registry.Counter(MetricFailures).Value(); failures != 4 {
+				t.Errorf("Expected 4 failures counted, got %d", failures)
+			}
+		})
+	}
+}
+
+func TestStore_MixedData(t *testing.T) {
+	s, base, _ := newStore(t, XXH64)
+	if err := base.Put("legacy", []byte("unchecked")); err != nil {
+		t.Fatal(err)
+	}
+	crc, err := New(base, Config{Algorithm: CRC32, Metrics: metrics.NewRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crc.Put("crc", []byte("crc32")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("xxh", append([]byte(nil), magic...)); err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := s.BatchGet([]string{"legacy", "crc", "xxh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pairs["legacy"]) != "unchecked" || string(pairs["crc"]) != "crc32" || string(pairs["xxh"]) != string(magic) {
+		t.Errorf("BatchGet = %q, want every value as written", pairs)
+	}
+
+	// A header cut short is corrupt as well
+	if err := base.Put("short", append(append([]byte(nil), magic...), headerXXH64, 1)); err != nil {
+		t.Fatal(err)
+	}
+	var corruption *CorruptionError
+	if _, _, err := s.Get("short"); !errors.As(err, &corruption) || corruption.Algorithm != "" {
+		t.Errorf("Get error = %v, want a CorruptionError for a damaged header", err)
+	}
+}
+
+func TestNew_UnknownAlgorithm(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(base, Config{Algorithm: "md5"}); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("New error = %v, want ErrUnknownAlgorithm", err)
+	}
+	if _, err := ParseAlgorithm("sha1"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("ParseAlgorithm error = %v, want ErrUnknownAlgorithm", err)
+	}
+}
@@ -0,0 +1,395 @@
+// Package compression wraps a store so that values above a size threshold are
+// stored compressed with Snappy or Zstandard, and decompressed again when
+// read.
+//
+// A compressed value starts with a four-byte header: three magic bytes and
+// the codec. Smaller values, and values that do not shrink, are stored as
+// they are, so values written before compression was enabled are read back
+// unchanged and codecs can be switched at any time. The only value stored
+// with a header without being compressed is one that starts with the magic
+// bytes itself; a value stored without the wrapper that starts with them
+// cannot be read through it.
+package compression
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is a compression algorithm.
+type Codec string
+
+const (
+	CodecSnappy Codec = "snappy" // Fast, with moderate savings
+	CodecZstd   Codec = "zstd"   // Slower, with larger savings
+)
+
+// DefaultThreshold is the Threshold of a Config that leaves it zero. Smaller
+// values rarely shrink enough to be worth the header and the CPU time.
+const DefaultThreshold = 256
+
+// ErrUnknownCodec is returned for codecs other than CodecSnappy and CodecZstd.
+var ErrUnknownCodec = errors.New("unknown compression codec")
+
+// ErrCorruptValue is returned when reading a stored value whose header names
+// an unknown codec or whose data does not decompress.
+var ErrCorruptValue = errors.New("corrupt compressed value")
+
+// magic starts every value stored with a header.
+var magic = []byte{0xff, 'C', 'Z'}
+
+// Codec identifiers in the header. None marks values stored with a header
+// only because they start with the magic bytes.
+const (
+	headerNone byte = iota
+	headerSnappy
+	headerZstd
+)
+
+// ParseCodec returns the codec named name.
+func ParseCodec(name string) (Codec, error) {
+	switch codec := Codec(name); codec {
+	case CodecSnappy, CodecZstd:
+		return codec, nil
+	}
+	return "", fmt.Errorf("%w %q: use snappy or zstd", ErrUnknownCodec, name)
+}
+
+// Config configures compression.
+type Config struct {
+	Codec     Codec // Codec of new values; CodecSnappy if empty
+	Threshold int   // Values of at least this many bytes are compressed; DefaultThreshold if zero
+}
+
+// Store compresses the values written to the wrapped store and decompresses
+// the values read from it.
+type Store struct {
+	store.Passthrough
+	config  Config
+	header  byte
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// New wraps base.
+func New(base store.Store, config Config) (*Store, error) {
+	if config.Codec == "" {
+		config.Codec = CodecSnappy
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	s := &Store{Passthrough: store.Passthrough{Store: base}, config: config}
+	switch config.Codec {
+	case CodecSnappy:
+		s.header = headerSnappy
+	case CodecZstd:
+		s.header = headerZstd
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownCodec, config.Codec)
+	}
+
+	var err error
+	if s.encoder, err = zstd.NewWriter(nil); err != nil {
+		return nil, err
+	}
+	if s.decoder, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+)
+
+// encode returns value as it is stored.
+func (s *Store) encode(value []byte) []byte {
+	if len(value) >= s.config.Threshold {
+		var compressed []byte
+		switch s.header {
+		case headerSnappy:
+			compressed = snappy.Encode(nil, value)
+		case headerZstd:
+			compressed = s.encoder.EncodeAll(value, nil)
+		}
+		if len(compressed)+len(magic)+1 < len(value) {
+			return withHeader(s.header, compressed)
+		}
+	}
+	if bytes.HasPrefix(value, magic) {
+		return withHeader(headerNone, value)
+	}
+	return value
+}
+
+func withHeader(codec byte, data []byte) []byte {
+	stored := make([]byte, 0, len(magic)+1+len(data))
+	stored = append(stored, magic...)
+	stored = append(stored, codec)
+	return append(stored, data...)
+}
+
+// decode returns the value stored as stored.
+func (s *Store) decode(stored []byte) ([]byte, error) {
+	if !bytes.HasPrefix(stored, magic) {
+		return stored, nil
+	}
+	if len(stored) == len(magic) {
+		return nil, ErrCorruptValue
+	}
+	data := stored[len(magic)+1:]
+	switch stored[len(magic)] {
+	case headerNone:
+		return data, nil
+	case headerSnappy:
+		value, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptValue, err)
+		}
+		return value, nil
+	case headerZstd:
+		value, err := s.decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptValue, err)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: unknown codec %d", ErrCorruptValue, stored[len(magic)])
+}
+
+func (s *Store) decodeAll(pairs map[string][]byte) (map[string][]byte, error) {
+	for key, stored := range pairs {
+		value, err := s.decode(stored)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// Close closes the wrapped store and releases the decoder.
+func (s *Store) Close() error {
+	s.decoder.Close()
+	return s.Store.Close()
+}
+
+// Put stores the value, compressed if it is large enough.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return store.PutContext(ctx, s.Store, key, s.encode(value))
+}
+
+// PutIfVersion conditionally stores the value, compressed if it is large
+// enough.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return store.PutIfVersionContext(ctx, s.Store, key, s.encode(value), expected)
+}
+
+// BatchPut stores the pairs, compressing the values large enough.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	encoded := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		encoded[key] = s.encode(value)
+	}
+	return store.BatchPutContext(ctx, s.Store, encoded)
+}
+
+// Get reads and decompresses the value of key.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	stored, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return stored, found, err
+	}
+	value, err := s.decode(stored)
+	if err != nil {
+		return nil, false, fmt.Errorf("key %q: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// GetView calls fn with the decompressed value of key.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	return store.GetView(s.Store, key, func(stored []byte) error {
+		value, err := s.decode(stored)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+		return fn(value)
+	})
+}
+
+// Scan reads and decompresses the pairs whose keys start with prefix.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(pairs)
+}
+
+// BatchGet reads and decompresses the values of keys.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(pairs)
+}
+
+// NewIterator streams the decompressed pairs whose keys start with prefix.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// ForEach visits the decompressed pairs whose keys start with prefix.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, stored []byte) error {
+		value, err := s.decode(stored)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the decompressed pairs whose keys are in r. A keys-only
+// range is streamed from the wrapped store as is, since there are no values
+// to decode.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil || r.KeysOnly {
+		return it, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// GetAt reads and decompresses the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	stored, at, found, err := reader.GetAt(key, version)
+	if err != nil || !found {
+		return stored, at, found, err
+	}
+	value, err := s.decode(stored)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("key %q: %w", key, err)
+	}
+	return value, at, true, nil
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the decompressed pairs whose keys start with prefix
+// as they were at version.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// EvaluateFilter presents the decompressed values to filter, with the write
+// times of the wrapped store when it tracks them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		value, err := s.decode(entry.Value)
+		if err != nil {
+			return false, err
+		}
+		entry.Value = value
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// iterator decompresses the values of the wrapped iterator. A value that
+// fails to decompress stops the iteration.
+type iterator struct {
+	store.Iterator
+	store *Store
+	value []byte
+	err   error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	it.value, it.err = it.store.decode(it.Iterator.Value())
+	if it.err != nil {
+		it.err = fmt.Errorf("key %q: %w", it.Iterator.Key(), it.err)
+		return false
+	}
+	return true
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
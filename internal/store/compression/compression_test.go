@@ -0,0 +1,175 @@
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T, config Config) (*Store, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(base, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, base
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	large := []byte(strings.Repeat("compressible ", 100))
+	values := map[string][]byte{
+		"small":   []byte("short"),
+		"large":   large,
+		"random":  []byte("\x8f\x1a\x03\x77\xc2\x90\x4e\x11"),
+		"magic":   append(append([]byte(nil), magic...), "\x01not compressed"...),
+		"empty":   {},
+		"onlyhdr": append([]byte(nil), magic...),
+	}
+
+	for _, codec := range []Codec{CodecSnappy, CodecZstd} {
+		t.Run(string(codec), func(t *testing.T) {
+			s, base := newStore(t, Config{Codec: codec, Threshold: 64})
+			for key, value := range values {
+				if err := s.Put(key, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for key, want := range values {
+				value, found, err := s.Get(key)
+				if err != nil || !found || !bytes.Equal(value, want) {
+					t.Errorf("Get(%q) = %q, %v, %v; want %q", key, value, found, err, want)
+				}
+			}
+			pairs, err := s.Scan("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range values {
+				if !bytes.Equal(pairs[key], want) {
+					t.Errorf("Scan()[%q] = %q, want %q", key, pairs[key], want)
+				}
+			}
+
+			stored, _, _ := base.Get("large")
+			if len(stored) >= len(large) || !bytes.HasPrefix(stored, magic) {
+				t.Errorf("Expected the large value to be stored compressed, got %d bytes", len(stored))
+			}
+			if stored, _, _ := base.Get("small"); string(stored) != "short" {
+				t.Errorf("Expected the small value to be stored as is, got %q", stored)
+			}
+		})
+	}
+}
+
+func TestStore_MixedData(t *testing.T) {
+	s, base := newStore(t, Config{Codec: CodecZstd, Threshold: 16})
+	large := []byte(strings.Repeat("x", 1000))
+
+	// Values written before compression was enabled, or with another codec
+	if err := base.Put("legacy", large); err != nil {
+		t.Fatal(err)
+	}
+	snappyStore, err := New(base, Config{Codec: CodecSnappy, Threshold: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snappyStore.Put("snappy", large); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"zstd": large}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.BatchGet([]string{"legacy", "snappy", "zstd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range got {
+		if !bytes.Equal(value, large) {
+			t.Errorf("BatchGet()[%q] = %d bytes, want the original value", key, len(value))
+		}
+	}
+
+	it, err := s.NewIterator("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	count := 0
+	for it.Next() {
+		count++
+		if !bytes.Equal(it.Value(), large) {
+			t.Errorf("Iterator value of %q = %d bytes, want the original value", it.Key(), len(it.Value()))
+		}
+	}
+	if err := it.Err(); err != nil || count != 3 {
+		t.Errorf("Iterated %d pairs with error %v, want 3 pairs", count, err)
+	}
+}
+
+func TestStore_CorruptValue(t *testing.T) {
+	s, base := newStore(t, Config{})
+	corrupt := append(append([]byte(nil), magic...), headerZstd, 'x', 'y')
+	if err := base.Put("corrupt", corrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := s.Get("corrupt"); !errors.Is(err, ErrCorruptValue) {
+		t.Errorf("Get error = %v, want ErrCorruptValue", err)
+	}
+	if _, err := s.Scan(""); !errors.Is(err, ErrCorruptValue) {
+		t.Errorf("Scan error = %v, want ErrCorruptValue", err)
+	}
+	it, err := s.NewIterator("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	if it.Next() || !errors.Is(it.Err(), ErrCorruptValue) {
+		t.Errorf("Iterator error = %v, want ErrCorruptValue", it.Err())
+	}
+}
+
+func TestStore_Filter(t *testing.T) {
+	s, _ := newStore(t, Config{Threshold: 16})
+	if err := s.Put("drop", []byte(strings.Repeat("drop me ", 10))); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("keep", []byte(strings.Repeat("keep me ", 10))); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		return bytes.HasPrefix(entry.Value, []byte("drop me")), nil
+	})
+	deleted, err := store.ApplyFilter(s, filter, time.Now())
+	if err != nil || deleted != 1 {
+		t.Fatalf("ApplyFilter = %d, %v; want 1 key deleted", deleted, err)
+	}
+	if _, found, _ := s.Get("drop"); found {
+		t.Error("Expected the filter to see the decompressed value")
+	}
+}
+
+func TestNew_UnknownCodec(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(base, Config{Codec: "lz4"}); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("New error = %v, want ErrUnknownCodec", err)
+	}
+	if _, err := ParseCodec("gzip"); !errors.Is(err, ErrUnknownCodec) {
+		t.Errorf("ParseCodec error = %v, want ErrUnknownCodec", err)
+	}
+}
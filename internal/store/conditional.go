@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrVersionConflict is returned by conditional writes when the key is no
+	// longer at the expected version.
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrConditionalPutUnsupported is returned for conditional writes to stores
+	// that do not implement ConditionalPutter.
+	ErrConditionalPutUnsupported = errors.New("conditional writes are not supported by this store")
+	// ErrPreconditionFailed is returned by writes whose precondition on the
+	// state of the store does not hold.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrKeyExists is returned by PutIfAbsent when the key already exists.
+	ErrKeyExists = fmt.Errorf("%w: key already exists", ErrPreconditionFailed)
+)
+
+// contextConditionalPutter is implemented by ContextWriter stores that also
+// write conditionally.
+type contextConditionalPutter interface {
+	PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error
+}
+
+// PutIfVersion stores the value in s only if key is at the expected version.
+// Like BatchPut, only s itself is checked, so wrappers must pass conditional
+// writes on explicitly for them to be supported.
+func PutIfVersion(s Store, key string, value []byte, expected uint64) error {
+	if putter, ok := s.(ConditionalPutter); ok {
+		return putter.PutIfVersion(key, value, expected)
+	}
+	return ErrConditionalPutUnsupported
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx, for
+// stores whose writes depend on it.
+func PutIfVersionContext(ctx context.Context, s Store, key string, value []byte, expected uint64) error {
+	if putter, ok := s.(contextConditionalPutter); ok {
+		return putter.PutIfVersionContext(ctx, key, value, expected)
+	}
+	return PutIfVersion(s, key, value, expected)
+}
+
+// PutIfAbsent stores the value in s only if key does not exist, failing with
+// ErrKeyExists otherwise. It is a conditional write at version 0, so it is
+// supported by the same stores as PutIfVersion.
+func PutIfAbsent(s Store, key string, value []byte) error {
+	return absent(PutIfVersion(s, key, value, 0))
+}
+
+// PutIfAbsentContext is PutIfAbsent on behalf of the request in ctx.
+func PutIfAbsentContext(ctx context.Context, s Store, key string, value []byte) error {
+	return absent(PutIfVersionContext(ctx, s, key, value, 0))
+}
+
+// absent reports the conflict of a conditional write at version 0 as the key
+// existing.
+func absent(err error) error {
+	if errors.Is(err, ErrVersionConflict) {
+		return ErrKeyExists
+	}
+	return err
+}
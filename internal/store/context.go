@@ -0,0 +1,50 @@
+package store
+
+import "context"
+
+// PutContext stores the value in s on behalf of the request in ctx when s
+// implements ContextWriter, and with a plain Put otherwise. As with BatchPut,
+// only s itself is checked, so wrappers must pass the context on explicitly
+// for the stores they wrap to see it.
+func PutContext(ctx context.Context, s Store, key string, value []byte) error {
+	if writer, ok := s.(ContextWriter); ok {
+		return writer.PutContext(ctx, key, value)
+	}
+	return s.Put(key, value)
+}
+
+// BatchPutContext stores all pairs in s on behalf of the request in ctx when s
+// implements ContextWriter, and with BatchPut otherwise.
+func BatchPutContext(ctx context.Context, s Store, pairs map[string][]byte) error {
+	if writer, ok := s.(ContextWriter); ok {
+		return writer.BatchPutContext(ctx, pairs)
+	}
+	return BatchPut(s, pairs)
+}
+
+// DeleteContext removes the key from s on behalf of the request in ctx when s
+// implements ContextWriter, and with a plain Delete otherwise.
+func DeleteContext(ctx context.Context, s Store, key string) error {
+	if writer, ok := s.(ContextWriter); ok {
+		return writer.DeleteContext(ctx, key)
+	}
+	return s.Delete(key)
+}
+
+// GetContext reads the key from s on behalf of the request in ctx when s
+// implements ContextReader, and with a plain Get otherwise.
+func GetContext(ctx context.Context, s Store, key string) ([]byte, bool, error) {
+	if reader, ok := s.(ContextReader); ok {
+		return reader.GetContext(ctx, key)
+	}
+	return s.Get(key)
+}
+
+// ScanContext scans s on behalf of the request in ctx when s implements
+// ContextReader, and with a plain Scan otherwise.
+func ScanContext(ctx context.Context, s Store, prefix string) (map[string][]byte, error) {
+	if reader, ok := s.(ContextReader); ok {
+		return reader.ScanContext(ctx, prefix)
+	}
+	return s.Scan(prefix)
+}
@@ -0,0 +1,39 @@
+package store
+
+import "context"
+
+// Exists reports whether key is in s, without reading its value.
+func Exists(s Store, key string) (bool, error) {
+	keys, err := Keys(s, KeyRange{Start: key, End: key + "\x00"}, 1)
+	if err != nil {
+		return false, err
+	}
+	return len(keys) == 1, nil
+}
+
+// Count returns the number of keys in s starting with prefix, without reading
+// their values. It stops with the error of ctx once ctx is done.
+func Count(ctx context.Context, s Store, prefix string) (n int64, err error) {
+	r := PrefixRange(prefix)
+	r.KeysOnly = true
+	it, err := NewRangeIterator(s, r)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
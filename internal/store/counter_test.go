@@ -0,0 +1,45 @@
+package store_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestCounterEncoding(t *testing.T) {
+	for _, encoding := range []store.CounterEncoding{store.CounterASCII, store.CounterLittleEndian} {
+		for _, n := range []int64{0, 1, -42, math.MaxInt64, math.MinInt64} {
+			if got, err := encoding.Decode(encoding.Encode(n)); err != nil || got != n {
+				t.Errorf("Round trip of %d in encoding %d = %d, %v", n, encoding, got, err)
+			}
+		}
+	}
+	if got := string(store.CounterASCII.Encode(-7)); got != "-7" {
+		t.Errorf("ASCII encoding of -7 = %q", got)
+	}
+	if _, err := store.CounterASCII.Decode([]byte("seven")); !errors.Is(err, store.ErrNotCounter) {
+		t.Errorf("Decode of a word = %v, want ErrNotCounter", err)
+	}
+	if _, err := store.CounterLittleEndian.Decode([]byte{1, 2, 3}); !errors.Is(err, store.ErrNotCounter) {
+		t.Errorf("Decode of 3 bytes = %v, want ErrNotCounter", err)
+	}
+}
+
+func TestAddCounter(t *testing.T) {
+	if next, encoded, err := store.AddCounter(store.CounterASCII, nil, 5); err != nil || next != 5 || string(encoded) != "5" {
+		t.Errorf("AddCounter to a missing key = %d, %q, %v; want 5", next, encoded, err)
+	}
+	if next, _, err := store.AddCounter(store.CounterASCII, []byte("10"), -15); err != nil || next != -5 {
+		t.Errorf("AddCounter(10, -15) = %d, %v; want -5", next, err)
+	}
+	max := store.CounterASCII.Encode(math.MaxInt64)
+	if _, _, err := store.AddCounter(store.CounterASCII, max, 1); !errors.Is(err, store.ErrCounterOverflow) {
+		t.Errorf("AddCounter past MaxInt64 = %v, want ErrCounterOverflow", err)
+	}
+	min := store.CounterASCII.Encode(math.MinInt64)
+	if _, _, err := store.AddCounter(store.CounterASCII, min, -1); !errors.Is(err, store.ErrCounterOverflow) {
+		t.Errorf("AddCounter past MinInt64 = %v, want ErrCounterOverflow", err)
+	}
+}
@@ -0,0 +1,574 @@
+// Package crypto wraps a store so that values are encrypted at rest with
+// AES-256-GCM.
+//
+// Values are encrypted with data-encryption keys, which are stored in the
+// wrapped store under KeyPrefix, themselves encrypted ("wrapped") with a
+// master key that is never stored. Each encrypted value starts with the id of
+// its key, so keys can be rotated without re-encrypting everything at once:
+// Rotate makes a new key the one values are written with, and Rewrap
+// re-encrypts the values written with older ones. The master key is rotated
+// by configuring a new one along with the previous ones, which the data
+// encryption keys are rewrapped from when the store is opened.
+//
+// Each value is authenticated together with its key, so a stored value cannot
+// be moved to another key unnoticed. Values stored before encryption was
+// enabled are read as they are until Rewrap encrypts them, except for those
+// that happen to start like an encrypted value, which fail to decrypt.
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+var (
+	// ErrReservedKey is returned for writes to the keys under KeyPrefix,
+	// which are hidden from reads.
+	ErrReservedKey = errors.New("key is reserved for data-encryption keys")
+	// ErrDecryptFailed is returned when reading a stored value whose key is
+	// unknown or that fails authentication.
+	ErrDecryptFailed = errors.New("failed to decrypt value")
+)
+
+// magic starts every encrypted value, followed by the id of its key and the
+// nonce and ciphertext.
+var magic = []byte{0xff, 'E', 'K'}
+
+const headerSize = 3 + 4
+
+// rewrapBatchSize is the number of values Rewrap re-encrypts at once, while
+// holding off writes.
+const rewrapBatchSize = 1000
+
+// Config configures encryption.
+type Config struct {
+	// MasterKey wraps the data-encryption keys; parsed from $CLAVIS_MASTER_KEY
+	// with ParseMasterKey if nil.
+	MasterKey []byte
+	// PreviousMasterKeys unwrap data-encryption keys wrapped before the
+	// master key was rotated; parsed from $CLAVIS_PREVIOUS_MASTER_KEYS if nil.
+	PreviousMasterKeys [][]byte
+	// Logger reports key rotations and rewrapped data-encryption keys;
+	// slog.Default() if nil.
+	Logger *slog.Logger
+}
+
+// Store encrypts the values written to the wrapped store and decrypts the
+// values read from it.
+type Store struct {
+	store.Passthrough
+	masters []cipher.AEAD // Current master key first
+	logger  *slog.Logger
+
+	keysMu sync.RWMutex
+	keys   map[uint32]cipher.AEAD
+	active uint32 // Zero until the first key is created
+
+	// writeMu is held for reading by writes and for writing by Rewrap, so a
+	// value is not re-encrypted over a newer one or after it was deleted.
+	writeMu sync.RWMutex
+}
+
+// New wraps base, reading the data-encryption keys stored in it.
+func New(base store.Store, config Config) (*Store, error) {
+	masters, err := masterKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	s := &Store{Passthrough: store.Passthrough{Store: base}, masters: masters, logger: config.Logger}
+	if err := s.loadKeys(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.Expirer               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+	_ store.Backuper              = (*Store)(nil)
+)
+
+func reserved(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+func checkKeys(keys ...string) error {
+	for _, key := range keys {
+		if reserved(key) {
+			return fmt.Errorf("%w: %q", ErrReservedKey, key)
+		}
+	}
+	return nil
+}
+
+// encrypt returns value as it is stored at key.
+func (s *Store) encrypt(key string, value []byte) ([]byte, error) {
+	id, aead, err := s.activeKey()
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = binary.BigEndian.AppendUint32(header, id)
+	return append(header, seal(aead, value, []byte(key))...), nil
+}
+
+// keyID returns the id of the key the value stored was encrypted with, or
+// zero if it is not encrypted.
+func keyID(stored []byte) uint32 {
+	if len(stored) < headerSize || !bytes.HasPrefix(stored, magic) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(stored[len(magic):headerSize])
+}
+
+// decrypt returns the value stored at key.
+func (s *Store) decrypt(key string, stored []byte) ([]byte, error) {
+	id := keyID(stored)
+	if id == 0 {
+		return stored, nil
+	}
+	aead, ok := s.key(id)
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w: unknown data-encryption key %d", key, ErrDecryptFailed, id)
+	}
+	value, err := open(aead, stored[headerSize:], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w: %v", key, ErrDecryptFailed, err)
+	}
+	return value, nil
+}
+
+// decryptAll decrypts pairs in place, dropping the reserved keys.
+func (s *Store) decryptAll(pairs map[string][]byte) (map[string][]byte, error) {
+	for key, stored := range pairs {
+		if reserved(key) {
+			delete(pairs, key)
+			continue
+		}
+		value, err := s.decrypt(key, stored)
+		if err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// Put encrypts and stores the value.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	stored, err := s.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.PutContext(ctx, s.Store, key, stored)
+}
+
+// PutIfVersion conditionally encrypts and stores the value.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	stored, err := s.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.PutIfVersionContext(ctx, s.Store, key, stored, expected)
+}
+
+// BatchPut encrypts and stores the pairs.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	encrypted := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		if err := checkKeys(key); err != nil {
+			return err
+		}
+		stored, err := s.encrypt(key, value)
+		if err != nil {
+			return err
+		}
+		encrypted[key] = stored
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.BatchPutContext(ctx, s.Store, encrypted)
+}
+
+// Delete removes the key, unless it is reserved.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.DeleteContext(ctx, s.Store, key)
+}
+
+// BatchDelete removes the keys, unless any of them is reserved.
+func (s *Store) BatchDelete(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.BatchDelete(s.Store, keys)
+}
+
+// ExpireKeys removes the keys as expired, unless any of them is reserved.
+func (s *Store) ExpireKeys(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+	return store.ExpireKeys(s.Store, keys)
+}
+
+// Get reads and decrypts the value of key.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	if reserved(key) {
+		return nil, false, nil
+	}
+	stored, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return stored, found, err
+	}
+	value, err := s.decrypt(key, stored)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// GetView calls fn with the decrypted value of key.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	if reserved(key) {
+		return false, nil
+	}
+	return store.GetView(s.Store, key, func(stored []byte) error {
+		value, err := s.decrypt(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(value)
+	})
+}
+
+// Scan reads and decrypts the pairs whose keys start with prefix.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(pairs)
+}
+
+// BatchGet reads and decrypts the values of keys.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(pairs)
+}
+
+// NewIterator streams the decrypted pairs whose keys start with prefix.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// ForEach visits the decrypted pairs whose keys start with prefix.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, stored []byte) error {
+		if reserved(key) {
+			return nil
+		}
+		value, err := s.decrypt(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the decrypted pairs whose keys are in r. The
+// values of a keys-only range are not decrypted.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s, keysOnly: r.KeysOnly}, nil
+}
+
+// GetAt reads and decrypts the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	if reserved(key) {
+		return nil, 0, false, nil
+	}
+	stored, at, found, err := reader.GetAt(key, version)
+	if err != nil || !found {
+		return stored, at, found, err
+	}
+	value, err := s.decrypt(key, stored)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return value, at, true, nil
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the decrypted pairs whose keys start with prefix as
+// they were at version.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s}, nil
+}
+
+// EvaluateFilter presents the decrypted values to filter, with the write
+// times of the wrapped store when it tracks them. The reserved keys are never
+// presented, so no filter drops them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		if reserved(entry.Key) {
+			return false, nil
+		}
+		value, err := s.decrypt(entry.Key, entry.Value)
+		if err != nil {
+			return false, err
+		}
+		entry.Value = value
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// Backup dumps the wrapped store, with values and data-encryption keys
+// encrypted as they are stored.
+func (s *Store) Backup(w io.Writer, since uint64) (uint64, error) {
+	return store.Backup(s.Store, w, since)
+}
+
+// Restore loads a dump into the wrapped store and then reads the
+// data-encryption keys it restored, which must be wrapped by one of the
+// configured master keys.
+func (s *Store) Restore(r io.Reader, since uint64) error {
+	if err := store.Restore(s.Store, r, since); err != nil {
+		return err
+	}
+	return s.loadKeys()
+}
+
+// RewrapStats reports on a Rewrap.
+type RewrapStats struct {
+	KeyID     uint32 // Key the values are now encrypted with
+	Scanned   int    // Values read
+	Rewrapped int    // Values re-encrypted, including those stored unencrypted before
+}
+
+// Rewrap re-encrypts every value not encrypted with the active
+// data-encryption key, including values stored before encryption was
+// enabled, so older keys are no longer needed to read them. Writes are held
+// off while each batch is re-encrypted.
+func (s *Store) Rewrap(ctx context.Context) (stats RewrapStats, err error) {
+	id, _, err := s.activeKey()
+	if err != nil {
+		return stats, err
+	}
+	stats.KeyID = id
+
+	it, err := store.NewIterator(s.Store, "")
+	if err != nil {
+		return stats, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	var batch []string
+	flush := func() error {
+		rewrapped, err := s.rewrapBatch(ctx, id, batch)
+		stats.Rewrapped += rewrapped
+		batch = batch[:0]
+		return err
+	}
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		if reserved(it.Key()) {
+			continue
+		}
+		stats.Scanned++
+		if keyID(it.Value()) == id {
+			continue
+		}
+		batch = append(batch, it.Key())
+		if len(batch) == rewrapBatchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return stats, err
+	}
+	if err := flush(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// rewrapBatch re-encrypts the values of keys that are still not encrypted
+// with key id, reading them again as they may have changed meanwhile.
+func (s *Store) rewrapBatch(ctx context.Context, id uint32, keys []string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return 0, err
+	}
+	rewrapped := make(map[string][]byte, len(pairs))
+	for key, stored := range pairs {
+		if keyID(stored) == id {
+			continue
+		}
+		value, err := s.decrypt(key, stored)
+		if err != nil {
+			return 0, err
+		}
+		if rewrapped[key], err = s.encrypt(key, value); err != nil {
+			return 0, err
+		}
+	}
+	if len(rewrapped) == 0 {
+		return 0, nil
+	}
+	return len(rewrapped), store.BatchPutContext(ctx, s.Store, rewrapped)
+}
+
+// iterator decrypts the values of the wrapped iterator and skips the
+// reserved keys. A value that fails to decrypt stops the iteration.
+type iterator struct {
+	store.Iterator
+	store    *Store
+	value    []byte
+	err      error
+	keysOnly bool
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.Iterator.Next() {
+		if reserved(it.Iterator.Key()) {
+			continue
+		}
+		if it.keysOnly {
+			return true
+		}
+		it.value, it.err = it.store.decrypt(it.Iterator.Key(), it.Iterator.Value())
+		return it.err == nil
+	}
+	return false
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
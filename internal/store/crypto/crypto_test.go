@@ -0,0 +1,242 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func masterKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func newStore(t *testing.T, base store.Store, config Config) *Store {
+	t.Helper()
+	s, err := New(base, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newBase(t *testing.T) *memory.MemoryStore {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	base := newBase(t)
+	s := newStore(t, base, Config{MasterKey: masterKey(1)})
+	if n, _ := base.Scan(""); len(n) != 0 {
+		t.Fatalf("Expected an unused store to stay empty, got %d keys", len(n))
+	}
+
+	if err := s.Put("a", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"b": []byte("other secret")}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _, _ := base.Get("a")
+	if bytes.Contains(stored, []byte("secret")) || keyID(stored) != 1 {
+		t.Errorf("Expected the value to be stored encrypted with key 1, got %q", stored)
+	}
+	if value, found, err := s.Get("a"); err != nil || !found || string(value) != "secret" {
+		t.Errorf("Get = %q, %v, %v; want the stored value", value, found, err)
+	}
+	pairs, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || string(pairs["b"]) != "other secret" {
+		t.Errorf("Scan = %q, want the two stored pairs without the keyring", pairs)
+	}
+	visited := map[string]string{}
+	if err := store.ForEach(s, "", func(key string, value []byte) error {
+		visited[key] = string(value)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(visited) != 2 || visited["a"] != "secret" {
+		t.Errorf("ForEach visited %q, want the two stored pairs without the keyring", visited)
+	}
+
+	// The same key opens the store again; another one does not
+	if value, _, _ := newStore(t, base, Config{MasterKey: masterKey(1)}).Get("b"); string(value) != "other secret" {
+		t.Errorf("Expected a reopened store to decrypt, got %q", value)
+	}
+	if _, err := New(base, Config{MasterKey: masterKey(2)}); !errors.Is(err, ErrUnwrapFailed) {
+		t.Errorf("New with another master key error = %v, want ErrUnwrapFailed", err)
+	}
+}
+
+func TestStore_Authentication(t *testing.T) {
+	base := newBase(t)
+	s := newStore(t, base, Config{MasterKey: masterKey(1)})
+	if err := s.Put("a", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A value moved to another key does not decrypt
+	stored, _, _ := base.Get("a")
+	if err := base.Put("b", stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Get("b"); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("Get of a moved value error = %v, want ErrDecryptFailed", err)
+	}
+	it, err := s.NewIterator("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	for it.Next() {
+	}
+	if !errors.Is(it.Err(), ErrDecryptFailed) {
+		t.Errorf("Iterator error = %v, want ErrDecryptFailed", it.Err())
+	}
+}
+
+func TestStore_ReservedKeys(t *testing.T) {
+	s := newStore(t, newBase(t), Config{MasterKey: masterKey(1)})
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	writes := map[string]func() error{
+		"Put":         func() error { return s.Put(keyName(1), []byte("x")) },
+		"Delete":      func() error { return s.Delete(keyName(1)) },
+		"BatchDelete": func() error { return store.BatchDelete(s, []string{"a", keyName(1)}) },
+		"ExpireKeys":  func() error { return store.ExpireKeys(s, []string{keyName(1)}) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrReservedKey) {
+			t.Errorf("%s error = %v, want ErrReservedKey", name, err)
+		}
+	}
+	if _, found, _ := s.Get(keyName(1)); found {
+		t.Error("Expected the keyring to be hidden from Get")
+	}
+	if value, _, _ := s.Get("a"); string(value) != "1" {
+		t.Errorf("Expected the keyring to survive, got %q", value)
+	}
+}
+
+func TestStore_RotateAndRewrap(t *testing.T) {
+	base := newBase(t)
+	if err := base.Put("legacy", []byte("plain")); err != nil {
+		t.Fatal(err)
+	}
+	s := newStore(t, base, Config{MasterKey: masterKey(1)})
+	if value, _, _ := s.Get("legacy"); string(value) != "plain" {
+		t.Errorf("Expected values stored before encryption to be readable, got %q", value)
+	}
+	if err := s.Put("old", []byte("first key")); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := s.Rotate()
+	if err != nil || id != 2 {
+		t.Fatalf("Rotate = %d, %v; want key 2", id, err)
+	}
+	if err := s.Put("new", []byte("second key")); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := s.Get("old"); string(value) != "first key" {
+		t.Errorf("Expected values of the previous key to stay readable, got %q", value)
+	}
+
+	stats, err := s.Rewrap(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (RewrapStats{KeyID: 2, Scanned: 3, Rewrapped: 2}) {
+		t.Errorf("Rewrap = %+v, want 2 of 3 values rewrapped with key 2", stats)
+	}
+	for key, want := range map[string]string{"legacy": "plain", "old": "first key", "new": "second key"} {
+		if stored, _, _ := base.Get(key); keyID(stored) != 2 {
+			t.Errorf("Expected %q to be encrypted with key 2, got key %d", key, keyID(stored))
+		}
+		if value, _, _ := s.Get(key); string(value) != want {
+			t.Errorf("Get(%q) = %q, want %q", key, value, want)
+		}
+	}
+}
+
+func TestStore_MasterKeyRotation(t *testing.T) {
+	base := newBase(t)
+	s := newStore(t, base, Config{MasterKey: masterKey(1)})
+	if err := s.Put("a", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	s = newStore(t, base, Config{MasterKey: masterKey(2), PreviousMasterKeys: [][]byte{masterKey(1)}})
+	if value, _, _ := s.Get("a"); string(value) != "secret" {
+		t.Errorf("Expected the value to decrypt after rotating the master key, got %q", value)
+	}
+	// The keyring was rewrapped, so the previous master key is no longer needed
+	if value, _, _ := newStore(t, base, Config{MasterKey: masterKey(2)}).Get("a"); string(value) != "secret" {
+		t.Errorf("Expected the keyring to be rewrapped, got %q", value)
+	}
+}
+
+func TestStore_ReloadKeys(t *testing.T) {
+	base := newBase(t)
+	s := newStore(t, base, Config{MasterKey: masterKey(1)})
+	if err := s.Put("a", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	stored, _, _ := base.Get("a")
+	wrapped, _, _ := base.Get(keyName(1))
+
+	// Keys written beneath the wrapper, as by Restore, are only used once reloaded
+	target := newStore(t, newBase(t), Config{MasterKey: masterKey(1)})
+	if err := target.Store.Put(keyName(1), wrapped); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Store.Put("a", stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.loadKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, err := target.Get("a"); err != nil || string(value) != "secret" {
+		t.Errorf("Get after loading the keyring = %q, %v; want the value", value, err)
+	}
+}
+
+func TestParseMasterKey(t *testing.T) {
+	key := masterKey(7)
+	for _, text := range []string{hex.EncodeToString(key), " " + strings.ToUpper(hex.EncodeToString(key)) + "\n", "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc="} {
+		got, err := ParseMasterKey(text)
+		if err != nil || !bytes.Equal(got, key) {
+			t.Errorf("ParseMasterKey(%q) = %x, %v; want %x", text, got, err, key)
+		}
+	}
+	for _, text := range []string{"", "abcd", "not a key at all"} {
+		if _, err := ParseMasterKey(text); !errors.Is(err, ErrInvalidMasterKey) {
+			t.Errorf("ParseMasterKey(%q) error = %v, want ErrInvalidMasterKey", text, err)
+		}
+	}
+
+	t.Setenv(MasterKeyEnv, "")
+	if _, err := New(newBase(t), Config{}); !errors.Is(err, ErrInvalidMasterKey) {
+		t.Errorf("New without a master key error = %v, want ErrInvalidMasterKey", err)
+	}
+	t.Setenv(MasterKeyEnv, hex.EncodeToString(key))
+	if _, err := New(newBase(t), Config{}); err != nil {
+		t.Errorf("New with $%s error = %v", MasterKeyEnv, err)
+	}
+}
@@ -0,0 +1,236 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// KeyPrefix is the internal key prefix under which the data-encryption
+	// keys are stored, wrapped by the master key.
+	KeyPrefix = "__clavis/crypto/keys/"
+
+	// MasterKeyEnv names the environment variable the master key is read
+	// from when the Config leaves it unset.
+	MasterKeyEnv = "CLAVIS_MASTER_KEY"
+	// PreviousMasterKeysEnv names the environment variable holding the
+	// comma-separated master keys used before, when the Config leaves them
+	// unset.
+	PreviousMasterKeysEnv = "CLAVIS_PREVIOUS_MASTER_KEYS"
+
+	// KeySize is the size of master and data-encryption keys, for AES-256.
+	KeySize = 32
+)
+
+var (
+	// ErrInvalidMasterKey is returned for master keys that are missing or
+	// not KeySize bytes long.
+	ErrInvalidMasterKey = errors.New("invalid master key")
+	// ErrUnwrapFailed is returned when a stored data-encryption key was
+	// wrapped by none of the configured master keys.
+	ErrUnwrapFailed = errors.New("data-encryption key not wrapped by any configured master key")
+)
+
+// ParseMasterKey decodes a master key given as 64 hexadecimal digits or as
+// standard base64.
+func ParseMasterKey(text string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidMasterKey)
+	}
+	key, err := hex.DecodeString(text)
+	if err != nil {
+		if key, err = base64.StdEncoding.DecodeString(text); err != nil {
+			return nil, fmt.Errorf("%w: neither hexadecimal nor base64", ErrInvalidMasterKey)
+		}
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidMasterKey, len(key), KeySize)
+	}
+	return key, nil
+}
+
+// masterKeys returns the AEADs of the current master key, first, and of the
+// previous ones, from config or the environment.
+func masterKeys(config Config) ([]cipher.AEAD, error) {
+	current := config.MasterKey
+	if current == nil {
+		key, err := ParseMasterKey(os.Getenv(MasterKeyEnv))
+		if err != nil {
+			return nil, fmt.Errorf("$%s: %w", MasterKeyEnv, err)
+		}
+		current = key
+	}
+	previous := config.PreviousMasterKeys
+	if previous == nil && os.Getenv(PreviousMasterKeysEnv) != "" {
+		for _, text := range strings.Split(os.Getenv(PreviousMasterKeysEnv), ",") {
+			key, err := ParseMasterKey(text)
+			if err != nil {
+				return nil, fmt.Errorf("$%s: %w", PreviousMasterKeysEnv, err)
+			}
+			previous = append(previous, key)
+		}
+	}
+
+	var aeads []cipher.AEAD
+	for _, key := range append([][]byte{current}, previous...) {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidMasterKey, len(key), KeySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		aeads = append(aeads, aead)
+	}
+	return aeads, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce, which it prepends.
+func seal(aead cipher.AEAD, plaintext, additional []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err) // crypto/rand does not fail on supported platforms
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional)
+}
+
+// open decrypts what seal returned.
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additional)
+}
+
+// keyName returns the internal key the data-encryption key id is stored at.
+func keyName(id uint32) string {
+	return KeyPrefix + fmt.Sprintf("%08x", id)
+}
+
+// parseKeyName returns the id of the data-encryption key stored at name.
+func parseKeyName(name string) (uint32, bool) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(name, KeyPrefix), 16, 32)
+	return uint32(id), err == nil && id != 0
+}
+
+// loadKeys reads the data-encryption keys from the wrapped store, rewrapping
+// those wrapped by a previous master key with the current one.
+func (s *Store) loadKeys() error {
+	wrapped, err := s.Store.Scan(KeyPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to read data-encryption keys: %w", err)
+	}
+
+	keys := make(map[uint32]cipher.AEAD, len(wrapped))
+	var active uint32
+	for name, value := range wrapped {
+		id, ok := parseKeyName(name)
+		if !ok {
+			continue
+		}
+		var key []byte
+		for i, master := range s.masters {
+			if key, err = open(master, value, []byte(name)); err != nil {
+				continue
+			}
+			if i > 0 {
+				if err := s.Store.Put(name, seal(s.masters[0], key, []byte(name))); err != nil {
+					return fmt.Errorf("failed to rewrap data-encryption key %d: %w", id, err)
+				}
+				s.logger.Info("Rewrapped data-encryption key with the current master key", "key_id", id)
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("key %d: %w", id, ErrUnwrapFailed)
+		}
+		if keys[id], err = newAEAD(key); err != nil {
+			return err
+		}
+		active = max(active, id)
+	}
+
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+	s.keys, s.active = keys, active
+	return nil
+}
+
+// activeKey returns the data-encryption key new values are encrypted with,
+// creating the first one on the first write so an unused store stays empty.
+func (s *Store) activeKey() (uint32, cipher.AEAD, error) {
+	s.keysMu.RLock()
+	id, aead := s.active, s.keys[s.active]
+	s.keysMu.RUnlock()
+	if aead != nil {
+		return id, aead, nil
+	}
+
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+	if s.active == 0 {
+		if err := s.addKey(); err != nil {
+			return 0, nil, err
+		}
+	}
+	return s.active, s.keys[s.active], nil
+}
+
+// key returns the data-encryption key id.
+func (s *Store) key(id uint32) (cipher.AEAD, bool) {
+	s.keysMu.RLock()
+	defer s.keysMu.RUnlock()
+	aead, ok := s.keys[id]
+	return aead, ok
+}
+
+// addKey generates and stores a data-encryption key and makes it the active
+// one. s.keysMu must be held for writing.
+func (s *Store) addKey() error {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	id := s.active + 1
+	name := keyName(id)
+	if err := s.Store.Put(name, seal(s.masters[0], key, []byte(name))); err != nil {
+		return fmt.Errorf("failed to store data-encryption key %d: %w", id, err)
+	}
+	s.keys[id], s.active = aead, id
+	return nil
+}
+
+// Rotate generates a data-encryption key that new values are encrypted with
+// from now on and returns its id. Values encrypted with the previous keys
+// remain readable; Rewrap re-encrypts them.
+func (s *Store) Rotate() (uint32, error) {
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+	if err := s.addKey(); err != nil {
+		return 0, err
+	}
+	s.logger.Info("Rotated data-encryption key", "key_id", s.active)
+	return s.active, nil
+}
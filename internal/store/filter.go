@@ -0,0 +1,225 @@
+package store
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+)
+
+const (
+	// MetricFilterDroppedKeys counts keys removed by compaction filters.
+	MetricFilterDroppedKeys = "store_filter_dropped_keys_total"
+	// MetricFilterExpiredKeys counts keys removed as expired by compaction filters.
+	MetricFilterExpiredKeys = "store_filter_expired_keys_total"
+
+	// DefaultFilterInterval is the time between compaction filter passes.
+	DefaultFilterInterval = 10 * time.Minute
+
+	// filterBatchSize bounds the number of keys deleted per batch.
+	filterBatchSize = 1000
+)
+
+// FilterEntry is a key-value pair presented to a compaction filter.
+type FilterEntry struct {
+	Key     string
+	Value   []byte
+	ModTime time.Time // When the pair was last written; zero if the store does not track it
+}
+
+// FilterEvaluator is implemented by stores that evaluate compaction filters
+// natively, typically to supply write times.
+type FilterEvaluator interface {
+	// EvaluateFilter returns the keys of the pairs that filter drops, without deleting them.
+	EvaluateFilter(filter CompactionFilter, now time.Time) ([]string, error)
+}
+
+// CompactionFilter decides which pairs background maintenance removes.
+type CompactionFilter interface {
+	// Drop reports whether the entry should be deleted.
+	Drop(entry FilterEntry, now time.Time) (bool, error)
+}
+
+// CompactionFilterFunc adapts a function to CompactionFilter.
+type CompactionFilterFunc func(entry FilterEntry, now time.Time) (bool, error)
+
+// Drop calls f.
+func (f CompactionFilterFunc) Drop(entry FilterEntry, now time.Time) (bool, error) {
+	return f(entry, now)
+}
+
+// ExpireAfter drops pairs under prefix that were last written more than maxAge
+// ago. Pairs whose write time the store does not track are kept.
+func ExpireAfter(prefix string, maxAge time.Duration) CompactionFilter {
+	return CompactionFilterFunc(func(entry FilterEntry, now time.Time) (bool, error) {
+		if !strings.HasPrefix(entry.Key, prefix) {
+			return false, nil
+		}
+		if entry.ModTime.IsZero() {
+			return false, nil
+		}
+		return now.Sub(entry.ModTime) > maxAge, nil
+	})
+}
+
+// DenyKeys drops pairs whose keys match pattern.
+func DenyKeys(pattern *regexp.Regexp) CompactionFilter {
+	return CompactionFilterFunc(func(entry FilterEntry, _ time.Time) (bool, error) {
+		return pattern.MatchString(entry.Key), nil
+	})
+}
+
+// AnyFilter drops the pairs that any of filters drops.
+func AnyFilter(filters ...CompactionFilter) CompactionFilter {
+	return CompactionFilterFunc(func(entry FilterEntry, now time.Time) (bool, error) {
+		for _, filter := range filters {
+			drop, err := filter.Drop(entry, now)
+			if err != nil || drop {
+				return drop, err
+			}
+		}
+		return false, nil
+	})
+}
+
+// ApplyFilter deletes every pair of s that filter drops and returns the number
+// of keys deleted. Pairs are evaluated by the first store in the chain that
+// implements FilterEvaluator, or read through s otherwise; deletes always go
+// through s, so wrappers observe them as ordinary deletes.
+func ApplyFilter(s Store, filter CompactionFilter, now time.Time) (int, error) {
+	return applyFilter(s, filter, now, BatchDelete)
+}
+
+// ApplyExpiry is ApplyFilter for filters that expire pairs: the dropped keys
+// are removed with ExpireKeys, so wrappers observe them as expirations.
+func ApplyExpiry(s Store, filter CompactionFilter, now time.Time) (int, error) {
+	return applyFilter(s, filter, now, ExpireKeys)
+}
+
+// ExpireKeys removes the keys from s as expired when s implements Expirer,
+// and with BatchDelete otherwise. Like BatchDelete, only s itself is checked.
+func ExpireKeys(s Store, keys []string) error {
+	if expirer, ok := s.(Expirer); ok {
+		return expirer.ExpireKeys(keys)
+	}
+	return BatchDelete(s, keys)
+}
+
+// applyFilter removes the pairs of s that filter drops in batches with remove.
+func applyFilter(s Store, filter CompactionFilter, now time.Time, remove func(Store, []string) error) (int, error) {
+	var dropped []string
+	var err error
+	if evaluator, ok := As[FilterEvaluator](s); ok {
+		dropped, err = evaluator.EvaluateFilter(filter, now)
+	} else {
+		dropped, err = EvaluateFilter(s, filter, now)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for start := 0; start < len(dropped); start += filterBatchSize {
+		batch := dropped[start:min(start+filterBatchSize, len(dropped))]
+		if err := remove(s, batch); err != nil {
+			return deleted, err
+		}
+		deleted += len(batch)
+	}
+	return deleted, nil
+}
+
+// EvaluateFilter returns the keys of the pairs of s that filter drops, reading
+// them through s without write times. The iterator is closed before it
+// returns, so callers may delete the keys right away.
+func EvaluateFilter(s Store, filter CompactionFilter, now time.Time) (dropped []string, err error) {
+	it, err := NewIterator(s, "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for it.Next() {
+		entry := FilterEntry{Key: it.Key(), Value: it.Value()}
+		drop, err := filter.Drop(entry, now)
+		if err != nil {
+			return nil, fmt.Errorf("filter failed on key %q: %w", entry.Key, err)
+		}
+		if drop {
+			dropped = append(dropped, entry.Key)
+		}
+	}
+	return dropped, it.Err()
+}
+
+// FilterConfig configures background compaction filtering.
+type FilterConfig struct {
+	Interval time.Duration     // Time between passes; DefaultFilterInterval if zero
+	Filter   CompactionFilter  // Pairs to remove; none if nil
+	Expire   CompactionFilter  // Pairs to remove as expired, such as ExpireAfter; none if nil
+	Metrics  *metrics.Registry // Registry for filter metrics; metrics.Default if nil
+	Logger   *slog.Logger      // Logger for failed passes; slog.Default() if nil
+}
+
+// StartFilter applies the configured filter to s in the background until the
+// returned function is called.
+func StartFilter(s Store, config FilterConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultFilterInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	dropped := registry.Counter(MetricFilterDroppedKeys)
+	expired := registry.Counter(MetricFilterExpiredKeys)
+	logger := logging.Or(config.Logger)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if config.Expire != nil {
+					n, err := ApplyExpiry(s, config.Expire, now)
+					if err != nil {
+						logger.Warn("Expiration pass failed", "error", err)
+					}
+					expired.Add(uint64(n)) // #nosec G115 -- counts are non-negative
+				}
+				if config.Filter != nil {
+					n, err := ApplyFilter(s, config.Filter, now)
+					if err != nil {
+						logger.Warn("Compaction filter pass failed", "error", err)
+					}
+					dropped.Add(uint64(n)) // #nosec G115 -- counts are non-negative
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package store_test
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+// datedStore evaluates filters with fixed write times
+type datedStore struct {
+	store.Store
+	modTimes map[string]time.Time
+}
+
+func (s *datedStore) Unwrap() store.Store { return s.Store }
+
+func (s *datedStore) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	pairs, err := s.Scan("")
+	if err != nil {
+		return nil, err
+	}
+	var dropped []string
+	for key, value := range pairs {
+		drop, err := filter.Drop(store.FilterEntry{Key: key, Value: value, ModTime: s.modTimes[key]}, now)
+		if err != nil {
+			return nil, err
+		}
+		if drop {
+			dropped = append(dropped, key)
+		}
+	}
+	return dropped, nil
+}
+
+func remainingKeys(t *testing.T, s store.Store) []string {
+	t.Helper()
+	pairs, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newFilterTestStore(t *testing.T, keys ...string) store.Store {
+	t.Helper()
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if err := s.Put(key, []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestApplyFilter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("DenyKeys", func(t *testing.T) {
+		s := newFilterTestStore(t, "tmp/a", "tmp/b", "users/a")
+		n, err := store.ApplyFilter(s, store.DenyKeys(regexp.MustCompile(`^tmp/`)), now)
+		if err != nil {
+			t.Fatalf("ApplyFilter failed: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("Expected 2 keys dropped, got %d", n)
+		}
+		if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"users/a"}) {
+			t.Errorf("Unexpected remaining keys %v", got)
+		}
+	})
+
+	t.Run("ExpireAfter", func(t *testing.T) {
+		s := &datedStore{
+			Store: newFilterTestStore(t, "cache/old", "cache/new", "users/old"),
+			modTimes: map[string]time.Time{
+				"cache/old": now.Add(-2 * time.Hour),
+				"cache/new": now.Add(-time.Minute),
+				"users/old": now.Add(-2 * time.Hour),
+			},
+		}
+		if _, err := store.ApplyFilter(s, store.ExpireAfter("cache/", time.Hour), now); err != nil {
+			t.Fatalf("ApplyFilter failed: %v", err)
+		}
+		if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"cache/new", "users/old"}) {
+			t.Errorf("Unexpected remaining keys %v", got)
+		}
+	})
+
+	t.Run("ExpireAfterKeepsUnknownAges", func(t *testing.T) {
+		s := newFilterTestStore(t, "cache/a")
+		if _, err := store.ApplyFilter(s, store.ExpireAfter("cache/", 0), now); err != nil {
+			t.Fatalf("ApplyFilter failed: %v", err)
+		}
+		if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"cache/a"}) {
+			t.Errorf("Expected keys without write times to be kept, got %v", got)
+		}
+	})
+
+	t.Run("AnyFilter", func(t *testing.T) {
+		s := newFilterTestStore(t, "a", "b", "c")
+		filter := store.AnyFilter(
+			store.DenyKeys(regexp.MustCompile(`^a$`)),
+			store.DenyKeys(regexp.MustCompile(`^c$`)),
+		)
+		if _, err := store.ApplyFilter(s, filter, now); err != nil {
+			t.Fatalf("ApplyFilter failed: %v", err)
+		}
+		if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"b"}) {
+			t.Errorf("Unexpected remaining keys %v", got)
+		}
+	})
+
+	t.Run("FilterError", func(t *testing.T) {
+		s := newFilterTestStore(t, "a")
+		filterErr := errors.New("broken policy")
+		failing := store.CompactionFilterFunc(func(store.FilterEntry, time.Time) (bool, error) { return false, filterErr })
+		if _, err := store.ApplyFilter(s, failing, now); !errors.Is(err, filterErr) {
+			t.Errorf("Expected the filter error, got %v", err)
+		}
+		if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"a"}) {
+			t.Errorf("Expected nothing deleted, got %v", got)
+		}
+	})
+}
+
+// expiringStore records the keys removed as expired
+type expiringStore struct {
+	store.Store
+	expired []string
+}
+
+func (s *expiringStore) Unwrap() store.Store { return s.Store }
+
+func (s *expiringStore) ExpireKeys(keys []string) error {
+	if err := store.BatchDelete(s.Store, keys); err != nil {
+		return err
+	}
+	s.expired = append(s.expired, keys...)
+	return nil
+}
+
+func TestApplyExpiry(t *testing.T) {
+	now := time.Now()
+	base := &datedStore{
+		Store: newFilterTestStore(t, "cache/old", "cache/new"),
+		modTimes: map[string]time.Time{
+			"cache/old": now.Add(-2 * time.Hour),
+			"cache/new": now,
+		},
+	}
+	s := &expiringStore{Store: base}
+
+	n, err := store.ApplyExpiry(s, store.ExpireAfter("cache/", time.Hour), now)
+	if err != nil {
+		t.Fatalf("ApplyExpiry failed: %v", err)
+	}
+	if n != 1 || !reflect.DeepEqual(s.expired, []string{"cache/old"}) {
+		t.Errorf("Expected cache/old to be expired, got %d keys %v", n, s.expired)
+	}
+	if got := remainingKeys(t, s); !reflect.DeepEqual(got, []string{"cache/new"}) {
+		t.Errorf("Unexpected remaining keys %v", got)
+	}
+
+	// Stores that do not distinguish expirations delete the keys
+	plain := newFilterTestStore(t, "a")
+	if n, err := store.ApplyExpiry(plain, store.DenyKeys(regexp.MustCompile(`^a$`)), now); err != nil || n != 1 {
+		t.Fatalf("Expected one key removed, got %d, %v", n, err)
+	}
+	if got := remainingKeys(t, plain); len(got) != 0 {
+		t.Errorf("Unexpected remaining keys %v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package store
 
-import "io"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 type Getter interface {
 	// Get retrieves the value associated with the key. Returns the value, a boolean indicating if the key exists, and an error if any.
@@ -29,4 +33,121 @@ type Store interface {
 	Putter
 	Deleter
 	Scanner
+	// Stats reports the contents and resource usage of the store.
+	Stats() (Stats, error)
+}
+
+// Relocator is implemented by stores whose data directory can be moved while they keep serving reads.
+type Relocator interface {
+	// Relocate copies all data into newPath, switches over to it and retires the old directory. Returns the path the old directory was moved to.
+	Relocate(newPath string) (retiredPath string, err error)
+}
+
+// Iterator walks key-value pairs in key order. Callers must call Close when done.
+type Iterator interface {
+	io.Closer
+	// Next advances to the next pair. Returns false when iteration is finished or has failed; check Err to tell which.
+	Next() bool
+	// Key returns the key of the current pair.
+	Key() string
+	// Value returns a copy of the value of the current pair.
+	Value() []byte
+	// Err returns the error that stopped iteration, if any.
+	Err() error
+}
+
+// IteratorProvider is implemented by stores that can stream key-value pairs without buffering the whole result set.
+type IteratorProvider interface {
+	// NewIterator returns an iterator over the pairs whose keys start with the given prefix, positioned before the first pair.
+	NewIterator(prefix string) (Iterator, error)
+}
+
+// Viewer is implemented by stores that can hand out a value without copying it, for readers that only look at it, such as to checksum it or write it to a connection.
+type Viewer interface {
+	// GetView calls fn with the value associated with the key, if the key exists, and reports whether it does. The value is only valid during the call and must not be modified. Returns the error fn returns, if any.
+	GetView(key string, fn func(value []byte) error) (bool, error)
+}
+
+// ForEacher is implemented by stores that can visit key-value pairs without copying them into an iterator or a map.
+type ForEacher interface {
+	// ForEach calls fn with every pair whose key starts with the given prefix, in key order, stopping at the first error fn returns. The value is only valid during the call.
+	ForEach(prefix string, fn func(key string, value []byte) error) error
+}
+
+// Batcher is implemented by stores that can apply many operations in a single call.
+type Batcher interface {
+	// BatchPut stores all the given pairs. Either every pair is stored or, on error, none are.
+	BatchPut(pairs map[string][]byte) error
+	// BatchGet retrieves the values of the given keys. Keys that do not exist are absent from the result.
+	BatchGet(keys []string) (map[string][]byte, error)
+	// BatchDelete removes all the given keys. Either every key is removed or, on error, none are.
+	BatchDelete(keys []string) error
+}
+
+// Expirer is implemented by stores that remove expired keys differently from deleted ones, such as to tell watchers why a key went away.
+type Expirer interface {
+	// ExpireKeys removes all the given keys as expired. Either every key is removed or, on error, none are.
+	ExpireKeys(keys []string) error
+}
+
+// VersionReader is implemented by stores that retain previous values of keys.
+type VersionReader interface {
+	// GetAt retrieves the value of key as of the given version, or the latest value when version is 0. Returns the value, the version it was written at, a boolean indicating if the key existed at that version, and an error if any.
+	GetAt(key string, version uint64) ([]byte, uint64, bool, error)
+}
+
+// Pinger is implemented by stores that can report whether they are able to serve requests.
+type Pinger interface {
+	// Ping returns an error if the store cannot serve requests, for example because it has been closed.
+	Ping() error
+}
+
+// ContextWriter is implemented by stores whose writes depend on the request they are made for, such as the identity of the caller.
+type ContextWriter interface {
+	// PutContext stores the value associated with the key on behalf of the request in ctx.
+	PutContext(ctx context.Context, key string, value []byte) error
+	// BatchPutContext stores all the given pairs on behalf of the request in ctx. Either every pair is stored or, on error, none are.
+	BatchPutContext(ctx context.Context, pairs map[string][]byte) error
+	// DeleteContext removes the key on behalf of the request in ctx.
+	DeleteContext(ctx context.Context, key string) error
+}
+
+// ContextReader is implemented by stores whose reads use the request they are made for, such as to trace them.
+type ContextReader interface {
+	// GetContext retrieves the value associated with the key on behalf of the request in ctx.
+	GetContext(ctx context.Context, key string) ([]byte, bool, error)
+	// ScanContext returns all pairs whose keys start with the prefix on behalf of the request in ctx.
+	ScanContext(ctx context.Context, prefix string) (map[string][]byte, error)
+}
+
+// SnapshotReader is implemented by stores that can read every key as it was at a past version, for consistent exports.
+type SnapshotReader interface {
+	// VersionAt returns the latest version known to have been committed at or before t. Returns ErrSnapshotUnavailable if the store cannot read that far back.
+	VersionAt(t time.Time) (uint64, error)
+	// NewIteratorAt returns an iterator over the pairs whose keys start with the given prefix as they were at version, positioned before the first pair. Iteration fails with ErrSnapshotUnavailable if the history of a key was discarded.
+	NewIteratorAt(prefix string, version uint64) (Iterator, error)
+}
+
+// Backuper is implemented by stores that can dump their data as of one instant, in full or as the changes since an earlier dump, and load such dumps back.
+type Backuper interface {
+	// Backup writes every change after version since, 0 for all data, as of a single point in time to w, in an encoding specific to the store. Returns the version the dump is complete up to, to be passed as since next time.
+	Backup(w io.Writer, since uint64) (until uint64, err error)
+	// Restore loads a stream written by Backup of the same kind of store. A full dump, where since is 0, needs an empty store and returns ErrStoreNotEmpty otherwise; an incremental one needs the store to have been restored up to version since and returns ErrBackupGap otherwise.
+	Restore(r io.Reader, since uint64) error
+}
+
+// ConditionalPutter is implemented by stores that can write a key only while it is at an expected version, as reported by VersionReader.
+type ConditionalPutter interface {
+	// PutIfVersion stores the value only if the latest version of the key is expected, where 0 means the key must not exist. Returns ErrVersionConflict otherwise.
+	PutIfVersion(key string, value []byte, expected uint64) error
+}
+
+// Maintainer is implemented by stores that keep data on disk and can be maintained while they serve requests.
+type Maintainer interface {
+	// DiskUsage returns the size of the data files in bytes.
+	DiskUsage() int64
+	// RunGC rewrites the data files in which at least discardRatio of the space is taken by deleted, expired or overwritten values, until none is left, with a default ratio suited to the store if discardRatio is zero. Returns the number of files rewritten.
+	RunGC(discardRatio float64) (int, error)
+	// Flush writes every buffered write to disk.
+	Flush() error
 }
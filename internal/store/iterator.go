@@ -0,0 +1,115 @@
+package store
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrStopIteration is returned by the callbacks of ForEach and VisitIterator
+// to stop early without failing: they then return nil.
+var ErrStopIteration = errors.New("stop iteration")
+
+// ForEach calls fn with every pair in s whose key starts with prefix, in key
+// order, until fn returns an error. Stores implementing ForEacher visit their
+// pairs in place, so fn must copy values it keeps after returning; any other
+// store is walked with NewIterator.
+func ForEach(s Store, prefix string, fn func(key string, value []byte) error) error {
+	if forEacher, ok := s.(ForEacher); ok {
+		return stopped(forEacher.ForEach(prefix, fn))
+	}
+	it, err := NewIterator(s, prefix)
+	if err != nil {
+		return err
+	}
+	return VisitIterator(it, fn)
+}
+
+// VisitIterator calls fn with every pair of it until fn returns an error, then
+// closes it.
+func VisitIterator(it Iterator, fn func(key string, value []byte) error) (err error) {
+	defer func() {
+		if closeErr := it.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return stopped(err)
+		}
+	}
+	return it.Err()
+}
+
+// stopped returns err, or nil if it is ErrStopIteration.
+func stopped(err error) error {
+	if errors.Is(err, ErrStopIteration) {
+		return nil
+	}
+	return err
+}
+
+// NewIterator returns an iterator over the pairs in s whose keys start with
+// prefix. Stores implementing IteratorProvider stream their results; for any
+// other store the Scan result is buffered and sorted.
+func NewIterator(s Store, prefix string) (Iterator, error) {
+	if provider, ok := s.(IteratorProvider); ok {
+		return provider.NewIterator(prefix)
+	}
+
+	results, err := s.Scan(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return NewSliceIterator(results), nil
+}
+
+// SliceIterator iterates over an in-memory set of pairs in key order.
+type SliceIterator struct {
+	keys   []string
+	values map[string][]byte
+	pos    int
+}
+
+// NewSliceIterator creates an iterator over the given pairs, sorted by key.
+func NewSliceIterator(pairs map[string][]byte) *SliceIterator {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return &SliceIterator{keys: keys, values: pairs, pos: -1}
+}
+
+// Next advances to the next pair
+func (it *SliceIterator) Next() bool {
+	if it.pos+1 >= len(it.keys) {
+		it.pos = len(it.keys)
+		return false
+	}
+	it.pos++
+	return true
+}
+
+// Key returns the current key
+func (it *SliceIterator) Key() string {
+	return it.keys[it.pos]
+}
+
+// Value returns the current value
+func (it *SliceIterator) Value() []byte {
+	return it.values[it.keys[it.pos]]
+}
+
+// Err always returns nil since the pairs are already in memory
+func (it *SliceIterator) Err() error {
+	return nil
+}
+
+// Close releases the buffered pairs
+func (it *SliceIterator) Close() error {
+	it.keys = nil
+	it.values = nil
+	return nil
+}
+
+var _ Iterator = (*SliceIterator)(nil)
@@ -0,0 +1,93 @@
+package store_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// scanOnlyStore implements only the base Store interface
+type scanOnlyStore struct {
+	store.Store
+	pairs map[string][]byte
+	err   error
+}
+
+func (s *scanOnlyStore) Scan(prefix string) (map[string][]byte, error) {
+	return s.pairs, s.err
+}
+
+func TestNewIterator_FallsBackToScan(t *testing.T) {
+	s := &scanOnlyStore{pairs: map[string][]byte{"b": []byte("2"), "a": []byte("1"), "c": []byte("3")}}
+
+	it, err := store.NewIterator(s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := it.Close(); err != nil {
+			t.Logf("Failed to close iterator: %v", err)
+		}
+	}()
+
+	var keys, values []string
+	for it.Next() {
+		keys = append(keys, it.Key())
+		values = append(values, string(it.Value()))
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) || !reflect.DeepEqual(values, []string{"1", "2", "3"}) {
+		t.Errorf("Unexpected iteration: keys=%v values=%v", keys, values)
+	}
+	if it.Next() {
+		t.Error("Expected exhausted iterator to stay exhausted")
+	}
+
+	s.err = errors.New("scan failed")
+	if _, err := store.NewIterator(s, ""); err == nil {
+		t.Error("Expected Scan error to be returned")
+	}
+}
+
+func TestForEach(t *testing.T) {
+	s := &scanOnlyStore{pairs: map[string][]byte{"b": []byte("2"), "a": []byte("1"), "c": []byte("3")}}
+
+	var keys, values []string
+	err := store.ForEach(s, "", func(key string, value []byte) error {
+		keys = append(keys, key)
+		values = append(values, string(value))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) || !reflect.DeepEqual(values, []string{"1", "2", "3"}) {
+		t.Errorf("Unexpected visit: keys=%v values=%v", keys, values)
+	}
+
+	keys = nil
+	err = store.ForEach(s, "", func(key string, value []byte) error {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			return store.ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected ErrStopIteration to stop without failing, got %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected the visit to stop after b, got %v", keys)
+	}
+
+	failed := errors.New("callback failed")
+	if err := store.ForEach(s, "", func(string, []byte) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+
+	s.err = errors.New("scan failed")
+	if err := store.ForEach(s, "", func(string, []byte) error { return nil }); err == nil {
+		t.Error("Expected Scan error to be returned")
+	}
+}
@@ -0,0 +1,435 @@
+// Package keymeta wraps a store so that every value is stored in an envelope
+// recording when its key was created and last updated, and how many times it
+// was written. Reads through the wrapper return the values alone; Metadata
+// returns the envelope.
+//
+// An envelope is three magic bytes, a format byte, the creation and update
+// times in Unix nanoseconds, the update count and the value. Values stored
+// before the wrapper was added are read back unchanged, with no metadata
+// until their next write; a value stored without the wrapper that starts
+// with the magic bytes cannot be read through it.
+package keymeta
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+var (
+	// ErrNotTracked is returned when reading metadata from a store without
+	// the wrapper.
+	ErrNotTracked = errors.New("key metadata is not tracked")
+	// ErrCorruptValue is returned when reading a stored value whose envelope
+	// is of an unknown format or is cut short.
+	ErrCorruptValue = errors.New("corrupt key metadata envelope")
+)
+
+// magic starts every value stored in an envelope.
+var magic = []byte{0xff, 'K', 'M'}
+
+// formatV1 is the format of envelopes written by this package.
+const formatV1 byte = 1
+
+// headerSize is the size of an envelope before its value.
+const headerSize = 4 + 3*8
+
+// Metadata describes the writes of a key. It is zero for keys last written
+// before the wrapper was added.
+type Metadata struct {
+	Created time.Time // When the key was first written, or written again after a delete
+	Updated time.Time // When the key was last written
+	Updates uint64    // Writes of the key since it was created, counting the first
+}
+
+// Store wraps the values written to the wrapped store in envelopes and
+// unwraps the values read from it.
+type Store struct {
+	store.Passthrough
+	now func() time.Time
+}
+
+// New wraps base.
+func New(base store.Store) *Store {
+	return &Store{Passthrough: store.Passthrough{Store: base}, now: time.Now}
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+)
+
+// encode returns value in an envelope with meta.
+func encode(value []byte, meta Metadata) []byte {
+	stored := make([]byte, 0, headerSize+len(value))
+	stored = append(stored, magic...)
+	stored = append(stored, formatV1)
+	stored = binary.BigEndian.AppendUint64(stored, uint64(meta.Created.UnixNano())) // #nosec G115 -- times are after 1970
+	stored = binary.BigEndian.AppendUint64(stored, uint64(meta.Updated.UnixNano())) // #nosec G115 -- times are after 1970
+	stored = binary.BigEndian.AppendUint64(stored, meta.Updates)
+	return append(stored, value...)
+}
+
+// decode returns the value stored as stored with its metadata, which is zero
+// for a value stored without an envelope.
+func decode(stored []byte) ([]byte, Metadata, error) {
+	if !bytes.HasPrefix(stored, magic) {
+		return stored, Metadata{}, nil
+	}
+	if len(stored) == len(magic) || stored[len(magic)] != formatV1 {
+		return nil, Metadata{}, ErrCorruptValue
+	}
+	if len(stored) < headerSize {
+		return nil, Metadata{}, ErrCorruptValue
+	}
+	fields := stored[len(magic)+1:]
+	meta := Metadata{
+		Created: time.Unix(0, int64(binary.BigEndian.Uint64(fields))),     // #nosec G115 -- written from an int64
+		Updated: time.Unix(0, int64(binary.BigEndian.Uint64(fields[8:]))), // #nosec G115 -- written from an int64
+		Updates: binary.BigEndian.Uint64(fields[16:]),
+	}
+	return stored[headerSize:], meta, nil
+}
+
+// decodeValue returns the value stored as stored for key.
+func decodeValue(key string, stored []byte) ([]byte, error) {
+	value, _, err := decode(stored)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", key, err)
+	}
+	return value, nil
+}
+
+// decodeAll replaces the envelopes of pairs with their values.
+func decodeAll(pairs map[string][]byte) (map[string][]byte, error) {
+	for key, stored := range pairs {
+		value, err := decodeValue(key, stored)
+		if err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// next returns the metadata of a write at now of a key stored as previous,
+// if found.
+func next(previous []byte, found bool, now time.Time) Metadata {
+	meta := Metadata{Created: now, Updated: now, Updates: 1}
+	if !found {
+		return meta
+	}
+	if _, prev, err := decode(previous); err == nil && !prev.Created.IsZero() {
+		meta.Created, meta.Updates = prev.Created, prev.Updates+1
+	}
+	return meta
+}
+
+// lookup reads the stored form of key with its version, which is 0 when the
+// wrapped store keeps no versions.
+func (s *Store) lookup(ctx context.Context, key string) (stored []byte, version uint64, found bool, err error) {
+	if reader, ok := store.As[store.VersionReader](s.Store); ok {
+		stored, version, found, err = reader.GetAt(key, 0)
+		if !errors.Is(err, store.ErrSnapshotsUnsupported) {
+			return stored, version, found, err
+		}
+	}
+	stored, found, err = store.GetContext(ctx, s.Store, key)
+	return stored, 0, found, err
+}
+
+// Put stores the value with the metadata of the key updated.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx. When the wrapped store
+// keeps versions, the value is written conditionally on the metadata it was
+// derived from, starting over when another write came in between.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	for {
+		previous, version, found, err := s.lookup(ctx, key)
+		if err != nil {
+			return err
+		}
+		stored := encode(value, next(previous, found, s.now()))
+		if version == 0 {
+			return store.PutContext(ctx, s.Store, key, stored)
+		}
+		err = store.PutIfVersionContext(ctx, s.Store, key, stored, version)
+		if !errors.Is(err, store.ErrVersionConflict) {
+			return err
+		}
+	}
+}
+
+// PutIfVersion conditionally stores the value with the metadata of the key
+// updated.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	previous, _, found, err := s.lookup(ctx, key)
+	if err != nil {
+		return err
+	}
+	return store.PutIfVersionContext(ctx, s.Store, key, encode(value, next(previous, found, s.now())), expected)
+}
+
+// BatchPut stores the pairs with the metadata of their keys updated.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx. The pairs are
+// written unconditionally, so the update count of a key written by another
+// batch at the same time may miss that write.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	previous, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	encoded := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		stored, found := previous[key]
+		encoded[key] = encode(value, next(stored, found, now))
+	}
+	return store.BatchPutContext(ctx, s.Store, encoded)
+}
+
+// Get reads the value of key.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	stored, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return stored, found, err
+	}
+	value, err := decodeValue(key, stored)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// GetView calls fn with the value of key.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	return store.GetView(s.Store, key, func(stored []byte) error {
+		value, err := decodeValue(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(value)
+	})
+}
+
+// Scan reads the pairs whose keys start with prefix.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll(pairs)
+}
+
+// BatchGet reads the values of keys.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll(pairs)
+}
+
+// NewIterator streams the pairs whose keys start with prefix.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// ForEach visits the pairs whose keys start with prefix.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, stored []byte) error {
+		value, err := decodeValue(key, stored)
+		if err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the pairs whose keys are in r. A keys-only range
+// is streamed from the wrapped store as is, since there are no values to
+// unwrap.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil || r.KeysOnly {
+		return it, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// GetAt reads the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	stored, at, found, err := reader.GetAt(key, version)
+	if err != nil || !found {
+		return stored, at, found, err
+	}
+	value, err := decodeValue(key, stored)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return value, at, true, nil
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the pairs whose keys start with prefix as they were
+// at version.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// EvaluateFilter presents the unwrapped values to filter. Pairs whose write
+// time the wrapped store does not track are presented with their update
+// time, so filters such as store.ExpireAfter work on any store.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	withTimes := store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		value, meta, err := decode(entry.Value)
+		if err != nil {
+			return false, fmt.Errorf("key %q: %w", entry.Key, err)
+		}
+		entry.Value = value
+		if entry.ModTime.IsZero() {
+			entry.ModTime = meta.Updated
+		}
+		return filter.Drop(entry, now)
+	})
+	if evaluator, ok := store.As[store.FilterEvaluator](s.Store); ok {
+		return evaluator.EvaluateFilter(withTimes, now)
+	}
+	return store.EvaluateFilter(s.Store, withTimes, now)
+}
+
+// Metadata returns the metadata of key as of version, the latest one if
+// zero, reporting false if the key does not exist.
+func (s *Store) Metadata(key string, version uint64) (Metadata, bool, error) {
+	var stored []byte
+	var found bool
+	var err error
+	if version == 0 {
+		stored, found, err = store.GetContext(context.Background(), s.Store, key)
+	} else if reader, ok := store.As[store.VersionReader](s.Store); ok {
+		stored, _, found, err = reader.GetAt(key, version)
+	} else {
+		err = store.ErrSnapshotsUnsupported
+	}
+	if err != nil || !found {
+		return Metadata{}, false, err
+	}
+	_, meta, err := decode(stored)
+	if err != nil {
+		return Metadata{}, false, fmt.Errorf("key %q: %w", key, err)
+	}
+	return meta, true, nil
+}
+
+// BatchMetadata returns the metadata of the keys that exist.
+func (s *Store) BatchMetadata(keys []string) (map[string]Metadata, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	metas := make(map[string]Metadata, len(pairs))
+	for key, stored := range pairs {
+		_, meta, err := decode(stored)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		metas[key] = meta
+	}
+	return metas, nil
+}
+
+// From returns the metadata wrapper beneath s, failing with ErrNotTracked if
+// there is none.
+func From(s store.Store) (*Store, error) {
+	meta, ok := store.As[*Store](s)
+	if !ok {
+		return nil, ErrNotTracked
+	}
+	return meta, nil
+}
+
+// iterator unwraps the values of the wrapped iterator. A value with a
+// corrupt envelope stops the iteration.
+type iterator struct {
+	store.Iterator
+	value []byte
+	err   error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	it.value, it.err = decodeValue(it.Iterator.Key(), it.Iterator.Value())
+	return it.err == nil
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
@@ -0,0 +1,158 @@
+package keymeta
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T) (*Store, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(base), base
+}
+
+func TestStore_TracksWrites(t *testing.T) {
+	s, base := newStore(t)
+	created := time.Unix(1700000000, 0)
+	now := created
+	s.now = func() time.Time { return now }
+
+	if err := s.Put("users/1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if err := s.BatchPut(map[string][]byte{"users/1": []byte("alicia"), "users/2": []byte("bob")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.Put("users/3", []byte("carol")); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found, err := s.Get("users/1"); err != nil || !found || string(value) != "alicia" {
+		t.Errorf("Get() = %q, %v, %v; want the unwrapped value", value, found, err)
+	}
+	if stored, _, _ := base.Get("users/1"); string(stored) == "alicia" {
+		t.Error("Expected the wrapped store to hold an envelope")
+	}
+	meta, found, err := s.Metadata("users/1", 0)
+	if err != nil || !found {
+		t.Fatalf("Metadata() = %v, %v; want the key found", found, err)
+	}
+	if !meta.Created.Equal(created) || !meta.Updated.Equal(now) || meta.Updates != 2 {
+		t.Errorf("Metadata() = %+v; want created %v, updated %v and 2 updates", meta, created, now)
+	}
+
+	metas, err := s.BatchMetadata([]string{"users/1", "users/2", "users/3", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metas) != 3 || metas["users/2"].Updates != 1 || !metas["users/2"].Created.Equal(now) {
+		t.Errorf("BatchMetadata() = %+v; want three keys with users/2 written once", metas)
+	}
+	// Values written before the wrapper read as they are, with no metadata
+	if meta := metas["users/3"]; !meta.Created.IsZero() || meta.Updates != 0 {
+		t.Errorf("Expected no metadata for a value written beneath the wrapper, got %+v", meta)
+	}
+	pairs, err := s.Scan("users/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"users/1": "alicia", "users/2": "bob", "users/3": "carol"} {
+		if string(pairs[key]) != want {
+			t.Errorf("Scan()[%q] = %q, want %q", key, pairs[key], want)
+		}
+	}
+
+	// A write after a delete creates the key anew
+	if err := s.Delete("users/1"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if err := s.Put("users/1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if meta, _, _ := s.Metadata("users/1", 0); !meta.Created.Equal(now) || meta.Updates != 1 {
+		t.Errorf("Metadata() = %+v after a delete; want the key created again", meta)
+	}
+}
+
+func TestStore_Versioned(t *testing.T) {
+	base, err := badger.NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = base.Close() })
+	s := New(base)
+
+	if err := store.PutIfAbsent(s, "hits", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	_, version, found, err := s.GetAt("hits", 0)
+	if err != nil || !found {
+		t.Fatalf("GetAt() = %v, %v; want the key found", found, err)
+	}
+	if err := s.PutIfVersion("hits", []byte("2"), version); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutIfVersion("hits", []byte("3"), version); !errors.Is(err, store.ErrVersionConflict) {
+		t.Errorf("Expected a stale version to conflict, got %v", err)
+	}
+	if err := s.Put("hits", []byte("4")); err != nil {
+		t.Fatal(err)
+	}
+
+	if meta, _, err := s.Metadata("hits", 0); err != nil || meta.Updates != 3 {
+		t.Errorf("Metadata() = %+v, %v; want 3 updates", meta, err)
+	}
+	if meta, found, err := s.Metadata("hits", version); err != nil || !found || meta.Updates != 1 {
+		t.Errorf("Metadata() as of the first version = %+v, %v, %v; want 1 update", meta, found, err)
+	}
+	if value, _, _, _ := s.GetAt("hits", version); string(value) != "1" {
+		t.Errorf("GetAt() = %q, want the first value", value)
+	}
+}
+
+func TestStore_EvaluateFilter(t *testing.T) {
+	s, _ := newStore(t)
+	now := time.Now()
+	s.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	if err := s.Put("sessions/old", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return now }
+	if err := s.Put("sessions/new", []byte("y")); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := s.EvaluateFilter(store.ExpireAfter("sessions/", time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "sessions/old" {
+		t.Errorf("EvaluateFilter() = %v, want [sessions/old]", keys)
+	}
+}
+
+func TestStore_CorruptValue(t *testing.T) {
+	s, base := newStore(t)
+	if err := base.Put("bad", append(append([]byte(nil), magic...), formatV1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Get("bad"); !errors.Is(err, ErrCorruptValue) {
+		t.Errorf("Expected ErrCorruptValue, got %v", err)
+	}
+	if _, err := From(base); !errors.Is(err, ErrNotTracked) {
+		t.Errorf("Expected ErrNotTracked without the wrapper, got %v", err)
+	}
+	if got, err := From(store.Passthrough{Store: s}); err != nil || got != s {
+		t.Errorf("From() = %v, %v; want the wrapper beneath", got, err)
+	}
+}
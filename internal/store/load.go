@@ -0,0 +1,88 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultLoadBatchSize is how many pairs NewLoad writes at once to stores
+// without a Loader, unless told otherwise.
+const DefaultLoadBatchSize = 1000
+
+// ErrUnsortedLoad is returned by Load.Add for a key not greater than the one
+// added before it.
+var ErrUnsortedLoad = errors.New("keys of a load must be added in increasing order")
+
+// Load ingests pairs added in increasing key order. Once Add fails, the load
+// must be canceled.
+type Load interface {
+	// Add adds a pair, whose key is greater than that of every pair added before. Returns ErrUnsortedLoad otherwise.
+	Add(key string, value []byte) error
+	// Finish writes the pairs not written yet and ends the load.
+	Finish() error
+	// Cancel ends the load, keeping only the pairs already written, if any.
+	Cancel()
+}
+
+// Loader is implemented by stores that can ingest sorted pairs into an empty store faster than by writing them.
+type Loader interface {
+	// NewLoad starts a load into the store, which must hold no key; ErrStoreNotEmpty otherwise. Other writes wait until the load ends, and the pairs loaded are only visible once it is finished.
+	NewLoad() (Load, error)
+}
+
+// NewLoad starts a load into s, with s itself if it implements Loader and
+// otherwise by writing the pairs with BatchPut, batchSize at a time or
+// DefaultLoadBatchSize if it is not positive. Like BatchPut, only s itself is
+// checked for Loader, so wrappers that act on individual writes are not
+// bypassed.
+func NewLoad(s Store, batchSize int) (Load, error) {
+	if loader, ok := s.(Loader); ok {
+		return loader.NewLoad()
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultLoadBatchSize
+	}
+	return &batchLoad{store: s, size: batchSize, pairs: make(map[string][]byte, batchSize)}, nil
+}
+
+// batchLoad loads pairs into a store with BatchPut.
+type batchLoad struct {
+	store   Store
+	size    int
+	pairs   map[string][]byte
+	last    string
+	started bool
+}
+
+func (l *batchLoad) Add(key string, value []byte) error {
+	if l.started && key <= l.last {
+		return fmt.Errorf("%w: %q after %q", ErrUnsortedLoad, key, l.last)
+	}
+	l.pairs[key] = value
+	l.last, l.started = key, true
+	if len(l.pairs) < l.size {
+		return nil
+	}
+	return l.flush()
+}
+
+func (l *batchLoad) Finish() error {
+	return l.flush()
+}
+
+func (l *batchLoad) Cancel() {
+	l.pairs = nil
+}
+
+// flush writes the pairs added since the last flush.
+func (l *batchLoad) flush() error {
+	if len(l.pairs) == 0 {
+		return nil
+	}
+	if err := BatchPut(l.store, l.pairs); err != nil {
+		return err
+	}
+	// Wrappers may hold on to the pairs they were given
+	l.pairs = make(map[string][]byte, l.size)
+	return nil
+}
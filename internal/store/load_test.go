@@ -0,0 +1,41 @@
+package store_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestNewLoad_Batches(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	load, err := store.NewLoad(s, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 25 {
+		if err := load.Add(fmt.Sprintf("k%02d", i), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Full batches are written as they fill up
+	if pairs, _ := s.Scan("k"); len(pairs) != 20 {
+		t.Errorf("Expected 2 batches written before Finish, got %d pairs", len(pairs))
+	}
+	if err := load.Add("k00", nil); !errors.Is(err, store.ErrUnsortedLoad) {
+		t.Errorf("Expected ErrUnsortedLoad, got %v", err)
+	}
+	if err := load.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, _ := s.Scan("k"); len(pairs) != 25 {
+		t.Errorf("Expected every pair written after Finish, got %d", len(pairs))
+	}
+}
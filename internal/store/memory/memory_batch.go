@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// BatchPut stores all pairs under a single lock acquisition
+func (ms *MemoryStore) BatchPut(pairs map[string][]byte) error {
+	ms.ops.CountPuts(len(pairs))
+	for key := range pairs {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.data == nil {
+		return fmt.Errorf("store is closed")
+	}
+
+	for key, value := range pairs {
+		valueCopy := make([]byte, len(value))
+		copy(valueCopy, value)
+		ms.data[key] = valueCopy
+	}
+	return nil
+}
+
+// BatchGet retrieves the values of keys under a single lock acquisition
+func (ms *MemoryStore) BatchGet(keys []string) (map[string][]byte, error) {
+	ms.ops.CountGets(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if ms.data == nil {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, found := ms.data[key]; found {
+			valueCopy := make([]byte, len(value))
+			copy(valueCopy, value)
+			result[key] = valueCopy
+		}
+	}
+	return result, nil
+}
+
+// BatchDelete removes keys under a single lock acquisition
+func (ms *MemoryStore) BatchDelete(keys []string) error {
+	ms.ops.CountDeletes(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.data == nil {
+		return fmt.Errorf("store is closed")
+	}
+
+	for _, key := range keys {
+		delete(ms.data, key)
+	}
+	return nil
+}
+
+var _ store.Batcher = (*MemoryStore)(nil)
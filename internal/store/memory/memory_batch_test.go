@@ -0,0 +1,48 @@
+package memory
+
+import "testing"
+
+func TestMemoryStore_Batch(t *testing.T) {
+	store := createTestStore(t)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	pairs := map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}
+	if err := store.BatchPut(pairs); err != nil {
+		t.Fatalf("BatchPut failed: %v", err)
+	}
+
+	got, err := store.BatchGet([]string{"a", "c", "missing"})
+	if err != nil {
+		t.Fatalf("BatchGet failed: %v", err)
+	}
+	if len(got) != 2 || string(got["a"]) != "1" || string(got["c"]) != "3" {
+		t.Errorf("Unexpected BatchGet result: %v", got)
+	}
+
+	if err := store.BatchDelete([]string{"a", "b", "missing"}); err != nil {
+		t.Fatalf("BatchDelete failed: %v", err)
+	}
+	remaining, err := store.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 1 || string(remaining["c"]) != "3" {
+		t.Errorf("Expected only c to remain, got %v", remaining)
+	}
+}
+
+func TestMemoryStore_BatchPut_RejectsEmptyKey(t *testing.T) {
+	store := createTestStore(t)
+
+	err := store.BatchPut(map[string][]byte{"ok": []byte("1"), "": []byte("2")})
+	if err == nil {
+		t.Fatal("Expected an error for an empty key")
+	}
+	if _, found, _ := store.Get("ok"); found {
+		t.Error("Expected no pairs to be stored when the batch is rejected")
+	}
+}
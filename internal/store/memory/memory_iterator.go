@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// memoryIterator walks a snapshot of the matching keys taken when it was
+// created, fetching each value lazily so only one value is copied at a time.
+// Keys deleted after the snapshot are skipped.
+type memoryIterator struct {
+	ms       *MemoryStore
+	keys     []string
+	pos      int
+	value    []byte
+	err      error
+	keysOnly bool
+}
+
+// NewIterator returns an iterator over the pairs whose keys start with prefix
+func (ms *MemoryStore) NewIterator(prefix string) (store.Iterator, error) {
+	ms.ops.CountScan()
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if ms.data == nil {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	keys := make([]string, 0)
+	for key := range ms.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return &memoryIterator{ms: ms, keys: keys, pos: -1}, nil
+}
+
+// NewRangeIterator returns an iterator over the pairs whose keys are in r
+func (ms *MemoryStore) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	ms.ops.CountScan()
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if ms.data == nil {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	keys := make([]string, 0)
+	for key := range ms.data {
+		if r.Contains(key) {
+			keys = append(keys, key)
+		}
+	}
+	if r.Reverse {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+
+	return &memoryIterator{ms: ms, keys: keys, pos: -1, keysOnly: r.KeysOnly}, nil
+}
+
+func (it *memoryIterator) Next() bool {
+	it.ms.mu.RLock()
+	defer it.ms.mu.RUnlock()
+
+	for it.pos+1 < len(it.keys) {
+		it.pos++
+		if it.ms.data == nil {
+			it.err = fmt.Errorf("store is closed")
+			return false
+		}
+		value, found := it.ms.data[it.keys[it.pos]]
+		if !found {
+			continue
+		}
+		if it.keysOnly {
+			it.value = nil
+			return true
+		}
+		// Copy to prevent external modification of internal data
+		it.value = make([]byte, len(value))
+		copy(it.value, value)
+		return true
+	}
+	return false
+}
+
+func (it *memoryIterator) Key() string {
+	return it.keys[it.pos]
+}
+
+func (it *memoryIterator) Value() []byte {
+	return it.value
+}
+
+func (it *memoryIterator) Err() error {
+	return it.err
+}
+
+func (it *memoryIterator) Close() error {
+	it.keys = nil
+	it.value = nil
+	return nil
+}
+
+var (
+	_ store.IteratorProvider      = (*MemoryStore)(nil)
+	_ store.RangeIteratorProvider = (*MemoryStore)(nil)
+)
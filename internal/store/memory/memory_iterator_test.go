@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestMemoryStore_NewIterator(t *testing.T) {
+	store := createTestStore(t)
+
+	for _, key := range []string{"user:2", "user:1", "user:3", "product:1"} {
+		if err := store.Put(key, []byte("value-"+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("IteratesInKeyOrder", func(t *testing.T) {
+		it, err := store.NewIterator("user:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := it.Close(); err != nil {
+				t.Logf("Failed to close iterator: %v", err)
+			}
+		}()
+
+		var keys []string
+		for it.Next() {
+			keys = append(keys, it.Key())
+			if string(it.Value()) != "value-"+it.Key() {
+				t.Errorf("Unexpected value for %s: %s", it.Key(), it.Value())
+			}
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(keys, []string{"user:1", "user:2", "user:3"}) {
+			t.Errorf("Unexpected keys: %v", keys)
+		}
+	})
+
+	t.Run("SkipsKeysDeletedDuringIteration", func(t *testing.T) {
+		it, err := store.NewIterator("user:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := it.Close(); err != nil {
+				t.Logf("Failed to close iterator: %v", err)
+			}
+		}()
+
+		if err := store.Delete("user:2"); err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for it.Next() {
+			keys = append(keys, it.Key())
+		}
+		if !reflect.DeepEqual(keys, []string{"user:1", "user:3"}) {
+			t.Errorf("Unexpected keys: %v", keys)
+		}
+	})
+
+	t.Run("ClosedStore", func(t *testing.T) {
+		closed := createTestStore(t)
+		if err := closed.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := closed.NewIterator(""); err == nil {
+			t.Error("Expected error when iterating a closed store")
+		}
+	})
+}
+
+func TestMemoryStore_NewRangeIterator(t *testing.T) {
+	ms := createTestStore(t)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := ms.Put(key, []byte("v"+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, r := range []store.KeyRange{{Start: "b", End: "d"}, {Start: "b", End: "d", Reverse: true}} {
+		it, err := ms.NewRangeIterator(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for it.Next() {
+			keys = append(keys, it.Key())
+		}
+		it.Close()
+		want := []string{"b", "c"}
+		if r.Reverse {
+			want = []string{"c", "b"}
+		}
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("NewRangeIterator(%+v) keys = %v, want %v", r, keys, want)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 type MemoryStore struct {
 	mu   sync.RWMutex
 	data map[string][]byte
+	// ops counts the operations served, for Stats
+	ops store.OperationCounters
 }
 
 func New(config *MemoryStoreConfig) (*MemoryStore, error) {
@@ -37,8 +39,19 @@ func (ms *MemoryStore) Close() error {
 	return nil
 }
 
+// Ping reports whether the store is still open
+func (ms *MemoryStore) Ping() error {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if ms.data == nil {
+		return fmt.Errorf("store is closed")
+	}
+	return nil
+}
+
 // Get the value associated with the key
 func (ms *MemoryStore) Get(key string) ([]byte, bool, error) {
+	ms.ops.CountGets(1)
 	if key == "" {
 		return nil, false, fmt.Errorf("key cannot be empty")
 	}
@@ -61,8 +74,31 @@ func (ms *MemoryStore) Get(key string) ([]byte, bool, error) {
 	return result, true, nil
 }
 
+// GetView calls fn with the value associated with the key under the read
+// lock, without copying it
+func (ms *MemoryStore) GetView(key string, fn func(value []byte) error) (bool, error) {
+	ms.ops.CountGets(1)
+	if key == "" {
+		return false, fmt.Errorf("key cannot be empty")
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if ms.data == nil {
+		return false, fmt.Errorf("store is closed")
+	}
+
+	value, found := ms.data[key]
+	if !found {
+		return false, nil
+	}
+	return true, fn(value)
+}
+
 // Store the value associated with the key
 func (ms *MemoryStore) Put(key string, value []byte) error {
+	ms.ops.CountPuts(1)
 	if key == "" {
 		return fmt.Errorf("key cannot be empty")
 	}
@@ -83,6 +119,7 @@ func (ms *MemoryStore) Put(key string, value []byte) error {
 
 // Remove the key and its associated value from the store
 func (ms *MemoryStore) Delete(key string) error {
+	ms.ops.CountDeletes(1)
 	if key == "" {
 		return fmt.Errorf("key cannot be empty")
 	}
@@ -100,6 +137,7 @@ func (ms *MemoryStore) Delete(key string) error {
 
 // Retrieve all key-value pairs that start with the given prefix
 func (ms *MemoryStore) Scan(prefix string) (map[string][]byte, error) {
+	ms.ops.CountScan()
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 
@@ -121,4 +159,51 @@ func (ms *MemoryStore) Scan(prefix string) (map[string][]byte, error) {
 	return result, nil
 }
 
-var _ store.Store = (*MemoryStore)(nil)
+// Stats reports the number of keys, the size of the keys and values held and
+// the operations served.
+func (ms *MemoryStore) Stats() (store.Stats, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if ms.data == nil {
+		return store.Stats{}, fmt.Errorf("store is closed")
+	}
+
+	stats := store.Stats{
+		EstimatedKeys: int64(len(ms.data)),
+		Operations:    ms.ops.OperationCounts(),
+	}
+	for key, value := range ms.data {
+		stats.MemoryBytes += int64(len(key) + len(value))
+	}
+	return stats, nil
+}
+
+// Increment adds delta to the counter at key while holding the write lock
+func (ms *MemoryStore) Increment(key string, delta int64, encoding store.CounterEncoding) (int64, error) {
+	ms.ops.CountPuts(1)
+	if key == "" {
+		return 0, fmt.Errorf("key cannot be empty")
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.data == nil {
+		return 0, fmt.Errorf("store is closed")
+	}
+
+	next, encoded, err := store.AddCounter(encoding, ms.data[key], delta)
+	if err != nil {
+		return 0, err
+	}
+	ms.data[key] = encoded
+	return next, nil
+}
+
+var (
+	_ store.Store       = (*MemoryStore)(nil)
+	_ store.Pinger      = (*MemoryStore)(nil)
+	_ store.Incrementer = (*MemoryStore)(nil)
+	_ store.Viewer      = (*MemoryStore)(nil)
+)
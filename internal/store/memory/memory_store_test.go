@@ -1,7 +1,9 @@
 package memory
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/William-Fernandes252/clavis/internal/store"
@@ -827,3 +829,110 @@ func TestMemoryStoreImplementsInterface(t *testing.T) {
 		t.Error("Key should not be found after deletion")
 	}
 }
+
+func TestMemoryStore_Stats(t *testing.T) {
+	memStore, err := NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := memStore.BatchPut(map[string][]byte{"a": []byte("12"), "bc": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := memStore.BatchGet([]string{"a", "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := memStore.Delete("x"); err != nil {
+		t.Fatal(err)
+	}
+	it, err := memStore.NewIterator("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	it.Close()
+
+	stats, err := memStore.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := store.Stats{
+		EstimatedKeys: 2,
+		MemoryBytes:   6,
+		Operations:    store.OperationCounts{Gets: 2, Puts: 2, Deletes: 1, Scans: 1},
+	}
+	if stats != want {
+		t.Errorf("Stats = %+v, want %+v", stats, want)
+	}
+
+	memStore.Close()
+	if _, err := memStore.Stats(); err == nil {
+		t.Error("Expected Stats to fail on a closed store")
+	}
+}
+
+func TestMemoryStore_Increment(t *testing.T) {
+	ms, err := NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if _, err := ms.Increment("hits", 1, store.CounterASCII); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if value, _, _ := ms.Get("hits"); string(value) != "100" {
+		t.Errorf("Expected every increment applied, got %q", value)
+	}
+
+	if err := ms.Put("name", []byte("clavis")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.Increment("name", 1, store.CounterASCII); !errors.Is(err, store.ErrNotCounter) {
+		t.Errorf("Increment of a non-counter = %v, want ErrNotCounter", err)
+	}
+}
+
+func TestMemoryStore_GetView(t *testing.T) {
+	store := createTestStore(t)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	}()
+
+	if err := store.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	var viewed string
+	found, err := store.GetView("key", func(value []byte) error {
+		viewed = string(value)
+		return nil
+	})
+	if err != nil || !found || viewed != "value" {
+		t.Errorf("GetView = %v, %v viewing %q; want the stored value", found, err, viewed)
+	}
+
+	called := false
+	found, err = store.GetView("missing", func([]byte) error {
+		called = true
+		return nil
+	})
+	if err != nil || found || called {
+		t.Errorf("GetView of a missing key = %v, %v, called %v; want not found without a call", found, err, called)
+	}
+
+	failed := errors.New("callback failed")
+	if _, err := store.GetView("key", func([]byte) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+}
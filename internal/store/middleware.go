@@ -0,0 +1,123 @@
+package store
+
+import "context"
+
+// Middleware decorates a store, such as to validate, meter or publish the
+// operations made on it. The store it returns should implement Wrapper, most
+// simply by embedding Passthrough, so capabilities of the stores beneath stay
+// reachable with As.
+type Middleware func(next Store) Store
+
+// Chain wraps base in middlewares. The first middleware is the outermost: a
+// call made on the returned store goes through the middlewares in the order
+// given and then reaches base, and results travel back in reverse order.
+func Chain(base Store, middlewares ...Middleware) Store {
+	s := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		s = middlewares[i](s)
+	}
+	return s
+}
+
+// Passthrough is embedded by wrappers to pass every optional interface on to
+// the wrapped store, so a wrapper does not hide batching, contexts or
+// iterators of the stores beneath it. A wrapper only overrides the methods it
+// acts on, but it must override all of those: a wrapper checking Put alone
+// would be bypassed by BatchPut, PutContext and PutIfVersion, which
+// Passthrough sends straight to the wrapped store.
+type Passthrough struct {
+	Store
+}
+
+var (
+	_ Wrapper               = Passthrough{}
+	_ Batcher               = Passthrough{}
+	_ Expirer               = Passthrough{}
+	_ ContextWriter         = Passthrough{}
+	_ ContextReader         = Passthrough{}
+	_ ConditionalPutter     = Passthrough{}
+	_ IteratorProvider      = Passthrough{}
+	_ ForEacher             = Passthrough{}
+	_ Viewer                = Passthrough{}
+	_ RangeIteratorProvider = Passthrough{}
+)
+
+// Unwrap returns the wrapped store.
+func (p Passthrough) Unwrap() Store {
+	return p.Store
+}
+
+// PutContext writes to the wrapped store.
+func (p Passthrough) PutContext(ctx context.Context, key string, value []byte) error {
+	return PutContext(ctx, p.Store, key, value)
+}
+
+// PutIfVersion writes to the wrapped store.
+func (p Passthrough) PutIfVersion(key string, value []byte, expected uint64) error {
+	return PutIfVersion(p.Store, key, value, expected)
+}
+
+// PutIfVersionContext writes to the wrapped store.
+func (p Passthrough) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return PutIfVersionContext(ctx, p.Store, key, value, expected)
+}
+
+// DeleteContext removes from the wrapped store.
+func (p Passthrough) DeleteContext(ctx context.Context, key string) error {
+	return DeleteContext(ctx, p.Store, key)
+}
+
+// BatchPut writes to the wrapped store.
+func (p Passthrough) BatchPut(pairs map[string][]byte) error {
+	return BatchPut(p.Store, pairs)
+}
+
+// BatchPutContext writes to the wrapped store.
+func (p Passthrough) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	return BatchPutContext(ctx, p.Store, pairs)
+}
+
+// BatchDelete removes from the wrapped store.
+func (p Passthrough) BatchDelete(keys []string) error {
+	return BatchDelete(p.Store, keys)
+}
+
+// ExpireKeys removes from the wrapped store as expired.
+func (p Passthrough) ExpireKeys(keys []string) error {
+	return ExpireKeys(p.Store, keys)
+}
+
+// GetContext reads from the wrapped store.
+func (p Passthrough) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	return GetContext(ctx, p.Store, key)
+}
+
+// ScanContext scans the wrapped store.
+func (p Passthrough) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	return ScanContext(ctx, p.Store, prefix)
+}
+
+// BatchGet reads from the wrapped store.
+func (p Passthrough) BatchGet(keys []string) (map[string][]byte, error) {
+	return BatchGet(p.Store, keys)
+}
+
+// NewIterator streams from the wrapped store.
+func (p Passthrough) NewIterator(prefix string) (Iterator, error) {
+	return NewIterator(p.Store, prefix)
+}
+
+// NewRangeIterator streams from the wrapped store.
+func (p Passthrough) NewRangeIterator(r KeyRange) (Iterator, error) {
+	return NewRangeIterator(p.Store, r)
+}
+
+// ForEach visits the pairs of the wrapped store.
+func (p Passthrough) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return ForEach(p.Store, prefix, fn)
+}
+
+// GetView reads from the wrapped store.
+func (p Passthrough) GetView(key string, fn func(value []byte) error) (bool, error) {
+	return GetView(p.Store, key, fn)
+}
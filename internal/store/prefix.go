@@ -0,0 +1,39 @@
+package store
+
+// DeletePrefixBatchSize is how many keys DeletePrefix deletes at once from
+// stores without a PrefixDeleter.
+const DeletePrefixBatchSize = 1000
+
+// PrefixDeleter is implemented by stores that can delete every key under a prefix faster than key by key.
+type PrefixDeleter interface {
+	// DeletePrefix deletes every key starting with prefix, with every previous version of it, and returns how many keys were deleted.
+	// If deleted is not nil, it is called with each key before the keys are deleted.
+	DeletePrefix(prefix string, deleted func(key string)) (int64, error)
+}
+
+// DeletePrefix deletes every key of s starting with prefix and returns how
+// many were deleted, with s itself if it implements PrefixDeleter and
+// otherwise by listing the keys and deleting them with BatchDelete,
+// DeletePrefixBatchSize at a time. Like BatchPut, only s itself is checked
+// for PrefixDeleter, so wrappers that act on individual deletes are not
+// bypassed. A failure leaves the batches already deleted.
+func DeletePrefix(s Store, prefix string) (int64, error) {
+	if deleter, ok := s.(PrefixDeleter); ok {
+		return deleter.DeletePrefix(prefix, nil)
+	}
+	var deleted int64
+	r := PrefixRange(prefix)
+	for {
+		keys, err := Keys(s, r, DeletePrefixBatchSize)
+		if err != nil || len(keys) == 0 {
+			return deleted, err
+		}
+		if err := BatchDelete(s, keys); err != nil {
+			return deleted, err
+		}
+		deleted += int64(len(keys))
+		// Resume after the last key, in case the wrappers keep deleted keys
+		// listed
+		r.Start = keys[len(keys)-1] + "\x00"
+	}
+}
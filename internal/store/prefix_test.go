@@ -0,0 +1,40 @@
+package store_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestDeletePrefix_Batches(t *testing.T) {
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	pairs := map[string][]byte{"other": []byte("kept"), "users": []byte("kept")}
+	for i := range store.DeletePrefixBatchSize + 5 {
+		pairs[fmt.Sprintf("users/%05d", i)] = []byte("v")
+	}
+	if err := s.BatchPut(pairs); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := store.DeletePrefix(s, "users/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != store.DeletePrefixBatchSize+5 {
+		t.Errorf("DeletePrefix() = %d, want %d", n, store.DeletePrefixBatchSize+5)
+	}
+	left, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 2 || left["users"] == nil || left["other"] == nil {
+		t.Errorf("Expected only the keys outside the prefix left, got %d keys", len(left))
+	}
+}
@@ -0,0 +1,443 @@
+// Package quota wraps a store so that each tenant, the namespace a key
+// belongs to, is held to a maximum number of keys and bytes of values. Writes
+// that would take a tenant beyond either limit are rejected with
+// ErrQuotaExceeded and leave the store untouched.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+)
+
+// ErrQuotaExceeded is matched by every error returned for a write rejected
+// because of a quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Resources a quota limits.
+const (
+	ResourceKeys  = "keys"
+	ResourceBytes = "bytes"
+)
+
+// ExceededError describes the limit a rejected write would have broken.
+type ExceededError struct {
+	Tenant   string
+	Resource string // ResourceKeys or ResourceBytes
+	Limit    int64
+	Usage    int64 // Usage the write would have resulted in
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%v: tenant %q would use %d %s, limit is %d", ErrQuotaExceeded, e.Tenant, e.Usage, e.Resource, e.Limit)
+}
+
+// Is reports whether target is ErrQuotaExceeded.
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Limits bounds the usage of one tenant. Zero means unlimited.
+type Limits struct {
+	MaxKeys  int64 `json:"max_keys,omitempty"`
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+}
+
+// Usage is what a tenant currently stores.
+type Usage struct {
+	Keys  int64
+	Bytes int64 // Total size of the values
+}
+
+// Config tells which tenant a key belongs to and what its limits are.
+type Config struct {
+	// Tenant returns the tenant of key and whether it has one;
+	// settings.NamespaceOf if nil. Keys without a tenant are not limited.
+	Tenant func(key string) (string, bool)
+	// Limits returns the limits of tenant, such as the MaxKeys and MaxBytes
+	// of its namespace settings. No tenant is limited if nil.
+	Limits func(tenant string) Limits
+	// WarnAt is the fraction of a limit above which successful writes carry
+	// a warnings.CodeNearQuota warning; 0.9 if zero, never if negative.
+	WarnAt float64
+	// RecountAfter is how long the usage of a tenant is trusted before it is
+	// counted again from the store, to catch up with writes that bypassed the
+	// wrapper such as expirations; never if zero.
+	RecountAfter time.Duration
+	// Events receives a TypeQuotaExceeded event for every rejected write.
+	Events *events.Bus
+}
+
+const (
+	// DefaultWarnAt is the WarnAt of a Config that leaves it zero.
+	DefaultWarnAt = 0.9
+	// DefaultRecountAfter bounds how long writes that bypass the wrapper can
+	// skew the usage of a tenant.
+	DefaultRecountAfter = 5 * time.Minute
+)
+
+// LimitsFromSettings returns a Config.Limits reading the MaxKeys and MaxBytes
+// of the namespace settings managed by m. Namespaces whose settings cannot be
+// read are not limited.
+func LimitsFromSettings(m *settings.Manager) func(tenant string) Limits {
+	return func(tenant string) Limits {
+		current, err := m.Get(tenant)
+		if err != nil {
+			return Limits{}
+		}
+		return Limits{MaxKeys: current.MaxKeys, MaxBytes: current.MaxBytes}
+	}
+}
+
+// Store enforces quotas on writes before passing them on.
+//
+// The usage of a tenant is counted from the store the first time it is
+// limited and kept up to date by the writes made through the Store. Writes to
+// a tenant are serialized so concurrent writes cannot overshoot its limits
+// together.
+type Store struct {
+	store.Passthrough
+	config Config
+
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// tenant holds the usage of one tenant. Its lock is held for the whole of a
+// write so the usage checked is the usage written against.
+type tenant struct {
+	mu        sync.Mutex
+	loaded    bool
+	countedAt time.Time
+	usage     Usage
+}
+
+// New wraps base.
+func New(base store.Store, config Config) *Store {
+	if config.Tenant == nil {
+		config.Tenant = settings.NamespaceOf
+	}
+	if config.WarnAt == 0 {
+		config.WarnAt = DefaultWarnAt
+	}
+	return &Store{Passthrough: store.Passthrough{Store: base}, config: config, tenants: make(map[string]*tenant)}
+}
+
+var (
+	_ store.Store             = (*Store)(nil)
+	_ store.Wrapper           = (*Store)(nil)
+	_ store.Batcher           = (*Store)(nil)
+	_ store.Expirer           = (*Store)(nil)
+	_ store.ContextWriter     = (*Store)(nil)
+	_ store.ContextReader     = (*Store)(nil)
+	_ store.ConditionalPutter = (*Store)(nil)
+)
+
+// Usage returns what tenant stores, counting it from the store if it was not
+// counted yet.
+func (s *Store) Usage(name string) (Usage, error) {
+	t := s.tenant(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := s.load(name, t); err != nil {
+		return Usage{}, err
+	}
+	return t.usage, nil
+}
+
+// Recount discards the usage counted for tenant, so it is counted again from
+// the store when next needed.
+func (s *Store) Recount(name string) {
+	t := s.tenant(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loaded = false
+}
+
+// RecountKeys discards the usage counted for the tenants of keys, such as
+// keys deleted without going through the Store.
+func (s *Store) RecountKeys(keys []string) {
+	recounted := make(map[string]bool)
+	for _, key := range keys {
+		if name, ok := s.config.Tenant(key); ok && !recounted[name] {
+			recounted[name] = true
+			s.Recount(name)
+		}
+	}
+}
+
+func (s *Store) tenant(name string) *tenant {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tenants[name]
+	if !ok {
+		t = &tenant{}
+		s.tenants[name] = t
+	}
+	return t
+}
+
+func (s *Store) limits(name string) Limits {
+	if s.config.Limits == nil {
+		return Limits{}
+	}
+	return s.config.Limits(name)
+}
+
+// load counts the usage of a tenant from the store unless it is already
+// counted and fresh. The tenant must be locked.
+func (s *Store) load(name string, t *tenant) error {
+	if t.loaded && (s.config.RecountAfter <= 0 || time.Since(t.countedAt) < s.config.RecountAfter) {
+		return nil
+	}
+	it, err := store.NewIterator(s.Store, name+settings.NamespaceSeparator)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = it.Close() }()
+	var usage Usage
+	for it.Next() {
+		if owner, ok := s.config.Tenant(it.Key()); !ok || owner != name {
+			continue
+		}
+		usage.Keys++
+		usage.Bytes += int64(len(it.Value()))
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	t.usage, t.loaded, t.countedAt = usage, true, time.Now()
+	return nil
+}
+
+// change is the effect of a write on the usage of one tenant.
+type change struct {
+	name  string
+	t     *tenant
+	delta Usage
+}
+
+// write applies a write touching the keys of keys, whose new values are
+// given by next (nil for removals), through apply. It locks the tenants of
+// the keys, rejects the write if it would take any of them beyond a limit,
+// and updates their usage once apply succeeds.
+func (s *Store) write(ctx context.Context, keys []string, next func(key string) ([]byte, bool), apply func() error) error {
+	byTenant := make(map[string][]string)
+	for _, key := range keys {
+		if name, ok := s.config.Tenant(key); ok {
+			byTenant[name] = append(byTenant[name], key)
+		}
+	}
+	names := make([]string, 0, len(byTenant))
+	for name := range byTenant {
+		names = append(names, name)
+	}
+	// Lock in a fixed order so batches spanning tenants cannot deadlock
+	slices.Sort(names)
+
+	var changes []change
+	for _, name := range names {
+		t := s.tenant(name)
+		limits := s.limits(name)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if !t.loaded && limits == (Limits{}) {
+			// Nothing to enforce, and nothing counted to keep up to date
+			continue
+		}
+		if err := s.load(name, t); err != nil {
+			return err
+		}
+		delta, err := s.delta(byTenant[name], next)
+		if err != nil {
+			return err
+		}
+		if exceeded := exceeds(name, limits, t.usage, delta); exceeded != nil {
+			s.publishExceeded(byTenant[name][0], exceeded)
+			return exceeded
+		}
+		changes = append(changes, change{name: name, t: t, delta: delta})
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		c.t.usage.Keys += c.delta.Keys
+		c.t.usage.Bytes += c.delta.Bytes
+		s.warn(ctx, c.name, c.t.usage)
+	}
+	return nil
+}
+
+// delta computes how writing keys changes the usage of their tenant.
+func (s *Store) delta(keys []string, next func(key string) ([]byte, bool)) (Usage, error) {
+	current, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return Usage{}, err
+	}
+	var delta Usage
+	for _, key := range keys {
+		old, existed := current[key]
+		value, stored := next(key)
+		switch {
+		case stored && !existed:
+			delta.Keys++
+		case !stored && existed:
+			delta.Keys--
+		}
+		if existed {
+			delta.Bytes -= int64(len(old))
+		}
+		if stored {
+			delta.Bytes += int64(len(value))
+		}
+	}
+	return delta, nil
+}
+
+// exceeds rejects a change that grows usage beyond limits. Changes that do
+// not grow a resource are allowed even over its limit, so a tenant whose
+// limit was lowered can still shrink back under it.
+func exceeds(name string, limits Limits, usage, delta Usage) *ExceededError {
+	if limits.MaxKeys > 0 && delta.Keys > 0 && usage.Keys+delta.Keys > limits.MaxKeys {
+		return &ExceededError{Tenant: name, Resource: ResourceKeys, Limit: limits.MaxKeys, Usage: usage.Keys + delta.Keys}
+	}
+	if limits.MaxBytes > 0 && delta.Bytes > 0 && usage.Bytes+delta.Bytes > limits.MaxBytes {
+		return &ExceededError{Tenant: name, Resource: ResourceBytes, Limit: limits.MaxBytes, Usage: usage.Bytes + delta.Bytes}
+	}
+	return nil
+}
+
+// publishExceeded reports a write to key rejected by err on the event bus.
+func (s *Store) publishExceeded(key string, err *ExceededError) {
+	if s.config.Events == nil {
+		return
+	}
+	s.config.Events.Publish(events.Event{Type: events.TypeQuotaExceeded, Key: key, Attributes: map[string]string{
+		"namespace": err.Tenant,
+		"resource":  err.Resource,
+		"usage":     strconv.FormatInt(err.Usage, 10),
+		"limit":     strconv.FormatInt(err.Limit, 10),
+	}})
+}
+
+// warn reports a tenant whose usage is close to one of its limits.
+func (s *Store) warn(ctx context.Context, name string, usage Usage) {
+	if s.config.WarnAt < 0 {
+		return
+	}
+	limits := s.limits(name)
+	for _, resource := range []struct {
+		name         string
+		usage, limit int64
+	}{
+		{ResourceKeys, usage.Keys, limits.MaxKeys},
+		{ResourceBytes, usage.Bytes, limits.MaxBytes},
+	} {
+		if resource.limit <= 0 || float64(resource.usage) < s.config.WarnAt*float64(resource.limit) {
+			continue
+		}
+		warnings.Add(ctx, warnings.Warning{
+			Code:    warnings.CodeNearQuota,
+			Message: fmt.Sprintf("namespace %q uses %d of its %d %s", name, resource.usage, resource.limit, resource.name),
+			Metadata: map[string]string{
+				"namespace": name,
+				"resource":  resource.name,
+				"usage":     strconv.FormatInt(resource.usage, 10),
+				"limit":     strconv.FormatInt(resource.limit, 10),
+			},
+		})
+	}
+}
+
+// stores returns a next function for writes storing pairs.
+func stores(pairs map[string][]byte) func(string) ([]byte, bool) {
+	return func(key string) ([]byte, bool) {
+		value, ok := pairs[key]
+		return value, ok
+	}
+}
+
+// removes is the next function of writes removing keys.
+func removes(string) ([]byte, bool) {
+	return nil, false
+}
+
+// Put stores the value if its tenant stays within quota.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext stores the value on behalf of the request in ctx if its tenant
+// stays within quota.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return s.write(ctx, []string{key}, stores(map[string][]byte{key: value}), func() error {
+		return store.PutContext(ctx, s.Store, key, value)
+	})
+}
+
+// PutIfVersion conditionally stores the value if its tenant stays within quota.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext conditionally stores the value on behalf of the request
+// in ctx if its tenant stays within quota.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return s.write(ctx, []string{key}, stores(map[string][]byte{key: value}), func() error {
+		return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+	})
+}
+
+// BatchPut stores all pairs if every tenant they belong to stays within quota.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext stores all pairs on behalf of the request in ctx if every
+// tenant they belong to stays within quota; otherwise none are stored.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	return s.write(ctx, keys, stores(pairs), func() error {
+		return store.BatchPutContext(ctx, s.Store, pairs)
+	})
+}
+
+// Delete removes the key, releasing its share of the quota.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext removes the key on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	return s.write(ctx, []string{key}, removes, func() error {
+		return store.DeleteContext(ctx, s.Store, key)
+	})
+}
+
+// BatchDelete removes the keys.
+func (s *Store) BatchDelete(keys []string) error {
+	return s.write(context.Background(), keys, removes, func() error {
+		return store.BatchDelete(s.Store, keys)
+	})
+}
+
+// ExpireKeys removes the keys from the wrapped store as expired.
+func (s *Store) ExpireKeys(keys []string) error {
+	return s.write(context.Background(), keys, removes, func() error {
+		return store.ExpireKeys(s.Store, keys)
+	})
+}
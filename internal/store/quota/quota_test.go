@@ -0,0 +1,162 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+)
+
+func newStore(t *testing.T, limits map[string]Limits) (*Store, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(base, Config{Limits: func(tenant string) Limits { return limits[tenant] }}), base
+}
+
+func TestStore_KeyLimit(t *testing.T) {
+	s, base := newStore(t, map[string]Limits{"acme": {MaxKeys: 2}})
+	// Keys written before the wrapper existed count too
+	if err := base.Put("acme/a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("acme/b", []byte("2")); err != nil {
+		t.Fatalf("Put within quota failed: %v", err)
+	}
+	if err := s.Put("acme/b", []byte("overwritten")); err != nil {
+		t.Errorf("Expected overwriting an existing key not to count as a new key, got %v", err)
+	}
+	err := s.Put("acme/c", []byte("3"))
+	var exceeded *ExceededError
+	if !errors.Is(err, ErrQuotaExceeded) || !errors.As(err, &exceeded) || exceeded.Resource != ResourceKeys {
+		t.Fatalf("Expected the key quota to be exceeded, got %v", err)
+	}
+	if _, found, _ := base.Get("acme/c"); found {
+		t.Error("Expected the rejected write not to be stored")
+	}
+	if err := s.Put("other/c", []byte("3")); err != nil {
+		t.Errorf("Expected other tenants to be unaffected, got %v", err)
+	}
+
+	if err := s.Delete("acme/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("acme/c", []byte("3")); err != nil {
+		t.Errorf("Expected a delete to free quota, got %v", err)
+	}
+}
+
+func TestStore_ByteLimit(t *testing.T) {
+	s, _ := newStore(t, map[string]Limits{"acme": {MaxBytes: 10}})
+
+	if err := s.Put("acme/a", []byte("12345678")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("acme/b", []byte("123")); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected the byte quota to be exceeded, got %v", err)
+	}
+	if err := s.Put("acme/a", []byte("1234567")); err != nil {
+		t.Errorf("Expected a shrinking write to be allowed, got %v", err)
+	}
+	usage, err := s.Usage("acme")
+	if err != nil || usage != (Usage{Keys: 1, Bytes: 7}) {
+		t.Errorf("Expected 1 key of 7 bytes, got %+v %v", usage, err)
+	}
+}
+
+func TestStore_Batches(t *testing.T) {
+	s, base := newStore(t, map[string]Limits{"acme": {MaxKeys: 2}, "globex": {MaxKeys: 1}})
+
+	err := store.BatchPut(s, map[string][]byte{"acme/a": nil, "acme/b": nil, "globex/a": nil, "globex/b": nil})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected the batch to exceed the quota of globex, got %v", err)
+	}
+	if pairs, _ := base.Scan(""); len(pairs) != 0 {
+		t.Errorf("Expected no pair of a rejected batch to be stored, got %v", pairs)
+	}
+
+	if err := store.BatchPut(s, map[string][]byte{"acme/a": nil, "acme/b": nil, "globex/a": nil}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ExpireKeys(s, []string{"acme/a", "globex/a"}); err != nil {
+		t.Fatal(err)
+	}
+	if usage, _ := s.Usage("acme"); usage.Keys != 1 {
+		t.Errorf("Expected expired keys to free quota, got %+v", usage)
+	}
+}
+
+func TestStore_ExceededEvents(t *testing.T) {
+	bus := events.NewBus(0)
+	defer bus.Close()
+	exceeded := make(chan events.Event, 4)
+	defer bus.Subscribe(func(e events.Event) { exceeded <- e }, events.TypeQuotaExceeded)()
+
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(base, Config{Limits: func(string) Limits { return Limits{MaxKeys: 1} }, Events: bus})
+	if err := s.Put("acme/a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("acme/b", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected the key quota to be exceeded, got %v", err)
+	}
+	select {
+	case e := <-exceeded:
+		if e.Key != "acme/b" || e.Attributes["namespace"] != "acme" || e.Attributes["resource"] != ResourceKeys || e.Attributes["usage"] != "2" || e.Attributes["limit"] != "1" {
+			t.Errorf("Unexpected event %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a quota_exceeded event")
+	}
+}
+
+func TestStore_RecountKeys(t *testing.T) {
+	s, base := newStore(t, map[string]Limits{"acme": {MaxKeys: 2}})
+	if err := store.BatchPut(s, map[string][]byte{"acme/a": nil, "acme/b": nil}); err != nil {
+		t.Fatal(err)
+	}
+	// Keys deleted behind the wrapper's back free quota once recounted
+	if err := base.Delete("acme/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("acme/c", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected the stale usage to exceed the quota, got %v", err)
+	}
+	s.RecountKeys([]string{"acme/a"})
+	if err := s.Put("acme/c", nil); err != nil {
+		t.Errorf("Expected the recounted usage to allow the write, got %v", err)
+	}
+}
+
+func TestStore_NearQuotaWarning(t *testing.T) {
+	s, _ := newStore(t, map[string]Limits{"acme": {MaxKeys: 10}})
+	ctx, collector := warnings.NewContext(context.Background())
+
+	for _, key := range []string{"acme/1", "acme/2", "acme/3", "acme/4", "acme/5", "acme/6", "acme/7", "acme/8"} {
+		if err := s.PutContext(ctx, key, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := collector.Warnings(); len(got) != 0 {
+		t.Fatalf("Expected no warning below 90%%, got %v", got)
+	}
+	if err := s.PutContext(ctx, "acme/9", nil); err != nil {
+		t.Fatal(err)
+	}
+	got := collector.Warnings()
+	if len(got) != 1 || got[0].Code != warnings.CodeNearQuota || !strings.Contains(got[0].Message, "9 of its 10 keys") {
+		t.Errorf("Expected a near-quota warning, got %v", got)
+	}
+}
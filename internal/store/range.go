@@ -0,0 +1,171 @@
+package store
+
+import "sort"
+
+// KeyRange selects the keys from Start, inclusive, up to End, exclusive. An
+// empty Start begins with the first key of the store and an empty End runs
+// to the last one.
+type KeyRange struct {
+	Start   string
+	End     string
+	Reverse bool // Visit the keys in descending order
+
+	// KeysOnly leaves the values out, so stores can visit the keys without
+	// reading any value; iterators then return nil values
+	KeysOnly bool
+}
+
+// PrefixRange returns the range of the keys starting with prefix.
+func PrefixRange(prefix string) KeyRange {
+	return KeyRange{Start: prefix, End: prefixEnd(prefix)}
+}
+
+// prefixEnd returns the first key after every key starting with prefix, or
+// an empty string if there is none.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+// Contains reports whether key is in the range.
+func (r KeyRange) Contains(key string) bool {
+	return key >= r.Start && (r.End == "" || key < r.End)
+}
+
+// RangeIteratorProvider is implemented by stores that can seek to the start
+// of a key range.
+type RangeIteratorProvider interface {
+	// NewRangeIterator returns an iterator over the pairs whose keys are in r, in the order r selects, positioned before the first pair.
+	NewRangeIterator(r KeyRange) (Iterator, error)
+}
+
+// KeyValue is a pair returned by Range.
+type KeyValue struct {
+	Key   string
+	Value []byte
+}
+
+// NewRangeIterator returns an iterator over the pairs in s whose keys are in
+// r. Stores implementing RangeIteratorProvider seek to the start of the
+// range; for any other store every pair is walked, and buffered for a
+// reverse range.
+func NewRangeIterator(s Store, r KeyRange) (Iterator, error) {
+	if provider, ok := s.(RangeIteratorProvider); ok {
+		return provider.NewRangeIterator(r)
+	}
+
+	it, err := NewIterator(s, "")
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var pairs []KeyValue
+	for it.Next() {
+		if !r.Contains(it.Key()) {
+			continue
+		}
+		pair := KeyValue{Key: it.Key()}
+		if !r.KeysOnly {
+			pair.Value = it.Value()
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return NewPairIterator(pairs, r.Reverse), nil
+}
+
+// Range returns up to limit pairs of s whose keys are in r, in the order r
+// selects. A zero limit returns every pair in the range.
+func Range(s Store, r KeyRange, limit int) (pairs []KeyValue, err error) {
+	it, err := NewRangeIterator(s, r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := it.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for (limit <= 0 || len(pairs) < limit) && it.Next() {
+		pairs = append(pairs, KeyValue{Key: it.Key(), Value: it.Value()})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// Keys returns up to limit keys of s in r, in the order r selects, without
+// reading their values. A zero limit returns every key in the range.
+func Keys(s Store, r KeyRange, limit int) ([]string, error) {
+	r.KeysOnly = true
+	pairs, err := Range(s, r, limit)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return keys, nil
+}
+
+// PairIterator iterates over an in-memory list of pairs in key order.
+type PairIterator struct {
+	pairs []KeyValue
+	pos   int
+}
+
+// NewPairIterator creates an iterator over the given pairs, sorted by key in
+// descending order if reverse.
+func NewPairIterator(pairs []KeyValue, reverse bool) *PairIterator {
+	sort.Slice(pairs, func(i, j int) bool {
+		if reverse {
+			return pairs[i].Key > pairs[j].Key
+		}
+		return pairs[i].Key < pairs[j].Key
+	})
+	return &PairIterator{pairs: pairs, pos: -1}
+}
+
+// Next advances to the next pair
+func (it *PairIterator) Next() bool {
+	if it.pos+1 >= len(it.pairs) {
+		it.pos = len(it.pairs)
+		return false
+	}
+	it.pos++
+	return true
+}
+
+// Key returns the current key
+func (it *PairIterator) Key() string {
+	return it.pairs[it.pos].Key
+}
+
+// Value returns the current value
+func (it *PairIterator) Value() []byte {
+	return it.pairs[it.pos].Value
+}
+
+// Err always returns nil since the pairs are already in memory
+func (it *PairIterator) Err() error {
+	return nil
+}
+
+// Close releases the buffered pairs
+func (it *PairIterator) Close() error {
+	it.pairs = nil
+	return nil
+}
+
+var _ Iterator = (*PairIterator)(nil)
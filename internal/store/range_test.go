@@ -0,0 +1,110 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func rangeKeys(t *testing.T, s store.Store, r store.KeyRange, limit int) []string {
+	t.Helper()
+	pairs, err := store.Range(s, r, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if string(pair.Value) != "v"+pair.Key {
+			t.Errorf("Value of %s = %q, want %q", pair.Key, pair.Value, "v"+pair.Key)
+		}
+		keys = append(keys, pair.Key)
+	}
+	return keys
+}
+
+func TestRange_FallsBackToIterator(t *testing.T) {
+	s := &scanOnlyStore{pairs: map[string][]byte{"a": []byte("va"), "b": []byte("vb"), "c": []byte("vc"), "d": []byte("vd")}}
+
+	tests := []struct {
+		name  string
+		r     store.KeyRange
+		limit int
+		want  []string
+	}{
+		{"Everything", store.KeyRange{}, 0, []string{"a", "b", "c", "d"}},
+		{"StartInclusiveEndExclusive", store.KeyRange{Start: "b", End: "d"}, 0, []string{"b", "c"}},
+		{"AfterKey", store.KeyRange{Start: "b\x00"}, 2, []string{"c", "d"}},
+		{"Reverse", store.KeyRange{End: "d", Reverse: true}, 2, []string{"c", "b"}},
+		{"Empty", store.KeyRange{Start: "x"}, 0, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rangeKeys(t, s, tt.r, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixRange(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   store.KeyRange
+	}{
+		{"", store.KeyRange{}},
+		{"user:", store.KeyRange{Start: "user:", End: "user;"}},
+		{"a\xff", store.KeyRange{Start: "a\xff", End: "b"}},
+		{"\xff\xff", store.KeyRange{Start: "\xff\xff"}},
+	}
+	for _, tt := range tests {
+		if got := store.PrefixRange(tt.prefix); got != tt.want {
+			t.Errorf("PrefixRange(%q) = %+v, want %+v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	s := &scanOnlyStore{pairs: map[string][]byte{"a/1": []byte("1"), "a/2": []byte("2"), "b": []byte("3")}}
+
+	keys, err := store.Keys(s, store.PrefixRange("a/"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a/1", "a/2"}) {
+		t.Errorf("Keys = %v, want a/1 and a/2", keys)
+	}
+
+	pairs, err := store.Range(s, store.KeyRange{KeysOnly: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pair := range pairs {
+		if pair.Value != nil {
+			t.Errorf("Expected no value for %s in a keys-only range, got %q", pair.Key, pair.Value)
+		}
+	}
+}
+
+func TestExistsAndCount(t *testing.T) {
+	s := &scanOnlyStore{pairs: map[string][]byte{"a": []byte("1"), "a/1": []byte("2"), "a/2": []byte("3"), "b": []byte("4")}}
+
+	for key, want := range map[string]bool{"a": true, "a/1": true, "a/": false, "c": false} {
+		if found, err := store.Exists(s, key); err != nil || found != want {
+			t.Errorf("Exists(%q) = %v, %v; want %v", key, found, err, want)
+		}
+	}
+	for prefix, want := range map[string]int64{"": 4, "a": 3, "a/": 2, "c": 0} {
+		if n, err := store.Count(context.Background(), s, prefix); err != nil || n != want {
+			t.Errorf("Count(%q) = %d, %v; want %d", prefix, n, err, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := store.Count(ctx, s, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("Count with a canceled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package readonly wraps a store so that writes can be turned away at
+// runtime, such as on a replica or during a maintenance window, while reads
+// carry on.
+package readonly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// ErrReadOnly is matched by every error returned for a write rejected
+// because the store is read-only.
+var ErrReadOnly = errors.New("store is read-only")
+
+// Mode tells whether a Store accepts writes.
+type Mode struct {
+	ReadOnly bool
+	Reason   string    // Why the store is read-only, for the rejected callers
+	Since    time.Time // When the mode was last changed
+}
+
+// Store rejects writes with ErrReadOnly while it is read-only.
+type Store struct {
+	store.Passthrough
+
+	// Writes hold mu for reading, so switching modes waits for the writes in
+	// progress.
+	mu   sync.RWMutex
+	mode Mode
+}
+
+// New wraps base, initially in mode.
+func New(base store.Store, mode Mode) *Store {
+	if mode.Since.IsZero() {
+		mode.Since = time.Now()
+	}
+	return &Store{Passthrough: store.Passthrough{Store: base}, mode: mode}
+}
+
+var (
+	_ store.Store             = (*Store)(nil)
+	_ store.Wrapper           = (*Store)(nil)
+	_ store.Batcher           = (*Store)(nil)
+	_ store.Expirer           = (*Store)(nil)
+	_ store.ContextWriter     = (*Store)(nil)
+	_ store.ContextReader     = (*Store)(nil)
+	_ store.ConditionalPutter = (*Store)(nil)
+)
+
+// Mode returns the current mode.
+func (s *Store) Mode() Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mode
+}
+
+// SetReadOnly switches the store to read-only, giving reason to the rejected
+// callers, or back to accepting writes. Once it returns read-only, no write
+// is in progress anymore. Returns the new mode.
+func (s *Store) SetReadOnly(readOnly bool, reason string) Mode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !readOnly {
+		reason = ""
+	}
+	if readOnly != s.mode.ReadOnly || reason != s.mode.Reason {
+		s.mode = Mode{ReadOnly: readOnly, Reason: reason, Since: time.Now()}
+	}
+	return s.mode
+}
+
+// write runs apply unless the store is read-only.
+func (s *Store) write(apply func() error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.mode.ReadOnly {
+		if s.mode.Reason == "" {
+			return ErrReadOnly
+		}
+		return fmt.Errorf("%w: %s", ErrReadOnly, s.mode.Reason)
+	}
+	return apply()
+}
+
+// Guard runs apply, a write made around the store such as straight on the
+// backend, unless the store is read-only. Modes are not switched until apply
+// returns.
+func (s *Store) Guard(apply func() error) error {
+	return s.write(apply)
+}
+
+// Put stores the value unless the store is read-only.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext stores the value on behalf of the request in ctx unless the
+// store is read-only.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return s.write(func() error { return store.PutContext(ctx, s.Store, key, value) })
+}
+
+// PutIfVersion conditionally stores the value unless the store is read-only.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext conditionally stores the value on behalf of the request
+// in ctx unless the store is read-only.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return s.write(func() error { return store.PutIfVersionContext(ctx, s.Store, key, value, expected) })
+}
+
+// BatchPut stores all pairs unless the store is read-only.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext stores all pairs on behalf of the request in ctx unless the
+// store is read-only.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	return s.write(func() error { return store.BatchPutContext(ctx, s.Store, pairs) })
+}
+
+// Delete removes the key unless the store is read-only.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext removes the key on behalf of the request in ctx unless the
+// store is read-only.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	return s.write(func() error { return store.DeleteContext(ctx, s.Store, key) })
+}
+
+// BatchDelete removes the keys unless the store is read-only.
+func (s *Store) BatchDelete(keys []string) error {
+	return s.write(func() error { return store.BatchDelete(s.Store, keys) })
+}
+
+// ExpireKeys removes the keys from the wrapped store as expired unless the
+// store is read-only.
+func (s *Store) ExpireKeys(keys []string) error {
+	return s.write(func() error { return store.ExpireKeys(s.Store, keys) })
+}
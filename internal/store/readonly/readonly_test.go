@@ -0,0 +1,53 @@
+package readonly
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func TestStore_SetReadOnly(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(base, Mode{})
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatalf("Put while writable failed: %v", err)
+	}
+
+	mode := s.SetReadOnly(true, "maintenance")
+	if !mode.ReadOnly || mode.Reason != "maintenance" || mode.Since.IsZero() {
+		t.Fatalf("SetReadOnly = %+v, want read-only for maintenance", mode)
+	}
+	writes := map[string]func() error{
+		"Put":          func() error { return s.Put("b", []byte("2")) },
+		"Delete":       func() error { return s.Delete("a") },
+		"BatchPut":     func() error { return store.BatchPut(s, map[string][]byte{"b": []byte("2")}) },
+		"BatchDelete":  func() error { return store.BatchDelete(s, []string{"a"}) },
+		"ExpireKeys":   func() error { return store.ExpireKeys(s, []string{"a"}) },
+		"PutIfVersion": func() error { return store.PutIfVersion(s, "b", []byte("2"), 0) },
+	}
+	for name, write := range writes {
+		err := write()
+		if !errors.Is(err, ErrReadOnly) || !strings.Contains(err.Error(), "maintenance") {
+			t.Errorf("%s while read-only = %v, want ErrReadOnly with the reason", name, err)
+		}
+	}
+	if value, found, err := s.Get("a"); err != nil || !found || string(value) != "1" {
+		t.Errorf("Get while read-only = %q, %v, %v; want the stored value", value, found, err)
+	}
+	if _, found, _ := base.Get("b"); found {
+		t.Error("Expected rejected writes not to be stored")
+	}
+
+	if mode := s.SetReadOnly(false, "ignored"); mode.ReadOnly || mode.Reason != "" {
+		t.Fatalf("SetReadOnly(false) = %+v, want writable without a reason", mode)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Errorf("Delete after switching back failed: %v", err)
+	}
+}
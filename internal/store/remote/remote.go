@@ -0,0 +1,139 @@
+// Package remote serves the store interface from a remote Clavis server, so
+// a server can be used wherever a local store is, such as to mirror writes
+// to it. Keys are read and written through the server's client API, so they
+// go through its validation, quotas and wrappers like those of any client.
+package remote
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/pkg/client"
+)
+
+// Store reads and writes the keys of a remote server.
+type Store struct {
+	store.OperationCounters
+	client    *client.Client
+	namespace string
+	timeout   time.Duration
+}
+
+// New connects to the server at addr. The connection is established lazily
+// by the first operation.
+func New(addr string, config client.Config) (*Store, error) {
+	c, err := client.New(addr, config)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{client: c, namespace: config.Namespace, timeout: config.Timeout}, nil
+}
+
+// withTimeout bounds calls made with the generated client as the typed
+// methods of the client are.
+func (s *Store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || s.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+var (
+	_ store.Store         = (*Store)(nil)
+	_ store.Batcher       = (*Store)(nil)
+	_ store.ContextReader = (*Store)(nil)
+	_ store.ContextWriter = (*Store)(nil)
+)
+
+// Get reads key from the server.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	s.CountGets(1)
+	return s.client.Get(ctx, key)
+}
+
+// Put writes key to the server, ignoring the warnings it reports.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	s.CountPuts(1)
+	_, err := s.client.Put(ctx, key, value)
+	return err
+}
+
+// Delete removes key from the server.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	s.CountDeletes(1)
+	_, err := s.client.Delete(ctx, key)
+	return err
+}
+
+// Scan reads every pair under prefix from the server, a page at a time.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	s.CountScan()
+	return s.client.Scan(ctx, prefix, 0)
+}
+
+// BatchPut writes pairs to the server in a single call.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	s.CountPuts(len(pairs))
+	req := &proto.BatchPutRequest{Items: make([]*proto.KeyValue, 0, len(pairs)), Namespace: s.namespace}
+	for key, value := range pairs {
+		req.Items = append(req.Items, &proto.KeyValue{Key: key, Value: value})
+	}
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	_, err := s.client.Clavis().BatchPut(ctx, req)
+	return err
+}
+
+// BatchGet reads keys from the server in a single call.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	s.CountGets(len(keys))
+	values, _, err := s.client.GetMany(context.Background(), keys)
+	return values, err
+}
+
+// BatchDelete removes keys from the server in a single call.
+func (s *Store) BatchDelete(keys []string) error {
+	s.CountDeletes(len(keys))
+	ctx, cancel := s.withTimeout(context.Background())
+	defer cancel()
+	_, err := s.client.Clavis().BatchDelete(ctx, &proto.BatchDeleteRequest{Keys: keys, Namespace: s.namespace})
+	return err
+}
+
+// Stats reports the operations made on the server through s; the size of
+// the server's data is reported by the server itself.
+func (s *Store) Stats() (store.Stats, error) {
+	return store.Stats{Operations: s.OperationCounts()}, nil
+}
+
+// Close closes the connection to the server.
+func (s *Store) Close() error {
+	return s.client.Close()
+}
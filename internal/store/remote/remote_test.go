@@ -0,0 +1,85 @@
+package remote
+
+import (
+	"net"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	grpcserver "github.com/William-Fernandes252/clavis/internal/server/grpc"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"github.com/William-Fernandes252/clavis/pkg/client"
+	"google.golang.org/grpc"
+)
+
+// startServer serves an empty memory store and returns its address.
+func startServer(t *testing.T) string {
+	t.Helper()
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	server, err := grpcserver.New(s, &grpcserver.GRPCServerConfig{Port: listener.Addr().String()}, grpcServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto.RegisterClavisServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+	return listener.Addr().String()
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(startServer(t), client.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"b": []byte("2"), "c": []byte("3"), "d": []byte("4")}); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, err := s.Get("a"); err != nil || !found || string(value) != "1" {
+		t.Errorf("Get(a) = %q, %v, %v; want 1", value, found, err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchDelete([]string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := s.Get("b"); err != nil || found {
+		t.Errorf("Get(b) = %v, %v after Delete; want it gone", found, err)
+	}
+
+	values, err := s.BatchGet([]string{"a", "c", "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || string(values["a"]) != "1" || string(values["d"]) != "4" {
+		t.Errorf("BatchGet() = %q, want a and d", values)
+	}
+	pairs, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || string(pairs["d"]) != "4" {
+		t.Errorf("Scan() = %q, want a and d", pairs)
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (store.OperationCounts{Gets: 5, Puts: 4, Deletes: 2, Scans: 1}); stats.Operations != want {
+		t.Errorf("Stats().Operations = %+v, want %+v", stats.Operations, want)
+	}
+}
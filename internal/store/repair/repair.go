@@ -0,0 +1,149 @@
+// Package repair composes a fast front store holding copies of values with an
+// authoritative source store, verifying every copy on read and repairing
+// copies that no longer match what the source wrote.
+package repair
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"log/slog"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// Metric names updated by the repairing store
+const (
+	MetricRepairs = "store_read_repairs_total"
+	MetricHits    = "store_front_hits_total"
+	MetricMisses  = "store_front_misses_total"
+)
+
+const checksumSize = 4
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Config controls how discrepancies are reported.
+type Config struct {
+	// Metrics receives the repair, hit and miss counters. Defaults to metrics.Default.
+	Metrics *metrics.Registry
+	// LogDiscrepancies logs every repaired key for later investigation.
+	LogDiscrepancies bool
+	// Logger receives repair records. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Store reads through a front store (a cache or a faster tier) to an
+// authoritative source. Copies in the front store carry a CRC-32C checksum of
+// the source value; when a copy fails verification it is invalidated,
+// re-fetched from the source and a repair is recorded.
+type Store struct {
+	store.Store // authoritative source of truth
+	front       store.Store
+	config      Config
+}
+
+// New composes front and source into a repairing store.
+func New(front, source store.Store, config Config) *Store {
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+	return &Store{Store: source, front: front, config: config}
+}
+
+// Get serves verified copies from the front store, falling back to the source.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	raw, found, err := s.front.Get(key)
+	if err == nil && found {
+		if value, ok := open(raw); ok {
+			s.config.Metrics.Counter(MetricHits).Inc()
+			return value, true, nil
+		}
+		s.repair(key, raw)
+	} else {
+		s.config.Metrics.Counter(MetricMisses).Inc()
+	}
+
+	value, found, err := s.Store.Get(key)
+	if err != nil || !found {
+		return value, found, err
+	}
+	// A failed refill only costs a future miss
+	_ = s.front.Put(key, seal(value))
+	return value, true, nil
+}
+
+// repair drops a copy that failed verification.
+func (s *Store) repair(key string, raw []byte) {
+	s.config.Metrics.Counter(MetricRepairs).Inc()
+	if s.config.LogDiscrepancies {
+		logging.Or(s.config.Logger).Warn("read repair: checksum mismatch, re-fetching from source", "key", key, "front_bytes", len(raw))
+	}
+	if err := s.front.Delete(key); err != nil {
+		logging.Or(s.config.Logger).Error("read repair: failed to invalidate key", "key", key, "error", err)
+	}
+}
+
+// Put writes to the source, then refreshes the front copy.
+func (s *Store) Put(key string, value []byte) error {
+	if err := s.Store.Put(key, value); err != nil {
+		return err
+	}
+	if err := s.front.Put(key, seal(value)); err != nil {
+		// Never leave a stale copy behind
+		_ = s.front.Delete(key)
+	}
+	return nil
+}
+
+// Delete removes the key from the source and the front store.
+func (s *Store) Delete(key string) error {
+	if err := s.Store.Delete(key); err != nil {
+		return err
+	}
+	return s.front.Delete(key)
+}
+
+// Close closes both stores.
+func (s *Store) Close() error {
+	frontErr := s.front.Close()
+	if err := s.Store.Close(); err != nil {
+		return err
+	}
+	if frontErr != nil {
+		return fmt.Errorf("failed to close front store: %w", frontErr)
+	}
+	return nil
+}
+
+// Unwrap returns the authoritative source.
+func (s *Store) Unwrap() store.Store {
+	return s.Store
+}
+
+// seal prefixes value with its checksum.
+func seal(value []byte) []byte {
+	raw := make([]byte, checksumSize+len(value))
+	binary.BigEndian.PutUint32(raw, crc32.Checksum(value, castagnoli))
+	copy(raw[checksumSize:], value)
+	return raw
+}
+
+// open verifies a sealed copy and returns the value it holds.
+func open(raw []byte) ([]byte, bool) {
+	if len(raw) < checksumSize {
+		return nil, false
+	}
+	value := raw[checksumSize:]
+	if binary.BigEndian.Uint32(raw) != crc32.Checksum(value, castagnoli) {
+		return nil, false
+	}
+	return value, true
+}
+
+var (
+	_ store.Store   = (*Store)(nil)
+	_ store.Wrapper = (*Store)(nil)
+)
@@ -0,0 +1,94 @@
+package repair
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func createTestStore(t *testing.T) (*Store, *memory.MemoryStore, *memory.MemoryStore, *metrics.Registry) {
+	t.Helper()
+	front, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := metrics.NewRegistry()
+	s := New(front, source, Config{Metrics: registry})
+	t.Cleanup(func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	})
+	return s, front, source, registry
+}
+
+func TestStore_ReadRepair(t *testing.T) {
+	s, front, _, registry := createTestStore(t)
+
+	if err := s.Put("key", []byte("good")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found, err := s.Get("key")
+	if err != nil || !found || string(value) != "good" {
+		t.Fatalf("Unexpected Get: %q %t %v", value, found, err)
+	}
+	if registry.Counter(MetricHits).Value() != 1 {
+		t.Errorf("Expected a front hit")
+	}
+
+	// Corrupt the front copy behind the store's back
+	raw, _, _ := front.Get("key")
+	raw[len(raw)-1] ^= 0xFF
+	if err := front.Put("key", raw); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found, err = s.Get("key")
+	if err != nil || !found || string(value) != "good" {
+		t.Fatalf("Expected repaired value, got %q %t %v", value, found, err)
+	}
+	if registry.Counter(MetricRepairs).Value() != 1 {
+		t.Errorf("Expected one repair, got %d", registry.Counter(MetricRepairs).Value())
+	}
+
+	// The front copy has been rewritten from the source
+	raw, _, _ = front.Get("key")
+	if repaired, ok := open(raw); !ok || string(repaired) != "good" {
+		t.Errorf("Expected front copy to be repaired, got %q", raw)
+	}
+}
+
+func TestStore_ReadThrough(t *testing.T) {
+	s, front, source, registry := createTestStore(t)
+
+	if err := source.Put("cold", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found, err := s.Get("cold")
+	if err != nil || !found || string(value) != "value" {
+		t.Fatalf("Unexpected Get: %q %t %v", value, found, err)
+	}
+	if registry.Counter(MetricMisses).Value() != 1 {
+		t.Error("Expected a front miss")
+	}
+	if _, found, _ := front.Get("cold"); !found {
+		t.Error("Expected the front store to be filled on miss")
+	}
+
+	if err := s.Delete("cold"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := front.Get("cold"); found {
+		t.Error("Expected Delete to drop the front copy")
+	}
+	if _, found, _ := s.Get("cold"); found {
+		t.Error("Expected key to be gone")
+	}
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ReservedPrefix starts the keys the server keeps for itself, such as
+// namespace settings, lock and lease records and sequence counters. They are
+// written by the server's own components beneath the client path, and clients
+// may not write, delete or list them directly.
+const ReservedPrefix = "__clavis/"
+
+// ErrReservedKey is returned for client operations on keys under
+// ReservedPrefix.
+var ErrReservedKey = errors.New("key is reserved for the server")
+
+// IsReserved reports whether key is under ReservedPrefix.
+func IsReserved(key string) bool {
+	return strings.HasPrefix(key, ReservedPrefix)
+}
+
+// CheckReserved fails with ErrReservedKey if key is under ReservedPrefix.
+func CheckReserved(key string) error {
+	if IsReserved(key) {
+		return fmt.Errorf("%w: %q", ErrReservedKey, key)
+	}
+	return nil
+}
+
+// CheckReservedPrefix fails with ErrReservedKey if any key starting with
+// prefix may be under ReservedPrefix, such as for "__clavis/settings/" or
+// "__", so operations on every key under a prefix cannot reach them.
+func CheckReservedPrefix(prefix string) error {
+	if IsReserved(prefix) || strings.HasPrefix(ReservedPrefix, prefix) {
+		return fmt.Errorf("%w: prefix %q", ErrReservedKey, prefix)
+	}
+	return nil
+}
+
+// WithoutReserved returns an iterator over the pairs of it whose keys are not
+// under ReservedPrefix, for client listings that may reach them, such as of
+// every key.
+func WithoutReserved(it Iterator) Iterator {
+	return &unreservedIterator{Iterator: it}
+}
+
+// unreservedIterator skips the reserved keys of the iterator it wraps.
+type unreservedIterator struct {
+	Iterator
+}
+
+// Next advances to the next pair whose key is not reserved
+func (it *unreservedIterator) Next() bool {
+	for it.Iterator.Next() {
+		if !IsReserved(it.Key()) {
+			return true
+		}
+	}
+	return false
+}
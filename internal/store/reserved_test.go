@@ -0,0 +1,43 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestCheckReservedPrefix(t *testing.T) {
+	for prefix, reserved := range map[string]bool{
+		"__clavis/settings/": true,
+		"__clavis/":          true,
+		"__":                 true,
+		"":                   true,
+		"__clavis-app/":      false,
+		"tenant/__clavis/":   false,
+		"a":                  false,
+	} {
+		if err := store.CheckReservedPrefix(prefix); errors.Is(err, store.ErrReservedKey) != reserved {
+			t.Errorf("CheckReservedPrefix(%q) = %v, want reserved %v", prefix, err, reserved)
+		}
+	}
+	if err := store.CheckReserved("__"); err != nil {
+		t.Errorf("CheckReserved(__) = %v, want the key allowed", err)
+	}
+}
+
+func TestWithoutReserved(t *testing.T) {
+	it := store.WithoutReserved(store.NewSliceIterator(map[string][]byte{
+		"__clavis/settings/current/tenant-a": nil,
+		"__clavis-app/x":                     nil,
+		"a":                                  nil,
+		"z":                                  nil,
+	}))
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if err := it.Err(); err != nil || len(keys) != 3 || keys[0] != "__clavis-app/x" || keys[1] != "a" || keys[2] != "z" {
+		t.Errorf("WithoutReserved() listed %q, %v; want every key but the reserved one", keys, err)
+	}
+}
@@ -0,0 +1,18 @@
+package store
+
+import "time"
+
+// RetentionPolicy bounds the version history kept for a key. The current
+// version of a key is always kept.
+type RetentionPolicy struct {
+	KeepVersions int           // Keep at most this many versions; zero keeps any number
+	MaxAge       time.Duration // Drop versions older than this; zero keeps versions of any age
+}
+
+// IsZero reports whether the policy keeps every version.
+func (p RetentionPolicy) IsZero() bool {
+	return p.KeepVersions <= 0 && p.MaxAge <= 0
+}
+
+// RetentionPolicyFunc returns the retention policy that applies to key.
+type RetentionPolicyFunc func(key string) RetentionPolicy
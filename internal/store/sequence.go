@@ -0,0 +1,38 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxSequenceRange is the most numbers NextSequence reserves at once.
+const MaxSequenceRange = 1 << 16
+
+var (
+	// ErrSequencesUnsupported is returned for sequences of stores that do not
+	// implement Sequencer.
+	ErrSequencesUnsupported = errors.New("sequences are not supported by this store")
+	// ErrInvalidSequenceRange is returned by NextSequence for a range of zero
+	// numbers or more than MaxSequenceRange.
+	ErrInvalidSequenceRange = errors.New("invalid sequence range")
+)
+
+// Sequencer is implemented by stores that keep named sequences of numbers.
+type Sequencer interface {
+	// NextSequence reserves the next n numbers of the sequence called name, starting at 1 for a new sequence, and returns the first of them. Numbers are never handed out twice, but the store may skip some after a crash.
+	NextSequence(name string, n uint64) (uint64, error)
+}
+
+// NextSequence reserves the next n numbers of the sequence called name with
+// the first store in the chain that implements Sequencer, and returns the
+// first of them. Sequences are kept apart from keys, so the wrappers in
+// between neither see nor replicate them.
+func NextSequence(s Store, name string, n uint64) (uint64, error) {
+	if n == 0 || n > MaxSequenceRange {
+		return 0, fmt.Errorf("%w: %d is not in [1, %d]", ErrInvalidSequenceRange, n, MaxSequenceRange)
+	}
+	if sequencer, ok := As[Sequencer](s); ok {
+		return sequencer.NextSequence(name, n)
+	}
+	return 0, ErrSequencesUnsupported
+}
@@ -0,0 +1,355 @@
+// Package shadow mirrors the writes made on a primary store to a shadow store
+// in the background, such as a store of another backend or a remote Clavis
+// server, and compares a sample of reads between the two, so a migration can
+// be rehearsed on production traffic before the shadow takes over.
+//
+// The primary alone serves reads and decides the outcome of writes: a slow or
+// failing shadow neither fails nor delays them. Writes that find the queue of
+// writes to mirror full are dropped for the shadow, which then misses them
+// until their keys are written again.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// Metric names updated by the shadow store
+const (
+	MetricMirroredWrites = "store_shadow_mirrored_writes_total"
+	MetricMirrorErrors   = "store_shadow_errors_total"
+	MetricDroppedWrites  = "store_shadow_dropped_writes_total"
+	MetricPendingWrites  = "store_shadow_pending_writes"
+	MetricComparedReads  = "store_shadow_compared_reads_total"
+	// MetricDivergences counts the compared reads that disagreed, in total
+	// and per kind: missing from the shadow, extra in it, or different
+	MetricDivergences = "store_shadow_divergences_total"
+)
+
+// DefaultQueueSize is the number of writes waiting to be mirrored beyond
+// which more are dropped.
+const DefaultQueueSize = 10000
+
+// Config controls how writes are mirrored and reads compared.
+type Config struct {
+	QueueSize int // Writes waiting to be mirrored before more are dropped; DefaultQueueSize if zero
+	// CompareRate is the fraction of Gets, between 0 and 1, that are read
+	// from the shadow as well once the writes before them are mirrored, and
+	// compared with what the primary returned
+	CompareRate float64
+	Metrics     *metrics.Registry // Registry for the shadow metrics; metrics.Default if nil
+	Logger      *slog.Logger      // Receives shadow failures; slog.Default() if nil
+}
+
+// kind is what an operation does to the shadow.
+type kind int
+
+const (
+	opPut kind = iota
+	opDelete
+	opExpire
+	opCompare
+)
+
+// operation is a write to mirror, or a read to compare, in the order the
+// primary served them.
+type operation struct {
+	kind  kind
+	pairs map[string][]byte // opPut
+	keys  []string          // opDelete and opExpire
+
+	// opCompare: the key read and what the primary returned
+	key   string
+	value []byte
+	found bool
+}
+
+// size returns the number of keys op writes.
+func (op operation) size() int {
+	return len(op.pairs) + len(op.keys)
+}
+
+// Store writes to the primary store it wraps and mirrors every successful
+// write to the shadow. Writes are serialized, so the shadow applies them in
+// the order the primary committed them.
+type Store struct {
+	store.Passthrough // primary
+	shadow            store.Store
+	config            Config
+
+	// mu serializes writes and compared reads with the queue they enter
+	mu       sync.Mutex
+	queue    chan operation
+	stopped  bool
+	finished chan struct{}
+	stopping sync.Once
+	stopErr  error
+
+	// failing is whether the last operation on the shadow failed, so only
+	// changes are logged; it is only used by the mirroring goroutine
+	failing bool
+
+	mirrored, errors, dropped, compared, divergences *metrics.Counter
+	pending                                          *metrics.Gauge
+}
+
+// New wraps primary so its writes are mirrored to shadow, and starts mirroring
+// in the background until the store is stopped or closed.
+func New(primary, shadow store.Store, config Config) *Store {
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+
+	s := &Store{
+		Passthrough: store.Passthrough{Store: primary},
+		shadow:      shadow,
+		config:      config,
+		queue:       make(chan operation, config.QueueSize),
+		finished:    make(chan struct{}),
+		mirrored:    config.Metrics.Counter(MetricMirroredWrites),
+		errors:      config.Metrics.Counter(MetricMirrorErrors),
+		dropped:     config.Metrics.Counter(MetricDroppedWrites),
+		compared:    config.Metrics.Counter(MetricComparedReads),
+		divergences: config.Metrics.Counter(MetricDivergences),
+		pending:     config.Metrics.Gauge(MetricPendingWrites),
+	}
+	go s.run()
+	return s
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.Expirer               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+)
+
+// run applies the queued operations to the shadow until the queue is closed
+// and drained.
+func (s *Store) run() {
+	defer close(s.finished)
+	for op := range s.queue {
+		s.pending.Add(-int64(op.size()))
+		s.apply(op)
+	}
+}
+
+// apply applies op to the shadow.
+func (s *Store) apply(op operation) {
+	var err error
+	switch op.kind {
+	case opPut:
+		err = store.BatchPut(s.shadow, op.pairs)
+	case opDelete:
+		err = store.BatchDelete(s.shadow, op.keys)
+	case opExpire:
+		err = store.ExpireKeys(s.shadow, op.keys)
+	case opCompare:
+		err = s.compare(op)
+	}
+	if err != nil {
+		s.errors.Inc()
+		if !s.failing {
+			logging.Or(s.config.Logger).Warn("Shadow store started failing", "error", err)
+		}
+		s.failing = true
+		return
+	}
+	if s.failing {
+		logging.Or(s.config.Logger).Info("Shadow store recovered")
+	}
+	s.failing = false
+	if op.kind != opCompare {
+		s.mirrored.Add(uint64(op.size())) // #nosec G115 -- sizes are non-negative
+	}
+}
+
+// compare reads the key of op from the shadow and counts a divergence if it
+// differs from what the primary returned.
+func (s *Store) compare(op operation) error {
+	value, found, err := s.shadow.Get(op.key)
+	if err != nil {
+		return err
+	}
+	s.compared.Inc()
+	var divergence string
+	switch {
+	case op.found && !found:
+		divergence = "missing"
+	case !op.found && found:
+		divergence = "extra"
+	case op.found && !bytes.Equal(op.value, value):
+		divergence = "different"
+	default:
+		return nil
+	}
+	s.divergences.Inc()
+	s.config.Metrics.Counter(fmt.Sprintf("%s{kind=%q}", MetricDivergences, divergence)).Inc()
+	logging.Or(s.config.Logger).Debug("Shadow store diverged", "key", op.key, "kind", divergence)
+	return nil
+}
+
+// enqueueLocked queues op for the shadow, dropping it if the queue is full.
+// s.mu must be held.
+func (s *Store) enqueueLocked(op operation) {
+	if s.stopped {
+		return
+	}
+	select {
+	case s.queue <- op:
+		s.pending.Add(int64(op.size()))
+	default:
+		if op.kind != opCompare {
+			s.dropped.Add(uint64(op.size())) // #nosec G115 -- sizes are non-negative
+		}
+	}
+}
+
+// write runs a write on the primary and queues op for the shadow on success.
+func (s *Store) write(op operation, run func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := run(); err != nil {
+		return err
+	}
+	s.enqueueLocked(op)
+	return nil
+}
+
+// put returns the operation mirroring a write of pairs, with copies of the
+// values, which callers may reuse once the write returns.
+func put(pairs map[string][]byte) operation {
+	copied := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		copied[key] = bytes.Clone(value)
+	}
+	return operation{kind: opPut, pairs: copied}
+}
+
+// Put writes key to the primary and mirrors it.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return s.write(put(map[string][]byte{key: value}), func() error {
+		return store.PutContext(ctx, s.Store, key, value)
+	})
+}
+
+// PutIfVersion writes key to the primary if it is at expected there, and
+// mirrors it as a plain write since versions differ between stores.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	return s.write(put(map[string][]byte{key: value}), func() error {
+		return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+	})
+}
+
+// BatchPut writes pairs to the primary and mirrors them.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	return s.write(put(pairs), func() error {
+		return store.BatchPutContext(ctx, s.Store, pairs)
+	})
+}
+
+// Delete removes key from the primary and mirrors it.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	return s.write(operation{kind: opDelete, keys: []string{key}}, func() error {
+		return store.DeleteContext(ctx, s.Store, key)
+	})
+}
+
+// BatchDelete removes keys from the primary and mirrors them.
+func (s *Store) BatchDelete(keys []string) error {
+	return s.write(operation{kind: opDelete, keys: append([]string(nil), keys...)}, func() error {
+		return store.BatchDelete(s.Store, keys)
+	})
+}
+
+// ExpireKeys removes keys from the primary as expired and mirrors them.
+func (s *Store) ExpireKeys(keys []string) error {
+	return s.write(operation{kind: opExpire, keys: append([]string(nil), keys...)}, func() error {
+		return store.ExpireKeys(s.Store, keys)
+	})
+}
+
+// Get reads key from the primary, comparing it with the shadow at
+// CompareRate.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	if s.config.CompareRate <= 0 || rand.Float64() >= s.config.CompareRate { // #nosec G404 -- sampling needs no secure randomness
+		return store.GetContext(ctx, s.Store, key)
+	}
+	// Reading under the write lock puts the comparison after the writes the
+	// read saw, and before those it did not
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, found, err := store.GetContext(ctx, s.Store, key)
+	if err == nil {
+		s.enqueueLocked(operation{kind: opCompare, key: key, value: bytes.Clone(value), found: found})
+	}
+	return value, found, err
+}
+
+// Stop mirrors the writes still queued, then closes the shadow. The primary
+// stays open, and its later writes are no longer mirrored.
+func (s *Store) Stop() error {
+	s.stopping.Do(func() {
+		s.mu.Lock()
+		s.stopped = true
+		close(s.queue)
+		s.mu.Unlock()
+		<-s.finished
+		if err := s.shadow.Close(); err != nil {
+			s.stopErr = fmt.Errorf("failed to close shadow store: %w", err)
+		}
+	})
+	return s.stopErr
+}
+
+// Close stops mirroring and closes both stores.
+func (s *Store) Close() error {
+	stopErr := s.Stop()
+	if err := s.Store.Close(); err != nil {
+		return err
+	}
+	return stopErr
+}
@@ -0,0 +1,174 @@
+package shadow
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+// unclosed keeps the shadow readable after Stop closes it.
+type unclosed struct {
+	store.Store
+}
+
+func (unclosed) Close() error { return nil }
+
+// faulty fails every write, and blocks them until release is closed.
+type faulty struct {
+	store.Store
+	release chan struct{}
+}
+
+func (f faulty) Put(string, []byte) error {
+	<-f.release
+	return errors.New("shadow is down")
+}
+
+func newMemory(t *testing.T) store.Store {
+	t.Helper()
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func createTestStore(t *testing.T, shadow store.Store, config Config) (*Store, *metrics.Registry) {
+	t.Helper()
+	config.Metrics = metrics.NewRegistry()
+	s := New(newMemory(t), shadow, config)
+	t.Cleanup(func() { _ = s.Close() })
+	return s, config.Metrics
+}
+
+func TestStore_Mirrors(t *testing.T) {
+	shadow := newMemory(t)
+	s, registry := createTestStore(t, unclosed{shadow}, Config{})
+
+	value := []byte("1")
+	if err := s.Put("a", value); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'x' // Values are copied before they are queued
+	if err := s.BatchPut(map[string][]byte{"b": []byte("2"), "c": []byte("3"), "d": []byte("4")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchDelete([]string{"c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExpireKeys([]string{"d"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	primary, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirrored, err := shadow.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.EqualFunc(primary, mirrored, func(a, b []byte) bool { return string(a) == string(b) }) || string(mirrored["a"]) != "1" {
+		t.Errorf("Shadow holds %q, want %q as the primary", mirrored, primary)
+	}
+	if got := registry.Counter(MetricMirroredWrites).Value(); got != 7 {
+		t.Errorf("%s = %d, want 7", MetricMirroredWrites, got)
+	}
+
+	// Writes after Stop reach the primary alone
+	if err := s.Put("e", []byte("5")); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := shadow.Get("e"); found {
+		t.Error("Expected writes after Stop not to be mirrored")
+	}
+}
+
+func TestStore_ComparesReads(t *testing.T) {
+	shadow := newMemory(t)
+	s, registry := createTestStore(t, unclosed{shadow}, Config{CompareRate: 1})
+
+	if err := s.BatchPut(map[string][]byte{"same": []byte("1"), "changed": []byte("2"), "lost": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	// Reads are compared once the writes before them are mirrored
+	for _, key := range []string{"same", "changed", "lost", "absent"} {
+		if _, _, err := s.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for deadline := time.Now().Add(5 * time.Second); registry.Counter(MetricComparedReads).Value() < 4; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the reads to be compared")
+		}
+	}
+	// Then the shadow drifts behind the primary's back
+	if err := shadow.Put("changed", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err := shadow.Delete("lost"); err != nil {
+		t.Fatal(err)
+	}
+	if err := shadow.Put("stray", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"same", "changed", "lost", "stray"} {
+		if _, _, err := s.Get(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := registry.Counter(MetricComparedReads).Value(); got != 8 {
+		t.Errorf("%s = %d, want 8", MetricComparedReads, got)
+	}
+	if got := registry.Counter(MetricDivergences).Value(); got != 3 {
+		t.Errorf("%s = %d, want 3", MetricDivergences, got)
+	}
+	for _, kind := range []string{"missing", "extra", "different"} {
+		if got := registry.Counter(MetricDivergences + `{kind="` + kind + `"}`).Value(); got != 1 {
+			t.Errorf("%s of kind %s = %d, want 1", MetricDivergences, kind, got)
+		}
+	}
+}
+
+func TestStore_FailingShadow(t *testing.T) {
+	release := make(chan struct{})
+	s, registry := createTestStore(t, faulty{Store: newMemory(t), release: release}, Config{QueueSize: 1})
+
+	// The first write blocks the shadow, the second waits in the queue and
+	// the third finds it full; none of them waits for the shadow
+	for _, key := range []string{"a", "b", "c"} {
+		if err := s.Put(key, []byte(key)); err != nil {
+			t.Fatalf("Put(%s) = %v, want the primary's outcome", key, err)
+		}
+	}
+	close(release)
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found, err := s.Get("c"); err != nil || !found || string(value) != "c" {
+		t.Errorf("Get(c) = %q, %v, %v; want the primary's value", value, found, err)
+	}
+	if got := registry.Counter(MetricMirrorErrors).Value(); got+registry.Counter(MetricDroppedWrites).Value() != 3 || got == 0 {
+		t.Errorf("%s = %d and %s = %d, want every write failed or dropped", MetricMirrorErrors, got,
+			MetricDroppedWrites, registry.Counter(MetricDroppedWrites).Value())
+	}
+	if got := registry.Gauge(MetricPendingWrites).Value(); got != 0 {
+		t.Errorf("%s = %d after Stop, want 0", MetricPendingWrites, got)
+	}
+}
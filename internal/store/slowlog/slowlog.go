@@ -0,0 +1,294 @@
+// Package slowlog wraps a store to log and count the operations that take
+// longer than a threshold, with the keys, prefixes and value sizes involved,
+// so operators can find the keys and scans that strain the store.
+package slowlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// MetricSlowOperations counts the operations slower than the threshold,
+	// in total and per operation.
+	MetricSlowOperations = "store_slow_operations_total"
+
+	// DefaultThreshold is the Threshold of a Config that leaves it zero.
+	DefaultThreshold = 100 * time.Millisecond
+)
+
+// Config selects the operations that are reported.
+type Config struct {
+	Threshold time.Duration     // Operations taking longer are reported; DefaultThreshold if zero
+	Logger    *slog.Logger      // Logger for slow operations; slog.Default() if nil
+	Metrics   *metrics.Registry // Registry for the slow operation counts; metrics.Default if nil
+}
+
+// Store measures the operations made on the wrapped store.
+type Store struct {
+	store.Passthrough
+	config Config
+}
+
+// New wraps base.
+func New(base store.Store, config Config) *Store {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+	return &Store{Passthrough: store.Passthrough{Store: base}, config: config}
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.Expirer               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+)
+
+// report logs and counts op if it took longer than the threshold. attrs
+// describe what it worked on.
+func (s *Store) report(ctx context.Context, op string, elapsed time.Duration, err error, attrs ...any) {
+	if elapsed < s.config.Threshold {
+		return
+	}
+	s.config.Metrics.Counter(MetricSlowOperations).Inc()
+	s.config.Metrics.Counter(fmt.Sprintf("%s{op=%q}", MetricSlowOperations, op)).Inc()
+	attrs = append([]any{"op", op, "duration", elapsed}, attrs...)
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	s.config.Logger.WarnContext(ctx, "Slow store operation", attrs...)
+}
+
+// Get reads key from the wrapped store.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	start := time.Now()
+	value, found, err := store.GetContext(ctx, s.Store, key)
+	s.report(ctx, "get", time.Since(start), err, "key", key, "value_bytes", len(value))
+	return value, found, err
+}
+
+// GetView reads key from the wrapped store in place, reporting the time spent
+// in the store without the time spent in fn.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	start := time.Now()
+	var inCallback time.Duration
+	size := 0
+	found, err := store.GetView(s.Store, key, func(value []byte) error {
+		size = len(value)
+		called := time.Now()
+		defer func() { inCallback = time.Since(called) }()
+		return fn(value)
+	})
+	s.report(context.Background(), "get", time.Since(start)-inCallback, err, "key", key, "value_bytes", size)
+	return found, err
+}
+
+// Put writes key to the wrapped store.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	start := time.Now()
+	err := store.PutContext(ctx, s.Store, key, value)
+	s.report(ctx, "put", time.Since(start), err, "key", key, "value_bytes", len(value))
+	return err
+}
+
+// PutIfVersion writes key to the wrapped store if it is at expected.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	start := time.Now()
+	err := store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+	s.report(ctx, "put_if_version", time.Since(start), err, "key", key, "value_bytes", len(value))
+	return err
+}
+
+// Delete removes key from the wrapped store.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	start := time.Now()
+	err := store.DeleteContext(ctx, s.Store, key)
+	s.report(ctx, "delete", time.Since(start), err, "key", key)
+	return err
+}
+
+// Scan reads the pairs under prefix from the wrapped store.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	start := time.Now()
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	s.report(ctx, "scan", time.Since(start), err, append([]any{"prefix", prefix}, pairsAttrs(pairs)...)...)
+	return pairs, err
+}
+
+// BatchPut writes pairs to the wrapped store.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	start := time.Now()
+	err := store.BatchPutContext(ctx, s.Store, pairs)
+	s.report(ctx, "batch_put", time.Since(start), err, pairsAttrs(pairs)...)
+	return err
+}
+
+// BatchGet reads keys from the wrapped store.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	start := time.Now()
+	pairs, err := store.BatchGet(s.Store, keys)
+	s.report(context.Background(), "batch_get", time.Since(start), err, pairsAttrs(pairs)...)
+	return pairs, err
+}
+
+// BatchDelete removes keys from the wrapped store.
+func (s *Store) BatchDelete(keys []string) error {
+	start := time.Now()
+	err := store.BatchDelete(s.Store, keys)
+	s.report(context.Background(), "batch_delete", time.Since(start), err, keysAttrs(keys)...)
+	return err
+}
+
+// ExpireKeys removes keys from the wrapped store as expired.
+func (s *Store) ExpireKeys(keys []string) error {
+	start := time.Now()
+	err := store.ExpireKeys(s.Store, keys)
+	s.report(context.Background(), "expire", time.Since(start), err, keysAttrs(keys)...)
+	return err
+}
+
+// NewIterator streams the pairs under prefix from the wrapped store,
+// reporting the iteration once it is closed.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	start := time.Now()
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		s.report(context.Background(), "iterate", time.Since(start), err, "prefix", prefix)
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s, attrs: []any{"prefix", prefix}, elapsed: time.Since(start)}, nil
+}
+
+// ForEach visits the pairs under prefix in the wrapped store, reporting the
+// time spent in the store once done, without the time spent in fn.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	start := time.Now()
+	var inCallback time.Duration
+	pairs := 0
+	err := store.ForEach(s.Store, prefix, func(key string, value []byte) error {
+		pairs++
+		called := time.Now()
+		defer func() { inCallback += time.Since(called) }()
+		return fn(key, value)
+	})
+	s.report(context.Background(), "iterate", time.Since(start)-inCallback, err, "prefix", prefix, "pairs", pairs)
+	return err
+}
+
+// NewRangeIterator streams the pairs of r from the wrapped store, reporting
+// the iteration once it is closed.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	start := time.Now()
+	attrs := []any{"start", r.Start, "end", r.End}
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		s.report(context.Background(), "iterate", time.Since(start), err, attrs...)
+		return nil, err
+	}
+	return &iterator{Iterator: it, store: s, attrs: attrs, elapsed: time.Since(start)}, nil
+}
+
+// iterator measures the time spent in the wrapped store, leaving out the
+// time the caller spends between pairs.
+type iterator struct {
+	store.Iterator
+	store   *Store
+	attrs   []any
+	elapsed time.Duration
+	pairs   int
+	closed  bool
+}
+
+// Next advances the wrapped iterator.
+func (it *iterator) Next() bool {
+	start := time.Now()
+	ok := it.Iterator.Next()
+	it.elapsed += time.Since(start)
+	if ok {
+		it.pairs++
+	}
+	return ok
+}
+
+// Close closes the wrapped iterator and reports the iteration.
+func (it *iterator) Close() error {
+	if !it.closed {
+		it.closed = true
+		it.store.report(context.Background(), "iterate", it.elapsed, it.Err(), append(it.attrs, "pairs", it.pairs)...)
+	}
+	return it.Iterator.Close()
+}
+
+// pairsAttrs describes pairs by their number, total size and largest value.
+func pairsAttrs(pairs map[string][]byte) []any {
+	total, largest, largestKey := 0, -1, ""
+	for key, value := range pairs {
+		total += len(value)
+		if len(value) > largest {
+			largest, largestKey = len(value), key
+		}
+	}
+	attrs := []any{"pairs", len(pairs), "bytes", total}
+	if largest >= 0 {
+		attrs = append(attrs, "largest_key", largestKey, "largest_bytes", largest)
+	}
+	return attrs
+}
+
+// keysAttrs describes keys by their number and first key.
+func keysAttrs(keys []string) []any {
+	attrs := []any{"keys", len(keys)}
+	if len(keys) > 0 {
+		attrs = append(attrs, "first_key", keys[0])
+	}
+	return attrs
+}
@@ -0,0 +1,110 @@
+package slowlog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+// slowStore delays the reads of keys starting with "slow/"
+type slowStore struct {
+	store.Store
+}
+
+func (s slowStore) Get(key string) ([]byte, bool, error) {
+	if strings.HasPrefix(key, "slow/") {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return s.Store.Get(key)
+}
+
+func (s slowStore) Scan(prefix string) (map[string][]byte, error) {
+	time.Sleep(5 * time.Millisecond)
+	return s.Store.Scan(prefix)
+}
+
+func TestStore(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	registry := metrics.NewRegistry()
+	s := New(slowStore{base}, Config{
+		Threshold: 2 * time.Millisecond,
+		Logger:    slog.New(slog.NewTextHandler(&logs, nil)),
+		Metrics:   registry,
+	})
+	for key, value := range map[string]string{"slow/big": strings.Repeat("x", 1000), "fast/a": "1"} {
+		if err := s.Put(key, []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, _, err := s.Get("fast/a"); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected fast operations not to be logged, got %s", logs.String())
+	}
+
+	if _, _, err := s.Get("slow/big"); err != nil {
+		t.Fatal(err)
+	}
+	if line := logs.String(); !strings.Contains(line, "op=get") || !strings.Contains(line, "key=slow/big") || !strings.Contains(line, "value_bytes=1000") {
+		t.Errorf("Expected the slow read to be logged with its key and size, got %s", line)
+	}
+
+	logs.Reset()
+	if _, err := s.Scan("slow/"); err != nil {
+		t.Fatal(err)
+	}
+	if line := logs.String(); !strings.Contains(line, "op=scan") || !strings.Contains(line, "prefix=slow/") || !strings.Contains(line, "largest_key=slow/big") {
+		t.Errorf("Expected the slow scan to be logged with its prefix and largest value, got %s", line)
+	}
+
+	if n := registry.Counter(MetricSlowOperations).Value(); n != 2 {
+		t.Errorf("%s = %d, want 2", MetricSlowOperations, n)
+	}
+	if n := registry.Counter(MetricSlowOperations + `{op="scan"}`).Value(); n != 1 {
+		t.Errorf("Slow scans = %d, want 1", n)
+	}
+}
+
+func TestStore_Iterator(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	s := New(base, Config{Threshold: time.Hour, Logger: slog.New(slog.NewTextHandler(&logs, nil)), Metrics: metrics.NewRegistry()})
+	for _, key := range []string{"a", "b", "c"} {
+		if err := s.Put(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it, err := store.NewIterator(s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for it.Next() {
+		time.Sleep(2 * time.Millisecond) // Time spent by the caller is not counted
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	iterated := it.(*iterator)
+	if iterated.pairs != 3 || iterated.elapsed >= 6*time.Millisecond {
+		t.Errorf("Iterator counted %d pairs in %v, want 3 pairs without the caller's time", iterated.pairs, iterated.elapsed)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected no log under the threshold, got %s", logs.String())
+	}
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrSnapshotUnavailable is returned when a store cannot read its keys as
+	// of the requested time or version, typically because history was pruned.
+	ErrSnapshotUnavailable = errors.New("snapshot unavailable")
+	// ErrSnapshotsUnsupported is returned for point-in-time reads of stores
+	// that do not implement SnapshotReader.
+	ErrSnapshotsUnsupported = errors.New("point-in-time reads are not supported by this store")
+)
+
+// VersionAt returns the version of s as of t, from the first store in the
+// chain that implements SnapshotReader.
+func VersionAt(s Store, t time.Time) (uint64, error) {
+	if reader, ok := As[SnapshotReader](s); ok {
+		return reader.VersionAt(t)
+	}
+	return 0, ErrSnapshotsUnsupported
+}
+
+// NewIteratorAt returns an iterator over the pairs of s whose keys start with
+// prefix as they were at version, from the first store in the chain that
+// implements SnapshotReader. Reading at a fixed version returns the same
+// pairs however many writes happen meanwhile, so exports made this way are
+// consistent and reproducible.
+func NewIteratorAt(s Store, prefix string, version uint64) (Iterator, error) {
+	if reader, ok := As[SnapshotReader](s); ok {
+		return reader.NewIteratorAt(prefix, version)
+	}
+	return nil, ErrSnapshotsUnsupported
+}
@@ -0,0 +1,630 @@
+// Package softdelete wraps a store so that deleting a key keeps its value in
+// a tombstone for a retention period, during which Undelete restores it.
+// Reads through the wrapper report tombstoned keys as missing, and Purge
+// removes the tombstones whose retention has passed.
+//
+// A tombstone is stored in place of the value: three magic bytes, a kind
+// byte, the time of the delete in Unix nanoseconds and the deleted value.
+// Other values are stored as they are, except those that start with the
+// magic bytes, which are stored behind a header of their own; a value stored
+// without the wrapper that starts with them cannot be read through it.
+// Expirations remove keys for good, without a tombstone.
+package softdelete
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// DefaultRetention is the Retention of a Config that leaves it zero.
+	DefaultRetention = 24 * time.Hour
+	// DefaultPurgeInterval is the time between purges of a PurgeConfig that
+	// leaves it zero.
+	DefaultPurgeInterval = 10 * time.Minute
+	// MetricPurgedTombstones counts tombstones removed by purges.
+	MetricPurgedTombstones = "store_softdelete_purged_total"
+
+	// purgeBatchSize bounds the number of tombstones removed per batch.
+	purgeBatchSize = 1000
+)
+
+var (
+	// ErrNotDeleted is returned by Undelete for keys without a tombstone, or
+	// whose tombstone is past its retention.
+	ErrNotDeleted = errors.New("no deleted value to restore")
+	// ErrNotEnabled is returned by Undelete for stores without soft deletes.
+	ErrNotEnabled = errors.New("soft deletes are not enabled")
+	// ErrCorruptValue is returned when reading a stored value whose header
+	// is of an unknown kind or is cut short.
+	ErrCorruptValue = errors.New("corrupt soft-delete header")
+)
+
+// magic starts every value stored with a header.
+var magic = []byte{0xff, 'S', 'D'}
+
+// Kinds of values stored with a header. Live marks values stored with a
+// header only because they start with the magic bytes.
+const (
+	kindLive byte = iota
+	kindTombstone
+)
+
+// headerSize is the size of the header of a tombstone, before its value.
+const headerSize = 4 + 8
+
+// Config configures soft deletes.
+type Config struct {
+	Retention time.Duration // How long a deleted value can be restored; DefaultRetention if zero
+}
+
+// Store turns the deletes of the wrapped store into tombstones and hides
+// them from reads.
+type Store struct {
+	store.Passthrough
+	retention time.Duration
+	now       func() time.Time
+
+	// mu is held shared by writes and exclusively while tombstones are
+	// removed, so a purge never removes a key written after it was checked
+	mu sync.RWMutex
+}
+
+// New wraps base.
+func New(base store.Store, config Config) *Store {
+	if config.Retention <= 0 {
+		config.Retention = DefaultRetention
+	}
+	return &Store{Passthrough: store.Passthrough{Store: base}, retention: config.Retention, now: time.Now}
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+)
+
+// encode returns a live value as it is stored.
+func encode(value []byte) []byte {
+	if !bytes.HasPrefix(value, magic) {
+		return value
+	}
+	stored := make([]byte, 0, len(magic)+1+len(value))
+	stored = append(stored, magic...)
+	stored = append(stored, kindLive)
+	return append(stored, value...)
+}
+
+// encodeTombstone returns the tombstone of value deleted at deletedAt.
+func encodeTombstone(value []byte, deletedAt time.Time) []byte {
+	stored := make([]byte, 0, headerSize+len(value))
+	stored = append(stored, magic...)
+	stored = append(stored, kindTombstone)
+	stored = binary.BigEndian.AppendUint64(stored, uint64(deletedAt.UnixNano())) // #nosec G115 -- times are after 1970
+	return append(stored, value...)
+}
+
+// decode returns the value stored as stored and, for a tombstone, when it
+// was deleted.
+func decode(stored []byte) (value []byte, deletedAt time.Time, deleted bool, err error) {
+	if !bytes.HasPrefix(stored, magic) {
+		return stored, time.Time{}, false, nil
+	}
+	if len(stored) == len(magic) {
+		return nil, time.Time{}, false, ErrCorruptValue
+	}
+	switch stored[len(magic)] {
+	case kindLive:
+		return stored[len(magic)+1:], time.Time{}, false, nil
+	case kindTombstone:
+		if len(stored) < headerSize {
+			return nil, time.Time{}, false, ErrCorruptValue
+		}
+		nanos := int64(binary.BigEndian.Uint64(stored[len(magic)+1:])) // #nosec G115 -- written from an int64
+		return stored[headerSize:], time.Unix(0, nanos), true, nil
+	}
+	return nil, time.Time{}, false, fmt.Errorf("%w: unknown kind %d", ErrCorruptValue, stored[len(magic)])
+}
+
+// decodeLive returns the value stored as stored, reporting false for a
+// tombstone.
+func decodeLive(key string, stored []byte) ([]byte, bool, error) {
+	value, _, deleted, err := decode(stored)
+	if err != nil {
+		return nil, false, fmt.Errorf("key %q: %w", key, err)
+	}
+	return value, !deleted, nil
+}
+
+// decodeAll drops the tombstones of pairs and decodes the other values.
+func decodeAll(pairs map[string][]byte) (map[string][]byte, error) {
+	for key, stored := range pairs {
+		value, live, err := decodeLive(key, stored)
+		if err != nil {
+			return nil, err
+		}
+		if live {
+			pairs[key] = value
+		} else {
+			delete(pairs, key)
+		}
+	}
+	return pairs, nil
+}
+
+// lookup reads the stored form of key with its version, which is 0 when the
+// wrapped store keeps no versions.
+func (s *Store) lookup(ctx context.Context, key string) (stored []byte, version uint64, found bool, err error) {
+	if reader, ok := store.As[store.VersionReader](s.Store); ok {
+		stored, version, found, err = reader.GetAt(key, 0)
+		if !errors.Is(err, store.ErrSnapshotsUnsupported) {
+			return stored, version, found, err
+		}
+	}
+	stored, found, err = store.GetContext(ctx, s.Store, key)
+	return stored, 0, found, err
+}
+
+// Put stores the value, replacing any tombstone of the key.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return store.PutContext(ctx, s.Store, key, encode(value))
+}
+
+// PutIfVersion conditionally stores the value. A tombstoned key is at
+// version 0, as a missing key is.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if expected == 0 {
+		stored, version, found, err := s.lookup(ctx, key)
+		if err != nil {
+			return err
+		}
+		if found {
+			_, live, err := decodeLive(key, stored)
+			if err != nil {
+				return err
+			}
+			if !live {
+				expected = version
+			}
+		}
+	}
+	return store.PutIfVersionContext(ctx, s.Store, key, encode(value), expected)
+}
+
+// BatchPut stores the pairs, replacing any tombstones of their keys.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	encoded := make(map[string][]byte, len(pairs))
+	for key, value := range pairs {
+		encoded[key] = encode(value)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return store.BatchPutContext(ctx, s.Store, encoded)
+}
+
+// Delete replaces the value of the key with a tombstone. Deleting a missing
+// or tombstoned key does nothing.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx. When the wrapped
+// store keeps versions, the tombstone is written conditionally, starting over
+// when another write came in between.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for {
+		stored, version, found, err := s.lookup(ctx, key)
+		if err != nil || !found {
+			return err
+		}
+		value, live, err := decodeLive(key, stored)
+		if err != nil || !live {
+			return err
+		}
+		tombstone := encodeTombstone(value, s.now())
+		if version == 0 {
+			return store.PutContext(ctx, s.Store, key, tombstone)
+		}
+		err = store.PutIfVersionContext(ctx, s.Store, key, tombstone, version)
+		if !errors.Is(err, store.ErrVersionConflict) {
+			return err
+		}
+	}
+}
+
+// BatchDelete replaces the values of the keys with tombstones in one batch.
+// Unlike Delete, the tombstones are written unconditionally, so a Put racing
+// the batch may be buried with it.
+func (s *Store) BatchDelete(keys []string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	tombstones := make(map[string][]byte, len(pairs))
+	for key, stored := range pairs {
+		value, live, err := decodeLive(key, stored)
+		if err != nil {
+			return err
+		}
+		if live {
+			tombstones[key] = encodeTombstone(value, now)
+		}
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+	return store.BatchPut(s.Store, tombstones)
+}
+
+// Get reads the value of key, reporting a tombstoned key as missing.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	stored, found, err := store.GetContext(ctx, s.Store, key)
+	if err != nil || !found {
+		return stored, found, err
+	}
+	value, live, err := decodeLive(key, stored)
+	if err != nil || !live {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// GetView calls fn with the value of key, unless it is tombstoned.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	live := false
+	found, err := store.GetView(s.Store, key, func(stored []byte) error {
+		value, ok, err := decodeLive(key, stored)
+		if err != nil || !ok {
+			return err
+		}
+		live = true
+		return fn(value)
+	})
+	return found && live, err
+}
+
+// Scan reads the pairs whose keys start with prefix, without tombstones.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll(pairs)
+}
+
+// BatchGet reads the values of keys, leaving out tombstoned keys.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll(pairs)
+}
+
+// NewIterator streams the pairs whose keys start with prefix, skipping
+// tombstones.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// ForEach visits the pairs whose keys start with prefix, skipping
+// tombstones.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, stored []byte) error {
+		value, live, err := decodeLive(key, stored)
+		if err != nil || !live {
+			return err
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the pairs whose keys are in r, skipping
+// tombstones. A keys-only range still reads the values from the wrapped
+// store, since they tell tombstones apart.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	keysOnly := r.KeysOnly
+	r.KeysOnly = false
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it, keysOnly: keysOnly}, nil
+}
+
+// GetAt reads the value of key as of version, reporting a key tombstoned at
+// that version as missing, and fails with store.ErrSnapshotsUnsupported if
+// the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	stored, at, found, err := reader.GetAt(key, version)
+	if err != nil || !found {
+		return stored, at, found, err
+	}
+	value, live, err := decodeLive(key, stored)
+	if err != nil || !live {
+		return nil, 0, false, err
+	}
+	return value, at, true, nil
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the pairs whose keys start with prefix as they were
+// at version, skipping tombstones.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// EvaluateFilter presents the pairs that are not tombstoned to filter, with
+// the write times of the wrapped store when it tracks them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		value, live, err := decodeLive(entry.Key, entry.Value)
+		if err != nil || !live {
+			return false, err
+		}
+		entry.Value = value
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// Tombstone is the value a key had when it was deleted.
+type Tombstone struct {
+	Value     []byte
+	DeletedAt time.Time
+	Version   uint64 // Version the tombstone was written at; 0 if the wrapped store keeps no versions
+}
+
+// Tombstone returns the tombstone of key, reporting false if the key is not
+// deleted or its tombstone is past its retention.
+func (s *Store) Tombstone(key string) (Tombstone, bool, error) {
+	stored, version, found, err := s.lookup(context.Background(), key)
+	if err != nil || !found {
+		return Tombstone{}, false, err
+	}
+	value, deletedAt, deleted, err := decode(stored)
+	if err != nil {
+		return Tombstone{}, false, fmt.Errorf("key %q: %w", key, err)
+	}
+	if !deleted || s.now().Sub(deletedAt) > s.retention {
+		return Tombstone{}, false, nil
+	}
+	return Tombstone{Value: value, DeletedAt: deletedAt, Version: version}, true, nil
+}
+
+// Undelete restores the value key had when it was deleted through the
+// soft-delete wrapper beneath s, or fails with ErrNotDeleted if there is no
+// such value left. The value is written through s, so every wrapper above
+// sees an ordinary Put; when the store keeps versions, it is written only if
+// the key is still at the version of its tombstone, failing with
+// store.ErrVersionConflict otherwise.
+func Undelete(ctx context.Context, s store.Store, key string) error {
+	sd, ok := store.As[*Store](s)
+	if !ok {
+		return ErrNotEnabled
+	}
+	tombstone, found, err := sd.Tombstone(key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: key %q", ErrNotDeleted, key)
+	}
+	if tombstone.Version == 0 {
+		return store.PutContext(ctx, s, key, tombstone.Value)
+	}
+	return store.PutIfVersionContext(ctx, s, key, tombstone.Value, tombstone.Version)
+}
+
+// Purge removes the tombstones deleted more than the retention before now
+// and returns how many it removed.
+func (s *Store) Purge(now time.Time) (int, error) {
+	var expired []string
+	err := store.ForEach(s.Store, "", func(key string, stored []byte) error {
+		if s.expired(stored, now) {
+			expired = append(expired, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for start := 0; start < len(expired); start += purgeBatchSize {
+		n, err := s.purge(expired[start:min(start+purgeBatchSize, len(expired))], now)
+		purged += n
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// purge removes the keys that still hold expired tombstones, with writes
+// held off while it checks and removes them.
+func (s *Store) purge(keys []string, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return 0, err
+	}
+	var expired []string
+	for key, stored := range pairs {
+		if s.expired(stored, now) {
+			expired = append(expired, key)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := store.BatchDelete(s.Store, expired); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// expired reports whether stored is a tombstone past its retention at now.
+func (s *Store) expired(stored []byte, now time.Time) bool {
+	_, deletedAt, deleted, err := decode(stored)
+	return err == nil && deleted && now.Sub(deletedAt) > s.retention
+}
+
+// PurgeConfig configures background purges.
+type PurgeConfig struct {
+	Interval time.Duration     // Time between purges; DefaultPurgeInterval if zero
+	Metrics  *metrics.Registry // Registry for purge metrics; metrics.Default if nil
+	Logger   *slog.Logger      // Logger for failed purges; slog.Default() if nil
+}
+
+// StartPurge purges expired tombstones in the background until the returned
+// function is called.
+func (s *Store) StartPurge(config PurgeConfig) (stop func()) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	registry := config.Metrics
+	if registry == nil {
+		registry = metrics.Default
+	}
+	purged := registry.Counter(MetricPurgedTombstones)
+	logger := logging.Or(config.Logger)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				n, err := s.Purge(now)
+				if err != nil {
+					logger.Warn("Tombstone purge failed", "error", err)
+				}
+				purged.Add(uint64(n)) // #nosec G115 -- counts are non-negative
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
+
+// iterator skips the tombstones of the wrapped iterator and decodes the
+// other values. A value with a corrupt header stops the iteration.
+type iterator struct {
+	store.Iterator
+	keysOnly bool
+	value    []byte
+	err      error
+}
+
+func (it *iterator) Next() bool {
+	for it.err == nil && it.Iterator.Next() {
+		var live bool
+		it.value, live, it.err = decodeLive(it.Iterator.Key(), it.Iterator.Value())
+		if live {
+			if it.keysOnly {
+				it.value = nil
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Err()
+}
@@ -0,0 +1,225 @@
+package softdelete
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T, config Config) (*Store, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(base, config), base
+}
+
+func TestStore_DeleteHidesKeys(t *testing.T) {
+	s, base := newStore(t, Config{})
+	values := map[string][]byte{
+		"users/1": []byte("alice"),
+		"users/2": []byte("bob"),
+		"users/3": append(append([]byte(nil), magic...), "\x01not a tombstone"...),
+	}
+	for key, value := range values {
+		if err := s.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete("users/1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchDelete([]string{"users/2", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"users/1", "users/2", "missing"} {
+		if value, found, err := s.Get(key); err != nil || found {
+			t.Errorf("Get(%q) = %q, %v, %v; want it missing", key, value, found, err)
+		}
+		if found, err := s.GetView(key, func([]byte) error { return nil }); err != nil || found {
+			t.Errorf("GetView(%q) = %v, %v; want it missing", key, found, err)
+		}
+	}
+	if _, found, _ := base.Get("users/1"); !found {
+		t.Error("Expected the tombstone to stay in the wrapped store")
+	}
+	if _, found, _ := base.Get("missing"); found {
+		t.Error("Expected no tombstone for a key that did not exist")
+	}
+
+	want := map[string][]byte{"users/3": values["users/3"]}
+	pairs, err := s.Scan("users/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPairs(t, "Scan", pairs, want)
+	pairs, err = s.BatchGet([]string{"users/1", "users/2", "users/3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPairs(t, "BatchGet", pairs, want)
+	visited := map[string][]byte{}
+	if err := s.ForEach("users/", func(key string, value []byte) error {
+		visited[key] = bytes.Clone(value)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertPairs(t, "ForEach", visited, want)
+	if n, err := store.Count(context.Background(), s, "users/"); err != nil || n != 1 {
+		t.Errorf("Count() = %d, %v; want 1", n, err)
+	}
+	if found, err := store.Exists(s, "users/1"); err != nil || found {
+		t.Errorf("Exists(users/1) = %v, %v; want false", found, err)
+	}
+}
+
+func TestUndelete(t *testing.T) {
+	s, _ := newStore(t, Config{Retention: time.Hour})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := s.Put("key", []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Undelete(ctx, s, "key"); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("Expected a live key not to be undeletable, got %v", err)
+	}
+	if err := s.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	tombstone, found, err := s.Tombstone("key")
+	if err != nil || !found || string(tombstone.Value) != "value" || !tombstone.DeletedAt.Equal(now) {
+		t.Errorf("Tombstone() = %+v, %v, %v; want the deleted value", tombstone, found, err)
+	}
+
+	// Undelete reaches the wrapper through the stores above it
+	outer := store.Passthrough{Store: s}
+	if err := Undelete(ctx, outer, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, err := s.Get("key"); err != nil || !found || string(value) != "value" {
+		t.Errorf("Get() = %q, %v, %v after Undelete; want the restored value", value, found, err)
+	}
+
+	// Past the retention, the value is gone
+	if err := s.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := Undelete(ctx, s, "key"); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("Expected the tombstone to be past its retention, got %v", err)
+	}
+	base, _ := memory.NewWithDefaults()
+	if err := Undelete(ctx, base, "key"); !errors.Is(err, ErrNotEnabled) {
+		t.Errorf("Expected ErrNotEnabled without the wrapper, got %v", err)
+	}
+}
+
+func TestStore_Versioned(t *testing.T) {
+	base, err := badger.NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = base.Close() })
+	s := New(base, Config{})
+	ctx := context.Background()
+
+	if err := s.Put("hits", []byte("41")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("hits"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, found, err := s.GetAt("hits", 0); err != nil || found {
+		t.Errorf("GetAt() = %v, %v; want the tombstoned key missing", found, err)
+	}
+
+	// A tombstoned key is absent to conditional writes and counters
+	if err := store.PutIfAbsent(s, "hits", []byte("1")); err != nil {
+		t.Errorf("Expected PutIfAbsent over a tombstone to succeed, got %v", err)
+	}
+	if err := s.Delete("hits"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.Increment(ctx, s, "hits", 1, store.CounterASCII); err != nil || n != 1 {
+		t.Errorf("Increment() = %d, %v; want 1", n, err)
+	}
+
+	// Undelete writes conditionally on the version of the tombstone
+	if err := s.Delete("hits"); err != nil {
+		t.Fatal(err)
+	}
+	tombstone, found, err := s.Tombstone("hits")
+	if err != nil || !found || tombstone.Version == 0 {
+		t.Fatalf("Tombstone() = %+v, %v, %v; want a versioned tombstone", tombstone, found, err)
+	}
+	if err := Undelete(ctx, s, "hits"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := s.Get("hits"); string(value) != "1" {
+		t.Errorf("Get() = %q after Undelete, want 1", value)
+	}
+}
+
+func TestStore_Purge(t *testing.T) {
+	s, base := newStore(t, Config{Retention: time.Hour})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	for _, key := range []string{"old", "recent", "live"} {
+		if err := s.Put(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete("old"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Minute)
+	if err := s.Delete("recent"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.Purge(now.Add(45 * time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("Purge() = %d, %v; want 1", n, err)
+	}
+	if _, found, _ := base.Get("old"); found {
+		t.Error("Expected the expired tombstone to be purged")
+	}
+	for _, key := range []string{"recent", "live"} {
+		if _, found, _ := base.Get(key); !found {
+			t.Errorf("Expected %q to be kept", key)
+		}
+	}
+}
+
+func TestStore_CorruptValue(t *testing.T) {
+	s, base := newStore(t, Config{})
+	if err := base.Put("bad", append(append([]byte(nil), magic...), 9)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Get("bad"); !errors.Is(err, ErrCorruptValue) {
+		t.Errorf("Expected ErrCorruptValue, got %v", err)
+	}
+}
+
+func assertPairs(t *testing.T, name string, got, want map[string][]byte) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s returned %d pairs, want %d", name, len(got), len(want))
+	}
+	for key, value := range want {
+		if !bytes.Equal(got[key], value) {
+			t.Errorf("%s[%q] = %q, want %q", name, key, got[key], value)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// BatchPut stores all pairs in a single transaction
+func (ss *SQLiteStore) BatchPut(pairs map[string][]byte) error {
+	ss.ops.CountPuts(len(pairs))
+	for key := range pairs {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	return ss.update(func(tx *sql.Tx) error {
+		put := tx.Stmt(ss.stmts.put)
+		for key, value := range pairs {
+			if _, err := put.Exec([]byte(key), nonNil(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BatchGet retrieves the values of keys. Each key is read on its own rather
+// than in a transaction, which would take the write lock, so writes
+// committed between the reads are seen.
+func (ss *SQLiteStore) BatchGet(keys []string) (map[string][]byte, error) {
+	ss.ops.CountGets(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("key cannot be empty")
+		}
+	}
+	if err := ss.open(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, found, err := ss.lookup(nil, key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// BatchDelete removes keys in a single transaction
+func (ss *SQLiteStore) BatchDelete(keys []string) error {
+	ss.ops.CountDeletes(len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+	}
+
+	return ss.update(func(tx *sql.Tx) error {
+		del := tx.Stmt(ss.stmts.delete)
+		for _, key := range keys {
+			if _, err := del.Exec([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var _ store.Batcher = (*SQLiteStore)(nil)
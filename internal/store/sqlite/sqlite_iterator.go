@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// iteratorPageSize is how many pairs an iterator reads in each query.
+const iteratorPageSize = 256
+
+// sqliteIterator reads the pairs of a key range a page at a time, each page
+// in a query of its own: a query left open for a whole iteration would hold
+// a connection and, without WAL, block every writer. Pairs written in the
+// part of the range not read yet are seen.
+type sqliteIterator struct {
+	ss   *SQLiteStore
+	r    store.KeyRange // Part of the range not read yet
+	page []store.KeyValue
+	pos  int
+	done bool // Whether the range holds no pair beyond the page
+	err  error
+}
+
+// NewIterator returns an iterator over the pairs whose keys start with prefix
+func (ss *SQLiteStore) NewIterator(prefix string) (store.Iterator, error) {
+	return ss.NewRangeIterator(store.PrefixRange(prefix))
+}
+
+// NewRangeIterator returns an iterator over the pairs whose keys are in r
+func (ss *SQLiteStore) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	ss.ops.CountScan()
+	if err := ss.open(); err != nil {
+		return nil, err
+	}
+	return &sqliteIterator{ss: ss, r: r}, nil
+}
+
+func (it *sqliteIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	if it.pos < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+	if it.err = it.fill(); it.err != nil {
+		return false
+	}
+	it.pos = 0
+	return len(it.page) > 0
+}
+
+// fill reads the next page of pairs and narrows the range to the pairs after
+// it
+func (it *sqliteIterator) fill() (err error) {
+	it.page = it.page[:0]
+	if err := it.ss.open(); err != nil {
+		return err
+	}
+	rows, err := it.ss.query(nil, it.r, iteratorPageSize)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+
+	for rows.Next() {
+		var key, value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		pair := store.KeyValue{Key: string(key)}
+		if !it.r.KeysOnly {
+			pair.Value = nonNil(value)
+		}
+		it.page = append(it.page, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(it.page) < iteratorPageSize {
+		it.done = true
+		return nil
+	}
+	last := it.page[len(it.page)-1].Key
+	if it.r.Reverse {
+		it.r.End = last
+	} else {
+		// The first key after last
+		it.r.Start = last + "\x00"
+	}
+	return nil
+}
+
+func (it *sqliteIterator) Key() string {
+	return it.page[it.pos].Key
+}
+
+func (it *sqliteIterator) Value() []byte {
+	return it.page[it.pos].Value
+}
+
+func (it *sqliteIterator) Err() error {
+	return it.err
+}
+
+func (it *sqliteIterator) Close() error {
+	it.page = nil
+	it.done = true
+	return nil
+}
+
+var (
+	_ store.IteratorProvider      = (*SQLiteStore)(nil)
+	_ store.RangeIteratorProvider = (*SQLiteStore)(nil)
+)
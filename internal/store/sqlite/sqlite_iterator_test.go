@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func TestSQLiteStore_NewIterator(t *testing.T) {
+	ss := createTestStore(t)
+
+	// More pairs than fit in one page
+	pairs := map[string][]byte{"product:1": []byte("book")}
+	for i := range iteratorPageSize*2 + 5 {
+		pairs[fmt.Sprintf("user:%04d", i)] = []byte(fmt.Sprint(i))
+	}
+	if err := ss.BatchPut(pairs); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("IteratesInKeyOrder", func(t *testing.T) {
+		it, err := ss.NewIterator("user:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = it.Close() }()
+
+		count := 0
+		for it.Next() {
+			if want := fmt.Sprintf("user:%04d", count); it.Key() != want || string(it.Value()) != fmt.Sprint(count) {
+				t.Fatalf("Pair %d = %s: %s, want %s", count, it.Key(), it.Value(), want)
+			}
+			count++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if count != iteratorPageSize*2+5 {
+			t.Errorf("Expected %d users, got %d", iteratorPageSize*2+5, count)
+		}
+	})
+
+	t.Run("SeesWritesToPagesNotReadYet", func(t *testing.T) {
+		it, err := ss.NewIterator("user:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = it.Close() }()
+
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+		if err := ss.Delete("user:0500"); err != nil {
+			t.Fatal(err)
+		}
+		count := 1
+		for it.Next() {
+			count++
+		}
+		if count != iteratorPageSize*2+4 {
+			t.Errorf("Expected the deleted user to be skipped, got %d users", count)
+		}
+	})
+
+	t.Run("ClosedStore", func(t *testing.T) {
+		closed := createTestStore(t)
+		if err := closed.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := closed.NewIterator(""); err == nil {
+			t.Error("Expected error when iterating a closed store")
+		}
+	})
+}
+
+func TestSQLiteStore_NewRangeIterator(t *testing.T) {
+	ss := createTestStore(t)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := ss.Put(key, []byte("v"+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		r    store.KeyRange
+		want []string
+	}{
+		{store.KeyRange{Start: "b", End: "d"}, []string{"b", "c"}},
+		{store.KeyRange{Start: "b", End: "d", Reverse: true}, []string{"c", "b"}},
+		{store.KeyRange{Start: "b", Reverse: true}, []string{"d", "c", "b"}},
+		{store.KeyRange{End: "bb", Reverse: true}, []string{"b", "a"}},
+		{store.KeyRange{Start: "e"}, nil},
+	} {
+		it, err := ss.NewRangeIterator(tt.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for it.Next() {
+			keys = append(keys, it.Key())
+		}
+		_ = it.Close()
+		if !reflect.DeepEqual(keys, tt.want) {
+			t.Errorf("NewRangeIterator(%+v) keys = %v, want %v", tt.r, keys, tt.want)
+		}
+	}
+
+	// Reverse pages resume before the last key read
+	pairs := make(map[string][]byte)
+	for i := range iteratorPageSize + 10 {
+		pairs[fmt.Sprintf("n/%04d", i)] = nil
+	}
+	if err := ss.BatchPut(pairs); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := store.Keys(ss, store.KeyRange{Start: "n/", End: "n0", Reverse: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != iteratorPageSize+10 || keys[0] != fmt.Sprintf("n/%04d", iteratorPageSize+9) || keys[len(keys)-1] != "n/0000" {
+		t.Errorf("Expected %d keys in descending order, got %d from %v", iteratorPageSize+10, len(keys), keys[:1])
+	}
+}
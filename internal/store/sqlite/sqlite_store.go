@@ -0,0 +1,354 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// SQLiteStore keeps the pairs in one table of a SQLite database, for
+// deployments that already back up and inspect SQLite files. Keys are stored
+// as BLOBs, so the table orders them byte by byte as the other stores do.
+type SQLiteStore struct {
+	db     *sql.DB
+	stmts  statements
+	closed atomic.Bool
+	// ops counts the operations served, for Stats
+	ops store.OperationCounters
+}
+
+// statements are prepared once when the store opens. database/sql prepares
+// each one again on every pooled connection it runs on and keeps it there,
+// so SQLite compiles every statement once per connection rather than once
+// per call.
+type statements struct {
+	get    *sql.Stmt
+	put    *sql.Stmt
+	delete *sql.Stmt
+	count  *sql.Stmt
+	// page selects the pairs from a start key, indexed by whether the keys
+	// are visited in reverse and whether the range has an end
+	page [2][2]*sql.Stmt
+}
+
+func New(config *SQLiteStoreConfig) (*SQLiteStore, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+	table, err := config.table()
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := config.ToDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	ss := &SQLiteStore{db: db}
+	if err := ss.prepare(table); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return ss, nil
+}
+
+func NewWithPath(path string) (*SQLiteStore, error) {
+	return New(DefaultConfig(path))
+}
+
+// prepare creates the table if missing and prepares the statements
+func (ss *SQLiteStore) prepare(table string) error {
+	create := "CREATE TABLE IF NOT EXISTS " + table + " (key BLOB PRIMARY KEY, value BLOB NOT NULL) WITHOUT ROWID"
+	if _, err := ss.db.Exec(create); err != nil {
+		return fmt.Errorf("failed to create table %q: %w", table, err)
+	}
+
+	queries := map[**sql.Stmt]string{
+		&ss.stmts.get:    "SELECT value FROM " + table + " WHERE key = ?",
+		&ss.stmts.put:    "INSERT INTO " + table + " (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value",
+		&ss.stmts.delete: "DELETE FROM " + table + " WHERE key = ?",
+		&ss.stmts.count:  "SELECT COUNT(*) FROM " + table,
+	}
+	for reverse, order := range []string{"ASC", "DESC"} {
+		// A LIMIT of -1 selects every pair
+		queries[&ss.stmts.page[reverse][0]] = "SELECT key, value FROM " + table + " WHERE key >= ? ORDER BY key " + order + " LIMIT ?"
+		queries[&ss.stmts.page[reverse][1]] = "SELECT key, value FROM " + table + " WHERE key >= ? AND key < ? ORDER BY key " + order + " LIMIT ?"
+	}
+	for stmt, query := range queries {
+		prepared, err := ss.db.Prepare(query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare %q: %w", query, err)
+		}
+		*stmt = prepared
+	}
+	return nil
+}
+
+// Close the statements and the database, once the queries in flight are done
+func (ss *SQLiteStore) Close() error {
+	if ss.closed.Swap(true) {
+		return nil
+	}
+	stmts := []*sql.Stmt{ss.stmts.get, ss.stmts.put, ss.stmts.delete, ss.stmts.count}
+	for _, page := range ss.stmts.page {
+		stmts = append(stmts, page[:]...)
+	}
+	var errs []error
+	for _, stmt := range stmts {
+		errs = append(errs, stmt.Close())
+	}
+	errs = append(errs, ss.db.Close())
+	return errors.Join(errs...)
+}
+
+// Ping reports whether the database is still open and reachable
+func (ss *SQLiteStore) Ping() error {
+	if err := ss.open(); err != nil {
+		return err
+	}
+	return ss.db.Ping()
+}
+
+// open reports calls on a closed store the way the other stores do
+func (ss *SQLiteStore) open() error {
+	if ss.closed.Load() {
+		return fmt.Errorf("store is closed")
+	}
+	return nil
+}
+
+// update runs fn in a transaction, committed if fn succeeds. Transactions
+// take the write lock as they begin, so they are serialized with every other
+// writer.
+func (ss *SQLiteStore) update(fn func(tx *sql.Tx) error) error {
+	if err := ss.open(); err != nil {
+		return err
+	}
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// lookup returns the value stored at key, using stmt from the transaction if
+// tx is not nil
+func (ss *SQLiteStore) lookup(tx *sql.Tx, key string) ([]byte, bool, error) {
+	stmt := ss.stmts.get
+	if tx != nil {
+		stmt = tx.Stmt(stmt)
+	}
+	var value []byte
+	err := stmt.QueryRow([]byte(key)).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return nonNil(value), true, nil
+}
+
+// nonNil keeps empty values apart from missing ones, as the driver scans
+// empty BLOBs as nil
+func nonNil(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return value
+}
+
+// query selects the pairs of r in the order r selects, at most limit of them
+// or all if limit is negative
+func (ss *SQLiteStore) query(tx *sql.Tx, r store.KeyRange, limit int) (*sql.Rows, error) {
+	reverse, args := 0, []any{[]byte(r.Start)}
+	if r.Reverse {
+		reverse = 1
+	}
+	bounded := 0
+	if r.End != "" {
+		bounded = 1
+		args = append(args, []byte(r.End))
+	}
+	stmt := ss.stmts.page[reverse][bounded]
+	if tx != nil {
+		stmt = tx.Stmt(stmt)
+	}
+	return stmt.Query(append(args, limit)...)
+}
+
+// Get the value associated with the key
+func (ss *SQLiteStore) Get(key string) ([]byte, bool, error) {
+	ss.ops.CountGets(1)
+	if key == "" {
+		return nil, false, fmt.Errorf("key cannot be empty")
+	}
+	if err := ss.open(); err != nil {
+		return nil, false, err
+	}
+	return ss.lookup(nil, key)
+}
+
+// Store the value associated with the key
+func (ss *SQLiteStore) Put(key string, value []byte) error {
+	ss.ops.CountPuts(1)
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	if err := ss.open(); err != nil {
+		return err
+	}
+	_, err := ss.stmts.put.Exec([]byte(key), nonNil(value))
+	return err
+}
+
+// Remove the key and its associated value from the store
+func (ss *SQLiteStore) Delete(key string) error {
+	ss.ops.CountDeletes(1)
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	if err := ss.open(); err != nil {
+		return err
+	}
+	_, err := ss.stmts.delete.Exec([]byte(key))
+	return err
+}
+
+// Retrieve all key-value pairs that start with the given prefix
+func (ss *SQLiteStore) Scan(prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	err := ss.ForEach(prefix, func(key string, value []byte) error {
+		result[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ForEach calls fn with every pair whose key starts with prefix, in key
+// order, within a single query
+func (ss *SQLiteStore) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	ss.ops.CountScan()
+	if err := ss.open(); err != nil {
+		return err
+	}
+	rows, err := ss.query(nil, store.PrefixRange(prefix), -1)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var key, value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		if err := fn(string(key), nonNil(value)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Stats reports the number of keys and the operations served. Counting the
+// keys reads the whole table.
+func (ss *SQLiteStore) Stats() (store.Stats, error) {
+	if err := ss.open(); err != nil {
+		return store.Stats{}, err
+	}
+	var stats store.Stats
+	if err := ss.stmts.count.QueryRow().Scan(&stats.EstimatedKeys); err != nil {
+		return store.Stats{}, err
+	}
+	stats.Operations = ss.ops.OperationCounts()
+	return stats, nil
+}
+
+// Increment adds delta to the counter at key within a single transaction
+func (ss *SQLiteStore) Increment(key string, delta int64, encoding store.CounterEncoding) (int64, error) {
+	ss.ops.CountPuts(1)
+	if key == "" {
+		return 0, fmt.Errorf("key cannot be empty")
+	}
+
+	var next int64
+	err := ss.update(func(tx *sql.Tx) error {
+		current, _, err := ss.lookup(tx, key)
+		if err != nil {
+			return err
+		}
+		value, encoded, err := store.AddCounter(encoding, current, delta)
+		if err != nil {
+			return err
+		}
+		next = value
+		_, err = tx.Stmt(ss.stmts.put).Exec([]byte(key), encoded)
+		return err
+	})
+	return next, err
+}
+
+// DeletePrefix deletes every key under prefix in a single transaction. An
+// empty prefix deletes every key.
+func (ss *SQLiteStore) DeletePrefix(prefix string, deleted func(key string)) (int64, error) {
+	var n int64
+	err := ss.update(func(tx *sql.Tx) error {
+		// The keys are collected first, as the query reads the table that
+		// the deletes change
+		rows, err := ss.query(tx, store.PrefixRange(prefix), -1)
+		if err != nil {
+			return err
+		}
+		var keys [][]byte
+		for rows.Next() {
+			var key, value []byte
+			if err := rows.Scan(&key, &value); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			keys = append(keys, key)
+			if deleted != nil {
+				deleted(string(key))
+			}
+		}
+		if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+			return err
+		}
+
+		del := tx.Stmt(ss.stmts.delete)
+		for _, key := range keys {
+			if _, err := del.Exec(key); err != nil {
+				return err
+			}
+		}
+		n = int64(len(keys))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	ss.ops.CountDeletes(int(n))
+	return n, nil
+}
+
+var (
+	_ store.Store         = (*SQLiteStore)(nil)
+	_ store.Pinger        = (*SQLiteStore)(nil)
+	_ store.Incrementer   = (*SQLiteStore)(nil)
+	_ store.ForEacher     = (*SQLiteStore)(nil)
+	_ store.PrefixDeleter = (*SQLiteStore)(nil)
+)
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// DefaultTable is the table the pairs are kept in when none is configured.
+	DefaultTable = "clavis"
+	// DefaultBusyTimeout bounds how long a write waits for another writer.
+	DefaultBusyTimeout = 5 * time.Second
+)
+
+// SQLiteStoreConfig holds the configuration options for SQLite
+type SQLiteStoreConfig struct {
+	store.StoreConfig        // Embedded struct with common config
+	Path              string // SQLite-specific: database file path
+	Table             string // SQLite-specific: table holding the pairs; DefaultTable if empty
+
+	// WAL journals writes in a write-ahead log, so reads do not wait for
+	// writes, instead of the default rollback journal
+	WAL bool
+	// Synchronous is how often SQLite waits for writes to reach the disk:
+	// "OFF", "NORMAL" or "FULL"; FULL if empty. NORMAL only risks the latest
+	// commits on a power loss with WAL, and corruption without it.
+	Synchronous string
+	// BusyTimeout is how long a write waits for another connection to finish
+	// writing; DefaultBusyTimeout if zero
+	BusyTimeout time.Duration
+}
+
+// DefaultConfig returns a SQLiteStoreConfig with sensible defaults
+func DefaultConfig(path string) *SQLiteStoreConfig {
+	return &SQLiteStoreConfig{
+		StoreConfig: store.StoreConfig{
+			LoggingLevel:      3, // ERROR level
+			NumVersionsToKeep: 1,
+		},
+		Path:        path,
+		Table:       DefaultTable,
+		WAL:         true,
+		Synchronous: "NORMAL",
+		BusyTimeout: DefaultBusyTimeout,
+	}
+}
+
+// tableName matches the table names used unquoted in statements
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ToDSN converts SQLiteStoreConfig to a data source name for the sqlite
+// driver. The pragmas are set on every connection the pool opens, and
+// transactions take the write lock as they begin, so a transaction that
+// reads before writing cannot fail to upgrade its lock.
+func (c *SQLiteStoreConfig) ToDSN() (string, error) {
+	if c.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+	synchronous := strings.ToUpper(c.Synchronous)
+	switch synchronous {
+	case "":
+		synchronous = "FULL"
+	case "OFF", "NORMAL", "FULL":
+	default:
+		return "", fmt.Errorf("invalid synchronous mode %q", c.Synchronous)
+	}
+	busyTimeout := c.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultBusyTimeout
+	}
+	journal := "DELETE"
+	if c.WAL {
+		journal = "WAL"
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout("+fmt.Sprint(busyTimeout.Milliseconds())+")")
+	params.Add("_pragma", "journal_mode("+journal+")")
+	params.Add("_pragma", "synchronous("+synchronous+")")
+	params.Set("_txlock", "immediate")
+	return "file:" + c.Path + "?" + params.Encode(), nil
+}
+
+// table returns the table holding the pairs
+func (c *SQLiteStoreConfig) table() (string, error) {
+	table := c.Table
+	if table == "" {
+		table = DefaultTable
+	}
+	if !tableName.MatchString(table) {
+		return "", fmt.Errorf("invalid table name %q", table)
+	}
+	return table, nil
+}
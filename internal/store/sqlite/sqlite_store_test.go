@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+func createTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	ss, err := NewWithPath(filepath.Join(t.TempDir(), "clavis.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ss.Close() })
+	return ss
+}
+
+func TestSQLiteStore_Configuration(t *testing.T) {
+	t.Run("NilConfigurationError", func(t *testing.T) {
+		if _, err := New(nil); err == nil {
+			t.Error("Expected error for nil config")
+		}
+	})
+
+	t.Run("InvalidConfiguration", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clavis.sqlite")
+		for _, config := range []*SQLiteStoreConfig{
+			{},
+			{Path: path, Table: "pairs; DROP TABLE pairs"},
+			{Path: path, Synchronous: "EXTRA"},
+		} {
+			if _, err := New(config); err == nil {
+				t.Errorf("Expected error for config %+v", config)
+			}
+		}
+	})
+
+	t.Run("JournalMode", func(t *testing.T) {
+		for _, tt := range []struct {
+			wal  bool
+			want string
+		}{
+			{true, "wal"},
+			{false, "delete"},
+		} {
+			config := DefaultConfig(filepath.Join(t.TempDir(), "clavis.sqlite"))
+			config.WAL = tt.wal
+			ss, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var mode string
+			if err := ss.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+				t.Fatal(err)
+			}
+			if strings.ToLower(mode) != tt.want {
+				t.Errorf("journal_mode with WAL %v = %s, want %s", tt.wal, mode, tt.want)
+			}
+			_ = ss.Close()
+		}
+	})
+
+	t.Run("TablesAreSeparate", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clavis.sqlite")
+		first, err := New(&SQLiteStoreConfig{Path: path, Table: "first"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Put("key", []byte("first")); err != nil {
+			t.Fatal(err)
+		}
+		if err := first.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		second, err := New(&SQLiteStoreConfig{Path: path, Table: "second", Synchronous: "off"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = second.Close() }()
+		if _, found, err := second.Get("key"); err != nil || found {
+			t.Errorf("Expected the key of another table to be missing, got %v %v", found, err)
+		}
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clavis.sqlite")
+		ss, err := NewWithPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ss.Put("key", []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ss.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened, err := NewWithPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = reopened.Close() }()
+		if value, found, err := reopened.Get("key"); err != nil || !found || string(value) != "value" {
+			t.Errorf("Expected the value to survive reopening, got %q %v %v", value, found, err)
+		}
+	})
+}
+
+func TestSQLiteStore_Operations(t *testing.T) {
+	ss := createTestStore(t)
+
+	if err := ss.Put("user:1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Put("user:2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Put("product:1", []byte("book")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Put("user:1", []byte("alice@example.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found, err := ss.Get("user:1"); err != nil || !found || string(value) != "alice@example.com" {
+		t.Errorf("Get() = %q, %v, %v; want the overwritten value", value, found, err)
+	}
+	if value, found, err := ss.Get("user:2"); err != nil || !found || value == nil || len(value) != 0 {
+		t.Errorf("Get() of an empty value = %q, %v, %v; want an empty value", value, found, err)
+	}
+	if _, found, err := ss.Get("user:3"); err != nil || found {
+		t.Errorf("Get() of a missing key = %v, %v; want not found", found, err)
+	}
+	if _, _, err := ss.Get(""); err == nil {
+		t.Error("Expected error for empty key")
+	}
+
+	pairs, err := ss.Scan("user:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || string(pairs["user:1"]) != "alice@example.com" {
+		t.Errorf("Scan() = %q, want both users", pairs)
+	}
+
+	if err := ss.Delete("user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := ss.Get("user:1"); found {
+		t.Error("Expected the deleted key to be missing")
+	}
+
+	stats, err := ss.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.EstimatedKeys != 2 || stats.Operations.Puts != 4 || stats.Operations.Deletes != 1 {
+		t.Errorf("Stats() = %+v, want 2 keys after 4 puts and 1 delete", stats)
+	}
+
+	if err := ss.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ss.Ping(); err == nil {
+		t.Error("Expected Ping to fail on a closed store")
+	}
+	if err := ss.Put("key", []byte("value")); err == nil {
+		t.Error("Expected error when writing to a closed store")
+	}
+}
+
+func TestSQLiteStore_Batch(t *testing.T) {
+	ss := createTestStore(t)
+
+	if err := ss.BatchPut(map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := ss.BatchGet([]string{"a", "c", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || string(values["a"]) != "1" || string(values["c"]) != "3" {
+		t.Errorf("BatchGet() = %q, want a and c", values)
+	}
+	if err := ss.BatchDelete([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, err := ss.Scan(""); err != nil || len(pairs) != 1 {
+		t.Errorf("Scan() after BatchDelete() = %q, %v; want only c", pairs, err)
+	}
+	if err := ss.BatchPut(map[string][]byte{"d": nil, "": nil}); err == nil {
+		t.Error("Expected a batch with an empty key to be rejected")
+	}
+	if _, found, _ := ss.Get("d"); found {
+		t.Error("Expected no pair of a rejected batch to be stored")
+	}
+}
+
+func TestSQLiteStore_Increment(t *testing.T) {
+	ss := createTestStore(t)
+
+	for want := int64(1); want <= 3; want++ {
+		if n, err := store.Increment(t.Context(), ss, "hits", 1, store.CounterASCII); err != nil || n != want {
+			t.Fatalf("Increment() = %d, %v; want %d", n, err, want)
+		}
+	}
+	if err := ss.Put("name", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.Increment("name", 1, store.CounterASCII); !errors.Is(err, store.ErrNotCounter) {
+		t.Errorf("Increment() of a value that is not a counter = %v, want ErrNotCounter", err)
+	}
+}
+
+func TestSQLiteStore_DeletePrefix(t *testing.T) {
+	ss := createTestStore(t)
+	if err := ss.BatchPut(map[string][]byte{"tenant-a/1": nil, "tenant-a/2": nil, "tenant-b/1": []byte("kept")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var deleted []string
+	n, err := ss.DeletePrefix("tenant-a/", func(key string) { deleted = append(deleted, key) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(deleted) != 2 || deleted[0] != "tenant-a/1" {
+		t.Errorf("DeletePrefix() = %d reporting %q, want tenant-a/1 and tenant-a/2", n, deleted)
+	}
+	if left, err := ss.Scan(""); err != nil || len(left) != 1 || string(left["tenant-b/1"]) != "kept" {
+		t.Errorf("Scan() after DeletePrefix() = %q, %v; want only tenant-b/1", left, err)
+	}
+}
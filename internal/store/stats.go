@@ -0,0 +1,53 @@
+package store
+
+import "sync/atomic"
+
+// Stats describes the contents and resource usage of a store, for capacity
+// monitoring. Sizes a store does not have are zero.
+type Stats struct {
+	// EstimatedKeys is the approximate number of keys. Stores that cannot
+	// count them cheaply may include deleted and overwritten keys that were
+	// not reclaimed yet, and leave out recent writes.
+	EstimatedKeys int64
+	MemoryBytes   int64 // Size of the keys and values held in memory
+	LSMBytes      int64 // Size of the LSM tree on disk
+	ValueLogBytes int64 // Size of the value log on disk
+	Operations    OperationCounts
+}
+
+// OperationCounts counts the operations a store served since it was opened.
+// Batches count one operation per key, and iterators count as scans.
+type OperationCounts struct {
+	Gets    uint64
+	Puts    uint64
+	Deletes uint64
+	Scans   uint64
+}
+
+// OperationCounters is embedded by stores to count the operations they serve.
+// It is safe for concurrent use.
+type OperationCounters struct {
+	gets, puts, deletes, scans atomic.Uint64
+}
+
+// CountGets records n key reads.
+func (c *OperationCounters) CountGets(n int) { c.gets.Add(uint64(n)) } // #nosec G115 -- counts are non-negative
+
+// CountPuts records n key writes.
+func (c *OperationCounters) CountPuts(n int) { c.puts.Add(uint64(n)) } // #nosec G115 -- counts are non-negative
+
+// CountDeletes records n key deletions.
+func (c *OperationCounters) CountDeletes(n int) { c.deletes.Add(uint64(n)) } // #nosec G115 -- counts are non-negative
+
+// CountScan records a scan.
+func (c *OperationCounters) CountScan() { c.scans.Add(1) }
+
+// OperationCounts returns the counts so far.
+func (c *OperationCounters) OperationCounts() OperationCounts {
+	return OperationCounts{
+		Gets:    c.gets.Load(),
+		Puts:    c.puts.Load(),
+		Deletes: c.deletes.Load(),
+		Scans:   c.scans.Load(),
+	}
+}
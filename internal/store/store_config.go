@@ -1,9 +1,12 @@
 package store
 
+import "log/slog"
+
 // Common configuration for all store implementations
 type StoreConfig struct {
-	LoggingLevel      int // 0=DEBUG, 1=INFO, 2=WARNING, 3=ERROR
-	NumVersionsToKeep int // Number of versions to keep for each key
+	LoggingLevel      int          // 0=DEBUG, 1=INFO, 2=WARNING, 3=ERROR
+	NumVersionsToKeep int          // Number of versions to keep for each key
+	Logger            *slog.Logger // Structured logger for store records; slog.Default() if nil
 }
 
 // Get the logging level for the store
@@ -0,0 +1,683 @@
+// Package tags wraps a store so that keys can carry string tags and be
+// looked up by tag without scanning the store.
+//
+// The tags of a write are taken from its context, set with NewContext. Each
+// tagged key has a record of its tags under RecordPrefix, and each of its
+// tags an index entry under IndexPrefix pointing back to it. The value, the
+// record and the index entries are written in a single batch, and removed in
+// another, so the index never disagrees with the keys. As a batch cannot mix
+// writes and deletes, the entries of tags a key no longer has are marked as
+// stale rather than deleted, listed in the record, and deleted with the key.
+//
+// Writes without tags keep the tags of the key, and reads through the wrapper
+// never see the reserved keys.
+package tags
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+const (
+	// KeyPrefix starts the keys the wrapper keeps its records and index in.
+	KeyPrefix = "__clavis/tags/"
+	// RecordPrefix starts the key of the record of the tags of each key.
+	RecordPrefix = KeyPrefix + "keys/"
+	// IndexPrefix starts the index entries, each the tag, a NUL byte and
+	// the key.
+	IndexPrefix = KeyPrefix + "index/"
+
+	// MaxTags is the number of tags a key can carry.
+	MaxTags = 64
+	// MaxTagLength is the length of a tag in bytes.
+	MaxTagLength = 256
+
+	// lockStripes is the number of locks writes are spread over by key.
+	lockStripes = 64
+)
+
+var (
+	// ErrReservedKey is returned for writes to the keys under KeyPrefix,
+	// which are hidden from reads.
+	ErrReservedKey = errors.New("key is reserved for the tag index")
+	// ErrInvalidTag is returned for writes with an empty, overlong or
+	// NUL-containing tag, or too many tags.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrNotIndexed is returned by From when no store indexes tags.
+	ErrNotIndexed = errors.New("tags are not indexed")
+	// ErrCorruptRecord is returned when the record of the tags of a key
+	// cannot be decoded.
+	ErrCorruptRecord = errors.New("corrupt tag record")
+)
+
+// Index entries hold whether the key still carries the tag.
+var (
+	live  = []byte{1}
+	stale = []byte{0}
+)
+
+// recordV1 starts every record, followed by the number of live tags and
+// stale tags and each tag prefixed with its length, as uvarints.
+const recordV1 byte = 1
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx whose writes tag the keys they write
+// with names, replacing the tags the keys had. Writes whose context carries
+// no tags keep them.
+func NewContext(ctx context.Context, names []string) context.Context {
+	return context.WithValue(ctx, contextKey{}, slices.Clone(names))
+}
+
+// FromContext returns the tags set on ctx by NewContext.
+func FromContext(ctx context.Context) ([]string, bool) {
+	names, ok := ctx.Value(contextKey{}).([]string)
+	return names, ok
+}
+
+// Store maintains the tag index of the wrapped store.
+type Store struct {
+	store.Passthrough
+	// locks serialize the writes to the same keys, so the record a write
+	// reads is still current when it writes the next one.
+	locks [lockStripes]sync.Mutex
+}
+
+// New wraps base.
+func New(base store.Store) *Store {
+	return &Store{Passthrough: store.Passthrough{Store: base}}
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.Expirer               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+)
+
+func reserved(key string) bool {
+	return strings.HasPrefix(key, KeyPrefix)
+}
+
+func checkKeys(keys ...string) error {
+	for _, key := range keys {
+		if reserved(key) {
+			return fmt.Errorf("%w: %q", ErrReservedKey, key)
+		}
+	}
+	return nil
+}
+
+// checkTags returns names sorted and without duplicates, failing with
+// ErrInvalidTag if any of them cannot be indexed.
+func checkTags(names []string) ([]string, error) {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(names) > MaxTags {
+		return nil, fmt.Errorf("%w: %d tags, at most %d allowed", ErrInvalidTag, len(names), MaxTags)
+	}
+	for _, name := range names {
+		if err := checkTag(name); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+func checkTag(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: tag cannot be empty", ErrInvalidTag)
+	case len(name) > MaxTagLength:
+		return fmt.Errorf("%w: tag longer than %d bytes", ErrInvalidTag, MaxTagLength)
+	case strings.IndexByte(name, 0) >= 0:
+		return fmt.Errorf("%w: tag %q contains a NUL byte", ErrInvalidTag, name)
+	}
+	return nil
+}
+
+func recordKey(key string) string {
+	return RecordPrefix + key
+}
+
+func indexKey(tag, key string) string {
+	return IndexPrefix + tag + "\x00" + key
+}
+
+// record holds the tags of a key, and the tags it no longer has whose index
+// entries are still to be deleted.
+type record struct {
+	live  []string
+	stale []string
+}
+
+func (r record) encode() []byte {
+	stored := []byte{recordV1}
+	stored = binary.AppendUvarint(stored, uint64(len(r.live)))
+	stored = binary.AppendUvarint(stored, uint64(len(r.stale)))
+	for _, name := range slices.Concat(r.live, r.stale) {
+		stored = binary.AppendUvarint(stored, uint64(len(name)))
+		stored = append(stored, name...)
+	}
+	return stored
+}
+
+func decodeRecord(key string, stored []byte) (record, error) {
+	corrupt := fmt.Errorf("key %q: %w", key, ErrCorruptRecord)
+	if len(stored) == 0 || stored[0] != recordV1 {
+		return record{}, corrupt
+	}
+	r := bytes.NewReader(stored[1:])
+	counts := make([]uint64, 2)
+	for i := range counts {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(len(stored)) {
+			return record{}, corrupt
+		}
+		counts[i] = n
+	}
+	names := make([]string, 0, counts[0]+counts[1])
+	for range counts[0] + counts[1] {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return record{}, corrupt
+		}
+		name := make([]byte, n)
+		_, _ = r.Read(name)
+		names = append(names, string(name))
+	}
+	return record{live: names[:counts[0]], stale: names[counts[0]:]}, nil
+}
+
+// indexKeys returns the keys of every index entry of key in r.
+func (r record) indexKeys(key string) []string {
+	keys := make([]string, 0, len(r.live)+len(r.stale))
+	for _, name := range slices.Concat(r.live, r.stale) {
+		keys = append(keys, indexKey(name, key))
+	}
+	return keys
+}
+
+// retag returns the record of a key tagged with names that had r, along
+// with the index entries to write.
+func (r record) retag(key string, names []string) (record, map[string][]byte) {
+	next := record{live: names}
+	entries := make(map[string][]byte, len(names)+len(r.live))
+	for _, name := range names {
+		entries[indexKey(name, key)] = live
+	}
+	for _, name := range slices.Concat(r.live, r.stale) {
+		if !slices.Contains(names, name) && !slices.Contains(next.stale, name) {
+			next.stale = append(next.stale, name)
+			entries[indexKey(name, key)] = stale
+		}
+	}
+	return next, entries
+}
+
+// lock locks the stripes of keys, returning the function unlocking them.
+func (s *Store) lock(keys ...string) (unlock func()) {
+	stripes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		stripes = append(stripes, int(h.Sum32()%lockStripes))
+	}
+	slices.Sort(stripes)
+	stripes = slices.Compact(stripes)
+	for _, stripe := range stripes {
+		s.locks[stripe].Lock()
+	}
+	return func() {
+		for _, stripe := range stripes {
+			s.locks[stripe].Unlock()
+		}
+	}
+}
+
+// records reads the records of keys, which are absent for untagged keys.
+func (s *Store) records(keys []string) (map[string]record, error) {
+	recordKeys := make([]string, len(keys))
+	for i, key := range keys {
+		recordKeys[i] = recordKey(key)
+	}
+	stored, err := store.BatchGet(s.Store, recordKeys)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]record, len(stored))
+	for _, key := range keys {
+		if value, ok := stored[recordKey(key)]; ok {
+			if records[key], err = decodeRecord(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, nil
+}
+
+// putTagged stores pairs in one batch with their records and index entries
+// for names. The stripes of the keys must be locked.
+func (s *Store) putTagged(ctx context.Context, pairs map[string][]byte, names []string) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	records, err := s.records(keys)
+	if err != nil {
+		return err
+	}
+	batch := make(map[string][]byte, len(pairs)*(2+len(names)))
+	for key, value := range pairs {
+		next, entries := records[key].retag(key, names)
+		batch[key] = value
+		if len(next.live) > 0 || len(next.stale) > 0 {
+			batch[recordKey(key)] = next.encode()
+		}
+		for entry, value := range entries {
+			batch[entry] = value
+		}
+	}
+	return store.BatchPutContext(ctx, s.Store, batch)
+}
+
+// Put stores the value, tagging the key when the context of the write is
+// tagged.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext is Put on behalf of the request in ctx, whose tags replace
+// those of the key.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	names, tagged := FromContext(ctx)
+	names, err := checkTags(names)
+	if err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	if !tagged {
+		return store.PutContext(ctx, s.Store, key, value)
+	}
+	if err := s.putTagged(ctx, map[string][]byte{key: value}, names); err != nil {
+		return err
+	}
+	return s.recordVersion(ctx, key)
+}
+
+// PutIfVersion conditionally stores the value.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx. A
+// tagged write checks the version of the key before writing the batch, which
+// only writes through the wrapper are kept from changing meanwhile.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	names, tagged := FromContext(ctx)
+	names, err := checkTags(names)
+	if err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	if !tagged {
+		return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+	}
+	if err := s.checkVersion(ctx, key, expected); err != nil {
+		return err
+	}
+	if err := s.putTagged(ctx, map[string][]byte{key: value}, names); err != nil {
+		return err
+	}
+	return s.recordVersion(ctx, key)
+}
+
+// checkVersion fails with store.ErrVersionConflict unless key is at the
+// expected version, where 0 means it must not exist.
+func (s *Store) checkVersion(ctx context.Context, key string, expected uint64) error {
+	var version uint64
+	var found bool
+	var err error
+	if reader, ok := store.As[store.VersionReader](s.Store); ok {
+		_, version, found, err = reader.GetAt(key, 0)
+	} else if expected != 0 {
+		return store.ErrConditionalPutUnsupported
+	} else {
+		_, found, err = store.GetContext(ctx, s.Store, key)
+	}
+	if err != nil {
+		return err
+	}
+	if (expected == 0 && found) || (expected != 0 && (!found || version != expected)) {
+		return store.ErrVersionConflict
+	}
+	return nil
+}
+
+// recordVersion records the version of a tagged write of key in ctx, when
+// it asks for one. The stripe of key must still be locked.
+func (s *Store) recordVersion(ctx context.Context, key string) error {
+	if !store.RecordsVersion(ctx) {
+		return nil
+	}
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil
+	}
+	_, version, _, err := reader.GetAt(key, 0)
+	if err != nil {
+		return err
+	}
+	store.RecordVersion(ctx, version)
+	return nil
+}
+
+// BatchPut stores the pairs.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx, whose tags
+// replace those of every key.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	names, tagged := FromContext(ctx)
+	names, err := checkTags(names)
+	if err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	if tagged {
+		return s.putTagged(ctx, pairs, names)
+	}
+	return store.BatchPutContext(ctx, s.Store, pairs)
+}
+
+// Delete removes the key along with its tags.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	if err := checkKeys(key); err != nil {
+		return err
+	}
+	defer s.lock(key)()
+	keys, err := s.withIndex([]string{key})
+	if err != nil {
+		return err
+	}
+	if len(keys) == 1 {
+		return store.DeleteContext(ctx, s.Store, key)
+	}
+	return store.BatchDelete(s.Store, keys)
+}
+
+// BatchDelete removes the keys along with their tags.
+func (s *Store) BatchDelete(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	keys, err := s.withIndex(keys)
+	if err != nil {
+		return err
+	}
+	return store.BatchDelete(s.Store, keys)
+}
+
+// ExpireKeys removes the keys as expired along with their tags.
+func (s *Store) ExpireKeys(keys []string) error {
+	if err := checkKeys(keys...); err != nil {
+		return err
+	}
+	defer s.lock(keys...)()
+	keys, err := s.withIndex(keys)
+	if err != nil {
+		return err
+	}
+	return store.ExpireKeys(s.Store, keys)
+}
+
+// withIndex returns keys followed by the keys of their records and index
+// entries. The stripes of keys must be locked.
+func (s *Store) withIndex(keys []string) ([]string, error) {
+	records, err := s.records(keys)
+	if err != nil {
+		return nil, err
+	}
+	for key, r := range records {
+		keys = append(append(keys, recordKey(key)), r.indexKeys(key)...)
+	}
+	return keys, nil
+}
+
+// Tags returns the tags of key, sorted, reporting false if it has none.
+func (s *Store) Tags(key string) ([]string, bool, error) {
+	records, err := s.records([]string{key})
+	if err != nil {
+		return nil, false, err
+	}
+	r, ok := records[key]
+	if !ok || len(r.live) == 0 {
+		return nil, false, nil
+	}
+	return r.live, true, nil
+}
+
+// Keys returns the keys tagged with tag that start with prefix, in key order,
+// beginning after the key after if not empty and stopping at limit keys if
+// not zero.
+func (s *Store) Keys(tag, prefix, after string, limit int) ([]string, error) {
+	if err := checkTag(tag); err != nil {
+		return nil, err
+	}
+	r := store.PrefixRange(indexKey(tag, prefix))
+	if start := indexKey(tag, after) + "\x00"; after != "" && start > r.Start {
+		r.Start = start
+	}
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	entryPrefix := indexKey(tag, "")
+	var keys []string
+	for (limit == 0 || len(keys) < limit) && it.Next() {
+		if bytes.Equal(it.Value(), live) {
+			keys = append(keys, strings.TrimPrefix(it.Key(), entryPrefix))
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// From returns the tag index beneath s, failing with ErrNotIndexed if there
+// is none.
+func From(s store.Store) (*Store, error) {
+	index, ok := store.As[*Store](s)
+	if !ok {
+		return nil, ErrNotIndexed
+	}
+	return index, nil
+}
+
+// Get reads the value of key, unless it is reserved.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	if reserved(key) {
+		return nil, false, nil
+	}
+	return store.GetContext(ctx, s.Store, key)
+}
+
+// GetView calls fn with the value of key, unless it is reserved.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	if reserved(key) {
+		return false, nil
+	}
+	return store.GetView(s.Store, key, fn)
+}
+
+// Scan reads the pairs whose keys start with prefix, except the reserved
+// ones.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return hideReserved(pairs), nil
+}
+
+// BatchGet reads the values of keys, except the reserved ones.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	pairs, err := store.BatchGet(s.Store, keys)
+	if err != nil {
+		return nil, err
+	}
+	return hideReserved(pairs), nil
+}
+
+// hideReserved drops the reserved keys from pairs, in place.
+func hideReserved(pairs map[string][]byte) map[string][]byte {
+	for key := range pairs {
+		if reserved(key) {
+			delete(pairs, key)
+		}
+	}
+	return pairs
+}
+
+// NewIterator streams the pairs whose keys start with prefix, except the
+// reserved ones.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// ForEach visits the pairs whose keys start with prefix, except the reserved
+// ones.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	return store.ForEach(s.Store, prefix, func(key string, value []byte) error {
+		if reserved(key) {
+			return nil
+		}
+		return fn(key, value)
+	})
+}
+
+// NewRangeIterator streams the pairs whose keys are in r, except the
+// reserved ones.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// GetAt reads the value of key as of version, failing with
+// store.ErrSnapshotsUnsupported if the wrapped store keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	if reserved(key) {
+		return nil, 0, false, nil
+	}
+	return reader.GetAt(key, version)
+}
+
+// VersionAt resolves t with the wrapped store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt streams the pairs whose keys start with prefix as they were
+// at version, except the reserved ones.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	it, err := store.NewIteratorAt(s.Store, prefix, version)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{Iterator: it}, nil
+}
+
+// EvaluateFilter presents the pairs of the wrapped store to filter, except
+// the reserved ones, which no filter drops. The dropped keys must be removed
+// through the wrapper for their tags to go with them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	evaluator, ok := store.As[store.FilterEvaluator](s.Store)
+	if !ok {
+		return store.EvaluateFilter(s, filter, now)
+	}
+	return evaluator.EvaluateFilter(store.CompactionFilterFunc(func(entry store.FilterEntry, now time.Time) (bool, error) {
+		if reserved(entry.Key) {
+			return false, nil
+		}
+		return filter.Drop(entry, now)
+	}), now)
+}
+
+// iterator skips the reserved keys of the wrapped iterator.
+type iterator struct {
+	store.Iterator
+}
+
+func (it *iterator) Next() bool {
+	for it.Iterator.Next() {
+		if !reserved(it.Iterator.Key()) {
+			return true
+		}
+	}
+	return false
+}
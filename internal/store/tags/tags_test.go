@@ -0,0 +1,189 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func newStore(t *testing.T) (*Store, *memory.MemoryStore) {
+	t.Helper()
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(base), base
+}
+
+func assertKeys(t *testing.T, s *Store, tag string, want ...string) {
+	t.Helper()
+	keys, err := s.Keys(tag, "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys, want) {
+		t.Errorf("Keys(%q) = %v, want %v", tag, keys, want)
+	}
+}
+
+func TestStore_Index(t *testing.T) {
+	s, _ := newStore(t)
+	ctx := context.Background()
+
+	if err := s.PutContext(NewContext(ctx, []string{"red", "big"}), "users/1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPutContext(NewContext(ctx, []string{"red"}), map[string][]byte{"users/2": []byte("bob"), "users/3": []byte("carol")}); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, s, "red", "users/1", "users/2", "users/3")
+	assertKeys(t, s, "big", "users/1")
+
+	// Writes without tags keep them; writes with tags replace them
+	if err := s.Put("users/1", []byte("alicia")); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, s, "big", "users/1")
+	if err := s.PutContext(NewContext(ctx, []string{"blue", "big"}), "users/1", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, s, "red", "users/2", "users/3")
+	assertKeys(t, s, "blue", "users/1")
+	if names, found, err := s.Tags("users/1"); err != nil || !found || !slices.Equal(names, []string{"big", "blue"}) {
+		t.Errorf("Tags() = %v, %v, %v; want [big blue]", names, found, err)
+	}
+
+	// Pagination and prefixes
+	if keys, _ := s.Keys("red", "users/", "users/2", 0); !slices.Equal(keys, []string{"users/3"}) {
+		t.Errorf("Keys() after users/2 = %v, want [users/3]", keys)
+	}
+	if keys, _ := s.Keys("red", "", "", 1); !slices.Equal(keys, []string{"users/2"}) {
+		t.Errorf("Keys() limited to 1 = %v, want [users/2]", keys)
+	}
+	if keys, _ := s.Keys("red", "users/3", "", 0); !slices.Equal(keys, []string{"users/3"}) {
+		t.Errorf("Keys() under users/3 = %v, want [users/3]", keys)
+	}
+
+	// Deletes remove the key from the index
+	if err := s.Delete("users/2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ExpireKeys([]string{"users/1"}); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, s, "red", "users/3")
+	assertKeys(t, s, "blue")
+	assertKeys(t, s, "big")
+}
+
+func TestStore_HidesIndex(t *testing.T) {
+	s, base := newStore(t)
+	if err := s.PutContext(NewContext(context.Background(), []string{"red"}), "a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := base.Get(recordKey("a")); !found {
+		t.Fatal("Expected the record in the wrapped store")
+	}
+
+	pairs, err := s.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Errorf("Scan() = %v, want only the tagged key", pairs)
+	}
+	if n, err := store.Count(context.Background(), s, ""); err != nil || n != 1 {
+		t.Errorf("Count() = %d, %v; want 1", n, err)
+	}
+	if _, found, _ := s.Get(recordKey("a")); found {
+		t.Error("Expected the record to be hidden")
+	}
+	if err := s.Put(indexKey("red", "b"), []byte{1}); !errors.Is(err, ErrReservedKey) {
+		t.Errorf("Expected ErrReservedKey, got %v", err)
+	}
+	for _, names := range [][]string{{""}, {"a\x00b"}, make([]string, MaxTags+1)} {
+		if err := s.PutContext(NewContext(context.Background(), names), "a", nil); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("Expected ErrInvalidTag for %q, got %v", names, err)
+		}
+	}
+}
+
+func TestStore_Conditional(t *testing.T) {
+	base, err := badger.NewWithPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = base.Close() })
+	s := New(base)
+	ctx, version := store.WithVersionRecord(NewContext(context.Background(), []string{"red"}))
+
+	if err := store.PutIfAbsentContext(ctx, s, "a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if version() == 0 {
+		t.Error("Expected the version of the tagged write to be recorded")
+	}
+	if err := store.PutIfAbsentContext(ctx, s, "a", []byte("2")); !errors.Is(err, store.ErrKeyExists) {
+		t.Errorf("Expected ErrKeyExists, got %v", err)
+	}
+	blue := NewContext(context.Background(), []string{"blue"})
+	if err := s.PutIfVersionContext(blue, "a", []byte("2"), version()+1); !errors.Is(err, store.ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+	if err := s.PutIfVersionContext(blue, "a", []byte("2"), version()); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, s, "red")
+	assertKeys(t, s, "blue", "a")
+}
+
+func TestStore_ConcurrentRetags(t *testing.T) {
+	s, _ := newStore(t)
+	var wg sync.WaitGroup
+	for _, tag := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if err := s.PutContext(NewContext(context.Background(), []string{tag}), "key", []byte(tag)); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Exactly the tag of the last write is indexed
+	value, _, _ := s.Get("key")
+	for _, tag := range []string{"a", "b", "c", "d"} {
+		keys, err := s.Keys(tag, "", "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tag == string(value); (len(keys) == 1) != want {
+			t.Errorf("Keys(%q) = %v with value %q", tag, keys, value)
+		}
+	}
+	if err := s.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, _ := s.Store.Scan(KeyPrefix); len(pairs) != 0 {
+		t.Errorf("Expected the record and every index entry to be deleted with the key, got %v", pairs)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	s, base := newStore(t)
+	if _, err := From(base); !errors.Is(err, ErrNotIndexed) {
+		t.Errorf("Expected ErrNotIndexed without the wrapper, got %v", err)
+	}
+	if got, err := From(store.Passthrough{Store: s}); err != nil || got != s {
+		t.Errorf("From() = %v, %v; want the wrapper beneath", got, err)
+	}
+}
@@ -0,0 +1,79 @@
+package tiered
+
+import "github.com/William-Fernandes252/clavis/internal/store"
+
+// pair is a pending write to a key.
+type pair struct {
+	key     string
+	value   []byte
+	deleted bool
+}
+
+// iterator merges the pairs of the back store with pending writes, both in
+// key order, or both in descending order if reverse. A pending write shadows
+// the pair of the back store with the same key, and a pending deletion hides
+// it.
+type iterator struct {
+	back     store.Iterator
+	overlay  []pair
+	reverse  bool
+	next     int  // Index of the next pending write
+	peeked   bool // The back iterator is positioned on a pair not returned yet
+	backDone bool
+
+	key   string
+	value []byte
+}
+
+func (it *iterator) Next() bool {
+	for {
+		if !it.peeked && !it.backDone {
+			it.peeked = it.back.Next()
+			it.backDone = !it.peeked
+		}
+
+		switch {
+		case it.next < len(it.overlay) && (!it.peeked || it.first(it.overlay[it.next].key, it.back.Key())):
+			p := it.overlay[it.next]
+			it.next++
+			if it.peeked && p.key == it.back.Key() {
+				it.peeked = false
+			}
+			if p.deleted {
+				continue
+			}
+			it.key, it.value = p.key, p.value
+			return true
+		case it.peeked:
+			it.peeked = false
+			it.key, it.value = it.back.Key(), it.back.Value()
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// first reports whether a comes no later than b in the iteration order.
+func (it *iterator) first(a, b string) bool {
+	if it.reverse {
+		return a >= b
+	}
+	return a <= b
+}
+
+func (it *iterator) Key() string {
+	return it.key
+}
+
+func (it *iterator) Value() []byte {
+	return append([]byte(nil), it.value...)
+}
+
+func (it *iterator) Err() error {
+	return it.back.Err()
+}
+
+func (it *iterator) Close() error {
+	return it.back.Close()
+}
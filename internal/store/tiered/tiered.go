@@ -0,0 +1,657 @@
+// Package tiered composes a memory front store absorbing writes with a back
+// store they are flushed to in the background, so bursts of writes are
+// acknowledged at memory speed and reach the back store in batches.
+//
+// Writes not yet flushed are lost if the process dies, so a tiered store
+// trades durability of the latest writes for write throughput.
+package tiered
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// Metric names updated by the tiered store
+const (
+	MetricFlushes       = "store_tiered_flushes_total"
+	MetricFlushedWrites = "store_tiered_flushed_writes_total"
+	MetricFlushErrors   = "store_tiered_flush_errors_total"
+	MetricPendingWrites = "store_tiered_pending_writes"
+	MetricReadThroughs  = "store_tiered_read_throughs_total"
+)
+
+const (
+	// DefaultFlushInterval is the time between background flushes.
+	DefaultFlushInterval = time.Second
+	// DefaultBatchSize is the number of writes flushed to the back store at once.
+	DefaultBatchSize = 1000
+	// DefaultMaxPending is the number of pending writes at which a flush
+	// starts before the interval is up.
+	DefaultMaxPending = 10000
+)
+
+// Config controls how pending writes are flushed.
+type Config struct {
+	FlushInterval time.Duration     // Time between flushes; DefaultFlushInterval if zero
+	BatchSize     int               // Writes sent to the back store in one batch; DefaultBatchSize if zero
+	MaxPending    int               // Pending writes that start a flush early; DefaultMaxPending if zero
+	Metrics       *metrics.Registry // Registry for the flush metrics; metrics.Default if nil
+	Logger        *slog.Logger      // Receives flush failures; slog.Default() if nil
+}
+
+// pending is a write held in the front store until it is flushed.
+type pending struct {
+	deleted bool   // The key was deleted, so it is absent from the front store
+	seq     uint64 // Orders writes to the same key, so a flush never drops a newer one
+}
+
+// Store holds writes in a front store, typically a memory.MemoryStore, and
+// flushes them to the back store in the background. Reads are served from
+// the pending writes and go through to the back store otherwise.
+//
+// Point-in-time reads, conditional writes, backups and compaction filters
+// work on the back store, so they flush the pending writes first.
+type Store struct {
+	store.Passthrough // back store
+	front             store.Store
+	config            Config
+
+	// mu guards pending and the front store, so readers see a batch of
+	// writes either entirely or not at all
+	mu      sync.RWMutex
+	pending map[string]pending
+	seq     uint64
+	// flushMu serializes flushes and the writes made on the back store directly
+	flushMu sync.Mutex
+
+	kick     chan struct{}
+	done     chan struct{}
+	finished chan struct{}
+	closing  sync.Once
+
+	flushes, flushed, flushErrors, readThroughs *metrics.Counter
+	pendingGauge                                *metrics.Gauge
+}
+
+// New composes front and back into a tiered store and starts flushing in the
+// background until the store is closed.
+func New(front, back store.Store, config Config) *Store {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = DefaultMaxPending
+	}
+	if config.Metrics == nil {
+		config.Metrics = metrics.Default
+	}
+
+	s := &Store{
+		Passthrough:  store.Passthrough{Store: back},
+		front:        front,
+		config:       config,
+		pending:      make(map[string]pending),
+		kick:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		finished:     make(chan struct{}),
+		flushes:      config.Metrics.Counter(MetricFlushes),
+		flushed:      config.Metrics.Counter(MetricFlushedWrites),
+		flushErrors:  config.Metrics.Counter(MetricFlushErrors),
+		readThroughs: config.Metrics.Counter(MetricReadThroughs),
+		pendingGauge: config.Metrics.Gauge(MetricPendingWrites),
+	}
+	go s.run()
+	return s
+}
+
+var (
+	_ store.Store                 = (*Store)(nil)
+	_ store.Wrapper               = (*Store)(nil)
+	_ store.Batcher               = (*Store)(nil)
+	_ store.Expirer               = (*Store)(nil)
+	_ store.ContextWriter         = (*Store)(nil)
+	_ store.ContextReader         = (*Store)(nil)
+	_ store.ConditionalPutter     = (*Store)(nil)
+	_ store.IteratorProvider      = (*Store)(nil)
+	_ store.RangeIteratorProvider = (*Store)(nil)
+	_ store.ForEacher             = (*Store)(nil)
+	_ store.Viewer                = (*Store)(nil)
+	_ store.VersionReader         = (*Store)(nil)
+	_ store.SnapshotReader        = (*Store)(nil)
+	_ store.Backuper              = (*Store)(nil)
+	_ store.FilterEvaluator       = (*Store)(nil)
+	_ store.Maintainer            = (*Store)(nil)
+)
+
+// run flushes on every interval, and early when too many writes are pending.
+func (s *Store) run() {
+	defer close(s.finished)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+		if err := s.flushPending(); err != nil {
+			logging.Or(s.config.Logger).Warn("Failed to flush pending writes", "error", err)
+		}
+	}
+}
+
+// markLocked records writes to keys, starting a flush if too many are pending.
+func (s *Store) markLocked(keys []string, deleted bool) {
+	for _, key := range keys {
+		s.seq++
+		s.pending[key] = pending{deleted: deleted, seq: s.seq}
+	}
+	s.pendingGauge.Set(int64(len(s.pending)))
+	if len(s.pending) >= s.config.MaxPending {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Put holds the value in the front store until it is flushed.
+func (s *Store) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.front.Put(key, value); err != nil {
+		return err
+	}
+	s.markLocked([]string{key}, false)
+	return nil
+}
+
+// PutContext is Put on behalf of the request in ctx.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	return s.Put(key, value)
+}
+
+// Delete removes the key from the front store and holds the deletion until
+// it is flushed.
+func (s *Store) Delete(key string) error {
+	return s.BatchDelete([]string{key})
+}
+
+// DeleteContext is Delete on behalf of the request in ctx.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	return s.Delete(key)
+}
+
+// BatchPut holds the pairs in the front store until they are flushed.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := store.BatchPut(s.front, pairs); err != nil {
+		return err
+	}
+	s.markLocked(keys, false)
+	return nil
+}
+
+// BatchPutContext is BatchPut on behalf of the request in ctx.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	return s.BatchPut(pairs)
+}
+
+// BatchDelete removes the keys from the front store and holds the deletions
+// until they are flushed.
+func (s *Store) BatchDelete(keys []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := store.BatchDelete(s.front, keys); err != nil {
+		return err
+	}
+	s.markLocked(keys, true)
+	return nil
+}
+
+// ExpireKeys drops the pending writes to keys and expires them in the back
+// store right away, so the back store reports them as expired.
+func (s *Store) ExpireKeys(keys []string) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := store.ExpireKeys(s.Store, keys); err != nil {
+		return err
+	}
+	return s.dropLocked(keys)
+}
+
+// dropLocked forgets the pending writes to keys.
+func (s *Store) dropLocked(keys []string) error {
+	for _, key := range keys {
+		delete(s.pending, key)
+	}
+	s.pendingGauge.Set(int64(len(s.pending)))
+	return store.BatchDelete(s.front, keys)
+}
+
+// PutIfVersion writes the value to the back store after the pending write to
+// key, if any, since versions are only assigned by the back store.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext is PutIfVersion on behalf of the request in ctx.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pending[key]; ok {
+		if err := s.writeBackLocked(key); err != nil {
+			return err
+		}
+	}
+	return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+}
+
+// writeBackLocked writes the pending write to key to the back store.
+func (s *Store) writeBackLocked(key string) error {
+	var err error
+	if s.pending[key].deleted {
+		err = s.Store.Delete(key)
+	} else {
+		var value []byte
+		if value, _, err = s.front.Get(key); err == nil {
+			err = s.Store.Put(key, value)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to flush pending write to %q: %w", key, err)
+	}
+	s.flushed.Inc()
+	return s.dropLocked([]string{key})
+}
+
+// Flush writes every pending write to the back store, then flushes the back
+// store to disk if it buffers writes itself.
+func (s *Store) Flush() error {
+	if err := s.flushPending(); err != nil {
+		return err
+	}
+	if maintainer, ok := store.As[store.Maintainer](s.Store); ok {
+		return maintainer.Flush()
+	}
+	return nil
+}
+
+// flushPending writes every pending write to the back store in batches of
+// Config.BatchSize. Writes made while a batch is written stay pending.
+func (s *Store) flushPending() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.flushes.Inc()
+
+	for {
+		s.mu.RLock()
+		batch := make(map[string]pending, min(len(s.pending), s.config.BatchSize))
+		var puts, deletes []string
+		for key, write := range s.pending {
+			if len(batch) == s.config.BatchSize {
+				break
+			}
+			batch[key] = write
+			if write.deleted {
+				deletes = append(deletes, key)
+			} else {
+				puts = append(puts, key)
+			}
+		}
+		values, err := store.BatchGet(s.front, puts)
+		s.mu.RUnlock()
+		if err != nil {
+			s.flushErrors.Inc()
+			return fmt.Errorf("failed to read pending writes: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := s.writeBatch(values, deletes); err != nil {
+			s.flushErrors.Inc()
+			return err
+		}
+		s.flushed.Add(uint64(len(batch)))
+
+		// Drop the writes that were not overwritten while the batch was written
+		s.mu.Lock()
+		flushed := make([]string, 0, len(batch))
+		for key, write := range batch {
+			if s.pending[key].seq == write.seq {
+				flushed = append(flushed, key)
+			}
+		}
+		err = s.dropLocked(flushed)
+		s.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to drop flushed writes: %w", err)
+		}
+		if len(batch) < s.config.BatchSize {
+			return nil
+		}
+	}
+}
+
+// writeBatch writes values and deletes keys in the back store.
+func (s *Store) writeBatch(values map[string][]byte, deletes []string) error {
+	if len(values) > 0 {
+		if err := store.BatchPut(s.Store, values); err != nil {
+			return fmt.Errorf("failed to flush %d writes: %w", len(values), err)
+		}
+	}
+	if len(deletes) > 0 {
+		if err := store.BatchDelete(s.Store, deletes); err != nil {
+			return fmt.Errorf("failed to flush %d deletions: %w", len(deletes), err)
+		}
+	}
+	return nil
+}
+
+// lookup returns the pending value of key, and whether a write to it is
+// pending at all.
+func (s *Store) lookup(key string) (value []byte, found, ok bool, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	write, ok := s.pending[key]
+	if !ok || write.deleted {
+		return nil, false, ok, nil
+	}
+	value, found, err = s.front.Get(key)
+	return value, found, true, err
+}
+
+// Get serves pending writes from the front store and reads through to the
+// back store otherwise.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext is Get on behalf of the request in ctx.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found, ok, err := s.lookup(key)
+	if ok || err != nil {
+		return value, found, err
+	}
+	s.readThroughs.Inc()
+	return store.GetContext(ctx, s.Store, key)
+}
+
+// GetView calls fn with the pending value of key, or with its value in the
+// back store otherwise.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	if value, found, ok, err := s.lookup(key); ok || err != nil {
+		if err != nil || !found {
+			return found, err
+		}
+		return true, fn(value)
+	}
+	s.readThroughs.Inc()
+	return store.GetView(s.Store, key, fn)
+}
+
+// BatchGet serves pending writes from the front store and reads the other
+// keys from the back store.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	var missing, held []string
+	s.mu.RLock()
+	for _, key := range keys {
+		write, ok := s.pending[key]
+		switch {
+		case !ok:
+			missing = append(missing, key)
+		case !write.deleted:
+			held = append(held, key)
+		}
+	}
+	result, err := store.BatchGet(s.front, held)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	s.readThroughs.Add(uint64(len(missing)))
+	values, err := store.BatchGet(s.Store, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// overlay returns the pending writes to keys starting with prefix and
+// matching match in key order, descending if reverse, with nil values for
+// deletions.
+func (s *Store) overlay(prefix string, match func(key string) bool, reverse bool) ([]pair, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	values, err := s.front.Scan(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []pair
+	for key, write := range s.pending {
+		if !strings.HasPrefix(key, prefix) || !match(key) {
+			continue
+		}
+		if write.deleted {
+			pairs = append(pairs, pair{key: key, deleted: true})
+		} else if value, ok := values[key]; ok {
+			pairs = append(pairs, pair{key: key, value: value})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return (pairs[i].key < pairs[j].key) != reverse })
+	return pairs, nil
+}
+
+// Scan reads the pairs whose keys start with prefix from the back store,
+// overlaid with the pending writes.
+func (s *Store) Scan(prefix string) (map[string][]byte, error) {
+	return s.ScanContext(context.Background(), prefix)
+}
+
+// ScanContext is Scan on behalf of the request in ctx.
+func (s *Store) ScanContext(ctx context.Context, prefix string) (map[string][]byte, error) {
+	// The pending writes are read first: a deletion flushed while the back
+	// store is scanned is then still seen as one
+	pairs, err := s.overlay(prefix, anyKey, false)
+	if err != nil {
+		return nil, err
+	}
+	result, err := store.ScanContext(ctx, s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pairs {
+		if p.deleted {
+			delete(result, p.key)
+		} else {
+			result[p.key] = p.value
+		}
+	}
+	return result, nil
+}
+
+// NewIterator streams the pairs whose keys start with prefix from the back
+// store, merged with the pending writes as they were when it was created.
+func (s *Store) NewIterator(prefix string) (store.Iterator, error) {
+	pairs, err := s.overlay(prefix, anyKey, false)
+	if err != nil {
+		return nil, err
+	}
+	it, err := store.NewIterator(s.Store, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{back: it, overlay: pairs}, nil
+}
+
+// ForEach visits the pairs whose keys start with prefix, as NewIterator
+// streams them.
+func (s *Store) ForEach(prefix string, fn func(key string, value []byte) error) error {
+	it, err := s.NewIterator(prefix)
+	if err != nil {
+		return err
+	}
+	return store.VisitIterator(it, fn)
+}
+
+// NewRangeIterator streams the pairs whose keys are in r from the back
+// store, merged with the pending writes as they were when it was created.
+func (s *Store) NewRangeIterator(r store.KeyRange) (store.Iterator, error) {
+	pairs, err := s.overlay("", r.Contains, r.Reverse)
+	if err != nil {
+		return nil, err
+	}
+	if r.KeysOnly {
+		for i := range pairs {
+			pairs[i].value = nil
+		}
+	}
+	it, err := store.NewRangeIterator(s.Store, r)
+	if err != nil {
+		return nil, err
+	}
+	return &iterator{back: it, overlay: pairs, reverse: r.Reverse}, nil
+}
+
+func anyKey(string) bool { return true }
+
+// GetAt flushes the pending writes and reads key as of version from the back
+// store, failing with store.ErrSnapshotsUnsupported if it keeps no versions.
+func (s *Store) GetAt(key string, version uint64) ([]byte, uint64, bool, error) {
+	reader, ok := store.As[store.VersionReader](s.Store)
+	if !ok {
+		return nil, 0, false, store.ErrSnapshotsUnsupported
+	}
+	if err := s.flushPending(); err != nil {
+		return nil, 0, false, err
+	}
+	return reader.GetAt(key, version)
+}
+
+// VersionAt resolves t with the back store.
+func (s *Store) VersionAt(t time.Time) (uint64, error) {
+	return store.VersionAt(s.Store, t)
+}
+
+// NewIteratorAt flushes the pending writes and streams the pairs whose keys
+// start with prefix as they were at version from the back store.
+func (s *Store) NewIteratorAt(prefix string, version uint64) (store.Iterator, error) {
+	if err := s.flushPending(); err != nil {
+		return nil, err
+	}
+	return store.NewIteratorAt(s.Store, prefix, version)
+}
+
+// Backup flushes the pending writes and backs up the back store.
+func (s *Store) Backup(w io.Writer, since uint64) (uint64, error) {
+	if err := s.flushPending(); err != nil {
+		return 0, err
+	}
+	return store.Backup(s.Store, w, since)
+}
+
+// Restore flushes the pending writes and restores into the back store.
+func (s *Store) Restore(r io.Reader, since uint64) error {
+	if err := s.flushPending(); err != nil {
+		return err
+	}
+	return store.Restore(s.Store, r, since)
+}
+
+// EvaluateFilter flushes the pending writes and evaluates filter on the back
+// store, with its write times when it tracks them.
+func (s *Store) EvaluateFilter(filter store.CompactionFilter, now time.Time) ([]string, error) {
+	if err := s.flushPending(); err != nil {
+		return nil, err
+	}
+	if evaluator, ok := store.As[store.FilterEvaluator](s.Store); ok {
+		return evaluator.EvaluateFilter(filter, now)
+	}
+	return store.EvaluateFilter(s.Store, filter, now)
+}
+
+// DiskUsage returns the disk usage of the back store, or 0 if it keeps no
+// data files.
+func (s *Store) DiskUsage() int64 {
+	if maintainer, ok := store.As[store.Maintainer](s.Store); ok {
+		return maintainer.DiskUsage()
+	}
+	return 0
+}
+
+// RunGC collects garbage in the back store.
+func (s *Store) RunGC(discardRatio float64) (int, error) {
+	maintainer, ok := store.As[store.Maintainer](s.Store)
+	if !ok {
+		return 0, errors.New("the back store does not support garbage collection")
+	}
+	return maintainer.RunGC(discardRatio)
+}
+
+// Stats reports the statistics of the back store, with the memory held by
+// the pending writes.
+func (s *Store) Stats() (store.Stats, error) {
+	stats, err := s.Store.Stats()
+	if err != nil {
+		return stats, err
+	}
+	front, err := s.front.Stats()
+	if err != nil {
+		return stats, fmt.Errorf("failed to read front store stats: %w", err)
+	}
+	stats.MemoryBytes += front.MemoryBytes
+	return stats, nil
+}
+
+// Close stops the background flushes, flushes the pending writes one last
+// time and closes both stores.
+func (s *Store) Close() error {
+	s.closing.Do(func() { close(s.done) })
+	<-s.finished
+
+	flushErr := s.flushPending()
+	frontErr := s.front.Close()
+	if err := s.Store.Close(); err != nil {
+		return err
+	}
+	if flushErr != nil {
+		return flushErr
+	}
+	if frontErr != nil {
+		return fmt.Errorf("failed to close front store: %w", frontErr)
+	}
+	return nil
+}
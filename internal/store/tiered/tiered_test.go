@@ -0,0 +1,254 @@
+package tiered
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/metrics"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+func createTestStore(t *testing.T, config Config) (*Store, *memory.MemoryStore, *metrics.Registry) {
+	t.Helper()
+	front, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = time.Hour
+	}
+	config.Metrics = metrics.NewRegistry()
+	s := New(front, back, config)
+	t.Cleanup(func() {
+		if err := s.Close(); err != nil {
+			t.Logf("Failed to close store: %v", err)
+		}
+	})
+	return s, back, config.Metrics
+}
+
+func TestStore_WriteBehind(t *testing.T) {
+	s, back, registry := createTestStore(t, Config{BatchSize: 2})
+	if err := back.BatchPut(map[string][]byte{"a": []byte("old"), "b": []byte("gone")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.BatchPut(map[string][]byte{"a": []byte("new"), "c": []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := back.Get("a"); string(value) != "old" {
+		t.Errorf("Expected writes to stay pending, got %q in the back store", value)
+	}
+	if value, found, err := s.Get("a"); err != nil || !found || string(value) != "new" {
+		t.Errorf("Get(a) = %q, %v, %v; want the pending write", value, found, err)
+	}
+	if _, found, _ := s.Get("b"); found {
+		t.Error("Expected the pending deletion to hide b")
+	}
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := back.Scan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 || string(pairs["a"]) != "new" || string(pairs["c"]) != "3" {
+		t.Errorf("Back store after Flush = %q, want a and c", pairs)
+	}
+	if flushed := registry.Counter(MetricFlushedWrites).Value(); flushed != 3 {
+		t.Errorf("Expected 3 writes flushed in batches of 2, got %d", flushed)
+	}
+	if pending := registry.Gauge(MetricPendingWrites).Value(); pending != 0 {
+		t.Errorf("Expected no pending writes after Flush, got %d", pending)
+	}
+	if value, _, _ := s.Get("c"); string(value) != "3" {
+		t.Errorf("Expected flushed writes to be read through, got %q", value)
+	}
+}
+
+func TestStore_ScanAndIterate(t *testing.T) {
+	s, back, _ := createTestStore(t, Config{})
+	if err := back.BatchPut(map[string][]byte{"k1": []byte("back"), "k2": []byte("back"), "k4": []byte("back"), "x": []byte("other")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"k2": []byte("front"), "k3": []byte("front")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("k4"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"k1": "back", "k2": "front", "k3": "front"}
+	pairs, err := s.Scan("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != len(want) {
+		t.Errorf("Scan = %q, want %q", pairs, want)
+	}
+	for key, value := range want {
+		if string(pairs[key]) != value {
+			t.Errorf("Scan[%s] = %q, want %q", key, pairs[key], value)
+		}
+	}
+
+	it, err := s.NewIterator("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var keys []string
+	for it.Next() {
+		keys = append(keys, it.Key())
+		if string(it.Value()) != want[it.Key()] {
+			t.Errorf("Iterator value of %s = %q, want %q", it.Key(), it.Value(), want[it.Key()])
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0] != "k1" || keys[1] != "k2" || keys[2] != "k3" {
+		t.Errorf("Iterator keys = %v, want k1, k2 and k3 in order", keys)
+	}
+
+	got, err := s.BatchGet([]string{"k1", "k3", "k4", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || string(got["k1"]) != "back" || string(got["k3"]) != "front" {
+		t.Errorf("BatchGet = %q, want k1 from the back store and k3 pending", got)
+	}
+}
+
+func TestStore_BackgroundFlush(t *testing.T) {
+	s, back, _ := createTestStore(t, Config{MaxPending: 2})
+	if err := s.BatchPut(map[string][]byte{"a": []byte("1"), "b": []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if pairs, _ := back.Scan(""); len(pairs) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected reaching MaxPending to flush in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStore_ConcurrentWrites(t *testing.T) {
+	s, back, _ := createTestStore(t, Config{FlushInterval: time.Millisecond, BatchSize: 3})
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				key := string(rune('a' + i))
+				if err := s.Put(key, []byte{byte(j)}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 4 {
+		key := string(rune('a' + i))
+		if value, _, _ := back.Get(key); len(value) != 1 || value[0] != 49 {
+			t.Errorf("Expected the last write to %s flushed, got %v", key, value)
+		}
+	}
+}
+
+func TestStore_ConditionalWrites(t *testing.T) {
+	s, back, _ := createTestStore(t, Config{})
+	if err := s.Put("a", []byte("pending")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The memory store keeps no versions, so the pending write is flushed
+	// before the conditional write is rejected
+	if err := s.PutIfVersion("a", []byte("x"), 0); !errors.Is(err, store.ErrConditionalPutUnsupported) {
+		t.Errorf("PutIfVersion error = %v, want ErrConditionalPutUnsupported", err)
+	}
+	if value, _, _ := back.Get("a"); string(value) != "pending" {
+		t.Errorf("Expected the pending write flushed first, got %q", value)
+	}
+	if _, _, _, err := s.GetAt("a", 1); !errors.Is(err, store.ErrSnapshotsUnsupported) {
+		t.Errorf("GetAt error = %v, want ErrSnapshotsUnsupported", err)
+	}
+}
+
+func TestStore_CloseFlushes(t *testing.T) {
+	front, _ := memory.NewWithDefaults()
+	base, _ := memory.NewWithDefaults()
+	var flushed map[string][]byte
+	// Capture the back store contents before it is closed
+	back := &closeRecorder{MemoryStore: base, onClose: func() { flushed, _ = base.Scan("") }}
+	s := New(front, back, Config{FlushInterval: time.Hour, Metrics: metrics.NewRegistry()})
+	if err := s.Put("a", []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if string(flushed["a"]) != "1" {
+		t.Errorf("Expected Close to flush pending writes, got %q", flushed)
+	}
+}
+
+type closeRecorder struct {
+	*memory.MemoryStore
+	onClose func()
+}
+
+func (c *closeRecorder) Close() error {
+	c.onClose()
+	return c.MemoryStore.Close()
+}
+
+func TestStore_ReverseRange(t *testing.T) {
+	s, back, _ := createTestStore(t, Config{})
+	if err := back.BatchPut(map[string][]byte{"a": []byte("back"), "c": []byte("back"), "e": []byte("back")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchPut(map[string][]byte{"b": []byte("front"), "c": []byte("front")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := store.Range(s, store.KeyRange{End: "e", Reverse: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []store.KeyValue{{Key: "c", Value: []byte("front")}, {Key: "b", Value: []byte("front")}}
+	if len(pairs) != len(want) {
+		t.Fatalf("Range = %q, want %q", pairs, want)
+	}
+	for i := range want {
+		if pairs[i].Key != want[i].Key || string(pairs[i].Value) != string(want[i].Value) {
+			t.Errorf("Range[%d] = %s=%q, want %s=%q", i, pairs[i].Key, pairs[i].Value, want[i].Key, want[i].Value)
+		}
+	}
+}
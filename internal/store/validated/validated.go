@@ -0,0 +1,355 @@
+// Package validated wraps a store so that every write is checked against a
+// validation profile first. The profile is chosen per write from the caller's
+// identity or the namespace of the key, so tenants with different
+// data-quality requirements can share one server.
+package validated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/settings"
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Config binds validation profiles to callers and namespaces. A write is
+// checked with the profile bound to its authenticated principal, else the one
+// bound to the namespace of its key, else the one NamespaceProfile reports for
+// that namespace, else Default.
+type Config struct {
+	// Default names the profile for writes no binding applies to;
+	// validation.ProfileStandard if empty.
+	Default string `json:"default,omitempty"`
+	// Principals binds principal names, which API tokens authenticate as, to profiles.
+	Principals map[string]string `json:"principals,omitempty"`
+	// Namespaces binds namespaces to profiles.
+	Namespaces map[string]string `json:"namespaces,omitempty"`
+	// NormalizeKeys rewrites keys to Unicode NFC before they are validated,
+	// stored, read or deleted, so keys that look the same name the same pair.
+	// Prefixes and ranges are passed through unchanged.
+	NormalizeKeys bool `json:"normalize_keys,omitempty"`
+	// Schemas binds key prefixes, such as a namespace followed by "/", to the
+	// JSON Schema the values written under them must satisfy, in addition to
+	// their profile. The longest matching prefix applies.
+	Schemas map[string]json.RawMessage `json:"schemas,omitempty"`
+
+	// Rules defines profiles from declarative rules, or redefines the
+	// built-in ones, in addition to Profiles; New compiles them.
+	Rules map[string]validation.Rules `json:"profiles,omitempty"`
+
+	// Profiles available for binding; validation.DefaultProfiles() if nil.
+	Profiles validation.Profiles `json:"-"`
+	// NamespaceProfile optionally looks up the profile of a namespace at
+	// request time, such as settings.Manager.ValidationProfile. Names it
+	// returns that are empty or unknown fall through to Default.
+	NamespaceProfile func(namespace string) string `json:"-"`
+	// Events receives a TypeValidationFailed event for every rejected write.
+	Events *events.Bus `json:"-"`
+}
+
+// LoadConfigFile reads the bindings, rules, key normalization and schemas of
+// a Config from a JSON file.
+func LoadConfigFile(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(path) // #nosec G304 -- path is operator-provided configuration
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse validation profile bindings %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Store checks writes against validation profiles before passing them on.
+type Store struct {
+	store.Passthrough
+	config  Config
+	def     *validation.Profile
+	schemas []prefixSchema // Longest prefix first
+}
+
+// prefixSchema is the schema of the values under a key prefix.
+type prefixSchema struct {
+	prefix string
+	schema *validation.Schema
+}
+
+// New wraps base, failing if the configuration binds a profile that does not
+// exist, or holds rules or a schema that cannot be compiled.
+func New(base store.Store, config Config) (*Store, error) {
+	if config.Profiles == nil {
+		config.Profiles = validation.DefaultProfiles()
+	}
+	if len(config.Rules) > 0 {
+		profiles := make(validation.Profiles, len(config.Profiles)+len(config.Rules))
+		maps.Copy(profiles, config.Profiles)
+		for name, rules := range config.Rules {
+			profile, err := rules.Compile(name)
+			if err != nil {
+				return nil, err
+			}
+			profiles[name] = profile
+		}
+		config.Profiles = profiles
+	}
+	if config.Default == "" {
+		config.Default = validation.ProfileStandard
+	}
+	def, err := config.Profiles.Lookup(config.Default)
+	if err != nil {
+		return nil, err
+	}
+	for _, bindings := range []map[string]string{config.Principals, config.Namespaces} {
+		for _, name := range bindings {
+			if _, err := config.Profiles.Lookup(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var schemas []prefixSchema
+	for prefix, data := range config.Schemas {
+		schema, err := validation.ParseSchema(data)
+		if err != nil {
+			return nil, fmt.Errorf("schema of %q: %w", prefix, err)
+		}
+		schemas = append(schemas, prefixSchema{prefix: prefix, schema: schema})
+	}
+	sort.Slice(schemas, func(i, j int) bool { return len(schemas[i].prefix) > len(schemas[j].prefix) })
+	return &Store{Passthrough: store.Passthrough{Store: base}, config: config, def: def, schemas: schemas}, nil
+}
+
+var (
+	_ store.Store             = (*Store)(nil)
+	_ store.Wrapper           = (*Store)(nil)
+	_ store.Batcher           = (*Store)(nil)
+	_ store.ContextWriter     = (*Store)(nil)
+	_ store.ContextReader     = (*Store)(nil)
+	_ store.ConditionalPutter = (*Store)(nil)
+	_ store.Viewer            = (*Store)(nil)
+)
+
+// canonical returns key in NFC when keys are normalized, warning the request
+// in ctx when that changes it.
+func (s *Store) canonical(ctx context.Context, key string) string {
+	if !s.config.NormalizeKeys || norm.NFC.IsNormalString(key) {
+		return key
+	}
+	normalized := norm.NFC.String(key)
+	warnings.Add(ctx, warnings.Warning{
+		Code:     warnings.CodeNormalized,
+		Key:      normalized,
+		Message:  "key was normalized to Unicode NFC",
+		Metadata: map[string]string{"original": key},
+	})
+	return normalized
+}
+
+// Profile returns the profile that applies to a write of key on behalf of the
+// request in ctx.
+func (s *Store) Profile(ctx context.Context, key string) *validation.Profile {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		if name, ok := s.config.Principals[principal.Name]; ok {
+			return s.config.Profiles[name]
+		}
+	}
+	namespace, ok := settings.NamespaceOf(key)
+	if !ok {
+		return s.def
+	}
+	if name, ok := s.config.Namespaces[namespace]; ok {
+		return s.config.Profiles[name]
+	}
+	if s.config.NamespaceProfile != nil {
+		if profile, ok := s.config.Profiles[s.config.NamespaceProfile(namespace)]; ok {
+			return profile
+		}
+	}
+	return s.def
+}
+
+// schema returns the schema bound to the longest prefix of key, if any.
+func (s *Store) schema(key string) (prefixSchema, bool) {
+	for _, bound := range s.schemas {
+		if strings.HasPrefix(key, bound.prefix) {
+			return bound, true
+		}
+	}
+	return prefixSchema{}, false
+}
+
+// tracer records a span for every validated write.
+var tracer = otel.Tracer("github.com/William-Fernandes252/clavis/internal/store/validated")
+
+// validate checks one write, publishing an event when it is rejected.
+func (s *Store) validate(ctx context.Context, key string, value []byte) error {
+	profile := s.Profile(ctx, key)
+	_, span := tracer.Start(ctx, "validation", trace.WithAttributes(
+		attribute.String("clavis.key", key),
+		attribute.String("clavis.validation.profile", profile.Name),
+	))
+	defer span.End()
+
+	namespace, _ := settings.NamespaceOf(key)
+	vctx := validation.Context{Namespace: namespace}
+	result := profile.Validate(vctx, key, value)
+	if bound, ok := s.schema(key); ok {
+		if err := validation.JSONSchema(bound.schema).Validate(vctx.At("value"), value); err != nil {
+			result.Add(err.WithMetadata("schema_prefix", bound.prefix))
+		}
+	}
+	if result.Valid() {
+		return nil
+	}
+	span.SetStatus(codes.Error, result.Error())
+	if s.config.Events != nil {
+		first := result.Errors[0]
+		s.config.Events.Publish(events.Event{Type: events.TypeValidationFailed, Key: key, Attributes: map[string]string{
+			"profile": profile.Name,
+			"target":  first.Target,
+			"code":    string(first.Code),
+		}})
+	}
+	return result.Err()
+}
+
+// Validate checks a write of value at key on behalf of the request in ctx
+// as Put does, without writing it.
+func (s *Store) Validate(ctx context.Context, key string, value []byte) error {
+	return s.validate(ctx, key, value)
+}
+
+// Put validates and stores the value with the default bindings for a write
+// made outside any request.
+func (s *Store) Put(key string, value []byte) error {
+	return s.PutContext(context.Background(), key, value)
+}
+
+// PutContext validates the value with the profile selected for the request
+// in ctx and stores it.
+func (s *Store) PutContext(ctx context.Context, key string, value []byte) error {
+	key = s.canonical(ctx, key)
+	if err := s.validate(ctx, key, value); err != nil {
+		return err
+	}
+	return store.PutContext(ctx, s.Store, key, value)
+}
+
+// PutIfVersion validates and conditionally stores the value outside any request.
+func (s *Store) PutIfVersion(key string, value []byte, expected uint64) error {
+	return s.PutIfVersionContext(context.Background(), key, value, expected)
+}
+
+// PutIfVersionContext validates the value with the profile selected for the
+// request in ctx and stores it if key is still at the expected version.
+func (s *Store) PutIfVersionContext(ctx context.Context, key string, value []byte, expected uint64) error {
+	key = s.canonical(ctx, key)
+	if err := s.validate(ctx, key, value); err != nil {
+		return err
+	}
+	return store.PutIfVersionContext(ctx, s.Store, key, value, expected)
+}
+
+// BatchPut validates and stores all pairs outside any request.
+func (s *Store) BatchPut(pairs map[string][]byte) error {
+	return s.BatchPutContext(context.Background(), pairs)
+}
+
+// BatchPutContext validates every pair before storing any of them, so a
+// single invalid pair rejects the whole batch.
+func (s *Store) BatchPutContext(ctx context.Context, pairs map[string][]byte) error {
+	if s.config.NormalizeKeys {
+		normalized := make(map[string][]byte, len(pairs))
+		for key, value := range pairs {
+			normalized[s.canonical(ctx, key)] = value
+		}
+		pairs = normalized
+	}
+	for key, value := range pairs {
+		if err := s.validate(ctx, key, value); err != nil {
+			return err
+		}
+	}
+	return store.BatchPutContext(ctx, s.Store, pairs)
+}
+
+// Delete removes the key from the wrapped store outside any request.
+func (s *Store) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext removes the key from the wrapped store; deletes are not validated.
+func (s *Store) DeleteContext(ctx context.Context, key string) error {
+	return store.DeleteContext(ctx, s.Store, s.canonical(ctx, key))
+}
+
+// BatchDelete removes the keys from the wrapped store.
+func (s *Store) BatchDelete(keys []string) error {
+	return store.BatchDelete(s.Store, s.canonicalKeys(keys))
+}
+
+// Get reads the key from the wrapped store outside any request.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	return s.GetContext(context.Background(), key)
+}
+
+// GetContext reads the key from the wrapped store; reads are not validated.
+func (s *Store) GetContext(ctx context.Context, key string) ([]byte, bool, error) {
+	return store.GetContext(ctx, s.Store, s.canonical(ctx, key))
+}
+
+// GetView reads the key from the wrapped store in place.
+func (s *Store) GetView(key string, fn func(value []byte) error) (bool, error) {
+	return store.GetView(s.Store, s.canonical(context.Background(), key), fn)
+}
+
+// BatchGet reads the keys from the wrapped store, returning the values under
+// the keys as requested.
+func (s *Store) BatchGet(keys []string) (map[string][]byte, error) {
+	if !s.config.NormalizeKeys {
+		return store.BatchGet(s.Store, keys)
+	}
+	normalized := s.canonicalKeys(keys)
+	found, err := store.BatchGet(s.Store, normalized)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(found))
+	for i, key := range keys {
+		if value, ok := found[normalized[i]]; ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// canonicalKeys returns keys in NFC when keys are normalized.
+func (s *Store) canonicalKeys(keys []string) []string {
+	if !s.config.NormalizeKeys {
+		return keys
+	}
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		normalized[i] = s.canonical(context.Background(), key)
+	}
+	return normalized
+}
+
+// ExpireKeys removes the keys from the wrapped store as expired.
+func (s *Store) ExpireKeys(keys []string) error {
+	return store.ExpireKeys(s.Store, keys)
+}
@@ -0,0 +1,259 @@
+package validated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/auth"
+	"github.com/William-Fernandes252/clavis/internal/events"
+	"github.com/William-Fernandes252/clavis/internal/model/validation"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+	"github.com/William-Fernandes252/clavis/internal/warnings"
+)
+
+func newMemoryStore(t *testing.T) *memory.MemoryStore {
+	t.Helper()
+	s, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_Profile(t *testing.T) {
+	s, err := New(newMemoryStore(t), Config{
+		Default:    validation.ProfileStandard,
+		Principals: map[string]string{"importer": validation.ProfilePermissive},
+		Namespaces: map[string]string{"billing": validation.ProfileStrict},
+		NamespaceProfile: func(namespace string) string {
+			return map[string]string{"billing": "permissive", "cache": "permissive", "odd": "nope"}[namespace]
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	importer := auth.WithPrincipal(context.Background(), auth.Principal{Name: "importer"})
+	other := auth.WithPrincipal(context.Background(), auth.Principal{Name: "web"})
+	tests := []struct {
+		name string
+		ctx  context.Context
+		key  string
+		want string
+	}{
+		{"principal binding wins", importer, "billing/1", validation.ProfilePermissive},
+		{"namespace binding", other, "billing/1", validation.ProfileStrict},
+		{"namespace settings", context.Background(), "cache/1", validation.ProfilePermissive},
+		{"unknown settings profile falls back", context.Background(), "odd/1", validation.ProfileStandard},
+		{"no namespace", context.Background(), "plain", validation.ProfileStandard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Profile(tt.ctx, tt.key).Name; got != tt.want {
+				t.Errorf("Profile(%q) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Writes(t *testing.T) {
+	bus := events.NewBus(0)
+	defer bus.Close()
+	failures := make(chan events.Event, 4)
+	defer bus.Subscribe(func(e events.Event) { failures <- e }, events.TypeValidationFailed)()
+
+	base := newMemoryStore(t)
+	s, err := New(base, Config{Namespaces: map[string]string{"strict": validation.ProfileStrict}, Events: bus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("strict/ok", []byte("v")); err != nil {
+		t.Fatalf("Expected a valid write to succeed, got %v", err)
+	}
+	err = s.PutContext(context.Background(), "strict/bad key", []byte("v"))
+	var result *validation.ValidationResult
+	if !errors.As(err, &result) || result.Errors[0].Code != validation.CodePatternMismatch {
+		t.Fatalf("Expected a pattern failure, got %v", err)
+	}
+	if _, found, _ := base.Get("strict/bad key"); found {
+		t.Error("Expected the rejected write not to reach the store")
+	}
+	select {
+	case e := <-failures:
+		if e.Key != "strict/bad key" || e.Attributes["profile"] != validation.ProfileStrict || e.Attributes["code"] != string(validation.CodePatternMismatch) {
+			t.Errorf("Unexpected event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected a validation failure event")
+	}
+
+	err = s.BatchPut(map[string][]byte{"other/1": []byte("v"), "strict/ü": []byte("v")})
+	if err == nil {
+		t.Fatal("Expected the batch to be rejected")
+	}
+	if _, found, _ := base.Get("other/1"); found {
+		t.Error("Expected no pair of a rejected batch to be stored")
+	}
+	if err := s.BatchPutContext(context.Background(), map[string][]byte{"other/1": []byte("v"), "strict/2": []byte("v")}); err != nil {
+		t.Fatalf("Expected a valid batch to succeed, got %v", err)
+	}
+	values, err := s.BatchGet([]string{"other/1", "strict/2"})
+	if err != nil || len(values) != 2 {
+		t.Errorf("Expected both pairs stored, got %v, %v", values, err)
+	}
+}
+
+func TestNew_UnknownProfile(t *testing.T) {
+	for _, config := range []Config{
+		{Default: "nope"},
+		{Principals: map[string]string{"a": "nope"}},
+		{Namespaces: map[string]string{"a": "nope"}},
+	} {
+		if _, err := New(newMemoryStore(t), config); err == nil || !strings.Contains(err.Error(), "nope") {
+			t.Errorf("Expected New(%+v) to reject the unknown profile, got %v", config, err)
+		}
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validation.json")
+	data := `{"default": "strict", "principals": {"importer": "permissive"}, "namespaces": {"logs": "standard"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Default != "strict" || config.Principals["importer"] != "permissive" || config.Namespaces["logs"] != "standard" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+}
+
+func TestStore_Rules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validation.json")
+	data := `{
+		"default": "tight",
+		"namespaces": {"logs": "standard"},
+		"profiles": {"tight": {"key": {"max_length": 8}, "value": {"max_size": 4}}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(newMemoryStore(t), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("a", []byte("1234")); err != nil {
+		t.Errorf("Expected a write within the configured limits, got %v", err)
+	}
+	if err := s.Put("a", []byte("12345")); err == nil {
+		t.Error("Expected the configured value size to be enforced")
+	}
+	if err := s.Put("logs/123456789", []byte("12345")); err != nil {
+		t.Errorf("Expected built-in profiles to remain bindable, got %v", err)
+	}
+
+	config.Rules["tight"] = validation.Rules{Key: validation.KeyRules{Pattern: "("}}
+	if _, err := New(newMemoryStore(t), config); err == nil {
+		t.Error("Expected invalid rules to be rejected")
+	}
+}
+
+func TestStore_NormalizeKeys(t *testing.T) {
+	base := newMemoryStore(t)
+	s, err := New(base, Config{NormalizeKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const decomposed, composed = "cafe\u0301", "caf\u00e9"
+
+	ctx, collected := warnings.NewContext(context.Background())
+	if err := s.PutContext(ctx, decomposed, []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := collected.Warnings(); len(got) != 1 || got[0].Code != warnings.CodeNormalized || got[0].Key != composed {
+		t.Errorf("Warnings = %v, want one normalized warning", got)
+	}
+	if _, found, _ := base.Get(decomposed); found {
+		t.Error("Expected the decomposed key not to be stored")
+	}
+	for _, key := range []string{composed, decomposed} {
+		if value, found, err := s.Get(key); err != nil || !found || string(value) != "1" {
+			t.Errorf("Get(%q) = %q, %v, %v; want the normalized pair", key, value, found, err)
+		}
+	}
+	if values, err := s.BatchGet([]string{decomposed}); err != nil || string(values[decomposed]) != "1" {
+		t.Errorf("BatchGet = %q, %v; want the value under the requested key", values, err)
+	}
+
+	if err := s.Delete(decomposed); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := base.Get(composed); found {
+		t.Error("Expected Delete to remove the normalized key")
+	}
+
+	plain, err := New(base, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.Put(decomposed, []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := plain.Get(composed); found {
+		t.Error("Expected keys to be stored as written without NormalizeKeys")
+	}
+}
+
+func TestStore_Schemas(t *testing.T) {
+	s, err := New(newMemoryStore(t), Config{
+		Default: validation.ProfilePermissive,
+		Schemas: map[string]json.RawMessage{
+			"users/":       json.RawMessage(`{"type": "object", "required": ["name"]}`),
+			"users/admin/": json.RawMessage(`{"type": "object", "required": ["name", "role"]}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("users/1", []byte(`{"name": "ada"}`)); err != nil {
+		t.Errorf("Expected a matching value to be stored, got %v", err)
+	}
+	if err := s.Put("other", []byte("not json")); err != nil {
+		t.Errorf("Expected keys without a schema to be unchecked, got %v", err)
+	}
+
+	err = s.Put("users/admin/1", []byte(`{"name": "ada"}`))
+	var result *validation.ValidationResult
+	if !errors.As(err, &result) || len(result.Errors) != 1 {
+		t.Fatalf("Put error = %v, want a validation result", err)
+	}
+	if verr := result.Errors[0]; verr.Target != "value" || verr.Metadata["schema_prefix"] != "users/admin/" ||
+		verr.Metadata["violation.0"] != `/: missing required property "role"` {
+		t.Errorf("Expected the violation of the longest prefix's schema, got %+v", verr)
+	}
+
+	if _, err := New(newMemoryStore(t), Config{Schemas: map[string]json.RawMessage{"x/": json.RawMessage(`{"type": "thing"}`)}}); err == nil {
+		t.Error("Expected an invalid schema to be rejected")
+	}
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type versionKey struct{}
+
+// WithVersionRecord returns a copy of ctx asking the stores that track
+// versions to record the version they commit a write made with it at, and a
+// function returning that version, 0 if none was recorded.
+func WithVersionRecord(ctx context.Context) (context.Context, func() uint64) {
+	var version atomic.Uint64
+	return context.WithValue(ctx, versionKey{}, &version), version.Load
+}
+
+// RecordsVersion reports whether ctx asks for the version of its write, so
+// stores need not look the version up otherwise.
+func RecordsVersion(ctx context.Context) bool {
+	_, ok := ctx.Value(versionKey{}).(*atomic.Uint64)
+	return ok
+}
+
+// RecordVersion records version as the commit version of the write made on
+// behalf of ctx. It does nothing when ctx asks for none.
+func RecordVersion(ctx context.Context, version uint64) {
+	if record, ok := ctx.Value(versionKey{}).(*atomic.Uint64); ok {
+		record.Store(version)
+	}
+}
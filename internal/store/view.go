@@ -0,0 +1,16 @@
+package store
+
+// GetView calls fn with the value of key in s if the key exists, and reports
+// whether it does. Stores implementing Viewer hand fn the value in place, so
+// fn must not modify it nor keep it after returning; any other store is read
+// with Get.
+func GetView(s Store, key string, fn func(value []byte) error) (bool, error) {
+	if viewer, ok := s.(Viewer); ok {
+		return viewer.GetView(key, fn)
+	}
+	value, found, err := s.Get(key)
+	if err != nil || !found {
+		return found, err
+	}
+	return true, fn(value)
+}
@@ -0,0 +1,52 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+)
+
+// getOnlyStore implements only the base Store interface
+type getOnlyStore struct {
+	store.Store
+	values map[string][]byte
+	err    error
+}
+
+func (s *getOnlyStore) Get(key string) ([]byte, bool, error) {
+	value, found := s.values[key]
+	return value, found, s.err
+}
+
+func TestGetView_FallsBackToGet(t *testing.T) {
+	s := &getOnlyStore{values: map[string][]byte{"a": []byte("1")}}
+
+	var viewed string
+	found, err := store.GetView(s, "a", func(value []byte) error {
+		viewed = string(value)
+		return nil
+	})
+	if err != nil || !found || viewed != "1" {
+		t.Errorf("GetView = %v, %v viewing %q; want the stored value", found, err, viewed)
+	}
+
+	called := false
+	found, err = store.GetView(s, "missing", func([]byte) error {
+		called = true
+		return nil
+	})
+	if err != nil || found || called {
+		t.Errorf("GetView of a missing key = %v, %v, called %v; want not found without a call", found, err, called)
+	}
+
+	failed := errors.New("callback failed")
+	if _, err := store.GetView(s, "a", func([]byte) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+
+	s.err = errors.New("get failed")
+	if _, err := store.GetView(s, "a", func([]byte) error { return nil }); err == nil {
+		t.Error("Expected Get error to be returned")
+	}
+}
@@ -0,0 +1,25 @@
+package store
+
+// Wrapper is implemented by stores that decorate another store.
+type Wrapper interface {
+	// Unwrap returns the store being decorated.
+	Unwrap() Store
+}
+
+// As walks the chain of wrapped stores starting at s and returns the first one
+// that implements T. It lets callers reach optional capabilities of a backend
+// through any number of decorators.
+func As[T any](s Store) (T, bool) {
+	for s != nil {
+		if target, ok := s.(T); ok {
+			return target, true
+		}
+		wrapper, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
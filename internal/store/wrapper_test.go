@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/internal/store"
+	"github.com/William-Fernandes252/clavis/internal/store/memory"
+)
+
+type wrappingStore struct {
+	store.Store
+}
+
+func (w *wrappingStore) Unwrap() store.Store {
+	return w.Store
+}
+
+func TestAs(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped := &wrappingStore{Store: &wrappingStore{Store: base}}
+
+	found, ok := store.As[*memory.MemoryStore](wrapped)
+	if !ok || found != base {
+		t.Errorf("Expected to find the base store through two wrappers, got %v %t", found, ok)
+	}
+
+	if _, ok := store.As[store.Relocator](wrapped); ok {
+		t.Error("Expected memory store not to implement Relocator")
+	}
+
+	if _, ok := store.As[store.Store](nil); ok {
+		t.Error("Expected nil store to yield no match")
+	}
+}
+
+// recordingStore records the Put calls it sees under its name.
+type recordingStore struct {
+	store.Passthrough
+	name  string
+	calls *[]string
+}
+
+func (r *recordingStore) Put(key string, value []byte) error {
+	*r.calls = append(*r.calls, r.name)
+	return r.Store.Put(key, value)
+}
+
+func TestChain(t *testing.T) {
+	base, err := memory.NewWithDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	recording := func(name string) store.Middleware {
+		return func(next store.Store) store.Store {
+			return &recordingStore{Passthrough: store.Passthrough{Store: next}, name: name, calls: &calls}
+		}
+	}
+	chained := store.Chain(base, recording("outer"), recording("inner"))
+
+	if err := chained.Put("k", []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || calls[0] != "outer" || calls[1] != "inner" {
+		t.Errorf("Expected calls to go through the middlewares in order, got %v", calls)
+	}
+	if outer, ok := chained.(*recordingStore); !ok || outer.name != "outer" {
+		t.Errorf("Expected the first middleware to be outermost, got %T", chained)
+	}
+
+	// Optional interfaces reach the base store through every wrapper
+	if _, ok := chained.(store.Batcher); !ok {
+		t.Error("Expected the chain to implement Batcher")
+	}
+	values, err := store.BatchGet(chained, []string{"k", "missing"})
+	if err != nil || len(values) != 1 || string(values["k"]) != "v" {
+		t.Errorf("Expected BatchGet to pass through, got %v, %v", values, err)
+	}
+	if found, ok := store.As[*memory.MemoryStore](chained); !ok || found != base {
+		t.Error("Expected to find the base store through the chain")
+	}
+	if store.Chain(base) != base {
+		t.Error("Expected a chain without middlewares to be the base store")
+	}
+}
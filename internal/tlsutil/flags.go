@@ -0,0 +1,39 @@
+package tlsutil
+
+import (
+	"flag"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ClientFlags holds the command-line options clients use to connect over TLS.
+type ClientFlags struct {
+	Enabled    bool
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// Register defines the TLS flags on fs.
+func (f *ClientFlags) Register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.Enabled, "tls", false, "connect over TLS, verifying the server against the system roots unless -tls-ca is given")
+	fs.StringVar(&f.CAFile, "tls-ca", "", "PEM file with the CA certificates that sign the server certificate; implies -tls")
+	fs.StringVar(&f.CertFile, "tls-cert", "", "PEM client certificate for mutual TLS; implies -tls")
+	fs.StringVar(&f.KeyFile, "tls-key", "", "PEM private key of the client certificate")
+	fs.StringVar(&f.ServerName, "tls-server-name", "", "server name to verify instead of the host in the address")
+}
+
+// Credentials returns TLS transport credentials when any TLS option is set,
+// and insecure ones otherwise.
+func (f *ClientFlags) Credentials() (credentials.TransportCredentials, error) {
+	if !f.Enabled && f.CAFile == "" && f.CertFile == "" && f.KeyFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	config, err := ClientConfig(f.CAFile, f.CertFile, f.KeyFile, f.ServerName)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
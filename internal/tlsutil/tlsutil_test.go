@@ -0,0 +1,183 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testPKI writes a CA plus server and client certificates signed by it
+type testPKI struct {
+	dir                   string
+	caFile                string
+	serverCert, serverKey string
+	clientCert, clientKey string
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "clavis test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pki := testPKI{dir: dir, caFile: filepath.Join(dir, "ca.pem")}
+	writePEM(t, pki.caFile, "CERTIFICATE", caDER)
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{name},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certFile, keyFile := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+		writePEM(t, certFile, "CERTIFICATE", der)
+		writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+	pki.serverCert, pki.serverKey = issue("localhost", 2, x509.ExtKeyUsageServerAuth)
+	pki.clientCert, pki.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+	return pki
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects a client to a server using the given configurations
+func handshake(t *testing.T, server, client *tls.Config) error {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+	if err == nil {
+		// TLS 1.3 reports client certificate rejection on the first read
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _ = conn.Read(make([]byte, 1))
+		_ = conn.Close()
+	}
+	if sErr := <-serverErr; sErr != nil {
+		return sErr
+	}
+	return err
+}
+
+func TestConfigs(t *testing.T) {
+	pki := newTestPKI(t)
+
+	t.Run("TLS", func(t *testing.T) {
+		server, err := ServerConfig(pki.serverCert, pki.serverKey, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := ClientConfig(pki.caFile, "", "", "localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handshake(t, server, client); err != nil {
+			t.Errorf("Handshake failed: %v", err)
+		}
+	})
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		server, err := ServerConfig(pki.serverCert, pki.serverKey, pki.caFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		withCert, err := ClientConfig(pki.caFile, pki.clientCert, pki.clientKey, "localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handshake(t, server, withCert); err != nil {
+			t.Errorf("Handshake with client certificate failed: %v", err)
+		}
+
+		withoutCert, err := ClientConfig(pki.caFile, "", "", "localhost")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := handshake(t, server, withoutCert); err == nil {
+			t.Error("Expected handshake without a client certificate to fail")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := ServerConfig(pki.serverCert, "", ""); !errors.Is(err, ErrIncompleteKeyPair) {
+			t.Errorf("Expected ErrIncompleteKeyPair, got %v", err)
+		}
+		if _, err := ClientConfig("", pki.clientCert, "", ""); !errors.Is(err, ErrIncompleteKeyPair) {
+			t.Errorf("Expected ErrIncompleteKeyPair, got %v", err)
+		}
+		if _, err := ServerConfig(pki.serverCert, pki.serverKey, pki.serverKey); err == nil {
+			t.Error("Expected an error for a CA file without certificates")
+		}
+		if _, err := ClientConfig(filepath.Join(pki.dir, "missing.pem"), "", "", ""); err == nil {
+			t.Error("Expected an error for a missing CA file")
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// The types below are the OTLP/HTTP JSON encoding of an export request, as
+// specified by opentelemetry-proto. IDs are hex strings and 64-bit integers
+// are decimal strings.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	TraceState        string      `json:"traceState,omitempty"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []eventData `json:"events,omitempty"`
+	Status            statusData  `json:"status"`
+}
+
+type eventData struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type statusData struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	DoubleValue *float64    `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue `json:"arrayValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+// OTLP status codes, which differ in order from codes.Code.
+const (
+	statusUnset = 0
+	statusOK    = 1
+	statusError = 2
+)
+
+// export sends one batch of ended spans to the collector.
+func (p *Provider) export(spans []*span) error {
+	body, err := json.Marshal(p.encode(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range p.config.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := p.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector responded %s", resp.Status)
+	}
+	return nil
+}
+
+// encode groups spans by instrumentation scope into an export request.
+func (p *Provider) encode(spans []*span) exportRequest {
+	var scopes []scopeSpans
+	index := make(map[string]int)
+	for _, s := range spans {
+		i, ok := index[s.scope]
+		if !ok {
+			i = len(scopes)
+			index[s.scope] = i
+			scopes = append(scopes, scopeSpans{Scope: scope{Name: s.scope}})
+		}
+		scopes[i].Spans = append(scopes[i].Spans, s.encode())
+	}
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   resource{Attributes: encodeAttributes([]attribute.KeyValue{attribute.String("service.name", p.config.ServiceName)})},
+		ScopeSpans: scopes,
+	}}}
+}
+
+// encode returns the exported form of an ended span.
+func (s *span) encode() spanData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := spanData{
+		TraceID:           s.spanContext.TraceID().String(),
+		SpanID:            s.spanContext.SpanID().String(),
+		TraceState:        s.spanContext.TraceState().String(),
+		Name:              s.name,
+		Kind:              int(s.kind),
+		StartTimeUnixNano: unixNano(s.start),
+		EndTimeUnixNano:   unixNano(s.end),
+		Attributes:        encodeAttributes(s.attributes),
+	}
+	if s.parent.IsValid() {
+		data.ParentSpanID = s.parent.String()
+	}
+	for _, e := range s.events {
+		data.Events = append(data.Events, eventData{TimeUnixNano: unixNano(e.time), Name: e.name, Attributes: encodeAttributes(e.attributes)})
+	}
+	switch s.statusCode {
+	case codes.Ok:
+		data.Status.Code = statusOK
+	case codes.Error:
+		data.Status = statusData{Code: statusError, Message: s.statusMessage}
+	default:
+		data.Status.Code = statusUnset
+	}
+	return data
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func encodeAttributes(attributes []attribute.KeyValue) []keyValue {
+	encoded := make([]keyValue, 0, len(attributes))
+	for _, attr := range attributes {
+		encoded = append(encoded, keyValue{Key: string(attr.Key), Value: encodeValue(attr.Value)})
+	}
+	return encoded
+}
+
+func encodeValue(v attribute.Value) anyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return anyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return anyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return anyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		values := v.AsBoolSlice()
+		array := &arrayValue{Values: make([]anyValue, len(values))}
+		for i, b := range values {
+			array.Values[i] = encodeValue(attribute.BoolValue(b))
+		}
+		return anyValue{ArrayValue: array}
+	case attribute.INT64SLICE:
+		values := v.AsInt64Slice()
+		array := &arrayValue{Values: make([]anyValue, len(values))}
+		for i, n := range values {
+			array.Values[i] = encodeValue(attribute.Int64Value(n))
+		}
+		return anyValue{ArrayValue: array}
+	case attribute.FLOAT64SLICE:
+		values := v.AsFloat64Slice()
+		array := &arrayValue{Values: make([]anyValue, len(values))}
+		for i, f := range values {
+			array.Values[i] = encodeValue(attribute.Float64Value(f))
+		}
+		return anyValue{ArrayValue: array}
+	case attribute.STRINGSLICE:
+		values := v.AsStringSlice()
+		array := &arrayValue{Values: make([]anyValue, len(values))}
+		for i, str := range values {
+			array.Values[i] = encodeValue(attribute.StringValue(str))
+		}
+		return anyValue{ArrayValue: array}
+	default:
+		str := v.Emit()
+		return anyValue{StringValue: &str}
+	}
+}
@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// event is a timestamped annotation of a span.
+type event struct {
+	name       string
+	time       time.Time
+	attributes []attribute.KeyValue
+}
+
+// span records one operation until it ends and is queued for export.
+type span struct {
+	embedded.Span
+
+	provider    *Provider
+	scope       string
+	spanContext trace.SpanContext
+	parent      trace.SpanID
+	kind        trace.SpanKind
+	start       time.Time
+
+	mu            sync.Mutex
+	name          string
+	end           time.Time
+	attributes    []attribute.KeyValue
+	events        []event
+	statusCode    codes.Code
+	statusMessage string
+	ended         bool
+}
+
+// End completes the span and queues it for export. Only the first call has
+// any effect.
+func (s *span) End(opts ...trace.SpanEndOption) {
+	config := trace.NewSpanEndConfig(opts...)
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = config.Timestamp()
+	if s.end.IsZero() {
+		s.end = time.Now()
+	}
+	s.mu.Unlock()
+	s.provider.enqueue(s)
+}
+
+// AddEvent annotates the span.
+func (s *span) AddEvent(name string, opts ...trace.EventOption) {
+	config := trace.NewEventConfig(opts...)
+	s.addEvent(event{name: name, time: config.Timestamp(), attributes: config.Attributes()})
+}
+
+// AddLink is a no-op; links are not exported.
+func (s *span) AddLink(trace.Link) {}
+
+// IsRecording reports whether the span has not ended yet.
+func (s *span) IsRecording() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.ended
+}
+
+// RecordError annotates the span with an exception event for err. It does
+// not change the span's status.
+func (s *span) RecordError(err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	config := trace.NewEventConfig(opts...)
+	attributes := append([]attribute.KeyValue{
+		attribute.String("exception.type", fmt.Sprintf("%T", err)),
+		attribute.String("exception.message", err.Error()),
+	}, config.Attributes()...)
+	s.addEvent(event{name: "exception", time: config.Timestamp(), attributes: attributes})
+}
+
+// SpanContext returns the identity of the span.
+func (s *span) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+// SetStatus sets the outcome of the span. An Ok status is final, and the
+// description is only kept for errors.
+func (s *span) SetStatus(code codes.Code, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended || code == codes.Unset || s.statusCode == codes.Ok {
+		return
+	}
+	s.statusCode = code
+	s.statusMessage = ""
+	if code == codes.Error {
+		s.statusMessage = description
+	}
+}
+
+// SetName renames the span.
+func (s *span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.name = name
+	}
+}
+
+// SetAttributes adds or replaces attributes of the span.
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	for _, attr := range kv {
+		replaced := false
+		for i := range s.attributes {
+			if s.attributes[i].Key == attr.Key {
+				s.attributes[i], replaced = attr, true
+				break
+			}
+		}
+		if !replaced {
+			s.attributes = append(s.attributes, attr)
+		}
+	}
+}
+
+// TracerProvider returns the provider that created the span.
+func (s *span) TracerProvider() trace.TracerProvider {
+	return s.provider
+}
+
+func (s *span) addEvent(e event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.events = append(s.events, e)
+	}
+}
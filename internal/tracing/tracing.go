@@ -0,0 +1,260 @@
+// Package tracing records OpenTelemetry spans and exports them to an OTLP/HTTP
+// collector. Instrumented code only depends on the OpenTelemetry API, so it
+// produces no spans until a Provider is installed as the global tracer provider.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// Defaults applied to a zero Config.
+const (
+	DefaultServiceName   = "clavis"
+	DefaultBatchSize     = 512
+	DefaultMaxQueueSize  = 4096
+	DefaultFlushInterval = 5 * time.Second
+	DefaultExportTimeout = 10 * time.Second
+)
+
+// Config configures span export.
+type Config struct {
+	Endpoint      string            // Base URL of the OTLP/HTTP collector, such as http://localhost:4318; required
+	ServiceName   string            // service.name resource attribute; DefaultServiceName if empty
+	Headers       map[string]string // Sent with every export, such as collector credentials
+	BatchSize     int               // Spans that trigger an early export; DefaultBatchSize if zero
+	MaxQueueSize  int               // Ended spans held for export before new ones are dropped; DefaultMaxQueueSize if zero
+	FlushInterval time.Duration     // Time between exports; DefaultFlushInterval if zero
+	Client        *http.Client      // Client used to export; one with DefaultExportTimeout if nil
+	Logger        *slog.Logger      // Receives export failures; slog.Default() if nil
+}
+
+// Provider is a trace.TracerProvider that samples every span not explicitly
+// unsampled by its parent and exports ended spans in batches.
+type Provider struct {
+	embedded.TracerProvider
+
+	config   Config
+	endpoint string
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	queue   []*span
+	dropped int
+
+	flush    chan struct{}
+	done     chan struct{}
+	finished chan struct{}
+	stopOnce sync.Once
+}
+
+// New returns a provider exporting to the configured collector. It must be
+// shut down to export the spans still queued.
+func New(config Config) (*Provider, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("tracing endpoint is required")
+	}
+	endpoint, err := url.JoinPath(config.Endpoint, "v1", "traces")
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.MaxQueueSize <= 0 {
+		config.MaxQueueSize = DefaultMaxQueueSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: DefaultExportTimeout}
+	}
+
+	p := &Provider{
+		config:   config,
+		endpoint: endpoint,
+		logger:   logging.Or(config.Logger),
+		flush:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// Install makes p the global tracer provider and W3C trace context the global
+// propagator, so instrumented packages and interceptors start recording.
+func Install(p *Provider) {
+	otel.SetTracerProvider(p)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Tracer returns a tracer whose spans are attributed to the named
+// instrumentation scope.
+func (p *Provider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return &tracer{provider: p, scope: name}
+}
+
+// Shutdown stops the provider after exporting the queued spans, or when ctx
+// is done. Spans ended afterwards are dropped.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.done) })
+	select {
+	case <-p.finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue queues an ended span for export.
+func (p *Provider) enqueue(s *span) {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+
+	p.mu.Lock()
+	if len(p.queue) >= p.config.MaxQueueSize {
+		p.dropped++
+		p.mu.Unlock()
+		return
+	}
+	p.queue = append(p.queue, s)
+	full := len(p.queue) >= p.config.BatchSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run exports queued spans every flush interval or once a batch is full,
+// and one last time on shutdown.
+func (p *Provider) run() {
+	defer close(p.finished)
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.flush:
+		case <-p.done:
+			p.exportQueued()
+			return
+		}
+		p.exportQueued()
+	}
+}
+
+// exportQueued exports and empties the queue, logging failures.
+func (p *Provider) exportQueued() {
+	p.mu.Lock()
+	spans, dropped := p.queue, p.dropped
+	p.queue, p.dropped = nil, 0
+	p.mu.Unlock()
+
+	if dropped > 0 {
+		p.logger.Warn("Dropped spans over the tracing queue limit", "spans", dropped)
+	}
+	for len(spans) > 0 {
+		batch := spans[:min(len(spans), p.config.BatchSize)]
+		spans = spans[len(batch):]
+		if err := p.export(batch); err != nil {
+			p.logger.Warn("Failed to export spans", "spans", len(batch), "error", err)
+		}
+	}
+}
+
+// tracer starts spans for one instrumentation scope.
+type tracer struct {
+	embedded.Tracer
+
+	provider *Provider
+	scope    string
+}
+
+// Start starts a span as a child of the span in ctx, or of the remote span
+// context in ctx such as one extracted from request headers. Children of
+// unsampled parents are not recorded.
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	if config.NewRoot() {
+		parent = trace.SpanContext{}
+	}
+
+	spanContext := trace.SpanContextConfig{
+		TraceID:    parent.TraceID(),
+		SpanID:     newSpanID(),
+		TraceFlags: trace.FlagsSampled,
+		TraceState: parent.TraceState(),
+	}
+	if !parent.IsValid() {
+		spanContext.TraceID = newTraceID()
+	}
+	if parent.IsValid() && !parent.IsSampled() {
+		spanContext.TraceFlags = 0
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(spanContext))
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	start := config.Timestamp()
+	if start.IsZero() {
+		start = time.Now()
+	}
+	s := &span{
+		provider:    t.provider,
+		scope:       t.scope,
+		name:        name,
+		spanContext: trace.NewSpanContext(spanContext),
+		parent:      parent.SpanID(),
+		kind:        config.SpanKind(),
+		start:       start,
+		attributes:  config.Attributes(),
+	}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// newTraceID returns a random, valid trace ID.
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	for !id.IsValid() {
+		for i := range id {
+			id[i] = byte(rand.Uint32())
+		}
+	}
+	return id
+}
+
+// newSpanID returns a random, valid span ID.
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	for !id.IsValid() {
+		for i := range id {
+			id[i] = byte(rand.Uint32())
+		}
+	}
+	return id
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// collector is an OTLP/HTTP endpoint recording the spans exported to it.
+type collector struct {
+	mu       sync.Mutex
+	requests []exportRequest
+	headers  http.Header
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "unexpected export", http.StatusBadRequest)
+		return
+	}
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	c.headers = r.Header
+}
+
+// spans returns the exported spans by name.
+func (c *collector) spans() map[string]spanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	spans := make(map[string]spanData)
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					spans[s.Name] = s
+				}
+			}
+		}
+	}
+	return spans
+}
+
+func TestProvider(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	p, err := New(Config{Endpoint: server.URL, FlushInterval: time.Hour, Headers: map[string]string{"Authorization": "Bearer t"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := p.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root", trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attribute.String("k", "v")))
+	_, child := tracer.Start(ctx, "child")
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	root.SetAttributes(attribute.Int("n", 3))
+	root.End()
+	root.End() // ending twice exports once
+
+	// A remote parent that was not sampled suppresses its children
+	unsampled := trace.NewSpanContext(trace.SpanContextConfig{TraceID: newTraceID(), SpanID: newSpanID(), Remote: true})
+	_, skipped := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), unsampled), "skipped")
+	if skipped.IsRecording() {
+		t.Error("Expected children of unsampled parents not to record")
+	}
+	skipped.End()
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := c.spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans exported, got %v", spans)
+	}
+	r, ch := spans["root"], spans["child"]
+	if ch.TraceID != r.TraceID || ch.ParentSpanID != r.SpanID || r.ParentSpanID != "" {
+		t.Errorf("Expected child to be parented by root, got root %+v and child %+v", r, ch)
+	}
+	if r.Kind != int(trace.SpanKindServer) || len(r.Attributes) != 2 || r.Status.Code != statusUnset {
+		t.Errorf("Unexpected root span %+v", r)
+	}
+	if ch.Status.Code != statusError || ch.Status.Message != "boom" || len(ch.Events) != 1 || ch.Events[0].Name != "exception" {
+		t.Errorf("Unexpected child span %+v", ch)
+	}
+	service := c.requests[0].ResourceSpans[0].Resource.Attributes[0]
+	if service.Key != "service.name" || *service.Value.StringValue != DefaultServiceName {
+		t.Errorf("Expected the default service name, got %+v", service)
+	}
+	if c.headers.Get("Authorization") != "Bearer t" {
+		t.Errorf("Expected configured headers to be sent, got %v", c.headers)
+	}
+}
+
+func TestProvider_ExportsFullBatches(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	p, err := New(Config{Endpoint: server.URL, FlushInterval: time.Hour, BatchSize: 2, MaxQueueSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = p.Shutdown(context.Background()) }()
+
+	tracer := p.Tracer("test")
+	for _, name := range []string{"a", "b"} {
+		_, s := tracer.Start(context.Background(), name)
+		s.End()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.spans()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(c.spans()) != 2 {
+		t.Errorf("Expected a full batch to be exported early, got %v", c.spans())
+	}
+}
+
+func TestNew_RequiresEndpoint(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("Expected an error without an endpoint")
+	}
+}
@@ -0,0 +1,62 @@
+// Package warnings carries non-fatal conditions met while serving a request,
+// such as a namespace nearing its quota, back to the server so they can be
+// reported to the client alongside a successful response.
+package warnings
+
+import (
+	"context"
+	"sync"
+)
+
+// Code is a machine-readable warning reason.
+type Code string
+
+const (
+	CodeNearQuota    Code = "near_quota"    // A limit is close to being reached
+	CodeDeprecated   Code = "deprecated"    // The request used a deprecated feature
+	CodeNormalized   Code = "normalized"    // The request was changed before it was applied
+	CodeDuplicateKey Code = "duplicate_key" // A batch named a key more than once
+)
+
+// Warning is a non-fatal condition met while serving a request.
+type Warning struct {
+	Code     Code
+	Message  string
+	Key      string            // Key the warning is about, if any
+	Metadata map[string]string // Details such as the limit being approached
+}
+
+// Collector accumulates the warnings of one request. It is safe for
+// concurrent use.
+type Collector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// Warnings returns the warnings added so far, in the order they were added.
+func (c *Collector) Warnings() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Warning(nil), c.warnings...)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying a new collector, which Add
+// appends to.
+func NewContext(ctx context.Context) (context.Context, *Collector) {
+	collector := &Collector{}
+	return context.WithValue(ctx, contextKey{}, collector), collector
+}
+
+// Add records w on the collector carried by ctx. It does nothing when ctx
+// carries none, so code outside requests can warn unconditionally.
+func Add(ctx context.Context, w Warning) {
+	collector, ok := ctx.Value(contextKey{}).(*Collector)
+	if !ok {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.warnings = append(collector.warnings, w)
+}
@@ -0,0 +1,21 @@
+package warnings
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	// Warnings outside a request are dropped
+	Add(context.Background(), Warning{Code: CodeDeprecated})
+
+	ctx, collector := NewContext(context.Background())
+	Add(ctx, Warning{Code: CodeNearQuota, Key: "a"})
+	Add(ctx, Warning{Code: CodeNormalized, Key: "b"})
+
+	want := []Warning{{Code: CodeNearQuota, Key: "a"}, {Code: CodeNormalized, Key: "b"}}
+	if got := collector.Warnings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
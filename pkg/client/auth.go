@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenCredentials attaches an API token to every call as a bearer token.
+type TokenCredentials struct {
+	Token string
+	// AllowInsecure permits sending the token over connections without
+	// transport security, for local development.
+	AllowInsecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.Token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c TokenCredentials) RequireTransportSecurity() bool {
+	return !c.AllowInsecure
+}
+
+// WithToken returns a dial option authenticating every call with token. The
+// connection must use TLS unless allowInsecure is set.
+func WithToken(token string, allowInsecure bool) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(TokenCredentials{Token: token, AllowInsecure: allowInsecure})
+}
+
+var _ credentials.PerRPCCredentials = TokenCredentials{}
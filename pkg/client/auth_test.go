@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTokenCredentials(t *testing.T) {
+	creds := TokenCredentials{Token: "secret"}
+	md, err := creds.GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md["authorization"] != "Bearer secret" {
+		t.Errorf("Expected bearer authorization metadata, got %v", md)
+	}
+	if !creds.RequireTransportSecurity() {
+		t.Error("Expected tokens to require transport security by default")
+	}
+	if (TokenCredentials{Token: "secret", AllowInsecure: true}).RequireTransportSecurity() {
+		t.Error("Expected AllowInsecure to lift the transport security requirement")
+	}
+}
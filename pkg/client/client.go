@@ -0,0 +1,424 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// DefaultAddr is the address Clavis servers listen on by default.
+const DefaultAddr = "localhost:50051"
+
+// maxScanPage bounds the pages Scan requests from the server.
+const maxScanPage = 1000
+
+// Config configures a Client. The zero value connects to a local server
+// without TLS or authentication.
+type Config struct {
+	// Credentials secure the connection, such as credentials.NewTLS with a
+	// configuration from tlsutil; insecure credentials are used if nil.
+	Credentials credentials.TransportCredentials
+	// Token authenticates every call when set. Tokens are only sent over
+	// insecure connections with AllowInsecureToken.
+	Token              string
+	AllowInsecureToken bool
+	// Namespace scopes the keys of the typed methods; empty addresses the
+	// whole keyspace.
+	Namespace string
+	// Timeout bounds each call whose context has no deadline; zero leaves
+	// such calls unbounded.
+	Timeout time.Duration
+	// Retry retries transient failures of idempotent calls when set.
+	Retry *RetryConfig
+	// Throttle limits the load the client puts on the server when set.
+	Throttle *ThrottleConfig
+	// Pool spreads calls over several connections when set.
+	Pool *PoolConfig
+	// DialOptions are appended to the options derived from the fields above.
+	DialOptions []grpc.DialOption
+}
+
+// Client is a connection to a Clavis server with typed key operations.
+// It is safe for concurrent use.
+type Client struct {
+	conn      io.Closer
+	rpc       proto.ClavisClient
+	namespace string
+	timeout   time.Duration
+}
+
+// New creates a client of the server at addr. The connection is established
+// lazily by the first call.
+func New(addr string, config Config) (*Client, error) {
+	creds := config.Credentials
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if config.Token != "" {
+		opts = append(opts, WithToken(config.Token, config.AllowInsecureToken))
+	}
+	if config.Retry != nil {
+		opts = append(opts, WithRetries(*config.Retry))
+	}
+	if config.Throttle != nil {
+		opts = append(opts, NewThrottle(*config.Throttle).DialOptions()...)
+	}
+	opts = append(opts, config.DialOptions...)
+
+	if config.Pool != nil {
+		pool, err := NewPool(addr, *config.Pool, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &Client{conn: pool, rpc: proto.NewClavisClient(pool), namespace: config.Namespace, timeout: config.Timeout}, nil
+	}
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, rpc: proto.NewClavisClient(conn), namespace: config.Namespace, timeout: config.Timeout}, nil
+}
+
+// Close closes the connection, or every connection of the pool. Calls in
+// flight fail.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Clavis returns the generated gRPC client over the same connection, for
+// the calls the typed methods do and for helpers such as ScanAll and
+// UpdateJSON.
+func (c *Client) Clavis() proto.ClavisClient {
+	return c.rpc
+}
+
+// withTimeout applies the default timeout to ctx unless it has a deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// Get retrieves the value of key. Returns the value, a boolean indicating if
+// the key exists, and an error if any.
+func (c *Client) Get(ctx context.Context, key string, opts ...grpc.CallOption) ([]byte, bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Get(ctx, &proto.GetRequest{Key: key, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.Value, resp.Found, nil
+}
+
+// Exists reports whether key exists, without transferring its value.
+func (c *Client) Exists(ctx context.Context, key string, opts ...grpc.CallOption) (bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Exists(ctx, &proto.ExistsRequest{Key: key, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return false, err
+	}
+	return resp.Found, nil
+}
+
+// Metadata returns when key was created and last updated, and how many times
+// it was written, reporting false if it does not exist. It fails with
+// UNIMPLEMENTED when the server does not track key metadata.
+func (c *Client) Metadata(ctx context.Context, key string, opts ...grpc.CallOption) (*proto.KeyMetadata, bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.GetMetadata(ctx, &proto.GetMetadataRequest{Key: key, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.Metadata, resp.Found, nil
+}
+
+// Count returns the number of keys starting with prefix.
+func (c *Client) Count(ctx context.Context, prefix string, opts ...grpc.CallOption) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Count(ctx, &proto.CountRequest{Prefix: prefix, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// PreviewDeletePrefix counts the keys starting with prefix and returns the
+// count with the confirmation token DeletePrefix needs to delete them.
+func (c *Client) PreviewDeletePrefix(ctx context.Context, prefix string, opts ...grpc.CallOption) (*proto.DeletePrefixResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.rpc.DeletePrefix(ctx, &proto.DeletePrefixRequest{Prefix: prefix, Namespace: c.namespace, DryRun: true}, opts...)
+}
+
+// DeletePrefix deletes every key starting with prefix, confirmed by the token
+// of a PreviewDeletePrefix of the same prefix, and returns how many were
+// deleted. An empty prefix deletes every key of the client's namespace, and
+// is rejected without one.
+func (c *Client) DeletePrefix(ctx context.Context, prefix, token string, opts ...grpc.CallOption) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.DeletePrefix(ctx, &proto.DeletePrefixRequest{Prefix: prefix, Namespace: c.namespace, ConfirmationToken: token}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// QueryByTag returns up to limit keys carrying tag that start with prefix, in
+// key order, requesting pages from the server as needed; a zero limit returns
+// every such key. The timeout applies to each page.
+func (c *Client) QueryByTag(ctx context.Context, tag, prefix string, limit int, opts ...grpc.CallOption) ([]string, error) {
+	var keys []string
+	req := &proto.QueryByTagRequest{Tag: tag, Prefix: prefix, Namespace: c.namespace}
+	for {
+		req.Limit = maxScanPage
+		if limit > 0 {
+			req.Limit = int32(min(limit-len(keys), maxScanPage)) // #nosec G115 -- bounded by the min above
+		}
+		pageCtx, cancel := c.withTimeout(ctx)
+		resp, err := c.rpc.QueryByTag(pageCtx, req, opts...)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, resp.Keys...)
+		if resp.NextCursor == "" || (limit > 0 && len(keys) >= limit) {
+			return keys, nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+}
+
+// Search returns up to limit keys starting with prefix whose values contain
+// every word of query, best matches first; a zero limit selects the server
+// default. It fails with UNIMPLEMENTED when the server does not index values.
+func (c *Client) Search(ctx context.Context, query, prefix string, limit int, opts ...grpc.CallOption) ([]*proto.SearchHit, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Search(ctx, &proto.SearchRequest{Query: query, Prefix: prefix, Limit: int32(min(limit, maxScanPage)), Namespace: c.namespace}, opts...) // #nosec G115 -- bounded by the min
+	if err != nil {
+		return nil, err
+	}
+	return resp.Hits, nil
+}
+
+// Subscribe calls visit with each change to the keys starting with prefix,
+// with the new values of puts, until ctx is done or visit returns an error,
+// which is returned. The timeout does not apply. It fails with a
+// RESOURCE_EXHAUSTED status coded SLOW_CONSUMER if visit falls too far
+// behind the changes, after which the caller may resubscribe and rescan.
+func (c *Client) Subscribe(ctx context.Context, prefix string, visit func(*proto.WatchEvent) error, opts ...grpc.CallOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.rpc.Subscribe(ctx, &proto.SubscribeRequest{Prefix: prefix, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := visit(event); err != nil {
+			return err
+		}
+	}
+}
+
+// GetMany returns the values of the keys that exist and, in request order,
+// the keys that do not, reading every key in one request. The server limits
+// how many keys one request may name.
+func (c *Client) GetMany(ctx context.Context, keys []string, opts ...grpc.CallOption) (map[string][]byte, []string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.BatchGet(ctx, &proto.BatchGetRequest{Keys: keys, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := make(map[string][]byte, len(resp.Items))
+	for _, item := range resp.Items {
+		values[item.Key] = item.Value
+	}
+	return values, resp.Missing, nil
+}
+
+// Put stores value at key. Returns the warnings the server reported for the
+// write, which did not prevent it, and an error if any.
+func (c *Client) Put(ctx context.Context, key string, value []byte, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Warnings, nil
+}
+
+// PutTagged stores value at key, replacing the tags of key with tags. It
+// fails with UNIMPLEMENTED when the server does not index tags.
+func (c *Client) PutTagged(ctx context.Context, key string, value []byte, tags []string, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: c.namespace, Tags: tags}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Warnings, nil
+}
+
+// CreateUnique stores value under a new key the server generates from prefix
+// and kind, and returns the key.
+func (c *Client) CreateUnique(ctx context.Context, prefix string, kind proto.KeyKind, value []byte, opts ...grpc.CallOption) (string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.CreateUnique(ctx, &proto.CreateUniqueRequest{Prefix: prefix, Kind: kind, Value: value, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return "", err
+	}
+	return resp.Key, nil
+}
+
+// PutIfAbsent stores value at key only if key does not exist yet. An existing
+// key fails with codes.FailedPrecondition and leaves its value as it was.
+// Returns the warnings the server reported for the write, and an error if any.
+func (c *Client) PutIfAbsent(ctx context.Context, key string, value []byte, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: c.namespace, MustNotExist: true}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Warnings, nil
+}
+
+// Increment adds delta to the counter stored at key as decimal digits, a
+// missing key counting as 0, and returns the new value. Counters stored in
+// another encoding are incremented with the Increment RPC directly.
+func (c *Client) Increment(ctx context.Context, key string, delta int64, opts ...grpc.CallOption) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Increment(ctx, &proto.IncrementRequest{Key: key, Delta: delta, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Value, nil
+}
+
+// Delete removes key. Deleting a key that does not exist is not an error.
+// Returns the warnings the server reported, and an error if any.
+func (c *Client) Delete(ctx context.Context, key string, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Delete(ctx, &proto.DeleteRequest{Key: key, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Warnings, nil
+}
+
+// Undelete restores the value key had when it was deleted. It fails with
+// NOT_FOUND when there is no deleted value left to restore, and UNSUPPORTED
+// when the server does not keep deleted values.
+func (c *Client) Undelete(ctx context.Context, key string, opts ...grpc.CallOption) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.rpc.Undelete(ctx, &proto.UndeleteRequest{Key: key, Namespace: c.namespace}, opts...)
+	return err
+}
+
+// ScanFunc calls visit with up to limit pairs whose keys start with prefix,
+// in key order, requesting pages from the server as needed; a zero limit
+// visits every pair. The timeout applies to each page. An error from visit
+// stops the scan and is returned.
+func (c *Client) ScanFunc(ctx context.Context, prefix string, limit int, visit func(key string, value []byte) error, opts ...grpc.CallOption) error {
+	count := 0
+	req := &proto.ScanRequest{Prefix: prefix, Namespace: c.namespace}
+	for {
+		req.Limit = maxScanPage
+		if limit > 0 {
+			req.Limit = int32(min(limit-count, maxScanPage)) // #nosec G115 -- bounded by the min above
+		}
+		resp, err := c.scanPage(ctx, req, opts...)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			if err := visit(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		count += len(resp.Items)
+		if resp.NextCursor == "" || (limit > 0 && count >= limit) {
+			return nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+}
+
+func (c *Client) scanPage(ctx context.Context, req *proto.ScanRequest, opts ...grpc.CallOption) (*proto.ScanResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.rpc.Scan(ctx, req, opts...)
+}
+
+// RangeFunc calls visit with up to limit pairs whose keys are in [start, end),
+// in key order or, if reverse, in descending order from end, requesting pages
+// from the server as needed. Empty bounds leave that side of the range open,
+// and a zero limit visits every pair. The timeout applies to each page. An
+// error from visit stops the iteration and is returned.
+func (c *Client) RangeFunc(ctx context.Context, start, end string, reverse bool, limit int, visit func(key string, value []byte) error, opts ...grpc.CallOption) error {
+	count := 0
+	req := &proto.RangeRequest{Start: start, End: end, Reverse: reverse, Namespace: c.namespace}
+	for {
+		req.Limit = maxScanPage
+		if limit > 0 {
+			req.Limit = int32(min(limit-count, maxScanPage)) // #nosec G115 -- bounded by the min above
+		}
+		resp, err := c.rangePage(ctx, req, opts...)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			if err := visit(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		count += len(resp.Items)
+		if resp.NextCursor == "" || (limit > 0 && count >= limit) {
+			return nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+}
+
+func (c *Client) rangePage(ctx context.Context, req *proto.RangeRequest, opts ...grpc.CallOption) (*proto.RangeResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.rpc.Range(ctx, req, opts...)
+}
+
+// Scan returns up to limit pairs whose keys start with prefix; a zero limit
+// returns every pair. Use ScanFunc to process large results without holding
+// them in memory.
+func (c *Client) Scan(ctx context.Context, prefix string, limit int, opts ...grpc.CallOption) (map[string][]byte, error) {
+	pairs := make(map[string][]byte)
+	err := c.ScanFunc(ctx, prefix, limit, func(key string, value []byte) error {
+		pairs[key] = value
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// mapServer serves the key operations from a map, with pages of at most two
+// items so scans need cursors.
+type mapServer struct {
+	proto.UnimplementedClavisServer
+	mu        sync.Mutex
+	data      map[string][]byte
+	deadlines []bool
+	peers     map[string]bool
+}
+
+func (s *mapServer) Get(ctx context.Context, req *proto.GetRequest) (*proto.GetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := ctx.Deadline()
+	s.deadlines = append(s.deadlines, ok)
+	if p, ok := peer.FromContext(ctx); ok {
+		s.peers[p.Addr.String()] = true
+	}
+	value, found := s.data[req.Key]
+	return &proto.GetResponse{Value: value, Found: found}, nil
+}
+
+func (s *mapServer) Put(ctx context.Context, req *proto.PutRequest) (*proto.PutResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[req.Key] = req.Value
+	return &proto.PutResponse{Warnings: []*proto.Warning{{Code: WarningNearQuota}}}, nil
+}
+
+func (s *mapServer) Delete(ctx context.Context, req *proto.DeleteRequest) (*proto.DeleteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, req.Key)
+	return &proto.DeleteResponse{}, nil
+}
+
+func (s *mapServer) Scan(ctx context.Context, req *proto.ScanRequest) (*proto.ScanResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.data {
+		if strings.HasPrefix(key, req.Prefix) && key > req.Cursor {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	resp := &proto.ScanResponse{}
+	for _, key := range keys {
+		if len(resp.Items) == 2 || (req.Limit > 0 && len(resp.Items) == int(req.Limit)) {
+			resp.NextCursor = resp.Items[len(resp.Items)-1].Key
+			break
+		}
+		resp.Items = append(resp.Items, &proto.KeyValue{Key: key, Value: s.data[key]})
+	}
+	return resp, nil
+}
+
+func (s *mapServer) Range(ctx context.Context, req *proto.RangeRequest) (*proto.RangeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.data {
+		if key >= req.Start && (req.End == "" || key < req.End) && key > req.Cursor {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	resp := &proto.RangeResponse{}
+	for _, key := range keys {
+		if len(resp.Items) == 2 || (req.Limit > 0 && len(resp.Items) == int(req.Limit)) {
+			resp.NextCursor = resp.Items[len(resp.Items)-1].Key
+			break
+		}
+		resp.Items = append(resp.Items, &proto.KeyValue{Key: key, Value: s.data[key]})
+	}
+	return resp, nil
+}
+
+func (s *mapServer) BatchGet(ctx context.Context, req *proto.BatchGetRequest) (*proto.BatchGetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &proto.BatchGetResponse{}
+	for _, key := range req.Keys {
+		if value, found := s.data[key]; found {
+			resp.Items = append(resp.Items, &proto.KeyValue{Key: key, Value: value})
+		} else {
+			resp.Missing = append(resp.Missing, key)
+		}
+	}
+	return resp, nil
+}
+
+func (s *mapServer) Exists(ctx context.Context, req *proto.ExistsRequest) (*proto.ExistsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, found := s.data[req.Key]
+	return &proto.ExistsResponse{Found: found}, nil
+}
+
+func (s *mapServer) Count(ctx context.Context, req *proto.CountRequest) (*proto.CountResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &proto.CountResponse{}
+	for key := range s.data {
+		if strings.HasPrefix(key, req.Prefix) {
+			resp.Count++
+		}
+	}
+	return resp, nil
+}
+
+func (s *mapServer) DeletePrefix(ctx context.Context, req *proto.DeletePrefixRequest) (*proto.DeletePrefixResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &proto.DeletePrefixResponse{}
+	if req.DryRun {
+		resp.ConfirmationToken = "confirm:" + req.Prefix
+	} else if req.ConfirmationToken != "confirm:"+req.Prefix {
+		return nil, status.Error(codes.FailedPrecondition, "invalid confirmation token")
+	}
+	for key := range s.data {
+		if strings.HasPrefix(key, req.Prefix) {
+			resp.Count++
+			if !req.DryRun {
+				delete(s.data, key)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Copy copies values without leases, reporting lease 1 for any TTL.
+func (s *mapServer) Copy(ctx context.Context, req *proto.CopyRequest) (*proto.CopyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[req.Source]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "source key not found")
+	}
+	if _, exists := s.data[req.Destination]; exists && !req.Overwrite {
+		return nil, status.Error(codes.FailedPrecondition, "key already exists")
+	}
+	s.data[req.Destination] = value
+	resp := &proto.CopyResponse{}
+	if req.TtlMs > 0 {
+		resp.Lease = 1
+	}
+	return resp, nil
+}
+
+func startMapServer(t *testing.T, services ...func(*grpc.Server)) (*mapServer, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &mapServer{data: map[string][]byte{}, peers: map[string]bool{}}
+	server := grpc.NewServer()
+	proto.RegisterClavisServer(server, backend)
+	for _, register := range services {
+		register(server)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return backend, listener.Addr().String()
+}
+
+func TestClient(t *testing.T) {
+	backend, addr := startMapServer(t)
+	c, err := New(addr, Config{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+
+	for _, key := range []string{"user/1", "user/2", "user/3", "order/1"} {
+		warnings, err := c.Put(ctx, key, []byte("v-"+key))
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if len(warnings) != 1 || warnings[0].Code != WarningNearQuota {
+			t.Errorf("Expected the server's warning, got %v", warnings)
+		}
+	}
+
+	value, found, err := c.Get(ctx, "user/1")
+	if err != nil || !found || string(value) != "v-user/1" {
+		t.Errorf("Expected v-user/1, got %q %v %v", value, found, err)
+	}
+	if !backend.deadlines[0] {
+		t.Error("Expected the default timeout to set a deadline")
+	}
+
+	pairs, err := c.Scan(ctx, "user/", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 3 || string(pairs["user/3"]) != "v-user/3" {
+		t.Errorf("Expected every user across pages, got %v", pairs)
+	}
+	var keys []string
+	err = c.ScanFunc(ctx, "", 3, func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil || !slices.Equal(keys, []string{"order/1", "user/1", "user/2"}) {
+		t.Errorf("Expected the first 3 keys, got %v %v", keys, err)
+	}
+
+	keys = nil
+	err = c.RangeFunc(ctx, "order/1", "user/3", false, 0, func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil || !slices.Equal(keys, []string{"order/1", "user/1", "user/2"}) {
+		t.Errorf("Expected the keys in the range across pages, got %v %v", keys, err)
+	}
+
+	values, missing, err := c.GetMany(ctx, []string{"user/1", "user/9", "order/1"})
+	if err != nil || len(values) != 2 || string(values["order/1"]) != "v-order/1" || !slices.Equal(missing, []string{"user/9"}) {
+		t.Errorf("Expected two values and user/9 missing, got %v %v %v", values, missing, err)
+	}
+
+	if found, err := c.Exists(ctx, "user/2"); err != nil || !found {
+		t.Errorf("Expected user/2 to exist, got %v %v", found, err)
+	}
+	if n, err := c.Count(ctx, "user/"); err != nil || n != 3 {
+		t.Errorf("Expected 3 users, got %d %v", n, err)
+	}
+
+	if _, err := c.Delete(ctx, "user/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := c.Get(ctx, "user/1"); found {
+		t.Error("Expected the deleted key to be gone")
+	}
+
+	if lease, err := c.Copy(ctx, "order/1", "order/9", CopyOptions{TTL: time.Minute}); err != nil || lease != 1 {
+		t.Errorf("Expected a copy with a lease granted, got %d %v", lease, err)
+	}
+	if _, err := c.Copy(ctx, "user/2", "order/9", CopyOptions{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected copying over an existing key to fail, got %v", err)
+	}
+	if lease, err := c.Copy(ctx, "user/2", "order/9", CopyOptions{Overwrite: true}); err != nil || lease != 0 {
+		t.Errorf("Expected an overwriting copy without a lease, got %d %v", lease, err)
+	}
+	if value, _, _ := c.Get(ctx, "order/9"); string(value) != "v-user/2" {
+		t.Errorf("Expected the copied value, got %q", value)
+	}
+
+	preview, err := c.PreviewDeletePrefix(ctx, "user/")
+	if err != nil || preview.Count != 2 {
+		t.Fatalf("Expected a preview to count 2 users, got %v %v", preview, err)
+	}
+	if _, err := c.DeletePrefix(ctx, "user/", ""); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected an unconfirmed delete to fail, got %v", err)
+	}
+	if n, err := c.DeletePrefix(ctx, "user/", preview.ConfirmationToken); err != nil || n != 2 {
+		t.Errorf("Expected 2 users deleted, got %d %v", n, err)
+	}
+	if n, err := c.Count(ctx, "user/"); err != nil || n != 0 {
+		t.Errorf("Expected no user left, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// CopyOptions controls how Copy writes the destination.
+type CopyOptions struct {
+	Overwrite bool          // Replace the value of an existing destination instead of failing
+	Lease     uint64        // Attach the destination to this live lease
+	TTL       time.Duration // Grant the destination a lease of its own lasting TTL; exclusive with Lease
+}
+
+// Copy copies the value of source to destination on the server, so it does
+// not travel through the client. It returns the ID of the lease granted for
+// options.TTL, or 0 without one; like any lease, it can be kept alive or
+// revoked. An existing destination fails with FAILED_PRECONDITION unless
+// options.Overwrite is set.
+func (c *Client) Copy(ctx context.Context, source, destination string, options CopyOptions, opts ...grpc.CallOption) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Copy(ctx, &proto.CopyRequest{
+		Source:      source,
+		Destination: destination,
+		Namespace:   c.namespace,
+		Overwrite:   options.Overwrite,
+		Lease:       options.Lease,
+		TtlMs:       options.TTL.Milliseconds(),
+	}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Lease, nil
+}
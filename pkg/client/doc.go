@@ -0,0 +1,5 @@
+// Package client contains helpers for applications talking to a Clavis
+// server over gRPC. Client wraps a connection with typed key operations, and
+// ShardedClient spreads them over several independent servers; the other
+// helpers work with any proto.ClavisClient, including Client.Clavis.
+package client
@@ -0,0 +1,16 @@
+package client
+
+import claviserrors "github.com/William-Fernandes252/clavis/pkg/errors"
+
+// FieldViolation is a field of a request the server rejected.
+type FieldViolation = claviserrors.Violation
+
+// FieldViolations returns the fields the server rejected in the request that
+// failed with err, decoded from the google.rpc.BadRequest and ErrorInfo
+// details of its status. It returns nil for any other error.
+func FieldViolations(err error) []FieldViolation {
+	if err == nil {
+		return nil
+	}
+	return claviserrors.FromError(err).Violations
+}
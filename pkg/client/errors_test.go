@@ -0,0 +1,40 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFieldViolations(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid request").WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "key", Reason: "TOO_LONG", Description: "key is too long"},
+			{Field: "value", Reason: "INVALID_CONTENT", Description: "value is not JSON"},
+		}},
+		&errdetails.ErrorInfo{Reason: "TOO_LONG", Domain: "clavis", Metadata: map[string]string{"field": "key", "max": "16"}},
+		&errdetails.ErrorInfo{Reason: "INVALID_CONTENT", Domain: "clavis", Metadata: map[string]string{"field": "value"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations := FieldViolations(fmt.Errorf("put: %w", st.Err()))
+	if len(violations) != 2 {
+		t.Fatalf("FieldViolations = %v, want 2", violations)
+	}
+	if v := violations[0]; v.Field != "key" || v.Code != "TOO_LONG" || v.Message != "key is too long" || v.Metadata["max"] != "16" {
+		t.Errorf("First violation = %+v", v)
+	}
+	if v := violations[1]; v.Field != "value" || v.Code != "INVALID_CONTENT" {
+		t.Errorf("Second violation = %+v", v)
+	}
+
+	if FieldViolations(status.Error(codes.Internal, "boom")) != nil || FieldViolations(errors.New("boom")) != nil {
+		t.Error("Expected no violations without BadRequest details")
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// GrantLease grants a lease for ttl, the server default if zero, and returns
+// its ID. Keys attached to it with PutLeased or AttachLease are deleted when
+// it expires or is revoked, or when a KeepAlive of it stops.
+func (c *Client) GrantLease(ctx context.Context, ttl time.Duration, opts ...grpc.CallOption) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.GrantLease(ctx, &proto.GrantLeaseRequest{TtlMs: ttl.Milliseconds()}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+// PutLeased stores value at key, attached to the lease id.
+func (c *Client) PutLeased(ctx context.Context, key string, value []byte, id uint64, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Put(ctx, &proto.PutRequest{Key: key, Value: value, Namespace: c.namespace, Lease: id}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Warnings, nil
+}
+
+// AttachLease attaches existing keys to the lease id.
+func (c *Client) AttachLease(ctx context.Context, id uint64, keys []string, opts ...grpc.CallOption) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, err := c.rpc.AttachLease(ctx, &proto.AttachLeaseRequest{Id: id, Keys: keys, Namespace: c.namespace}, opts...)
+	return err
+}
+
+// KeepAlive keeps the lease id alive, renewing it every interval, until ctx
+// is done or the lease is lost; the timeout does not apply. The server
+// revokes the lease as soon as KeepAlive returns, which it does with nil
+// only when ctx is done.
+func (c *Client) KeepAlive(ctx context.Context, id uint64, interval time.Duration, opts ...grpc.CallOption) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.rpc.KeepAlive(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := stream.Send(&proto.KeepAliveRequest{Id: id})
+		// Send fails with io.EOF when the server ended the stream; Recv
+		// returns why
+		if err == nil || errors.Is(err, io.EOF) {
+			_, err = stream.Recv()
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RevokeLease ends the lease id at once, deleting the keys attached to it,
+// and returns how many keys were attached.
+func (c *Client) RevokeLease(ctx context.Context, id uint64, opts ...grpc.CallOption) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.RevokeLease(ctx, &proto.RevokeLeaseRequest{Id: id}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.AttachedKeys, nil
+}
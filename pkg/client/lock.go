@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// DefaultLockTTL is the lease duration requested by Lock when none is given.
+const DefaultLockTTL = 30 * time.Second
+
+// Lease is a lock held through a Client. It is renewed in the background
+// every third of its TTL until Unlock is called or a renewal fails, which
+// closes Lost: from then on the holder must stop writing under the lock.
+type Lease struct {
+	// Fencing is the token to attach to the writes made under the lock, so
+	// that the server rejects them once a newer lease is granted.
+	Fencing *proto.FencingToken
+
+	client *Client
+	name   string
+	ttl    time.Duration
+	lost   chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// Lock acquires a lease on the named lock for ttl, DefaultLockTTL if zero,
+// and starts renewing it. It fails with FAILED_PRECONDITION, coded
+// LOCK_HELD, while another lease on the lock is live.
+func (c *Client) Lock(ctx context.Context, name, owner string, ttl time.Duration, opts ...grpc.CallOption) (*Lease, error) {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.Lock(ctx, &proto.LockRequest{Name: name, Owner: owner, TtlMs: ttl.Milliseconds(), Namespace: c.namespace}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	l := &Lease{
+		Fencing: resp.Fencing,
+		client:  c,
+		name:    name,
+		ttl:     ttl,
+		lost:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go l.renew(opts)
+	return l, nil
+}
+
+// renew renews the lease until it is stopped or a renewal fails.
+func (l *Lease) renew(opts []grpc.CallOption) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3)
+		_, err := l.client.rpc.RenewLock(ctx, &proto.RenewLockRequest{
+			Name:      l.name,
+			Token:     l.Fencing.Token,
+			TtlMs:     l.ttl.Milliseconds(),
+			Namespace: l.client.namespace,
+		}, opts...)
+		cancel()
+		if err != nil {
+			l.mu.Lock()
+			l.err = err
+			l.mu.Unlock()
+			close(l.lost)
+			return
+		}
+	}
+}
+
+// Lost is closed when the lease could not be renewed; Err tells why.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Err returns the error that lost the lease, nil while it is held.
+func (l *Lease) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Unlock stops renewing the lease and releases it, so the lock can be
+// acquired again at once. Releasing a lease that was lost fails with
+// FAILED_PRECONDITION, coded LEASE_NOT_HELD.
+func (l *Lease) Unlock(ctx context.Context, opts ...grpc.CallOption) error {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+	ctx, cancel := l.client.withTimeout(ctx)
+	defer cancel()
+	_, err := l.client.rpc.Unlock(ctx, &proto.UnlockRequest{Name: l.name, Token: l.Fencing.Token, Namespace: l.client.namespace}, opts...)
+	return err
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lockClient is a ClavisClient granting leases on one lock. Renewals fail
+// once expired is set.
+type lockClient struct {
+	proto.ClavisClient
+	mu       sync.Mutex
+	renewals int
+	unlocked bool
+	expired  bool
+}
+
+func (c *lockClient) Lock(ctx context.Context, req *proto.LockRequest, opts ...grpc.CallOption) (*proto.LockResponse, error) {
+	return &proto.LockResponse{Fencing: &proto.FencingToken{Lock: req.Name, Token: 7}}, nil
+}
+
+func (c *lockClient) RenewLock(ctx context.Context, req *proto.RenewLockRequest, opts ...grpc.CallOption) (*proto.LockResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expired || req.Token != 7 {
+		return nil, status.Error(codes.FailedPrecondition, "lease is not held")
+	}
+	c.renewals++
+	return &proto.LockResponse{Fencing: &proto.FencingToken{Lock: req.Name, Token: req.Token}}, nil
+}
+
+func (c *lockClient) Unlock(ctx context.Context, req *proto.UnlockRequest, opts ...grpc.CallOption) (*proto.UnlockResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unlocked = true
+	return &proto.UnlockResponse{}, nil
+}
+
+func (c *lockClient) renewed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.renewals
+}
+
+func TestClient_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RenewsUntilUnlocked", func(t *testing.T) {
+		rpc := &lockClient{}
+		c := &Client{rpc: rpc, namespace: "tenant"}
+		lease, err := c.Lock(ctx, "jobs", "worker-1", 30*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lease.Fencing.Lock != "jobs" || lease.Fencing.Token != 7 {
+			t.Errorf("Fencing = %v, want token 7 of jobs", lease.Fencing)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for rpc.renewed() < 2 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if rpc.renewed() < 2 {
+			t.Fatal("Expected the lease to be renewed in the background")
+		}
+		if err := lease.Unlock(ctx); err != nil {
+			t.Fatal(err)
+		}
+		renewals := rpc.renewed()
+		time.Sleep(50 * time.Millisecond)
+		if !rpc.unlocked || rpc.renewed() != renewals {
+			t.Errorf("Expected Unlock to release the lease and stop renewing it")
+		}
+		select {
+		case <-lease.Lost():
+			t.Error("Expected a released lease not to be reported lost")
+		default:
+		}
+	})
+
+	t.Run("ReportsLostLeases", func(t *testing.T) {
+		rpc := &lockClient{expired: true}
+		c := &Client{rpc: rpc}
+		lease, err := c.Lock(ctx, "jobs", "worker-1", 30*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-lease.Lost():
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the failed renewal to lose the lease")
+		}
+		if status.Code(lease.Err()) != codes.FailedPrecondition {
+			t.Errorf("Err() = %v, want the renewal error", lease.Err())
+		}
+	})
+}
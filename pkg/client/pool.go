@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// ErrPoolClosed is returned by calls made through a closed Pool.
+var ErrPoolClosed = errors.New("connection pool closed")
+
+// PoolConfig configures a Pool. Zero values select the corresponding
+// DefaultPoolConfig value.
+type PoolConfig struct {
+	Size           int           // Connections kept open to the server
+	HealthInterval time.Duration // How often each connection is probed with the gRPC health service
+	HealthTimeout  time.Duration // Deadline of each probe
+	FailedProbes   int           // Consecutive failed probes after which a connection is replaced
+}
+
+// DefaultPoolConfig spreads load over a few connections and replaces broken
+// ones within about half a minute.
+var DefaultPoolConfig = PoolConfig{
+	Size:           4,
+	HealthInterval: 10 * time.Second,
+	HealthTimeout:  2 * time.Second,
+	FailedProbes:   3,
+}
+
+// Pool maintains several connections to one server and spreads calls over
+// the healthy ones in turn, so a high-throughput embedder is not limited by
+// the concurrent streams of a single HTTP/2 connection. It implements
+// grpc.ClientConnInterface, so generated clients can be created on it with
+// proto.NewClavisClient.
+//
+// Connections are probed in the background. One that keeps failing probes or
+// reports a transport failure is skipped by calls and, after FailedProbes
+// probes, closed and dialed again.
+type Pool struct {
+	addr   string
+	opts   []grpc.DialOption
+	config PoolConfig
+	slots  []*poolSlot
+	next   atomic.Uint64
+	closed atomic.Bool
+	stop   chan struct{}
+	done   sync.WaitGroup
+}
+
+// poolSlot holds one connection of a pool and its probe results.
+type poolSlot struct {
+	mu      sync.RWMutex
+	conn    *grpc.ClientConn
+	healthy bool
+	failed  int
+}
+
+// NewPool dials config.Size connections to addr with opts and starts probing
+// them.
+func NewPool(addr string, config PoolConfig, opts ...grpc.DialOption) (*Pool, error) {
+	config = config.withDefaults()
+	p := &Pool{addr: addr, opts: opts, config: config, stop: make(chan struct{})}
+	for range config.Size {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			for _, slot := range p.slots {
+				_ = slot.conn.Close()
+			}
+			return nil, err
+		}
+		p.slots = append(p.slots, &poolSlot{conn: conn, healthy: true})
+	}
+	p.done.Add(1)
+	go p.probeLoop()
+	return p, nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (p *Pool) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, err := p.pick()
+	if err != nil {
+		return err
+	}
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (p *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, err := p.pick()
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+// pick returns the next healthy connection in turn, or the next one at all
+// when none is healthy, so calls fail with the transport's own error.
+func (p *Pool) pick() (*grpc.ClientConn, error) {
+	if p.closed.Load() {
+		return nil, ErrPoolClosed
+	}
+	start := p.next.Add(1)
+	n := uint64(len(p.slots))
+	for i := range n {
+		slot := p.slots[(start+i)%n]
+		slot.mu.RLock()
+		conn, healthy := slot.conn, slot.healthy
+		slot.mu.RUnlock()
+		if healthy && conn.GetState() != connectivity.TransientFailure {
+			return conn, nil
+		}
+	}
+	slot := p.slots[start%n]
+	slot.mu.RLock()
+	defer slot.mu.RUnlock()
+	return slot.conn, nil
+}
+
+// Healthy returns how many connections are currently considered healthy.
+func (p *Pool) Healthy() int {
+	healthy := 0
+	for _, slot := range p.slots {
+		slot.mu.RLock()
+		if slot.healthy {
+			healthy++
+		}
+		slot.mu.RUnlock()
+	}
+	return healthy
+}
+
+// Close stops probing and closes every connection. Calls in flight fail.
+func (p *Pool) Close() error {
+	if !p.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(p.stop)
+	p.done.Wait()
+	var errs []error
+	for _, slot := range p.slots {
+		errs = append(errs, slot.conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Pool) probeLoop() {
+	defer p.done.Done()
+	ticker := time.NewTicker(p.config.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			for _, slot := range p.slots {
+				p.probe(slot)
+			}
+		}
+	}
+}
+
+// probe checks one connection and replaces it once it has failed too many
+// probes in a row.
+func (p *Pool) probe(slot *poolSlot) {
+	slot.mu.RLock()
+	conn := slot.conn
+	slot.mu.RUnlock()
+
+	ok := p.check(conn)
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if ok {
+		slot.healthy, slot.failed = true, 0
+		return
+	}
+	slot.healthy = false
+	slot.failed++
+	if slot.failed < p.config.FailedProbes {
+		return
+	}
+
+	// Dial afresh rather than wait out the backoff of the broken connection
+	replacement, err := grpc.NewClient(p.addr, p.opts...)
+	if err != nil {
+		return
+	}
+	replacement.Connect()
+	_ = slot.conn.Close()
+	slot.conn, slot.failed = replacement, 0
+}
+
+// check reports whether conn serves the Clavis service. Servers without the
+// health service are judged by the call reaching them at all.
+func (p *Pool) check(conn *grpc.ClientConn) bool {
+	if conn.GetState() == connectivity.TransientFailure {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.HealthTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: proto.Clavis_ServiceDesc.ServiceName})
+	switch status.Code(err) {
+	case codes.OK:
+		return resp.Status == healthpb.HealthCheckResponse_SERVING
+	case codes.Unimplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c PoolConfig) withDefaults() PoolConfig {
+	if c.Size <= 0 {
+		c.Size = DefaultPoolConfig.Size
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = DefaultPoolConfig.HealthInterval
+	}
+	if c.HealthTimeout <= 0 {
+		c.HealthTimeout = DefaultPoolConfig.HealthTimeout
+	}
+	if c.FailedProbes <= 0 {
+		c.FailedProbes = DefaultPoolConfig.FailedProbes
+	}
+	return c
+}
+
+var _ grpc.ClientConnInterface = (*Pool)(nil)
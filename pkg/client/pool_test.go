@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestPool(t *testing.T) {
+	healthServer := health.NewServer()
+	backend, addr := startMapServer(t, func(s *grpc.Server) { healthpb.RegisterHealthServer(s, healthServer) })
+	pool, err := NewPool(addr, PoolConfig{Size: 3, HealthInterval: 10 * time.Millisecond, FailedProbes: 2}, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := proto.NewClavisClient(pool)
+	ctx := context.Background()
+
+	for range 6 {
+		if _, err := c.Get(ctx, &proto.GetRequest{Key: "k"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.mu.Lock()
+	if len(backend.peers) != 3 {
+		t.Errorf("Expected calls on all 3 connections, got %d", len(backend.peers))
+	}
+	backend.mu.Unlock()
+
+	// Failing connections are skipped, then replaced
+	first := pool.slots[0].conn
+	healthServer.SetServingStatus(proto.Clavis_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	waitFor(t, func() bool { return pool.Healthy() == 0 })
+	waitFor(t, func() bool {
+		pool.slots[0].mu.RLock()
+		defer pool.slots[0].mu.RUnlock()
+		return pool.slots[0].conn != first
+	})
+	healthServer.SetServingStatus(proto.Clavis_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	waitFor(t, func() bool { return pool.Healthy() == 3 })
+	if _, err := c.Get(ctx, &proto.GetRequest{Key: "k"}); err != nil {
+		t.Errorf("Expected calls to work on replaced connections, got %v", err)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, &proto.GetRequest{Key: "k"}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
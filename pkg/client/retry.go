@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryConfig controls the retries of idempotent calls. Zero values select
+// the corresponding DefaultRetryConfig value.
+type RetryConfig struct {
+	MaxAttempts       int           // Attempts per call, counting the first; 1 disables retries
+	InitialBackoff    time.Duration // Wait after the first failure, doubled after each further one
+	MaxBackoff        time.Duration // Upper bound on the wait between attempts
+	PerAttemptTimeout time.Duration // Deadline of each attempt, so a hung attempt can be retried; none if zero
+	Codes             []codes.Code  // Status codes that are retried
+	Methods           []string      // Full method names that are retried
+}
+
+// DefaultRetryConfig retries transient failures of reads and deletes a few
+// times within about a second.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:    4,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Codes:          []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+	Methods:        IdempotentMethods,
+}
+
+// IdempotentMethods are the calls that can be repeated without changing
+// their outcome. Puts are not among them: a retried conditional Put could
+// fail against its own first attempt.
+var IdempotentMethods = []string{
+	proto.Clavis_Get_FullMethodName,
+	proto.Clavis_Scan_FullMethodName,
+	proto.Clavis_Delete_FullMethodName,
+	proto.Clavis_BatchGet_FullMethodName,
+	proto.Clavis_BatchDelete_FullMethodName,
+	proto.Clavis_Diff_FullMethodName,
+	proto.Clavis_FetchSpill_FullMethodName,
+	proto.Clavis_Range_FullMethodName,
+	proto.Clavis_Exists_FullMethodName,
+	proto.Clavis_Count_FullMethodName,
+	proto.Clavis_GetMetadata_FullMethodName,
+	proto.Clavis_QueryByTag_FullMethodName,
+	proto.Clavis_Search_FullMethodName,
+}
+
+// RetryError is returned when a call failed after more than one attempt. It
+// carries the status of the last attempt, so status.Code and status.Convert
+// work on it as on the error it wraps.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the status of the last attempt.
+func (e *RetryError) GRPCStatus() *status.Status {
+	return status.Convert(e.Err)
+}
+
+// WithRetries returns a dial option retrying the calls of config.Methods that
+// fail with one of config.Codes, waiting with jittered exponential backoff
+// between attempts. Streams are not retried.
+func WithRetries(config RetryConfig) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(RetryUnaryInterceptor(config))
+}
+
+// RetryUnaryInterceptor retries unary calls as described by WithRetries.
+func RetryUnaryInterceptor(config RetryConfig) grpc.UnaryClientInterceptor {
+	config = config.withDefaults()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !slices.Contains(config.Methods, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		backoff := config.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := config.invoke(ctx, method, req, reply, cc, invoker, opts...)
+			if err == nil {
+				return nil
+			}
+			if !slices.Contains(config.Codes, status.Code(err)) || ctx.Err() != nil {
+				return retryError(attempt, err)
+			}
+			if attempt >= config.MaxAttempts {
+				return retryError(attempt, err)
+			}
+
+			// Sleep for between half and all of the backoff so clients reconnecting at once spread out
+			wait := backoff/2 + rand.N(backoff/2+1) // #nosec G404 -- jitter does not need a secure source
+			select {
+			case <-ctx.Done():
+				return retryError(attempt, err)
+			case <-time.After(wait):
+			}
+			backoff = min(2*backoff, config.MaxBackoff)
+		}
+	}
+}
+
+// invoke makes one attempt, bounded by the per-attempt timeout.
+func (c RetryConfig) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if c.PerAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.PerAttemptTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// retryError wraps err with the attempt count when the call was retried.
+func retryError(attempts int, err error) error {
+	if attempts <= 1 {
+		return err
+	}
+	return &RetryError{Attempts: attempts, Err: err}
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultRetryConfig.MaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultRetryConfig.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultRetryConfig.MaxBackoff
+	}
+	if c.Codes == nil {
+		c.Codes = DefaultRetryConfig.Codes
+	}
+	if c.Methods == nil {
+		c.Methods = DefaultRetryConfig.Methods
+	}
+	return c
+}
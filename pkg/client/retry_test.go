@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingInvoker fails the first failures calls with code and counts calls.
+func failingInvoker(failures int, code codes.Code, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "try again")
+		}
+		return nil
+	}
+}
+
+func TestRetryUnaryInterceptor(t *testing.T) {
+	interceptor := RetryUnaryInterceptor(RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	ctx := context.Background()
+
+	calls := 0
+	err := interceptor(ctx, proto.Clavis_Get_FullMethodName, nil, nil, nil, failingInvoker(2, codes.Unavailable, &calls))
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = interceptor(ctx, proto.Clavis_Scan_FullMethodName, nil, nil, nil, failingInvoker(5, codes.DeadlineExceeded, &calls))
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 || calls != 3 {
+		t.Fatalf("Expected a RetryError after 3 attempts, got %v after %d calls", err, calls)
+	}
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected the status of the last attempt, got %v", status.Code(err))
+	}
+
+	calls = 0
+	err = interceptor(ctx, proto.Clavis_Put_FullMethodName, nil, nil, nil, failingInvoker(1, codes.Unavailable, &calls))
+	if status.Code(err) != codes.Unavailable || calls != 1 {
+		t.Errorf("Expected Put not to be retried, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = interceptor(ctx, proto.Clavis_Get_FullMethodName, nil, nil, nil, failingInvoker(1, codes.InvalidArgument, &calls))
+	if errors.As(err, &retryErr) || calls != 1 {
+		t.Errorf("Expected InvalidArgument not to be retried, got %v after %d calls", err, calls)
+	}
+}
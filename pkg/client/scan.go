@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// ScanAll calls visit with every pair whose key starts with prefix, in key
+// order, using only unary calls. It asks the server to spill results too
+// large for one response and then downloads the spill in chunks, so it suits
+// exports through proxies or clients that cannot use ScanStream. An error
+// from visit stops the scan and is returned.
+func ScanAll(ctx context.Context, c proto.ClavisClient, prefix string, visit func(*proto.KeyValue) error, opts ...grpc.CallOption) error {
+	_, err := scanAll(ctx, c, &proto.ScanRequest{Prefix: prefix, AllowSpill: true}, visit, opts...)
+	return err
+}
+
+// ExportAt is ScanAll reading every pair as it was at the given time, so the
+// export is consistent however many writes happen meanwhile. It returns the
+// store version the time resolved to; exports at that version are
+// reproducible for as long as the server retains its history.
+func ExportAt(ctx context.Context, c proto.ClavisClient, prefix string, at time.Time, visit func(*proto.KeyValue) error, opts ...grpc.CallOption) (version uint64, err error) {
+	return scanAll(ctx, c, &proto.ScanRequest{Prefix: prefix, AllowSpill: true, AsOfUnixNano: at.UnixNano()}, visit, opts...)
+}
+
+// scanAll runs a spilling scan and visits its items, inline or spilled.
+func scanAll(ctx context.Context, c proto.ClavisClient, req *proto.ScanRequest, visit func(*proto.KeyValue) error, opts ...grpc.CallOption) (uint64, error) {
+	resp, err := c.Scan(ctx, req, opts...)
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range resp.Items {
+		if err := visit(item); err != nil {
+			return 0, err
+		}
+	}
+	if resp.Spill == nil {
+		return resp.AsOfVersion, nil
+	}
+
+	fetch := &proto.FetchSpillRequest{Id: resp.Spill.Id, Prefix: req.Prefix}
+	for {
+		chunk, err := c.FetchSpill(ctx, fetch, opts...)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range chunk.Items {
+			if err := visit(item); err != nil {
+				return 0, err
+			}
+		}
+		if chunk.Done {
+			return resp.AsOfVersion, nil
+		}
+		fetch.Offset = chunk.NextOffset
+	}
+}
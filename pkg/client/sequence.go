@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// DefaultSequenceBandwidth is how many numbers a Sequence reserves at once
+// unless told otherwise.
+const DefaultSequenceBandwidth = 100
+
+// NextSequence reserves the next bandwidth numbers, at least one, of the
+// sequence called name, and returns the first of them. The numbers run from
+// first to first+bandwidth-1 and are handed to no one else.
+func (c *Client) NextSequence(ctx context.Context, name string, bandwidth uint64, opts ...grpc.CallOption) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	resp, err := c.rpc.NextSequence(ctx, &proto.NextSequenceRequest{Name: name, Bandwidth: bandwidth, Namespace: c.namespace}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return resp.First, nil
+}
+
+// Sequence hands out the numbers of a named sequence, reserving them from
+// the server bandwidth at a time so that most calls to Next need no round
+// trip. Numbers reserved but not handed out by the time the Sequence is
+// dropped are skipped, so IDs increase but are not contiguous. It is safe
+// for concurrent use.
+type Sequence struct {
+	client    *Client
+	name      string
+	bandwidth uint64
+
+	mu   sync.Mutex
+	next uint64 // Next number to hand out
+	end  uint64 // Number after the last one reserved
+}
+
+// Sequence returns a Sequence handing out the numbers of the sequence called
+// name, reserving bandwidth of them at a time, DefaultSequenceBandwidth if
+// zero.
+func (c *Client) Sequence(name string, bandwidth uint64) *Sequence {
+	if bandwidth == 0 {
+		bandwidth = DefaultSequenceBandwidth
+	}
+	return &Sequence{client: c, name: name, bandwidth: bandwidth}
+}
+
+// Next returns the next number of the sequence, reserving another range from
+// the server once the current one is used up.
+func (s *Sequence) Next(ctx context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == s.end {
+		first, err := s.client.NextSequence(ctx, s.name, s.bandwidth)
+		if err != nil {
+			return 0, err
+		}
+		s.next, s.end = first, first+s.bandwidth
+	}
+	n := s.next
+	s.next++
+	return n, nil
+}
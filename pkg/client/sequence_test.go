@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// sequenceClient is a ClavisClient reserving numbers of one sequence.
+type sequenceClient struct {
+	proto.ClavisClient
+	next  uint64
+	calls int
+}
+
+func (c *sequenceClient) NextSequence(ctx context.Context, req *proto.NextSequenceRequest, opts ...grpc.CallOption) (*proto.NextSequenceResponse, error) {
+	c.calls++
+	first := c.next
+	c.next += req.Bandwidth
+	return &proto.NextSequenceResponse{First: first, Count: req.Bandwidth}, nil
+}
+
+func TestSequence_Next(t *testing.T) {
+	rpc := &sequenceClient{next: 1}
+	c := &Client{rpc: rpc}
+	seq := c.Sequence("orders", 3)
+	for want := uint64(1); want <= 7; want++ {
+		n, err := seq.Next(context.Background())
+		if err != nil || n != want {
+			t.Fatalf("Next() = %d, %v; want %d", n, err, want)
+		}
+	}
+	if rpc.calls != 3 {
+		t.Errorf("Expected 3 ranges reserved for 7 numbers, got %d", rpc.calls)
+	}
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+// DefaultVirtualNodes is the number of points each shard has on the hash
+// ring, enough to spread keys within a few percent of evenly.
+const DefaultVirtualNodes = 128
+
+var (
+	// ErrNoShards is returned by calls made through a ShardedClient without
+	// shards.
+	ErrNoShards = errors.New("no shards configured")
+	// ErrUnknownShard is returned when removing a shard that was not added.
+	ErrUnknownShard = errors.New("unknown shard")
+)
+
+// ShardedConfig configures a ShardedClient.
+type ShardedConfig struct {
+	// Config is applied to the client of every shard.
+	Config
+	// VirtualNodes is the number of points each shard has on the hash ring;
+	// DefaultVirtualNodes if zero. Every client of the same shards must use
+	// the same value to agree on where keys live.
+	VirtualNodes int
+}
+
+// ShardedClient spreads keys over several independent Clavis servers by
+// consistent hashing: each key lives on the shard owning the first point of
+// the hash ring at or after the key's hash. Adding or removing a shard only
+// moves the keys between it and its neighbors on the ring, about one shard's
+// share, and it is up to the caller to copy them over.
+//
+// Key operations go to the shard of the key; scans go to every shard and
+// their results are merged. It is safe for concurrent use.
+type ShardedClient struct {
+	config ShardedConfig
+
+	mu     sync.RWMutex
+	shards map[string]*Client
+	ring   []ringPoint // Sorted by hash
+}
+
+// ringPoint is one of the points a shard has on the hash ring.
+type ringPoint struct {
+	hash uint64
+	addr string
+}
+
+// NewSharded creates a client of the servers at addrs.
+func NewSharded(addrs []string, config ShardedConfig) (*ShardedClient, error) {
+	if config.VirtualNodes <= 0 {
+		config.VirtualNodes = DefaultVirtualNodes
+	}
+	c := &ShardedClient{config: config, shards: make(map[string]*Client)}
+	for _, addr := range addrs {
+		if err := c.AddShard(addr); err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// AddShard adds the server at addr. Keys hashed to it from now on are no
+// longer read from the shards that held them before. Adding a shard twice
+// has no effect.
+func (c *ShardedClient) AddShard(addr string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.shards[addr]; ok {
+		return nil
+	}
+	shard, err := New(addr, c.config.Config)
+	if err != nil {
+		return fmt.Errorf("shard %s: %w", addr, err)
+	}
+	c.shards[addr] = shard
+	for i := range c.config.VirtualNodes {
+		c.ring = append(c.ring, ringPoint{hash: hashKey(addr + "#" + strconv.Itoa(i)), addr: addr})
+	}
+	sort.Slice(c.ring, func(i, j int) bool { return c.ring[i].hash < c.ring[j].hash })
+	return nil
+}
+
+// RemoveShard removes the server at addr and closes its client. Its keys are
+// hashed to the remaining shards from now on.
+func (c *ShardedClient) RemoveShard(addr string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	shard, ok := c.shards[addr]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownShard, addr)
+	}
+	delete(c.shards, addr)
+	c.ring = slices.DeleteFunc(c.ring, func(p ringPoint) bool { return p.addr == addr })
+	return shard.Close()
+}
+
+// Shards returns the addresses of the shards, sorted.
+func (c *ShardedClient) Shards() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	addrs := make([]string, 0, len(c.shards))
+	for addr := range c.shards {
+		addrs = append(addrs, addr)
+	}
+	slices.Sort(addrs)
+	return addrs
+}
+
+// ShardFor returns the address of the shard key lives on, empty if there are
+// no shards.
+func (c *ShardedClient) ShardFor(key string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.owner(key)
+}
+
+// owner returns the address of the shard key lives on. c.mu must be held.
+func (c *ShardedClient) owner(key string) string {
+	if len(c.ring) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	i := sort.Search(len(c.ring), func(i int) bool { return c.ring[i].hash >= h })
+	if i == len(c.ring) {
+		i = 0
+	}
+	return c.ring[i].addr
+}
+
+// shard returns the client of the shard key lives on.
+func (c *ShardedClient) shard(key string) (*Client, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	shard, ok := c.shards[c.owner(key)]
+	if !ok {
+		return nil, ErrNoShards
+	}
+	return shard, nil
+}
+
+// Close closes the clients of every shard.
+func (c *ShardedClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for addr, shard := range c.shards {
+		if err := shard.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Get retrieves the value of key from its shard.
+func (c *ShardedClient) Get(ctx context.Context, key string, opts ...grpc.CallOption) ([]byte, bool, error) {
+	shard, err := c.shard(key)
+	if err != nil {
+		return nil, false, err
+	}
+	return shard.Get(ctx, key, opts...)
+}
+
+// Put stores value at key on its shard.
+func (c *ShardedClient) Put(ctx context.Context, key string, value []byte, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	shard, err := c.shard(key)
+	if err != nil {
+		return nil, err
+	}
+	return shard.Put(ctx, key, value, opts...)
+}
+
+// Delete removes key from its shard.
+func (c *ShardedClient) Delete(ctx context.Context, key string, opts ...grpc.CallOption) ([]*proto.Warning, error) {
+	shard, err := c.shard(key)
+	if err != nil {
+		return nil, err
+	}
+	return shard.Delete(ctx, key, opts...)
+}
+
+// Scan returns up to limit pairs whose keys start with prefix from all
+// shards, the first ones in key order when limited; a zero limit returns
+// every pair. The shards are scanned concurrently, and the scan fails if any
+// of them does.
+func (c *ShardedClient) Scan(ctx context.Context, prefix string, limit int, opts ...grpc.CallOption) (map[string][]byte, error) {
+	c.mu.RLock()
+	shards := make(map[string]*Client, len(c.shards))
+	for addr, shard := range c.shards {
+		shards[addr] = shard
+	}
+	c.mu.RUnlock()
+	if len(shards) == 0 {
+		return nil, ErrNoShards
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	pairs := make(map[string][]byte)
+	for addr, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each shard's first limit keys include its share of the overall first limit
+			found, err := shard.Scan(ctx, prefix, limit, opts...)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("shard %s: %w", addr, err)
+					cancel()
+				}
+				return
+			}
+			for key, value := range found {
+				pairs[key] = value
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if limit > 0 && len(pairs) > limit {
+		keys := make([]string, 0, len(pairs))
+		for key := range pairs {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys[limit:] {
+			delete(pairs, key)
+		}
+	}
+	return pairs, nil
+}
+
+// hashKey places a key or ring point on the hash ring. FNV-1a is stable
+// across processes, as every client must agree on the ring; the final mix
+// spreads keys that differ only in their last bytes.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestShardedClient(t *testing.T) {
+	backends := make(map[string]*mapServer)
+	var addrs []string
+	for range 3 {
+		backend, addr := startMapServer(t)
+		backends[addr] = backend
+		addrs = append(addrs, addr)
+	}
+	c, err := NewSharded(addrs, ShardedConfig{Config: Config{Timeout: time.Second}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+
+	const keys = 3000
+	for i := range keys {
+		key := fmt.Sprintf("user/%d", i)
+		if _, err := c.Put(ctx, key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("SpreadsKeys", func(t *testing.T) {
+		for addr, backend := range backends {
+			if n := len(backend.data); n < keys/3*3/4 || n > keys/3*5/4 {
+				t.Errorf("Shard %s holds %d of %d keys, want about a third", addr, n, keys)
+			}
+			for key := range backend.data {
+				if owner := c.ShardFor(key); owner != addr {
+					t.Fatalf("Key %s is on %s, but ShardFor says %s", key, addr, owner)
+				}
+			}
+		}
+		value, found, err := c.Get(ctx, "user/42")
+		if err != nil || !found || string(value) != "user/42" {
+			t.Errorf("Get = %q, %v, %v; want the stored value", value, found, err)
+		}
+	})
+
+	t.Run("ScanMergesShards", func(t *testing.T) {
+		pairs, err := c.Scan(ctx, "user/1", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// user/1 and user/10 to user/1999
+		if len(pairs) != 1111 {
+			t.Errorf("Scan returned %d pairs, want 1111", len(pairs))
+		}
+		pairs, err = c.Scan(ctx, "user/", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"user/0", "user/1", "user/10"} {
+			if _, ok := pairs[key]; !ok || len(pairs) != 3 {
+				t.Errorf("Limited scan = %d pairs without %s, want the first 3 keys", len(pairs), key)
+			}
+		}
+	})
+
+	t.Run("AddingShardMovesOnlyItsShare", func(t *testing.T) {
+		before := make(map[string]string, keys)
+		for i := range keys {
+			key := fmt.Sprintf("user/%d", i)
+			before[key] = c.ShardFor(key)
+		}
+		_, added := startMapServer(t)
+		if err := c.AddShard(added); err != nil {
+			t.Fatal(err)
+		}
+		moved := 0
+		for key, owner := range before {
+			if now := c.ShardFor(key); now != owner {
+				if now != added {
+					t.Fatalf("Key %s moved from %s to %s, not to the added shard", key, owner, now)
+				}
+				moved++
+			}
+		}
+		if moved < keys/4*3/4 || moved > keys/4*5/4 {
+			t.Errorf("Adding a fourth shard moved %d of %d keys, want about a quarter", moved, keys)
+		}
+
+		if err := c.RemoveShard(added); err != nil {
+			t.Fatal(err)
+		}
+		for key, owner := range before {
+			if now := c.ShardFor(key); now != owner {
+				t.Fatalf("Key %s is on %s after removing the added shard, want %s", key, now, owner)
+			}
+		}
+		if err := c.RemoveShard(added); !errors.Is(err, ErrUnknownShard) {
+			t.Errorf("RemoveShard twice = %v, want ErrUnknownShard", err)
+		}
+	})
+}
+
+func TestShardedClient_NoShards(t *testing.T) {
+	c, err := NewSharded(nil, ShardedConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Get(context.Background(), "k"); !errors.Is(err, ErrNoShards) {
+		t.Errorf("Get = %v, want ErrNoShards", err)
+	}
+	if _, err := c.Scan(context.Background(), "", 0); !errors.Is(err, ErrNoShards) {
+		t.Errorf("Scan = %v, want ErrNoShards", err)
+	}
+}
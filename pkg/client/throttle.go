@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/William-Fernandes252/clavis/internal/ratelimit"
+	"google.golang.org/grpc"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// ErrThrottled is returned by fail-fast calls that would exceed a throttle.
+var ErrThrottled = errors.New("request throttled by client")
+
+// ThrottleMode selects what a call does when a throttle is exhausted.
+type ThrottleMode int
+
+const (
+	// Block waits until the call fits within the limits or its context ends.
+	Block ThrottleMode = iota
+	// FailFast returns ErrThrottled immediately.
+	FailFast
+)
+
+// ThrottleConfig limits the load a client puts on the server. Zero values
+// disable the corresponding limit.
+type ThrottleConfig struct {
+	MaxConcurrent     int          // Requests in flight at once, counting open streams
+	RequestsPerSecond float64      // Calls started per second
+	BytesPerSecond    float64      // Request payload bytes sent per second
+	Mode              ThrottleMode // Default behavior, overridable per call with WithThrottleMode
+}
+
+// Throttle enforces a ThrottleConfig on every call made through a connection.
+type Throttle struct {
+	mode     ThrottleMode
+	slots    chan struct{}
+	requests *ratelimit.Limiter
+	bytes    *ratelimit.Limiter
+}
+
+// NewThrottle creates a throttle for the given limits.
+func NewThrottle(config ThrottleConfig) *Throttle {
+	t := &Throttle{mode: config.Mode}
+	if config.MaxConcurrent > 0 {
+		t.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	if config.RequestsPerSecond > 0 {
+		t.requests = ratelimit.New(config.RequestsPerSecond, 0)
+	}
+	if config.BytesPerSecond > 0 {
+		t.bytes = ratelimit.New(config.BytesPerSecond, 0)
+	}
+	return t
+}
+
+// DialOptions returns the options that install the throttle on a connection.
+func (t *Throttle) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(t.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(t.StreamClientInterceptor()),
+	}
+}
+
+// throttleModeOption overrides the throttle mode for a single call.
+type throttleModeOption struct {
+	grpc.EmptyCallOption
+	mode ThrottleMode
+}
+
+// WithThrottleMode selects blocking or fail-fast throttling for one call.
+func WithThrottleMode(mode ThrottleMode) grpc.CallOption {
+	return throttleModeOption{mode: mode}
+}
+
+// modeFor returns the throttle mode requested by opts, or the default.
+func (t *Throttle) modeFor(opts []grpc.CallOption) ThrottleMode {
+	mode := t.mode
+	for _, opt := range opts {
+		if o, ok := opt.(throttleModeOption); ok {
+			mode = o.mode
+		}
+	}
+	return mode
+}
+
+// acquire admits one call of the given size, returning a function that
+// releases its concurrency slot.
+func (t *Throttle) acquire(ctx context.Context, mode ThrottleMode, size int) (release func(), err error) {
+	if err := t.take(ctx, mode, t.requests, 1, "request rate"); err != nil {
+		return nil, err
+	}
+	if err := t.take(ctx, mode, t.bytes, float64(size), "byte rate"); err != nil {
+		return nil, err
+	}
+
+	if t.slots == nil {
+		return func() {}, nil
+	}
+	if mode == FailFast {
+		select {
+		case t.slots <- struct{}{}:
+		default:
+			return nil, fmt.Errorf("%w: concurrency limit reached", ErrThrottled)
+		}
+	} else {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-t.slots }) }, nil
+}
+
+// take draws n tokens from limiter, if there is one.
+func (t *Throttle) take(ctx context.Context, mode ThrottleMode, limiter *ratelimit.Limiter, n float64, name string) error {
+	if limiter == nil || n <= 0 {
+		return nil
+	}
+	if mode == FailFast {
+		if !limiter.Allow(n) {
+			return fmt.Errorf("%w: %s limit reached", ErrThrottled, name)
+		}
+		return nil
+	}
+	return limiter.Wait(ctx, n)
+}
+
+// UnaryClientInterceptor throttles unary calls.
+func (t *Throttle) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		release, err := t.acquire(ctx, t.modeFor(opts), messageSize(req))
+		if err != nil {
+			return err
+		}
+		defer release()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor throttles streams. A stream holds its concurrency
+// slot until it finishes, and every message it sends counts toward the byte
+// rate.
+func (t *Throttle) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		mode := t.modeFor(opts)
+		release, err := t.acquire(ctx, mode, 0)
+		if err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		// The stream context ends when the stream does, however it finishes
+		go func() {
+			<-stream.Context().Done()
+			release()
+		}()
+		return &throttledStream{ClientStream: stream, throttle: t, mode: mode}, nil
+	}
+}
+
+// throttledStream applies the byte rate to outgoing stream messages.
+type throttledStream struct {
+	grpc.ClientStream
+	throttle *Throttle
+	mode     ThrottleMode
+}
+
+func (s *throttledStream) SendMsg(m any) error {
+	if err := s.throttle.take(s.Context(), s.mode, s.throttle.bytes, float64(messageSize(m)), "byte rate"); err != nil {
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+// messageSize returns the encoded size of a protobuf message, or 0 otherwise.
+func messageSize(m any) int {
+	if msg, ok := m.(protobuf.Message); ok {
+		return protobuf.Size(msg)
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+)
+
+func noopInvoker(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	return nil
+}
+
+func TestThrottle_Concurrency(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{MaxConcurrent: 1})
+	interceptor := throttle.UnaryClientInterceptor()
+	req := &proto.GetRequest{Key: "k"}
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- interceptor(context.Background(), "/m", req, nil, nil,
+			func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				close(started)
+				<-finish
+				return nil
+			})
+	}()
+	<-started
+
+	err := interceptor(context.Background(), "/m", req, nil, nil, noopInvoker, WithThrottleMode(FailFast))
+	if !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected ErrThrottled for a fail-fast call, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := interceptor(ctx, "/m", req, nil, nil, noopInvoker); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a blocking call to wait until its deadline, got %v", err)
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := interceptor(context.Background(), "/m", req, nil, nil, noopInvoker, WithThrottleMode(FailFast)); err != nil {
+		t.Errorf("Expected the slot to be released, got %v", err)
+	}
+}
+
+func TestThrottle_Rates(t *testing.T) {
+	t.Run("Requests", func(t *testing.T) {
+		interceptor := NewThrottle(ThrottleConfig{RequestsPerSecond: 1, Mode: FailFast}).UnaryClientInterceptor()
+		req := &proto.GetRequest{Key: "k"}
+
+		if err := interceptor(context.Background(), "/m", req, nil, nil, noopInvoker); err != nil {
+			t.Fatalf("First call failed: %v", err)
+		}
+		if err := interceptor(context.Background(), "/m", req, nil, nil, noopInvoker); !errors.Is(err, ErrThrottled) {
+			t.Errorf("Expected ErrThrottled, got %v", err)
+		}
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		interceptor := NewThrottle(ThrottleConfig{BytesPerSecond: 100, Mode: FailFast}).UnaryClientInterceptor()
+		small := &proto.PutRequest{Key: "k", Value: make([]byte, 10)}
+		large := &proto.PutRequest{Key: "k", Value: make([]byte, 500)}
+
+		if err := interceptor(context.Background(), "/m", large, nil, nil, noopInvoker); err != nil {
+			t.Fatalf("Expected an oversized call to pass on a full budget, got %v", err)
+		}
+		if err := interceptor(context.Background(), "/m", small, nil, nil, noopInvoker); !errors.Is(err, ErrThrottled) {
+			t.Errorf("Expected ErrThrottled after exceeding the byte budget, got %v", err)
+		}
+	})
+}
+
+// fakeClientStream is a stream whose lifetime is controlled by its context
+type fakeClientStream struct {
+	grpc.ClientStream
+	ctx  context.Context
+	sent int
+}
+
+func (s *fakeClientStream) Context() context.Context { return s.ctx }
+
+func (s *fakeClientStream) SendMsg(m any) error {
+	s.sent++
+	return nil
+}
+
+func TestThrottle_Stream(t *testing.T) {
+	throttle := NewThrottle(ThrottleConfig{MaxConcurrent: 1, Mode: FailFast})
+	interceptor := throttle.StreamClientInterceptor()
+
+	streamCtx, endStream := context.WithCancel(context.Background())
+	fake := &fakeClientStream{ctx: streamCtx}
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return fake, nil
+	}
+
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/m", streamer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(&proto.ScanRequest{}); err != nil || fake.sent != 1 {
+		t.Errorf("Expected message to be sent, err=%v sent=%d", err, fake.sent)
+	}
+	if _, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/m", streamer); !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected an open stream to hold its slot, got %v", err)
+	}
+
+	endStream()
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/m", streamer)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Slot was not released after the stream ended: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTooManyConflicts is returned by UpdateJSON when every attempt lost the
+// race against another writer.
+var ErrTooManyConflicts = errors.New("too many conflicting updates")
+
+// UpdateConfig controls the retry loop of UpdateJSON. Zero values select the
+// corresponding DefaultUpdateConfig value.
+type UpdateConfig struct {
+	MaxAttempts    int           // Attempts before giving up with ErrTooManyConflicts
+	InitialBackoff time.Duration // Wait after the first conflict, doubled after each further one
+	MaxBackoff     time.Duration // Upper bound on the wait between attempts
+}
+
+// DefaultUpdateConfig suits a handful of writers contending for a key.
+var DefaultUpdateConfig = UpdateConfig{
+	MaxAttempts:    10,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// UpdateJSON applies update to the JSON value stored at key with optimistic
+// concurrency control, using DefaultUpdateConfig. See UpdateJSONWithConfig.
+func UpdateJSON[T any](ctx context.Context, c proto.ClavisClient, key string, update func(old T) (T, error), opts ...grpc.CallOption) (T, error) {
+	return UpdateJSONWithConfig(ctx, c, key, DefaultUpdateConfig, update, opts...)
+}
+
+// UpdateJSONWithConfig reads the value at key with its version, decodes it
+// into a T (the zero T when the key does not exist), and writes back the
+// result of update only if no one else wrote the key in between. When someone
+// did, it waits with jittered exponential backoff and starts over with the new
+// value, so update may run several times and must not have side effects.
+// Errors from update and from the server other than conflicts end the loop
+// immediately. Returns the value that was written.
+func UpdateJSONWithConfig[T any](ctx context.Context, c proto.ClavisClient, key string, config UpdateConfig, update func(old T) (T, error), opts ...grpc.CallOption) (T, error) {
+	config = config.withDefaults()
+	backoff := config.InitialBackoff
+
+	var zero T
+	for attempt := 1; ; attempt++ {
+		current, err := c.Get(ctx, &proto.GetRequest{Key: key, WithVersion: true}, opts...)
+		if err != nil {
+			return zero, err
+		}
+		var old T
+		if current.Found {
+			if err := json.Unmarshal(current.Value, &old); err != nil {
+				return zero, fmt.Errorf("failed to decode %q: %w", key, err)
+			}
+		}
+
+		next, err := update(old)
+		if err != nil {
+			return zero, err
+		}
+		value, err := json.Marshal(next)
+		if err != nil {
+			return zero, fmt.Errorf("failed to encode %q: %w", key, err)
+		}
+
+		version := current.Version
+		_, err = c.Put(ctx, &proto.PutRequest{Key: key, Value: value, ExpectedVersion: &version}, opts...)
+		if err == nil {
+			return next, nil
+		}
+		if status.Code(err) != codes.Aborted {
+			return zero, err
+		}
+		if attempt >= config.MaxAttempts {
+			return zero, fmt.Errorf("%w: %q after %d attempts", ErrTooManyConflicts, key, attempt)
+		}
+
+		// Sleep for between half and all of the backoff so contending writers spread out
+		wait := backoff/2 + rand.N(backoff/2+1) // #nosec G404 -- jitter does not need a secure source
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(2*backoff, config.MaxBackoff)
+	}
+}
+
+func (c UpdateConfig) withDefaults() UpdateConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultUpdateConfig.MaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultUpdateConfig.InitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultUpdateConfig.MaxBackoff
+	}
+	return c
+}
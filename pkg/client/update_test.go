@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// versionedClient is a ClavisClient holding one key, whose version grows
+// with every write. Conflicts makes that many writes conflict first.
+type versionedClient struct {
+	proto.ClavisClient
+	value     []byte
+	version   uint64
+	conflicts int
+	puts      int
+}
+
+func (c *versionedClient) Get(ctx context.Context, req *proto.GetRequest, opts ...grpc.CallOption) (*proto.GetResponse, error) {
+	return &proto.GetResponse{Value: c.value, Found: c.version > 0, Version: c.version}, nil
+}
+
+func (c *versionedClient) Put(ctx context.Context, req *proto.PutRequest, opts ...grpc.CallOption) (*proto.PutResponse, error) {
+	c.puts++
+	if c.conflicts > 0 {
+		// Someone else wrote first
+		c.conflicts--
+		c.version++
+	}
+	if req.ExpectedVersion == nil || *req.ExpectedVersion != c.version {
+		return nil, status.Error(codes.Aborted, "version conflict")
+	}
+	c.value = req.Value
+	c.version++
+	return &proto.PutResponse{}, nil
+}
+
+type counter struct {
+	Count int `json:"count"`
+}
+
+func increment(old counter) (counter, error) {
+	old.Count++
+	return old, nil
+}
+
+func TestUpdateJSON(t *testing.T) {
+	fast := UpdateConfig{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxAttempts: 3}
+
+	t.Run("MissingKey", func(t *testing.T) {
+		c := &versionedClient{}
+		got, err := UpdateJSON(context.Background(), c, "counter", increment)
+		if err != nil || got.Count != 1 {
+			t.Fatalf("UpdateJSON = %+v, %v", got, err)
+		}
+		var stored counter
+		if err := json.Unmarshal(c.value, &stored); err != nil || stored.Count != 1 {
+			t.Errorf("Expected {count: 1} to be stored, got %s", c.value)
+		}
+	})
+
+	t.Run("RetriesConflicts", func(t *testing.T) {
+		c := &versionedClient{value: []byte(`{"count": 5}`), version: 7, conflicts: 2}
+		calls := 0
+		got, err := UpdateJSONWithConfig(context.Background(), c, "counter", fast, func(old counter) (counter, error) {
+			calls++
+			return increment(old)
+		})
+		if err != nil || got.Count != 6 {
+			t.Fatalf("UpdateJSON = %+v, %v", got, err)
+		}
+		if calls != 3 || c.puts != 3 {
+			t.Errorf("Expected 3 attempts, got %d updates and %d puts", calls, c.puts)
+		}
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		c := &versionedClient{value: []byte(`{"count": 1}`), version: 1, conflicts: 10}
+		_, err := UpdateJSONWithConfig(context.Background(), c, "counter", fast, increment)
+		if !errors.Is(err, ErrTooManyConflicts) || c.puts != 3 {
+			t.Errorf("Expected ErrTooManyConflicts after 3 puts, got %v after %d", err, c.puts)
+		}
+	})
+
+	t.Run("UpdateError", func(t *testing.T) {
+		c := &versionedClient{}
+		refused := errors.New("refused")
+		_, err := UpdateJSON(context.Background(), c, "counter", func(counter) (counter, error) { return counter{}, refused })
+		if !errors.Is(err, refused) || c.puts != 0 {
+			t.Errorf("Expected the update error without writing, got %v", err)
+		}
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		c := &versionedClient{value: []byte("not json"), version: 1}
+		if _, err := UpdateJSON(context.Background(), c, "counter", increment); err == nil || c.puts != 0 {
+			t.Errorf("Expected a decoding error without writing, got %v", err)
+		}
+	})
+}
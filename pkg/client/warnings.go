@@ -0,0 +1,36 @@
+package client
+
+import "github.com/William-Fernandes252/clavis/api/proto"
+
+// Warning codes the server reports on successful writes.
+const (
+	WarningNearQuota    = "near_quota"
+	WarningDeprecated   = "deprecated"
+	WarningNormalized   = "normalized"
+	WarningDuplicateKey = "duplicate_key"
+)
+
+// WarningsCarrier is implemented by the responses of writes, such as
+// PutResponse, DeleteResponse, BatchPutResponse and BatchDeleteResponse.
+type WarningsCarrier interface {
+	GetWarnings() []*proto.Warning
+}
+
+// Warnings returns the non-fatal conditions the server reported for a write.
+// It returns nil for a nil response.
+func Warnings(resp WarningsCarrier) []*proto.Warning {
+	if resp == nil {
+		return nil
+	}
+	return resp.GetWarnings()
+}
+
+// HasWarning reports whether resp carries a warning with the given code.
+func HasWarning(resp WarningsCarrier, code string) bool {
+	for _, w := range Warnings(resp) {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/William-Fernandes252/clavis/api/proto"
+)
+
+func TestWarnings(t *testing.T) {
+	resp := &proto.BatchPutResponse{Warnings: []*proto.Warning{{Code: WarningDuplicateKey, Key: "a"}}}
+	if got := Warnings(resp); len(got) != 1 || got[0].Key != "a" {
+		t.Errorf("Unexpected warnings: %v", got)
+	}
+	if !HasWarning(resp, WarningDuplicateKey) || HasWarning(resp, WarningNearQuota) {
+		t.Error("HasWarning does not match the codes of the response")
+	}
+
+	var missing *proto.PutResponse
+	if Warnings(missing) != nil || HasWarning(missing, WarningNearQuota) {
+		t.Error("Expected no warnings on a nil response")
+	}
+	if Warnings(nil) != nil {
+		t.Error("Expected no warnings without a response")
+	}
+}
@@ -0,0 +1,275 @@
+// Package errors defines the stable codes of the errors Clavis servers
+// return, so applications can handle them without matching messages. Servers
+// attach the code of an error to its gRPC status as a google.rpc.ErrorInfo of
+// the Domain; FromError and CodeOf read it back.
+package errors
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the domain of the google.rpc.ErrorInfo details carrying codes.
+const Domain = "clavis"
+
+// Code identifies the kind of an error. Codes are stable across releases.
+type Code string
+
+const (
+	CodeUnknown             Code = "UNKNOWN"
+	CodeInternal            Code = "INTERNAL"
+	CodeNotFound            Code = "NOT_FOUND"
+	CodeInvalidArgument     Code = "INVALID_ARGUMENT"
+	CodeValidationFailed    Code = "VALIDATION_FAILED"
+	CodeQuotaExceeded       Code = "QUOTA_EXCEEDED"
+	CodeRateLimited         Code = "RATE_LIMITED"
+	CodeOverloaded          Code = "OVERLOADED"
+	CodeVersionConflict     Code = "VERSION_CONFLICT"
+	CodePreconditionFailed  Code = "PRECONDITION_FAILED"
+	CodeReadOnly            Code = "READ_ONLY"
+	CodeStaleFencingToken   Code = "STALE_FENCING_TOKEN"
+	CodeSnapshotUnavailable Code = "SNAPSHOT_UNAVAILABLE"
+	CodeUnsupported         Code = "UNSUPPORTED"
+	CodeNotCounter          Code = "NOT_COUNTER"
+	CodeCounterOverflow     Code = "COUNTER_OVERFLOW"
+	CodeDataLoss            Code = "DATA_LOSS"
+	CodeTooManySubscribers  Code = "TOO_MANY_SUBSCRIBERS"
+	CodeSlowConsumer        Code = "SLOW_CONSUMER"
+	CodeLockHeld            Code = "LOCK_HELD"
+	CodeLeaseNotHeld        Code = "LEASE_NOT_HELD"
+	CodeLeaseNotFound       Code = "LEASE_NOT_FOUND"
+)
+
+// CodeInfo describes a registered code.
+type CodeInfo struct {
+	Code        Code
+	GRPCCode    codes.Code // Status code of the errors with the code
+	Description string
+}
+
+// registry holds every code servers return.
+var registry = map[Code]CodeInfo{}
+
+func init() {
+	for _, info := range []CodeInfo{
+		{CodeUnknown, codes.Unknown, "the error has no code"},
+		{CodeInternal, codes.Internal, "the server failed unexpectedly"},
+		{CodeNotFound, codes.NotFound, "the key or resource does not exist"},
+		{CodeInvalidArgument, codes.InvalidArgument, "the request is malformed"},
+		{CodeValidationFailed, codes.InvalidArgument, "a key or value failed validation; see the violations"},
+		{CodeQuotaExceeded, codes.ResourceExhausted, "the write would exceed a namespace quota"},
+		{CodeRateLimited, codes.ResourceExhausted, "the caller sent too many requests; retry after the retry_after metadata"},
+		{CodeOverloaded, codes.Unavailable, "the server has too many requests in flight; retry with backoff"},
+		{CodeVersionConflict, codes.Aborted, "the key changed since the expected version"},
+		{CodePreconditionFailed, codes.FailedPrecondition, "a condition of the write does not hold, such as the key already existing"},
+		{CodeReadOnly, codes.FailedPrecondition, "the server rejects writes"},
+		{CodeStaleFencingToken, codes.FailedPrecondition, "a newer lock holder has written"},
+		{CodeSnapshotUnavailable, codes.FailedPrecondition, "the requested version is no longer kept"},
+		{CodeUnsupported, codes.Unimplemented, "the store does not support the operation"},
+		{CodeNotCounter, codes.FailedPrecondition, "the value is not a counter"},
+		{CodeCounterOverflow, codes.OutOfRange, "the counter would overflow"},
+		{CodeDataLoss, codes.DataLoss, "a stored value is corrupt or cannot be decrypted"},
+		{CodeTooManySubscribers, codes.ResourceExhausted, "the server has as many subscriptions open as it allows"},
+		{CodeSlowConsumer, codes.ResourceExhausted, "the subscriber fell too far behind and missed events; resubscribe"},
+		{CodeLockHeld, codes.FailedPrecondition, "another lease on the lock is live"},
+		{CodeLeaseNotHeld, codes.FailedPrecondition, "the lease expired, was released or was superseded; acquire the lock again"},
+		{CodeLeaseNotFound, codes.NotFound, "the lease expired, was revoked or was never granted"},
+	} {
+		registry[info.Code] = info
+	}
+}
+
+// Lookup returns the registration of code.
+func Lookup(code Code) (CodeInfo, bool) {
+	info, ok := registry[code]
+	return info, ok
+}
+
+// Codes returns every registered code, sorted.
+func Codes() []CodeInfo {
+	infos := make([]CodeInfo, 0, len(registry))
+	for _, code := range slices.Sorted(maps.Keys(registry)) {
+		infos = append(infos, registry[code])
+	}
+	return infos
+}
+
+// Violation is a field of a request that failed validation.
+type Violation struct {
+	Field    string            `json:"field"` // Name of the field, such as "key" or "value"
+	Code     string            `json:"code"`  // Validation code, such as "TOO_LONG"
+	Message  string            `json:"message"`
+	Metadata map[string]string `json:"metadata,omitempty"` // Details such as the limit exceeded
+}
+
+// Error is an error with a stable code. It converts to and from gRPC statuses
+// and JSON.
+type Error struct {
+	Code       Code              `json:"code"`
+	Message    string            `json:"message"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Violations []Violation       `json:"violations,omitempty"`
+}
+
+// New returns an error with code and message.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Error formats the error as "<code>: <message>".
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// Is reports whether target is an *Error with the same code, so errors.Is
+// can match codes.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// GRPCStatus converts the error to a status with the code of its
+// registration, an ErrorInfo carrying its code and metadata, and for
+// validation failures a BadRequest and an ErrorInfo per violation.
+func (e *Error) GRPCStatus() *status.Status {
+	grpcCode := codes.Unknown
+	if info, ok := Lookup(e.Code); ok {
+		grpcCode = info.GRPCCode
+	}
+	st := status.New(grpcCode, e.Message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(e.Code), Domain: Domain, Metadata: e.Metadata}}
+	if len(e.Violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		details = append(details, badRequest)
+		for _, v := range e.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field: v.Field, Description: v.Message, Reason: v.Code,
+			})
+			metadata := map[string]string{"field": v.Field}
+			maps.Copy(metadata, v.Metadata)
+			details = append(details, &errdetails.ErrorInfo{Reason: v.Code, Domain: Domain, Metadata: metadata})
+		}
+	}
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// grpcFallbacks are the codes of statuses without an ErrorInfo of the Domain,
+// such as those of older servers or of the gRPC runtime, for the gRPC codes
+// only one code maps to; statuses with any other code are CodeUnknown.
+var grpcFallbacks = map[codes.Code]Code{
+	codes.NotFound:        CodeNotFound,
+	codes.InvalidArgument: CodeInvalidArgument,
+	codes.Unimplemented:   CodeUnsupported,
+	codes.DataLoss:        CodeDataLoss,
+	codes.Internal:        CodeInternal,
+}
+
+// FromStatus converts a status to an error, reading the code from its first
+// ErrorInfo of the Domain and falling back on its gRPC code.
+func FromStatus(st *status.Status) *Error {
+	e := New(CodeUnknown, st.Message())
+	if code, ok := grpcFallbacks[st.Code()]; ok {
+		e.Code = code
+	}
+	var violationInfos []*errdetails.ErrorInfo
+	coded := false
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() != Domain {
+				continue
+			}
+			if _, ok := detail.GetMetadata()["field"]; ok {
+				violationInfos = append(violationInfos, detail)
+			} else if !coded {
+				e.Code, e.Metadata, coded = Code(detail.GetReason()), detail.GetMetadata(), true
+			}
+		case *errdetails.BadRequest:
+			for _, v := range detail.GetFieldViolations() {
+				e.Violations = append(e.Violations, Violation{Field: v.GetField(), Code: v.GetReason(), Message: v.GetDescription()})
+			}
+		}
+	}
+
+	// Servers send an ErrorInfo per violation, in the same order
+	if len(violationInfos) == len(e.Violations) {
+		for i, info := range violationInfos {
+			if info.GetMetadata()["field"] == e.Violations[i].Field {
+				e.Violations[i].Metadata = info.GetMetadata()
+			}
+		}
+	}
+	if !coded && len(e.Violations) > 0 {
+		e.Code = CodeValidationFailed
+	}
+	return e
+}
+
+// FromError converts err to an *Error: the one err wraps, if any, else one
+// read from its gRPC status, else a CodeUnknown error. It returns nil for a
+// nil err.
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if st, ok := status.FromError(err); ok {
+		return FromStatus(st)
+	}
+	return New(CodeUnknown, err.Error())
+}
+
+// CodeOf returns the code of err, or an empty code for a nil err.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	return FromError(err).Code
+}
+
+// IsNotFound reports whether err is about a key or resource that does not exist.
+func IsNotFound(err error) bool {
+	return CodeOf(err) == CodeNotFound
+}
+
+// IsValidation reports whether err rejected a request as invalid, either by
+// a validation profile or for being malformed.
+func IsValidation(err error) bool {
+	code := CodeOf(err)
+	return code == CodeValidationFailed || code == CodeInvalidArgument
+}
+
+// IsQuotaExceeded reports whether err rejected a write over a namespace quota.
+func IsQuotaExceeded(err error) bool {
+	return CodeOf(err) == CodeQuotaExceeded
+}
+
+// IsRateLimited reports whether err rejected a request over a rate limit.
+func IsRateLimited(err error) bool {
+	return CodeOf(err) == CodeRateLimited
+}
+
+// IsVersionConflict reports whether err rejected a conditional write because
+// the key changed.
+func IsVersionConflict(err error) bool {
+	return CodeOf(err) == CodeVersionConflict
+}
+
+// IsPreconditionFailed reports whether err rejected a write whose condition
+// does not hold, such as a key that already exists.
+func IsPreconditionFailed(err error) bool {
+	return CodeOf(err) == CodePreconditionFailed
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestError_StatusRoundTrip(t *testing.T) {
+	original := &Error{
+		Code:    CodeValidationFailed,
+		Message: "invalid request",
+		Violations: []Violation{
+			{Field: "key", Code: "TOO_LONG", Message: "key is too long", Metadata: map[string]string{"max": "16"}},
+			{Field: "value", Code: "INVALID_CONTENT", Message: "value is not JSON"},
+		},
+	}
+	err := fmt.Errorf("put: %w", original.GRPCStatus().Err())
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Status code = %v, want InvalidArgument", status.Code(err))
+	}
+
+	// Decode from the status alone, as a client would
+	decoded := FromStatus(status.Convert(original.GRPCStatus().Err()))
+	if decoded.Code != CodeValidationFailed || decoded.Message != "invalid request" || len(decoded.Violations) != 2 {
+		t.Fatalf("Decoded error = %+v", decoded)
+	}
+	if v := decoded.Violations[0]; v.Field != "key" || v.Code != "TOO_LONG" || v.Metadata["max"] != "16" {
+		t.Errorf("First violation = %+v", v)
+	}
+	if v := decoded.Violations[1]; v.Field != "value" || v.Message != "value is not JSON" {
+		t.Errorf("Second violation = %+v", v)
+	}
+	if !IsValidation(err) {
+		t.Error("Expected IsValidation for a validation failure")
+	}
+}
+
+func TestError_Metadata(t *testing.T) {
+	original := New(CodeQuotaExceeded, "namespace tenant-a is full")
+	original.Metadata = map[string]string{"namespace": "tenant-a"}
+
+	decoded := FromStatus(status.Convert(original.GRPCStatus().Err()))
+	if decoded.Code != CodeQuotaExceeded || decoded.Metadata["namespace"] != "tenant-a" {
+		t.Errorf("Decoded error = %+v, want the quota code and namespace", decoded)
+	}
+	if status.Code(original.GRPCStatus().Err()) != codes.ResourceExhausted {
+		t.Errorf("Status code = %v, want ResourceExhausted", status.Code(original.GRPCStatus().Err()))
+	}
+}
+
+func TestError_JSON(t *testing.T) {
+	original := &Error{Code: CodeValidationFailed, Message: "invalid", Violations: []Violation{{Field: "key", Code: "REQUIRED", Message: "key is required"}}}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"code":"VALIDATION_FAILED","message":"invalid","violations":[{"field":"key","code":"REQUIRED","message":"key is required"}]}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+
+	var decoded Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Code != original.Code || len(decoded.Violations) != 1 || decoded.Violations[0].Field != "key" {
+		t.Errorf("Decoded error = %+v, want %+v", decoded, original)
+	}
+}
+
+func TestFromError_Fallbacks(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{status.Error(codes.NotFound, "key not found"), CodeNotFound},
+		{status.Error(codes.InvalidArgument, "bad key"), CodeInvalidArgument},
+		{status.Error(codes.Unimplemented, "no watch"), CodeUnsupported},
+		{status.Error(codes.FailedPrecondition, "read-only"), CodeUnknown},
+		{errors.New("boom"), CodeUnknown},
+		{New(CodeVersionConflict, "changed"), CodeVersionConflict},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("CodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+
+	if FromError(nil) != nil {
+		t.Error("Expected no error for nil")
+	}
+	if !IsNotFound(status.Error(codes.NotFound, "gone")) || IsNotFound(errors.New("gone")) {
+		t.Error("IsNotFound should match the NotFound status only")
+	}
+	if !IsVersionConflict(New(CodeVersionConflict, "changed").GRPCStatus().Err()) {
+		t.Error("Expected IsVersionConflict for a version conflict status")
+	}
+	if !IsPreconditionFailed(New(CodePreconditionFailed, "exists").GRPCStatus().Err()) {
+		t.Error("Expected IsPreconditionFailed for a precondition status")
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("get: %w", New(CodeNotFound, "key not found"))
+	if !errors.Is(err, New(CodeNotFound, "")) {
+		t.Error("Expected errors.Is to match the code")
+	}
+	if errors.Is(err, New(CodeInternal, "")) {
+		t.Error("Expected errors.Is not to match another code")
+	}
+	if got := New(CodeNotFound, "key not found").Error(); got != "NOT_FOUND: key not found" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestCodes(t *testing.T) {
+	infos := Codes()
+	if !slices.IsSortedFunc(infos, func(a, b CodeInfo) int { return strings.Compare(string(a.Code), string(b.Code)) }) {
+		t.Error("Codes are not sorted")
+	}
+	for _, info := range infos {
+		if looked, ok := Lookup(info.Code); !ok || looked != info || info.Description == "" {
+			t.Errorf("Lookup(%s) = %+v, %v", info.Code, looked, ok)
+		}
+	}
+	if _, ok := Lookup("NOPE"); ok {
+		t.Error("Expected unknown codes to be missing")
+	}
+}
@@ -1,17 +1,21 @@
 package integration
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/William-Fernandes252/clavis/api/proto"
 	grpcserver "github.com/William-Fernandes252/clavis/internal/server/grpc"
 	"github.com/William-Fernandes252/clavis/internal/store/badger"
+	"github.com/William-Fernandes252/clavis/pkg/client"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -244,6 +248,62 @@ func TestGRPCServer_Integration_BasicOperations(t *testing.T) {
 	})
 }
 
+func TestGRPCServer_Integration_Scan(t *testing.T) {
+	// Create and start test server
+	testServer := NewTestServer(t)
+	defer testServer.Stop()
+	testServer.Start(t)
+
+	// Create client
+	client, conn := testServer.NewClient(t)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("Failed to close connection: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 25; i++ {
+		_, err := client.Put(ctx, &proto.PutRequest{
+			Key:   fmt.Sprintf("scan:%02d", i),
+			Value: []byte(fmt.Sprintf("value-%d", i)),
+		})
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if _, err := client.Put(ctx, &proto.PutRequest{Key: "other", Value: []byte("x")}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	var keys []string
+	cursor := ""
+	for {
+		resp, err := client.Scan(ctx, &proto.ScanRequest{Prefix: "scan:", Limit: 10, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		for _, item := range resp.Items {
+			keys = append(keys, item.Key)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	if len(keys) != 25 {
+		t.Fatalf("Expected 25 keys, got %d", len(keys))
+	}
+	for i, key := range keys {
+		if expected := fmt.Sprintf("scan:%02d", i); key != expected {
+			t.Errorf("Expected %s at position %d, got %s", expected, i, key)
+		}
+	}
+}
+
 func TestGRPCServer_Integration_ValidationErrors(t *testing.T) {
 	// Create and start test server
 	testServer := NewTestServer(t)
@@ -673,3 +733,217 @@ func TestGRPCServer_Integration_Persistence(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCServer_Integration_UpdateJSON(t *testing.T) {
+	testServer := NewTestServer(t)
+	testServer.Start(t)
+	defer testServer.Stop()
+
+	c, conn := testServer.NewClient(t)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	type counter struct {
+		Count int `json:"count"`
+	}
+	const writers, increments = 4, 10
+	config := client.UpdateConfig{MaxAttempts: 100, InitialBackoff: time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				_, err := client.UpdateJSONWithConfig(ctx, c, "counters/cas", config, func(old counter) (counter, error) {
+					old.Count++
+					return old, nil
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("UpdateJSON failed: %v", err)
+	}
+
+	resp, err := c.Get(ctx, &proto.GetRequest{Key: "counters/cas", WithVersion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Value) != fmt.Sprintf(`{"count":%d}`, writers*increments) {
+		t.Errorf("Expected every increment to be applied once, got %s", resp.Value)
+	}
+	if resp.Version == 0 {
+		t.Error("Expected a version for an existing key")
+	}
+
+	stale := resp.Version - 1
+	_, err = c.Put(ctx, &proto.PutRequest{Key: "counters/cas", Value: []byte(`{}`), ExpectedVersion: &stale})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected a stale version to be rejected with Aborted, got %v", err)
+	}
+
+	if _, err := c.Put(ctx, &proto.PutRequest{Key: "locks/job", Value: []byte("worker-1"), MustNotExist: true}); err != nil {
+		t.Fatalf("Expected to create a missing key, got %v", err)
+	}
+	_, err = c.Put(ctx, &proto.PutRequest{Key: "locks/job", Value: []byte("worker-2"), MustNotExist: true})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected an existing key to be rejected with FailedPrecondition, got %v", err)
+	}
+	if resp, _ := c.Get(ctx, &proto.GetRequest{Key: "locks/job"}); string(resp.GetValue()) != "worker-1" {
+		t.Errorf("Expected the first writer to keep the key, got %q", resp.GetValue())
+	}
+	_, err = c.Put(ctx, &proto.PutRequest{Key: "locks/job", MustNotExist: true, ExpectedVersion: &stale})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected both preconditions to be rejected with InvalidArgument, got %v", err)
+	}
+}
+
+func TestGRPCServer_Integration_Versions(t *testing.T) {
+	testServer := NewTestServer(t)
+	testServer.Start(t)
+	defer testServer.Stop()
+
+	c, conn := testServer.NewClient(t)
+	defer func() { _ = conn.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Write another key first so the first version of the key is above 1
+	if _, err := c.Put(ctx, &proto.PutRequest{Key: "other", Value: []byte("x")}); err != nil {
+		t.Fatal(err)
+	}
+	first, err := c.Put(ctx, &proto.PutRequest{Key: "versioned", Value: []byte("v1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Put(ctx, &proto.PutRequest{Key: "versioned", Value: []byte("v2"), ExpectedVersion: &first.Version})
+	if err != nil {
+		t.Fatalf("Expected the version returned by Put to allow a conditional update, got %v", err)
+	}
+	if first.Version == 0 || second.Version <= first.Version {
+		t.Errorf("Expected increasing versions, got %d then %d", first.Version, second.Version)
+	}
+
+	resp, err := c.Get(ctx, &proto.GetRequest{Key: "versioned", WithVersion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Version != second.Version || string(resp.Value) != "v2" {
+		t.Errorf("Get = %q at %d, want v2 at %d", resp.Value, resp.Version, second.Version)
+	}
+	resp, err = c.Get(ctx, &proto.GetRequest{Key: "versioned", AtVersion: second.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Found || resp.Version != second.Version || string(resp.Value) != "v2" {
+		t.Errorf("Get at %d = %q at %d, found %v; want v2", second.Version, resp.Value, resp.Version, resp.Found)
+	}
+	if resp, err := c.Get(ctx, &proto.GetRequest{Key: "versioned", AtVersion: first.Version - 1}); err != nil || resp.Found {
+		t.Errorf("Expected no value before the first write, got %v %v", resp, err)
+	}
+}
+
+func TestGRPCServer_Integration_ScanAll(t *testing.T) {
+	testServer := NewTestServer(t)
+	testServer.Start(t)
+	defer testServer.Stop()
+
+	c, conn := testServer.NewClient(t)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Six 1MB values exceed what one Scan response may carry, so the result is spilled
+	var want []string
+	for i := 0; i < 6; i++ {
+		key := fmt.Sprintf("export/%d", i)
+		want = append(want, key)
+		if _, err := c.Put(ctx, &proto.PutRequest{Key: key, Value: bytes.Repeat([]byte{byte('a' + i)}, 1024*1024)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := c.Scan(ctx, &proto.ScanRequest{Prefix: "export/", AllowSpill: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Spill == nil || resp.Spill.Items != 6 {
+		t.Fatalf("Expected the scan to be spilled, got %d items and spill %v", len(resp.Items), resp.Spill)
+	}
+
+	var keys []string
+	err = client.ScanAll(ctx, c, "export/", func(item *proto.KeyValue) error {
+		if len(item.Value) != 1024*1024 {
+			t.Errorf("Unexpected value size %d for %s", len(item.Value), item.Key)
+		}
+		keys = append(keys, item.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanAll failed: %v", err)
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Unexpected keys: %v", keys)
+	}
+}
+
+func TestGRPCServer_Integration_ExportAt(t *testing.T) {
+	testServer := NewTestServer(t)
+	testServer.Start(t)
+	defer testServer.Stop()
+
+	c, conn := testServer.NewClient(t)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Put(ctx, &proto.PutRequest{Key: fmt.Sprintf("export/%d", i), Value: []byte("v")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A time not yet reached resolves to the latest version
+	count := 0
+	version, err := client.ExportAt(ctx, c, "export/", time.Now().Add(time.Hour), func(*proto.KeyValue) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportAt failed: %v", err)
+	}
+	if count != 3 || version == 0 {
+		t.Errorf("Expected 3 keys at a version, got %d keys at version %d", count, version)
+	}
+
+	// Pages of a point-in-time scan resume at the same version
+	page, err := c.Scan(ctx, &proto.ScanRequest{Prefix: "export/", Limit: 2, AsOfVersion: version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == "" || page.AsOfVersion != version {
+		t.Errorf("Expected a first page of 2 at version %d, got %v", version, page)
+	}
+
+	// Without version history the server cannot read the past
+	_, err = client.ExportAt(ctx, c, "export/", time.Now().Add(-time.Hour), func(*proto.KeyValue) error { return nil })
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without history, got %v", err)
+	}
+	_, err = c.Scan(ctx, &proto.ScanRequest{AsOfVersion: version, AsOfUnixNano: time.Now().UnixNano()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for both points in time, got %v", err)
+	}
+}
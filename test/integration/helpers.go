@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/William-Fernandes252/clavis/api/proto"
+	"github.com/William-Fernandes252/clavis/internal/bench"
 )
 
 // BenchmarkHelper provides utilities for benchmarking integration tests
@@ -25,8 +26,8 @@ func NewBenchmarkHelper(client proto.ClavisClient, ctx context.Context) *Benchma
 
 // BenchmarkPut benchmarks the Put operation
 func (bh *BenchmarkHelper) BenchmarkPut(b *testing.B, keySize, valueSize int) {
-	key := generateString("key-", keySize)
-	value := generateBytes(valueSize)
+	key := bench.Key("key-", 0, keySize)
+	value := bench.Value(valueSize)
 
 	b.ResetTimer()
 	b.SetBytes(int64(len(key) + len(value)))
@@ -46,8 +47,8 @@ func (bh *BenchmarkHelper) BenchmarkPut(b *testing.B, keySize, valueSize int) {
 // BenchmarkGet benchmarks the Get operation
 func (bh *BenchmarkHelper) BenchmarkGet(b *testing.B, keySize, valueSize int) {
 	// Pre-populate data
-	key := generateString("get-key-", keySize)
-	value := generateBytes(valueSize)
+	key := bench.Key("get-key-", 0, keySize)
+	value := bench.Value(valueSize)
 
 	req := &proto.PutRequest{
 		Key:   key,
@@ -72,7 +73,7 @@ func (bh *BenchmarkHelper) BenchmarkGet(b *testing.B, keySize, valueSize int) {
 
 // BenchmarkDelete benchmarks the Delete operation
 func (bh *BenchmarkHelper) BenchmarkDelete(b *testing.B, keySize int) {
-	key := generateString("del-key-", keySize)
+	key := bench.Key("del-key-", 0, keySize)
 
 	// Pre-populate data
 	for i := 0; i < b.N; i++ {
@@ -98,30 +99,6 @@ func (bh *BenchmarkHelper) BenchmarkDelete(b *testing.B, keySize int) {
 	}
 }
 
-// generateString generates a string of specified length with a prefix
-func generateString(prefix string, totalLength int) string {
-	if len(prefix) >= totalLength {
-		return prefix[:totalLength]
-	}
-
-	remaining := totalLength - len(prefix)
-	suffix := make([]byte, remaining)
-	for i := range suffix {
-		suffix[i] = byte('a' + (i % 26))
-	}
-
-	return prefix + string(suffix)
-}
-
-// generateBytes generates a byte slice of specified length
-func generateBytes(length int) []byte {
-	bytes := make([]byte, length)
-	for i := range bytes {
-		bytes[i] = byte(i % 256)
-	}
-	return bytes
-}
-
 // TestDataGenerator provides utilities for generating test data
 type TestDataGenerator struct{}
 
@@ -137,7 +114,7 @@ func (tdg *TestDataGenerator) GenerateKeyValuePairs(count int, keyPrefix string,
 
 	for i := 0; i < count; i++ {
 		pairs[i].Key = fmt.Sprintf("%s-%d", keyPrefix, i)
-		pairs[i].Value = generateBytes(valueSize)
+		pairs[i].Value = bench.Value(valueSize)
 		// Make each value unique
 		copy(pairs[i].Value[:4], []byte(fmt.Sprintf("%04d", i)))
 	}